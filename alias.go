@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// AliasNotFoundError is returned when no bug has the alias. It matches
+// IsNotFound as well as IsAliasNotFound.
+type AliasNotFoundError struct {
+	// Alias is the alias which was looked up.
+	Alias string
+	// Err is the error returned by the server.
+	Err error
+}
+
+func (e *AliasNotFoundError) Error() string {
+	return fmt.Sprintf("no bug has the alias %q: %v", e.Alias, e.Err)
+}
+
+func (e *AliasNotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// IsAliasNotFound returns true if no bug has the alias which was looked up
+func IsAliasNotFound(err error) bool {
+	var target *AliasNotFoundError
+	return errors.As(err, &target)
+}
+
+// aliasPath returns the path of the bug with the alias. Aliases which are
+// numbers are rejected, as the server would take them for bug IDs.
+func (c *client) aliasPath(alias string) (string, error) {
+	if alias == "" {
+		return "", fmt.Errorf("alias must not be empty")
+	}
+	if _, err := strconv.Atoi(alias); err == nil {
+		return "", fmt.Errorf("invalid alias %q: aliases can not be numbers", alias)
+	}
+	return fmt.Sprintf("%s/rest/bug/%s", c.endpoint, url.PathEscape(alias)), nil
+}
+
+// aliasNotFound wraps the error in an AliasNotFoundError if the server
+// responded that there is no bug with the alias
+func aliasNotFound(alias string, err error) error {
+	if IsNotFound(err) || IsInvalidBug(err) {
+		return &AliasNotFoundError{Alias: alias, Err: err}
+	}
+	return err
+}
+
+// GetBugByAlias retrieves the bug with the alias from the server
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#get-bug
+func (c *client) GetBugByAlias(alias string) (*Bug, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "GetBugByAlias", "alias": alias})
+	path, err := c.aliasPath(alias)
+	if err != nil {
+		return nil, err
+	}
+	bugs, err := c.getBugs(path, nil, logger)
+	if err != nil {
+		return nil, aliasNotFound(alias, err)
+	}
+	if len(bugs) != 1 {
+		return nil, fmt.Errorf("did not get one bug, but %d: %v", len(bugs), bugs)
+	}
+	return bugs[0], nil
+}
+
+// UpdateBugByAlias updates the fields of the bug with the alias on the server
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#update-bug
+func (c *client) UpdateBugByAlias(alias string, update BugUpdate) error {
+	logger := c.logger.WithFields(LogFields{methodField: "UpdateBugByAlias", "alias": alias})
+	path, err := c.aliasPath(alias)
+	if err != nil {
+		return err
+	}
+	update = c.adaptUpdate(update, logger)
+	body, err := json.Marshal(update)
+	logger = logger.WithField("update", string(body))
+	if err != nil {
+		return fmt.Errorf("failed to marshal update payload: %v", err)
+	}
+	if err := c.checkLastChange(0, alias, update, logger); err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPut, path, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if changed, ok := updatedBugs(update); ok {
+		req = declareChanges(req, changedBugs{ids: changed, aliases: []string{alias}})
+	}
+
+	_, err = c.request(req, logger)
+	return aliasNotFound(alias, midairCollision(0, alias, update, err))
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAliases(t *testing.T) {
+	updated := false
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/rest/bug/CVE-2020-1234%2Fkernel":
+			if r.Method == http.MethodPut {
+				updated = true
+				w.Write([]byte(`{"bugs":[{"id":1705243,"changes":{}}]}`))
+				return
+			}
+			w.Write(bugData)
+		case "/rest/bug/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":true,"code":100,"message":"'missing' is not a valid bug number nor an alias to a bug."}`))
+		default:
+			t.Errorf("incorrect path to address a bug by alias: %s", r.URL.EscapedPath())
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+		}
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	bug, err := client.GetBugByAlias("CVE-2020-1234/kernel")
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if bug.ID != 1705243 {
+		t.Errorf("expected bug 1705243, got %d", bug.ID)
+	}
+	if err := client.UpdateBugByAlias("CVE-2020-1234/kernel", BugUpdate{Status: "ASSIGNED"}); err != nil {
+		t.Errorf("expected no error, but got one: %v", err)
+	}
+	if !updated {
+		t.Error("expected the bug to be updated")
+	}
+
+	_, err = client.GetBugByAlias("missing")
+	if !IsAliasNotFound(err) || !IsNotFound(err) || !IsInvalidBug(err) {
+		t.Errorf("expected an alias not found error, got %v", err)
+	}
+	if !IsAliasNotFound(fmt.Errorf("could not get bug: %w", err)) {
+		t.Errorf("expected the wrapped error to be recognized, got %v", err)
+	}
+	var notFound *AliasNotFoundError
+	if !errors.As(err, &notFound) || notFound.Alias != "missing" {
+		t.Errorf("expected the error to hold the alias, got %v", err)
+	}
+	if err := client.UpdateBugByAlias("missing", BugUpdate{Status: "ASSIGNED"}); !IsAliasNotFound(err) {
+		t.Errorf("expected an alias not found error, got %v", err)
+	}
+	for _, alias := range []string{"", "1705243"} {
+		if _, err := client.GetBugByAlias(alias); err == nil {
+			t.Errorf("expected an error for the invalid alias %q", alias)
+		}
+	}
+}
+
+func TestFakeAliases(t *testing.T) {
+	fake := &Fake{Bugs: map[int]Bug{1: {ID: 1, Alias: []string{"CVE-2020-1234"}, Status: "NEW"}}}
+	if err := fake.UpdateBugByAlias("CVE-2020-1234", BugUpdate{Status: "ASSIGNED"}); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	bug, err := fake.GetBugByAlias("CVE-2020-1234")
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if bug.ID != 1 || bug.Status != "ASSIGNED" {
+		t.Errorf("expected bug 1 to be updated, got bug %d in %s", bug.ID, bug.Status)
+	}
+	if _, err := fake.GetBugByAlias("missing"); !IsAliasNotFound(err) || !IsNotFound(err) {
+		t.Errorf("expected an alias not found error, got %v", err)
+	}
+}
@@ -0,0 +1,311 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Annotations store metadata about bugs which does not belong in any field
+// of the bug, like when automation last nagged about a bug or how far it got
+// processing it. Keys are namespaced by the backend, so automation which
+// shares a backend only has to pick distinct keys.
+type Annotations interface {
+	// Get returns the value of the annotation and whether it is set.
+	Get(id int, key string) (string, bool, error)
+	// Set sets the annotation to the value.
+	Set(id int, key, value string) error
+	// Delete removes the annotation, if it is set.
+	Delete(id int, key string) error
+}
+
+// NewWhiteboardAnnotations returns Annotations stored as `<prefix><key>:<value>`
+// tokens in the status whiteboard of the bug, where people and queries can
+// see them. Neither keys nor values may contain whitespace.
+func NewWhiteboardAnnotations(c Client, prefix string) Annotations {
+	return &whiteboardAnnotations{client: c, prefix: prefix}
+}
+
+type whiteboardAnnotations struct {
+	client Client
+	prefix string
+}
+
+func (a *whiteboardAnnotations) whiteboard(id int) (string, error) {
+	bug, err := a.client.GetBugWithFields(id, []string{"id", "whiteboard"})
+	if err != nil {
+		return "", err
+	}
+	return bug.Whiteboard, nil
+}
+
+func (a *whiteboardAnnotations) Get(id int, key string) (string, bool, error) {
+	if err := validateAnnotationKey(key); err != nil {
+		return "", false, err
+	}
+	whiteboard, err := a.whiteboard(id)
+	if err != nil {
+		return "", false, err
+	}
+	value, ok := WhiteboardToken(whiteboard, a.prefix+key)
+	return value, ok, nil
+}
+
+func (a *whiteboardAnnotations) Set(id int, key, value string) error {
+	if err := validateAnnotationKey(key); err != nil {
+		return err
+	}
+	if value == "" || strings.ContainsAny(value, " \t\n") {
+		return fmt.Errorf("whiteboard annotation value %q must not be empty or contain whitespace", value)
+	}
+	return a.update(id, func(whiteboard string) string {
+		return SetWhiteboardToken(whiteboard, a.prefix+key, value)
+	})
+}
+
+func (a *whiteboardAnnotations) Delete(id int, key string) error {
+	if err := validateAnnotationKey(key); err != nil {
+		return err
+	}
+	return a.update(id, func(whiteboard string) string {
+		return RemoveWhiteboardToken(whiteboard, a.prefix+key)
+	})
+}
+
+// update applies the change to the whiteboard, unless it is applied already,
+// guarded by the time the bug last changed, see updateGuarded
+func (a *whiteboardAnnotations) update(id int, change func(string) string) error {
+	_, err := updateGuarded(a.client, id, []string{"id", "whiteboard"}, func(bug *Bug) (*BugUpdate, error) {
+		whiteboard := change(bug.Whiteboard)
+		if whiteboard == bug.Whiteboard {
+			return nil, nil
+		}
+		return &BugUpdate{Whiteboard: whiteboard}, nil
+	})
+	return err
+}
+
+// NewCommentAnnotations returns Annotations stored as comments on the bug
+// which start with the marker, like `[bot] set last-nag=2020-06-01`. Setting
+// or deleting an annotation adds a comment, the latest comment for a key
+// wins. Only comments by the author, the login of the user the client acts
+// as, are read, so nobody else can set annotations by commenting with the
+// marker. Comments are private unless public is set. Values may not contain
+// line breaks.
+func NewCommentAnnotations(c Client, marker, author string, public bool) Annotations {
+	return &commentAnnotations{client: c, marker: marker, author: author, public: public}
+}
+
+type commentAnnotations struct {
+	client Client
+	marker string
+	author string
+	public bool
+}
+
+const (
+	commentAnnotationSet   = "set"
+	commentAnnotationUnset = "unset"
+)
+
+func (a *commentAnnotations) Get(id int, key string) (string, bool, error) {
+	if err := validateAnnotationKey(key); err != nil {
+		return "", false, err
+	}
+	comments, err := a.client.GetBugComments(id)
+	if err != nil {
+		return "", false, err
+	}
+	var value string
+	var ok bool
+	set := fmt.Sprintf("%s %s=", commentAnnotationSet, key)
+	unset := fmt.Sprintf("%s %s", commentAnnotationUnset, key)
+	for _, comment := range comments {
+		if comment.Creator != a.author {
+			continue
+		}
+		for _, line := range strings.Split(comment.Text, "\n") {
+			if !strings.HasPrefix(line, a.marker) {
+				continue
+			}
+			line = strings.TrimSpace(strings.TrimPrefix(line, a.marker))
+			switch {
+			case line == unset:
+				value, ok = "", false
+			case strings.HasPrefix(line, set):
+				value, ok = strings.TrimPrefix(line, set), true
+			}
+		}
+	}
+	return value, ok, nil
+}
+
+func (a *commentAnnotations) Set(id int, key, value string) error {
+	if err := validateAnnotationKey(key); err != nil {
+		return err
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("comment annotation value %q must not contain line breaks", value)
+	}
+	return a.comment(id, fmt.Sprintf("%s %s %s=%s", a.marker, commentAnnotationSet, key, value))
+}
+
+func (a *commentAnnotations) Delete(id int, key string) error {
+	if err := validateAnnotationKey(key); err != nil {
+		return err
+	}
+	if _, ok, err := a.Get(id, key); err != nil || !ok {
+		return err
+	}
+	return a.comment(id, fmt.Sprintf("%s %s %s", a.marker, commentAnnotationUnset, key))
+}
+
+func (a *commentAnnotations) comment(id int, body string) error {
+	return a.client.UpdateBug(id, BugUpdate{MinorUpdate: true, Comment: &BugComment{Body: body, Private: !a.public}})
+}
+
+// NewFileAnnotations returns Annotations stored in a JSON file outside of
+// Bugzilla, for metadata which nobody but the automation needs to see. The
+// file is created when the first annotation is set and is read on every call
+// while holding a lock on the file `<path>.lock`, so processes on the same
+// host which share the file see each other's annotations and do not undo
+// each other's changes. File locks are supported on Linux, macOS, the BSDs
+// and Windows, calls fail on other platforms.
+func NewFileAnnotations(path string) Annotations {
+	return &fileAnnotations{path: path}
+}
+
+type fileAnnotations struct {
+	lock sync.Mutex
+	path string
+}
+
+// acquire locks the annotations against other users in this process and,
+// with a lock file next to the file, in other processes
+func (a *fileAnnotations) acquire() (func(), error) {
+	a.lock.Lock()
+	unlockFile, err := lockFile(a.path + ".lock")
+	if err != nil {
+		a.lock.Unlock()
+		return nil, fmt.Errorf("could not lock annotations: %v", err)
+	}
+	return func() {
+		unlockFile()
+		a.lock.Unlock()
+	}, nil
+}
+
+// load reads all annotations from the file, keyed by bug ID
+func (a *fileAnnotations) load() (map[int]map[string]string, error) {
+	annotations := map[int]map[string]string{}
+	raw, err := ioutil.ReadFile(a.path)
+	if os.IsNotExist(err) {
+		return annotations, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read annotations: %v", err)
+	}
+	if err := json.Unmarshal(raw, &annotations); err != nil {
+		return nil, fmt.Errorf("could not unmarshal annotations: %v", err)
+	}
+	return annotations, nil
+}
+
+// store replaces the file, so readers never see a partially written file
+func (a *fileAnnotations) store(annotations map[int]map[string]string) error {
+	raw, err := json.MarshalIndent(annotations, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal annotations: %v", err)
+	}
+	if err := WriteFileAtomic(a.path, raw); err != nil {
+		return fmt.Errorf("could not write annotations: %v", err)
+	}
+	return nil
+}
+
+func (a *fileAnnotations) Get(id int, key string) (string, bool, error) {
+	if err := validateAnnotationKey(key); err != nil {
+		return "", false, err
+	}
+	unlock, err := a.acquire()
+	if err != nil {
+		return "", false, err
+	}
+	defer unlock()
+	annotations, err := a.load()
+	if err != nil {
+		return "", false, err
+	}
+	value, ok := annotations[id][key]
+	return value, ok, nil
+}
+
+func (a *fileAnnotations) Set(id int, key, value string) error {
+	if err := validateAnnotationKey(key); err != nil {
+		return err
+	}
+	unlock, err := a.acquire()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	annotations, err := a.load()
+	if err != nil {
+		return err
+	}
+	if annotations[id] == nil {
+		annotations[id] = map[string]string{}
+	}
+	annotations[id][key] = value
+	return a.store(annotations)
+}
+
+func (a *fileAnnotations) Delete(id int, key string) error {
+	if err := validateAnnotationKey(key); err != nil {
+		return err
+	}
+	unlock, err := a.acquire()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	annotations, err := a.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := annotations[id][key]; !ok {
+		return nil
+	}
+	delete(annotations[id], key)
+	if len(annotations[id]) == 0 {
+		delete(annotations, id)
+	}
+	return a.store(annotations)
+}
+
+// validateAnnotationKey makes sure the key can be stored by every backend
+func validateAnnotationKey(key string) error {
+	if key == "" || strings.ContainsAny(key, ":= \t\r\n") {
+		return fmt.Errorf("annotation key %q must not be empty or contain colons, equal signs or whitespace", key)
+	}
+	return nil
+}
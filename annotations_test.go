@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestAnnotations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bugzilla")
+	if err != nil {
+		t.Fatalf("could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	var testCases = []struct {
+		name        string
+		annotations func(c Client) Annotations
+	}{
+		{
+			name: "whiteboard",
+			annotations: func(c Client) Annotations {
+				return NewWhiteboardAnnotations(c, "bot-")
+			},
+		},
+		{
+			name: "comments",
+			annotations: func(c Client) Annotations {
+				return NewCommentAnnotations(c, "[bot]", "bot@example.com", false)
+			},
+		},
+		{
+			name: "file",
+			annotations: func(c Client) Annotations {
+				return NewFileAnnotations(filepath.Join(dir, "annotations.json"))
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fake := &Fake{
+				Bugs:        map[int]Bug{1: {ID: 1, Whiteboard: "keep:me"}, 2: {ID: 2}},
+				BugComments: map[int][]Comment{1: {{Text: "description"}}, 2: {{Text: "description"}}},
+				CurrentUser: &User{Name: "bot@example.com"},
+			}
+			annotations := testCase.annotations(fake)
+			expect := func(id int, key, expectedValue string, expectedOk bool) {
+				t.Helper()
+				value, ok, err := annotations.Get(id, key)
+				if err != nil {
+					t.Fatalf("expected no error getting %s on bug %d, but got one: %v", key, id, err)
+				}
+				if value != expectedValue || ok != expectedOk {
+					t.Errorf("expected %s on bug %d to be %q (set %v), got %q (set %v)", key, id, expectedValue, expectedOk, value, ok)
+				}
+			}
+			expect(1, "last-nag", "", false)
+			if err := annotations.Set(1, "last-nag", "2020-06-01"); err != nil {
+				t.Fatalf("expected no error setting annotation, but got one: %v", err)
+			}
+			if err := annotations.Set(1, "state", "triaged"); err != nil {
+				t.Fatalf("expected no error setting annotation, but got one: %v", err)
+			}
+			if err := annotations.Set(1, "last-nag", "2020-06-08"); err != nil {
+				t.Fatalf("expected no error setting annotation, but got one: %v", err)
+			}
+			expect(1, "last-nag", "2020-06-08", true)
+			expect(1, "state", "triaged", true)
+			expect(2, "last-nag", "", false)
+			if err := annotations.Delete(1, "last-nag"); err != nil {
+				t.Fatalf("expected no error deleting annotation, but got one: %v", err)
+			}
+			if err := annotations.Delete(2, "last-nag"); err != nil {
+				t.Fatalf("expected no error deleting missing annotation, but got one: %v", err)
+			}
+			expect(1, "last-nag", "", false)
+			expect(1, "state", "triaged", true)
+			if err := annotations.Set(1, "bad key", "value"); err == nil {
+				t.Error("expected an error setting an annotation with an invalid key, but got none")
+			}
+		})
+	}
+}
+
+func TestWhiteboardAnnotationsPreserveWhiteboard(t *testing.T) {
+	fake := &Fake{Bugs: map[int]Bug{1: {ID: 1, Whiteboard: "keep:me other"}}}
+	annotations := NewWhiteboardAnnotations(fake, "bot-")
+	if err := annotations.Set(1, "state", "triaged"); err != nil {
+		t.Fatalf("expected no error setting annotation, but got one: %v", err)
+	}
+	if actual, expected := fake.Bugs[1].Whiteboard, "keep:me other bot-state:triaged"; actual != expected {
+		t.Errorf("expected whiteboard %q, got %q", expected, actual)
+	}
+	if err := annotations.Delete(1, "state"); err != nil {
+		t.Fatalf("expected no error deleting annotation, but got one: %v", err)
+	}
+	if actual, expected := fake.Bugs[1].Whiteboard, "keep:me other"; actual != expected {
+		t.Errorf("expected whiteboard %q, got %q", expected, actual)
+	}
+	if err := annotations.Set(1, "state", "needs info"); err == nil {
+		t.Error("expected an error setting a value with whitespace, but got none")
+	}
+}
+
+func TestCommentAnnotationsArePrivateMinorUpdates(t *testing.T) {
+	fake := &Fake{Bugs: map[int]Bug{1: {ID: 1}}, CurrentUser: &User{Name: "bot@example.com"}}
+	annotations := NewCommentAnnotations(fake, "[bot]", "bot@example.com", false)
+	if err := annotations.Set(1, "state", "needs info"); err != nil {
+		t.Fatalf("expected no error setting annotation, but got one: %v", err)
+	}
+	comments := fake.BugComments[1]
+	if len(comments) != 1 || comments[0].Text != "[bot] set state=needs info" || !comments[0].IsPrivate {
+		t.Errorf("expected one private annotation comment, got %+v", comments)
+	}
+	if value, ok, err := annotations.Get(1, "state"); err != nil || !ok || value != "needs info" {
+		t.Errorf("expected annotation %q, got %q (set %v, error %v)", "needs info", value, ok, err)
+	}
+}
+
+func TestCommentAnnotationsOnlyReadTheAuthor(t *testing.T) {
+	fake := &Fake{
+		Bugs: map[int]Bug{1: {ID: 1}},
+		BugComments: map[int][]Comment{1: {
+			{Text: "description", Creator: "reporter@example.com"},
+			{Text: "[bot] set state=triaged", Creator: "bot@example.com"},
+			{Text: "[bot] set state=closed", Creator: "reporter@example.com"},
+		}},
+	}
+	annotations := NewCommentAnnotations(fake, "[bot]", "bot@example.com", false)
+	if value, ok, err := annotations.Get(1, "state"); err != nil || !ok || value != "triaged" {
+		t.Errorf("expected the annotation of the author %q, got %q (set %v, error %v)", "triaged", value, ok, err)
+	}
+}
+
+func TestFileAnnotationsAreShared(t *testing.T) {
+	dir, err := ioutil.TempDir("", "annotations")
+	if err != nil {
+		t.Fatalf("could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "annotations.json")
+
+	// every instance locks the file like a separate process would
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := NewFileAnnotations(path).Set(1, fmt.Sprintf("key-%d", i), "value"); err != nil {
+				t.Errorf("expected no error setting annotation, but got one: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	for i := 0; i < 10; i++ {
+		if _, ok, err := NewFileAnnotations(path).Get(1, fmt.Sprintf("key-%d", i)); err != nil || !ok {
+			t.Errorf("expected annotation key-%d to be kept, got set %v, error %v", i, ok, err)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AuditRecord describes a call which changed, or tried to change, bugs
+type AuditRecord struct {
+	// Time is when the call was made.
+	Time time.Time `json:"time"`
+	// Actor is who made the call, as configured with WithAuditSink.
+	Actor string `json:"actor,omitempty"`
+	// Method is the client method, like "UpdateBug" or "AddExternalBug".
+	Method string `json:"method"`
+	// BugIDs are the bugs which were changed, if the client knows them. For
+	// created bugs, this is the ID of the new bug.
+	BugIDs []int `json:"bug_ids,omitempty"`
+	// Alias is the alias of the changed bug, for calls addressing it by alias.
+	Alias string `json:"alias,omitempty"`
+	// Verb and URL are those of the request, with credentials redacted.
+	Verb string `json:"verb"`
+	URL  string `json:"url"`
+	// Payload is the body of the request, with credentials redacted.
+	Payload string `json:"payload,omitempty"`
+	// Response is the body of a successful response, with credentials redacted.
+	Response string `json:"response,omitempty"`
+	// Error is the error the call failed with, if it failed.
+	Error string `json:"error,omitempty"`
+}
+
+// AuditSink receives a record of every call which changes bugs, like
+// UpdateBug, CreateBug or AddPullRequestAsExternalBug, whether it succeeded
+// or not. Record is called concurrently when the client is used
+// concurrently, and should not block for long, as the call waits for it.
+type AuditSink interface {
+	Record(AuditRecord)
+}
+
+// WithAuditSink records every call changing bugs in the sink, as made by the
+// actor, e.g. the name of the bot using the client
+func WithAuditSink(actor string, sink AuditSink) Option {
+	return func(c *client) {
+		c.audit = &auditor{actor: actor, sink: sink}
+	}
+}
+
+type auditor struct {
+	actor string
+	sink  AuditSink
+}
+
+// auditable determines if the request changes something and must be
+// recorded: requests of client methods which only read are never recorded,
+// even if they are not GET requests, like the RPC calls the registry of RPC
+// methods marks as only reading, see markRetrySafe
+func auditable(req *http.Request) bool {
+	return !mayRetry(req)
+}
+
+// NewJSONAuditSink returns an AuditSink writing every record as a line of
+// JSON to the writer. Records which can not be written are logged.
+func NewJSONAuditSink(w io.Writer) AuditSink {
+	return &jsonAuditSink{writer: w}
+}
+
+type jsonAuditSink struct {
+	lock   sync.Mutex
+	writer io.Writer
+}
+
+func (s *jsonAuditSink) Record(record AuditRecord) {
+	raw, err := json.Marshal(record)
+	if err == nil {
+		s.lock.Lock()
+		_, err = fmt.Fprintf(s.writer, "%s\n", raw)
+		s.lock.Unlock()
+	}
+	if err != nil {
+		newLogEntry(defaultLogger, LogFields{methodField: record.Method}).WithError(err).Error("Could not write audit record.")
+	}
+}
+
+// auditedRequest sends the request and records it, see auditable
+func (c *client) auditedRequest(req *http.Request, logger *logEntry) ([]byte, error) {
+	record := AuditRecord{
+		Time:   c.now(),
+		Actor:  c.audit.actor,
+		Method: logger.Data[methodField].(string),
+		Verb:   req.Method,
+		URL:    redact(req.URL.String(), c.credentials()),
+	}
+	if ids, ok := logger.Data["ids"].([]int); ok {
+		record.BugIDs = append([]int{}, ids...)
+	}
+	if id, ok := logger.Data["id"].(int); ok {
+		record.BugIDs = []int{id}
+	}
+	if alias, ok := logger.Data["alias"].(string); ok {
+		record.Alias = alias
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			payload, _ := ioutil.ReadAll(body)
+			record.Payload = redact(string(payload), c.credentials())
+		}
+	}
+	raw, err := c.degradableRequest(req, logger)
+	if err != nil {
+		record.Error = err.Error()
+	} else {
+		record.Response = redact(string(raw), c.credentials())
+		if len(record.BugIDs) == 0 && record.Method == "CreateBug" {
+			var created struct {
+				ID int `json:"id"`
+			}
+			if json.Unmarshal(raw, &created) == nil && created.ID != 0 {
+				record.BugIDs = []int{created.ID}
+			}
+		}
+	}
+	c.audit.sink.Record(record)
+	return raw, err
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+type recordingAuditSink struct {
+	records []AuditRecord
+}
+
+func (s *recordingAuditSink) Record(record AuditRecord) {
+	s.records = append(s.records, record)
+}
+
+func TestAuditSink(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			w.Write(bugData)
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/bug":
+			w.Write([]byte(`{"id":7}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/jsonrpc.cgi":
+			w.Write([]byte(`{"id":"identifier","result":[{"id":1,"type":"GitHub"}]}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/rest/bug/2":
+			http.Error(w, `{"error":true,"code":101,"message":"Bug #2 does not exist."}`, http.StatusNotFound)
+		default:
+			w.Write([]byte(`{"bugs":[{"id":1}]}`))
+		}
+	}))
+	defer testServer.Close()
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	sink := &recordingAuditSink{}
+	c := clientForUrl(testServer.URL).(*client)
+	WithClock(&fakeClock{now: now})(c)
+	WithAuditSink("triage-bot", sink)(c)
+
+	if _, err := c.GetBug(1705243); err != nil {
+		t.Fatalf("expected no error getting a bug, got %v", err)
+	}
+	// the RPC call is a POST, but only reads
+	if _, err := c.GetExternalTrackerTypes(); err != nil {
+		t.Fatalf("expected no error getting the trackers, got %v", err)
+	}
+	if err := c.UpdateBug(1, BugUpdate{Status: "POST"}); err != nil {
+		t.Fatalf("expected no error updating a bug, got %v", err)
+	}
+	if err := c.UpdateBug(2, BugUpdate{Status: "POST"}); err == nil {
+		t.Fatal("expected an error updating a missing bug, got none")
+	}
+	if _, err := c.CreateBug(BugCreate{Product: "OCP", Summary: "new bug"}); err != nil {
+		t.Fatalf("expected no error creating a bug, got %v", err)
+	}
+
+	if len(sink.records) != 3 {
+		t.Fatalf("expected 3 records of the changes, got %d: %v", len(sink.records), sink.records)
+	}
+	update := sink.records[0]
+	expected := AuditRecord{
+		Time:     now,
+		Actor:    "triage-bot",
+		Method:   "UpdateBug",
+		BugIDs:   []int{1},
+		Verb:     http.MethodPut,
+		URL:      testServer.URL + "/rest/bug/1",
+		Payload:  `{"status":"POST"}`,
+		Response: `{"bugs":[{"id":1}]}`,
+	}
+	if update.URL != expected.URL {
+		// the API key is sent as a query parameter too, and must be redacted
+		if !strings.HasPrefix(update.URL, expected.URL+"?") || strings.Contains(update.URL, "api-key") {
+			t.Errorf("got incorrect URL: %s", update.URL)
+		}
+		update.URL = expected.URL
+	}
+	if !reflect.DeepEqual(update, expected) {
+		t.Errorf("got incorrect record of the update: %v", diff.ObjectReflectDiff(expected, update))
+	}
+	if failed := sink.records[1]; failed.Error == "" || failed.Response != "" || len(failed.BugIDs) != 1 || failed.BugIDs[0] != 2 {
+		t.Errorf("got incorrect record of the failed update: %+v", failed)
+	}
+	if created := sink.records[2]; created.Method != "CreateBug" || len(created.BugIDs) != 1 || created.BugIDs[0] != 7 {
+		t.Errorf("got incorrect record of the created bug: %+v", created)
+	}
+}
+
+func TestJSONAuditSink(t *testing.T) {
+	var out bytes.Buffer
+	sink := NewJSONAuditSink(&out)
+	sink.Record(AuditRecord{Method: "UpdateBug", BugIDs: []int{1}})
+	sink.Record(AuditRecord{Method: "CreateBug", Error: "oops"})
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %q", len(lines), out.String())
+	}
+	var record AuditRecord
+	if err := json.Unmarshal([]byte(lines[1]), &record); err != nil {
+		t.Fatalf("could not parse record: %v", err)
+	}
+	if record.Method != "CreateBug" || record.Error != "oops" {
+		t.Errorf("got incorrect record: %+v", record)
+	}
+}
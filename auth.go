@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// AuthBasic authenticates requests with the Authorization: Basic header using
+// the credentials given with WithBasicAuth, e.g. for proxies in front of the
+// server. It is usually chained with a method which authenticates with
+// Bugzilla itself, e.g. "basic,x-bugzilla-api-key", see SetAuthMethod.
+const AuthBasic = "basic"
+
+// WithBasicAuth configures the username and the function supplying the
+// password which are sent by the AuthBasic auth method.
+func WithBasicAuth(username string, getPassword func() []byte) Option {
+	return func(c *client) {
+		c.basicAuth = &basicAuth{username: username, getPassword: getPassword}
+	}
+}
+
+// basicAuth holds the credentials of the AuthBasic auth method
+type basicAuth struct {
+	username    string
+	getPassword func() []byte
+}
+
+// parseAuthMethods splits the auth method into the methods chained with
+// commas and validates them
+func (c *client) parseAuthMethods(authMethod string) ([]string, error) {
+	if authMethod == "" {
+		return nil, nil
+	}
+	var methods []string
+	chained := map[string]bool{}
+	for _, method := range strings.Split(authMethod, ",") {
+		method = strings.TrimSpace(method)
+		switch method {
+		case AuthBearer, AuthQuery, AuthXBugzillaAPIKey, AuthToken, AuthBasic:
+		default:
+			return nil, fmt.Errorf("invalid auth-method %s. Valid values are bearer,query,x-bugzilla-api-key,token or basic, or several of them separated by commas", method)
+		}
+		if chained[method] {
+			return nil, fmt.Errorf("auth-method %s is chained more than once", method)
+		}
+		chained[method] = true
+		methods = append(methods, method)
+	}
+	if chained[AuthBasic] && chained[AuthBearer] {
+		return nil, fmt.Errorf("auth-methods %s and %s cannot be chained, both use the Authorization header", AuthBasic, AuthBearer)
+	}
+	if chained[AuthToken] && len(methods) > 1 && !(len(methods) == 2 && chained[AuthBasic]) {
+		return nil, fmt.Errorf("auth-method %s can only be chained with %s", AuthToken, AuthBasic)
+	}
+	if chained[AuthToken] && c.session == nil {
+		return nil, fmt.Errorf("auth-method %s requires a username and password, see WithLogin", AuthToken)
+	}
+	if chained[AuthBasic] && c.basicAuth == nil {
+		return nil, fmt.Errorf("auth-method %s requires a username and password, see WithBasicAuth", AuthBasic)
+	}
+	return methods, nil
+}
+
+// usesAuthMethod returns true if the method is one of the chained auth methods
+func (c *client) usesAuthMethod(method string) bool {
+	for _, chained := range c.authMethods() {
+		if chained == method {
+			return true
+		}
+	}
+	return false
+}
+
+// setBasicAuth sets the Authorization: Basic header if the AuthBasic auth
+// method is used
+func (c *client) setBasicAuth(req *http.Request) {
+	if c.usesAuthMethod(AuthBasic) {
+		req.SetBasicAuth(c.basicAuth.username, string(c.basicAuth.getPassword()))
+	}
+}
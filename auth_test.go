@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestChainedAuth(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		authMethod     string
+		login          bool
+		expectedAPIKey bool
+		expectedQuery  bool
+		expectedToken  bool
+	}{
+		{
+			name:           "basic and x-bugzilla-api-key",
+			authMethod:     "basic,x-bugzilla-api-key",
+			expectedAPIKey: true,
+		},
+		{
+			name:          "basic and query with spaces",
+			authMethod:    "basic, query",
+			expectedQuery: true,
+		},
+		{
+			name:       "basic only",
+			authMethod: AuthBasic,
+		},
+		{
+			name:          "basic and token",
+			authMethod:    "token,basic",
+			login:         true,
+			expectedToken: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if username, password, ok := r.BasicAuth(); !ok || username != "proxy" || password != "proxy-secret" {
+					t.Errorf("%s: did not get the basic auth credentials for %s", testCase.name, r.URL.Path)
+					http.Error(w, "407 Proxy Authentication Required", http.StatusProxyAuthRequired)
+					return
+				}
+				if r.URL.Path == "/rest/login" {
+					fmt.Fprint(w, `{"id":1,"token":"token"}`)
+					return
+				}
+				if got := r.Header.Get("X-BUGZILLA-API-KEY") == "api-key"; got != testCase.expectedAPIKey {
+					t.Errorf("%s: expected the X-BUGZILLA-API-KEY header %v, got %v", testCase.name, testCase.expectedAPIKey, got)
+				}
+				if got := r.URL.Query().Get("api_key") == "api-key"; got != testCase.expectedQuery {
+					t.Errorf("%s: expected the api_key query parameter %v, got %v", testCase.name, testCase.expectedQuery, got)
+				}
+				if got := r.URL.Query().Get("token") == "token"; got != testCase.expectedToken {
+					t.Errorf("%s: expected the token query parameter %v, got %v", testCase.name, testCase.expectedToken, got)
+				}
+				w.Write(bugData)
+			}))
+			defer testServer.Close()
+			c := clientForUrl(testServer.URL).(*client)
+			WithBasicAuth("proxy", func() []byte { return []byte("proxy-secret") })(c)
+			if testCase.login {
+				WithLogin("user", "secret")(c)
+			}
+			if err := c.SetAuthMethod(testCase.authMethod); err != nil {
+				t.Fatalf("%s: expected no error setting auth method, but got one: %v", testCase.name, err)
+			}
+			if _, err := c.GetBug(1705243); err != nil {
+				t.Errorf("%s: expected no error, but got one: %v", testCase.name, err)
+			}
+		})
+	}
+}
+
+func TestInvalidAuthChains(t *testing.T) {
+	for _, authMethod := range []string{"basic,bearer", "basic,basic", "basic,", "token,query", "token,basic,query", "garbage,basic"} {
+		c := clientForUrl("").(*client)
+		WithBasicAuth("proxy", func() []byte { return []byte("proxy-secret") })(c)
+		WithLogin("user", "secret")(c)
+		if err := c.SetAuthMethod(authMethod); err == nil {
+			t.Errorf("expected an error setting the auth method %q", authMethod)
+		}
+	}
+	if err := clientForUrl("").SetAuthMethod("basic,x-bugzilla-api-key"); err == nil {
+		t.Error("expected an error chaining basic without basic auth credentials")
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package autofile files bugs for CI failures without filing duplicates: a
+// failure is identified by a fingerprint kept in the whiteboard of its bug,
+// so a failure which happens again is commented on the open bug filed for it
+// the first time instead.
+package autofile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eparis/bugzilla"
+	"github.com/eparis/bugzilla/routing"
+)
+
+// FingerprintWhiteboardKey is the key of the whiteboard token holding the
+// fingerprint of the failure a bug was filed for, e.g.
+// `ci-fingerprint:3f2a9c0d1e4b5a6f`
+const FingerprintWhiteboardKey = "ci-fingerprint"
+
+// Fingerprint returns a fingerprint for the failure identified by the parts,
+// like the name of the job and of the failed test
+func Fingerprint(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// FailureReport describes a CI failure
+type FailureReport struct {
+	// Fingerprint identifies the failure and may not contain whitespace. It
+	// defaults to the Fingerprint of the summary.
+	Fingerprint string
+	// Summary is the summary of a new bug.
+	Summary string
+	// Description is the description of a new bug, or the comment on the
+	// existing bug for the failure.
+	Description string
+	// Org, Repo and Path locate the failure in GitHub, for the routing table
+	// of the Filer.
+	Org, Repo, Path string
+	// Bug holds the other fields of a new bug, like the product, component,
+	// version or severity. Its summary, description and whiteboard are set
+	// from the report.
+	Bug bugzilla.BugCreate
+	// Attachments, like logs, are attached to a new bug.
+	Attachments []bugzilla.AttachmentCreate
+}
+
+// Result is the outcome of filing a failure
+type Result struct {
+	// ID is the ID of the bug for the failure.
+	ID int
+	// Created is set if the bug was filed for the failure, instead of
+	// commenting on an existing bug.
+	Created bool
+	// Attachments are the IDs of the attachments added to the bug.
+	Attachments []int
+}
+
+// Filer files bugs for CI failures
+type Filer struct {
+	Client bugzilla.Client
+	// Routes, if set, decides the product and component of new bugs from the
+	// org, repo and path of the failure, unless the report sets a product.
+	Routes *routing.Table
+}
+
+// FileIssueFromFailure comments on the open bug filed for the failure before,
+// if any, or files a new bug for it with its attachments. If an attachment
+// can not be added, the result holds the new bug along with the error.
+func (f *Filer) FileIssueFromFailure(report FailureReport) (*Result, error) {
+	fingerprint := report.Fingerprint
+	if fingerprint == "" {
+		fingerprint = Fingerprint(report.Summary)
+	}
+	if strings.ContainsAny(fingerprint, " \t\r\n") {
+		return nil, fmt.Errorf("fingerprint %q may not contain whitespace", fingerprint)
+	}
+	existing, err := f.find(fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	if existing != 0 {
+		if err := f.Client.UpdateBug(existing, bugzilla.BugUpdate{Comment: &bugzilla.BugComment{Body: report.Description}}); err != nil {
+			return nil, fmt.Errorf("could not comment on bug %d for failure %s: %v", existing, fingerprint, err)
+		}
+		return &Result{ID: existing}, nil
+	}
+
+	create := report.Bug
+	create.Summary = report.Summary
+	create.Description = report.Description
+	create.Whiteboard = bugzilla.SetWhiteboardToken(create.Whiteboard, FingerprintWhiteboardKey, fingerprint)
+	if create.Product == "" && f.Routes != nil {
+		destination, ok := f.Routes.Lookup(report.Org, report.Repo, report.Path)
+		if !ok {
+			return nil, fmt.Errorf("no route for failure %s in %s/%s", fingerprint, report.Org, report.Repo)
+		}
+		destination.Apply(&create)
+	}
+	id, err := f.Client.CreateBug(create)
+	if err != nil {
+		return nil, fmt.Errorf("could not file bug for failure %s: %v", fingerprint, err)
+	}
+	result := &Result{ID: id, Created: true}
+	for _, attachment := range report.Attachments {
+		attachmentID, err := f.Client.CreateAttachment(id, attachment)
+		if err != nil {
+			return result, fmt.Errorf("filed bug %d for failure %s, but could not attach %s: %v", id, fingerprint, attachment.FileName, err)
+		}
+		result.Attachments = append(result.Attachments, attachmentID)
+	}
+	return result, nil
+}
+
+// find returns the ID of the oldest open bug filed for the failure, or 0
+func (f *Filer) find(fingerprint string) (int, error) {
+	bugs, err := f.Client.Search(bugzilla.Query{
+		Advanced: []bugzilla.AdvancedQuery{{
+			Field: "status_whiteboard",
+			Op:    "substring",
+			Value: FingerprintWhiteboardKey + ":" + fingerprint,
+		}},
+		IncludeFields: []string{"id", "is_open", "whiteboard"},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("could not search for bugs for failure %s: %v", fingerprint, err)
+	}
+	// the search matches substrings, so the token is checked again
+	var ids []int
+	for _, bug := range bugs {
+		if token, ok := bugzilla.WhiteboardToken(bug.Whiteboard, FingerprintWhiteboardKey); ok && token == fingerprint && bug.IsOpen {
+			ids = append(ids, bug.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	sort.Ints(ids)
+	return ids[0], nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autofile
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/eparis/bugzilla"
+	"github.com/eparis/bugzilla/routing"
+)
+
+func TestFileIssueFromFailure(t *testing.T) {
+	fingerprint := Fingerprint("e2e-aws", "[sig-network] services should work")
+	fake := &bugzilla.Fake{Bugs: map[int]bugzilla.Bug{
+		1: {ID: 1, Whiteboard: "ci-fingerprint:" + fingerprint, IsOpen: false},
+		2: {ID: 2, Whiteboard: "ci-fingerprint:" + fingerprint + "0", IsOpen: true},
+	}}
+	routes := &routing.Table{Routes: []routing.Route{{Org: "openshift", Destination: routing.Destination{Product: "OCP", Component: "Networking"}}}}
+	filer := &Filer{Client: fake, Routes: routes}
+	report := FailureReport{
+		Fingerprint: fingerprint,
+		Summary:     "services should work fails",
+		Description: "Failed in https://ci.example.com/1",
+		Org:         "openshift",
+		Repo:        "origin",
+		Bug:         bugzilla.BugCreate{Version: "4.6", Whiteboard: "ci"},
+		Attachments: []bugzilla.AttachmentCreate{{FileName: "build.log", ContentType: "text/plain", Data: []byte("log")}},
+	}
+
+	result, err := filer.FileIssueFromFailure(report)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if expected := (&Result{ID: 3, Created: true, Attachments: []int{1}}); !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected a new bug for the failure, got %+v", result)
+	}
+	bug := fake.Bugs[3]
+	if bug.Product != "OCP" || !reflect.DeepEqual(bug.Component, []string{"Networking"}) || bug.Whiteboard != "ci ci-fingerprint:"+fingerprint {
+		t.Errorf("filed an incorrect bug: %+v", bug)
+	}
+	if data, err := fake.GetAttachmentData(1); err != nil || string(data) != "log" {
+		t.Errorf("expected the log to be attached, got %q, %v", data, err)
+	}
+
+	report.Description = "Failed again in https://ci.example.com/2"
+	result, err = filer.FileIssueFromFailure(report)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if expected := (&Result{ID: 3}); !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected the existing bug to be commented on, got %+v", result)
+	}
+	if comments := fake.BugComments[3]; len(comments) != 2 || comments[1].Text != report.Description {
+		t.Errorf("expected a comment for the recurrence, got %v", comments)
+	}
+	if len(fake.Bugs) != 3 {
+		t.Errorf("expected no duplicate to be filed, got %d bugs", len(fake.Bugs))
+	}
+}
+
+func TestFileIssueFromFailureErrors(t *testing.T) {
+	filer := &Filer{Client: &bugzilla.Fake{}, Routes: &routing.Table{}}
+	if _, err := filer.FileIssueFromFailure(FailureReport{Fingerprint: "two words"}); err == nil {
+		t.Error("expected an error for a fingerprint with whitespace, got none")
+	}
+	if _, err := filer.FileIssueFromFailure(FailureReport{Summary: "broken", Org: "elsewhere"}); err == nil {
+		t.Error("expected an error for a failure without a route, got none")
+	}
+}
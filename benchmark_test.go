@@ -0,0 +1,152 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// The benchmarks cover the hot paths of the client, so that changes made for
+// performance can be measured. Compare a change against its base with:
+//
+//	go test -run '^$' -bench . -benchmem -count 5 > old.txt # on the base
+//	go test -run '^$' -bench . -benchmem -count 5 > new.txt # with the change
+//	go run ./cmd/bench-compare old.txt new.txt
+
+// sampleBug decodes the bug used throughout the tests
+func sampleBug(b *testing.B) Bug {
+	var response struct {
+		Bugs []Bug `json:"bugs"`
+	}
+	if err := json.Unmarshal(bugData, &response); err != nil {
+		b.Fatalf("could not decode sample bug: %v", err)
+	}
+	return response.Bugs[0]
+}
+
+// encodeBugs encodes the bugs like a response holding a list of bugs
+func encodeBugs(b *testing.B, bugs []Bug) []byte {
+	raw, err := json.Marshal(struct {
+		Bugs []Bug `json:"bugs"`
+	}{Bugs: bugs})
+	if err != nil {
+		b.Fatalf("could not encode bugs: %v", err)
+	}
+	return raw
+}
+
+// largeBug returns a bug with long lists, like a bug everyone is watching
+func largeBug(b *testing.B) Bug {
+	bug := sampleBug(b)
+	for i := 0; i < 500; i++ {
+		user := User{ID: i, Name: fmt.Sprintf("user%d", i), RealName: fmt.Sprintf("User %d", i), Email: fmt.Sprintf("user%d@example.com", i)}
+		bug.CC = append(bug.CC, user.Email)
+		bug.CCDetail = append(bug.CCDetail, user)
+		bug.Blocks = append(bug.Blocks, 1000000+i)
+		bug.DependsOn = append(bug.DependsOn, 2000000+i)
+	}
+	for i := 0; i < 50; i++ {
+		bug.Keywords = append(bug.Keywords, fmt.Sprintf("Keyword%d", i))
+		bug.Flags = append(bug.Flags, Flag{ID: i, Name: fmt.Sprintf("flag%d", i), Status: "+", Setter: "user0@example.com"})
+	}
+	bug.Whiteboard = strings.Repeat("whiteboard ", 100)
+	return bug
+}
+
+// searchPageOf returns a search response holding count bugs, starting at ID first
+func searchPageOf(b *testing.B, first, count int) []byte {
+	bug := sampleBug(b)
+	bugs := make([]Bug, count)
+	for i := range bugs {
+		bugs[i] = bug
+		bugs[i].ID = first + i
+	}
+	return encodeBugs(b, bugs)
+}
+
+func BenchmarkDecodeLargeBug(b *testing.B) {
+	c := clientForUrl("").(*client)
+	raw := encodeBugs(b, []Bug{largeBug(b)})
+	b.SetBytes(int64(len(raw)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.decodeBugs(raw); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeSearchPage(b *testing.B) {
+	c := clientForUrl("").(*client)
+	raw := searchPageOf(b, 1, 10000)
+	b.SetBytes(int64(len(raw)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.decodeSearchPage(raw, Query{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSearchPagination(b *testing.B) {
+	const total, pageSize = 1000, 100
+	pages := map[int][]byte{}
+	for offset := 0; offset < total; offset += pageSize {
+		pages[offset] = searchPageOf(b, offset+1, pageSize)
+	}
+	pages[total] = encodeBugs(b, nil)
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		w.Write(pages[offset])
+	}))
+	defer testServer.Close()
+	c := clientForUrl(testServer.URL)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bugs, err := c.Search(Query{})
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(bugs) != total {
+			b.Fatalf("expected %d bugs, got %d", total, len(bugs))
+		}
+	}
+}
+
+func BenchmarkBulkGetBugs(b *testing.B) {
+	ids := make([]int, 100)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bugData)
+	}))
+	defer testServer.Close()
+	c := clientForUrl(testServer.URL)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.BulkGetBugs(ids, 10); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
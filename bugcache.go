@@ -0,0 +1,226 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WithBugCache caches the bugs retrieved with GetBug. A cached bug is served
+// without asking the server for the TTL. After that, only the time the bug
+// last changed is retrieved, and the cached bug is served again if the bug
+// did not change since. Bugs changed through the client, like with UpdateBug,
+// are dropped from the cache, other bugs can be dropped with
+// InvalidateCachedBug when they are known to have changed.
+func WithBugCache(ttl time.Duration) Option {
+	return func(c *client) {
+		c.bugCache = &bugCache{ttl: ttl, entries: map[int]*bugCacheEntry{}}
+	}
+}
+
+// InvalidateCachedBug drops the bug from the cache of the client, if it has
+// one, so the next GetBug retrieves it from the server
+func InvalidateCachedBug(c Client, id int) {
+	if original, ok := c.(*client); ok && original.bugCache != nil {
+		original.bugCache.invalidate(id)
+	}
+}
+
+type bugCache struct {
+	ttl time.Duration
+
+	lock    sync.Mutex
+	entries map[int]*bugCacheEntry
+}
+
+type bugCacheEntry struct {
+	// raw is the response holding the bug, it is decoded for every call so
+	// callers can not change the cached bug
+	raw        []byte
+	aliases    []string
+	lastChange time.Time
+	checked    time.Time
+}
+
+func (b *bugCache) get(id int) *bugCacheEntry {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	entry, ok := b.entries[id]
+	if !ok {
+		return nil
+	}
+	copy := *entry
+	return &copy
+}
+
+func (b *bugCache) put(id int, entry *bugCacheEntry) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.entries[id] = entry
+}
+
+// checked records that the cached bug is still current at the time
+func (b *bugCache) checked(id int, lastChange, at time.Time) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if entry, ok := b.entries[id]; ok && entry.lastChange.Equal(lastChange) {
+		entry.checked = at
+	}
+}
+
+func (b *bugCache) invalidate(id int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	delete(b.entries, id)
+}
+
+func (b *bugCache) invalidateAlias(alias string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for id, entry := range b.entries {
+		for _, entryAlias := range entry.aliases {
+			if entryAlias == alias {
+				delete(b.entries, id)
+			}
+		}
+	}
+}
+
+func (b *bugCache) invalidateAll() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.entries = map[int]*bugCacheEntry{}
+}
+
+// changedBugsKey holds the bugs a request changes in its context, see
+// changesBugs
+type changedBugsKey struct{}
+
+// changedBugs are the bugs a request changes
+type changedBugs struct {
+	ids     []int
+	aliases []string
+}
+
+// declareChanges declares the bugs the request changes, so only those are
+// dropped from the bug cache. Requests which may change bugs without
+// declaring which drop all cached bugs.
+func declareChanges(req *http.Request, changed changedBugs) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), changedBugsKey{}, changed))
+}
+
+// changesBugs declares that the request changes the bugs with the IDs
+func changesBugs(req *http.Request, ids ...int) *http.Request {
+	return declareChanges(req, changedBugs{ids: ids})
+}
+
+// invalidateChanged drops the bugs the request declared to change, or all
+// bugs if it did not declare which
+func (b *bugCache) invalidateChanged(req *http.Request) {
+	changed, ok := req.Context().Value(changedBugsKey{}).(changedBugs)
+	if !ok {
+		b.invalidateAll()
+		return
+	}
+	for _, id := range changed.ids {
+		b.invalidate(id)
+	}
+	for _, alias := range changed.aliases {
+		b.invalidateAlias(alias)
+	}
+}
+
+// updatedBugs returns the bugs the update of the bugs with the IDs changes,
+// which includes the bugs it adds or removes as blockers, dependencies or
+// the original of a duplicate, and false if it can not tell which bugs it
+// changes, like when it replaces the blockers
+func updatedBugs(update BugUpdate, ids ...int) ([]int, bool) {
+	changed := append([]int{}, ids...)
+	for _, related := range []*BugIDs{update.Blocks, update.DependsOn} {
+		if related == nil {
+			continue
+		}
+		if related.Set != nil {
+			return nil, false
+		}
+		changed = append(append(changed, related.Add...), related.Remove...)
+	}
+	if update.DupeOf != nil {
+		changed = append(changed, *update.DupeOf)
+	}
+	return changed, true
+}
+
+// changesUpdatedBugs declares the bugs the update of the bugs with the IDs
+// changes, see updatedBugs
+func changesUpdatedBugs(req *http.Request, update BugUpdate, ids ...int) *http.Request {
+	changed, ok := updatedBugs(update, ids...)
+	if !ok {
+		return req
+	}
+	return changesBugs(req, changed...)
+}
+
+// cachedBug serves the bug from the cache if it is current, and retrieves
+// and caches it otherwise
+func (c *client) cachedBug(id int, logger *logEntry) (*Bug, error) {
+	if entry := c.bugCache.get(id); entry != nil {
+		now := c.now()
+		if now.Sub(entry.checked) < c.bugCache.ttl {
+			return c.decodeBug(entry.raw)
+		}
+		current, err := c.getBug(id, Fields{Include: []string{"id", "last_change_time"}}, logger.WithField("revalidate", true))
+		if err != nil {
+			return nil, err
+		}
+		if current.LastChangeTime.Equal(entry.lastChange) {
+			c.bugCache.checked(id, entry.lastChange, now)
+			return c.decodeBug(entry.raw)
+		}
+	}
+	checked := c.now()
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/rest/bug/%d", c.endpoint, id), nil)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := c.request(req, logger)
+	if err != nil {
+		return nil, err
+	}
+	bug, err := c.decodeBug(raw)
+	if err != nil {
+		return nil, err
+	}
+	c.bugCache.put(id, &bugCacheEntry{raw: raw, aliases: bug.Alias, lastChange: bug.LastChangeTime.Time, checked: checked})
+	return bug, nil
+}
+
+// decodeBug decodes the bug in a response holding one bug
+func (c *client) decodeBug(raw []byte) (*Bug, error) {
+	bugs, err := c.decodeBugs(raw)
+	if err != nil {
+		return nil, err
+	}
+	if len(bugs) != 1 {
+		return nil, fmt.Errorf("did not get one bug, but %d: %v", len(bugs), bugs)
+	}
+	return bugs[0], nil
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBugCache(t *testing.T) {
+	lastChange := "2019-05-17T15:13:13Z"
+	var full, revalidations int
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			lastChange = "2019-05-18T10:00:00Z"
+			w.Write([]byte(`{"bugs":[{"id":1705243}]}`))
+			return
+		}
+		if r.URL.Query().Get("include_fields") == "id,last_change_time" {
+			revalidations++
+			fmt.Fprintf(w, `{"bugs":[{"id":1705243,"last_change_time":%q}]}`, lastChange)
+			return
+		}
+		full++
+		fmt.Fprintf(w, `{"bugs":[{"id":1705243,"alias":["my-alias"],"status":"NEW","last_change_time":%q}]}`, lastChange)
+	}))
+	defer testServer.Close()
+	clock := &fakeClock{now: time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)}
+	c := clientForUrl(testServer.URL).(*client)
+	WithClock(clock)(c)
+	WithBugCache(time.Minute)(c)
+
+	get := func(expectedFull, expectedRevalidations int) {
+		t.Helper()
+		bug, err := c.GetBug(1705243)
+		if err != nil {
+			t.Fatalf("expected no error getting the bug, got %v", err)
+		}
+		if bug.ID != 1705243 || bug.Status != "NEW" {
+			t.Errorf("got incorrect bug: %+v", bug)
+		}
+		// callers changing the bug must not change the cache
+		bug.Status = "CLOSED"
+		if full != expectedFull || revalidations != expectedRevalidations {
+			t.Errorf("expected %d full and %d revalidating requests, got %d and %d", expectedFull, expectedRevalidations, full, revalidations)
+		}
+	}
+
+	get(1, 0)
+	get(1, 0)
+	clock.now = clock.now.Add(2 * time.Minute)
+	get(1, 1)
+	// the revalidation restarts the TTL
+	get(1, 1)
+
+	if err := c.UpdateBug(1705243, BugUpdate{Status: "NEW"}); err != nil {
+		t.Fatalf("expected no error updating the bug, got %v", err)
+	}
+	get(2, 1)
+
+	InvalidateCachedBug(c, 1705243)
+	get(3, 1)
+	if err := c.UpdateBugByAlias("my-alias", BugUpdate{Status: "NEW"}); err != nil {
+		t.Fatalf("expected no error updating the bug, got %v", err)
+	}
+	get(4, 1)
+
+	// a bug which changed elsewhere is retrieved again once the TTL passed
+	lastChange = "2019-05-19T10:00:00Z"
+	get(4, 1)
+	clock.now = clock.now.Add(2 * time.Minute)
+	get(5, 2)
+}
+
+func TestBugCacheInvalidation(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		write    func(c *client) error
+		expected []int
+	}{
+		{
+			name: "updates drop the updated bug",
+			write: func(c *client) error {
+				return c.UpdateBug(1, BugUpdate{Status: "POST"})
+			},
+			expected: []int{1},
+		},
+		{
+			name: "updates drop the bugs added or removed as blockers and dependencies",
+			write: func(c *client) error {
+				return c.UpdateBug(1, BugUpdate{Blocks: &BugIDs{Add: []int{2}}, DependsOn: &BugIDs{Remove: []int{3}}})
+			},
+			expected: []int{1, 2, 3},
+		},
+		{
+			name: "updates replacing blockers drop all bugs",
+			write: func(c *client) error {
+				return c.UpdateBug(1, BugUpdate{Blocks: &BugIDs{Set: []int{2}}})
+			},
+			expected: []int{1, 2, 3},
+		},
+		{
+			name: "new bugs drop the bugs they block",
+			write: func(c *client) error {
+				_, err := c.CreateBug(BugCreate{Summary: "new", Blocks: []int{2}})
+				return err
+			},
+			expected: []int{2},
+		},
+		{
+			name: "tagging comments drops all bugs",
+			write: func(c *client) error {
+				_, err := c.UpdateCommentTags(10, []string{"spam"}, nil)
+				return err
+			},
+			expected: []int{1, 2, 3},
+		},
+		{
+			name: "writes to the path of a bug drop the bug",
+			write: func(c *client) error {
+				return c.Do(context.Background(), http.MethodPost, "/rest/bug/3/comment", map[string]string{"comment": "text"}, nil)
+			},
+			expected: []int{3},
+		},
+		{
+			name: "other writes drop all bugs",
+			write: func(c *client) error {
+				return c.Do(context.Background(), http.MethodPost, "/rest/component", map[string]string{"name": "new"}, nil)
+			},
+			expected: []int{1, 2, 3},
+		},
+		{
+			name: "reads through Do drop no bugs",
+			write: func(c *client) error {
+				return c.Do(context.Background(), http.MethodGet, "/rest/bug/3/comment", nil, nil)
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var fetched []int
+			testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/comment"):
+					w.Write([]byte(`{"bugs":{}}`))
+				case r.Method == http.MethodGet:
+					var id int
+					fmt.Sscanf(r.URL.Path, "/rest/bug/%d", &id)
+					fetched = append(fetched, id)
+					fmt.Fprintf(w, `{"bugs":[{"id":%d,"last_change_time":"2019-05-17T15:13:13Z"}]}`, id)
+				case r.URL.Path == "/rest/bug":
+					w.Write([]byte(`{"id":4}`))
+				case strings.HasSuffix(r.URL.Path, "/tags"):
+					w.Write([]byte(`["spam"]`))
+				default:
+					w.Write([]byte(`{}`))
+				}
+			}))
+			defer testServer.Close()
+			c := clientForUrl(testServer.URL).(*client)
+			WithBugCache(time.Hour)(c)
+
+			for _, id := range []int{1, 2, 3} {
+				if _, err := c.GetBug(id); err != nil {
+					t.Fatalf("%s: expected no error getting bug %d, got %v", testCase.name, id, err)
+				}
+			}
+			if err := testCase.write(c); err != nil {
+				t.Fatalf("%s: expected no error writing, got %v", testCase.name, err)
+			}
+			fetched = nil
+			for _, id := range []int{1, 2, 3} {
+				if _, err := c.GetBug(id); err != nil {
+					t.Fatalf("%s: expected no error getting bug %d, got %v", testCase.name, id, err)
+				}
+			}
+			if !reflect.DeepEqual(fetched, testCase.expected) {
+				t.Errorf("%s: expected bugs %v to be dropped, got %v", testCase.name, testCase.expected, fetched)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DiffBugs computes the smallest update which changes the old bug into the
+// new one, so controllers can declare the state a bug should be in. Fields
+// the server maintains, like LastChangeTime, IsOpen or the user details, are
+// ignored. It fails if the bugs differ in a way no update can express, e.g.
+// if the new bug has several components or changes a field which can not be
+// updated, like the platform.
+func DiffBugs(old, new *Bug) (BugUpdate, error) {
+	var update BugUpdate
+	if old.ID != new.ID {
+		return update, fmt.Errorf("can not diff different bugs %d and %d", old.ID, new.ID)
+	}
+	var problems []string
+	unsupported := func(field string, changed bool) {
+		if changed {
+			problems = append(problems, fmt.Sprintf("%s can not be updated", field))
+		}
+	}
+	unsupported("op_sys", old.OperatingSystem != new.OperatingSystem)
+	unsupported("platform", old.Platform != new.Platform)
+	unsupported("docs_contact", old.DocsContact != new.DocsContact)
+	unsupported("estimated_time", old.EstimatedTime != new.EstimatedTime)
+	unsupported("remaining_time", old.RemainingTime != new.RemainingTime)
+	unsupported("is_cc_accessible", old.IsCCAccessible != new.IsCCAccessible)
+	unsupported("is_creator_accessible", old.IsCreatorAccessible != new.IsCreatorAccessible)
+	unsupported("external_bugs", !reflect.DeepEqual(old.ExternalBugs, new.ExternalBugs))
+
+	// set sets a string field which changed, clearing it if clearable
+	set := func(field string, oldValue, newValue string, target *string, clearable bool) {
+		switch {
+		case oldValue == newValue:
+		case newValue != "":
+			*target = newValue
+		case clearable:
+			update.ClearFields = append(update.ClearFields, field)
+		default:
+			problems = append(problems, fmt.Sprintf("%s can not be cleared", field))
+		}
+	}
+	set("status", old.Status, new.Status, &update.Status, false)
+	// the server clears the resolution when the bug is reopened
+	if update.Status == "" || new.Resolution != "" {
+		set("resolution", old.Resolution, new.Resolution, &update.Resolution, false)
+	}
+	set("target_milestone", old.TargetMilestone, new.TargetMilestone, &update.TargetMilestone, false)
+	set("summary", old.Summary, new.Summary, &update.Summary, false)
+	set("product", old.Product, new.Product, &update.Product, false)
+	set("url", old.URL, new.URL, &update.URL, true)
+	set("whiteboard", old.Whiteboard, new.Whiteboard, &update.Whiteboard, true)
+	set("cf_devel_whiteboard", old.DevelWhiteboard, new.DevelWhiteboard, &update.DevWhiteboard, true)
+	set("priority", old.Priority, new.Priority, &update.Priority, false)
+	set("severity", old.Severity, new.Severity, &update.Severity, false)
+	if old.AssignedTo != new.AssignedTo {
+		update.AssignedTo = new.AssignedTo
+		update.ResetAssignedTo = new.AssignedTo == ""
+	}
+	if old.QAContact != new.QAContact {
+		update.QAContact = new.QAContact
+		update.ResetQAContact = new.QAContact == ""
+	}
+	if old.Deadline != new.Deadline {
+		deadline := new.Deadline
+		update.Deadline = &deadline
+	}
+	if old.DupeOf != new.DupeOf {
+		if new.DupeOf == 0 {
+			problems = append(problems, "dupe_of can not be cleared")
+		} else {
+			dupeOf := new.DupeOf
+			update.DupeOf = &dupeOf
+		}
+	}
+
+	// single sets a field which bugs have one value for
+	single := func(field string, oldValues, newValues []string, target *string) {
+		if add, remove := diffStrings(oldValues, newValues); add == nil && remove == nil {
+			return
+		}
+		if len(newValues) != 1 {
+			problems = append(problems, fmt.Sprintf("%s must have one value, not %d", field, len(newValues)))
+			return
+		}
+		*target = newValues[0]
+	}
+	single("component", old.Component, new.Component, &update.Component)
+	single("version", old.Version, new.Version, &update.Version)
+	single("target_release", old.TargetRelease, new.TargetRelease, &update.TargetRelease)
+
+	if add, remove := diffStrings(old.Alias, new.Alias); len(add)+len(remove) > 0 {
+		update.Alias = &BugAliases{Add: add, Remove: remove}
+	}
+	if add, remove := diffStrings(old.Keywords, new.Keywords); len(add)+len(remove) > 0 {
+		update.Keywords = &BugKeywords{Add: add, Remove: remove}
+	}
+	if add, remove := diffStrings(old.CC, new.CC); len(add)+len(remove) > 0 {
+		update.CC = &BugCC{Add: add, Remove: remove}
+	}
+	if add, remove := diffInts(old.DependsOn, new.DependsOn); len(add)+len(remove) > 0 {
+		update.DependsOn = &BugIDs{Add: add, Remove: remove}
+	}
+	if add, remove := diffInts(old.Blocks, new.Blocks); len(add)+len(remove) > 0 {
+		update.Blocks = &BugIDs{Add: add, Remove: remove}
+	}
+	if add, remove := diffStrings(old.Groups, new.Groups); len(add)+len(remove) > 0 {
+		update.Groups = &BugGroups{Add: add, Remove: remove}
+	}
+	if add, remove := diffStrings(old.SeeAlso, new.SeeAlso); len(add)+len(remove) > 0 {
+		update.SeeAlso = &BugSeeAlso{Add: add, Remove: remove}
+	}
+	update.Flags = diffFlags(old.Flags, new.Flags)
+	if !reflect.DeepEqual(old.SubComponent, new.SubComponent) {
+		if len(new.SubComponent) == 0 {
+			problems = append(problems, "sub_components can not be cleared")
+		} else {
+			update.SubComponents = new.SubComponent
+		}
+	}
+	if !reflect.DeepEqual(old.Verified, new.Verified) {
+		if len(new.Verified) == 0 {
+			update.ClearFields = append(update.ClearFields, "cf_verified")
+		} else {
+			update.Verified = new.Verified
+		}
+	}
+
+	oldCustom, newCustom := customFieldValues(old), customFieldValues(new)
+	for field, value := range newCustom {
+		if oldValue, ok := oldCustom[field]; !ok || !reflect.DeepEqual(oldValue, value) {
+			if update.CustomFields == nil {
+				update.CustomFields = map[string]interface{}{}
+			}
+			update.CustomFields[field] = value
+		}
+	}
+	for field := range oldCustom {
+		if _, ok := newCustom[field]; !ok {
+			update.ClearFields = append(update.ClearFields, field)
+		}
+	}
+	sort.Strings(update.ClearFields)
+
+	if len(problems) > 0 {
+		return BugUpdate{}, fmt.Errorf("can not update bug %d: %s", old.ID, strings.Join(problems, ", "))
+	}
+	return update, nil
+}
+
+// diffStrings returns the values to add and to remove to change the old
+// values into the new ones, in lexical order
+func diffStrings(old, new []string) ([]string, []string) {
+	oldValues, newValues := map[string]bool{}, map[string]bool{}
+	for _, value := range old {
+		oldValues[value] = true
+	}
+	for _, value := range new {
+		newValues[value] = true
+	}
+	var add, remove []string
+	for value := range newValues {
+		if !oldValues[value] {
+			add = append(add, value)
+		}
+	}
+	for value := range oldValues {
+		if !newValues[value] {
+			remove = append(remove, value)
+		}
+	}
+	sort.Strings(add)
+	sort.Strings(remove)
+	return add, remove
+}
+
+// diffInts returns the values to add and to remove to change the old values
+// into the new ones, in ascending order
+func diffInts(old, new []int) ([]int, []int) {
+	oldValues, newValues := map[int]bool{}, map[int]bool{}
+	for _, value := range old {
+		oldValues[value] = true
+	}
+	for _, value := range new {
+		newValues[value] = true
+	}
+	var add, remove []int
+	for value := range newValues {
+		if !oldValues[value] {
+			add = append(add, value)
+		}
+	}
+	for value := range oldValues {
+		if !newValues[value] {
+			remove = append(remove, value)
+		}
+	}
+	sort.Ints(add)
+	sort.Ints(remove)
+	return add, remove
+}
+
+// diffFlags returns the changes which set the flags of the new bug which
+// differ from the old bug and clear the flags the new bug does not have
+func diffFlags(old, new []Flag) []FlagChange {
+	oldFlags := map[string]Flag{}
+	for _, flag := range old {
+		oldFlags[flag.Name] = flag
+	}
+	var changes []FlagChange
+	newNames := map[string]bool{}
+	for _, flag := range new {
+		newNames[flag.Name] = true
+		if oldFlag, ok := oldFlags[flag.Name]; ok && oldFlag.Status == flag.Status && oldFlag.Requestee == flag.Requestee {
+			continue
+		}
+		changes = append(changes, FlagChange{Name: flag.Name, Status: flag.Status, Requestee: flag.Requestee})
+	}
+	for _, flag := range old {
+		if !newNames[flag.Name] {
+			changes = append(changes, FlagChange{Name: flag.Name, Status: FlagClear})
+			newNames[flag.Name] = true
+		}
+	}
+	return changes
+}
+
+// customFieldValues returns the custom fields of the bug, including those
+// which Bug has a field for but BugUpdate does not, by name
+func customFieldValues(bug *Bug) map[string]interface{} {
+	values := map[string]interface{}{}
+	for field, value := range bug.CustomFields {
+		values[field] = value
+	}
+	if bug.PMScore != "" {
+		values["cf_pm_score"] = bug.PMScore
+	}
+	if bug.Escalation != "" {
+		values["cf_cust_facing"] = bug.Escalation
+	}
+	return values
+}
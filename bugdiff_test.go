@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestDiffBugs(t *testing.T) {
+	deadline := "2020-06-01"
+	dupeOf := 2
+	var testCases = []struct {
+		name        string
+		old, new    Bug
+		expected    BugUpdate
+		expectedErr bool
+	}{
+		{
+			name: "identical bugs need no update",
+			old:  Bug{ID: 1, Status: "NEW", Keywords: []string{"a"}, Flags: []Flag{{Name: "qe", Status: "+"}}},
+			new:  Bug{ID: 1, Status: "NEW", Keywords: []string{"a"}, Flags: []Flag{{Name: "qe", Status: "+"}}},
+		},
+		{
+			name:     "changed fields are set",
+			old:      Bug{ID: 1, Status: "NEW", Summary: "old", Component: []string{"a"}, Deadline: "2020-01-01"},
+			new:      Bug{ID: 1, Status: "ASSIGNED", Summary: "new", Component: []string{"b"}, Deadline: deadline, DupeOf: dupeOf},
+			expected: BugUpdate{Status: "ASSIGNED", Summary: "new", Component: "b", Deadline: &deadline, DupeOf: &dupeOf},
+		},
+		{
+			name:     "removed values are cleared",
+			old:      Bug{ID: 1, Whiteboard: "wb", URL: "https://example.com", AssignedTo: "someone", CustomFields: map[string]interface{}{"cf_x": "y"}},
+			new:      Bug{ID: 1},
+			expected: BugUpdate{ClearFields: []string{"cf_x", "url", "whiteboard"}, ResetAssignedTo: true},
+		},
+		{
+			name:     "reopening does not clear the resolution",
+			old:      Bug{ID: 1, Status: "CLOSED", Resolution: "WONTFIX"},
+			new:      Bug{ID: 1, Status: "NEW"},
+			expected: BugUpdate{Status: "NEW"},
+		},
+		{
+			name: "sets of values are added and removed",
+			old:  Bug{ID: 1, Keywords: []string{"a", "b"}, CC: []string{"x"}, DependsOn: []int{1, 2}},
+			new:  Bug{ID: 1, Keywords: []string{"c", "b"}, CC: []string{"x"}, DependsOn: []int{3, 2}, Blocks: []int{4}},
+			expected: BugUpdate{
+				Keywords:  &BugKeywords{Add: []string{"c"}, Remove: []string{"a"}},
+				DependsOn: &BugIDs{Add: []int{3}, Remove: []int{1}},
+				Blocks:    &BugIDs{Add: []int{4}},
+			},
+		},
+		{
+			name: "flags are changed, added and cleared",
+			old:  Bug{ID: 1, Flags: []Flag{{Name: "qe", Status: "?"}, {Name: "dev", Status: "+"}, {Name: "pm", Status: "+"}}},
+			new:  Bug{ID: 1, Flags: []Flag{{Name: "qe", Status: "+"}, {Name: "pm", Status: "+"}, {Name: "docs", Status: "?", Requestee: "writer"}}},
+			expected: BugUpdate{Flags: []FlagChange{
+				{Name: "qe", Status: "+"},
+				{Name: "docs", Status: "?", Requestee: "writer"},
+				{Name: "dev", Status: FlagClear},
+			}},
+		},
+		{
+			name:     "groups are added and removed",
+			old:      Bug{ID: 1, Groups: []string{"private"}},
+			new:      Bug{ID: 1, Groups: []string{"security"}},
+			expected: BugUpdate{Groups: &BugGroups{Add: []string{"security"}, Remove: []string{"private"}}},
+		},
+		{
+			name:     "custom fields with their own fields are custom fields in the update",
+			old:      Bug{ID: 1, PMScore: "10"},
+			new:      Bug{ID: 1, PMScore: "20", Escalation: "Yes"},
+			expected: BugUpdate{CustomFields: map[string]interface{}{"cf_pm_score": "20", "cf_cust_facing": "Yes"}},
+		},
+		{
+			name:        "different bugs can not be diffed",
+			old:         Bug{ID: 1},
+			new:         Bug{ID: 2},
+			expectedErr: true,
+		},
+		{
+			name:        "several components can not be set",
+			old:         Bug{ID: 1, Component: []string{"a"}},
+			new:         Bug{ID: 1, Component: []string{"b", "c"}},
+			expectedErr: true,
+		},
+		{
+			name:        "fields without an update can not be changed",
+			old:         Bug{ID: 1, Platform: "x86_64"},
+			new:         Bug{ID: 1, Platform: "s390x"},
+			expectedErr: true,
+		},
+		{
+			name:        "required fields can not be cleared",
+			old:         Bug{ID: 1, Summary: "summary"},
+			new:         Bug{ID: 1},
+			expectedErr: true,
+		},
+		{
+			name:     "server maintained fields are ignored",
+			old:      Bug{ID: 1, LastChangeTime: Timestamp{time.Unix(1, 0)}, IsOpen: true},
+			new:      Bug{ID: 1, LastChangeTime: Timestamp{time.Unix(2, 0)}},
+			expected: BugUpdate{},
+		},
+	}
+	for _, testCase := range testCases {
+		actual, err := DiffBugs(&testCase.old, &testCase.new)
+		if testCase.expectedErr != (err != nil) {
+			t.Errorf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+			continue
+		}
+		if !reflect.DeepEqual(actual, testCase.expected) {
+			t.Errorf("%s: got incorrect update: %s", testCase.name, diff.ObjectReflectDiff(testCase.expected, actual))
+		}
+	}
+}
@@ -0,0 +1,306 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bugzillatest helps consumers of the bugzilla package test against
+// realistic payloads without network access. A Recorder records the
+// interactions of a client with a real server into a JSON fixture, with
+// credentials and other secrets removed, and replays them in CI:
+//
+//	recorder, err := bugzillatest.NewRecorder("testdata/triage.json", bugzillatest.ModeFromEnv())
+//	client := bugzilla.NewClient(getAPIKey, endpoint, bugzilla.WithTransport(recorder))
+//	... // use the client
+//	err = recorder.Save()
+package bugzillatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Mode is whether a Recorder records or replays interactions
+type Mode int
+
+const (
+	// ModeReplay answers requests with the recorded interactions
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the server and records the interactions
+	ModeRecord
+)
+
+// RecordEnv is the environment variable which makes ModeFromEnv record
+const RecordEnv = "BUGZILLA_RECORD"
+
+// ModeFromEnv returns ModeRecord if the RecordEnv environment variable is set
+// to a non-empty value and ModeReplay otherwise, so fixtures are replayed in
+// CI and can be recorded again by setting it.
+func ModeFromEnv() Mode {
+	if os.Getenv(RecordEnv) != "" {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// Redacted replaces credentials and secrets in fixtures
+const Redacted = "REDACTED"
+
+// Interaction is a request and the response of the server to it
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a sanitized request
+type Request struct {
+	Method string `json:"method"`
+	// URL is the path and query of the request, without the scheme and host so
+	// fixtures can be replayed against any endpoint.
+	URL  string `json:"url"`
+	Body string `json:"body,omitempty"`
+}
+
+// Response is a sanitized response
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// sensitiveParameters are the names of query parameters and body fields
+// holding credentials
+var sensitiveParameters = map[string]bool{
+	"api_key":           true,
+	"bugzilla_api_key":  true,
+	"bugzilla_login":    true,
+	"bugzilla_password": true,
+	"bugzilla_token":    true,
+	"login":             true,
+	"password":          true,
+	"token":             true,
+}
+
+// xmlrpcCredential matches the values of credentials in XML-RPC calls
+var xmlrpcCredential = regexp.MustCompile(`(?i)(<name>(?:api_key|bugzilla_api_key|bugzilla_login|bugzilla_password|bugzilla_token|login|password|token)</name>\s*<value>(?:<string>)?)[^<]*`)
+
+// Recorder is an http.RoundTripper which records interactions with the server
+// or replays them, see NewRecorder.
+type Recorder struct {
+	// Base is the transport used to send requests while recording,
+	// http.DefaultTransport if nil.
+	Base http.RoundTripper
+	// Secrets are replaced in the recorded requests and responses in addition
+	// to credentials, e.g. the e-mail address of the user.
+	Secrets []string
+
+	path string
+	mode Mode
+
+	lock         sync.Mutex
+	interactions []Interaction
+	replayed     []bool
+}
+
+// NewRecorder returns a recorder for the fixture at the path. In ModeReplay
+// the fixture is loaded and every recorded interaction answers one matching
+// request, by method, path, query and body, in order; in ModeRecord requests
+// are sent to the server and Save writes the fixture.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode}
+	if mode != ModeReplay {
+		return r, nil
+	}
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read fixture: %v", err)
+	}
+	if err := json.Unmarshal(raw, &r.interactions); err != nil {
+		return nil, fmt.Errorf("could not parse fixture %s: %v", path, err)
+	}
+	r.replayed = make([]bool, len(r.interactions))
+	return r, nil
+}
+
+// Interactions returns the interactions recorded or loaded so far
+func (r *Recorder) Interactions() []Interaction {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]Interaction{}, r.interactions...)
+}
+
+// Save writes the recorded interactions to the fixture. It does nothing when
+// replaying.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	raw, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(raw, '\n'), 0644)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	request, err := r.sanitizeRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.mode == ModeRecord {
+		return r.record(req, request)
+	}
+	return r.replay(req, request)
+}
+
+func (r *Recorder) record(req *http.Request, request Request) (*http.Response, error) {
+	base := r.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+	// the body may be shorter or longer once sanitized
+	header.Del("Content-Length")
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.interactions = append(r.interactions, Interaction{
+		Request: request,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       r.redact(sanitizeBody(string(body))),
+		},
+	})
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, request Request) (*http.Response, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for i, interaction := range r.interactions {
+		if r.replayed[i] || interaction.Request != request {
+			continue
+		}
+		r.replayed[i] = true
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no recorded interaction in %s for %s %s", r.path, request.Method, request.URL)
+}
+
+// sanitizeRequest returns the request without its scheme, host and credentials
+func (r *Recorder) sanitizeRequest(req *http.Request) (Request, error) {
+	values := req.URL.Query()
+	for name := range values {
+		if sensitiveParameters[strings.ToLower(name)] {
+			values[name] = []string{Redacted}
+		}
+	}
+	u := req.URL.Path
+	if len(values) > 0 {
+		u += "?" + values.Encode()
+	}
+	request := Request{Method: req.Method, URL: r.redact(u)}
+	if req.Body == nil || req.Body == http.NoBody {
+		return request, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return Request{}, fmt.Errorf("could not read request body: %v", err)
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	request.Body = r.redact(sanitizeBody(string(body)))
+	return request, nil
+}
+
+// redact replaces the secrets
+func (r *Recorder) redact(text string) string {
+	for _, secret := range r.Secrets {
+		if secret == "" {
+			continue
+		}
+		text = strings.ReplaceAll(text, secret, Redacted)
+		if escaped := url.QueryEscape(secret); escaped != secret {
+			text = strings.ReplaceAll(text, escaped, Redacted)
+		}
+	}
+	return text
+}
+
+// sanitizeBody replaces credentials in JSON and XML-RPC bodies. JSON bodies
+// are encoded again so that their fields are ordered consistently.
+func sanitizeBody(body string) string {
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err == nil && !decoder.More() {
+		sanitized, err := json.Marshal(sanitizeJSON(decoded))
+		if err == nil {
+			return string(sanitized)
+		}
+	}
+	return xmlrpcCredential.ReplaceAllString(body, "${1}"+Redacted)
+}
+
+func sanitizeJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if sensitiveParameters[strings.ToLower(key)] {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = sanitizeJSON(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = sanitizeJSON(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzillatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eparis/bugzilla"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	requests := 0
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("X-BUGZILLA-API-KEY") != "secret-key" {
+			http.Error(w, "403 Forbidden", http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/rest/bug/1":
+			w.Write([]byte(`{"bugs":[{"id":1,"summary":"broken","creator":"someone@example.com"}]}`))
+		case "/jsonrpc.cgi":
+			var call struct {
+				ID string `json:"id"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&call); err != nil {
+				t.Errorf("malformed JSONRPC payload: %v", err)
+			}
+			fmt.Fprintf(w, `{"error":null,"id":%q,"result":{"bugs":[{"changes":{"ext_bz_bug_map.ext_bz_bug_id":{"added":"org/repo/pull/1","removed":""}},"id":1}]}}`, call.ID)
+		default:
+			http.Error(w, "404 Not Found", http.StatusNotFound)
+		}
+	}))
+	defer testServer.Close()
+	dir, err := ioutil.TempDir("", "bugzillatest")
+	if err != nil {
+		t.Fatalf("could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	fixture := filepath.Join(dir, "fixture.json")
+
+	exercise := func(client bugzilla.Client) {
+		bug, err := client.GetBug(1)
+		if err != nil {
+			t.Fatalf("expected no error, but got one: %v", err)
+		}
+		if bug.Summary != "broken" {
+			t.Errorf("got incorrect bug: %v", bug)
+		}
+		changed, err := client.AddPullRequestAsExternalBug(1, "org", "repo", 1)
+		if err != nil {
+			t.Fatalf("expected no error, but got one: %v", err)
+		}
+		if !changed {
+			t.Error("expected the pull request to be added, but it was not")
+		}
+	}
+
+	recorder, err := NewRecorder(fixture, ModeRecord)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	recorder.Base = testServer.Client().Transport
+	recorder.Secrets = []string{"someone@example.com"}
+	exercise(bugzilla.NewClient(func() []byte { return []byte("secret-key") }, testServer.URL, bugzilla.WithTransport(recorder)))
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("could not save fixture: %v", err)
+	}
+	recorded := requests
+
+	raw, err := ioutil.ReadFile(fixture)
+	if err != nil {
+		t.Fatalf("could not read fixture: %v", err)
+	}
+	for _, secret := range []string{"secret-key", "someone@example.com"} {
+		if strings.Contains(string(raw), secret) {
+			t.Errorf("expected %q to be removed from the fixture, got %s", secret, raw)
+		}
+	}
+
+	replayer, err := NewRecorder(fixture, ModeReplay)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	exercise(bugzilla.NewClient(func() []byte { return []byte("other-key") }, "https://bugzilla.invalid", bugzilla.WithTransport(replayer)))
+	if requests != recorded {
+		t.Errorf("expected no requests to be sent while replaying, got %d", requests-recorded)
+	}
+	if _, err := bugzilla.NewClient(func() []byte { return []byte("other-key") }, "https://bugzilla.invalid", bugzilla.WithTransport(replayer)).GetBug(1); err == nil {
+		t.Error("expected an error once the recorded interaction was replayed, got none")
+	}
+}
+
+func TestSanitizeBody(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "credentials in JSON are redacted",
+			body:     `{"params":[{"api_key":"key","bug_ids":[1705243]}],"Bugzilla_password":"hunter2"}`,
+			expected: `{"Bugzilla_password":"REDACTED","params":[{"api_key":"REDACTED","bug_ids":[1705243]}]}`,
+		},
+		{
+			name:     "credentials in XML-RPC are redacted",
+			body:     `<member><name>api_key</name><value><string>key</string></value></member><member><name>bug_ids</name>`,
+			expected: `<member><name>api_key</name><value><string>REDACTED</string></value></member><member><name>bug_ids</name>`,
+		},
+		{
+			name:     "other bodies are kept",
+			body:     `not json`,
+			expected: `not json`,
+		},
+	}
+	for _, testCase := range testCases {
+		if actual := sanitizeBody(testCase.body); actual != testCase.expected {
+			t.Errorf("%s: expected %s, got %s", testCase.name, testCase.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BulkError is returned when some of the bugs requested in bulk could not be
+// retrieved. The bugs which were retrieved are returned along with it.
+type BulkError struct {
+	// Errors holds the error retrieving every bug which failed, by ID.
+	Errors map[int]error
+}
+
+func (e *BulkError) Error() string {
+	ids := e.IDs()
+	messages := make([]string, 0, len(ids))
+	for _, id := range ids {
+		messages = append(messages, fmt.Sprintf("bug %d: %v", id, e.Errors[id]))
+	}
+	return fmt.Sprintf("could not get %d bugs: %s", len(ids), strings.Join(messages, "; "))
+}
+
+// IDs returns the sorted IDs of the bugs which could not be retrieved, e.g.
+// to retry them later.
+func (e *BulkError) IDs() []int {
+	var ids []int
+	for id := range e.Errors {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// bulkGetBugs retrieves the bugs with at most concurrency calls to get in
+// flight. The bugs are returned in the order of the IDs, leaving out those
+// which failed; any failures are returned as a *BulkError.
+func bulkGetBugs(get func(id int) (*Bug, error), ids []int, concurrency int) ([]*Bug, error) {
+	bugs := make([]*Bug, len(ids))
+	errs := bulk(ids, concurrency, func(index int) (err error) {
+		bugs[index], err = get(ids[index])
+		return err
+	})
+	var retrieved []*Bug
+	for index := range ids {
+		if errs[index] == nil {
+			retrieved = append(retrieved, bugs[index])
+		}
+	}
+	return retrieved, bulkError(ids, errs)
+}
+
+// bulk calls get with the index of every ID with at most concurrency calls
+// in flight and returns the errors of the calls, by index
+func bulk(ids []int, concurrency int, get func(index int) error) []error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	errs := make([]error, len(ids))
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(ids); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indices {
+				errs[index] = get(index)
+			}
+		}()
+	}
+	for index := range ids {
+		indices <- index
+	}
+	close(indices)
+	wg.Wait()
+	return errs
+}
+
+// bulkError returns a *BulkError holding the errors of the IDs, by index,
+// or nil if there are none
+func bulkError(ids []int, errs []error) error {
+	bulkErr := &BulkError{Errors: map[int]error{}}
+	for index, err := range errs {
+		if err != nil {
+			bulkErr.Errors[ids[index]] = err
+		}
+	}
+	if len(bulkErr.Errors) > 0 {
+		return bulkErr
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestBulkGetBugs(t *testing.T) {
+	var lock sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		lock.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		lock.Lock()
+		inFlight--
+		lock.Unlock()
+
+		id := strings.TrimPrefix(r.URL.Path, "/rest/bug/")
+		if id == "3" || id == "5" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"error":true,"code":101,"message":"Bug #%s does not exist."}`, id)
+			return
+		}
+		fmt.Fprintf(w, `{"bugs":[{"id":%s}],"faults":[]}`, id)
+	}))
+	defer testServer.Close()
+	c := clientForUrl(testServer.URL)
+
+	bugs, err := c.BulkGetBugs([]int{1, 2, 3, 4, 5, 6}, 2)
+	var ids []int
+	for _, bug := range bugs {
+		ids = append(ids, bug.ID)
+	}
+	if expected := []int{1, 2, 4, 6}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("got incorrect bugs: %v", diff.ObjectReflectDiff(expected, ids))
+	}
+	bulkErr, ok := err.(*BulkError)
+	if !ok {
+		t.Fatalf("expected a *BulkError, got %v", err)
+	}
+	if expected := []int{3, 5}; !reflect.DeepEqual(bulkErr.IDs(), expected) {
+		t.Errorf("got incorrect failed bugs: %v", diff.ObjectReflectDiff(expected, bulkErr.IDs()))
+	}
+	if !IsNotFound(bulkErr.Errors[3]) {
+		t.Errorf("expected the error for bug 3 to be kept, got %v", bulkErr.Errors[3])
+	}
+	if maxInFlight > 2 {
+		t.Errorf("expected at most 2 requests in flight, got %d", maxInFlight)
+	}
+
+	if bugs, err := c.BulkGetBugs([]int{1, 2}, 0); err != nil || len(bugs) != 2 {
+		t.Errorf("expected both bugs without error, got %d bugs and %v", len(bugs), err)
+	}
+}
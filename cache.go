@@ -0,0 +1,294 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxCachedResponses is how many responses a CachingTransport
+	// holds if MaxEntries is not set.
+	DefaultMaxCachedResponses = 1000
+	// DefaultMaxCachedResponseSize is the size of the largest response a
+	// CachingTransport holds if MaxEntrySize is not set.
+	DefaultMaxCachedResponseSize = 1 << 20
+)
+
+// WithResponseCache caches the responses to GET requests made by the client,
+// see CachingTransport. The cache uses the clock of the client, so
+// WithClock must come before it.
+func WithResponseCache(ttl time.Duration) Option {
+	return func(c *client) {
+		WithTransport(&CachingTransport{Base: c.client.Transport, TTL: ttl, Clock: c.timeSource()})(c)
+	}
+}
+
+// CachingTransport caches successful responses to GET requests by URL and
+// credentials, so a response is never served to another user. When the
+// server sent an ETag or Last-Modified header with a response, every later
+// request is sent with If-None-Match or If-Modified-Since and the cached
+// response is used if the server answers 304 Not Modified. Responses without
+// either header are used without asking the server until the TTL passed.
+// Responses the server marks with Cache-Control no-store or private, or which
+// vary on every request, are not cached, and responses varying on request
+// headers are only served to requests with the same values of them.
+//
+// Any request which may change something, that is every request other than
+// GET, HEAD and OPTIONS unless it is known to only read, drops all cached
+// responses, since one update can change many resources, like the bugs
+// depending on or blocking an updated bug. The least recently used
+// responses are dropped once the cache holds MaxEntries.
+type CachingTransport struct {
+	// Base is the transport used to send requests, http.DefaultTransport if nil.
+	Base http.RoundTripper
+	// TTL is how long responses without ETag or Last-Modified are used.
+	TTL time.Duration
+	// MaxEntries is how many responses are cached, DefaultMaxCachedResponses
+	// if 0.
+	MaxEntries int
+	// MaxEntrySize is the size in bytes of the largest response which is
+	// cached, DefaultMaxCachedResponseSize if 0. Larger responses are passed
+	// on as they are read, so limits like WithMaxResponseSize apply to them.
+	MaxEntrySize int64
+	// Clock tells the age of cached responses, the system clock if nil.
+	Clock Clock
+
+	lock    sync.Mutex
+	entries map[string]*list.Element
+	// recent orders the cached responses from the most to the least
+	// recently used
+	recent *list.List
+}
+
+type cacheEntry struct {
+	key          string
+	header       http.Header
+	body         []byte
+	etag         string
+	lastModified string
+	stored       time.Time
+	// vary holds the values of the request headers the response varies on
+	vary map[string]string
+}
+
+func (e *cacheEntry) validated() bool {
+	return e.etag != "" || e.lastModified != ""
+}
+
+// matches determines if the response can be served to the request, that is
+// if the request has the same values of the headers the response varies on
+func (e *cacheEntry) matches(req *http.Request) bool {
+	for name, value := range e.vary {
+		if req.Header.Get(name) != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+func (t *CachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		if !mayRetry(req) {
+			t.invalidateAll()
+		}
+		return t.base().RoundTrip(req)
+	}
+	key := cacheKey(req)
+	entry := t.get(key)
+	if entry != nil && !entry.matches(req) {
+		entry = nil
+	}
+	if entry != nil && !entry.validated() && t.now().Sub(entry.stored) < t.TTL {
+		return entry.response(req), nil
+	}
+	if entry != nil && entry.validated() {
+		req = req.Clone(req.Context())
+		if entry.etag != "" {
+			req.Header.Set("If-None-Match", entry.etag)
+		}
+		if entry.lastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.lastModified)
+		}
+	}
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+		return entry.response(req), nil
+	}
+	vary, cacheable := cacheable(resp, req)
+	if !cacheable {
+		return resp, nil
+	}
+	limit := t.maxEntrySize()
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		// the response is too large to cache, the rest of it is read by the caller
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{Reader: io.MultiReader(bytes.NewReader(body), resp.Body), Closer: resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	t.put(&cacheEntry{
+		key:          key,
+		header:       resp.Header.Clone(),
+		body:         body,
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+		stored:       t.now(),
+		vary:         vary,
+	})
+	return resp, nil
+}
+
+// cacheKey identifies the response to the request by its URL, which holds
+// the API key if it is sent as a query parameter, and by a hash of the
+// credentials sent in headers, so the credentials are not held in memory
+func cacheKey(req *http.Request) string {
+	hash := sha256.New()
+	for _, header := range []string{"X-BUGZILLA-API-KEY", "Authorization", "Cookie"} {
+		io.WriteString(hash, req.Header.Get(header))
+		hash.Write([]byte{0})
+	}
+	return req.URL.String() + " " + hex.EncodeToString(hash.Sum(nil))
+}
+
+// cacheable determines if the response can be cached and returns the values
+// of the request headers it varies on
+func cacheable(resp *http.Response, req *http.Request) (map[string]string, bool) {
+	if resp.StatusCode != http.StatusOK {
+		return nil, false
+	}
+	for _, directive := range strings.Split(resp.Header.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		if directive == "no-store" || directive == "private" || strings.HasPrefix(directive, "private=") {
+			return nil, false
+		}
+	}
+	vary := map[string]string{}
+	for _, value := range resp.Header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "*" {
+				return nil, false
+			}
+			if name != "" {
+				vary[http.CanonicalHeaderKey(name)] = req.Header.Get(name)
+			}
+		}
+	}
+	return vary, true
+}
+
+func (t *CachingTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *CachingTransport) now() time.Time {
+	if t.Clock != nil {
+		return t.Clock.Now()
+	}
+	return time.Now()
+}
+
+func (t *CachingTransport) maxEntries() int {
+	if t.MaxEntries > 0 {
+		return t.MaxEntries
+	}
+	return DefaultMaxCachedResponses
+}
+
+func (t *CachingTransport) maxEntrySize() int64 {
+	if t.MaxEntrySize > 0 {
+		return t.MaxEntrySize
+	}
+	return DefaultMaxCachedResponseSize
+}
+
+func (t *CachingTransport) get(key string) *cacheEntry {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	element, ok := t.entries[key]
+	if !ok {
+		return nil
+	}
+	t.recent.MoveToFront(element)
+	return element.Value.(*cacheEntry)
+}
+
+func (t *CachingTransport) put(entry *cacheEntry) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.entries == nil {
+		t.entries = map[string]*list.Element{}
+		t.recent = list.New()
+	}
+	if element, ok := t.entries[entry.key]; ok {
+		element.Value = entry
+		t.recent.MoveToFront(element)
+		return
+	}
+	t.entries[entry.key] = t.recent.PushFront(entry)
+	for len(t.entries) > t.maxEntries() {
+		oldest := t.recent.Back()
+		t.recent.Remove(oldest)
+		delete(t.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func (t *CachingTransport) invalidateAll() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.entries = nil
+	t.recent = nil
+}
@@ -0,0 +1,302 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResponseCache(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		etag             string
+		lastModified     string
+		ttl              time.Duration
+		modified         bool
+		expectedRequests int
+		expectedFull     int
+	}{
+		{
+			name:             "responses without validators are used until the TTL passed",
+			ttl:              time.Hour,
+			expectedRequests: 1,
+			expectedFull:     1,
+		},
+		{
+			name:             "responses without validators are fetched again after the TTL",
+			expectedRequests: 2,
+			expectedFull:     2,
+		},
+		{
+			name:             "unchanged responses with an ETag are revalidated",
+			etag:             `"v1"`,
+			ttl:              time.Hour,
+			expectedRequests: 2,
+			expectedFull:     1,
+		},
+		{
+			name:             "unchanged responses with Last-Modified are revalidated",
+			lastModified:     "Fri, 17 May 2019 15:13:13 GMT",
+			expectedRequests: 2,
+			expectedFull:     1,
+		},
+		{
+			name:             "changed responses are fetched again",
+			etag:             `"v1"`,
+			modified:         true,
+			expectedRequests: 2,
+			expectedFull:     2,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			requests, full := 0, 0
+			testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				notModified := (testCase.etag != "" && r.Header.Get("If-None-Match") == testCase.etag) ||
+					(testCase.lastModified != "" && r.Header.Get("If-Modified-Since") == testCase.lastModified)
+				if notModified && !testCase.modified {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				if testCase.etag != "" {
+					w.Header().Set("ETag", testCase.etag)
+				}
+				if testCase.lastModified != "" {
+					w.Header().Set("Last-Modified", testCase.lastModified)
+				}
+				full++
+				w.Write(bugData)
+			}))
+			defer testServer.Close()
+			c := clientForUrl(testServer.URL).(*client)
+			WithResponseCache(testCase.ttl)(c)
+
+			for i := 0; i < 2; i++ {
+				bug, err := c.GetBug(1705243)
+				if err != nil {
+					t.Fatalf("%s: expected no error, but got one: %v", testCase.name, err)
+				}
+				if bug.ID != 1705243 {
+					t.Errorf("%s: got incorrect bug %d", testCase.name, bug.ID)
+				}
+			}
+			if requests != testCase.expectedRequests {
+				t.Errorf("%s: expected %d requests, got %d", testCase.name, testCase.expectedRequests, requests)
+			}
+			if full != testCase.expectedFull {
+				t.Errorf("%s: expected %d full responses, got %d", testCase.name, testCase.expectedFull, full)
+			}
+		})
+	}
+}
+
+func TestResponseCacheInvalidatedByUpdate(t *testing.T) {
+	gets := 0
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			gets++
+			w.Write(bugData)
+		}
+	}))
+	defer testServer.Close()
+	c := clientForUrl(testServer.URL).(*client)
+	WithResponseCache(time.Hour)(c)
+
+	if _, err := c.GetBug(1705243); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if _, err := c.GetExternalBugPRsOnBug(1705243); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if err := c.UpdateBug(1, BugUpdate{Status: "POST"}); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if _, err := c.GetBug(1705243); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if _, err := c.GetExternalBugPRsOnBug(1705243); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if gets != 4 {
+		t.Errorf("expected all responses to be fetched again after an update, got %d requests", gets)
+	}
+}
+
+func TestResponseCacheIsKeyedByCredentials(t *testing.T) {
+	requests := 0
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(r.Header.Get("X-BUGZILLA-API-KEY")))
+	}))
+	defer testServer.Close()
+	httpClient := &http.Client{Transport: &CachingTransport{Base: testServer.Client().Transport, TTL: time.Hour}}
+
+	for _, key := range []string{"first", "second", "first"} {
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+"/rest/bug/1", nil)
+		if err != nil {
+			t.Fatalf("could not create request: %v", err)
+		}
+		req.Header.Set("X-BUGZILLA-API-KEY", key)
+		if body := getCached(t, httpClient, req); body != key {
+			t.Errorf("expected the response for %q, got %q", key, body)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("expected a request per API key, got %d", requests)
+	}
+}
+
+func TestResponseCacheStorage(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		header           http.Header
+		body             string
+		maxEntries       int
+		paths            []string
+		expectedRequests int
+	}{
+		{
+			name:             "responses are cached",
+			paths:            []string{"/rest/bug/1", "/rest/bug/1"},
+			expectedRequests: 1,
+		},
+		{
+			name:             "responses marked no-store are not cached",
+			header:           http.Header{"Cache-Control": []string{"no-store"}},
+			paths:            []string{"/rest/bug/1", "/rest/bug/1"},
+			expectedRequests: 2,
+		},
+		{
+			name:             "private responses are not cached",
+			header:           http.Header{"Cache-Control": []string{"max-age=60, private"}},
+			paths:            []string{"/rest/bug/1", "/rest/bug/1"},
+			expectedRequests: 2,
+		},
+		{
+			name:             "responses varying on every request are not cached",
+			header:           http.Header{"Vary": []string{"*"}},
+			paths:            []string{"/rest/bug/1", "/rest/bug/1"},
+			expectedRequests: 2,
+		},
+		{
+			name:             "responses larger than the maximum size are not cached",
+			body:             strings.Repeat(" ", DefaultMaxCachedResponseSize+1),
+			paths:            []string{"/rest/bug/1", "/rest/bug/1"},
+			expectedRequests: 2,
+		},
+		{
+			name:             "the least recently used responses are dropped",
+			maxEntries:       2,
+			paths:            []string{"/rest/bug/1", "/rest/bug/2", "/rest/bug/1", "/rest/bug/3", "/rest/bug/1", "/rest/bug/2"},
+			expectedRequests: 4,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			requests := 0
+			testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				for name, values := range testCase.header {
+					w.Header()[name] = values
+				}
+				w.Write([]byte(r.URL.Path + testCase.body))
+			}))
+			defer testServer.Close()
+			httpClient := &http.Client{Transport: &CachingTransport{Base: testServer.Client().Transport, TTL: time.Hour, MaxEntries: testCase.maxEntries}}
+
+			for _, path := range testCase.paths {
+				req, err := http.NewRequest(http.MethodGet, testServer.URL+path, nil)
+				if err != nil {
+					t.Fatalf("%s: could not create request: %v", testCase.name, err)
+				}
+				if body := getCached(t, httpClient, req); body != path+testCase.body {
+					t.Errorf("%s: got incorrect response for %s", testCase.name, path)
+				}
+			}
+			if requests != testCase.expectedRequests {
+				t.Errorf("%s: expected %d requests, got %d", testCase.name, testCase.expectedRequests, requests)
+			}
+		})
+	}
+}
+
+func TestResponseCacheVary(t *testing.T) {
+	requests := 0
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Vary", "Accept-Language")
+		w.Write([]byte(r.Header.Get("Accept-Language")))
+	}))
+	defer testServer.Close()
+	httpClient := &http.Client{Transport: &CachingTransport{Base: testServer.Client().Transport, TTL: time.Hour}}
+
+	for _, language := range []string{"en", "en", "de"} {
+		req, err := http.NewRequest(http.MethodGet, testServer.URL+"/rest/bug/1", nil)
+		if err != nil {
+			t.Fatalf("could not create request: %v", err)
+		}
+		req.Header.Set("Accept-Language", language)
+		if body := getCached(t, httpClient, req); body != language {
+			t.Errorf("expected the response for %q, got %q", language, body)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("expected a request per language, got %d", requests)
+	}
+}
+
+func TestResponseCacheUsesTheClock(t *testing.T) {
+	requests := 0
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(bugData)
+	}))
+	defer testServer.Close()
+	clock := &fakeClock{now: time.Now()}
+	c := clientForUrl(testServer.URL).(*client)
+	WithClock(clock)(c)
+	WithResponseCache(time.Minute)(c)
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetBug(1705243); err != nil {
+			t.Fatalf("expected no error, but got one: %v", err)
+		}
+		clock.Sleep(45 * time.Second)
+	}
+	if requests != 2 {
+		t.Errorf("expected the response to expire after a minute on the clock, got %d requests", requests)
+	}
+}
+
+func getCached(t *testing.T, httpClient *http.Client, req *http.Request) string {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("could not read response: %v", err)
+	}
+	return string(body)
+}
@@ -0,0 +1,661 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Chaos configures the failures a chaos client injects. Every call draws at
+// most one failure: a timeout with probability TimeoutRate, a malformed
+// response with probability MalformedRate or a partial response with
+// probability PartialRate. The rates should add up to at most 1.
+type Chaos struct {
+	// Seed seeds the random decisions, so a soak test can be replayed.
+	Seed int64
+	// TimeoutRate is the probability of a call timing out. A timed out update
+	// may or may not have been applied, just like with a real server.
+	TimeoutRate float64
+	// Timeout is how long a timed out call hangs before it fails.
+	Timeout time.Duration
+	// MalformedRate is the probability of a call failing to decode the
+	// response. A malformed response to an update is sent after the update
+	// was applied.
+	MalformedRate float64
+	// PartialRate is the probability of a call returning only a part of a
+	// list of results, like a response which was cut off. Calls which do not
+	// return a list are not affected.
+	PartialRate float64
+	// Sleep is used to hang timed out calls, time.Sleep if nil.
+	Sleep func(time.Duration)
+}
+
+type fault int
+
+const (
+	noFault fault = iota
+	timeoutFault
+	malformedFault
+	partialFault
+)
+
+// errChaosMalformed mimics the error returned for a response which could not be decoded
+var errChaosMalformed = errors.New("could not unmarshal response body: unexpected end of JSON input")
+
+// NewChaosClient wraps the client so that its calls fail at random in the
+// ways a misbehaving Bugzilla does, to test that callers handle it gracefully.
+func NewChaosClient(c Client, chaos Chaos) Client {
+	return &chaosClient{
+		Client: c,
+		chaos:  chaos,
+		rand:   rand.New(rand.NewSource(chaos.Seed)),
+	}
+}
+
+type chaosClient struct {
+	Client
+	chaos Chaos
+
+	lock sync.Mutex
+	rand *rand.Rand
+}
+
+// draw decides which failure, if any, to inject into a call
+func (c *chaosClient) draw() fault {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	roll := c.rand.Float64()
+	switch {
+	case roll < c.chaos.TimeoutRate:
+		return timeoutFault
+	case roll < c.chaos.TimeoutRate+c.chaos.MalformedRate:
+		return malformedFault
+	case roll < c.chaos.TimeoutRate+c.chaos.MalformedRate+c.chaos.PartialRate:
+		return partialFault
+	}
+	return noFault
+}
+
+func (c *chaosClient) coinFlip() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.rand.Intn(2) == 0
+}
+
+// keep returns how many of count results a partial response holds
+func (c *chaosClient) keep(count int) int {
+	if count == 0 {
+		return 0
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.rand.Intn(count)
+}
+
+func (c *chaosClient) timeout() error {
+	sleep := c.chaos.Sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	sleep(c.chaos.Timeout)
+	return &RequestError{StatusCode: -1, Message: "simulated timeout: context deadline exceeded (Client.Timeout exceeded while awaiting headers)"}
+}
+
+// read injects a failure into a call which only reads, returning whether the
+// results of the call should be cut short
+func (c *chaosClient) read() (bool, error) {
+	switch c.draw() {
+	case timeoutFault:
+		return false, c.timeout()
+	case malformedFault:
+		return false, errChaosMalformed
+	case partialFault:
+		return true, nil
+	}
+	return false, nil
+}
+
+// write injects a failure into a call which changes the bug, using call to
+// apply the change
+func (c *chaosClient) write(call func() error) error {
+	switch c.draw() {
+	case timeoutFault:
+		if c.coinFlip() {
+			call()
+		}
+		return c.timeout()
+	case malformedFault:
+		if err := call(); err != nil {
+			return err
+		}
+		return errChaosMalformed
+	}
+	return call()
+}
+
+func (c *chaosClient) GetBug(id int) (*Bug, error) {
+	if _, err := c.read(); err != nil {
+		return nil, err
+	}
+	return c.Client.GetBug(id)
+}
+
+func (c *chaosClient) GetBugWithFields(id int, fields []string) (*Bug, error) {
+	if _, err := c.read(); err != nil {
+		return nil, err
+	}
+	return c.Client.GetBugWithFields(id, fields)
+}
+
+func (c *chaosClient) GetBugs(ids []int, fields Fields) ([]*Bug, error) {
+	partial, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	bugs, err := c.Client.GetBugs(ids, fields)
+	if partial {
+		bugs = bugs[:c.keep(len(bugs))]
+	}
+	return bugs, err
+}
+
+func (c *chaosClient) GetBugByAlias(alias string) (*Bug, error) {
+	if _, err := c.read(); err != nil {
+		return nil, err
+	}
+	return c.Client.GetBugByAlias(alias)
+}
+
+// BulkGetBugs injects failures into the retrieval of every bug
+func (c *chaosClient) BulkGetBugs(ids []int, concurrency int) ([]*Bug, error) {
+	return bulkGetBugs(c.GetBug, ids, concurrency)
+}
+
+func (c *chaosClient) GetBugComments(id int) ([]Comment, error) {
+	partial, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	comments, err := c.Client.GetBugComments(id)
+	if partial {
+		comments = comments[:c.keep(len(comments))]
+	}
+	return comments, err
+}
+
+func (c *chaosClient) GetBugDescription(id int) (string, error) {
+	if _, err := c.read(); err != nil {
+		return "", err
+	}
+	return c.Client.GetBugDescription(id)
+}
+
+func (c *chaosClient) GetVersion() (string, error) {
+	if _, err := c.read(); err != nil {
+		return "", err
+	}
+	return c.Client.GetVersion()
+}
+
+func (c *chaosClient) GetBugHistory(id int) ([]History, error) {
+	partial, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	history, err := c.Client.GetBugHistory(id)
+	if partial {
+		history = history[:c.keep(len(history))]
+	}
+	return history, err
+}
+
+func (c *chaosClient) GetAttachmentData(id int) ([]byte, error) {
+	partial, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.Client.GetAttachmentData(id)
+	if partial {
+		data = data[:c.keep(len(data))]
+	}
+	return data, err
+}
+
+func (c *chaosClient) GetBugAttachments(id int) ([]Attachment, error) {
+	partial, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := c.Client.GetBugAttachments(id)
+	if partial {
+		attachments = attachments[:c.keep(len(attachments))]
+	}
+	return attachments, err
+}
+
+// GetBugFull injects failures into every call it makes
+func (c *chaosClient) GetBugFull(id int) (*FullBug, error) {
+	return getBugFull(c, id)
+}
+
+func (c *chaosClient) Search(query Query) ([]*Bug, error) {
+	partial, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	bugs, err := c.Client.Search(query)
+	if partial {
+		bugs = bugs[:c.keep(len(bugs))]
+	}
+	return bugs, err
+}
+
+func (c *chaosClient) GetBugsModifiedSince(since time.Time, query Query) ([]*Bug, error) {
+	partial, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	bugs, err := c.Client.GetBugsModifiedSince(since, query)
+	if partial {
+		bugs = bugs[:c.keep(len(bugs))]
+	}
+	return bugs, err
+}
+
+func (c *chaosClient) SearchInto(query Query, dest interface{}) error {
+	if _, err := c.read(); err != nil {
+		return err
+	}
+	return c.Client.SearchInto(query, dest)
+}
+
+// SearchBugsIter injects failures into the retrieval of every bug
+func (c *chaosClient) SearchBugsIter(query Query) *BugIter {
+	iter := c.Client.SearchBugsIter(query)
+	return newBugIter(func(limit, offset int) ([]*Bug, error) {
+		partial, err := c.read()
+		if err != nil {
+			return nil, err
+		}
+		if partial || !iter.Next() {
+			return nil, iter.Err()
+		}
+		return []*Bug{iter.Bug()}, nil
+	})
+}
+
+func (c *chaosClient) GetExternalBugs(id int) ([]ExternalBug, error) {
+	partial, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	bugs, err := c.Client.GetExternalBugs(id)
+	if partial {
+		bugs = bugs[:c.keep(len(bugs))]
+	}
+	return bugs, err
+}
+
+func (c *chaosClient) GetExternalBugsForBugs(ids []int) (map[int][]ExternalBug, error) {
+	partial, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	external, err := c.Client.GetExternalBugsForBugs(ids)
+	if partial {
+		var kept []int
+		for id := range external {
+			kept = append(kept, id)
+		}
+		sort.Ints(kept)
+		for _, id := range kept[c.keep(len(kept)):] {
+			delete(external, id)
+		}
+	}
+	return external, err
+}
+
+func (c *chaosClient) GetExternalBugPRsOnBugs(ids []int, allowed ...string) (map[int][]ExternalBug, error) {
+	partial, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	prs, err := c.Client.GetExternalBugPRsOnBugs(ids, allowed...)
+	if partial {
+		var kept []int
+		for id := range prs {
+			kept = append(kept, id)
+		}
+		sort.Ints(kept)
+		for _, id := range kept[c.keep(len(kept)):] {
+			delete(prs, id)
+		}
+	}
+	return prs, err
+}
+
+func (c *chaosClient) GetExternalBugPRsOnBug(id int) ([]ExternalBug, error) {
+	partial, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	bugs, err := c.Client.GetExternalBugPRsOnBug(id)
+	if partial {
+		bugs = bugs[:c.keep(len(bugs))]
+	}
+	return bugs, err
+}
+
+func (c *chaosClient) GetFlags(id int) ([]Flag, error) {
+	partial, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	flags, err := c.Client.GetFlags(id)
+	if partial {
+		flags = flags[:c.keep(len(flags))]
+	}
+	return flags, err
+}
+
+func (c *chaosClient) GetProduct(name string) (*Product, error) {
+	if _, err := c.read(); err != nil {
+		return nil, err
+	}
+	return c.Client.GetProduct(name)
+}
+
+func (c *chaosClient) ListProducts() ([]Product, error) {
+	partial, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	products, err := c.Client.ListProducts()
+	if partial {
+		products = products[:c.keep(len(products))]
+	}
+	return products, err
+}
+
+func (c *chaosClient) GetProductSchema(product string) (*ProductSchema, error) {
+	if _, err := c.read(); err != nil {
+		return nil, err
+	}
+	return c.Client.GetProductSchema(product)
+}
+
+func (c *chaosClient) GetSubComponentsForComponent(product, component string) ([]string, error) {
+	partial, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	subComponents, err := c.Client.GetSubComponentsForComponent(product, component)
+	if partial {
+		subComponents = subComponents[:c.keep(len(subComponents))]
+	}
+	return subComponents, err
+}
+
+func (c *chaosClient) GetCurrentUser() (*User, error) {
+	if _, err := c.read(); err != nil {
+		return nil, err
+	}
+	return c.Client.GetCurrentUser()
+}
+
+func (c *chaosClient) SearchUsers(match string) ([]User, error) {
+	partial, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	users, err := c.Client.SearchUsers(match)
+	if partial {
+		users = users[:c.keep(len(users))]
+	}
+	return users, err
+}
+
+func (c *chaosClient) GetUser(idOrLogin string) (*User, error) {
+	if _, err := c.read(); err != nil {
+		return nil, err
+	}
+	return c.Client.GetUser(idOrLogin)
+}
+
+func (c *chaosClient) GetGroups() ([]Group, error) {
+	partial, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	groups, err := c.Client.GetGroups()
+	if partial {
+		groups = groups[:c.keep(len(groups))]
+	}
+	return groups, err
+}
+
+func (c *chaosClient) Do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	if method != http.MethodGet {
+		return c.write(func() error {
+			return c.Client.Do(ctx, method, path, body, out)
+		})
+	}
+	if _, err := c.read(); err != nil {
+		return err
+	}
+	return c.Client.Do(ctx, method, path, body, out)
+}
+
+func (c *chaosClient) GetExternalTrackerTypes() ([]ExternalBugType, error) {
+	partial, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	types, err := c.Client.GetExternalTrackerTypes()
+	if partial {
+		types = types[:c.keep(len(types))]
+	}
+	return types, err
+}
+
+func (c *chaosClient) GetFields(fieldName string) ([]Field, error) {
+	partial, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	fields, err := c.Client.GetFields(fieldName)
+	if partial {
+		fields = fields[:c.keep(len(fields))]
+	}
+	return fields, err
+}
+
+func (c *chaosClient) UpdateBug(id int, update BugUpdate) error {
+	return c.write(func() error {
+		return c.Client.UpdateBug(id, update)
+	})
+}
+
+func (c *chaosClient) UpdateBugs(ids []int, update BugUpdate) error {
+	return c.write(func() error {
+		return c.Client.UpdateBugs(ids, update)
+	})
+}
+
+func (c *chaosClient) UpdateCommentTags(commentID int, add, remove []string) ([]string, error) {
+	var tags []string
+	err := c.write(func() error {
+		var err error
+		tags, err = c.Client.UpdateCommentTags(commentID, add, remove)
+		return err
+	})
+	return tags, err
+}
+
+func (c *chaosClient) UpdateBugByAlias(alias string, update BugUpdate) error {
+	return c.write(func() error {
+		return c.Client.UpdateBugByAlias(alias, update)
+	})
+}
+
+func (c *chaosClient) SetFlag(id int, name, status string) error {
+	return c.write(func() error {
+		return c.Client.SetFlag(id, name, status)
+	})
+}
+
+func (c *chaosClient) ClearFlag(id int, name string) error {
+	return c.write(func() error {
+		return c.Client.ClearFlag(id, name)
+	})
+}
+
+func (c *chaosClient) AddKeywords(id int, keywords ...string) error {
+	return c.write(func() error {
+		return c.Client.AddKeywords(id, keywords...)
+	})
+}
+
+func (c *chaosClient) RemoveKeywords(id int, keywords ...string) error {
+	return c.write(func() error {
+		return c.Client.RemoveKeywords(id, keywords...)
+	})
+}
+
+func (c *chaosClient) GetSeeAlso(id int) ([]SeeAlso, error) {
+	partial, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	links, err := c.Client.GetSeeAlso(id)
+	if partial {
+		links = links[:c.keep(len(links))]
+	}
+	return links, err
+}
+
+func (c *chaosClient) UpdateSeeAlso(id int, add, remove []string) error {
+	return c.write(func() error {
+		return c.Client.UpdateSeeAlso(id, add, remove)
+	})
+}
+
+func (c *chaosClient) MarkAsDuplicate(id, dupeOf int) error {
+	return c.write(func() error {
+		return c.Client.MarkAsDuplicate(id, dupeOf)
+	})
+}
+
+func (c *chaosClient) ResolveDuplicateChain(id int) (int, error) {
+	if _, err := c.read(); err != nil {
+		return 0, err
+	}
+	return c.Client.ResolveDuplicateChain(id)
+}
+
+func (c *chaosClient) CreateAttachment(id int, attachment AttachmentCreate) (int, error) {
+	var attachmentID int
+	err := c.write(func() error {
+		var err error
+		attachmentID, err = c.Client.CreateAttachment(id, attachment)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return attachmentID, nil
+}
+
+func (c *chaosClient) CreateBug(bug BugCreate) (int, error) {
+	var id int
+	err := c.write(func() error {
+		var err error
+		id, err = c.Client.CreateBug(bug)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (c *chaosClient) EnsureBugState(id int, desired DesiredBugState) (*BugUpdate, error) {
+	var update *BugUpdate
+	err := c.write(func() error {
+		var err error
+		update, err = c.Client.EnsureBugState(id, desired)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return update, nil
+}
+
+func (c *chaosClient) CloneBug(bug *Bug, mutations ...CloneOption) (int, error) {
+	var id int
+	err := c.write(func() error {
+		var err error
+		id, err = c.Client.CloneBug(bug, mutations...)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (c *chaosClient) UpdateExternalBugStatus(bugID int, identifier ExternalBugIdentifier, status string) error {
+	return c.write(func() error {
+		return c.Client.UpdateExternalBugStatus(bugID, identifier, status)
+	})
+}
+
+func (c *chaosClient) AddJiraIssueAsExternalBug(id int, project string, num int) (bool, error) {
+	var changed bool
+	err := c.write(func() error {
+		var err error
+		changed, err = c.Client.AddJiraIssueAsExternalBug(id, project, num)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return changed, nil
+}
+
+func (c *chaosClient) AddPullRequestAsExternalBug(id int, org, repo string, num int) (bool, error) {
+	var changed bool
+	err := c.write(func() error {
+		var err error
+		changed, err = c.Client.AddPullRequestAsExternalBug(id, org, repo, num)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return changed, nil
+}
+
+// the chaosClient is a Client
+var _ Client = &chaosClient{}
@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChaosClient(t *testing.T) {
+	var testCases = []struct {
+		name  string
+		chaos Chaos
+		check func(t *testing.T, c Client, fake *Fake)
+	}{
+		{
+			name:  "no failures are injected by default",
+			chaos: Chaos{},
+			check: func(t *testing.T, c Client, fake *Fake) {
+				for i := 0; i < 10; i++ {
+					bugs, err := c.Search(Query{})
+					if err != nil || len(bugs) != 3 {
+						t.Fatalf("expected all bugs without error, got %d bugs and %v", len(bugs), err)
+					}
+				}
+			},
+		},
+		{
+			name:  "timeouts hang the call and fail it",
+			chaos: Chaos{TimeoutRate: 1, Timeout: time.Minute},
+			check: func(t *testing.T, c Client, fake *Fake) {
+				_, err := c.GetBug(1)
+				reqError, ok := asRequestError(err)
+				if !ok || reqError.StatusCode != -1 {
+					t.Errorf("expected a transport error, got %v", err)
+				}
+			},
+		},
+		{
+			name:  "malformed responses fail reads",
+			chaos: Chaos{MalformedRate: 1},
+			check: func(t *testing.T, c Client, fake *Fake) {
+				if _, err := c.GetBugComments(1); err != errChaosMalformed {
+					t.Errorf("expected a malformed response error, got %v", err)
+				}
+			},
+		},
+		{
+			name:  "malformed responses to updates are sent after the update",
+			chaos: Chaos{MalformedRate: 1},
+			check: func(t *testing.T, c Client, fake *Fake) {
+				if err := c.UpdateBug(1, BugUpdate{Status: "MODIFIED"}); err != errChaosMalformed {
+					t.Errorf("expected a malformed response error, got %v", err)
+				}
+				if fake.Bugs[1].Status != "MODIFIED" {
+					t.Errorf("expected the update to be applied, got status %q", fake.Bugs[1].Status)
+				}
+			},
+		},
+		{
+			name:  "partial responses cut lists short",
+			chaos: Chaos{PartialRate: 1},
+			check: func(t *testing.T, c Client, fake *Fake) {
+				bugs, err := c.Search(Query{})
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if len(bugs) >= 3 {
+					t.Errorf("expected fewer than 3 bugs, got %d", len(bugs))
+				}
+				if _, err := c.GetBug(1); err != nil {
+					t.Errorf("expected single bugs to be unaffected, got %v", err)
+				}
+			},
+		},
+		{
+			name:  "iterators fail midway",
+			chaos: Chaos{MalformedRate: 1},
+			check: func(t *testing.T, c Client, fake *Fake) {
+				iter := c.SearchBugsIter(Query{})
+				if iter.Next() {
+					t.Error("expected the iterator to stop")
+				}
+				if iter.Err() != errChaosMalformed {
+					t.Errorf("expected a malformed response error, got %v", iter.Err())
+				}
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fake := &Fake{
+				Bugs:        map[int]Bug{1: {ID: 1, Status: "NEW"}, 2: {ID: 2}, 3: {ID: 3}},
+				BugComments: map[int][]Comment{1: {{Id: 1}}},
+			}
+			var slept time.Duration
+			testCase.chaos.Sleep = func(d time.Duration) { slept += d }
+			testCase.check(t, NewChaosClient(fake, testCase.chaos), fake)
+			if slept != testCase.chaos.Timeout && testCase.chaos.TimeoutRate > 0 {
+				t.Errorf("expected to hang for %s, hung for %s", testCase.chaos.Timeout, slept)
+			}
+		})
+	}
+}
+
+func TestChaosClientIsReproducible(t *testing.T) {
+	outcomes := func() []bool {
+		c := NewChaosClient(&Fake{Bugs: map[int]Bug{1: {ID: 1}}}, Chaos{Seed: 42, MalformedRate: 0.5})
+		var failed []bool
+		for i := 0; i < 20; i++ {
+			_, err := c.GetBug(1)
+			failed = append(failed, err != nil)
+		}
+		return failed
+	}
+	first, second := outcomes(), outcomes()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("expected the same seed to inject the same failures, differed at call %d", i)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WithCircuitBreaker makes the client stop sending requests to a server which
+// is down, so automation does not pile on while it recovers. The circuit opens
+// after threshold consecutive responses with a server error, after which all
+// requests fail fast with a *CircuitOpenError. Once the cool-down is over, the
+// circuit is half-open and a single request is let through: if the server
+// answers it without a server error, the circuit closes again, otherwise it
+// stays open for another cool-down.
+func WithCircuitBreaker(threshold int, coolDown time.Duration) Option {
+	return func(c *client) {
+		c.breaker = &circuitBreaker{threshold: threshold, coolDown: coolDown, now: c.now}
+	}
+}
+
+// CircuitOpenError is returned for a request which was not sent because the
+// circuit breaker is open.
+type CircuitOpenError struct {
+	// Until is when the circuit breaker lets the next request through, or
+	// when the request was turned away if a request probing the server was
+	// in flight.
+	Until time.Time
+}
+
+func (e CircuitOpenError) Error() string {
+	return fmt.Sprintf("request not sent because the circuit breaker is open until %s", e.Until.Format(time.RFC3339))
+}
+
+// IsCircuitOpen returns true if the error was returned because a request was
+// not sent while the circuit breaker was open.
+func IsCircuitOpen(err error) bool {
+	var target *CircuitOpenError
+	return errors.As(err, &target)
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitOpen:
+		return "open"
+	case circuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+type circuitBreaker struct {
+	lock      sync.Mutex
+	threshold int
+	coolDown  time.Duration
+	now       func() time.Time
+
+	state    circuitState
+	failures int
+	openedAt time.Time
+}
+
+// allow returns an error if the request may not be sent. Once the cool-down
+// is over, the first caller is let through as the probe, all others are
+// turned away until the probe was observed.
+func (b *circuitBreaker) allow() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	switch b.state {
+	case circuitOpen:
+		until := b.openedAt.Add(b.coolDown)
+		if b.now().Before(until) {
+			return &CircuitOpenError{Until: until}
+		}
+		b.state = circuitHalfOpen
+		return nil
+	case circuitHalfOpen:
+		return &CircuitOpenError{Until: b.now()}
+	default:
+		return nil
+	}
+}
+
+// observe records the outcome of a request which was sent and returns the
+// state of the circuit before and after it
+func (b *circuitBreaker) observe(err error) (circuitState, circuitState) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	before := b.state
+	reqError, ok := err.(*RequestError)
+	switch {
+	case ok && reqError.StatusCode >= http.StatusInternalServerError:
+		b.failures++
+		if b.state == circuitHalfOpen || b.failures >= b.threshold {
+			b.state, b.openedAt = circuitOpen, b.now()
+		}
+	case ok && reqError.StatusCode == -1:
+		// the server may not have been reached at all, which neither proves
+		// it is down nor up, but the probe has to be finished either way
+		if b.state == circuitHalfOpen {
+			b.state, b.openedAt = circuitOpen, b.now()
+		}
+	default:
+		b.state, b.failures = circuitClosed, 0
+	}
+	return before, b.state
+}
+
+// guardedRequest sends the request unless the circuit breaker, if any, is open
+func (c *client) guardedRequest(req *http.Request, logger *logEntry) ([]byte, error) {
+	if c.breaker == nil {
+		return c.doRequest(req, logger)
+	}
+	if err := c.breaker.allow(); err != nil {
+		c.metrics.rejectedRequest(logger.Data[methodField].(string))
+		return nil, err
+	}
+	raw, err := c.doRequest(req, logger)
+	if before, after := c.breaker.observe(err); before != after {
+		logger.WithError(err).Warnf("Circuit breaker is now %s, was %s.", after, before)
+	}
+	return raw, err
+}
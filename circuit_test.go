@@ -0,0 +1,145 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	failing := true
+	requests := 0
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if failing {
+			http.Error(w, "503 Service Unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(bugData)
+	}))
+	defer testServer.Close()
+	c := clientForUrl(testServer.URL).(*client)
+	WithCircuitBreaker(2, time.Minute)(c)
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	c.breaker.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetBug(1705243); err == nil || IsCircuitOpen(err) {
+			t.Fatalf("expected request %d to be sent and fail, got %v", i, err)
+		}
+	}
+	_, err := c.GetBug(1705243)
+	if !IsCircuitOpen(err) {
+		t.Fatalf("expected the circuit to be open, got %v", err)
+	}
+	if !IsCircuitOpen(fmt.Errorf("could not get bug: %w", err)) {
+		t.Errorf("expected the wrapped error to be recognized, got %v", err)
+	}
+	if until := err.(*CircuitOpenError).Until; !until.Equal(now.Add(time.Minute)) {
+		t.Errorf("expected the circuit to be open until %s, got %s", now.Add(time.Minute), until)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests to be sent, got %d", requests)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := c.GetBug(1705243); err == nil || IsCircuitOpen(err) {
+		t.Fatalf("expected the probe to be sent and fail, got %v", err)
+	}
+	if _, err := c.GetBug(1705243); !IsCircuitOpen(err) {
+		t.Fatalf("expected the circuit to open again after a failed probe, got %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	failing = false
+	if _, err := c.GetBug(1705243); err != nil {
+		t.Fatalf("expected the probe to succeed, got %v", err)
+	}
+	if _, err := c.GetBug(1705243); err != nil {
+		t.Errorf("expected the circuit to be closed after a successful probe, got %v", err)
+	}
+	if requests != 5 {
+		t.Errorf("expected 5 requests to be sent, got %d", requests)
+	}
+}
+
+func TestCircuitBreakerObserve(t *testing.T) {
+	serverError := &RequestError{StatusCode: http.StatusInternalServerError}
+	noResponse := &RequestError{StatusCode: -1}
+	notFound := &RequestError{StatusCode: http.StatusNotFound}
+	var testCases = []struct {
+		name     string
+		outcomes []error
+		expected circuitState
+	}{
+		{
+			name:     "stays closed below the threshold",
+			outcomes: []error{serverError, serverError},
+			expected: circuitClosed,
+		},
+		{
+			name:     "opens at the threshold",
+			outcomes: []error{serverError, serverError, serverError},
+			expected: circuitOpen,
+		},
+		{
+			name:     "failures must be consecutive",
+			outcomes: []error{serverError, serverError, nil, serverError, serverError},
+			expected: circuitClosed,
+		},
+		{
+			name:     "client errors show the server is up",
+			outcomes: []error{serverError, serverError, notFound, serverError},
+			expected: circuitClosed,
+		},
+		{
+			name:     "transport errors do not count",
+			outcomes: []error{serverError, noResponse, serverError, noResponse},
+			expected: circuitClosed,
+		},
+	}
+	for _, testCase := range testCases {
+		breaker := &circuitBreaker{threshold: 3, coolDown: time.Minute, now: time.Now}
+		for _, outcome := range testCase.outcomes {
+			breaker.observe(outcome)
+		}
+		if breaker.state != testCase.expected {
+			t.Errorf("%s: expected the circuit to be %s, got %s", testCase.name, testCase.expected, breaker.state)
+		}
+	}
+}
+
+func TestCircuitBreakerSingleProbe(t *testing.T) {
+	now := time.Now()
+	breaker := &circuitBreaker{threshold: 1, coolDown: time.Minute, now: func() time.Time { return now }}
+	breaker.observe(&RequestError{StatusCode: http.StatusBadGateway})
+	now = now.Add(time.Minute)
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("expected the probe to be let through, got %v", err)
+	}
+	if err := breaker.allow(); !IsCircuitOpen(err) {
+		t.Errorf("expected requests to be turned away while probing, got %v", err)
+	}
+	breaker.observe(&RequestError{StatusCode: -1})
+	if breaker.state != circuitOpen {
+		t.Errorf("expected a probe without a response to open the circuit again, got %s", breaker.state)
+	}
+}
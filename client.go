@@ -18,18 +18,22 @@ package bugzilla
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/sirupsen/logrus"
 )
 
 const (
@@ -41,64 +45,260 @@ const (
 
 type Client interface {
 	Endpoint() string
+	// GetVersion retrieves the version of the server.
+	GetVersion() (string, error)
 	GetBug(id int) (*Bug, error)
+	GetBugWithFields(id int, fields []string) (*Bug, error)
+	// GetBugs retrieves the bugs in one request, with the selected fields.
+	GetBugs(ids []int, fields Fields) ([]*Bug, error)
+	GetBugByAlias(alias string) (*Bug, error)
+	// BulkGetBugs retrieves the bugs with at most concurrency requests in
+	// flight, returning the bugs which were retrieved and a *BulkError for
+	// those which were not.
+	BulkGetBugs(ids []int, concurrency int) ([]*Bug, error)
 	GetBugComments(id int) ([]Comment, error)
+	// GetBugDescription retrieves only the description of the bug, its comment 0.
+	GetBugDescription(id int) (string, error)
+	UpdateCommentTags(commentID int, add, remove []string) ([]string, error)
 	GetBugHistory(id int) ([]History, error)
+	// GetBugAttachments retrieves the metadata of the attachments of a bug,
+	// without their data.
+	GetBugAttachments(id int) ([]Attachment, error)
+	// GetAttachmentData retrieves the data of an attachment, to download only
+	// the attachments which are needed.
+	GetAttachmentData(id int) ([]byte, error)
+	// GetBugFull retrieves a bug with its comments, history, attachments and
+	// external bugs, concurrently.
+	GetBugFull(id int) (*FullBug, error)
 	Search(query Query) ([]*Bug, error)
+	SearchInto(query Query, dest interface{}) error
+	SearchBugsIter(query Query) *BugIter
+	// GetBugsModifiedSince searches for the bugs matching the query which changed
+	// at or after the time, ordered by when they last changed.
+	GetBugsModifiedSince(since time.Time, query Query) ([]*Bug, error)
 	GetExternalBugs(id int) ([]ExternalBug, error)
 	GetExternalBugPRsOnBug(id int) ([]ExternalBug, error)
+	// GetExternalBugsForBugs retrieves the external bugs of many bugs at once, by bug ID.
+	GetExternalBugsForBugs(ids []int) (map[int][]ExternalBug, error)
+	// GetExternalBugPRsOnBugs retrieves the GitHub pull requests linked to many
+	// bugs at once, by bug ID, keeping only those in the allowed orgs or repos.
+	GetExternalBugPRsOnBugs(ids []int, allowed ...string) (map[int][]ExternalBug, error)
 	UpdateBug(id int, update BugUpdate) error
+	// UpdateBugs applies the same update to all of the bugs in one call.
+	UpdateBugs(ids []int, update BugUpdate) error
+	UpdateBugByAlias(alias string, update BugUpdate) error
+	GetFlags(id int) ([]Flag, error)
+	SetFlag(id int, name, status string) error
+	ClearFlag(id int, name string) error
+	AddKeywords(id int, keywords ...string) error
+	RemoveKeywords(id int, keywords ...string) error
+	// GetSeeAlso retrieves the "see also" links of the bug, parsing links to GitHub and Jira.
+	GetSeeAlso(id int) ([]SeeAlso, error)
+	// UpdateSeeAlso adds and removes "see also" links of the bug in one update.
+	UpdateSeeAlso(id int, add, remove []string) error
+	MarkAsDuplicate(id, dupeOf int) error
+	ResolveDuplicateChain(id int) (int, error)
+	// EnsureBugState updates the bug only where it differs from the desired state.
+	EnsureBugState(id int, desired DesiredBugState) (*BugUpdate, error)
+	CreateBug(bug BugCreate) (int, error)
+	CloneBug(bug *Bug, mutations ...CloneOption) (int, error)
+	// CreateAttachment attaches the file to the bug and returns the ID of the attachment.
+	CreateAttachment(id int, attachment AttachmentCreate) (int, error)
 	AddPullRequestAsExternalBug(id int, org, repo string, num int) (bool, error)
+	// AddJiraIssueAsExternalBug links the Jira issue, like OCPBUGS-1234, to the bug.
+	AddJiraIssueAsExternalBug(id int, project string, num int) (bool, error)
+	// UpdateExternalBugStatus sets the status of the external bug linked to the bug, e.g. when a pull request merged.
+	UpdateExternalBugStatus(bugID int, identifier ExternalBugIdentifier, status string) error
+	GetProduct(name string) (*Product, error)
+	ListProducts() ([]Product, error)
+	GetProductSchema(product string) (*ProductSchema, error)
+	// GetFields retrieves the metadata and legal values of the named bug field, or of all bug fields if the name is empty.
+	GetFields(fieldName string) ([]Field, error)
+	GetSubComponentsForComponent(product, component string) ([]string, error)
+	// GetCurrentUser retrieves the user the client is authenticated as.
+	GetCurrentUser() (*User, error)
+	// SearchUsers retrieves the users whose login, real name or e-mail matches.
+	SearchUsers(match string) ([]User, error)
+	// GetUser retrieves the user with the ID or login name, with the groups the user is a member of.
+	GetUser(idOrLogin string) (*User, error)
+	// GetGroups retrieves the groups the client can see, which are the groups
+	// the user is a member of unless the user can administer groups.
+	GetGroups() ([]Group, error)
+	// GetExternalTrackerTypes retrieves the external bug trackers configured on the server.
+	GetExternalTrackerTypes() ([]ExternalBugType, error)
 	SetAuthMethod(authMethod string) error
+	// SetAPIKeySupplier replaces the function which supplies the API key
+	// for every request, e.g. to pick up a rotated key. It is safe to call
+	// while requests are in flight and fails if getAPIKey is nil.
+	SetAPIKeySupplier(getAPIKey func() []byte) error
+	// Do sends a request to an endpoint the client does not wrap, decoding the JSON response into out.
+	Do(ctx context.Context, method, path string, body interface{}, out interface{}) error
 
 	WithCGIClient(user, password string) Client
 	// only supported with CGI client
 	BugList(queryName, sharerID string) ([]Bug, error)
 }
 
-func NewClient(getAPIKey func() []byte, endpoint string) Client {
-	return &client{
-		logger:    logrus.WithField("client", "bugzilla"),
-		client:    &http.Client{},
-		endpoint:  endpoint,
-		getAPIKey: getAPIKey,
+// Option configures optional behavior of the client created by NewClient
+type Option func(*client)
+
+func NewClient(getAPIKey func() []byte, endpoint string, opts ...Option) Client {
+	c := &client{
+		logger:   newLogEntry(defaultLogger, LogFields{"client": "bugzilla"}),
+		client:   &http.Client{},
+		endpoint: endpoint,
+		apiKey:   newAPIKeySupplier(getAPIKey),
+		settings: &clientSettings{},
+		versions: &versionCache{},
+		schemas:  &schemaCache{ttl: DefaultSchemaTTL},
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	c.applyTLSConfig()
+	c.startWarmUp()
+	return c
 }
 
 type client struct {
-	logger     *logrus.Entry
-	client     *http.Client
-	cgiClient  *bugzillaCGIClient
-	endpoint   string
-	getAPIKey  func() []byte
-	authMethod string
+	logger   *logEntry
+	client   *http.Client
+	endpoint string
+	// apiKey is shared by the copies of the client, see SetAPIKeySupplier
+	apiKey *apiKeySupplier
+	// settings are shared by the copies of the client, like NonCritical ones
+	settings *clientSettings
+
+	basicAuth *basicAuth
+
+	// tlsUpdates are applied to the transport once all options ran
+	tlsUpdates []func(*tls.Config) *tls.Config
+	// optionsErr is returned by every request if the options could not be applied
+	optionsErr error
+
+	maxRetries   int
+	retryBackoff time.Duration
+
+	metrics *clientMetrics
+
+	strictNulls    bool
+	strictDecoding bool
+
+	maxResponseSize int64
+
+	limiter *rateLimiter
+
+	session *session
+
+	audit *auditor
+
+	degradation DegradationPolicy
+	nonCritical bool
+
+	// priority, if set, is the priority of all requests, see WithRequestPriority
+	priority *Priority
+
+	timeout        time.Duration
+	methodTimeouts map[string]time.Duration
+	// callTimeout, if set, is the timeout of all requests, see WithCallTimeout
+	callTimeout *time.Duration
+
+	breaker *circuitBreaker
+
+	// secondaries are the endpoints of read replicas, see WithSecondaryEndpoints
+	secondaries []string
+
+	// githubTrackers are the URLs of the trackers for GitHub, see WithGitHubTrackers
+	githubTrackers []string
+
+	requestHooks  []func(*http.Request)
+	responseHooks []func(*http.Response)
+	logPayloads   bool
+
+	rpc      *rpcNegotiation
+	versions *versionCache
+	schemas  *schemaCache
+	bugCache *bugCache
+
+	// rpcRequests numbers the JSON-RPC requests, unless rpcID is set to
+	// generate their IDs
+	rpcRequests uint32
+	rpcID       func() string
+
+	clock Clock
 }
 
 // the client is a Client impl
 var _ Client = &client{}
 
+// clientSettings are the settings which can be changed after the client was
+// created, so a change applies to all copies of the client
+type clientSettings struct {
+	lock sync.RWMutex
+	// authMethods are the chained auth methods, see SetAuthMethod
+	authMethods []string
+	// cgiClient is the client for the CGI pages, see WithCGIClient
+	cgiClient *bugzillaCGIClient
+}
+
+// authMethods returns the chained auth methods
+func (c *client) authMethods() []string {
+	c.settings.lock.RLock()
+	defer c.settings.lock.RUnlock()
+	return c.settings.authMethods
+}
+
+// cgiClient returns the client for the CGI pages, or nil if there is none
+func (c *client) cgiClient() *bugzillaCGIClient {
+	c.settings.lock.RLock()
+	defer c.settings.lock.RUnlock()
+	return c.settings.cgiClient
+}
+
+// SetAuthMethod sets how requests are authenticated. Several methods can be
+// chained with commas to apply them all, e.g. "basic,x-bugzilla-api-key" for
+// a server behind a proxy requiring basic auth.
 func (c *client) SetAuthMethod(authMethod string) error {
-	if authMethod != "" && authMethod != AuthBearer && authMethod != AuthQuery && authMethod != AuthXBugzillaAPIKey {
-		return fmt.Errorf("invalid auth-method %s. Valid values are bearer,query or x-bugzilla-api-key", authMethod)
+	methods, err := c.parseAuthMethods(authMethod)
+	if err != nil {
+		return err
+	}
+	c.settings.lock.Lock()
+	defer c.settings.lock.Unlock()
+	c.settings.authMethods = methods
+	return nil
+}
+
+func (c *client) SetAPIKeySupplier(getAPIKey func() []byte) error {
+	if getAPIKey == nil {
+		return errors.New("the API key supplier must not be nil")
 	}
-	c.authMethod = authMethod
+	c.apiKey.set(getAPIKey)
 	return nil
 }
 
+func (c *client) getAPIKey() []byte {
+	return c.apiKey.get()
+}
+
 func (c *client) Endpoint() string {
 	return c.endpoint
 }
 
 func (c *client) WithCGIClient(username, password string) Client {
-	var err error
-	c.cgiClient, err = newCGIClient(c.endpoint, username, password)
+	cgiClient, err := newCGIClient(c.endpoint, username, password)
 	if err != nil {
 		panic(err)
 	}
+	// the CGI client keeps its own cookies and timeout but shares the transport
+	cgiClient.httpClient.Transport = c.client.Transport
+	c.settings.lock.Lock()
+	defer c.settings.lock.Unlock()
+	c.settings.cgiClient = cgiClient
 	return c
 }
 
-func (c *client) getBugs(url string, values *url.Values, logger *logrus.Entry) ([]*Bug, error) {
+func (c *client) getBugs(url string, values *url.Values, logger *logEntry) ([]*Bug, error) {
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
@@ -110,21 +310,71 @@ func (c *client) getBugs(url string, values *url.Values, logger *logrus.Entry) (
 	if err != nil {
 		return nil, err
 	}
+	return c.decodeBugs(raw)
+}
+
+// decodeBugs decodes the bugs in a response holding a list of bugs
+func (c *client) decodeBugs(raw []byte) ([]*Bug, error) {
 	var parsedResponse struct {
 		Bugs []*Bug `json:"bugs,omitempty"`
 	}
-	if err := json.Unmarshal(raw, &parsedResponse); err != nil {
+	if err := c.unmarshal(raw, &parsedResponse); err != nil {
 		return nil, fmt.Errorf("could not unmarshal response body: %v", err)
 	}
+	if c.strictNulls {
+		if err := decodeNullableFields(raw, parsedResponse.Bugs); err != nil {
+			return nil, err
+		}
+	}
 	return parsedResponse.Bugs, nil
 }
 
 // GetBug retrieves a Bug from the server
 // https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#get-bug
 func (c *client) GetBug(id int) (*Bug, error) {
-	logger := c.logger.WithFields(logrus.Fields{methodField: "GetBug", "id": id})
+	logger := c.logger.WithFields(LogFields{methodField: "GetBug", "id": id})
+	if c.bugCache != nil {
+		return c.cachedBug(id, logger)
+	}
+	return c.getBug(id, FieldsDefault, logger)
+}
+
+// GetBugWithFields retrieves a Bug from the server, asking the server to only
+// include the given fields in the response. Fields which were not requested
+// are left at their zero value in the returned Bug.
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/general.html#useful-parameters
+func (c *client) GetBugWithFields(id int, fields []string) (*Bug, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "GetBugWithFields", "id": id, "fields": fields})
+	return c.getBug(id, Fields{Include: fields}, logger)
+}
+
+// GetBugs retrieves the bugs with the IDs in one request, with the selected
+// fields. The server fails the request if any of the bugs does not exist.
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#get-bug
+func (c *client) GetBugs(ids []int, fields Fields) ([]*Bug, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "GetBugs", "ids": ids, "include": fields.Include, "exclude": fields.Exclude})
+	if len(ids) == 0 {
+		return []*Bug{}, nil
+	}
+	values := &url.Values{}
+	for _, id := range ids {
+		values.Add("id", strconv.Itoa(id))
+	}
+	fields.addTo(values)
+	return c.getBugs(fmt.Sprintf("%s/rest/bug", c.endpoint), values, logger)
+}
+
+// BulkGetBugs retrieves the bugs concurrently. Every request goes through the
+// rate limiter, if any, so concurrency only bounds the requests in flight.
+func (c *client) BulkGetBugs(ids []int, concurrency int) ([]*Bug, error) {
+	return bulkGetBugs(c.GetBug, ids, concurrency)
+}
+
+func (c *client) getBug(id int, fields Fields, logger *logEntry) (*Bug, error) {
+	values := &url.Values{}
+	fields.addTo(values)
 	url := fmt.Sprintf("%s/rest/bug/%d", c.endpoint, id)
-	bugs, err := c.getBugs(url, nil, logger)
+	bugs, err := c.getBugs(url, values, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -137,7 +387,7 @@ func (c *client) GetBug(id int) (*Bug, error) {
 // GetBugComments retrieves the comments of a Bug from the server
 // https://bugzilla.readthedocs.io/en/latest/api/core/v1/comment.html#get-comments
 func (c *client) GetBugComments(id int) ([]Comment, error) {
-	logger := c.logger.WithFields(logrus.Fields{methodField: "GetBugComments", "id": id})
+	logger := c.logger.WithFields(LogFields{methodField: "GetBugComments", "id": id})
 	url := fmt.Sprintf("%s/rest/bug/%d/comment", c.endpoint, id)
 
 	req, err := http.NewRequest(http.MethodGet, url, nil)
@@ -153,7 +403,7 @@ func (c *client) GetBugComments(id int) ([]Comment, error) {
 			Comments []Comment `json:"comments,omitempty"`
 		} `json:"bugs,omitempty"`
 	}
-	if err := json.Unmarshal(raw, &parsedResponse); err != nil {
+	if err := c.unmarshal(raw, &parsedResponse); err != nil {
 		return nil, fmt.Errorf("could not unmarshal response body: %v", err)
 	}
 	if len(parsedResponse.Bugs) != 1 {
@@ -165,10 +415,110 @@ func (c *client) GetBugComments(id int) ([]Comment, error) {
 	return nil, nil
 }
 
+// GetBugDescription retrieves the description of the bug, which is its first
+// comment, without downloading the text of all comments: only the IDs of the
+// comments are retrieved to find the first one, which is then retrieved alone.
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/comment.html#get-comments
+func (c *client) GetBugDescription(id int) (string, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "GetBugDescription", "id": id})
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/rest/bug/%d/comment", c.endpoint, id), nil)
+	if err != nil {
+		return "", err
+	}
+	values := req.URL.Query()
+	values.Set("include_fields", "id,count")
+	req.URL.RawQuery = values.Encode()
+	raw, err := c.request(req, logger)
+	if err != nil {
+		return "", err
+	}
+	var parsedResponse struct {
+		Bugs map[string]*struct {
+			Comments []Comment `json:"comments,omitempty"`
+		} `json:"bugs,omitempty"`
+	}
+	if err := c.unmarshal(raw, &parsedResponse); err != nil {
+		return "", fmt.Errorf("could not unmarshal response body: %v", err)
+	}
+	if len(parsedResponse.Bugs) != 1 {
+		return "", fmt.Errorf("did not get one bug, but %d: %v", len(parsedResponse.Bugs), parsedResponse.Bugs)
+	}
+	var description *Comment
+	for _, comments := range parsedResponse.Bugs {
+		description = firstComment(comments.Comments)
+	}
+	if description == nil {
+		return "", fmt.Errorf("bug %d has no description", id)
+	}
+
+	req, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/rest/bug/comment/%d", c.endpoint, description.Id), nil)
+	if err != nil {
+		return "", err
+	}
+	raw, err = c.request(req, logger)
+	if err != nil {
+		return "", err
+	}
+	var commentResponse struct {
+		Comments map[string]Comment `json:"comments,omitempty"`
+	}
+	if err := c.unmarshal(raw, &commentResponse); err != nil {
+		return "", fmt.Errorf("could not unmarshal response body: %v", err)
+	}
+	comment, ok := commentResponse.Comments[strconv.Itoa(description.Id)]
+	if !ok {
+		return "", fmt.Errorf("did not get comment %d", description.Id)
+	}
+	return comment.Text, nil
+}
+
+// firstComment returns the comment with the lowest count, which is the
+// description, or nil if there are no comments
+func firstComment(comments []Comment) *Comment {
+	var first *Comment
+	for i := range comments {
+		if first == nil || comments[i].Count < first.Count {
+			first = &comments[i]
+		}
+	}
+	return first
+}
+
+// UpdateCommentTags adds tags to and removes tags from the comment, e.g. to
+// mark it as spam, and returns the tags the comment has afterwards
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/comment.html#update-comment-tags
+func (c *client) UpdateCommentTags(commentID int, add, remove []string) ([]string, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "UpdateCommentTags", "comment": commentID})
+	body, err := json.Marshal(struct {
+		CommentID int      `json:"comment_id"`
+		Add       []string `json:"add,omitempty"`
+		Remove    []string `json:"remove,omitempty"`
+	}{CommentID: commentID, Add: add, Remove: remove})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal update payload: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/rest/bug/comment/%d/tags", c.endpoint, commentID), bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// the bug of the comment is not known, so the request declares no
+	// changes and all cached bugs are dropped
+	raw, err := c.request(req, logger)
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	if err := c.unmarshal(raw, &tags); err != nil {
+		return nil, fmt.Errorf("could not unmarshal response body: %v", err)
+	}
+	return tags, nil
+}
+
 // GetBugHistory retrieves the history of a Bug from the server
 // https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#bug-history
 func (c *client) GetBugHistory(id int) ([]History, error) {
-	logger := c.logger.WithFields(logrus.Fields{methodField: "GetBugHistory", "id": id})
+	logger := c.logger.WithFields(LogFields{methodField: "GetBugHistory", "id": id})
 	url := fmt.Sprintf("%s/rest/bug/%d/history", c.endpoint, id)
 
 	req, err := http.NewRequest(http.MethodGet, url, nil)
@@ -184,7 +534,7 @@ func (c *client) GetBugHistory(id int) ([]History, error) {
 			History []History `json:"history,omitempty"`
 		} `json:"bugs,omitempty"`
 	}
-	if err := json.Unmarshal(raw, &parsedResponse); err != nil {
+	if err := c.unmarshal(raw, &parsedResponse); err != nil {
 		return nil, fmt.Errorf("could not unmarshal response body: %v", err)
 	}
 	if len(parsedResponse.Bugs) != 1 {
@@ -193,6 +543,191 @@ func (c *client) GetBugHistory(id int) ([]History, error) {
 	return parsedResponse.Bugs[0].History, nil
 }
 
+// GetBugAttachments retrieves the metadata of the attachments of a Bug from the server
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/attachment.html#get-attachment
+func (c *client) GetBugAttachments(id int) ([]Attachment, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "GetBugAttachments", "id": id})
+	url := fmt.Sprintf("%s/rest/bug/%d/attachment", c.endpoint, id)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	values := req.URL.Query()
+	values.Set("exclude_fields", "data")
+	req.URL.RawQuery = values.Encode()
+	raw, err := c.request(req, logger)
+	if err != nil {
+		return nil, err
+	}
+	var parsedResponse struct {
+		Bugs map[string][]Attachment `json:"bugs,omitempty"`
+	}
+	if err := c.unmarshal(raw, &parsedResponse); err != nil {
+		return nil, fmt.Errorf("could not unmarshal response body: %v", err)
+	}
+	if len(parsedResponse.Bugs) != 1 {
+		return nil, fmt.Errorf("did not get one bug, but %d: %v", len(parsedResponse.Bugs), parsedResponse.Bugs)
+	}
+	for _, attachments := range parsedResponse.Bugs {
+		return attachments, nil
+	}
+	return nil, nil
+}
+
+// GetAttachmentData retrieves the decoded data of an attachment from the server
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/attachment.html#get-attachment
+func (c *client) GetAttachmentData(id int) ([]byte, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "GetAttachmentData", "id": id})
+	url := fmt.Sprintf("%s/rest/bug/attachment/%d", c.endpoint, id)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	values := req.URL.Query()
+	values.Set("include_fields", "data")
+	req.URL.RawQuery = values.Encode()
+	raw, err := c.request(req, logger)
+	if err != nil {
+		return nil, err
+	}
+	var parsedResponse struct {
+		Attachments map[string]struct {
+			Data []byte `json:"data"`
+		} `json:"attachments,omitempty"`
+	}
+	if err := c.unmarshal(raw, &parsedResponse); err != nil {
+		return nil, fmt.Errorf("could not unmarshal response body: %v", err)
+	}
+	attachment, ok := parsedResponse.Attachments[strconv.Itoa(id)]
+	if !ok {
+		return nil, &RequestError{StatusCode: http.StatusNotFound, Message: fmt.Sprintf("attachment %d not found", id)}
+	}
+	return attachment.Data, nil
+}
+
+// GetBugFull retrieves a bug with everything attached to it, see getBugFull
+func (c *client) GetBugFull(id int) (*FullBug, error) {
+	return getBugFull(c, id)
+}
+
+// GetProduct retrieves the metadata of a product, including its components,
+// versions and milestones, from the server
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/product.html#get-product
+func (c *client) GetProduct(name string) (*Product, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "GetProduct", "product": name})
+	values := &url.Values{}
+	values.Set("names", name)
+	products, err := c.getProducts(values, logger)
+	if err != nil {
+		return nil, err
+	}
+	for i := range products {
+		if products[i].Name == name {
+			return &products[i], nil
+		}
+	}
+	return nil, &RequestError{StatusCode: http.StatusNotFound, Message: fmt.Sprintf("product %q not found", name)}
+}
+
+// ListProducts retrieves the metadata of all products the user can search
+// or file bugs against from the server
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/product.html#get-product
+func (c *client) ListProducts() ([]Product, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "ListProducts"})
+	values := &url.Values{}
+	values.Set("type", "accessible")
+	return c.getProducts(values, logger)
+}
+
+func (c *client) getProducts(values *url.Values, logger *logEntry) ([]Product, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/rest/product", c.endpoint), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.URL.RawQuery = values.Encode()
+	raw, err := c.request(req, logger)
+	if err != nil {
+		return nil, err
+	}
+	var parsedResponse struct {
+		Products []Product `json:"products,omitempty"`
+	}
+	if err := c.unmarshal(raw, &parsedResponse); err != nil {
+		return nil, fmt.Errorf("could not unmarshal response body: %v", err)
+	}
+	return parsedResponse.Products, nil
+}
+
+// GetCurrentUser retrieves the user the client is authenticated as, to check
+// whose identity the API key carries
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/user.html#who-am-i
+func (c *client) GetCurrentUser() (*User, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "GetCurrentUser"})
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/rest/whoami", c.endpoint), nil)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := c.request(req, logger)
+	if err != nil {
+		return nil, err
+	}
+	var user User
+	if err := c.unmarshal(raw, &user); err != nil {
+		return nil, fmt.Errorf("could not unmarshal response body: %v", err)
+	}
+	return &user, nil
+}
+
+// SearchUsers retrieves the users whose login name, real name or e-mail
+// matches, e.g. to resolve an e-mail to an account before assigning bugs. The
+// server limits the number of users returned and only does substring matching
+// for logged in users.
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/user.html#get-user
+func (c *client) SearchUsers(match string) ([]User, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "SearchUsers", "match": match})
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/rest/user", c.endpoint), nil)
+	if err != nil {
+		return nil, err
+	}
+	values := req.URL.Query()
+	values.Add("match", match)
+	req.URL.RawQuery = values.Encode()
+	raw, err := c.request(req, logger)
+	if err != nil {
+		return nil, err
+	}
+	var parsedResponse struct {
+		Users []User `json:"users,omitempty"`
+	}
+	if err := c.unmarshal(raw, &parsedResponse); err != nil {
+		return nil, fmt.Errorf("could not unmarshal response body: %v", err)
+	}
+	return parsedResponse.Users, nil
+}
+
+// GetGroups retrieves the groups visible to the user from the server
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/group.html#get-group
+func (c *client) GetGroups() ([]Group, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "GetGroups"})
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/rest/group", c.endpoint), nil)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := c.request(req, logger)
+	if err != nil {
+		return nil, err
+	}
+	var parsedResponse struct {
+		Groups []Group `json:"groups,omitempty"`
+	}
+	if err := c.unmarshal(raw, &parsedResponse); err != nil {
+		return nil, fmt.Errorf("could not unmarshal response body: %v", err)
+	}
+	return parsedResponse.Groups, nil
+}
+
 // GetExternalBugPRsOnBug retrieves external bugs on a Bug from the server
 // and returns any that reference a Pull Request in GitHub
 // https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#get-bug
@@ -202,13 +737,13 @@ func (c *client) GetExternalBugPRsOnBug(id int) ([]ExternalBug, error) {
 		return nil, err
 	}
 
-	return filterPRs(ebs)
+	return filterPRs(ebs, c.gitHubTrackers())
 }
 
 // GetExternalBugPRsOnBug retrieves external bugs on a Bug from the server
 // https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#get-bug
 func (c *client) GetExternalBugs(id int) ([]ExternalBug, error) {
-	logger := c.logger.WithFields(logrus.Fields{methodField: "GetExternalBugPRsOnBug", "id": id})
+	logger := c.logger.WithFields(LogFields{methodField: "GetExternalBugPRsOnBug", "id": id})
 	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/rest/bug/%d", c.endpoint, id), nil)
 	if err != nil {
 		return nil, err
@@ -225,7 +760,7 @@ func (c *client) GetExternalBugs(id int) ([]ExternalBug, error) {
 			ExternalBugs []ExternalBug `json:"external_bugs"`
 		} `json:"bugs"`
 	}
-	if err := json.Unmarshal(raw, &parsedResponse); err != nil {
+	if err := c.unmarshal(raw, &parsedResponse); err != nil {
 		return nil, fmt.Errorf("could not unmarshal response body: %v", err)
 	}
 	if len(parsedResponse.Bugs) != 1 {
@@ -241,11 +776,88 @@ func (c *client) GetExternalBugs(id int) ([]ExternalBug, error) {
 	return prs, nil
 }
 
-func filterPRs(ebs []ExternalBug) ([]ExternalBug, error) {
+// externalBugsBatchSize is the number of bugs GetExternalBugsForBugs asks for
+// per request, which keeps the URL of the request short enough for proxies
+const externalBugsBatchSize = 100
+
+// GetExternalBugsForBugs retrieves the external bugs of all of the bugs, by
+// bug ID, asking for many bugs in every request. Bugs without external bugs
+// have no entry. If any of the bugs does not exist, the server fails the
+// request.
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#get-bug
+func (c *client) GetExternalBugsForBugs(ids []int) (map[int][]ExternalBug, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "GetExternalBugsForBugs"})
+	external := map[int][]ExternalBug{}
+	for start := 0; start < len(ids); start += externalBugsBatchSize {
+		end := start + externalBugsBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/rest/bug", c.endpoint), nil)
+		if err != nil {
+			return nil, err
+		}
+		values := req.URL.Query()
+		for _, id := range ids[start:end] {
+			values.Add("id", strconv.Itoa(id))
+		}
+		values.Add("include_fields", "id,external_bugs")
+		req.URL.RawQuery = values.Encode()
+		raw, err := c.request(req, logger.WithField("ids", ids[start:end]))
+		if err != nil {
+			return nil, err
+		}
+		var parsedResponse struct {
+			Bugs []struct {
+				ID           int           `json:"id"`
+				ExternalBugs []ExternalBug `json:"external_bugs"`
+			} `json:"bugs"`
+		}
+		if err := c.unmarshal(raw, &parsedResponse); err != nil {
+			return nil, fmt.Errorf("could not unmarshal response body: %v", err)
+		}
+		for _, bug := range parsedResponse.Bugs {
+			for _, externalBug := range bug.ExternalBugs {
+				if externalBug.BugzillaBugID != bug.ID {
+					continue
+				}
+				external[bug.ID] = append(external[bug.ID], externalBug)
+			}
+		}
+	}
+	return external, nil
+}
+
+// GetExternalBugPRsOnBugs retrieves the external bugs of all of the bugs, like
+// GetExternalBugsForBugs does, and returns those which reference a pull
+// request in GitHub, by bug ID. Allowed org names, like "openshift", or
+// org/repo names, like "openshift/installer", limit the pull requests to
+// those orgs and repos; all pull requests are returned if none are allowed.
+// Bugs without matching pull requests have no entry.
+func (c *client) GetExternalBugPRsOnBugs(ids []int, allowed ...string) (map[int][]ExternalBug, error) {
+	external, err := c.GetExternalBugsForBugs(ids)
+	if err != nil {
+		return nil, err
+	}
+	prs := map[int][]ExternalBug{}
+	for id, ebs := range external {
+		filtered, err := filterPRs(ebs, c.gitHubTrackers())
+		if err != nil {
+			return nil, fmt.Errorf("bug %d: %v", id, err)
+		}
+		if filtered = filterAllowedPRs(filtered, allowed); len(filtered) > 0 {
+			prs[id] = filtered
+		}
+	}
+	return prs, nil
+}
+
+// filterPRs returns the external bugs which are pull requests in any of the
+// GitHub trackers
+func filterPRs(ebs []ExternalBug, trackers []string) ([]ExternalBug, error) {
 	var prs []ExternalBug
 	for _, bug := range ebs {
-		if bug.Type.URL != "https://github.com/" {
-			// TODO: skuznets: figure out how to honor the endpoints given to the GitHub client to support enterprise here
+		if !contains(trackers, bug.Type.URL) {
 			continue
 		}
 		org, repo, num, err := PullFromIdentifier(bug.ExternalBugID)
@@ -263,37 +875,193 @@ func filterPRs(ebs []ExternalBug) ([]ExternalBug, error) {
 	return prs, nil
 }
 
+// filterAllowedPRs returns the pull requests in the allowed orgs, like "org",
+// or repos, like "org/repo", or all of them if none are allowed
+func filterAllowedPRs(prs []ExternalBug, allowed []string) []ExternalBug {
+	if len(allowed) == 0 {
+		return prs
+	}
+	var filtered []ExternalBug
+	for _, pr := range prs {
+		for _, name := range allowed {
+			if strings.EqualFold(name, pr.Org) || strings.EqualFold(name, pr.Org+"/"+pr.Repo) {
+				filtered = append(filtered, pr)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
 // UpdateBug updates the fields of a bug on the server
 // https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#update-bug
 func (c *client) UpdateBug(id int, update BugUpdate) error {
+	logger := c.logger.WithFields(LogFields{methodField: "UpdateBug", "id": id})
+	update = c.adaptUpdate(update, logger)
 	body, err := json.Marshal(update)
-	logger := c.logger.WithFields(logrus.Fields{methodField: "UpdateBug", "id": id, "update": string(body)})
+	logger = logger.WithField("update", string(body))
 	if err != nil {
 		return fmt.Errorf("failed to marshal update payload: %v", err)
 	}
+	if err := c.checkLastChange(id, "", update, logger); err != nil {
+		return err
+	}
 	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/rest/bug/%d", c.endpoint, id), bytes.NewBuffer(body))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
+	req = changesUpdatedBugs(req, update, id)
+
+	_, err = c.request(req, logger)
+	return midairCollision(id, "", update, err)
+}
+
+// UpdateBugs applies the update to all of the bugs in a single call, which
+// the server applies to all of the bugs or, if any of them fails, none.
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#update-bug
+func (c *client) UpdateBugs(ids []int, update BugUpdate) error {
+	logger := c.logger.WithFields(LogFields{methodField: "UpdateBugs", "ids": ids})
+	if len(ids) == 0 {
+		return errors.New("no bugs to update")
+	}
+	if update.LastChangeTime != nil && len(ids) > 1 {
+		return errors.New("the last change time can only guard updates of a single bug")
+	}
+	update = c.adaptUpdate(update, logger)
+	raw, err := json.Marshal(update)
+	if err != nil {
+		return fmt.Errorf("failed to marshal update payload: %v", err)
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return fmt.Errorf("failed to marshal update payload: %v", err)
+	}
+	if fields["ids"], err = json.Marshal(ids); err != nil {
+		return fmt.Errorf("failed to marshal update payload: %v", err)
+	}
+	body, err := json.Marshal(fields)
+	logger = logger.WithField("update", string(body))
+	if err != nil {
+		return fmt.Errorf("failed to marshal update payload: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/rest/bug/%d", c.endpoint, ids[0]), bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req = changesUpdatedBugs(req, update, ids...)
 
 	_, err = c.request(req, logger)
 	return err
 }
 
-func (c *client) request(req *http.Request, logger *logrus.Entry) ([]byte, error) {
+// CreateBug creates a new bug on the server and returns its ID
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#create-bug
+func (c *client) CreateBug(bug BugCreate) (int, error) {
+	body, err := json.Marshal(bug)
+	logger := c.logger.WithFields(LogFields{methodField: "CreateBug", "bug": string(body)})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal create payload: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/rest/bug", c.endpoint), bytes.NewBuffer(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// the new bug is not cached, but the bugs it blocks or depends on change
+	req = changesBugs(req, append(append([]int{}, bug.Blocks...), bug.DependsOn...)...)
+
+	raw, err := c.request(req, logger)
+	if err != nil {
+		return 0, err
+	}
+	var parsedResponse struct {
+		ID int `json:"id"`
+	}
+	if err := c.unmarshal(raw, &parsedResponse); err != nil {
+		return 0, fmt.Errorf("could not unmarshal response body: %v", err)
+	}
+	return parsedResponse.ID, nil
+}
+
+// CreateAttachment attaches the file to the bug and returns the ID of the
+// attachment
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/attachment.html#create-attachment
+func (c *client) CreateAttachment(id int, attachment AttachmentCreate) (int, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "CreateAttachment", "id": id, "file": attachment.FileName})
+	body, err := json.Marshal(struct {
+		IDs []int `json:"ids"`
+		AttachmentCreate
+	}{IDs: []int{id}, AttachmentCreate: attachment})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal attachment payload: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/rest/bug/%d/attachment", c.endpoint, id), bytes.NewBuffer(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req = changesBugs(req, id)
+	raw, err := c.request(req, logger)
+	if err != nil {
+		return 0, err
+	}
+	var parsedResponse struct {
+		// the server sends the IDs as strings or numbers, depending on its version
+		IDs []json.Number `json:"ids"`
+	}
+	if err := c.unmarshal(raw, &parsedResponse); err != nil {
+		return 0, fmt.Errorf("could not unmarshal response body: %v", err)
+	}
+	if len(parsedResponse.IDs) != 1 {
+		return 0, fmt.Errorf("did not get one attachment, but %d: %v", len(parsedResponse.IDs), parsedResponse.IDs)
+	}
+	attachmentID, err := parsedResponse.IDs[0].Int64()
+	if err != nil {
+		return 0, fmt.Errorf("could not parse attachment ID: %v", err)
+	}
+	return int(attachmentID), nil
+}
+
+// CloneBug creates a copy of the bug and returns the ID of the clone, see cloneBug
+func (c *client) CloneBug(bug *Bug, mutations ...CloneOption) (int, error) {
+	return cloneBug(c, bug, mutations...)
+}
+
+func (c *client) request(req *http.Request, logger *logEntry) ([]byte, error) {
+	if c.bugCache != nil && !mayRetry(req) {
+		defer c.bugCache.invalidateChanged(req)
+	}
+	if c.audit != nil && auditable(req) {
+		return c.auditedRequest(req, logger)
+	}
+	return c.degradableRequest(req, logger)
+}
+
+func (c *client) degradableRequest(req *http.Request, logger *logEntry) ([]byte, error) {
+	if c.degradation == nil {
+		return c.authenticatedRequest(req, logger)
+	}
+	method := logger.Data[methodField].(string)
+	if c.nonCritical && c.degradation.Degraded() {
+		c.metrics.skippedRequest(method)
+		return nil, &SkippedDueToDegradationError{Method: method}
+	}
+	raw, err := c.authenticatedRequest(req, logger)
+	c.degradation.Observe(err)
+	return raw, err
+}
+
+func (c *client) authenticatedRequest(req *http.Request, logger *logEntry) ([]byte, error) {
 	logger = logger.WithField("url", obfuscatedURL(req.URL.String())).WithField("verb", req.Method)
+	c.setBasicAuth(req)
+	if c.usesAuthMethod(AuthToken) {
+		return c.requestWithToken(req, logger)
+	}
 	if apiKey := c.getAPIKey(); len(apiKey) > 0 {
-		switch c.authMethod {
-		case AuthBearer:
-			req.Header.Set("Authorization", "Bearer "+string(apiKey))
-		case AuthQuery:
-			values := req.URL.Query()
-			values.Add("api_key", string(apiKey))
-			req.URL.RawQuery = values.Encode()
-		case AuthXBugzillaAPIKey:
-			req.Header.Set("X-BUGZILLA-API-KEY", string(apiKey))
-		default:
+		methods := c.authMethods()
+		if len(methods) == 0 {
 			// If there is no auth method specified, we use a union of `query` and
 			// `x-bugzilla-api-key` to mimic the previous default behavior which attempted
 			// to satisfy different BugZilla server versions.
@@ -302,7 +1070,38 @@ func (c *client) request(req *http.Request, logger *logrus.Entry) ([]byte, error
 			values.Add("api_key", string(apiKey))
 			req.URL.RawQuery = values.Encode()
 		}
+		for _, method := range methods {
+			switch method {
+			case AuthBearer:
+				req.Header.Set("Authorization", "Bearer "+string(apiKey))
+			case AuthQuery:
+				values := req.URL.Query()
+				values.Add("api_key", string(apiKey))
+				req.URL.RawQuery = values.Encode()
+			case AuthXBugzillaAPIKey:
+				req.Header.Set("X-BUGZILLA-API-KEY", string(apiKey))
+			}
+		}
+	}
+	return c.requestWithFailover(req, logger)
+}
+
+func (c *client) doRequest(req *http.Request, logger *logEntry) ([]byte, error) {
+	if c.optionsErr != nil {
+		return nil, c.optionsErr
+	}
+	if c.limiter != nil && c.limiter.priorities != nil {
+		c.limiter.waitForTurn(c.timeSource(), c.requestPriority(logger))
+	} else if c.limiter != nil {
+		c.limiter.wait(c.timeSource())
+	}
+	observed := c.observesPayloads(logger)
+	var observedReq *http.Request
+	if observed {
+		observedReq = c.observeRequest(req, logger)
 	}
+	req, cancel := c.withTimeout(req, logger)
+	defer cancel()
 	start := time.Now()
 	resp, err := c.client.Do(req)
 	stop := time.Now()
@@ -311,6 +1110,9 @@ func (c *client) request(req *http.Request, logger *logrus.Entry) ([]byte, error
 		promLabels["status"] = strconv.Itoa(resp.StatusCode)
 	}
 	requestDurations.With(promLabels).Observe(float64(stop.Sub(start).Seconds()))
+	if c.metrics != nil {
+		c.metrics.observe(promLabels, stop.Sub(start), err != nil || resp.StatusCode != http.StatusOK)
+	}
 	if resp != nil {
 		logger.WithField("response", resp.StatusCode).Debug("Got response from Bugzilla.")
 	}
@@ -319,122 +1121,105 @@ func (c *client) request(req *http.Request, logger *logrus.Entry) ([]byte, error
 		if resp != nil {
 			code = resp.StatusCode
 		}
-		return nil, &requestError{statusCode: code, message: err.Error()}
+		return nil, c.redactError(&RequestError{StatusCode: code, Message: err.Error()})
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
 			logger.WithError(err).Warn("could not close response body")
 		}
 	}()
+	body := io.Reader(resp.Body)
+	if c.maxResponseSize > 0 {
+		if resp.ContentLength > c.maxResponseSize {
+			return nil, &ResponseTooLargeError{StatusCode: resp.StatusCode, Limit: c.maxResponseSize}
+		}
+		body = io.LimitReader(resp.Body, c.maxResponseSize+1)
+	}
+	raw, err := ioutil.ReadAll(body)
+	if c.maxResponseSize > 0 && int64(len(raw)) > c.maxResponseSize {
+		return nil, &ResponseTooLargeError{StatusCode: resp.StatusCode, Limit: c.maxResponseSize}
+	}
+	if observed {
+		c.observeResponse(resp, raw, observedReq, logger)
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, &requestError{statusCode: resp.StatusCode, message: fmt.Sprintf("response code %d not %d", resp.StatusCode, http.StatusOK)}
+		reqError := newRequestError(resp.StatusCode, raw)
+		reqError.RetryAfter = retryAfter(resp.Header.Get("Retry-After"), c.now())
+		return nil, c.redactError(reqError)
 	}
-	raw, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("could not read response body: %v", err)
 	}
 	return raw, nil
 }
 
-type requestError struct {
-	statusCode int
-	message    string
+// AddPullRequestAsExternalBug attempts to add a PR to the external tracker list.
+// External bugs are assumed to fall under the type identified by their hostname,
+// so we will provide GitHubTracker, or the first tracker given to
+// WithGitHubTrackers, here for the URL identifier. We return
+// any error as well as whether a change was actually made.
+// This will be done via JSONRPC or XMLRPC, see WithRPCProtocol:
+// https://bugzilla.redhat.com/docs/en/html/integrating/api/Bugzilla/Extension/ExternalBugs/WebService.html#add-external-bug
+func (c *client) AddPullRequestAsExternalBug(id int, org, repo string, num int) (bool, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "AddExternalBug", "id": id, "org": org, "repo": repo, "num": num})
+	return c.addExternalBug(id, c.gitHubTrackers()[0], IdentifierForPull(org, repo, num), logger)
 }
 
-func (e requestError) Error() string {
-	return e.message
+// AddJiraIssueAsExternalBug attempts to add a Jira issue to the external tracker
+// list of the bug, using the tracker of issues.redhat.com. Like for pull requests,
+// it returns whether a change was actually made.
+// https://bugzilla.redhat.com/docs/en/html/integrating/api/Bugzilla/Extension/ExternalBugs/WebService.html#add-external-bug
+func (c *client) AddJiraIssueAsExternalBug(id int, project string, num int) (bool, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "AddExternalBug", "id": id, "project": project, "num": num})
+	return c.addExternalBug(id, JiraTracker, IdentifierForJiraIssue(project, num), logger)
 }
 
-func IsNotFound(err error) bool {
-	reqError, ok := err.(*requestError)
-	if !ok {
-		return false
+// UpdateExternalBugStatus updates the status of the external bug linked to
+// the bug, as shown in the external tracker table of the bug, e.g. to show
+// that a linked pull request merged or closed.
+// This will be done via JSONRPC or XMLRPC, see WithRPCProtocol:
+// https://bugzilla.redhat.com/docs/en/html/integrating/api/Bugzilla/Extension/ExternalBugs/WebService.html#update-external-bug
+func (c *client) UpdateExternalBugStatus(bugID int, identifier ExternalBugIdentifier, status string) error {
+	logger := c.logger.WithFields(LogFields{methodField: "UpdateExternalBugStatus", "id": bugID, "type": identifier.Type, "identifier": identifier.ID, "status": status})
+	params := UpdateExternalBugParameters{
+		APIKey:                string(c.getAPIKey()),
+		BugIDs:                []int{bugID},
+		ExternalBugIdentifier: identifier,
+		Status:                status,
 	}
-	return reqError.statusCode == http.StatusNotFound
+	var result interface{}
+	return c.rpcClient(logger).changing(bugID).Call("ExternalBugs.update_external_bug", params, &result)
 }
 
-// AddPullRequestAsExternalBug attempts to add a PR to the external tracker list.
-// External bugs are assumed to fall under the type identified by their hostname,
-// so we will provide https://github.com/ here for the URL identifier. We return
-// any error as well as whether a change was actually made.
-// This will be done via JSONRPC:
-// https://bugzilla.redhat.com/docs/en/html/integrating/api/Bugzilla/Extension/ExternalBugs/WebService.html#add-external-bug
-func (c *client) AddPullRequestAsExternalBug(id int, org, repo string, num int) (bool, error) {
-	logger := c.logger.WithFields(logrus.Fields{methodField: "AddExternalBug", "id": id, "org": org, "repo": repo, "num": num})
-	pullIdentifier := IdentifierForPull(org, repo, num)
-	rpcPayload := struct {
-		// Version is the version of JSONRPC to use. All Bugzilla servers
-		// support 1.0. Some support 1.1 and some support 2.0
-		Version string `json:"jsonrpc"`
-		Method  string `json:"method"`
-		// Parameters must be specified in JSONRPC 1.0 as a structure in the first
-		// index of this slice
-		Parameters []AddExternalBugParameters `json:"params"`
-		ID         string                     `json:"id"`
-	}{
-		Version: "1.0", // some Bugzilla servers support 2.0 but all support 1.0
-		Method:  "ExternalBugs.add_external_bug",
-		ID:      "identifier", // this is useful when fielding asynchronous responses, but not here
-		Parameters: []AddExternalBugParameters{{
-			APIKey: string(c.getAPIKey()),
-			BugIDs: []int{id},
-			ExternalBugs: []NewExternalBugIdentifier{{
-				Type: "https://github.com/",
-				ID:   pullIdentifier,
-			}},
+// addExternalBug adds the external bug with the identifier in the tracker of
+// the type to the bug and returns whether it was not added before
+func (c *client) addExternalBug(id int, trackerType, identifier string, logger *logEntry) (bool, error) {
+	params := AddExternalBugParameters{
+		APIKey: string(c.getAPIKey()),
+		BugIDs: []int{id},
+		ExternalBugs: []NewExternalBugIdentifier{{
+			Type: trackerType,
+			ID:   identifier,
 		}},
 	}
-	body, err := json.Marshal(rpcPayload)
-	if err != nil {
-		return false, fmt.Errorf("failed to marshal JSONRPC payload: %v", err)
-	}
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/jsonrpc.cgi", c.endpoint), bytes.NewBuffer(body))
-	if err != nil {
-		return false, err
+	var result struct {
+		Bugs []struct {
+			ID      int `json:"id"`
+			Changes struct {
+				ExternalBugs struct {
+					Added   string `json:"added"`
+					Removed string `json:"removed"`
+				} `json:"ext_bz_bug_map.ext_bz_bug_id"`
+			} `json:"changes"`
+		} `json:"bugs"`
 	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.request(req, logger)
-	if err != nil {
+	if err := c.rpcClient(logger).changing(id).Call("ExternalBugs.add_external_bug", params, &result); err != nil {
 		return false, err
 	}
-	var response struct {
-		Error *struct {
-			Code    int    `json:"code"`
-			Message string `json:"message"`
-		} `json:"error,omitempty"`
-		ID     string `json:"id"`
-		Result *struct {
-			Bugs []struct {
-				ID      int `json:"id"`
-				Changes struct {
-					ExternalBugs struct {
-						Added   string `json:"added"`
-						Removed string `json:"removed"`
-					} `json:"ext_bz_bug_map.ext_bz_bug_id"`
-				} `json:"changes"`
-			} `json:"bugs"`
-		} `json:"result,omitempty"`
-	}
-	if err := json.Unmarshal(resp, &response); err != nil {
-		return false, fmt.Errorf("failed to unmarshal JSONRPC response: %v", err)
-	}
-	if response.Error != nil {
-		if response.Error.Code == 100500 && strings.Contains(response.Error.Message, `duplicate key value violates unique constraint "ext_bz_bug_map_bug_id_idx"`) {
-			// adding the external bug failed since it is already added, this is not an error
-			return false, nil
-		}
-		return false, fmt.Errorf("JSONRPC error %d: %v", response.Error.Code, response.Error.Message)
-	}
-	if response.ID != rpcPayload.ID {
-		return false, fmt.Errorf("JSONRPC returned mismatched identifier, expected %s but got %s", rpcPayload.ID, response.ID)
-	}
 	changed := false
-	if response.Result != nil {
-		for _, bug := range response.Result.Bugs {
-			if bug.ID == id {
-				changed = changed || strings.Contains(bug.Changes.ExternalBugs.Added, pullIdentifier)
-			}
+	for _, bug := range result.Bugs {
+		if bug.ID == id {
+			changed = changed || strings.Contains(bug.Changes.ExternalBugs.Added, identifier)
 		}
 	}
 	return changed, nil
@@ -473,8 +1258,32 @@ func IsIdentifierNotForPullErr(err error) bool {
 	return ok
 }
 
-var re = regexp.MustCompile(`api_key=[^&]*&`)
+// JiraTracker is the URL identifying the external tracker for Jira issues
+const JiraTracker = "https://issues.redhat.com/"
+
+// GitHubTracker is the URL identifying the external tracker for GitHub issues
+// and pull requests, see ResolveExternalTracker for other trackers
+const GitHubTracker = "https://github.com/"
+
+// jiraIssueKey matches the key of a Jira issue, like OCPBUGS-1234
+var jiraIssueKey = regexp.MustCompile(`^([A-Z][A-Z0-9_]*)-([1-9][0-9]*)$`)
 
-func obfuscatedURL(url string) string {
-	return re.ReplaceAllString(url, `api_key=xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx`)
+// IdentifierForJiraIssue returns the external bug identifier of the Jira
+// issue, which is its key
+func IdentifierForJiraIssue(project string, num int) string {
+	return fmt.Sprintf("%s-%d", project, num)
+}
+
+// JiraIssueFromIdentifier parses the external bug identifier of a Jira issue
+// into the key of its project and its number
+func JiraIssueFromIdentifier(identifier string) (project string, num int, err error) {
+	match := jiraIssueKey.FindStringSubmatch(identifier)
+	if match == nil {
+		return "", 0, fmt.Errorf("invalid Jira issue identifier: %q", identifier)
+	}
+	number, err := strconv.Atoi(match[2])
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid Jira issue identifier: could not parse %s as number: %v", match[2], err)
+	}
+	return match[1], number, nil
 }
@@ -19,6 +19,7 @@ package bugzilla
 import (
 	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -27,27 +28,29 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/diff"
 )
 
 var (
 	bugData   = []byte(`{"bugs":[{"alias":[],"assigned_to":"Steve Kuznetsov","assigned_to_detail":{"email":"skuznets","id":381851,"name":"skuznets","real_name":"Steve Kuznetsov"},"blocks":[],"cc":["Sudha Ponnaganti"],"cc_detail":[{"email":"sponnaga","id":426940,"name":"sponnaga","real_name":"Sudha Ponnaganti"}],"classification":"Red Hat","component":["Test Infrastructure"],"creation_time":"2019-05-01T19:33:36Z","creator":"Dan Mace","creator_detail":{"email":"dmace","id":330250,"name":"dmace","real_name":"Dan Mace"},"deadline":null,"depends_on":[],"docs_contact":"","dupe_of":null,"groups":[],"id":1705243,"is_cc_accessible":true,"is_confirmed":true,"is_creator_accessible":true,"is_open":true,"keywords":[],"last_change_time":"2019-05-17T15:13:13Z","op_sys":"Unspecified","platform":"Unspecified","priority":"unspecified","product":"OpenShift Container Platform","qa_contact":"","resolution":"","see_also":[],"severity":"medium","status":"VERIFIED","summary":"[ci] cli image flake affecting *-images jobs","target_milestone":"---","target_release":["3.11.z"],"url":"","version":["3.11.0"],"whiteboard":""}],"faults":[]}`)
-	bugStruct = &Bug{Alias: []string{}, AssignedTo: "Steve Kuznetsov", AssignedToDetail: &User{Email: "skuznets", ID: 381851, Name: "skuznets", RealName: "Steve Kuznetsov"}, Blocks: []int{}, CC: []string{"Sudha Ponnaganti"}, CCDetail: []User{{Email: "sponnaga", ID: 426940, Name: "sponnaga", RealName: "Sudha Ponnaganti"}}, Classification: "Red Hat", Component: []string{"Test Infrastructure"}, CreationTime: "2019-05-01T19:33:36Z", Creator: "Dan Mace", CreatorDetail: &User{Email: "dmace", ID: 330250, Name: "dmace", RealName: "Dan Mace"}, DependsOn: []int{}, ID: 1705243, IsCCAccessible: true, IsConfirmed: true, IsCreatorAccessible: true, IsOpen: true, Groups: []string{}, Keywords: []string{}, LastChangeTime: "2019-05-17T15:13:13Z", OperatingSystem: "Unspecified", Platform: "Unspecified", Priority: "unspecified", Product: "OpenShift Container Platform", SeeAlso: []string{}, Severity: "medium", Status: "VERIFIED", Summary: "[ci] cli image flake affecting *-images jobs", TargetRelease: []string{"3.11.z"}, TargetMilestone: "---", Version: []string{"3.11.0"}}
+	bugStruct = &Bug{Alias: []string{}, AssignedTo: "Steve Kuznetsov", AssignedToDetail: &User{Email: "skuznets", ID: 381851, Name: "skuznets", RealName: "Steve Kuznetsov"}, Blocks: []int{}, CC: []string{"Sudha Ponnaganti"}, CCDetail: []User{{Email: "sponnaga", ID: 426940, Name: "sponnaga", RealName: "Sudha Ponnaganti"}}, Classification: "Red Hat", Component: []string{"Test Infrastructure"}, CreationTime: mustParseTimestamp("2019-05-01T19:33:36Z"), Creator: "Dan Mace", CreatorDetail: &User{Email: "dmace", ID: 330250, Name: "dmace", RealName: "Dan Mace"}, DependsOn: []int{}, ID: 1705243, IsCCAccessible: true, IsConfirmed: true, IsCreatorAccessible: true, IsOpen: true, Groups: []string{}, Keywords: []string{}, LastChangeTime: mustParseTimestamp("2019-05-17T15:13:13Z"), OperatingSystem: "Unspecified", Platform: "Unspecified", Priority: "unspecified", Product: "OpenShift Container Platform", SeeAlso: []string{}, Severity: "medium", Status: "VERIFIED", Summary: "[ci] cli image flake affecting *-images jobs", TargetRelease: []string{"3.11.z"}, TargetMilestone: "---", Version: []string{"3.11.0"}}
 )
 
 func clientForUrl(url string) Client {
 	return &client{
-		logger:   logrus.WithField("testing", "true"),
+		logger:   newLogEntry(defaultLogger, LogFields{"testing": "true"}),
 		endpoint: url,
 		client: &http.Client{
 			Transport: &http.Transport{
 				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 			},
 		},
-		getAPIKey: func() []byte {
+		apiKey: newAPIKeySupplier(func() []byte {
 			return []byte("api-key")
-		},
+		}),
+		settings: &clientSettings{},
+		versions: &versionCache{},
+		rpcID:    func() string { return "identifier" },
 	}
 }
 
@@ -109,6 +112,42 @@ func TestGetBug(t *testing.T) {
 	}
 }
 
+func TestGetBugWithFields(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("incorrect method to get a bug: %s", r.Method)
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path != "/rest/bug/1705243" {
+			http.Error(w, "404 Not Found", http.StatusNotFound)
+			return
+		}
+		if actual, expected := r.URL.Query().Get("include_fields"), "id,status,target_release"; actual != expected {
+			t.Errorf("got incorrect include_fields: expected %q, got %q", expected, actual)
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"bugs":[{"id":1705243,"status":"VERIFIED","target_release":["3.11.z"]}],"faults":[]}`))
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	bug, err := client.GetBugWithFields(1705243, []string{"id", "status", "target_release"})
+	if err != nil {
+		t.Errorf("expected no error, but got one: %v", err)
+	}
+	expected := &Bug{ID: 1705243, Status: "VERIFIED", TargetRelease: []string{"3.11.z"}}
+	if !reflect.DeepEqual(bug, expected) {
+		t.Errorf("got incorrect bug: %v", diff.ObjectReflectDiff(bug, expected))
+	}
+
+	// this should 404
+	if _, err := client.GetBugWithFields(1, []string{"id"}); !IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
 func TestUpdateBug(t *testing.T) {
 	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Header.Get("X-BUGZILLA-API-KEY") != "api-key" {
@@ -171,6 +210,321 @@ func TestUpdateBug(t *testing.T) {
 	}
 }
 
+func TestUpdateBugs(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("incorrect method to update bugs: %s", r.Method)
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path != "/rest/bug/1" {
+			t.Errorf("incorrect path to update bugs: %s", r.URL.Path)
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+			return
+		}
+		raw, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read update body: %v", err)
+		}
+		if actual, expected := string(raw), `{"ids":[1,2,3],"target_release":"4.6.0"}`; actual != expected {
+			t.Errorf("got incorrect update: expected %v, got %v", expected, actual)
+		}
+		w.Write([]byte(`{"bugs":[{"id":1},{"id":2},{"id":3}]}`))
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	if err := client.UpdateBugs([]int{1, 2, 3}, BugUpdate{TargetRelease: "4.6.0"}); err != nil {
+		t.Errorf("expected no error, but got one: %v", err)
+	}
+	if err := client.UpdateBugs(nil, BugUpdate{TargetRelease: "4.6.0"}); err == nil {
+		t.Error("expected an error updating no bugs, but got none")
+	}
+}
+
+func TestFakeUpdateBugsIsAtomic(t *testing.T) {
+	fake := &Fake{Bugs: map[int]Bug{1: {ID: 1, TargetRelease: []string{"4.5.0"}}, 2: {ID: 2, TargetRelease: []string{"4.5.0"}}}}
+	if err := fake.UpdateBugs([]int{1, 3}, BugUpdate{TargetRelease: "4.6.0"}); !IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+	if actual := fake.Bugs[1].TargetRelease; !reflect.DeepEqual(actual, []string{"4.5.0"}) {
+		t.Errorf("expected bug 1 to be left alone, got target release %v", actual)
+	}
+	if err := fake.UpdateBugs([]int{1, 2}, BugUpdate{TargetRelease: "4.6.0"}); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	for _, id := range []int{1, 2} {
+		if actual := fake.Bugs[id].TargetRelease; !reflect.DeepEqual(actual, []string{"4.6.0"}) {
+			t.Errorf("expected bug %d to be updated, got target release %v", id, actual)
+		}
+	}
+}
+
+func TestFakeUpdateBugKeepsUnsetFields(t *testing.T) {
+	fake := &Fake{Bugs: map[int]Bug{1: {ID: 1, Status: "CLOSED", Resolution: "ERRATA"}}}
+	if err := fake.UpdateBug(1, BugUpdate{Whiteboard: "token"}); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if bug := fake.Bugs[1]; bug.Status != "CLOSED" || bug.Resolution != "ERRATA" || bug.Whiteboard != "token" {
+		t.Errorf("expected only the whiteboard to change, got %v", bug)
+	}
+	if err := fake.UpdateBug(1, BugUpdate{Status: "ASSIGNED"}); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if bug := fake.Bugs[1]; bug.Status != "ASSIGNED" || bug.Resolution != "" {
+		t.Errorf("expected reopening to clear the resolution, got %v", bug)
+	}
+}
+
+func TestCreateBug(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Error("did not correctly set content-type header for JSON")
+			http.Error(w, "403 Forbidden", http.StatusForbidden)
+			return
+		}
+		if r.Method != http.MethodPost {
+			t.Errorf("incorrect method to create a bug: %s", r.Method)
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path != "/rest/bug" {
+			t.Errorf("incorrect path to create a bug: %s", r.URL.Path)
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+			return
+		}
+		raw, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read create body: %v", err)
+		}
+		if actual, expected := string(raw), `{"product":"OpenShift Container Platform","component":"Test Infrastructure","summary":"flake","version":"4.5","description":"it flaked","depends_on":[1705243]}`; actual != expected {
+			t.Errorf("got incorrect create: expected %v, got %v", expected, actual)
+		}
+		w.Write([]byte(`{"id":1705244}`))
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	id, err := client.CreateBug(BugCreate{
+		Product:     "OpenShift Container Platform",
+		Component:   "Test Infrastructure",
+		Summary:     "flake",
+		Version:     "4.5",
+		Description: "it flaked",
+		DependsOn:   []int{1705243},
+	})
+	if err != nil {
+		t.Errorf("expected no error, but got one: %v", err)
+	}
+	if id != 1705244 {
+		t.Errorf("expected ID 1705244, got %d", id)
+	}
+}
+
+func TestGetProduct(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("incorrect method to get a product: %s", r.Method)
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path != "/rest/product" {
+			t.Errorf("incorrect path to get a product: %s", r.URL.Path)
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+			return
+		}
+		if r.URL.Query().Get("type") == "accessible" {
+			w.Write([]byte(`{"products":[{"id":1,"name":"OpenShift Container Platform"},{"id":2,"name":"Red Hat Enterprise Linux 8"}]}`))
+			return
+		}
+		if r.URL.Query().Get("names") != "OpenShift Container Platform" {
+			w.Write([]byte(`{"products":[]}`))
+			return
+		}
+		w.Write([]byte(`{"products":[{"id":1,"name":"OpenShift Container Platform","is_active":true,"default_milestone":"---","components":[{"id":2,"name":"Test Infrastructure","default_assigned_to":"dev@example.com","default_qa_contact":"qa@example.com","is_active":true}],"versions":[{"id":3,"name":"4.5","sort_key":0,"is_active":true}],"milestones":[{"id":4,"name":"---","sort_key":0,"is_active":true}]}]}`))
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	product, err := client.GetProduct("OpenShift Container Platform")
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	expected := &Product{
+		ID:               1,
+		Name:             "OpenShift Container Platform",
+		IsActive:         true,
+		DefaultMilestone: "---",
+		Components:       []ProductComponent{{ID: 2, Name: "Test Infrastructure", DefaultAssignedTo: "dev@example.com", DefaultQAContact: "qa@example.com", IsActive: true}},
+		Versions:         []ProductValue{{ID: 3, Name: "4.5", IsActive: true}},
+		Milestones:       []ProductValue{{ID: 4, Name: "---", IsActive: true}},
+	}
+	if !reflect.DeepEqual(product, expected) {
+		t.Errorf("got incorrect product: %v", diff.ObjectReflectDiff(expected, product))
+	}
+
+	if _, err := client.GetProduct("Missing"); !IsNotFound(err) {
+		t.Errorf("expected a not found error for a missing product, got %v", err)
+	}
+
+	products, err := client.ListProducts()
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if len(products) != 2 || products[0].Name != "OpenShift Container Platform" || products[1].Name != "Red Hat Enterprise Linux 8" {
+		t.Errorf("got incorrect products: %v", products)
+	}
+}
+
+func TestUsers(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("incorrect method to get users: %s", r.Method)
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/rest/whoami":
+			w.Write([]byte(`{"id":1,"real_name":"Bot","name":"bot@example.com","login":"bot@example.com"}`))
+		case "/rest/user":
+			if r.URL.Query().Get("match") != "example.com" {
+				w.Write([]byte(`{"users":[]}`))
+				return
+			}
+			w.Write([]byte(`{"users":[{"id":1,"real_name":"Bot","name":"bot@example.com","email":"bot@example.com"},{"id":2,"real_name":"Dev","name":"dev@example.com","email":"dev@example.com"}]}`))
+		default:
+			t.Errorf("incorrect path to get users: %s", r.URL.Path)
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+		}
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	user, err := client.GetCurrentUser()
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if expected := (&User{ID: 1, RealName: "Bot", Name: "bot@example.com"}); !reflect.DeepEqual(user, expected) {
+		t.Errorf("got incorrect user: %v", diff.ObjectReflectDiff(expected, user))
+	}
+
+	users, err := client.SearchUsers("example.com")
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	expected := []User{
+		{ID: 1, RealName: "Bot", Name: "bot@example.com", Email: "bot@example.com"},
+		{ID: 2, RealName: "Dev", Name: "dev@example.com", Email: "dev@example.com"},
+	}
+	if !reflect.DeepEqual(users, expected) {
+		t.Errorf("got incorrect users: %v", diff.ObjectReflectDiff(expected, users))
+	}
+	if users, err := client.SearchUsers("nobody"); err != nil || len(users) != 0 {
+		t.Errorf("expected no users, got %v (error %v)", users, err)
+	}
+}
+
+func TestFakeUsers(t *testing.T) {
+	fake := &Fake{Users: []User{{Name: "dev@example.com", RealName: "Dev Eloper"}, {Name: "qe@example.com", Email: "QE@example.com"}}}
+	if _, err := fake.GetCurrentUser(); !IsUnauthorized(err) {
+		t.Errorf("expected an unauthorized error without a current user, got %v", err)
+	}
+	users, err := fake.SearchUsers("eloper")
+	if err != nil || len(users) != 1 || users[0].Name != "dev@example.com" {
+		t.Errorf("expected to find the developer by real name, got %v (error %v)", users, err)
+	}
+	users, err = fake.SearchUsers("qe@EXAMPLE")
+	if err != nil || len(users) != 1 || users[0].Name != "qe@example.com" {
+		t.Errorf("expected to find the QE ignoring case, got %v (error %v)", users, err)
+	}
+}
+
+func TestGetGroups(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/rest/group" {
+			t.Errorf("incorrect request to get groups: %s %s", r.Method, r.URL.Path)
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"groups":[{"id":1,"name":"security","description":"Security Sensitive Bug","is_active":true,"user_regexp":""}]}`))
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	groups, err := client.GetGroups()
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	expected := []Group{{ID: 1, Name: "security", Description: "Security Sensitive Bug", IsActive: true}}
+	if !reflect.DeepEqual(groups, expected) {
+		t.Errorf("got incorrect groups: %v", diff.ObjectReflectDiff(expected, groups))
+	}
+}
+
+func TestFakeGroups(t *testing.T) {
+	fake := &Fake{}
+	id, err := fake.CreateBug(BugCreate{Summary: "leak", Groups: []string{"security"}})
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if err := fake.UpdateBug(id, BugUpdate{Groups: &BugGroups{Add: []string{"private"}, Remove: []string{"security"}}}); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if actual, expected := fake.Bugs[id].Groups, []string{"private"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected groups %v, got %v", expected, actual)
+	}
+}
+
+func TestBugUpdatePayload(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		update   BugUpdate
+		expected string
+	}{
+		{
+			name:     "empty update sends nothing",
+			expected: `{}`,
+		},
+		{
+			name: "list fields are sent as add/remove",
+			update: BugUpdate{
+				Keywords:  &BugKeywords{Add: []string{"Regression"}},
+				CC:        &BugCC{Add: []string{"a@example.com"}, Remove: []string{"b@example.com"}},
+				DependsOn: &BugIDs{Add: []int{1}},
+				Blocks:    &BugIDs{Remove: []int{2}},
+			},
+			expected: `{"keywords":{"add":["Regression"]},"cc":{"add":["a@example.com"],"remove":["b@example.com"]},"depends_on":{"add":[1]},"blocks":{"remove":[2]}}`,
+		},
+		{
+			name: "scalar fields and comment are sent when set",
+			update: BugUpdate{
+				Status:        "CLOSED",
+				Resolution:    "ERRATA",
+				TargetRelease: "4.5.0",
+				AssignedTo:    "someone@example.com",
+				Comment:       &BugComment{Body: "Fixed."},
+			},
+			expected: `{"status":"CLOSED","resolution":"ERRATA","target_release":"4.5.0","comment":{"body":"Fixed."},"assigned_to":"someone@example.com"}`,
+		},
+		{
+			name:     "groups are sent as add/remove",
+			update:   BugUpdate{Groups: &BugGroups{Add: []string{"security"}, Remove: []string{"private"}}},
+			expected: `{"groups":{"add":["security"],"remove":["private"]}}`,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			raw, err := json.Marshal(testCase.update)
+			if err != nil {
+				t.Fatalf("%s: failed to marshal update: %v", testCase.name, err)
+			}
+			if actual, expected := string(raw), testCase.expected; actual != expected {
+				t.Errorf("%s: got incorrect payload: expected %v, got %v", testCase.name, expected, actual)
+			}
+		})
+	}
+}
+
 func TestAddPullRequestAsExternalBug(t *testing.T) {
 	var testCases = []struct {
 		name            string
@@ -309,6 +663,46 @@ func TestAddPullRequestAsExternalBug(t *testing.T) {
 	}
 }
 
+func TestSearchInto(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/bug" {
+			t.Errorf("incorrect path to search bugs: %s", r.URL.Path)
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+			return
+		}
+		if actual, expected := r.URL.Query().Get("include_fields"), "id,status"; actual != expected {
+			t.Errorf("got incorrect include_fields: expected %q, got %q", expected, actual)
+		}
+		switch r.URL.Query().Get("offset") {
+		case "0":
+			w.Write([]byte(`{"bugs":[{"id":1,"status":"NEW"},{"id":2,"status":"ASSIGNED"}]}`))
+		case "2":
+			w.Write([]byte(`{"bugs":[{"id":3,"status":"POST"}]}`))
+		default:
+			w.Write([]byte(`{"bugs":[]}`))
+		}
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	type slimBug struct {
+		ID     int    `json:"id"`
+		Status string `json:"status"`
+	}
+	var bugs []slimBug
+	if err := client.SearchInto(Query{IncludeFields: []string{"id", "status"}}, &bugs); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	expected := []slimBug{{ID: 1, Status: "NEW"}, {ID: 2, Status: "ASSIGNED"}, {ID: 3, Status: "POST"}}
+	if !reflect.DeepEqual(bugs, expected) {
+		t.Errorf("got incorrect bugs: %v", diff.ObjectReflectDiff(bugs, expected))
+	}
+
+	if err := client.SearchInto(Query{IncludeFields: []string{"id", "status"}}, bugs); err == nil {
+		t.Error("expected an error for a destination which is not a pointer, but got none")
+	}
+}
+
 func TestIdentifierForPull(t *testing.T) {
 	var testCases = []struct {
 		name      string
@@ -389,6 +783,132 @@ func TestPullFromIdentifier(t *testing.T) {
 	}
 }
 
+func TestJiraIssueFromIdentifier(t *testing.T) {
+	var testCases = []struct {
+		name            string
+		identifier      string
+		expectedProject string
+		expectedNum     int
+		expectedErr     bool
+	}{
+		{
+			name:            "normal works as expected",
+			identifier:      "OCPBUGS-1234",
+			expectedProject: "OCPBUGS",
+			expectedNum:     1234,
+		},
+		{
+			name:        "pull identifier fails",
+			identifier:  "organization/repository/pull/1234",
+			expectedErr: true,
+		},
+		{
+			name:        "lower case project fails",
+			identifier:  "ocpbugs-1234",
+			expectedErr: true,
+		},
+		{
+			name:        "missing number fails",
+			identifier:  "OCPBUGS-",
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		project, num, err := JiraIssueFromIdentifier(testCase.identifier)
+		if testCase.expectedErr != (err != nil) {
+			t.Errorf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+		}
+		if project != testCase.expectedProject || num != testCase.expectedNum {
+			t.Errorf("%s: expected %s %d, got %s %d", testCase.name, testCase.expectedProject, testCase.expectedNum, project, num)
+		}
+		if err == nil {
+			if actual := IdentifierForJiraIssue(project, num); actual != testCase.identifier {
+				t.Errorf("%s: expected identifier %s, got %s", testCase.name, testCase.identifier, actual)
+			}
+		}
+	}
+}
+
+func TestAddJiraIssueAsExternalBug(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read request body: %v", err)
+		}
+		expected := `{"jsonrpc":"1.0","method":"ExternalBugs.add_external_bug","params":[{"api_key":"api-key","bug_ids":[1705243],"external_bugs":[{"ext_type_url":"https://issues.redhat.com/","ext_bz_bug_id":"OCPBUGS-1234"}]}],"id":"identifier"}`
+		if actual := string(raw); actual != expected {
+			t.Errorf("got incorrect JSONRPC payload: %v", diff.ObjectReflectDiff(expected, actual))
+		}
+		w.Write([]byte(`{"error":null,"id":"identifier","result":{"bugs":[{"alias":[],"changes":{"ext_bz_bug_map.ext_bz_bug_id":{"added":"Red Hat Issue Tracker OCPBUGS-1234","removed":""}},"id":1705243}]}}`))
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	changed, err := client.AddJiraIssueAsExternalBug(1705243, "OCPBUGS", 1234)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if !changed {
+		t.Error("expected the issue to be added, but it was not")
+	}
+}
+
+func TestFakeAddJiraIssueAsExternalBug(t *testing.T) {
+	fake := &Fake{Bugs: map[int]Bug{1: {ID: 1}}}
+	for i, expected := range []bool{true, false} {
+		changed, err := fake.AddJiraIssueAsExternalBug(1, "OCPBUGS", 1234)
+		if err != nil {
+			t.Fatalf("expected no error, but got one: %v", err)
+		}
+		if changed != expected {
+			t.Errorf("call %d: expected changed %v, got %v", i, expected, changed)
+		}
+	}
+	if _, err := fake.AddJiraIssueAsExternalBug(2, "OCPBUGS", 1234); !IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestUpdateExternalBugStatus(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read request body: %v", err)
+		}
+		expected := `{"jsonrpc":"1.0","method":"ExternalBugs.update_external_bug","params":[{"api_key":"api-key","bug_ids":[1705243],"ext_type_url":"https://github.com/","ext_bz_bug_id":"org/repo/pull/1","ext_status":"MERGED"}],"id":"identifier"}`
+		if actual := string(raw); actual != expected {
+			t.Errorf("got incorrect JSONRPC payload: %v", diff.ObjectReflectDiff(expected, actual))
+		}
+		w.Write([]byte(`{"error":null,"id":"identifier","result":[{"ext_bz_bug_id":"org/repo/pull/1","ext_status":"MERGED"}]}`))
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	identifier := ExternalBugIdentifier{Type: "https://github.com/", ID: IdentifierForPull("org", "repo", 1)}
+	if err := client.UpdateExternalBugStatus(1705243, identifier, "MERGED"); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+}
+
+func TestFakeUpdateExternalBugStatus(t *testing.T) {
+	fake := &Fake{Bugs: map[int]Bug{1: {ID: 1}}, ExternalBugs: map[int][]ExternalBug{}}
+	if _, err := fake.AddPullRequestAsExternalBug(1, "org", "repo", 1); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	identifier := ExternalBugIdentifier{Type: "https://github.com/", ID: IdentifierForPull("org", "repo", 1)}
+	if err := fake.UpdateExternalBugStatus(1, identifier, "MERGED"); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if status := fake.ExternalBugs[1][0].ExternalStatus; status != "MERGED" {
+		t.Errorf("expected the external bug to be MERGED, got %q", status)
+	}
+	identifier.ID = IdentifierForPull("org", "repo", 2)
+	if err := fake.UpdateExternalBugStatus(1, identifier, "MERGED"); !IsNotFound(err) {
+		t.Errorf("expected a not found error for an unlinked external bug, got %v", err)
+	}
+}
+
 func TestGetExternalBugPRsOnBug(t *testing.T) {
 	var testCases = []struct {
 		name          string
@@ -618,6 +1138,127 @@ func TestGetExternalBugs(t *testing.T) {
 	}
 }
 
+func TestGetExternalBugsForBugs(t *testing.T) {
+	var requested [][]string
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/rest/bug" {
+			t.Errorf("incorrect request to get bugs: %s %s", r.Method, r.URL.Path)
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+			return
+		}
+		if r.URL.Query().Get("include_fields") != "id,external_bugs" {
+			t.Errorf("did not get id and external bugs passed in include_fields query parameter")
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+			return
+		}
+		ids := r.URL.Query()["id"]
+		requested = append(requested, ids)
+		var bugs []string
+		for _, id := range ids {
+			switch id {
+			case "1":
+				bugs = append(bugs, `{"id":1,"external_bugs":[{"bug_id":1,"ext_bz_bug_id":"org/repo/pull/1","type":{"url":"https://github.com/"}},{"bug_id":3,"ext_bz_bug_id":"org/repo/pull/3","type":{"url":"https://github.com/"}}]}`)
+			case "2":
+				bugs = append(bugs, `{"id":2,"external_bugs":[]}`)
+			default:
+				bugs = append(bugs, fmt.Sprintf(`{"id":%s,"external_bugs":[{"bug_id":%s,"ext_bz_bug_id":"org/repo/pull/%s","type":{"url":"https://github.com/"}}]}`, id, id, id))
+			}
+		}
+		fmt.Fprintf(w, `{"bugs":[%s]}`, strings.Join(bugs, ","))
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	external, err := client.GetExternalBugsForBugs([]int{1, 2})
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	expected := map[int][]ExternalBug{1: {{Type: ExternalBugType{URL: "https://github.com/"}, BugzillaBugID: 1, ExternalBugID: "org/repo/pull/1"}}}
+	if !reflect.DeepEqual(external, expected) {
+		t.Errorf("got incorrect external bugs: %v", diff.ObjectReflectDiff(expected, external))
+	}
+	if expected := [][]string{{"1", "2"}}; !reflect.DeepEqual(requested, expected) {
+		t.Errorf("expected one request for both bugs, got %v", requested)
+	}
+
+	requested = nil
+	var ids []int
+	for id := 100; id < 100+externalBugsBatchSize+1; id++ {
+		ids = append(ids, id)
+	}
+	external, err = client.GetExternalBugsForBugs(ids)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if len(external) != len(ids) {
+		t.Errorf("expected external bugs for %d bugs, got %d", len(ids), len(external))
+	}
+	if len(requested) != 2 || len(requested[0]) != externalBugsBatchSize || len(requested[1]) != 1 {
+		t.Errorf("expected the bugs to be requested in two batches, got %d", len(requested))
+	}
+}
+
+func TestGetExternalBugPRsOnBugs(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("include_fields") != "id,external_bugs" {
+			t.Errorf("did not get id and external bugs passed in include_fields query parameter")
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"bugs":[`+
+			`{"id":1,"external_bugs":[{"bug_id":1,"ext_bz_bug_id":"org/repo/pull/1","type":{"url":"https://github.com/"}},{"bug_id":1,"ext_bz_bug_id":"other/repo/pull/2","type":{"url":"https://github.com/"}},{"bug_id":1,"ext_bz_bug_id":"OCPBUGS-1","type":{"url":"https://issues.redhat.com/"}}]},`+
+			`{"id":2,"external_bugs":[{"bug_id":2,"ext_bz_bug_id":"org/fork/pull/3","type":{"url":"https://github.com/"}}]},`+
+			`{"id":3,"external_bugs":[{"bug_id":3,"ext_bz_bug_id":"elsewhere/repo/pull/4","type":{"url":"https://github.com/"}}]}]}`)
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+	github := ExternalBugType{URL: "https://github.com/"}
+
+	var testCases = []struct {
+		name     string
+		allowed  []string
+		expected map[int][]ExternalBug
+	}{
+		{
+			name: "all pull requests are returned without allowed orgs or repos",
+			expected: map[int][]ExternalBug{
+				1: {
+					{Type: github, BugzillaBugID: 1, ExternalBugID: "org/repo/pull/1", Org: "org", Repo: "repo", Num: 1},
+					{Type: github, BugzillaBugID: 1, ExternalBugID: "other/repo/pull/2", Org: "other", Repo: "repo", Num: 2},
+				},
+				2: {{Type: github, BugzillaBugID: 2, ExternalBugID: "org/fork/pull/3", Org: "org", Repo: "fork", Num: 3}},
+				3: {{Type: github, BugzillaBugID: 3, ExternalBugID: "elsewhere/repo/pull/4", Org: "elsewhere", Repo: "repo", Num: 4}},
+			},
+		},
+		{
+			name:    "pull requests are filtered by org and by repo",
+			allowed: []string{"org/repo", "Other"},
+			expected: map[int][]ExternalBug{
+				1: {
+					{Type: github, BugzillaBugID: 1, ExternalBugID: "org/repo/pull/1", Org: "org", Repo: "repo", Num: 1},
+					{Type: github, BugzillaBugID: 1, ExternalBugID: "other/repo/pull/2", Org: "other", Repo: "repo", Num: 2},
+				},
+			},
+		},
+		{
+			name:     "bugs without allowed pull requests have no entry",
+			allowed:  []string{"nobody"},
+			expected: map[int][]ExternalBug{},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			prs, err := client.GetExternalBugPRsOnBugs([]int{1, 2, 3}, testCase.allowed...)
+			if err != nil {
+				t.Fatalf("%s: expected no error, but got one: %v", testCase.name, err)
+			}
+			if !reflect.DeepEqual(prs, testCase.expected) {
+				t.Errorf("%s: got incorrect pull requests: %v", testCase.name, diff.ObjectReflectDiff(testCase.expected, prs))
+			}
+		})
+	}
+}
+
 type authExpected struct {
 	bearer bool
 	query  bool
@@ -720,3 +1361,173 @@ func TestAuth(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateCommentTags(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("incorrect method to update comment tags: %s", r.Method)
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path != "/rest/bug/comment/42/tags" {
+			t.Errorf("incorrect path to update comment tags: %s", r.URL.Path)
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+			return
+		}
+		raw, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("could not read request body: %v", err)
+		}
+		if expected := `{"comment_id":42,"add":["spam"],"remove":["needinfo"]}`; string(raw) != expected {
+			t.Errorf("expected payload %s, got %s", expected, raw)
+		}
+		w.Write([]byte(`["abuse","spam"]`))
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	tags, err := client.UpdateCommentTags(42, []string{"spam"}, []string{"needinfo"})
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if expected := []string{"abuse", "spam"}; !reflect.DeepEqual(tags, expected) {
+		t.Errorf("expected tags %v, got %v", expected, tags)
+	}
+}
+
+func TestFakeUpdateCommentTags(t *testing.T) {
+	fake := &Fake{BugComments: map[int][]Comment{1: {{Id: 42, BugId: 1, Tags: []string{"needinfo"}}}}}
+	tags, err := fake.UpdateCommentTags(42, []string{"spam"}, []string{"needinfo"})
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if expected := []string{"spam"}; !reflect.DeepEqual(tags, expected) || !reflect.DeepEqual(fake.BugComments[1][0].Tags, expected) {
+		t.Errorf("expected tags %v, got %v", expected, fake.BugComments[1][0].Tags)
+	}
+	if _, err := fake.UpdateCommentTags(43, []string{"spam"}, nil); !IsNotFound(err) {
+		t.Errorf("expected a not found error for a missing comment, got %v", err)
+	}
+}
+
+func TestGetBugDescription(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/bug/1705243/comment":
+			if actual, expected := r.URL.Query().Get("include_fields"), "id,count"; actual != expected {
+				t.Errorf("got incorrect include_fields: expected %q, got %q", expected, actual)
+			}
+			w.Write([]byte(`{"bugs":{"1705243":{"comments":[{"id":11,"count":1},{"id":10,"count":0},{"id":12,"count":2}]}}}`))
+		case "/rest/bug/comment/10":
+			w.Write([]byte(`{"comments":{"10":{"id":10,"bug_id":1705243,"count":0,"text":"Description of problem"}}}`))
+		case "/rest/bug/1/comment":
+			w.Write([]byte(`{"bugs":{"1":{"comments":[]}}}`))
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+			http.Error(w, "404 Not Found", http.StatusNotFound)
+		}
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	description, err := client.GetBugDescription(1705243)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if expected := "Description of problem"; description != expected {
+		t.Errorf("expected description %q, got %q", expected, description)
+	}
+	if _, err := client.GetBugDescription(1); err == nil {
+		t.Error("expected an error for a bug without comments, got none")
+	}
+
+	fake := &Fake{Bugs: map[int]Bug{1: {ID: 1}}, BugComments: map[int][]Comment{1: {{Count: 1, Text: "comment"}, {Count: 0, Text: "description"}}}}
+	if description, err := fake.GetBugDescription(1); err != nil || description != "description" {
+		t.Errorf("expected the fake to return the description, got %q and %v", description, err)
+	}
+}
+
+func TestCreateAttachment(t *testing.T) {
+	for _, ids := range []string{`[7]`, `["7"]`} {
+		testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/rest/bug/1705243/attachment" {
+				t.Errorf("incorrect request to create attachment: %s %s", r.Method, r.URL.Path)
+				http.Error(w, "400 Bad Request", http.StatusBadRequest)
+				return
+			}
+			raw, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				t.Errorf("failed to read request body: %v", err)
+			}
+			expected := `{"ids":[1705243],"file_name":"build.log","summary":"Build log","content_type":"text/plain","data":"bG9n","comment":"Failed again."}`
+			if actual := string(raw); actual != expected {
+				t.Errorf("got incorrect payload: %v", diff.ObjectReflectDiff(expected, actual))
+			}
+			fmt.Fprintf(w, `{"ids":%s}`, ids)
+		}))
+		client := clientForUrl(testServer.URL)
+
+		id, err := client.CreateAttachment(1705243, AttachmentCreate{FileName: "build.log", Summary: "Build log", ContentType: "text/plain", Data: []byte("log"), Comment: "Failed again."})
+		if err != nil {
+			t.Fatalf("%s: expected no error, but got one: %v", ids, err)
+		}
+		if id != 7 {
+			t.Errorf("%s: expected attachment 7, got %d", ids, id)
+		}
+		testServer.Close()
+	}
+}
+
+func TestFakeCreateAttachment(t *testing.T) {
+	fake := &Fake{Bugs: map[int]Bug{1: {ID: 1}}, AttachmentData: map[int][]byte{3: []byte("old")}}
+	id, err := fake.CreateAttachment(1, AttachmentCreate{FileName: "build.log", Data: []byte("log"), Comment: "Failed again."})
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if id != 4 {
+		t.Errorf("expected attachment 4, got %d", id)
+	}
+	if data, err := fake.GetAttachmentData(id); err != nil || string(data) != "log" {
+		t.Errorf("expected the attachment data to be registered, got %q, %v", data, err)
+	}
+	if comments := fake.BugComments[1]; len(comments) != 1 || comments[0].Text != "Failed again." {
+		t.Errorf("expected the comment to be added, got %v", comments)
+	}
+	if _, err := fake.CreateAttachment(2, AttachmentCreate{}); !IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestGetAttachmentData(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("incorrect method to get attachment data: %s", r.Method)
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+			return
+		}
+		if r.URL.Query().Get("include_fields") != "data" {
+			t.Errorf("expected only the data to be requested, got %q", r.URL.Query().Get("include_fields"))
+		}
+		switch r.URL.Path {
+		case "/rest/bug/attachment/7":
+			w.Write([]byte(`{"attachments":{"7":{"data":"bXVzdC1nYXRoZXI="}},"bugs":{}}`))
+		case "/rest/bug/attachment/8":
+			w.Write([]byte(`{"attachments":{},"bugs":{}}`))
+		default:
+			t.Errorf("incorrect path to get attachment data: %s", r.URL.Path)
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+		}
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	data, err := client.GetAttachmentData(7)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if string(data) != "must-gather" {
+		t.Errorf("expected the decoded data, got %q", data)
+	}
+	if _, err := client.GetAttachmentData(8); !IsNotFound(err) {
+		t.Errorf("expected a not found error for a missing attachment, got %v", err)
+	}
+}
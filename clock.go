@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import "time"
+
+// Clock tells the time and waits, so tests can control how time passes for
+// the client instead of waiting for retries, back-offs and cool-downs.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// Sleep waits for the duration.
+	Sleep(d time.Duration)
+}
+
+// WithClock makes the client use the clock for retry back-offs, the circuit
+// breaker, rate limiting, the product schema cache and reloading API keys
+// from secret files. It must come before the options which start measuring
+// time when they are applied, like WithWarmUp.
+func WithClock(clock Clock) Option {
+	return func(c *client) {
+		c.clock = clock
+	}
+}
+
+// realClock is the Clock of the system
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// timeSource returns the clock of the client, the system clock if none is set
+func (c *client) timeSource() Clock {
+	if c.clock == nil {
+		return realClock{}
+	}
+	return c.clock
+}
+
+// now returns the current time of the clock of the client
+func (c *client) now() time.Time {
+	return c.timeSource().Now()
+}
+
+// sleep waits for the duration on the clock of the client
+func (c *client) sleep(d time.Duration) {
+	c.timeSource().Sleep(d)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeClock advances its time by the durations it sleeps for and records them
+type fakeClock struct {
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+}
+
+func TestWithClock(t *testing.T) {
+	failures := 2
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failures > 0 {
+			failures--
+			http.Error(w, "503 Service Unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(bugData)
+	}))
+	defer testServer.Close()
+	clock := &fakeClock{now: time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)}
+	c := clientForUrl(testServer.URL).(*client)
+	for _, opt := range []Option{WithClock(clock), WithRetries(2, time.Hour), WithRateLimit(1, 1)} {
+		opt(c)
+	}
+
+	if _, err := c.GetBug(1705243); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if expected := []time.Duration{time.Hour, 2 * time.Hour}; !reflect.DeepEqual(clock.sleeps, expected) {
+		t.Errorf("expected the back-offs %v on the clock, got %v", expected, clock.sleeps)
+	}
+
+	clock.sleeps = nil
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetBug(1705243); err != nil {
+			t.Fatalf("expected no error, but got one: %v", err)
+		}
+	}
+	// the last retry took the token, so both requests wait for a new one
+	if expected := []time.Duration{time.Second, time.Second}; !reflect.DeepEqual(clock.sleeps, expected) {
+		t.Errorf("expected the rate limit to wait %v on the clock, got %v", expected, clock.sleeps)
+	}
+}
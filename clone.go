@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import "fmt"
+
+// CloneOption mutates the new bug before a clone is created
+type CloneOption func(clone *BugCreate)
+
+// CloneTargetRelease sets the target release of the clone
+func CloneTargetRelease(release string) CloneOption {
+	return func(clone *BugCreate) {
+		clone.TargetRelease = []string{release}
+	}
+}
+
+// CloneVersion sets the version of the clone
+func CloneVersion(version string) CloneOption {
+	return func(clone *BugCreate) {
+		clone.Version = version
+	}
+}
+
+// CloneBlocksOriginal links the clone as blocking the original bug, instead of
+// the default of the clone depending on the original bug
+func CloneBlocksOriginal() CloneOption {
+	return func(clone *BugCreate) {
+		clone.Blocks, clone.DependsOn = clone.DependsOn, clone.Blocks
+	}
+}
+
+// cloneBug creates a copy of the bug, for instance to backport a fix to a
+// previous z-stream release. The clone has the summary, description (first
+// comment), product, component, version, target release, priority, severity,
+// assignee and keywords of the bug, and depends on the bug. The mutations are
+// applied in order before the clone is created.
+func cloneBug(c Client, bug *Bug, mutations ...CloneOption) (int, error) {
+	comments, err := c.GetBugComments(bug.ID)
+	if err != nil {
+		return 0, err
+	}
+	var description string
+	for _, comment := range comments {
+		if comment.Count == 0 {
+			description = comment.Text
+			break
+		}
+	}
+	clone := BugCreate{
+		Product:         bug.Product,
+		Summary:         bug.Summary,
+		Description:     description,
+		OperatingSystem: bug.OperatingSystem,
+		Platform:        bug.Platform,
+		Priority:        bug.Priority,
+		Severity:        bug.Severity,
+		AssignedTo:      bug.AssignedTo,
+		QAContact:       bug.QAContact,
+		TargetRelease:   bug.TargetRelease,
+		Keywords:        bug.Keywords,
+		Groups:          bug.Groups,
+		DependsOn:       []int{bug.ID},
+	}
+	if len(bug.Component) > 0 {
+		clone.Component = bug.Component[0]
+	}
+	if len(bug.Version) > 0 {
+		clone.Version = bug.Version[0]
+	}
+	for _, mutate := range mutations {
+		mutate(&clone)
+	}
+	id, err := c.CreateBug(clone)
+	if err != nil {
+		return 0, fmt.Errorf("could not create clone of bug %d: %v", bug.ID, err)
+	}
+	return id, nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestCloneBug(t *testing.T) {
+	original := Bug{
+		ID:            1,
+		Product:       "OpenShift Container Platform",
+		Component:     []string{"Networking"},
+		Version:       []string{"4.5"},
+		TargetRelease: []string{"4.5.0"},
+		Summary:       "pods can not talk",
+		Severity:      "high",
+		Status:        "MODIFIED",
+	}
+	var testCases = []struct {
+		name      string
+		mutations []CloneOption
+		expected  Bug
+	}{
+		{
+			name: "clone copies the bug and depends on it",
+			expected: Bug{
+				Product:       "OpenShift Container Platform",
+				Component:     []string{"Networking"},
+				Version:       []string{"4.5"},
+				TargetRelease: []string{"4.5.0"},
+				Summary:       "pods can not talk",
+				Severity:      "high",
+				DependsOn:     []int{1},
+				IsOpen:        true,
+			},
+		},
+		{
+			name:      "mutations retarget the clone and link it the other way",
+			mutations: []CloneOption{CloneTargetRelease("4.4.z"), CloneVersion("4.4"), CloneBlocksOriginal()},
+			expected: Bug{
+				Product:       "OpenShift Container Platform",
+				Component:     []string{"Networking"},
+				Version:       []string{"4.4"},
+				TargetRelease: []string{"4.4.z"},
+				Summary:       "pods can not talk",
+				Severity:      "high",
+				Blocks:        []int{1},
+				IsOpen:        true,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fake := &Fake{
+				Bugs:        map[int]Bug{1: original},
+				BugComments: map[int][]Comment{1: {{BugId: 1, Count: 0, Text: "description"}, {BugId: 1, Count: 1, Text: "comment"}}},
+			}
+			id, err := fake.CloneBug(&original, testCase.mutations...)
+			if err != nil {
+				t.Fatalf("%s: expected no error, got %v", testCase.name, err)
+			}
+			clone := fake.Bugs[id]
+			testCase.expected.ID = id
+			if !reflect.DeepEqual(clone, testCase.expected) {
+				t.Errorf("%s: got incorrect clone: %v", testCase.name, diff.ObjectReflectDiff(testCase.expected, clone))
+			}
+			if actual := fake.BugComments[id][0].Text; actual != "description" {
+				t.Errorf("%s: expected the description to be copied, got %q", testCase.name, actual)
+			}
+		})
+	}
+}
+
+func TestFakeCloneBugWithoutComments(t *testing.T) {
+	fake := &Fake{Bugs: map[int]Bug{1: {ID: 1, Product: "OpenShift Container Platform", Summary: "broken"}}}
+	id, err := fake.CloneBug(&Bug{ID: 1, Product: "OpenShift Container Platform", Summary: "broken"})
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if bug, exists := fake.Bugs[id]; !exists || bug.Summary != "broken" {
+		t.Errorf("expected the clone to be registered in the fake, got %v", fake.Bugs)
+	}
+	if len(fake.BugComments[id]) != 1 {
+		t.Errorf("expected the description of the clone to be registered in the fake, got %v", fake.BugComments)
+	}
+}
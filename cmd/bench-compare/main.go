@@ -0,0 +1,184 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// bench-compare compares the output of two runs of go test -bench and fails
+// if any benchmark regressed by more than a threshold, to gate changes made
+// for performance:
+//
+//	go test -run '^$' -bench . -benchmem -count 5 > old.txt # on the base
+//	go test -run '^$' -bench . -benchmem -count 5 > new.txt # with the change
+//	go run ./cmd/bench-compare --threshold 0.1 old.txt new.txt
+//
+// Runs with a -count above one are summarized by their median. Benchmarks
+// which are only in one of the runs are reported but never fail the gate.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// results holds the measurements of every benchmark, by name and unit
+type results map[string]map[string][]float64
+
+// higherIsBetter are the units for which a higher value is an improvement
+var higherIsBetter = map[string]bool{
+	"MB/s": true,
+}
+
+func main() {
+	threshold := flag.Float64("threshold", 0.1, "Relative change beyond which a benchmark regressed, e.g. 0.1 for 10%.")
+	flag.Parse()
+	if flag.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "usage: bench-compare [--threshold 0.1] old.txt new.txt")
+		os.Exit(2)
+	}
+	old, err := parseFile(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	current, err := parseFile(flag.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if regressed := compare(os.Stdout, old, current, *threshold); len(regressed) > 0 {
+		fmt.Fprintf(os.Stderr, "%d benchmarks regressed by more than %.0f%%: %s\n", len(regressed), *threshold*100, strings.Join(regressed, ", "))
+		os.Exit(1)
+	}
+}
+
+func parseFile(path string) (results, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	parsed, err := parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s: %v", path, err)
+	}
+	return parsed, nil
+}
+
+// parse reads the benchmark lines of go test -bench output, like
+// BenchmarkSearch-8   100   12345 ns/op   678 B/op   9 allocs/op
+func parse(r io.Reader) (results, error) {
+	parsed := results{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") || len(fields)%2 != 0 {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue
+		}
+		name := trimProcs(fields[0])
+		if parsed[name] == nil {
+			parsed[name] = map[string][]float64{}
+		}
+		for i := 2; i < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q for %s: %v", fields[i], name, err)
+			}
+			parsed[name][fields[i+1]] = append(parsed[name][fields[i+1]], value)
+		}
+	}
+	return parsed, scanner.Err()
+}
+
+// trimProcs removes the GOMAXPROCS suffix from a benchmark name, so runs on
+// machines with a different number of CPUs can be compared
+func trimProcs(name string) string {
+	if i := strings.LastIndex(name, "-"); i != -1 {
+		if _, err := strconv.Atoi(name[i+1:]); err == nil {
+			return name[:i]
+		}
+	}
+	return name
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// compare writes the change of every measurement to out and returns the
+// benchmarks which regressed by more than the threshold
+func compare(out io.Writer, old, current results, threshold float64) []string {
+	var names []string
+	for name := range old {
+		names = append(names, name)
+	}
+	for name := range current {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var regressed []string
+	for _, name := range names {
+		oldUnits, newUnits := old[name], current[name]
+		if oldUnits == nil || newUnits == nil {
+			fmt.Fprintf(out, "%-40s only in one run\n", name)
+			continue
+		}
+		var units []string
+		for unit := range oldUnits {
+			if _, ok := newUnits[unit]; ok {
+				units = append(units, unit)
+			}
+		}
+		sort.Strings(units)
+		failed := false
+		for _, unit := range units {
+			oldValue, newValue := median(oldUnits[unit]), median(newUnits[unit])
+			change := 0.0
+			if oldValue != 0 {
+				change = (newValue - oldValue) / oldValue
+			}
+			worse := change > threshold
+			if higherIsBetter[unit] {
+				worse = -change > threshold
+			}
+			marker := ""
+			if worse {
+				marker = " REGRESSED"
+				failed = true
+			}
+			fmt.Fprintf(out, "%-40s %-10s %14.2f %14.2f %+8.2f%%%s\n", name, unit, oldValue, newValue, change*100, marker)
+		}
+		if failed {
+			regressed = append(regressed, name)
+		}
+	}
+	return regressed
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+const baseline = `goos: linux
+goarch: amd64
+pkg: github.com/eparis/bugzilla
+BenchmarkDecode-8   	     100	   1000 ns/op	  50.00 MB/s	  200 B/op	    10 allocs/op
+BenchmarkDecode-8   	     100	   1200 ns/op	  40.00 MB/s	  200 B/op	    10 allocs/op
+BenchmarkDecode-8   	     100	   1100 ns/op	  45.00 MB/s	  200 B/op	    10 allocs/op
+BenchmarkSearch-8   	      10	  50000 ns/op	 3000 B/op	    30 allocs/op
+BenchmarkRemoved-8  	      10	  50000 ns/op
+PASS
+ok  	github.com/eparis/bugzilla	1.234s
+`
+
+func TestParse(t *testing.T) {
+	parsed, err := parse(strings.NewReader(baseline))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := results{
+		"BenchmarkDecode": {
+			"ns/op":     {1000, 1200, 1100},
+			"MB/s":      {50, 40, 45},
+			"B/op":      {200, 200, 200},
+			"allocs/op": {10, 10, 10},
+		},
+		"BenchmarkSearch": {
+			"ns/op":     {50000},
+			"B/op":      {3000},
+			"allocs/op": {30},
+		},
+		"BenchmarkRemoved": {
+			"ns/op": {50000},
+		},
+	}
+	if !reflect.DeepEqual(parsed, expected) {
+		t.Errorf("got incorrect results: %v", diff.ObjectReflectDiff(expected, parsed))
+	}
+}
+
+func TestCompare(t *testing.T) {
+	old, err := parse(strings.NewReader(baseline))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var testCases = []struct {
+		name      string
+		current   string
+		threshold float64
+		expected  []string
+	}{
+		{
+			name: "within the threshold",
+			current: `BenchmarkDecode-4   100   1150 ns/op   44.00 MB/s   210 B/op   10 allocs/op
+BenchmarkSearch-4   10   52000 ns/op   3000 B/op   30 allocs/op`,
+			threshold: 0.1,
+		},
+		{
+			name: "slower",
+			current: `BenchmarkDecode-4   100   1150 ns/op   44.00 MB/s   210 B/op   10 allocs/op
+BenchmarkSearch-4   10   60000 ns/op   3000 B/op   30 allocs/op`,
+			threshold: 0.1,
+			expected:  []string{"BenchmarkSearch"},
+		},
+		{
+			name:      "lower throughput",
+			current:   `BenchmarkDecode-4   100   1100 ns/op   30.00 MB/s   200 B/op   10 allocs/op`,
+			threshold: 0.1,
+			expected:  []string{"BenchmarkDecode"},
+		},
+		{
+			name:      "more allocations",
+			current:   `BenchmarkDecode-4   100   1100 ns/op   45.00 MB/s   200 B/op   20 allocs/op`,
+			threshold: 0.1,
+			expected:  []string{"BenchmarkDecode"},
+		},
+		{
+			name:      "faster",
+			current:   `BenchmarkSearch-4   10   10000 ns/op   1000 B/op   3 allocs/op`,
+			threshold: 0.1,
+		},
+	}
+	for _, testCase := range testCases {
+		current, err := parse(strings.NewReader(testCase.current))
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", testCase.name, err)
+		}
+		if actual := compare(ioutil.Discard, old, current, testCase.threshold); !reflect.DeepEqual(actual, testCase.expected) {
+			t.Errorf("%s: got incorrect regressions: %v", testCase.name, diff.ObjectReflectDiff(testCase.expected, actual))
+		}
+	}
+}
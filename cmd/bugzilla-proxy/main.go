@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// bugzilla-proxy serves a minimal REST API in front of Bugzilla, so internal
+// tools can get, search, update and comment on bugs without each holding
+// Bugzilla credentials. Callers authenticate with one of the bearer tokens in
+// the tokens file, so the proxy serves TLS with the given certificate unless
+// it runs behind something terminating TLS for it. Bugs are cached for a
+// short time and all requests to Bugzilla share one rate limit.
+//
+//	GET  /bug/{id}           get a bug
+//	GET  /search?{query}     search for bugs, e.g. /search?product=OpenShift+Container+Platform&bug_status=NEW
+//	PUT  /bug/{id}           update a bug with a JSON BugUpdate
+//	POST /bug/{id}/comment   comment on a bug with a JSON body like {"body":"text","private":false}
+package main
+
+import (
+	"flag"
+	"net/http"
+	"time"
+
+	"github.com/eparis/bugzilla"
+	"github.com/eparis/bugzilla/logrusadapter"
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	address := flag.String("address", ":8080", "Address to serve on.")
+	endpoint := flag.String("endpoint", "https://bugzilla.redhat.com", "Bugzilla endpoint.")
+	apiKeyPath := flag.String("api-key-path", "", "Path to the file holding the Bugzilla API key.")
+	tokensPath := flag.String("tokens-path", "", "Path to the file holding the bearer tokens of the callers, one per line.")
+	cacheTTL := flag.Duration("cache-ttl", time.Minute, "How long bugs are cached.")
+	qps := flag.Float64("qps", 5, "Requests per second allowed to Bugzilla on average.")
+	burst := flag.Int("burst", 10, "Requests allowed to Bugzilla in a burst.")
+	certPath := flag.String("tls-cert-path", "", "Path to the TLS certificate to serve with.")
+	keyPath := flag.String("tls-key-path", "", "Path to the key of the TLS certificate.")
+	plaintext := flag.Bool("insecure-plaintext", false, "Serve without TLS, only for running behind something terminating TLS.")
+	flag.Parse()
+
+	if *apiKeyPath == "" || *tokensPath == "" {
+		logrus.Fatal("--api-key-path and --tokens-path are required")
+	}
+	if (*certPath == "" || *keyPath == "") != *plaintext {
+		logrus.Fatal("either --tls-cert-path and --tls-key-path or --insecure-plaintext are required")
+	}
+	tokens, err := loadTokens(*tokensPath)
+	if err != nil {
+		logrus.WithError(err).Fatal("Could not load tokens.")
+	}
+	client, err := bugzilla.NewClientFromSecretFile(*apiKeyPath, time.Minute, *endpoint,
+		bugzilla.WithRateLimit(*qps, *burst),
+		bugzilla.WithRetries(3, time.Second),
+		bugzilla.WithLogger(logrusadapter.New(logrus.NewEntry(logrus.StandardLogger()))),
+	)
+	if err != nil {
+		logrus.WithError(err).Fatal("Could not create Bugzilla client.")
+	}
+	httpServer := &http.Server{
+		Addr:              *address,
+		Handler:           newServer(client, tokens, *cacheTTL),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       time.Minute,
+		// requests to Bugzilla may wait for the rate limit and retries
+		WriteTimeout: 5 * time.Minute,
+		IdleTimeout:  2 * time.Minute,
+	}
+	logrus.WithField("address", *address).Info("Serving Bugzilla proxy.")
+	if *plaintext {
+		err = httpServer.ListenAndServe()
+	} else {
+		err = httpServer.ListenAndServeTLS(*certPath, *keyPath)
+	}
+	if err != nil {
+		logrus.WithError(err).Fatal("Could not serve.")
+	}
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eparis/bugzilla"
+	"github.com/sirupsen/logrus"
+)
+
+// loadTokens reads the bearer tokens of the callers, one per line
+func loadTokens(path string) ([][]byte, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read tokens: %v", err)
+	}
+	var tokens [][]byte
+	for _, line := range bytes.Split(raw, []byte("\n")) {
+		if token := bytes.TrimSpace(line); len(token) != 0 {
+			tokens = append(tokens, token)
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("no tokens in %s", path)
+	}
+	return tokens, nil
+}
+
+// server serves the simplified API using the Bugzilla client
+type server struct {
+	client bugzilla.Client
+	tokens [][]byte
+	cache  *bugCache
+	logger *logrus.Entry
+}
+
+func newServer(client bugzilla.Client, tokens [][]byte, cacheTTL time.Duration) *server {
+	return &server{
+		client: client,
+		tokens: tokens,
+		cache:  &bugCache{ttl: cacheTTL, bugs: map[int]cachedBug{}},
+		logger: logrus.WithField("component", "bugzilla-proxy"),
+	}
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authenticated(r) {
+		writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+		return
+	}
+	path := strings.Trim(r.URL.Path, "/")
+	parts := strings.Split(path, "/")
+	switch {
+	case path == "search" && r.Method == http.MethodGet:
+		s.search(w, r)
+	case len(parts) == 2 && parts[0] == "bug" && r.Method == http.MethodGet:
+		s.withID(w, parts[1], s.getBug)
+	case len(parts) == 2 && parts[0] == "bug" && r.Method == http.MethodPut:
+		s.withID(w, parts[1], func(w http.ResponseWriter, id int) { s.updateBug(w, r, id) })
+	case len(parts) == 3 && parts[0] == "bug" && parts[2] == "comment" && r.Method == http.MethodPost:
+		s.withID(w, parts[1], func(w http.ResponseWriter, id int) { s.comment(w, r, id) })
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("no such operation: %s %s", r.Method, r.URL.Path))
+	}
+}
+
+func (s *server) authenticated(r *http.Request) bool {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return false
+	}
+	given := []byte(strings.TrimPrefix(header, "Bearer "))
+	for _, token := range s.tokens {
+		if subtle.ConstantTimeCompare(given, token) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *server) withID(w http.ResponseWriter, rawID string, handle func(w http.ResponseWriter, id int)) {
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid bug ID %q", rawID))
+		return
+	}
+	handle(w, id)
+}
+
+func (s *server) getBug(w http.ResponseWriter, id int) {
+	if bug, ok := s.cache.get(id); ok {
+		writeJSON(w, bug)
+		return
+	}
+	bug, err := s.client.GetBug(id)
+	if err != nil {
+		s.writeClientError(w, err)
+		return
+	}
+	s.cache.put(bug)
+	writeJSON(w, bug)
+}
+
+func (s *server) search(w http.ResponseWriter, r *http.Request) {
+	if r.URL.RawQuery == "" {
+		writeError(w, http.StatusBadRequest, errors.New("a search query is required"))
+		return
+	}
+	bugs, err := s.client.Search(bugzilla.Query{Raw: r.URL.RawQuery})
+	if err != nil {
+		s.writeClientError(w, err)
+		return
+	}
+	writeJSON(w, bugs)
+}
+
+func (s *server) updateBug(w http.ResponseWriter, r *http.Request, id int) {
+	var update bugzilla.BugUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid update: %v", err))
+		return
+	}
+	s.update(w, id, update)
+}
+
+func (s *server) comment(w http.ResponseWriter, r *http.Request, id int) {
+	var comment struct {
+		Body    string `json:"body"`
+		Private bool   `json:"private"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&comment); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid comment: %v", err))
+		return
+	}
+	if comment.Body == "" {
+		writeError(w, http.StatusBadRequest, errors.New("the comment body is required"))
+		return
+	}
+	s.update(w, id, bugzilla.BugUpdate{Comment: &bugzilla.BugComment{Body: comment.Body, Private: comment.Private}})
+}
+
+// update updates the bug and drops it from the cache afterwards, whether the
+// update failed or not, as failed updates may have been applied and the bug
+// may have been cached again while it was updated
+func (s *server) update(w http.ResponseWriter, id int, update bugzilla.BugUpdate) {
+	defer s.cache.invalidate(id)
+	if err := s.client.UpdateBug(id, update); err != nil {
+		s.writeClientError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeClientError responds with the status matching an error from the client.
+// Errors which are not about the requested bug are reported as a bad gateway.
+func (s *server) writeClientError(w http.ResponseWriter, err error) {
+	switch {
+	case bugzilla.IsNotFound(err), bugzilla.IsInvalidBug(err):
+		writeError(w, http.StatusNotFound, err)
+	case bugzilla.IsForbidden(err):
+		writeError(w, http.StatusForbidden, err)
+	case bugzilla.IsRateLimited(err):
+		writeError(w, http.StatusTooManyRequests, err)
+	default:
+		s.logger.WithError(err).Warn("Request to Bugzilla failed.")
+		writeError(w, http.StatusBadGateway, err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		logrus.WithError(err).Warn("Could not write response.")
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{Error: err.Error()})
+}
+
+// bugCache holds bugs for a limited time
+type bugCache struct {
+	ttl  time.Duration
+	lock sync.Mutex
+	bugs map[int]cachedBug
+}
+
+type cachedBug struct {
+	bug     *bugzilla.Bug
+	expires time.Time
+}
+
+func (c *bugCache) get(id int) (*bugzilla.Bug, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	cached, ok := c.bugs[id]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(cached.expires) {
+		delete(c.bugs, id)
+		return nil, false
+	}
+	return cached.bug, true
+}
+
+func (c *bugCache) put(bug *bugzilla.Bug) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.bugs[bug.ID] = cachedBug{bug: bug, expires: time.Now().Add(c.ttl)}
+}
+
+func (c *bugCache) invalidate(id int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.bugs, id)
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eparis/bugzilla"
+)
+
+// countingClient counts the bugs retrieved from the wrapped client
+type countingClient struct {
+	bugzilla.Client
+	gets int
+}
+
+func (c *countingClient) GetBug(id int) (*bugzilla.Bug, error) {
+	c.gets++
+	return c.Client.GetBug(id)
+}
+
+func TestServer(t *testing.T) {
+	fake := &bugzilla.Fake{
+		Bugs:        map[int]bugzilla.Bug{1: {ID: 1, Summary: "flake", Status: "NEW"}},
+		BugComments: map[int][]bugzilla.Comment{},
+	}
+	client := &countingClient{Client: fake}
+	s := newServer(client, [][]byte{[]byte("secret")}, time.Hour)
+
+	var testCases = []struct {
+		name           string
+		method         string
+		path           string
+		token          string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "missing token",
+			method:         http.MethodGet,
+			path:           "/bug/1",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid token",
+			method:         http.MethodGet,
+			path:           "/bug/1",
+			token:          "guess",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "get bug",
+			method:         http.MethodGet,
+			path:           "/bug/1",
+			token:          "secret",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"summary":"flake"`,
+		},
+		{
+			name:           "get bug from the cache",
+			method:         http.MethodGet,
+			path:           "/bug/1",
+			token:          "secret",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"status":"NEW"`,
+		},
+		{
+			name:           "missing bug",
+			method:         http.MethodGet,
+			path:           "/bug/2",
+			token:          "secret",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid bug ID",
+			method:         http.MethodGet,
+			path:           "/bug/one",
+			token:          "secret",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "update bug",
+			method:         http.MethodPut,
+			path:           "/bug/1",
+			token:          "secret",
+			body:           `{"status":"ASSIGNED","assigned_to":"dev@example.com"}`,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "updated bug is not served from the cache",
+			method:         http.MethodGet,
+			path:           "/bug/1",
+			token:          "secret",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"status":"ASSIGNED"`,
+		},
+		{
+			name:           "empty comment",
+			method:         http.MethodPost,
+			path:           "/bug/1/comment",
+			token:          "secret",
+			body:           `{"body":""}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "search",
+			method:         http.MethodGet,
+			path:           "/search?product=OpenShift+Container+Platform",
+			token:          "secret",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"id":1`,
+		},
+		{
+			name:           "unknown operation",
+			method:         http.MethodDelete,
+			path:           "/bug/1",
+			token:          "secret",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+	for _, testCase := range testCases {
+		req := httptest.NewRequest(testCase.method, testCase.path, strings.NewReader(testCase.body))
+		if testCase.token != "" {
+			req.Header.Set("Authorization", "Bearer "+testCase.token)
+		}
+		recorder := httptest.NewRecorder()
+		s.ServeHTTP(recorder, req)
+		if recorder.Code != testCase.expectedStatus {
+			t.Errorf("%s: expected status %d, got %d: %s", testCase.name, testCase.expectedStatus, recorder.Code, recorder.Body.String())
+		}
+		if !strings.Contains(recorder.Body.String(), testCase.expectedBody) {
+			t.Errorf("%s: expected body to contain %q, got %s", testCase.name, testCase.expectedBody, recorder.Body.String())
+		}
+		if recorder.Code >= http.StatusBadRequest {
+			var response struct {
+				Error string `json:"error"`
+			}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil || response.Error == "" {
+				t.Errorf("%s: expected an error response, got %s", testCase.name, recorder.Body.String())
+			}
+		}
+	}
+	if client.gets != 3 {
+		t.Errorf("expected 3 bugs to be retrieved from Bugzilla, got %d", client.gets)
+	}
+}
+
+// racingClient runs the hook before every update, like a concurrent request
+// would while the update is in flight
+type racingClient struct {
+	bugzilla.Client
+	beforeUpdate func()
+}
+
+func (c *racingClient) UpdateBug(id int, update bugzilla.BugUpdate) error {
+	c.beforeUpdate()
+	return c.Client.UpdateBug(id, update)
+}
+
+func TestUpdateDropsBugsCachedDuringTheUpdate(t *testing.T) {
+	fake := &bugzilla.Fake{Bugs: map[int]bugzilla.Bug{1: {ID: 1, Status: "NEW"}}}
+	client := &racingClient{Client: fake}
+	s := newServer(client, [][]byte{[]byte("secret")}, time.Hour)
+	get := func() string {
+		req := httptest.NewRequest(http.MethodGet, "/bug/1", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		recorder := httptest.NewRecorder()
+		s.ServeHTTP(recorder, req)
+		return recorder.Body.String()
+	}
+	client.beforeUpdate = func() { get() }
+
+	req := httptest.NewRequest(http.MethodPut, "/bug/1", strings.NewReader(`{"status":"ASSIGNED"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	recorder := httptest.NewRecorder()
+	s.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusNoContent {
+		t.Fatalf("expected the update to succeed, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if body := get(); !strings.Contains(body, `"status":"ASSIGNED"`) {
+		t.Errorf("expected the updated bug, got %s", body)
+	}
+}
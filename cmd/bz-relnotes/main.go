@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// bz-relnotes renders the release notes of a release from the bugs which were
+// shipped in it, i.e. which are CLOSED ERRATA with the release as target:
+//
+//	bz-relnotes --api-key-path /etc/bugzilla/api-key --product 'OpenShift Container Platform' \
+//		--target-release 4.6.0 --format asciidoc > release-notes.adoc
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/eparis/bugzilla"
+	"github.com/eparis/bugzilla/relnotes"
+)
+
+func main() {
+	endpoint := flag.String("endpoint", "https://bugzilla.redhat.com", "Bugzilla endpoint.")
+	apiKeyPath := flag.String("api-key-path", "", "Path to the file holding the Bugzilla API key.")
+	product := flag.String("product", "", "Product of the release.")
+	targetRelease := flag.String("target-release", "", "Target release of the bugs shipped in the release, e.g. 4.6.0.")
+	format := flag.String("format", string(relnotes.Markdown), "Format of the release notes, markdown or asciidoc.")
+	title := flag.String("title", "", "Title of the release notes, the product and target release by default.")
+	flag.Parse()
+
+	if *apiKeyPath == "" || *product == "" || *targetRelease == "" {
+		fmt.Fprintln(os.Stderr, "--api-key-path, --product and --target-release are required")
+		os.Exit(1)
+	}
+	if *title == "" {
+		*title = fmt.Sprintf("%s %s", *product, *targetRelease)
+	}
+	client, err := bugzilla.NewClientFromSecretFile(*apiKeyPath, time.Minute, *endpoint)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	notes, err := relnotes.Collect(client, relnotes.Query(*product, *targetRelease))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := relnotes.Render(os.Stdout, relnotes.Format(*format), *title, *endpoint, notes); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
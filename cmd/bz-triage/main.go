@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// bz-triage is a terminal UI to triage the bugs matching a query. It lists
+// the bugs and reads single-letter commands to move between them and to
+// assign, retarget, comment on or close the current bug:
+//
+//	bz-triage --endpoint https://bugzilla.redhat.com --api-key-path /etc/bugzilla/api-key \
+//		--query 'product=OpenShift Container Platform&bug_status=NEW'
+//
+// Updates which fail because Bugzilla can not be reached are queued on disk
+// and sent again with the flush command or on the next start.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/eparis/bugzilla"
+)
+
+func main() {
+	endpoint := flag.String("endpoint", "https://bugzilla.redhat.com", "Bugzilla endpoint.")
+	apiKeyPath := flag.String("api-key-path", "", "Path to the file holding the Bugzilla API key.")
+	query := flag.String("query", "", "Search query in URL query format, e.g. 'product=OpenShift Container Platform&bug_status=NEW'.")
+	queuePath := flag.String("queue", defaultQueuePath(), "Path to the file holding the queued updates.")
+	flag.Parse()
+
+	if *apiKeyPath == "" || *query == "" {
+		fmt.Fprintln(os.Stderr, "--api-key-path and --query are required")
+		os.Exit(1)
+	}
+	client, err := bugzilla.NewClientFromSecretFile(*apiKeyPath, time.Minute, *endpoint)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	queue, err := loadQueue(*queuePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	t := &triager{
+		client: client,
+		query: bugzilla.Query{
+			Raw:           *query,
+			IncludeFields: []string{"id", "summary", "status", "resolution", "assigned_to", "target_release", "component", "severity"},
+		},
+		queue: queue,
+		in:    os.Stdin,
+		out:   os.Stdout,
+	}
+	if err := t.run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func defaultQueuePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".bz-triage-queue.json"
+	}
+	return filepath.Join(home, ".bz-triage-queue.json")
+}
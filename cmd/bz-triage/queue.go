@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/eparis/bugzilla"
+)
+
+// action is an update to a bug which is queued while Bugzilla can not be reached
+type action struct {
+	BugID       int                `json:"bug_id"`
+	Description string             `json:"description"`
+	Update      bugzilla.BugUpdate `json:"update"`
+}
+
+// queue holds the actions which still need to be sent, persisted to a file
+// so they survive a restart
+type queue struct {
+	path    string
+	actions []action
+}
+
+func loadQueue(path string) (*queue, error) {
+	q := &queue{path: path}
+	raw, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read queue: %v", err)
+	}
+	if err := json.Unmarshal(raw, &q.actions); err != nil {
+		return nil, fmt.Errorf("could not parse queue %s: %v", path, err)
+	}
+	return q, nil
+}
+
+func (q *queue) add(a action) error {
+	q.actions = append(q.actions, a)
+	return q.save()
+}
+
+func (q *queue) save() error {
+	if len(q.actions) == 0 {
+		if err := os.Remove(q.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("could not remove queue: %v", err)
+		}
+		return nil
+	}
+	raw, err := json.MarshalIndent(q.actions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal queue: %v", err)
+	}
+	if err := ioutil.WriteFile(q.path, raw, 0600); err != nil {
+		return fmt.Errorf("could not write queue: %v", err)
+	}
+	return nil
+}
+
+// flush sends the queued actions in order. It stops at the first action which
+// fails because Bugzilla can not be reached and keeps it and all later actions
+// queued. Actions which Bugzilla rejected are dropped and returned as failed.
+func (q *queue) flush(client bugzilla.Client) (sent int, failed []error, err error) {
+	for len(q.actions) > 0 {
+		a := q.actions[0]
+		if updateErr := client.UpdateBug(a.BugID, a.Update); updateErr != nil {
+			if offline(updateErr) {
+				return sent, failed, q.save()
+			}
+			failed = append(failed, fmt.Errorf("bug %d: %s: %v", a.BugID, a.Description, updateErr))
+		} else {
+			sent++
+		}
+		q.actions = q.actions[1:]
+	}
+	return sent, failed, q.save()
+}
+
+// offline determines whether the error means Bugzilla could not be reached or
+// could not handle the request right now, so sending it later may succeed
+func offline(err error) bool {
+	if bugzilla.IsRetryExhausted(err) {
+		return true
+	}
+	var reqError *bugzilla.RequestError
+	if !errors.As(err, &reqError) {
+		return false
+	}
+	return reqError.StatusCode == -1 || reqError.StatusCode == http.StatusTooManyRequests || reqError.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/eparis/bugzilla"
+)
+
+const help = `Commands:
+  n                  next bug
+  p                  previous bug
+  g <index>          go to the bug with the index in the list
+  l                  list the bugs
+  a <user>           assign the bug to the user
+  t <release>        retarget the bug to the release
+  c <text>           comment on the bug
+  x <resolution>     close the bug with the resolution
+  f                  send the queued updates
+  r                  reload the bugs from the query
+  ?                  show this help
+  q                  quit
+`
+
+// triager runs the triage session, reading commands from in and writing the
+// bugs and results to out
+type triager struct {
+	client bugzilla.Client
+	query  bugzilla.Query
+	queue  *queue
+	in     io.Reader
+	out    io.Writer
+
+	bugs    []*bugzilla.Bug
+	current int
+}
+
+func (t *triager) run() error {
+	if len(t.queue.actions) > 0 {
+		t.flush()
+	}
+	if err := t.reload(); err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(t.in)
+	for {
+		t.show()
+		fmt.Fprint(t.out, "bz-triage> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(t.out)
+			return scanner.Err()
+		}
+		command, argument := parseCommand(scanner.Text())
+		switch command {
+		case "":
+		case "n":
+			t.move(t.current + 1)
+		case "p":
+			t.move(t.current - 1)
+		case "g":
+			var index int
+			if _, err := fmt.Sscan(argument, &index); err != nil {
+				fmt.Fprintf(t.out, "invalid index %q\n", argument)
+				continue
+			}
+			t.move(index)
+		case "l":
+			t.list()
+		case "a":
+			t.update(argument, "assign to "+argument, bugzilla.BugUpdate{AssignedTo: argument, Status: "ASSIGNED"})
+		case "t":
+			t.update(argument, "retarget to "+argument, bugzilla.BugUpdate{TargetRelease: argument})
+		case "c":
+			t.update(argument, "comment", bugzilla.BugUpdate{Comment: &bugzilla.BugComment{Body: argument}})
+		case "x":
+			t.update(argument, "close as "+argument, bugzilla.BugUpdate{Status: "CLOSED", Resolution: argument})
+		case "f":
+			t.flush()
+		case "r":
+			if err := t.reload(); err != nil {
+				fmt.Fprintf(t.out, "could not reload bugs: %v\n", err)
+			}
+		case "?":
+			fmt.Fprint(t.out, help)
+		case "q":
+			return nil
+		default:
+			fmt.Fprintf(t.out, "unknown command %q, use ? for help\n", command)
+		}
+	}
+}
+
+func parseCommand(line string) (string, string) {
+	line = strings.TrimSpace(line)
+	parts := strings.SplitN(line, " ", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], strings.TrimSpace(parts[1])
+}
+
+func (t *triager) reload() error {
+	bugs, err := t.client.Search(t.query)
+	if err != nil {
+		return fmt.Errorf("could not search for bugs: %v", err)
+	}
+	t.bugs = bugs
+	t.current = 0
+	t.list()
+	return nil
+}
+
+func (t *triager) list() {
+	fmt.Fprintf(t.out, "%d bugs:\n", len(t.bugs))
+	for i, bug := range t.bugs {
+		marker := " "
+		if i == t.current {
+			marker = ">"
+		}
+		fmt.Fprintf(t.out, "%s %3d %8d %-16s %s\n", marker, i, bug.ID, bugzilla.PrettyStatus(bug.Status, bug.Resolution), bug.Summary)
+	}
+}
+
+func (t *triager) show() {
+	if len(t.bugs) == 0 {
+		return
+	}
+	bug := t.bugs[t.current]
+	fmt.Fprintf(t.out, "\n[%d/%d] Bug %d: %s\n", t.current+1, len(t.bugs), bug.ID, bug.Summary)
+	fmt.Fprintf(t.out, "  Status: %s  Severity: %s  Component: %s\n", bugzilla.PrettyStatus(bug.Status, bug.Resolution), bug.Severity, strings.Join(bug.Component, ","))
+	fmt.Fprintf(t.out, "  Assignee: %s  Target release: %s\n", bug.AssignedTo, strings.Join(bug.TargetRelease, ","))
+}
+
+func (t *triager) move(index int) {
+	if index < 0 || index >= len(t.bugs) {
+		fmt.Fprintln(t.out, "no such bug")
+		return
+	}
+	t.current = index
+}
+
+// update sends the update for the current bug, queueing it if Bugzilla can
+// not be reached
+func (t *triager) update(argument, description string, update bugzilla.BugUpdate) {
+	if len(t.bugs) == 0 {
+		fmt.Fprintln(t.out, "no bug selected")
+		return
+	}
+	if argument == "" {
+		fmt.Fprintln(t.out, "missing argument, use ? for help")
+		return
+	}
+	bug := t.bugs[t.current]
+	err := t.client.UpdateBug(bug.ID, update)
+	switch {
+	case err == nil:
+		fmt.Fprintf(t.out, "bug %d: %s\n", bug.ID, description)
+		applyLocally(bug, update)
+	case offline(err):
+		if queueErr := t.queue.add(action{BugID: bug.ID, Description: description, Update: update}); queueErr != nil {
+			fmt.Fprintf(t.out, "bug %d: could not %s: %v, and could not queue it: %v\n", bug.ID, description, err, queueErr)
+			return
+		}
+		fmt.Fprintf(t.out, "bug %d: Bugzilla is unavailable, queued %s (%d queued)\n", bug.ID, description, len(t.queue.actions))
+		applyLocally(bug, update)
+	default:
+		fmt.Fprintf(t.out, "bug %d: could not %s: %v\n", bug.ID, description, err)
+	}
+}
+
+func (t *triager) flush() {
+	sent, failed, err := t.queue.flush(t.client)
+	fmt.Fprintf(t.out, "sent %d queued updates, %d still queued\n", sent, len(t.queue.actions))
+	for _, failure := range failed {
+		fmt.Fprintf(t.out, "dropped rejected update for %v\n", failure)
+	}
+	if err != nil {
+		fmt.Fprintln(t.out, err)
+	}
+}
+
+// applyLocally reflects the update in the listed bug, so the list shows the
+// state the bug will have once the update is sent
+func applyLocally(bug *bugzilla.Bug, update bugzilla.BugUpdate) {
+	if update.AssignedTo != "" {
+		bug.AssignedTo = update.AssignedTo
+	}
+	if update.Status != "" {
+		bug.Status = update.Status
+		bug.Resolution = update.Resolution
+	}
+	if update.TargetRelease != "" {
+		bug.TargetRelease = []string{update.TargetRelease}
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eparis/bugzilla"
+)
+
+// flakyClient fails updates as if Bugzilla could not be reached while offline is set
+type flakyClient struct {
+	bugzilla.Client
+	offline bool
+}
+
+func (c *flakyClient) UpdateBug(id int, update bugzilla.BugUpdate) error {
+	if c.offline {
+		return &bugzilla.RequestError{StatusCode: -1, Message: "connection refused"}
+	}
+	return c.Client.UpdateBug(id, update)
+}
+
+func TestTriage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bz-triage")
+	if err != nil {
+		t.Fatalf("could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	queuePath := filepath.Join(dir, "queue.json")
+
+	fake := &bugzilla.Fake{Bugs: map[int]bugzilla.Bug{
+		1: {ID: 1, Summary: "first", Status: "NEW"},
+	}}
+	client := &flakyClient{Client: fake, offline: true}
+	q, err := loadQueue(queuePath)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	out := &bytes.Buffer{}
+	triager := &triager{
+		client: client,
+		queue:  q,
+		in:     strings.NewReader("a dev@example.com\nt 4.6.0\nx\nq\n"),
+		out:    out,
+	}
+	if err := triager.run(); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if !strings.Contains(out.String(), "missing argument") {
+		t.Errorf("expected closing without a resolution to be refused, got output:\n%s", out.String())
+	}
+	if fake.Bugs[1].AssignedTo != "" {
+		t.Errorf("expected no update to be sent while offline, got %#v", fake.Bugs[1])
+	}
+
+	// the queued updates are sent on the next start
+	q, err = loadQueue(queuePath)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if len(q.actions) != 2 {
+		t.Fatalf("expected 2 queued updates, got %d", len(q.actions))
+	}
+	client.offline = false
+	triager.queue = q
+	triager.in = strings.NewReader("x NOTABUG\nq\n")
+	if err := triager.run(); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	bug := fake.Bugs[1]
+	if bug.AssignedTo != "dev@example.com" || len(bug.TargetRelease) != 1 || bug.TargetRelease[0] != "4.6.0" || bug.Status != "CLOSED" || bug.Resolution != "NOTABUG" {
+		t.Errorf("expected the queued and new updates to be sent, got %#v", bug)
+	}
+	if _, err := os.Stat(queuePath); !os.IsNotExist(err) {
+		t.Errorf("expected the queue to be removed once flushed, got %v", err)
+	}
+}
+
+func TestFlushDropsRejectedUpdates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bz-triage")
+	if err != nil {
+		t.Fatalf("could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	fake := &bugzilla.Fake{Bugs: map[int]bugzilla.Bug{1: {ID: 1}}}
+	q := &queue{path: filepath.Join(dir, "queue.json"), actions: []action{
+		{BugID: 2, Description: "comment", Update: bugzilla.BugUpdate{Comment: &bugzilla.BugComment{Body: "hi"}}},
+		{BugID: 1, Description: "assign", Update: bugzilla.BugUpdate{AssignedTo: "dev@example.com"}},
+	}}
+	sent, failed, err := q.flush(fake)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if sent != 1 || len(failed) != 1 || len(q.actions) != 0 {
+		t.Errorf("expected one sent and one dropped update, got %d sent, %v failed, %d queued", sent, failed, len(q.actions))
+	}
+}
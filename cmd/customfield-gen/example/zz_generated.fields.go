@@ -0,0 +1,82 @@
+// Code generated by customfield-gen. DO NOT EDIT.
+
+package example
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/eparis/bugzilla"
+)
+
+// CustomFields holds the custom fields of a bug
+type CustomFields struct {
+	// ID is the ID of the bug the fields belong to
+	ID int `json:"id"`
+	// DocType is the type of documentation needed for the bug
+	DocType string `json:"cf_doc_type,omitempty"`
+	// PmScore is the score assigned by product management
+	PmScore int `json:"cf_pm_score,omitempty"`
+	// VerifiedBranches is the value of the cf_verified_branch custom field
+	VerifiedBranches []string `json:"cf_verified_branch,omitempty"`
+}
+
+// CustomFieldsNames are the names of the custom fields, for use as IncludeFields in a query
+var CustomFieldsNames = []string{"id", "cf_doc_type", "cf_pm_score", "cf_verified_branch"}
+
+// GetCustomFields retrieves the custom fields of the bug from the server
+func GetCustomFields(c bugzilla.Client, id int) (*CustomFields, error) {
+	var fields []CustomFields
+	query := bugzilla.Query{
+		BugIDs:        []string{strconv.Itoa(id)},
+		BugIDsType:    "anyexact",
+		IncludeFields: CustomFieldsNames,
+	}
+	if err := c.SearchInto(query, &fields); err != nil {
+		return nil, err
+	}
+	if len(fields) != 1 {
+		return nil, fmt.Errorf("did not get one bug, but %d", len(fields))
+	}
+	return &fields[0], nil
+}
+
+// SetDocType sets the type of documentation needed for the bug in the update
+func SetDocType(update *bugzilla.BugUpdate, value string) {
+	if update.CustomFields == nil {
+		update.CustomFields = map[string]interface{}{}
+	}
+	update.CustomFields["cf_doc_type"] = value
+}
+
+// DocTypeQuery returns a query term matching bugs by the type of documentation needed for the bug
+func DocTypeQuery(value string) bugzilla.AdvancedQuery {
+	return bugzilla.AdvancedQuery{Field: "cf_doc_type", Op: "equals", Value: value}
+}
+
+// SetPmScore sets the score assigned by product management in the update
+func SetPmScore(update *bugzilla.BugUpdate, value int) {
+	if update.CustomFields == nil {
+		update.CustomFields = map[string]interface{}{}
+	}
+	update.CustomFields["cf_pm_score"] = value
+}
+
+// PmScoreQuery returns a query term matching bugs by the score assigned by product management
+func PmScoreQuery(value int) bugzilla.AdvancedQuery {
+	return bugzilla.AdvancedQuery{Field: "cf_pm_score", Op: "equals", Value: strconv.Itoa(value)}
+}
+
+// SetVerifiedBranches sets the value of the cf_verified_branch custom field in the update
+func SetVerifiedBranches(update *bugzilla.BugUpdate, value []string) {
+	if update.CustomFields == nil {
+		update.CustomFields = map[string]interface{}{}
+	}
+	update.CustomFields["cf_verified_branch"] = value
+}
+
+// VerifiedBranchesQuery returns a query term matching bugs by the value of the cf_verified_branch custom field
+func VerifiedBranchesQuery(value ...string) bugzilla.AdvancedQuery {
+	return bugzilla.AdvancedQuery{Field: "cf_verified_branch", Op: "anyexact", Value: strings.Join(value, ",")}
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// customfield-gen generates typed accessors for Bugzilla custom fields. Given
+// a YAML configuration like:
+//
+//	package: customfields
+//	fields:
+//	- name: cf_doc_type
+//	  type: string
+//	  description: the type of documentation needed for the bug
+//	- name: cf_pm_score
+//	  type: int
+//
+// it generates a struct holding the fields, for use with Client.SearchInto,
+// a getter for the fields of one bug, a setter per field on BugUpdate and a
+// query term per field. Use it with a go:generate directive like:
+//
+//	//go:generate go run github.com/eparis/bugzilla/cmd/customfield-gen --config fields.yaml --output zz_generated.fields.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/template"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Config is the configuration for the generated code
+type Config struct {
+	// Package is the name of the package of the generated code
+	Package string `json:"package"`
+	// TypeName is the name of the generated struct, CustomFields by default
+	TypeName string `json:"typeName,omitempty"`
+	// Fields are the custom fields to generate accessors for
+	Fields []Field `json:"fields"`
+}
+
+// Field is a custom field
+type Field struct {
+	// Name is the name of the field in Bugzilla, like cf_doc_type
+	Name string `json:"name"`
+	// GoName is the name used in the generated code, derived from the name by default
+	GoName string `json:"goName,omitempty"`
+	// Type is the type of the field: string, int or []string for multi-select fields
+	Type string `json:"type"`
+	// Description is used in the doc comments of the generated code
+	Description string `json:"description,omitempty"`
+}
+
+// QueryOp is the search operator matching the field against a value
+func (f Field) QueryOp() string {
+	if f.Type == "[]string" {
+		return "anyexact"
+	}
+	return "equals"
+}
+
+func loadConfig(raw []byte) (*Config, error) {
+	var config Config
+	if err := yaml.UnmarshalStrict(raw, &config); err != nil {
+		return nil, fmt.Errorf("could not parse config: %v", err)
+	}
+	if config.Package == "" {
+		return nil, fmt.Errorf("package must be set")
+	}
+	if config.TypeName == "" {
+		config.TypeName = "CustomFields"
+	}
+	seen := map[string]bool{}
+	for i := range config.Fields {
+		field := &config.Fields[i]
+		if !strings.HasPrefix(field.Name, "cf_") {
+			return nil, fmt.Errorf("field %q is not a custom field, custom fields start with cf_", field.Name)
+		}
+		switch field.Type {
+		case "string", "int", "[]string":
+		default:
+			return nil, fmt.Errorf("field %q has unsupported type %q, supported types are string, int and []string", field.Name, field.Type)
+		}
+		if field.GoName == "" {
+			field.GoName = goName(field.Name)
+		}
+		if seen[field.GoName] {
+			return nil, fmt.Errorf("more than one field is named %s", field.GoName)
+		}
+		seen[field.GoName] = true
+		if field.Description == "" {
+			field.Description = fmt.Sprintf("the value of the %s custom field", field.Name)
+		}
+	}
+	return &config, nil
+}
+
+// goName turns cf_doc_type into DocType
+func goName(name string) string {
+	var parts []string
+	for _, part := range strings.Split(strings.TrimPrefix(name, "cf_"), "_") {
+		if part == "" {
+			continue
+		}
+		parts = append(parts, strings.ToUpper(part[:1])+part[1:])
+	}
+	return strings.Join(parts, "")
+}
+
+var generated = template.Must(template.New("generated").Parse(`// Code generated by customfield-gen. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/eparis/bugzilla"
+)
+
+// {{ .TypeName }} holds the custom fields of a bug
+type {{ .TypeName }} struct {
+	// ID is the ID of the bug the fields belong to
+	ID int ` + "`json:\"id\"`" + `
+{{- range .Fields }}
+	// {{ .GoName }} is {{ .Description }}
+	{{ .GoName }} {{ .Type }} ` + "`json:\"{{ .Name }},omitempty\"`" + `
+{{- end }}
+}
+
+// {{ .TypeName }}Names are the names of the custom fields, for use as IncludeFields in a query
+var {{ .TypeName }}Names = []string{"id"{{ range .Fields }}, "{{ .Name }}"{{ end }}}
+
+// Get{{ .TypeName }} retrieves the custom fields of the bug from the server
+func Get{{ .TypeName }}(c bugzilla.Client, id int) (*{{ .TypeName }}, error) {
+	var fields []{{ .TypeName }}
+	query := bugzilla.Query{
+		BugIDs:        []string{strconv.Itoa(id)},
+		BugIDsType:    "anyexact",
+		IncludeFields: {{ .TypeName }}Names,
+	}
+	if err := c.SearchInto(query, &fields); err != nil {
+		return nil, err
+	}
+	if len(fields) != 1 {
+		return nil, fmt.Errorf("did not get one bug, but %d", len(fields))
+	}
+	return &fields[0], nil
+}
+{{ range .Fields }}
+// Set{{ .GoName }} sets {{ .Description }} in the update
+func Set{{ .GoName }}(update *bugzilla.BugUpdate, value {{ .Type }}) {
+	if update.CustomFields == nil {
+		update.CustomFields = map[string]interface{}{}
+	}
+	update.CustomFields["{{ .Name }}"] = value
+}
+
+// {{ .GoName }}Query returns a query term matching bugs by {{ .Description }}
+func {{ .GoName }}Query(value {{ if eq .Type "[]string" }}...string{{ else }}{{ .Type }}{{ end }}) bugzilla.AdvancedQuery {
+	return bugzilla.AdvancedQuery{Field: "{{ .Name }}", Op: "{{ .QueryOp }}", Value: {{ if eq .Type "[]string" }}strings.Join(value, ","){{ else if eq .Type "int" }}strconv.Itoa(value){{ else }}value{{ end }}}
+}
+{{ end -}}
+`))
+
+func generate(config *Config) ([]byte, error) {
+	var out bytes.Buffer
+	if err := generated.Execute(&out, config); err != nil {
+		return nil, fmt.Errorf("could not render generated code: %v", err)
+	}
+	source := out.Bytes()
+	for _, field := range config.Fields {
+		if field.Type == "[]string" {
+			// only import strings when a multi-select field needs it
+			source = bytes.Replace(source, []byte("\t\"strconv\"\n"), []byte("\t\"strconv\"\n\t\"strings\"\n"), 1)
+			break
+		}
+	}
+	formatted, err := format.Source(source)
+	if err != nil {
+		return nil, fmt.Errorf("could not format generated code: %v\n%s", err, source)
+	}
+	return formatted, nil
+}
+
+func main() {
+	configPath := flag.String("config", "", "Path to the YAML configuration of the custom fields.")
+	outputPath := flag.String("output", "", "Path to write the generated code to. Defaults to stdout.")
+	flag.Parse()
+
+	if *configPath == "" {
+		fmt.Fprintln(os.Stderr, "--config is required")
+		os.Exit(1)
+	}
+	raw, err := ioutil.ReadFile(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not read config: %v\n", err)
+		os.Exit(1)
+	}
+	config, err := loadConfig(raw)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	source, err := generate(config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *outputPath == "" {
+		os.Stdout.Write(source)
+		return
+	}
+	if err := ioutil.WriteFile(*outputPath, source, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "could not write generated code: %v\n", err)
+		os.Exit(1)
+	}
+}
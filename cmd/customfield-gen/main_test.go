@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestGenerateExample(t *testing.T) {
+	raw, err := ioutil.ReadFile("example/fields.yaml")
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	config, err := loadConfig(raw)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	actual, err := generate(config)
+	if err != nil {
+		t.Fatalf("failed to generate code: %v", err)
+	}
+	expected, err := ioutil.ReadFile("example/zz_generated.fields.go")
+	if err != nil {
+		t.Fatalf("failed to read generated code: %v", err)
+	}
+	if string(actual) != string(expected) {
+		t.Errorf("generated code is out of date, run go generate: %v", diff.StringDiff(string(expected), string(actual)))
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		config string
+	}{
+		{
+			name:   "missing package",
+			config: "fields:\n- name: cf_doc_type\n  type: string\n",
+		},
+		{
+			name:   "not a custom field",
+			config: "package: p\nfields:\n- name: status\n  type: string\n",
+		},
+		{
+			name:   "unsupported type",
+			config: "package: p\nfields:\n- name: cf_doc_type\n  type: float\n",
+		},
+		{
+			name:   "conflicting names",
+			config: "package: p\nfields:\n- name: cf_doc_type\n  type: string\n- name: cf_doc__type\n  type: string\n",
+		},
+		{
+			name:   "unknown key",
+			config: "package: p\nfeilds: []\n",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if _, err := loadConfig([]byte(testCase.config)); err == nil {
+				t.Errorf("%s: expected an error, got none", testCase.name)
+			}
+		})
+	}
+}
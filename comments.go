@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// BugReference returns the reference to the bug in a comment, like
+// "bug 12345", which the server turns into a link to the bug.
+func BugReference(id int) string {
+	return fmt.Sprintf("bug %d", id)
+}
+
+// CommentReference returns the reference to the comment with the count on
+// the bug, like "bug 12345 comment 3", which the server turns into a link.
+func CommentReference(id, count int) string {
+	return fmt.Sprintf("bug %d comment %d", id, count)
+}
+
+// BugURL returns the URL of the page of the bug on the server with the
+// endpoint, for links outside of comments.
+func BugURL(endpoint string, id int) string {
+	return fmt.Sprintf("%s/show_bug.cgi?id=%d", strings.TrimSuffix(endpoint, "/"), id)
+}
+
+// Quote quotes the text like the server does when replying to a comment, by
+// prefixing every line with "> ". The quote ends with a newline.
+func Quote(text string) string {
+	text = strings.TrimRight(text, "\n")
+	var quoted strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		if line == "" {
+			quoted.WriteString(">\n")
+			continue
+		}
+		quoted.WriteString("> " + line + "\n")
+	}
+	return quoted.String()
+}
+
+// QuoteComment quotes the comment like the server does when replying to it,
+// starting with a line naming its author and count.
+func QuoteComment(comment Comment) string {
+	return fmt.Sprintf("(In reply to %s from comment #%d)\n%s", comment.Creator, comment.Count, Quote(comment.Text))
+}
+
+// backticks matches runs of backticks
+var backticks = regexp.MustCompile("`+")
+
+// CodeBlock fences the text as a code block for comments with Markdown, so
+// that it is shown as it is. The fence is longer than any run of backticks in
+// the text.
+func CodeBlock(text string) string {
+	fence := "```"
+	for _, run := range backticks.FindAllString(text, -1) {
+		if len(run) >= len(fence) {
+			fence = strings.Repeat("`", len(run)+1)
+		}
+	}
+	return fmt.Sprintf("%s\n%s\n%s\n", fence, strings.TrimRight(text, "\n"), fence)
+}
+
+// commentFuncs are the functions available in comment templates
+var commentFuncs = template.FuncMap{
+	"bug":       BugReference,
+	"comment":   CommentReference,
+	"quote":     Quote,
+	"codeblock": CodeBlock,
+	"join":      strings.Join,
+}
+
+// CommentTemplate renders the comments of bots consistently, see
+// ParseCommentTemplate.
+type CommentTemplate struct {
+	template *template.Template
+}
+
+// ParseCommentTemplate parses a text/template for comments. The bug is
+// available as dot, so {{.Status}} renders its status, and the functions
+// bug, comment, quote, codeblock and join render references to bugs, like
+// {{bug .ID}}, references to comments, quotes, code blocks and lists.
+func ParseCommentTemplate(text string) (*CommentTemplate, error) {
+	parsed, err := template.New("comment").Funcs(commentFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse comment template: %v", err)
+	}
+	return &CommentTemplate{template: parsed}, nil
+}
+
+// Render renders the comment for the bug, without trailing whitespace
+func (t *CommentTemplate) Render(bug *Bug) (string, error) {
+	var rendered strings.Builder
+	if err := t.template.Execute(&rendered, bug); err != nil {
+		return "", fmt.Errorf("could not render comment for bug %d: %v", bug.ID, err)
+	}
+	return strings.TrimRight(rendered.String(), " \t\n"), nil
+}
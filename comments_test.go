@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"testing"
+)
+
+func TestQuoteComment(t *testing.T) {
+	comment := Comment{Count: 3, Creator: "someone@example.com", Text: "It broke.\n\nAgain.\n"}
+	expected := "(In reply to someone@example.com from comment #3)\n> It broke.\n>\n> Again.\n"
+	if actual := QuoteComment(comment); actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestCodeBlock(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "text is fenced",
+			text:     "panic: oops\n",
+			expected: "```\npanic: oops\n```\n",
+		},
+		{
+			name:     "fences in the text are escaped by a longer fence",
+			text:     "```\ncode\n```",
+			expected: "````\n```\ncode\n```\n````\n",
+		},
+	}
+	for _, testCase := range testCases {
+		if actual := CodeBlock(testCase.text); actual != testCase.expected {
+			t.Errorf("%s: expected %q, got %q", testCase.name, testCase.expected, actual)
+		}
+	}
+}
+
+func TestCommentTemplate(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		template    string
+		expected    string
+		expectedErr bool
+	}{
+		{
+			name:     "bug fields and helpers are available",
+			template: "{{bug .ID}} moved to {{.Status}} for {{join .TargetRelease \", \"}}, see {{comment .ID 2}}.\n\n",
+			expected: "bug 12345 moved to MODIFIED for 4.6.0, 4.5.z, see bug 12345 comment 2.",
+		},
+		{
+			name:     "text is quoted",
+			template: "{{quote .Summary}}",
+			expected: "> Cluster fails",
+		},
+		{
+			name:        "unknown fields fail",
+			template:    "{{.Missing}}",
+			expectedErr: true,
+		},
+	}
+	bug := &Bug{ID: 12345, Status: "MODIFIED", Summary: "Cluster fails", TargetRelease: []string{"4.6.0", "4.5.z"}}
+	for _, testCase := range testCases {
+		tmpl, err := ParseCommentTemplate(testCase.template)
+		if err != nil {
+			t.Fatalf("%s: expected no error, but got one: %v", testCase.name, err)
+		}
+		actual, err := tmpl.Render(bug)
+		if testCase.expectedErr != (err != nil) {
+			t.Errorf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+		}
+		if actual != testCase.expected {
+			t.Errorf("%s: expected %q, got %q", testCase.name, testCase.expected, actual)
+		}
+	}
+}
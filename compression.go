@@ -0,0 +1,235 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// WithCompression compresses the traffic of the client, see
+// CompressingTransport. Request bodies of at least minSize bytes are
+// compressed, request bodies are never compressed if minSize is 0.
+func WithCompression(minSize int) Option {
+	return func(c *client) {
+		WithTransport(&CompressingTransport{Base: c.client.Transport, MinRequestSize: minSize})(c)
+	}
+}
+
+// CompressingTransport asks the server for gzip or deflate compressed
+// responses and decompresses them while they are read, and gzips large
+// request bodies, like those of bulk updates. Not all servers accept
+// compressed requests: once the server rejects a compressed request because
+// of its Content-Encoding, later requests are not compressed. The rejected
+// request is sent again uncompressed if it is idempotent, see WithRetries,
+// otherwise the rejection is returned.
+type CompressingTransport struct {
+	// Base is the transport used to send requests, http.DefaultTransport if nil.
+	Base http.RoundTripper
+	// MinRequestSize is the size from which request bodies are compressed,
+	// request bodies are not compressed if it is 0.
+	MinRequestSize int
+
+	lock sync.Mutex
+	// requestsRejected is set once the server rejected a compressed request
+	requestsRejected bool
+}
+
+func (t *CompressingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Accept-Encoding") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("Accept-Encoding", "gzip, deflate")
+	}
+	compressed, err := t.compressRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if compressed == nil {
+		return t.roundTrip(req)
+	}
+	resp, err := t.roundTrip(compressed)
+	if err != nil {
+		return nil, err
+	}
+	rejected, err := rejectsCompression(resp)
+	if err != nil || !rejected {
+		return resp, err
+	}
+	t.lock.Lock()
+	t.requestsRejected = true
+	t.lock.Unlock()
+	if !mayRetry(req) {
+		return resp, nil
+	}
+	resp.Body.Close()
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("could not reset request body to send it uncompressed: %v", err)
+		}
+		req.Body = body
+	}
+	return t.roundTrip(req)
+}
+
+// maxRejectionSize is how much of a bad request response is read to find out
+// whether it is about the Content-Encoding
+const maxRejectionSize = 4096
+
+// rejectsCompression determines if the server rejected a compressed request
+// because of its Content-Encoding: with 415 Unsupported Media Type, which is
+// how servers reject content codings they do not support, or with a 400 Bad
+// Request saying so. Other bad requests, like invalid values, are not about
+// the compression. The body of the response is left intact.
+func rejectsCompression(resp *http.Response) (bool, error) {
+	switch resp.StatusCode {
+	case http.StatusUnsupportedMediaType:
+		return true, nil
+	case http.StatusBadRequest:
+	default:
+		return false, nil
+	}
+	prefix, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxRejectionSize))
+	if err != nil {
+		resp.Body.Close()
+		return false, err
+	}
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{Reader: io.MultiReader(bytes.NewReader(prefix), resp.Body), Closer: resp.Body}
+	message := strings.ToLower(string(prefix))
+	return strings.Contains(message, "content-encoding") || strings.Contains(message, "content encoding"), nil
+}
+
+// compressRequest returns a copy of the request with a gzipped body, or nil if
+// the request should be sent as it is
+func (t *CompressingTransport) compressRequest(req *http.Request) (*http.Request, error) {
+	if t.MinRequestSize <= 0 || req.Body == nil || req.GetBody == nil || req.ContentLength < int64(t.MinRequestSize) || req.Header.Get("Content-Encoding") != "" {
+		return nil, nil
+	}
+	t.lock.Lock()
+	rejected := t.requestsRejected
+	t.lock.Unlock()
+	if rejected {
+		return nil, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("could not read request body to compress it: %v", err)
+	}
+	defer body.Close()
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := io.Copy(writer, body); err != nil {
+		return nil, fmt.Errorf("could not compress request body: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("could not compress request body: %v", err)
+	}
+	payload := buffer.Bytes()
+	compressed := req.Clone(req.Context())
+	compressed.Header.Set("Content-Encoding", "gzip")
+	compressed.Body = ioutil.NopCloser(bytes.NewReader(payload))
+	compressed.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(payload)), nil
+	}
+	compressed.ContentLength = int64(len(payload))
+	return compressed, nil
+}
+
+// roundTrip sends the request and replaces the body of a compressed response
+// with one decompressing it while it is read, so limits like
+// WithMaxResponseSize apply to the decompressed size without decompressing
+// more than they allow
+func (t *CompressingTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding != "gzip" && encoding != "deflate" {
+		return resp, nil
+	}
+	body, err := decompress(encoding, resp.Body)
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("could not decompress %s response: %v", encoding, err)
+	}
+	resp.Body = body
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+// decompressingBody decompresses a body while it is read
+type decompressingBody struct {
+	io.Reader
+	decompressor io.Closer
+	body         io.Closer
+}
+
+func (b *decompressingBody) Close() error {
+	b.decompressor.Close()
+	return b.body.Close()
+}
+
+// decompress returns a reader decompressing a gzip or deflate encoded body.
+// Deflate encoded bodies should be zlib streams, but some servers send raw
+// deflate streams.
+func decompress(encoding string, body io.ReadCloser) (io.ReadCloser, error) {
+	var reader io.ReadCloser
+	var err error
+	if encoding == "gzip" {
+		reader, err = gzip.NewReader(body)
+	} else {
+		buffered := bufio.NewReader(body)
+		if header, peekErr := buffered.Peek(2); peekErr == nil && isZlibHeader(header) {
+			reader, err = zlib.NewReader(buffered)
+		} else {
+			reader = flate.NewReader(buffered)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &decompressingBody{Reader: reader, decompressor: reader, body: body}, nil
+}
+
+// isZlibHeader determines if the two bytes start a zlib stream of deflate
+// compressed data
+func isZlibHeader(header []byte) bool {
+	return header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0
+}
+
+func (t *CompressingTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
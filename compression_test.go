@@ -0,0 +1,257 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompressedResponses(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		encoding string
+		writer   func(io.Writer) io.WriteCloser
+	}{
+		{
+			name: "uncompressed responses are used as they are",
+		},
+		{
+			name:     "gzip responses are decompressed",
+			encoding: "gzip",
+			writer:   func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		},
+		{
+			name:     "deflate responses are decompressed",
+			encoding: "deflate",
+			writer:   func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if accepted := r.Header.Get("Accept-Encoding"); accepted != "gzip, deflate" {
+					t.Errorf("%s: expected compressed responses to be accepted, got Accept-Encoding %q", testCase.name, accepted)
+				}
+				if testCase.writer == nil {
+					w.Write(bugData)
+					return
+				}
+				w.Header().Set("Content-Encoding", testCase.encoding)
+				writer := testCase.writer(w)
+				writer.Write(bugData)
+				writer.Close()
+			}))
+			defer testServer.Close()
+			c := clientForUrl(testServer.URL).(*client)
+			WithCompression(0)(c)
+
+			bug, err := c.GetBug(1705243)
+			if err != nil {
+				t.Fatalf("%s: expected no error, but got one: %v", testCase.name, err)
+			}
+			if bug.ID != 1705243 {
+				t.Errorf("%s: got incorrect bug %d", testCase.name, bug.ID)
+			}
+		})
+	}
+}
+
+func TestCompressedRequests(t *testing.T) {
+	var testCases = []struct {
+		name               string
+		minSize            int
+		rejectionCode      int
+		rejection          string
+		retrySafe          bool
+		expectedRequests   int
+		expectedCompressed int
+		expectedErrors     int
+	}{
+		{
+			name:             "requests are not compressed without a minimum size",
+			expectedRequests: 2,
+		},
+		{
+			name:               "large requests are compressed",
+			minSize:            1,
+			expectedRequests:   2,
+			expectedCompressed: 2,
+		},
+		{
+			name:             "small requests are not compressed",
+			minSize:          1 << 20,
+			expectedRequests: 2,
+		},
+		{
+			name:               "requests are sent uncompressed once the server rejected a compressed one as unsupported, but updates are not sent again",
+			minSize:            1,
+			rejectionCode:      http.StatusUnsupportedMediaType,
+			rejection:          "compressed requests are not supported",
+			expectedRequests:   2,
+			expectedCompressed: 1,
+			expectedErrors:     1,
+		},
+		{
+			name:               "requests are sent uncompressed once the server rejected the content encoding of one",
+			minSize:            1,
+			rejectionCode:      http.StatusBadRequest,
+			rejection:          "unsupported Content-Encoding: gzip",
+			expectedRequests:   2,
+			expectedCompressed: 1,
+			expectedErrors:     1,
+		},
+		{
+			name:               "requests stay compressed when the server rejects them for other reasons",
+			minSize:            1,
+			rejectionCode:      http.StatusBadRequest,
+			rejection:          "invalid status",
+			expectedRequests:   2,
+			expectedCompressed: 2,
+			expectedErrors:     2,
+		},
+		{
+			name:               "requests safe to send again are sent again uncompressed",
+			minSize:            1,
+			rejectionCode:      http.StatusUnsupportedMediaType,
+			rejection:          "compressed requests are not supported",
+			retrySafe:          true,
+			expectedRequests:   3,
+			expectedCompressed: 1,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			requests, compressed := 0, 0
+			testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				body := r.Body
+				if r.Header.Get("Content-Encoding") == "gzip" {
+					compressed++
+					if testCase.rejectionCode != 0 {
+						http.Error(w, testCase.rejection, testCase.rejectionCode)
+						return
+					}
+					reader, err := gzip.NewReader(r.Body)
+					if err != nil {
+						t.Fatalf("%s: could not decompress request: %v", testCase.name, err)
+					}
+					body = reader
+				}
+				raw, err := ioutil.ReadAll(body)
+				if err != nil {
+					t.Fatalf("%s: could not read request: %v", testCase.name, err)
+				}
+				if !strings.Contains(string(raw), `"status":"POST"`) {
+					t.Errorf("%s: got unexpected request body %s", testCase.name, raw)
+				}
+			}))
+			defer testServer.Close()
+			c := clientForUrl(testServer.URL).(*client)
+			WithCompression(testCase.minSize)(c)
+
+			errs := 0
+			for i := 0; i < 2; i++ {
+				var err error
+				if testCase.retrySafe {
+					req, reqErr := http.NewRequest(http.MethodPut, testServer.URL+"/rest/bug/1705243", strings.NewReader(`{"status":"POST"}`))
+					if reqErr != nil {
+						t.Fatalf("%s: could not create request: %v", testCase.name, reqErr)
+					}
+					var resp *http.Response
+					if resp, err = c.client.Do(markRetrySafe(req)); err == nil {
+						resp.Body.Close()
+						if resp.StatusCode != http.StatusOK {
+							err = fmt.Errorf("got status %d", resp.StatusCode)
+						}
+					}
+				} else {
+					err = c.UpdateBug(1705243, BugUpdate{Status: "POST"})
+				}
+				if err != nil {
+					errs++
+				}
+			}
+			if errs != testCase.expectedErrors {
+				t.Errorf("%s: expected %d failed requests, got %d", testCase.name, testCase.expectedErrors, errs)
+			}
+			if requests != testCase.expectedRequests {
+				t.Errorf("%s: expected %d requests, got %d", testCase.name, testCase.expectedRequests, requests)
+			}
+			if compressed != testCase.expectedCompressed {
+				t.Errorf("%s: expected %d compressed requests, got %d", testCase.name, testCase.expectedCompressed, compressed)
+			}
+		})
+	}
+}
+
+func TestCompressionKeepsTheHTTPClient(t *testing.T) {
+	httpClient := &http.Client{}
+	c := &client{client: httpClient}
+	WithCompression(0)(c)
+	if httpClient.Transport != nil {
+		t.Errorf("expected the given HTTP client not to be changed, got transport %T", httpClient.Transport)
+	}
+	if _, ok := c.client.Transport.(*CompressingTransport); !ok {
+		t.Errorf("expected the client to compress, got transport %T", c.client.Transport)
+	}
+}
+
+func TestDecompressedResponsesAreLimited(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		writer := gzip.NewWriter(w)
+		writer.Write(bytes.Repeat([]byte(" "), 1<<20))
+		writer.Write(bugData)
+		writer.Close()
+	}))
+	defer testServer.Close()
+	c := clientForUrl(testServer.URL).(*client)
+	WithCompression(0)(c)
+	WithMaxResponseSize(1 << 10)(c)
+
+	if _, err := c.GetBug(1705243); !IsResponseTooLarge(err) {
+		t.Errorf("expected the decompressed response to be too large, got %v", err)
+	}
+}
+
+func TestDecompressRawDeflate(t *testing.T) {
+	var buffer bytes.Buffer
+	writer, _ := flate.NewWriter(&buffer, flate.DefaultCompression)
+	writer.Write([]byte("raw"))
+	writer.Close()
+	reader, err := decompress("deflate", ioutil.NopCloser(&buffer))
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if string(body) != "raw" {
+		t.Errorf("expected %q, got %q", "raw", body)
+	}
+}
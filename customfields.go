@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// customFieldPrefix is the prefix of the names of custom fields
+const customFieldPrefix = "cf_"
+
+// bugFields are the JSON names of the fields of Bug, custom fields among them
+// are decoded into their own field and not into Bug.CustomFields
+var bugFields = jsonFields(reflect.TypeOf(Bug{}))
+
+// jsonFields returns the JSON names of the fields of the struct type
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+}
+
+// UnmarshalJSON unmarshals the bug, collecting the custom fields which Bug
+// has no field for in CustomFields
+func (b *Bug) UnmarshalJSON(raw []byte) error {
+	// the alias type does not have this method, which avoids recursing
+	type bug Bug
+	if err := json.Unmarshal(raw, (*bug)(b)); err != nil {
+		return err
+	}
+	b.CustomFields = nil
+	if !strings.Contains(string(raw), `"`+customFieldPrefix) {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return err
+	}
+	for name, rawValue := range fields {
+		if !strings.HasPrefix(name, customFieldPrefix) || bugFields[name] {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(rawValue, &value); err != nil {
+			return fmt.Errorf("could not unmarshal custom field %q: %v", name, err)
+		}
+		if b.CustomFields == nil {
+			b.CustomFields = map[string]interface{}{}
+		}
+		b.CustomFields[name] = value
+	}
+	return nil
+}
+
+// MarshalJSON marshals the bug, adding the CustomFields
+func (b Bug) MarshalJSON() ([]byte, error) {
+	type bug Bug
+	raw, err := json.Marshal(bug(b))
+	if err != nil || len(b.CustomFields) == 0 {
+		return raw, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	if err := addCustomFields(fields, b.CustomFields); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// MarshalJSON marshals the new bug, adding the CustomFields
+func (b BugCreate) MarshalJSON() ([]byte, error) {
+	type create BugCreate
+	raw, err := json.Marshal(create(b))
+	if err != nil || len(b.CustomFields) == 0 {
+		return raw, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	if err := addCustomFields(fields, b.CustomFields); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// addCustomFields adds the custom fields to the marshalled fields of an
+// object, refusing to overwrite any field which is already set
+func addCustomFields(fields map[string]json.RawMessage, custom map[string]interface{}) error {
+	for field, value := range custom {
+		if _, set := fields[field]; set {
+			return fmt.Errorf("custom field %q is already set", field)
+		}
+		rawValue, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("could not marshal custom field %q: %v", field, err)
+		}
+		fields[field] = rawValue
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestBugCustomFields(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		raw      string
+		expected Bug
+	}{
+		{
+			name:     "no custom fields",
+			raw:      `{"id":1,"status":"NEW"}`,
+			expected: Bug{ID: 1, Status: "NEW"},
+		},
+		{
+			name: "unknown custom fields are collected",
+			raw:  `{"id":1,"cf_doc_type":"Bug Fix","cf_release_notes":null,"cf_story_points":3,"cf_target_upstream_version":["1.2"]}`,
+			expected: Bug{ID: 1, CustomFields: map[string]interface{}{
+				"cf_doc_type":                "Bug Fix",
+				"cf_release_notes":           nil,
+				"cf_story_points":            float64(3),
+				"cf_target_upstream_version": []interface{}{"1.2"},
+			}},
+		},
+		{
+			name:     "known custom fields are decoded into their field",
+			raw:      `{"id":1,"cf_pm_score":"42","cf_devel_whiteboard":"devel"}`,
+			expected: Bug{ID: 1, PMScore: "42", DevelWhiteboard: "devel"},
+		},
+		{
+			name:     "other unknown fields are ignored",
+			raw:      `{"id":1,"flagtypes":[],"description_cf_doc_type":"ignored"}`,
+			expected: Bug{ID: 1},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var bug Bug
+			if err := json.Unmarshal([]byte(testCase.raw), &bug); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(bug, testCase.expected) {
+				t.Errorf("got incorrect bug: %v", diff.ObjectReflectDiff(testCase.expected, bug))
+			}
+
+			raw, err := json.Marshal(bug)
+			if err != nil {
+				t.Fatalf("expected no error marshalling the bug, got %v", err)
+			}
+			var roundTripped Bug
+			if err := json.Unmarshal(raw, &roundTripped); err != nil {
+				t.Fatalf("expected no error unmarshalling the marshalled bug, got %v", err)
+			}
+			if !reflect.DeepEqual(roundTripped, testCase.expected) {
+				t.Errorf("bug changed in a round trip: %v", diff.ObjectReflectDiff(testCase.expected, roundTripped))
+			}
+		})
+	}
+}
+
+func TestCustomFieldsMarshal(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		object      interface{}
+		expected    string
+		expectedErr bool
+	}{
+		{
+			name:     "bug without custom fields",
+			object:   Bug{ID: 1},
+			expected: `{"creation_time":null,"id":1,"last_change_time":null}`,
+		},
+		{
+			name:     "bug with custom fields",
+			object:   Bug{ID: 1, CustomFields: map[string]interface{}{"cf_doc_type": "Bug Fix"}},
+			expected: `{"cf_doc_type":"Bug Fix","creation_time":null,"id":1,"last_change_time":null}`,
+		},
+		{
+			name:     "created bug with custom fields",
+			object:   BugCreate{Product: "OpenShift", CustomFields: map[string]interface{}{"cf_doc_type": "Known Issue"}},
+			expected: `{"cf_doc_type":"Known Issue","product":"OpenShift"}`,
+		},
+		{
+			name:        "custom fields do not override fields",
+			object:      Bug{PMScore: "42", CustomFields: map[string]interface{}{"cf_pm_score": "0"}},
+			expectedErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			raw, err := json.Marshal(testCase.object)
+			if testCase.expectedErr != (err != nil) {
+				t.Fatalf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+			}
+			if err == nil && string(raw) != testCase.expected {
+				t.Errorf("%s: expected payload %s, got %s", testCase.name, testCase.expected, string(raw))
+			}
+		})
+	}
+}
+
+func TestFakeCustomFields(t *testing.T) {
+	fake := &Fake{}
+	id, err := fake.CreateBug(BugCreate{Product: "OpenShift", CustomFields: map[string]interface{}{"cf_doc_type": "Known Issue"}})
+	if err != nil {
+		t.Fatalf("expected no error creating the bug, got %v", err)
+	}
+	if err := fake.UpdateBug(id, BugUpdate{CustomFields: map[string]interface{}{"cf_release_notes": "Fixed."}}); err != nil {
+		t.Fatalf("expected no error updating the bug, got %v", err)
+	}
+	bug, err := fake.GetBug(id)
+	if err != nil {
+		t.Fatalf("expected no error getting the bug, got %v", err)
+	}
+	expected := map[string]interface{}{"cf_doc_type": "Known Issue", "cf_release_notes": "Fixed."}
+	if !reflect.DeepEqual(bug.CustomFields, expected) {
+		t.Errorf("got incorrect custom fields: %v", diff.ObjectReflectDiff(expected, bug.CustomFields))
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DegradationPolicy decides when the client is degraded, i.e. when calls
+// which are not critical should be skipped to spare a struggling server.
+type DegradationPolicy interface {
+	// Observe is called with the outcome of every request which was sent.
+	Observe(err error)
+	// Degraded returns true if non-critical calls should currently be skipped.
+	Degraded() bool
+}
+
+// WithDegradationPolicy makes the client skip calls made through a client
+// returned by NonCritical while the policy considers the client degraded.
+// Calls made through the client itself are critical and are always sent.
+// Policies created by NewErrorRatePolicy use the clock of the client.
+func WithDegradationPolicy(policy DegradationPolicy) Option {
+	return func(c *client) {
+		if errorRate, ok := policy.(*errorRatePolicy); ok {
+			errorRate.setClock(c.now)
+		}
+		c.degradation = policy
+	}
+}
+
+// NonCritical returns a client whose calls are skipped while the client is
+// degraded, for calls like metrics refreshes or enrichment which can be left
+// out without harm. The returned client shares all configuration and state
+// with the given one, including changes made later like SetAuthMethod.
+// Clients which do not support degradation are returned as they are.
+func NonCritical(c Client) Client {
+	original, ok := c.(*client)
+	if !ok {
+		return c
+	}
+	nonCritical := *original
+	nonCritical.nonCritical = true
+	return &nonCritical
+}
+
+// SkippedDueToDegradationError is returned for a non-critical call which
+// was not sent because the client is degraded.
+type SkippedDueToDegradationError struct {
+	// Method is the client method which was skipped.
+	Method string
+}
+
+func (e SkippedDueToDegradationError) Error() string {
+	return fmt.Sprintf("non-critical call %s skipped because the client is degraded", e.Method)
+}
+
+// IsSkippedDueToDegradation returns true if the error was returned because a
+// non-critical call was skipped while the client was degraded.
+func IsSkippedDueToDegradation(err error) bool {
+	var target *SkippedDueToDegradationError
+	return errors.As(err, &target)
+}
+
+// NewErrorRatePolicy returns a DegradationPolicy which considers the client
+// degraded while more than threshold (between 0 and 1) of the last window
+// requests failed. Only failures which indicate a struggling server, like
+// server errors, rate limiting and transport errors, count towards the rate.
+// The client is never degraded before window requests were observed.
+// Outcomes are forgotten after maxAge, or never if it is 0, so a client
+// which only makes non-critical calls recovers although they are not sent.
+func NewErrorRatePolicy(window int, threshold float64, maxAge time.Duration) DegradationPolicy {
+	return &errorRatePolicy{
+		outcomes:  make([]outcome, window),
+		threshold: threshold,
+		maxAge:    maxAge,
+		now:       time.Now,
+	}
+}
+
+type errorRatePolicy struct {
+	lock      sync.Mutex
+	outcomes  []outcome
+	next      int
+	observed  int
+	failures  int
+	threshold float64
+	maxAge    time.Duration
+	now       func() time.Time
+}
+
+// outcome is an observed request
+type outcome struct {
+	failed bool
+	at     time.Time
+}
+
+func (p *errorRatePolicy) setClock(now func() time.Time) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.now = now
+}
+
+func (p *errorRatePolicy) Observe(err error) {
+	failed := isRetryable(err) || IsRetryExhausted(err)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if len(p.outcomes) == 0 {
+		return
+	}
+	now := p.now()
+	p.forget(now)
+	if p.observed == len(p.outcomes) {
+		if p.outcomes[p.next].failed {
+			p.failures--
+		}
+	} else {
+		p.observed++
+	}
+	p.outcomes[p.next] = outcome{failed: failed, at: now}
+	if failed {
+		p.failures++
+	}
+	p.next = (p.next + 1) % len(p.outcomes)
+}
+
+func (p *errorRatePolicy) Degraded() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if len(p.outcomes) == 0 {
+		return false
+	}
+	p.forget(p.now())
+	if p.observed < len(p.outcomes) {
+		return false
+	}
+	return float64(p.failures)/float64(p.observed) > p.threshold
+}
+
+// forget drops the outcomes older than maxAge, starting with the oldest
+func (p *errorRatePolicy) forget(now time.Time) {
+	if p.maxAge <= 0 {
+		return
+	}
+	for p.observed > 0 {
+		oldest := (p.next - p.observed + len(p.outcomes)) % len(p.outcomes)
+		if now.Sub(p.outcomes[oldest].at) < p.maxAge {
+			return
+		}
+		if p.outcomes[oldest].failed {
+			p.failures--
+		}
+		p.outcomes[oldest] = outcome{}
+		p.observed--
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestErrorRatePolicy(t *testing.T) {
+	serverError := &RequestError{StatusCode: http.StatusInternalServerError}
+	notFound := &RequestError{StatusCode: http.StatusNotFound}
+	var testCases = []struct {
+		name     string
+		outcomes []error
+		expected bool
+	}{
+		{
+			name:     "not degraded before the window is full",
+			outcomes: []error{serverError, serverError, serverError},
+		},
+		{
+			name:     "not degraded at the threshold",
+			outcomes: []error{serverError, serverError, nil, nil},
+		},
+		{
+			name:     "degraded above the threshold",
+			outcomes: []error{serverError, serverError, serverError, nil},
+			expected: true,
+		},
+		{
+			name:     "client errors do not count",
+			outcomes: []error{notFound, notFound, notFound, errors.New("oops")},
+		},
+		{
+			name:     "recovers when old failures leave the window",
+			outcomes: []error{serverError, serverError, serverError, nil, nil, nil, nil},
+		},
+	}
+	for _, testCase := range testCases {
+		policy := NewErrorRatePolicy(4, 0.5, 0)
+		for _, outcome := range testCase.outcomes {
+			policy.Observe(outcome)
+		}
+		if actual := policy.Degraded(); actual != testCase.expected {
+			t.Errorf("%s: expected degraded %v, got %v", testCase.name, testCase.expected, actual)
+		}
+	}
+}
+
+func TestNonCriticalCallsSkippedWhenDegraded(t *testing.T) {
+	failing := true
+	requests := 0
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if failing {
+			http.Error(w, "503 Service Unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(bugData)
+	}))
+	defer testServer.Close()
+	c := clientForUrl(testServer.URL).(*client)
+	WithDegradationPolicy(NewErrorRatePolicy(2, 0.5, 0))(c)
+	nonCritical := NonCritical(c)
+
+	if _, err := nonCritical.GetBug(1705243); err == nil || IsSkippedDueToDegradation(err) {
+		t.Fatalf("expected the first call to be sent and fail, got %v", err)
+	}
+	if _, err := c.GetBug(1705243); err == nil || IsSkippedDueToDegradation(err) {
+		t.Fatalf("expected the critical call to be sent and fail, got %v", err)
+	}
+	failing = false
+	if _, err := nonCritical.GetBug(1705243); !IsSkippedDueToDegradation(err) {
+		t.Errorf("expected the non-critical call to be skipped, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests to be sent, got %d", requests)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetBug(1705243); err != nil {
+			t.Fatalf("expected the critical call to succeed, got %v", err)
+		}
+	}
+	if _, err := nonCritical.GetBug(1705243); err != nil {
+		t.Errorf("expected the non-critical call to succeed after recovery, got %v", err)
+	}
+}
+
+func TestErrorRatePolicyForgetsOldOutcomes(t *testing.T) {
+	serverError := &RequestError{StatusCode: http.StatusInternalServerError}
+	clock := &fakeClock{now: time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)}
+	c := clientForUrl("https://bugzilla.example.com").(*client)
+	WithClock(clock)(c)
+	policy := NewErrorRatePolicy(2, 0.5, time.Minute)
+	WithDegradationPolicy(policy)(c)
+
+	policy.Observe(serverError)
+	clock.Sleep(30 * time.Second)
+	policy.Observe(serverError)
+	if !policy.Degraded() {
+		t.Fatal("expected the policy to be degraded")
+	}
+	clock.Sleep(30 * time.Second)
+	if policy.Degraded() {
+		t.Error("expected the policy to recover once the first failure is a minute old")
+	}
+	policy.Observe(serverError)
+	if !policy.Degraded() {
+		t.Error("expected the policy to be degraded by a new failure")
+	}
+	clock.Sleep(time.Minute)
+	if policy.Degraded() {
+		t.Error("expected the policy to recover once all failures are a minute old")
+	}
+}
+
+func TestNonCriticalSharesSettings(t *testing.T) {
+	c := clientForUrl("https://bugzilla.example.com").(*client)
+	nonCritical := NonCritical(c).(*client)
+	if err := c.SetAuthMethod(AuthBearer); err != nil {
+		t.Fatalf("expected no error setting auth method, got %v", err)
+	}
+	if !nonCritical.usesAuthMethod(AuthBearer) {
+		t.Errorf("expected the non-critical client to use the auth method, got %v", nonCritical.authMethods())
+	}
+	c.WithCGIClient("user", "password")
+	if nonCritical.cgiClient() == nil {
+		t.Error("expected the non-critical client to use the CGI client")
+	}
+}
+
+func TestIsSkippedDueToDegradation(t *testing.T) {
+	skipped := &SkippedDueToDegradationError{Method: "GetBug"}
+	if !IsSkippedDueToDegradation(skipped) {
+		t.Error("expected the error to be recognized")
+	}
+	if !IsSkippedDueToDegradation(fmt.Errorf("refreshing: %w", skipped)) {
+		t.Error("expected the wrapped error to be recognized")
+	}
+	if IsSkippedDueToDegradation(errors.New("oops")) {
+		t.Error("expected other errors not to be recognized")
+	}
+}
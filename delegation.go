@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// AsUser returns a client which acts as the user owning the API key, e.g. a
+// team's service account, for delegated actions. Calls changing bugs are
+// audited as made by the actor, if the client has an AuditSink. The returned
+// client shares the transport, rate limiter and all other configuration with
+// the given one, but has caches of its own, so bugs retrieved by one user are
+// never served to the other. Clients logging in with WithLogin authenticate
+// with the API key instead, and the CGI client is not shared since it is
+// logged in as the original user. Clients which can not act as another user,
+// like the Fake or read-only clients, are rejected.
+func AsUser(c Client, actor string, apiKey []byte) (Client, error) {
+	original, ok := c.(*client)
+	if !ok {
+		return nil, fmt.Errorf("%T can not act as another user", c)
+	}
+	delegated := *original
+	delegated.apiKey = newAPIKeySupplier(func() []byte { return apiKey })
+	delegated.session = nil
+	delegated.settings = &clientSettings{authMethods: original.authMethods()}
+	if delegated.usesAuthMethod(AuthToken) {
+		// the API key is sent like with the default auth method instead of the token
+		var methods []string
+		for _, method := range delegated.settings.authMethods {
+			if method != AuthToken {
+				methods = append(methods, method)
+			}
+		}
+		delegated.settings.authMethods = append(methods, AuthQuery, AuthXBugzillaAPIKey)
+	}
+	if original.audit != nil {
+		delegated.audit = &auditor{actor: actor, sink: original.audit.sink}
+	}
+	if original.bugCache != nil {
+		delegated.bugCache = &bugCache{ttl: original.bugCache.ttl, entries: map[int]*bugCacheEntry{}}
+	}
+	if transport, ok := unsharedCaches(original.client.Transport); ok {
+		httpClient := *original.client
+		httpClient.Transport = transport
+		delegated.client = &httpClient
+	}
+	return &delegated, nil
+}
+
+// unsharedCaches returns a copy of the transport, or of the transports it
+// wraps, with an empty cache instead of every CachingTransport, or false if
+// there is no CachingTransport
+func unsharedCaches(transport http.RoundTripper) (http.RoundTripper, bool) {
+	switch t := transport.(type) {
+	case *CachingTransport:
+		base, _ := unsharedCaches(t.Base)
+		return &CachingTransport{Base: base, TTL: t.TTL, MaxEntries: t.MaxEntries, MaxEntrySize: t.MaxEntrySize, Clock: t.Clock}, true
+	case *CompressingTransport:
+		base, ok := unsharedCaches(t.Base)
+		if !ok {
+			return transport, false
+		}
+		return &CompressingTransport{Base: base, MinRequestSize: t.MinRequestSize}, true
+	}
+	return transport, false
+}
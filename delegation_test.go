@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestAsUser(t *testing.T) {
+	var keys []string
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/login" {
+			fmt.Fprint(w, `{"id":1,"token":"token"}`)
+			return
+		}
+		key := r.Header.Get("X-BUGZILLA-API-KEY")
+		if key == "" {
+			key = "token " + r.URL.Query().Get("token")
+		}
+		keys = append(keys, key)
+		w.Write(bugData)
+	}))
+	defer testServer.Close()
+	original := clientForUrl(testServer.URL).(*client)
+	WithRateLimit(100, 1)(original)
+	delegated, err := AsUser(original, "team-bot", []byte("service-account-key"))
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+
+	for _, c := range []Client{original, delegated, original} {
+		if _, err := c.GetBug(1705243); err != nil {
+			t.Fatalf("expected no error, but got one: %v", err)
+		}
+	}
+	if expected := []string{"api-key", "service-account-key", "api-key"}; !reflect.DeepEqual(keys, expected) {
+		t.Errorf("got incorrect API keys: %v", diff.ObjectReflectDiff(expected, keys))
+	}
+	if delegated.(*client).limiter != original.limiter || delegated.(*client).client != original.client {
+		t.Error("expected the delegated client to share the rate limiter and transport")
+	}
+
+	keys = nil
+	WithLogin("user", "secret")(original)
+	if err := original.SetAuthMethod(AuthToken); err != nil {
+		t.Fatalf("expected no error setting auth method, but got one: %v", err)
+	}
+	delegated, err = AsUser(original, "team-bot", []byte("service-account-key"))
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	for _, c := range []Client{original, delegated} {
+		if _, err := c.GetBug(1705243); err != nil {
+			t.Fatalf("expected no error, but got one: %v", err)
+		}
+	}
+	if expected := []string{"token token", "service-account-key"}; !reflect.DeepEqual(keys, expected) {
+		t.Errorf("got incorrect credentials: %v", diff.ObjectReflectDiff(expected, keys))
+	}
+
+	for _, c := range []Client{&Fake{}, NewReadOnlyClient(original)} {
+		if _, err := AsUser(c, "team-bot", []byte("service-account-key")); err == nil {
+			t.Errorf("expected %T to be rejected", c)
+		}
+	}
+}
+
+func TestAsUserHasItsOwnCachesAndActor(t *testing.T) {
+	var requests []string
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.Header.Get("X-BUGZILLA-API-KEY"))
+		if r.Method == http.MethodPut {
+			fmt.Fprint(w, `{"bugs":[]}`)
+			return
+		}
+		w.Write(bugData)
+	}))
+	defer testServer.Close()
+	original := clientForUrl(testServer.URL).(*client)
+	WithResponseCache(time.Hour)(original)
+	WithBugCache(time.Hour)(original)
+	sink := &recordingAuditSink{}
+	WithAuditSink("bot", sink)(original)
+	delegated, err := AsUser(original, "team-bot", []byte("service-account-key"))
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+
+	for _, c := range []Client{original, delegated, original, delegated} {
+		if _, err := c.GetBug(1705243); err != nil {
+			t.Fatalf("expected no error, but got one: %v", err)
+		}
+	}
+	if expected := []string{"GET api-key", "GET service-account-key"}; !reflect.DeepEqual(requests, expected) {
+		t.Errorf("got incorrect requests: %v", diff.ObjectReflectDiff(expected, requests))
+	}
+	if delegated.(*client).client.Transport == original.client.Transport {
+		t.Error("expected the delegated client to have its own response cache")
+	}
+
+	for _, c := range []Client{original, delegated} {
+		if err := c.UpdateBug(1705243, BugUpdate{Status: "POST"}); err != nil {
+			t.Fatalf("expected no error, but got one: %v", err)
+		}
+	}
+	var actors []string
+	for _, record := range sink.records {
+		actors = append(actors, record.Actor)
+	}
+	if expected := []string{"bot", "team-bot"}; !reflect.DeepEqual(actors, expected) {
+		t.Errorf("got incorrect actors: %v", diff.ObjectReflectDiff(expected, actors))
+	}
+}
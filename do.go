@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// bugPath matches the paths of bugs and their resources, like
+// "/rest/bug/1/comment", capturing the ID of the bug
+var bugPath = regexp.MustCompile(`^/?rest/bug/(\d+)(/|$)`)
+
+// Do sends a request to an endpoint of the server which the client does not
+// wrap, like "/rest/bug/1/flag_types" or "rest/component?product=Foo", going
+// through authentication, retries, rate limiting and logging like any other
+// call. The body, if not nil, is sent as JSON and the JSON response is
+// decoded into out, if not nil. Writes to a path of a bug, like
+// "/rest/bug/1/comment", drop the bug from the cache of WithBugCache, other
+// writes drop all cached bugs.
+func (c *client) Do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	logger := c.logger.WithFields(LogFields{methodField: "Do", "verb": method, "path": path})
+	parsed, err := url.Parse(path)
+	if err != nil || parsed.IsAbs() || parsed.Host != "" {
+		return fmt.Errorf("path %q must be relative to the endpoint", path)
+	}
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %v", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s", strings.TrimSuffix(c.endpoint, "/"), strings.TrimPrefix(path, "/")), reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if match := bugPath.FindStringSubmatch(parsed.Path); match != nil {
+		id, _ := strconv.Atoi(match[1])
+		req = changesBugs(req, id)
+	}
+	raw, err := c.request(req, logger)
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	if err := c.unmarshal(raw, out); err != nil {
+		return fmt.Errorf("could not unmarshal response body: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestDo(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-BUGZILLA-API-KEY") != "api-key" {
+			t.Error("did not get api-key passed in X-BUGZILLA-API-KEY header")
+			http.Error(w, "403 Forbidden", http.StatusForbidden)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/bug/1/flag_types" && r.URL.Query().Get("product") == "Foo":
+			w.Write([]byte(`{"bug":[{"id":1,"name":"needinfo"}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/bug/1/attachment":
+			raw, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				t.Errorf("failed to read request body: %v", err)
+			}
+			if string(raw) != `{"summary":"logs"}` {
+				t.Errorf("got incorrect request body %s", raw)
+			}
+			if r.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("expected a JSON request body, got %q", r.Header.Get("Content-Type"))
+			}
+			w.Write([]byte(`{"ids":[2]}`))
+		default:
+			t.Errorf("got unexpected request %s %s", r.Method, r.URL)
+			http.Error(w, "404 Not Found", http.StatusNotFound)
+		}
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	var flagTypes struct {
+		Bug []struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"bug"`
+	}
+	if err := client.Do(context.Background(), http.MethodGet, "/rest/bug/1/flag_types?product=Foo", nil, &flagTypes); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if len(flagTypes.Bug) != 1 || flagTypes.Bug[0].Name != "needinfo" {
+		t.Errorf("got incorrect flag types: %v", flagTypes)
+	}
+
+	var created struct {
+		IDs []int `json:"ids"`
+	}
+	if err := client.Do(context.Background(), http.MethodPost, "rest/bug/1/attachment", map[string]string{"summary": "logs"}, &created); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if !reflect.DeepEqual(created.IDs, []int{2}) {
+		t.Errorf("got incorrect response: %v", created)
+	}
+
+	if err := client.Do(context.Background(), http.MethodGet, "https://elsewhere.example.com/rest/bug", nil, nil); err == nil {
+		t.Error("expected an error for a request to another host, got none")
+	}
+}
+
+func TestFakeDo(t *testing.T) {
+	fake := &Fake{Responses: map[string]interface{}{"GET /rest/whoami": map[string]string{"name": "user"}}}
+	var whoami struct {
+		Name string `json:"name"`
+	}
+	if err := fake.Do(context.Background(), http.MethodGet, "/rest/whoami", nil, &whoami); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if whoami.Name != "user" {
+		t.Errorf("got incorrect response: %v", whoami)
+	}
+	if err := fake.Do(context.Background(), http.MethodGet, "/rest/unknown", nil, nil); !IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bugzilla is a client for the Bugzilla REST API.
+//
+// The root package is the core client: the Client interface, its
+// implementation and options, the Fake used in tests and the types exchanged
+// with the server. It keeps the import path of the module, so consumers of
+// the client alone need nothing else. Everything built on top of the client
+// lives in sub-packages which only use the Client interface:
+//
+//   - search builds queries, shards large searches and compares queries
+//   - events syncs and watches the bugs which changed, with checkpoints, and
+//     events/webhook receives the changes the server pushes
+//   - lifecycle tracks how a fix is verified and which payloads ship it
+//   - export streams search results to CSV or JSON Lines and bugs to YAML
+//
+// Larger automation, like mirrors or release tooling, has sub-packages of its
+// own. The dependency only ever points from a sub-package to the root
+// package, so the root package never imports any of them and consumers of the
+// client alone do not compile their dependencies. Subsystems with heavy
+// dependencies of their own, like the gRPC gateway, are separate modules, so
+// their dependencies do not even enter the module graph of the client.
+//
+// Besides the standard library, the root package only imports Prometheus for
+// its metrics. The client logs with the Logger interface, by default to the
+// standard library's log package; the logrusadapter package plugs in logrus.
+// Neither logrus nor k8s.io/apimachinery, which only the tests use, are
+// dependencies of the core client.
+package bugzilla
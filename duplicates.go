@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MarkAsDuplicate resolves the bug as a duplicate of the other bug. It fails
+// without changing the bug if the other bug is the bug itself or, following
+// its own duplicates, a duplicate of the bug, as the server rejects loops.
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#update-bug
+func (c *client) MarkAsDuplicate(id, dupeOf int) error {
+	return markAsDuplicate(c, id, dupeOf)
+}
+
+// ResolveDuplicateChain follows the duplicates starting at the bug to the bug
+// which is not a duplicate and returns its ID, which is the ID of the bug
+// itself if it is not a duplicate. It fails with a DuplicateCycleError if the
+// duplicates form a loop.
+func (c *client) ResolveDuplicateChain(id int) (int, error) {
+	return resolveDuplicateChain(c, id)
+}
+
+// DuplicateCycleError is returned when duplicates form a loop
+type DuplicateCycleError struct {
+	// Chain holds the IDs of the bugs in the order they were followed, the
+	// last of which was already seen before.
+	Chain []int
+}
+
+func (e *DuplicateCycleError) Error() string {
+	ids := make([]string, 0, len(e.Chain))
+	for _, id := range e.Chain {
+		ids = append(ids, strconv.Itoa(id))
+	}
+	return fmt.Sprintf("duplicates form a loop: %s", strings.Join(ids, " -> "))
+}
+
+func markAsDuplicate(c Client, id, dupeOf int) error {
+	if id == dupeOf {
+		return fmt.Errorf("bug %d cannot be a duplicate of itself", id)
+	}
+	canonical, err := resolveDuplicateChain(c, dupeOf)
+	if err != nil {
+		return err
+	}
+	if canonical == id {
+		return &DuplicateCycleError{Chain: []int{id, dupeOf, id}}
+	}
+	return c.UpdateBug(id, BugUpdate{Status: "RESOLVED", Resolution: "DUPLICATE", DupeOf: &dupeOf})
+}
+
+func resolveDuplicateChain(c Client, id int) (int, error) {
+	seen := map[int]bool{}
+	var chain []int
+	for {
+		chain = append(chain, id)
+		if seen[id] {
+			return 0, &DuplicateCycleError{Chain: chain}
+		}
+		seen[id] = true
+		bug, err := c.GetBugWithFields(id, []string{"id", "dupe_of"})
+		if err != nil {
+			return 0, err
+		}
+		if bug.DupeOf == 0 {
+			return id, nil
+		}
+		id = bug.DupeOf
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarkAsDuplicate(t *testing.T) {
+	var body string
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var id int
+		if _, err := fmt.Sscanf(r.URL.Path, "/rest/bug/%d", &id); err != nil {
+			t.Errorf("incorrect path to mark a duplicate: %s", r.URL.Path)
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+			return
+		}
+		if r.Method == http.MethodGet {
+			// 2 is a duplicate of 3, which is not a duplicate
+			dupeOf := map[int]int{2: 3}[id]
+			fmt.Fprintf(w, `{"bugs":[{"id":%d,"dupe_of":%d}]}`, id, dupeOf)
+			return
+		}
+		raw, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("could not read request body: %v", err)
+		}
+		body = strings.TrimSpace(string(raw))
+		w.Write([]byte(`{"bugs":[{"id":1,"changes":{}}]}`))
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	if err := client.MarkAsDuplicate(1, 2); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if expected := `{"status":"RESOLVED","resolution":"DUPLICATE","dupe_of":2}`; body != expected {
+		t.Errorf("expected update %s, got %s", expected, body)
+	}
+	canonical, err := client.ResolveDuplicateChain(2)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if canonical != 3 {
+		t.Errorf("expected bug 2 to resolve to bug 3, got %d", canonical)
+	}
+}
+
+func TestFakeDuplicates(t *testing.T) {
+	fake := &Fake{Bugs: map[int]Bug{
+		1: {ID: 1, Status: "NEW"},
+		2: {ID: 2, Status: "NEW"},
+		3: {ID: 3, Status: "NEW"},
+	}}
+	if err := fake.MarkAsDuplicate(1, 2); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if err := fake.MarkAsDuplicate(2, 3); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if bug := fake.Bugs[1]; bug.Status != "RESOLVED" || bug.Resolution != "DUPLICATE" || bug.DupeOf != 2 {
+		t.Errorf("expected bug 1 to be resolved as a duplicate of bug 2, got %s %s of %d", bug.Status, bug.Resolution, bug.DupeOf)
+	}
+	for id, expected := range map[int]int{1: 3, 2: 3, 3: 3} {
+		if canonical, err := fake.ResolveDuplicateChain(id); err != nil || canonical != expected {
+			t.Errorf("expected bug %d to resolve to bug %d, got %d (error %v)", id, expected, canonical, err)
+		}
+	}
+
+	var cycle *DuplicateCycleError
+	err := fake.MarkAsDuplicate(3, 1)
+	if cycle, _ = err.(*DuplicateCycleError); cycle == nil {
+		t.Errorf("expected a cycle error marking the canonical bug as a duplicate, got %v", err)
+	}
+	if fake.Bugs[3].DupeOf != 0 {
+		t.Error("expected the bug not to change when the duplicate would form a loop")
+	}
+	if err := fake.MarkAsDuplicate(3, 3); err == nil {
+		t.Error("expected an error marking a bug as a duplicate of itself")
+	}
+
+	bug := fake.Bugs[3]
+	bug.DupeOf = 1
+	fake.Bugs[3] = bug
+	_, err = fake.ResolveDuplicateChain(1)
+	if cycle, _ = err.(*DuplicateCycleError); cycle == nil || !reflect.DeepEqual(cycle.Chain, []int{1, 2, 3, 1}) {
+		t.Errorf("expected a cycle error with the chain, got %v", err)
+	}
+	if _, err := fake.ResolveDuplicateChain(4); !IsNotFound(err) {
+		t.Errorf("expected a not found error for a missing bug, got %v", err)
+	}
+}
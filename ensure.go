@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+// DesiredBugState is the state EnsureBugState brings a bug into. Fields which
+// are left empty are not enforced.
+type DesiredBugState struct {
+	// Status is the status the bug must have.
+	Status string
+	// Resolution is the resolution the bug must have. It is only enforced
+	// if set, as the server clears it when the bug is reopened.
+	Resolution string
+	// TargetRelease is the only target release the bug must have.
+	TargetRelease string
+	// Keywords are keywords the bug must have.
+	Keywords []string
+	// AbsentKeywords are keywords the bug must not have.
+	AbsentKeywords []string
+}
+
+// EnsureBugState brings the bug into the desired state, updating it only if
+// it is not in that state already, so it can be called repeatedly. The update
+// is guarded by the time the bug last changed and recomputed if the bug
+// changed concurrently. It returns the update which was sent, or nil if the
+// bug was in the desired state.
+func (c *client) EnsureBugState(id int, desired DesiredBugState) (*BugUpdate, error) {
+	return ensureBugState(c, id, desired)
+}
+
+func ensureBugState(c Client, id int, desired DesiredBugState) (*BugUpdate, error) {
+	return updateGuarded(c, id, []string{"id", "status", "resolution", "target_release", "keywords"}, func(current *Bug) (*BugUpdate, error) {
+		wanted := *current
+		if desired.Status != "" {
+			wanted.Status = desired.Status
+		}
+		if desired.Resolution != "" {
+			wanted.Resolution = desired.Resolution
+		}
+		if desired.TargetRelease != "" {
+			wanted.TargetRelease = []string{desired.TargetRelease}
+		}
+		wanted.Keywords = updateStrings(current.Keywords, desired.Keywords, desired.AbsentKeywords, nil)
+		update, err := DiffBugs(current, &wanted)
+		if err != nil {
+			return nil, err
+		}
+		if update.Status == "" && update.Resolution == "" && update.TargetRelease == "" && update.Keywords == nil {
+			return nil, nil
+		}
+		return &update, nil
+	})
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestEnsureBugState(t *testing.T) {
+	read := Timestamp{Time: time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)}
+	changed := Timestamp{Time: read.Add(time.Second)}
+	var testCases = []struct {
+		name           string
+		bug            Bug
+		desired        DesiredBugState
+		expectedUpdate *BugUpdate
+		expectedBug    Bug
+	}{
+		{
+			name:        "bug in the desired state is not updated",
+			bug:         Bug{ID: 1, Status: "MODIFIED", TargetRelease: []string{"4.6.0"}, Keywords: []string{"a"}},
+			desired:     DesiredBugState{Status: "MODIFIED", TargetRelease: "4.6.0", Keywords: []string{"a"}, AbsentKeywords: []string{"b"}},
+			expectedBug: Bug{ID: 1, Status: "MODIFIED", TargetRelease: []string{"4.6.0"}, Keywords: []string{"a"}},
+		},
+		{
+			name:    "only differences are updated",
+			bug:     Bug{ID: 1, Status: "POST", TargetRelease: []string{"4.6.0"}, Keywords: []string{"a", "b"}, LastChangeTime: read},
+			desired: DesiredBugState{Status: "MODIFIED", TargetRelease: "4.6.0", Keywords: []string{"a", "c"}, AbsentKeywords: []string{"b"}},
+			expectedUpdate: &BugUpdate{
+				Status:   "MODIFIED",
+				Keywords: &BugKeywords{Add: []string{"c"}, Remove: []string{"b"}},
+			},
+			expectedBug: Bug{ID: 1, Status: "MODIFIED", TargetRelease: []string{"4.6.0"}, Keywords: []string{"a", "c"}, LastChangeTime: changed},
+		},
+		{
+			name:           "reopening leaves the resolution to the server",
+			bug:            Bug{ID: 1, Status: "CLOSED", Resolution: "ERRATA", TargetRelease: []string{"4.5.0"}, Keywords: []string{"a"}, LastChangeTime: read},
+			desired:        DesiredBugState{Status: "NEW", TargetRelease: "4.6.0"},
+			expectedUpdate: &BugUpdate{Status: "NEW", TargetRelease: "4.6.0"},
+			expectedBug:    Bug{ID: 1, Status: "NEW", TargetRelease: []string{"4.6.0"}, Keywords: []string{"a"}, LastChangeTime: changed},
+		},
+	}
+	for _, testCase := range testCases {
+		fake := &Fake{Bugs: map[int]Bug{1: testCase.bug}}
+		update, err := fake.EnsureBugState(1, testCase.desired)
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", testCase.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(update, testCase.expectedUpdate) {
+			t.Errorf("%s: got incorrect update: %s", testCase.name, diff.ObjectReflectDiff(testCase.expectedUpdate, update))
+		}
+		if actual := fake.Bugs[1]; !reflect.DeepEqual(actual, testCase.expectedBug) {
+			t.Errorf("%s: got incorrect bug: %s", testCase.name, diff.ObjectReflectDiff(testCase.expectedBug, actual))
+		}
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Bugzilla error codes, see:
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/general.html#errors
+const (
+	// ErrorCodeInvalidBugID is returned for a bug ID or alias which is invalid
+	ErrorCodeInvalidBugID = 100
+	// ErrorCodeBugDoesNotExist is returned for a bug ID which does not exist
+	ErrorCodeBugDoesNotExist = 101
+	// ErrorCodeBugAccessDenied is returned for a bug the user is not allowed to access
+	ErrorCodeBugAccessDenied = 102
+	// ErrorCodeInvalidLogin is returned for an invalid username or password
+	ErrorCodeInvalidLogin = 300
+	// ErrorCodeInvalidAPIKey is returned for an invalid API key
+	ErrorCodeInvalidAPIKey = 306
+	// ErrorCodeLoginRequired is returned when the request needs an authenticated user
+	ErrorCodeLoginRequired = 410
+	// ErrorCodeInvalidToken is returned for a session token which is invalid or expired
+	ErrorCodeInvalidToken = 32000
+)
+
+// RequestError is returned when a request to Bugzilla fails
+type RequestError struct {
+	// StatusCode is the HTTP status code of the response, or -1 if no response was received.
+	StatusCode int
+	// Code is the Bugzilla error code, if the server returned one.
+	Code int
+	// Message describes the failure, including the message from the server if there was one.
+	Message string
+	// RetryAfter is the delay the server asked for before the request is sent
+	// again with the Retry-After header, if it did.
+	RetryAfter time.Duration
+}
+
+func (e RequestError) Error() string {
+	return e.Message
+}
+
+// newRequestError creates a RequestError for a response with an unexpected
+// status code, using the error code and message from the body if it has them.
+func newRequestError(statusCode int, body []byte) *RequestError {
+	reqError := &RequestError{
+		StatusCode: statusCode,
+		Message:    fmt.Sprintf("response code %d not %d", statusCode, http.StatusOK),
+	}
+	var response struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &response); err == nil && response.Message != "" {
+		reqError.Code = response.Code
+		reqError.Message = fmt.Sprintf("%s: code %d: %s", reqError.Message, response.Code, response.Message)
+	}
+	return reqError
+}
+
+func asRequestError(err error) (*RequestError, bool) {
+	var reqError *RequestError
+	if !errors.As(err, &reqError) {
+		return nil, false
+	}
+	return reqError, true
+}
+
+// IsNotFound returns true if the server responded that the resource does not exist
+func IsNotFound(err error) bool {
+	reqError, ok := asRequestError(err)
+	if !ok {
+		return false
+	}
+	return reqError.StatusCode == http.StatusNotFound
+}
+
+// IsUnauthorized returns true if the server rejected the credentials or
+// required credentials which were not given
+func IsUnauthorized(err error) bool {
+	reqError, ok := asRequestError(err)
+	if !ok {
+		return false
+	}
+	return reqError.StatusCode == http.StatusUnauthorized || reqError.Code == ErrorCodeInvalidLogin || reqError.Code == ErrorCodeInvalidAPIKey || reqError.Code == ErrorCodeLoginRequired || reqError.Code == ErrorCodeInvalidToken
+}
+
+// IsForbidden returns true if the credentials are not allowed to access the resource
+func IsForbidden(err error) bool {
+	reqError, ok := asRequestError(err)
+	if !ok {
+		return false
+	}
+	return reqError.StatusCode == http.StatusForbidden || reqError.Code == ErrorCodeBugAccessDenied
+}
+
+// IsInvalidBug returns true if the server responded that the bug ID or alias
+// is invalid or does not exist
+func IsInvalidBug(err error) bool {
+	reqError, ok := asRequestError(err)
+	if !ok {
+		return false
+	}
+	return reqError.Code == ErrorCodeInvalidBugID || reqError.Code == ErrorCodeBugDoesNotExist
+}
+
+// IsRateLimited returns true if the server rejected the request because too
+// many requests were sent
+func IsRateLimited(err error) bool {
+	reqError, ok := asRequestError(err)
+	if !ok {
+		return false
+	}
+	return reqError.StatusCode == http.StatusTooManyRequests
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestErrors(t *testing.T) {
+	var testCases = []struct {
+		name                 string
+		status               int
+		body                 string
+		expectedCode         int
+		expectedMessage      string
+		expectedNotFound     bool
+		expectedUnauthorized bool
+		expectedForbidden    bool
+		expectedInvalidBug   bool
+		expectedRateLimited  bool
+	}{
+		{
+			name:               "missing bug",
+			status:             http.StatusNotFound,
+			body:               `{"error":true,"code":101,"message":"Bug #1 does not exist.","documentation":"https://bugzilla.readthedocs.org/en/latest/api/"}`,
+			expectedCode:       ErrorCodeBugDoesNotExist,
+			expectedMessage:    "response code 404 not 200: code 101: Bug #1 does not exist.",
+			expectedNotFound:   true,
+			expectedInvalidBug: true,
+		},
+		{
+			name:                 "invalid API key",
+			status:               http.StatusBadRequest,
+			body:                 `{"error":true,"code":306,"message":"The API key you specified is invalid."}`,
+			expectedCode:         ErrorCodeInvalidAPIKey,
+			expectedMessage:      "response code 400 not 200: code 306: The API key you specified is invalid.",
+			expectedUnauthorized: true,
+		},
+		{
+			name:              "private bug",
+			status:            http.StatusUnauthorized,
+			body:              `{"error":true,"code":102,"message":"You are not authorized to access bug #1."}`,
+			expectedCode:      ErrorCodeBugAccessDenied,
+			expectedMessage:   "response code 401 not 200: code 102: You are not authorized to access bug #1.",
+			expectedForbidden: true,
+			// the HTTP status is 401 for access denied errors
+			expectedUnauthorized: true,
+		},
+		{
+			name:                "rate limited by a proxy without a Bugzilla body",
+			status:              http.StatusTooManyRequests,
+			body:                `<html>slow down</html>`,
+			expectedMessage:     "response code 429 not 200",
+			expectedRateLimited: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(testCase.status)
+				w.Write([]byte(testCase.body))
+			}))
+			defer testServer.Close()
+			client := clientForUrl(testServer.URL)
+
+			_, err := client.GetBug(1)
+			reqError, ok := err.(*RequestError)
+			if !ok {
+				t.Fatalf("%s: expected a *RequestError, got %T: %v", testCase.name, err, err)
+			}
+			if reqError.StatusCode != testCase.status || reqError.Code != testCase.expectedCode || reqError.Message != testCase.expectedMessage {
+				t.Errorf("%s: got incorrect error: %#v", testCase.name, reqError)
+			}
+			for _, predicate := range []struct {
+				name     string
+				actual   bool
+				expected bool
+			}{
+				{name: "IsNotFound", actual: IsNotFound(err), expected: testCase.expectedNotFound},
+				{name: "IsUnauthorized", actual: IsUnauthorized(err), expected: testCase.expectedUnauthorized},
+				{name: "IsForbidden", actual: IsForbidden(err), expected: testCase.expectedForbidden},
+				{name: "IsInvalidBug", actual: IsInvalidBug(err), expected: testCase.expectedInvalidBug},
+				{name: "IsRateLimited", actual: IsRateLimited(err), expected: testCase.expectedRateLimited},
+			} {
+				if predicate.actual != predicate.expected {
+					t.Errorf("%s: expected %s to be %v", testCase.name, predicate.name, predicate.expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/eparis/bugzilla"
+)
+
+// Checkpoint stores how far an incremental process got, like SyncModifiedSince
+// or a Watcher, so a restarted process resumes where the last one
+// stopped. Users can store it wherever they like, NewFileCheckpoint stores it
+// in a file.
+type Checkpoint interface {
+	// Load decodes the stored state into state, which must be a pointer, and
+	// leaves state unchanged if none was stored yet.
+	Load(state interface{}) error
+	// Save stores the state.
+	Save(state interface{}) error
+}
+
+// NewFileCheckpoint returns a Checkpoint storing the state as JSON in the
+// file at path
+func NewFileCheckpoint(path string) Checkpoint {
+	return &fileCheckpoint{path: path}
+}
+
+type fileCheckpoint struct {
+	path string
+}
+
+func (c *fileCheckpoint) Load(state interface{}) error {
+	raw, err := ioutil.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not read checkpoint: %v", err)
+	}
+	if err := json.Unmarshal(raw, state); err != nil {
+		return fmt.Errorf("could not unmarshal checkpoint: %v", err)
+	}
+	return nil
+}
+
+// Save replaces the file, so a crash while saving leaves the previous state
+func (c *fileCheckpoint) Save(state interface{}) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("could not marshal checkpoint: %v", err)
+	}
+	if err := bugzilla.WriteFileAtomic(c.path, raw); err != nil {
+		return fmt.Errorf("could not write checkpoint: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"time"
+
+	"github.com/eparis/bugzilla"
+)
+
+// SyncModifiedSince hands the bugs matching the query which changed since the
+// time stored in the checkpoint to handle and, if handle succeeds, advances
+// the checkpoint to when the last of them changed. Without a stored time, all
+// bugs matching the query are handled. As the server tracks changes to the
+// second, the bugs which changed at the stored time are handled again by the
+// next sync, so handle must tolerate seeing a bug again.
+func SyncModifiedSince(c bugzilla.Client, checkpoint Checkpoint, query bugzilla.Query, handle func([]*bugzilla.Bug) error) error {
+	var since time.Time
+	if err := checkpoint.Load(&since); err != nil {
+		return err
+	}
+	bugs, err := c.GetBugsModifiedSince(since, query)
+	if err != nil {
+		return err
+	}
+	if len(bugs) == 0 {
+		return nil
+	}
+	if err := handle(bugs); err != nil {
+		return err
+	}
+	latest := since
+	for _, bug := range bugs {
+		if bug.LastChangeTime.After(latest) {
+			latest = bug.LastChangeTime.Time
+		}
+	}
+	if latest.Equal(since) {
+		return nil
+	}
+	return checkpoint.Save(latest)
+}
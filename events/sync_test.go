@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/eparis/bugzilla"
+)
+
+func TestSyncModifiedSince(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events")
+	if err != nil {
+		t.Fatalf("could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	checkpoint := NewFileCheckpoint(filepath.Join(dir, "checkpoint.json"))
+
+	start := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	fake := &bugzilla.Fake{Bugs: map[int]bugzilla.Bug{
+		1: {ID: 1, LastChangeTime: bugzilla.NewTimestamp(start)},
+		2: {ID: 2, LastChangeTime: bugzilla.NewTimestamp(start.Add(time.Minute))},
+	}}
+	sync := func(handleErr error) []int {
+		var ids []int
+		if err := SyncModifiedSince(fake, checkpoint, bugzilla.Query{}, func(bugs []*bugzilla.Bug) error {
+			for _, bug := range bugs {
+				ids = append(ids, bug.ID)
+			}
+			return handleErr
+		}); err != handleErr {
+			t.Fatalf("expected error %v, got %v", handleErr, err)
+		}
+		return ids
+	}
+
+	if ids := sync(nil); !reflect.DeepEqual(ids, []int{1, 2}) {
+		t.Errorf("expected the first sync to handle all bugs, got %v", ids)
+	}
+	var since time.Time
+	if err := checkpoint.Load(&since); err != nil || !since.Equal(start.Add(time.Minute)) {
+		t.Errorf("expected the checkpoint to be the last change, got %v (error %v)", since, err)
+	}
+
+	fake.Bugs[3] = bugzilla.Bug{ID: 3, LastChangeTime: bugzilla.NewTimestamp(start.Add(time.Hour))}
+	failed := errors.New("failed")
+	if ids := sync(failed); !reflect.DeepEqual(ids, []int{2, 3}) {
+		t.Errorf("expected the sync to handle the bugs changed since the checkpoint, got %v", ids)
+	}
+	if ids := sync(nil); !reflect.DeepEqual(ids, []int{2, 3}) {
+		t.Errorf("expected the sync to retry after handling failed, got %v", ids)
+	}
+	if err := checkpoint.Load(&since); err != nil || !since.Equal(start.Add(time.Hour)) {
+		t.Errorf("expected the checkpoint to advance, got %v (error %v)", since, err)
+	}
+}
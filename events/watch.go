@@ -0,0 +1,174 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package events follows the bugs which change on a Bugzilla server.
+// SyncModifiedSince hands the bugs which changed since the last sync to a
+// handler and the Watcher polls for changed bugs, for servers which can not
+// push changes with webhooks; the webhook sub-package receives them from
+// servers which can. Both remember how far they got in a Checkpoint, so a
+// restarted process resumes where the last one stopped.
+package events
+
+import (
+	"sort"
+	"time"
+
+	"github.com/eparis/bugzilla"
+	"github.com/sirupsen/logrus"
+)
+
+// Event reports a change of a bug
+type Event struct {
+	// Bug is the bug as it was after the change, with the fields the query of
+	// the Watcher includes.
+	Bug *bugzilla.Bug
+}
+
+// State is how far a Watcher got: the time of the latest change it delivered
+// and the bugs it delivered which changed at that time, as the next poll asks
+// for that time again to not miss bugs which changed in the same second.
+type State struct {
+	Since time.Time `json:"since"`
+	Seen  []int     `json:"seen,omitempty"`
+}
+
+// Watcher polls for the bugs matching a query which changed since the last
+// poll. A bug which changed more than once between two polls is delivered
+// once. The events of a poll count as handled once the next poll starts, which
+// is when the checkpoint is saved, so after a crash the events of the last
+// poll are delivered again.
+type Watcher struct {
+	Client bugzilla.Client
+	// Query selects the bugs to watch. The id and last_change_time fields are
+	// added to its IncludeFields, if it has any.
+	Query bugzilla.Query
+	// Interval is the time between polls.
+	Interval time.Duration
+	// Checkpoint, if set, stores the State of the watcher and is where the
+	// watcher resumes from.
+	Checkpoint Checkpoint
+	// Since is when to watch for changes from if there is no checkpoint, by
+	// default the time of the first poll.
+	Since time.Time
+	// Logger logs polls which failed, logrus' standard logger if nil.
+	Logger *logrus.Entry
+
+	state  State
+	loaded bool
+	// unsaved is set when the state has not been saved to the checkpoint yet
+	unsaved bool
+}
+
+// Poll returns the bugs which changed since the previous poll, ordered by the
+// time they changed. It first saves the checkpoint, if any, for the events of
+// the previous poll, which are handled by now.
+func (w *Watcher) Poll() ([]Event, error) {
+	if !w.loaded {
+		if err := w.load(); err != nil {
+			return nil, err
+		}
+	}
+	if w.unsaved && w.Checkpoint != nil {
+		if err := w.Checkpoint.Save(w.state); err != nil {
+			return nil, err
+		}
+	}
+	w.unsaved = false
+	bugs, err := w.Client.GetBugsModifiedSince(w.state.Since, w.Query)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[int]bool{}
+	for _, id := range w.state.Seen {
+		seen[id] = true
+	}
+	next := w.state
+	var events []Event
+	for _, bug := range bugs {
+		changed := bug.LastChangeTime.Time
+		if changed.Before(w.state.Since) || (changed.Equal(w.state.Since) && seen[bug.ID]) {
+			continue
+		}
+		events = append(events, Event{Bug: bug})
+		if changed.After(next.Since) {
+			next = State{Since: changed}
+		}
+		next.Seen = append(next.Seen, bug.ID)
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+	sort.Ints(next.Seen)
+	w.state, w.unsaved = next, true
+	return events, nil
+}
+
+// load initializes the state from the checkpoint or Since
+func (w *Watcher) load() error {
+	if w.Checkpoint != nil {
+		var state State
+		if err := w.Checkpoint.Load(&state); err != nil {
+			return err
+		}
+		if !state.Since.IsZero() {
+			w.state, w.loaded = state, true
+			return nil
+		}
+	}
+	w.state = State{Since: w.Since}
+	if w.state.Since.IsZero() {
+		w.state.Since = time.Now()
+	}
+	w.loaded = true
+	return nil
+}
+
+// Run polls right away and then every interval until stop is closed,
+// delivering the events on the returned channel, which is closed when the
+// watcher stops. Failed polls are logged and retried at the next interval.
+// The events of a poll count as handled once they were all received and the
+// next poll starts.
+func (w *Watcher) Run(stop <-chan struct{}) <-chan Event {
+	logger := w.Logger
+	if logger == nil {
+		logger = logrus.NewEntry(logrus.StandardLogger())
+	}
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(w.Interval)
+		defer ticker.Stop()
+		for {
+			polled, err := w.Poll()
+			if err != nil {
+				logger.WithError(err).Warn("Could not poll for changed bugs.")
+			}
+			for _, event := range polled {
+				select {
+				case events <- event:
+				case <-stop:
+					return
+				}
+			}
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return events
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/eparis/bugzilla"
+)
+
+// changeClient holds bugs and when they last changed, answering queries for
+// bugs changed since a time like the server does
+type changeClient struct {
+	bugzilla.Client
+	changed map[int]time.Time
+	queries []bugzilla.Query
+}
+
+func (c *changeClient) GetBugsModifiedSince(since time.Time, query bugzilla.Query) ([]*bugzilla.Bug, error) {
+	c.queries = append(c.queries, query)
+	var bugs []*bugzilla.Bug
+	for id, changed := range c.changed {
+		if !changed.Before(since) {
+			bugs = append(bugs, &bugzilla.Bug{ID: id, LastChangeTime: bugzilla.NewTimestamp(changed)})
+		}
+	}
+	sort.Slice(bugs, func(i, j int) bool {
+		if !bugs[i].LastChangeTime.Equal(bugs[j].LastChangeTime.Time) {
+			return bugs[i].LastChangeTime.Before(bugs[j].LastChangeTime.Time)
+		}
+		return bugs[i].ID < bugs[j].ID
+	})
+	return bugs, nil
+}
+
+func ids(events []Event) []int {
+	var ids []int
+	for _, event := range events {
+		ids = append(ids, event.Bug.ID)
+	}
+	return ids
+}
+
+func TestPoll(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events")
+	if err != nil {
+		t.Fatalf("could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	checkpoint := NewFileCheckpoint(filepath.Join(dir, "checkpoint.json"))
+
+	start := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	client := &changeClient{changed: map[int]time.Time{
+		1: start.Add(-time.Minute),
+		2: start.Add(time.Minute),
+		3: start,
+	}}
+	watcher := &Watcher{Client: client, Query: bugzilla.Query{Product: []string{"OCP"}, IncludeFields: []string{"id", "status"}}, Checkpoint: checkpoint, Since: start}
+
+	poll := func(expected []int) {
+		t.Helper()
+		events, err := watcher.Poll()
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if actual := ids(events); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("expected bugs %v to have changed, got %v", expected, actual)
+		}
+	}
+	poll([]int{3, 2})
+	if expected := []string{"id", "status"}; !reflect.DeepEqual(client.queries[0].IncludeFields, expected) {
+		t.Errorf("expected the query of the watcher to be used, got fields %v", client.queries[0].IncludeFields)
+	}
+	var state State
+	if err := checkpoint.Load(&state); err != nil || !state.Since.IsZero() {
+		t.Errorf("expected no checkpoint before the events were handled, got %v (error %v)", state, err)
+	}
+
+	client.changed[4] = start.Add(time.Minute)
+	poll([]int{4})
+	expected := State{Since: start.Add(time.Minute), Seen: []int{2}}
+	if err := checkpoint.Load(&state); err != nil || !reflect.DeepEqual(state, expected) {
+		t.Errorf("expected checkpoint %v, got %v (error %v)", expected, state, err)
+	}
+	poll(nil)
+
+	client.changed[1] = start.Add(2 * time.Minute)
+	resumed := &Watcher{Client: client, Checkpoint: checkpoint}
+	events, err := resumed.Poll()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if actual := ids(events); !reflect.DeepEqual(actual, []int{1}) {
+		t.Errorf("expected the resumed watcher to deliver bug 1, got %v", actual)
+	}
+}
+
+func TestRun(t *testing.T) {
+	start := time.Now().Add(-time.Hour)
+	client := &changeClient{changed: map[int]time.Time{1: start, 2: start.Add(time.Minute)}}
+	watcher := &Watcher{Client: client, Interval: time.Hour, Since: start}
+	stop := make(chan struct{})
+	events := watcher.Run(stop)
+	var received []int
+	for i := 0; i < 2; i++ {
+		received = append(received, (<-events).Bug.ID)
+	}
+	close(stop)
+	if _, open := <-events; open {
+		t.Error("expected the events to be closed when the watcher stops")
+	}
+	if !reflect.DeepEqual(received, []int{1, 2}) {
+		t.Errorf("expected bugs 1 and 2, got %v", received)
+	}
+}
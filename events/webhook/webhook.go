@@ -0,0 +1,232 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook receives the events Bugzilla pushes when bugs change, so
+// automation can react to changes instead of polling for them. The Handler
+// checks the signature of every delivery and dispatches it by type of event.
+//
+// Bugzilla itself does not sign its deliveries. The signature in the
+// SignatureHeader is this package's own protocol: deliveries must be signed
+// with Sign by a relay which receives them from Bugzilla on a trusted
+// network, or by whatever else sends them to the Handler.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/eparis/bugzilla"
+	"github.com/sirupsen/logrus"
+)
+
+// SignatureHeader is the header holding the signature of a delivery: the
+// hex-encoded HMAC-SHA256 of the body under the shared secret, prefixed with
+// "sha256=". It is not sent by Bugzilla, see the package documentation.
+const SignatureHeader = "X-Bugzilla-Signature"
+
+// maxPayloadSize is the size of the largest delivery which is accepted
+const maxPayloadSize = 10 << 20
+
+// Payload is the body of a delivery
+type Payload struct {
+	WebhookName string `json:"webhook_name"`
+	WebhookID   int    `json:"webhook_id"`
+	Event       Event  `json:"event"`
+	Bug         Bug    `json:"bug"`
+}
+
+// Event describes what happened
+type Event struct {
+	// Action is what happened to the target, like "create" or "modify".
+	Action string `json:"action"`
+	// Target is what the action happened to, like "bug" or "comment".
+	Target string `json:"target"`
+	// RoutingKey combines the target, action and changed fields, like
+	// "bug.modify:status".
+	RoutingKey string             `json:"routing_key"`
+	Time       bugzilla.Timestamp `json:"time"`
+	// User is who made the change.
+	User    User     `json:"user"`
+	Changes []Change `json:"changes,omitempty"`
+}
+
+// User is a user as described in deliveries
+type User struct {
+	ID       int    `json:"id"`
+	Login    string `json:"login"`
+	RealName string `json:"real_name"`
+}
+
+// Change is the change of one field of the bug
+type Change struct {
+	Field   string `json:"field"`
+	Removed string `json:"removed"`
+	Added   string `json:"added"`
+}
+
+// Bug is the state of the bug after the change
+type Bug struct {
+	ID             int                `json:"id"`
+	Alias          []string           `json:"alias,omitempty"`
+	Summary        string             `json:"summary"`
+	Product        string             `json:"product"`
+	Component      string             `json:"component"`
+	Version        string             `json:"version"`
+	Status         string             `json:"status"`
+	Resolution     string             `json:"resolution"`
+	Priority       string             `json:"priority"`
+	Severity       string             `json:"severity"`
+	Keywords       []string           `json:"keywords,omitempty"`
+	Whiteboard     string             `json:"whiteboard"`
+	AssignedTo     User               `json:"assigned_to"`
+	QAContact      *User              `json:"qa_contact,omitempty"`
+	Reporter       User               `json:"reporter"`
+	IsPrivate      bool               `json:"is_private"`
+	CreationTime   bugzilla.Timestamp `json:"creation_time"`
+	LastChangeTime bugzilla.Timestamp `json:"last_change_time"`
+	// Comment is the comment which was added, for comment events.
+	Comment *Comment `json:"comment,omitempty"`
+}
+
+// Comment is a comment as described in deliveries
+type Comment struct {
+	ID           int                `json:"id"`
+	Number       int                `json:"number"`
+	Body         string             `json:"body"`
+	IsPrivate    bool               `json:"is_private"`
+	CreationTime bugzilla.Timestamp `json:"creation_time"`
+}
+
+// BugCreated is dispatched when a bug was filed
+type BugCreated struct {
+	Payload
+}
+
+// BugModified is dispatched when fields of a bug changed
+type BugModified struct {
+	Payload
+}
+
+// CommentAdded is dispatched when a comment was added to a bug
+type CommentAdded struct {
+	Payload
+	Comment Comment
+}
+
+// Handler receives deliveries and dispatches them to the function for their
+// type of event. Events without a function are acknowledged and dropped. If a
+// function fails, the delivery is answered with a server error, so Bugzilla
+// delivers it again later.
+type Handler struct {
+	// Secret returns the shared secret deliveries are signed with. It is
+	// called for every delivery, so the secret can be rotated. All deliveries
+	// are rejected while it is nil or returns an empty secret.
+	Secret func() []byte
+
+	BugCreated   func(BugCreated) error
+	BugModified  func(BugModified) error
+	CommentAdded func(CommentAdded) error
+
+	// Logger logs deliveries which were rejected or failed, logrus' standard
+	// logger if nil.
+	Logger *logrus.Entry
+}
+
+// Sign returns the value of the SignatureHeader for the body
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// validSignature checks the signature in constant time
+func validSignature(secret, body []byte, signature string) bool {
+	if len(secret) == 0 || !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+func (h *Handler) logger() *logrus.Entry {
+	if h.Logger == nil {
+		return logrus.NewEntry(logrus.StandardLogger())
+	}
+	return h.Logger
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "405 Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil {
+		h.logger().WithError(err).Warn("Could not read webhook delivery.")
+		http.Error(w, "400 Bad Request", http.StatusBadRequest)
+		return
+	}
+	if h.Secret == nil {
+		h.logger().Error("Rejected webhook delivery since no secret is configured.")
+		http.Error(w, "401 Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !validSignature(h.Secret(), body, r.Header.Get(SignatureHeader)) {
+		h.logger().Warn("Rejected webhook delivery with an invalid signature.")
+		http.Error(w, "401 Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	var payload Payload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		h.logger().WithError(err).Warn("Could not unmarshal webhook delivery.")
+		http.Error(w, "400 Bad Request", http.StatusBadRequest)
+		return
+	}
+	logger := h.logger().WithFields(logrus.Fields{"webhook": payload.WebhookName, "id": payload.Bug.ID, "routing_key": payload.Event.RoutingKey})
+	if err := h.dispatch(payload); err != nil {
+		logger.WithError(err).Error("Could not handle webhook delivery.")
+		http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// dispatch calls the function for the type of the event, if any
+func (h *Handler) dispatch(payload Payload) error {
+	switch {
+	case payload.Event.Target == "bug" && payload.Event.Action == "create":
+		if h.BugCreated != nil {
+			return h.BugCreated(BugCreated{Payload: payload})
+		}
+	case payload.Event.Target == "bug" && payload.Event.Action == "modify":
+		if h.BugModified != nil {
+			return h.BugModified(BugModified{Payload: payload})
+		}
+	case payload.Event.Target == "comment" && payload.Event.Action == "create":
+		if payload.Bug.Comment == nil {
+			return fmt.Errorf("comment event for bug %d has no comment", payload.Bug.ID)
+		}
+		if h.CommentAdded != nil {
+			return h.CommentAdded(CommentAdded{Payload: payload, Comment: *payload.Bug.Comment})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eparis/bugzilla"
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+const (
+	createdPayload  = `{"webhook_name":"bots","webhook_id":1,"event":{"action":"create","target":"bug","routing_key":"bug.create","time":"2020-06-01T12:00:00Z","user":{"id":1,"login":"qe@example.com","real_name":"QE"}},"bug":{"id":1,"summary":"router drops connections","product":"OpenShift Container Platform","component":"Routing","status":"NEW","assigned_to":{"id":2,"login":"dev@example.com","real_name":"Dev"}}}`
+	modifiedPayload = `{"webhook_name":"bots","webhook_id":1,"event":{"action":"modify","target":"bug","routing_key":"bug.modify:status","user":{"id":2,"login":"dev@example.com"},"changes":[{"field":"status","removed":"NEW","added":"ASSIGNED"}]},"bug":{"id":1,"status":"ASSIGNED"}}`
+	commentPayload  = `{"webhook_name":"bots","webhook_id":1,"event":{"action":"create","target":"comment","routing_key":"comment.create","user":{"id":2,"login":"dev@example.com"}},"bug":{"id":1,"comment":{"id":10,"number":1,"body":"looking into it"}}}`
+	unknownPayload  = `{"webhook_name":"bots","webhook_id":1,"event":{"action":"create","target":"attachment","routing_key":"attachment.create"},"bug":{"id":1}}`
+)
+
+func TestHandler(t *testing.T) {
+	secret := []byte("secret")
+	var dispatched []interface{}
+	var failure error
+	handler := &Handler{
+		Secret: func() []byte { return secret },
+		BugCreated: func(event BugCreated) error {
+			dispatched = append(dispatched, event)
+			return failure
+		},
+		BugModified: func(event BugModified) error {
+			dispatched = append(dispatched, event)
+			return failure
+		},
+		CommentAdded: func(event CommentAdded) error {
+			dispatched = append(dispatched, event)
+			return failure
+		},
+	}
+	var testCases = []struct {
+		name           string
+		method         string
+		body           string
+		signature      string
+		failure        error
+		expectedStatus int
+		expected       []interface{}
+	}{
+		{
+			name:           "bug created",
+			body:           createdPayload,
+			expectedStatus: http.StatusOK,
+			expected: []interface{}{BugCreated{Payload: Payload{
+				WebhookName: "bots",
+				WebhookID:   1,
+				Event: Event{
+					Action:     "create",
+					Target:     "bug",
+					RoutingKey: "bug.create",
+					Time:       bugzilla.NewTimestamp(time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)),
+					User:       User{ID: 1, Login: "qe@example.com", RealName: "QE"},
+				},
+				Bug: Bug{ID: 1, Summary: "router drops connections", Product: "OpenShift Container Platform", Component: "Routing", Status: "NEW", AssignedTo: User{ID: 2, Login: "dev@example.com", RealName: "Dev"}},
+			}}},
+		},
+		{
+			name:           "bug modified",
+			body:           modifiedPayload,
+			expectedStatus: http.StatusOK,
+			expected: []interface{}{BugModified{Payload: Payload{
+				WebhookName: "bots",
+				WebhookID:   1,
+				Event: Event{
+					Action:     "modify",
+					Target:     "bug",
+					RoutingKey: "bug.modify:status",
+					User:       User{ID: 2, Login: "dev@example.com"},
+					Changes:    []Change{{Field: "status", Removed: "NEW", Added: "ASSIGNED"}},
+				},
+				Bug: Bug{ID: 1, Status: "ASSIGNED"},
+			}}},
+		},
+		{
+			name:           "comment added",
+			body:           commentPayload,
+			expectedStatus: http.StatusOK,
+			expected: []interface{}{CommentAdded{
+				Payload: Payload{
+					WebhookName: "bots",
+					WebhookID:   1,
+					Event:       Event{Action: "create", Target: "comment", RoutingKey: "comment.create", User: User{ID: 2, Login: "dev@example.com"}},
+					Bug:         Bug{ID: 1, Comment: &Comment{ID: 10, Number: 1, Body: "looking into it"}},
+				},
+				Comment: Comment{ID: 10, Number: 1, Body: "looking into it"},
+			}},
+		},
+		{
+			name:           "unknown events are acknowledged",
+			body:           unknownPayload,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid signatures are rejected",
+			body:           createdPayload,
+			signature:      Sign([]byte("other"), []byte(createdPayload)),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "missing signatures are rejected",
+			body:           createdPayload,
+			signature:      "none",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "malformed payloads are rejected",
+			body:           `{"event":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "only deliveries are accepted",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "failures are reported for redelivery",
+			body:           modifiedPayload,
+			failure:        errors.New("oops"),
+			expectedStatus: http.StatusInternalServerError,
+			expected: []interface{}{BugModified{Payload: Payload{
+				WebhookName: "bots",
+				WebhookID:   1,
+				Event: Event{
+					Action:     "modify",
+					Target:     "bug",
+					RoutingKey: "bug.modify:status",
+					User:       User{ID: 2, Login: "dev@example.com"},
+					Changes:    []Change{{Field: "status", Removed: "NEW", Added: "ASSIGNED"}},
+				},
+				Bug: Bug{ID: 1, Status: "ASSIGNED"},
+			}}},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dispatched, failure = nil, testCase.failure
+			method := testCase.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, "/", strings.NewReader(testCase.body))
+			signature := testCase.signature
+			if signature == "" {
+				signature = Sign(secret, []byte(testCase.body))
+			}
+			if signature != "none" {
+				req.Header.Set(SignatureHeader, signature)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			if recorder.Code != testCase.expectedStatus {
+				t.Errorf("%s: expected status %d, got %d", testCase.name, testCase.expectedStatus, recorder.Code)
+			}
+			if !reflect.DeepEqual(dispatched, testCase.expected) {
+				t.Errorf("%s: got incorrect events: %v", testCase.name, diff.ObjectReflectDiff(testCase.expected, dispatched))
+			}
+		})
+	}
+}
+
+func TestEmptySecretRejectsAll(t *testing.T) {
+	for _, handler := range []*Handler{{Secret: func() []byte { return nil }}, {}} {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(createdPayload))
+		req.Header.Set(SignatureHeader, Sign(nil, []byte(createdPayload)))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusUnauthorized {
+			t.Errorf("expected deliveries to be rejected without a secret, got status %d", recorder.Code)
+		}
+	}
+}
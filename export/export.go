@@ -0,0 +1,180 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package export streams the bugs matching a search to CSV or JSON Lines, to
+// land snapshots of Bugzilla in spreadsheets and data lakes, and serializes
+// single bugs as YAML.
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/eparis/bugzilla"
+)
+
+// Format is the format bugs are exported in
+type Format string
+
+const (
+	// CSV writes a header with the columns and a row for every bug. Lists
+	// are joined with commas and objects are written as JSON.
+	CSV Format = "csv"
+	// JSONLines writes a JSON object holding the columns for every bug, one
+	// per line.
+	JSONLines Format = "jsonl"
+)
+
+// DefaultColumns are the columns exported if none are configured
+var DefaultColumns = []string{"id", "summary", "status", "resolution", "severity", "priority", "component", "assigned_to", "last_change_time"}
+
+// bugFields are the JSON names of the fields of bugzilla.Bug
+var bugFields = func() map[string]bool {
+	fields := map[string]bool{}
+	bug := reflect.TypeOf(bugzilla.Bug{})
+	for i := 0; i < bug.NumField(); i++ {
+		if name := strings.Split(bug.Field(i).Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+}()
+
+// Exporter exports the bugs matching searches
+type Exporter struct {
+	Client bugzilla.Client
+	// Columns are the fields of the bugs to export by their JSON names, like
+	// "assigned_to" or custom fields like "cf_doc_type", DefaultColumns if
+	// empty. Fields a bug does not have or which are at their zero value,
+	// like false or 0, are exported as an empty cell or null.
+	Columns []string
+}
+
+// Export writes the bugs matching the query in the format as they are
+// retrieved one page at a time, so large searches are not held in memory.
+// Only the columns are requested from the server. It returns the number of
+// bugs written, which were written even if an error is returned.
+func (e *Exporter) Export(w io.Writer, format Format, query bugzilla.Query) (int, error) {
+	columns := e.Columns
+	if len(columns) == 0 {
+		columns = DefaultColumns
+	}
+	for _, column := range columns {
+		if !bugFields[column] && !strings.HasPrefix(column, "cf_") {
+			return 0, fmt.Errorf("unknown column %q", column)
+		}
+	}
+	var write func(fields map[string]json.RawMessage) error
+	var flush func() error
+	switch format {
+	case CSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(columns); err != nil {
+			return 0, err
+		}
+		write = func(fields map[string]json.RawMessage) error {
+			row := make([]string, 0, len(columns))
+			for _, column := range columns {
+				row = append(row, cell(fields[column]))
+			}
+			return writer.Write(row)
+		}
+		flush = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+	case JSONLines:
+		write = func(fields map[string]json.RawMessage) error {
+			return writeLine(w, columns, fields)
+		}
+		flush = func() error { return nil }
+	default:
+		return 0, fmt.Errorf("unknown format %q, expected %s or %s", format, CSV, JSONLines)
+	}
+
+	query.IncludeFields = columns
+	iter := e.Client.SearchBugsIter(query)
+	written := 0
+	for iter.Next() {
+		raw, err := json.Marshal(iter.Bug())
+		if err != nil {
+			return written, fmt.Errorf("could not marshal bug %d: %v", iter.Bug().ID, err)
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return written, fmt.Errorf("could not unmarshal bug %d: %v", iter.Bug().ID, err)
+		}
+		if err := write(fields); err != nil {
+			return written, err
+		}
+		written++
+	}
+	if err := flush(); err != nil {
+		return written, err
+	}
+	return written, iter.Err()
+}
+
+// cell formats a field of a bug for a CSV cell
+func cell(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return text
+	}
+	var list []json.RawMessage
+	if json.Unmarshal(raw, &list) == nil {
+		cells := make([]string, 0, len(list))
+		for _, item := range list {
+			cells = append(cells, cell(item))
+		}
+		return strings.Join(cells, ",")
+	}
+	return string(raw)
+}
+
+// writeLine writes the columns of the bug as a JSON object in the order of
+// the columns
+func writeLine(w io.Writer, columns []string, fields map[string]json.RawMessage) error {
+	var line bytes.Buffer
+	line.WriteByte('{')
+	for i, column := range columns {
+		if i > 0 {
+			line.WriteByte(',')
+		}
+		key, err := json.Marshal(column)
+		if err != nil {
+			return err
+		}
+		value := fields[column]
+		if len(value) == 0 {
+			value = json.RawMessage("null")
+		}
+		line.Write(key)
+		line.WriteByte(':')
+		line.Write(value)
+	}
+	line.WriteString("}\n")
+	_, err := w.Write(line.Bytes())
+	return err
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eparis/bugzilla"
+)
+
+func TestExport(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fields := r.URL.Query().Get("include_fields"); fields != "id,summary,component,assigned_to_detail,cf_doc_type" {
+			t.Errorf("expected only the columns to be requested, got %q", fields)
+		}
+		if r.URL.Query().Get("offset") != "0" {
+			w.Write([]byte(`{"bugs":[]}`))
+			return
+		}
+		w.Write([]byte(`{"bugs":[{"id":1,"summary":"Pods lose \"connectivity\", again","component":["Networking","ovn"],"assigned_to_detail":{"email":"dev@example.com"},"cf_doc_type":"Bug Fix"},{"id":2,"summary":"Installer hangs","component":["Installer"]}]}`))
+	}))
+	defer testServer.Close()
+	exporter := &Exporter{
+		Client:  bugzilla.NewClient(nil, testServer.URL, bugzilla.WithHTTPClient(testServer.Client())),
+		Columns: []string{"id", "summary", "component", "assigned_to_detail", "cf_doc_type"},
+	}
+
+	var testCases = []struct {
+		format   Format
+		expected string
+	}{
+		{
+			format: CSV,
+			expected: `id,summary,component,assigned_to_detail,cf_doc_type
+1,"Pods lose ""connectivity"", again","Networking,ovn","{""email"":""dev@example.com""}",Bug Fix
+2,Installer hangs,Installer,,
+`,
+		},
+		{
+			format: JSONLines,
+			expected: `{"id":1,"summary":"Pods lose \"connectivity\", again","component":["Networking","ovn"],"assigned_to_detail":{"email":"dev@example.com"},"cf_doc_type":"Bug Fix"}
+{"id":2,"summary":"Installer hangs","component":["Installer"],"assigned_to_detail":null,"cf_doc_type":null}
+`,
+		},
+	}
+	for _, testCase := range testCases {
+		var out bytes.Buffer
+		written, err := exporter.Export(&out, testCase.format, bugzilla.Query{Product: []string{"OCP"}})
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", testCase.format, err)
+		}
+		if written != 2 {
+			t.Errorf("%s: expected 2 bugs to be written, got %d", testCase.format, written)
+		}
+		if out.String() != testCase.expected {
+			t.Errorf("%s: expected output\n%s\ngot\n%s", testCase.format, testCase.expected, out.String())
+		}
+	}
+
+	if _, err := exporter.Export(&bytes.Buffer{}, "xml", bugzilla.Query{}); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+	if _, err := (&Exporter{Client: exporter.Client, Columns: []string{"owner"}}).Export(&bytes.Buffer{}, CSV, bugzilla.Query{}); err == nil {
+		t.Error("expected an error for an unknown column")
+	}
+}
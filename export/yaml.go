@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/eparis/bugzilla"
+	"gopkg.in/yaml.v2"
+)
+
+// customFieldPrefix is the prefix of the names of custom fields
+const customFieldPrefix = "cf_"
+
+// yamlBug is a bug with its custom fields next to the other fields, like in
+// the JSON the server sends
+type yamlBug struct {
+	bugzilla.Bug `yaml:",inline"`
+	CustomFields map[string]interface{} `yaml:",inline"`
+}
+
+// MarshalBugYAML serializes the bug as YAML with the same field names and
+// values as the JSON the server sends, including its custom fields, e.g. for
+// snapshots of bugs in configuration or reports. The bug can be read back
+// with UnmarshalBugYAML.
+func MarshalBugYAML(bug *bugzilla.Bug) ([]byte, error) {
+	for field := range bug.CustomFields {
+		if bugFields[field] {
+			return nil, fmt.Errorf("could not marshal bug %d as YAML: custom field %q is a field of the bug", bug.ID, field)
+		}
+	}
+	raw, err := yaml.Marshal(yamlBug{Bug: *bug, CustomFields: bug.CustomFields})
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal bug %d as YAML: %v", bug.ID, err)
+	}
+	return raw, nil
+}
+
+// UnmarshalBugYAML reads a bug serialized by MarshalBugYAML. Like for the
+// JSON of the server, fields which are not custom fields are ignored and the
+// values of custom fields are decoded like JSON values.
+func UnmarshalBugYAML(raw []byte) (*bugzilla.Bug, error) {
+	var decoded yamlBug
+	if err := yaml.Unmarshal(raw, &decoded); err != nil {
+		return nil, fmt.Errorf("could not unmarshal bug from YAML: %v", err)
+	}
+	bug := decoded.Bug
+	for name, value := range decoded.CustomFields {
+		if !strings.HasPrefix(name, customFieldPrefix) {
+			continue
+		}
+		value, err := jsonValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("could not unmarshal custom field %q: %v", name, err)
+		}
+		if bug.CustomFields == nil {
+			bug.CustomFields = map[string]interface{}{}
+		}
+		bug.CustomFields[name] = value
+	}
+	return &bug, nil
+}
+
+// jsonValue converts a value decoded from YAML into the value decoding it
+// from JSON gives, e.g. with float64 numbers and string keyed objects
+func jsonValue(value interface{}) (interface{}, error) {
+	raw, err := json.Marshal(stringKeys(value))
+	if err != nil {
+		return nil, err
+	}
+	var converted interface{}
+	if err := json.Unmarshal(raw, &converted); err != nil {
+		return nil, err
+	}
+	return converted, nil
+}
+
+// stringKeys replaces the maps YAML decodes objects into, which JSON can not
+// encode, with string keyed maps
+func stringKeys(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		converted := map[string]interface{}{}
+		for key, item := range value {
+			converted[fmt.Sprint(key)] = stringKeys(item)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(value))
+		for i, item := range value {
+			converted[i] = stringKeys(item)
+		}
+		return converted
+	default:
+		return value
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eparis/bugzilla"
+	yamlv2 "gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func mustParseTimestamp(value string) bugzilla.Timestamp {
+	t, err := bugzilla.ParseTimestamp(value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestBugYAMLRoundTrip(t *testing.T) {
+	// empty lists are left out, so they are read back as nil
+	bug := bugzilla.Bug{
+		ID:               1705243,
+		AssignedTo:       "Steve Kuznetsov",
+		AssignedToDetail: &bugzilla.User{Email: "skuznets", ID: 381851, Name: "skuznets", RealName: "Steve Kuznetsov"},
+		Component:        []string{"Test Infrastructure"},
+		CreationTime:     mustParseTimestamp("2019-05-01T19:33:36Z"),
+		LastChangeTime:   mustParseTimestamp("2019-05-17T15:13:13Z"),
+		IsOpen:           true,
+		Status:           "VERIFIED",
+		TargetRelease:    []string{"3.11.z"},
+		ExternalBugs:     []bugzilla.ExternalBug{{Type: bugzilla.ExternalBugType{URL: "https://github.com/"}, BugzillaBugID: 1705243, ExternalBugID: "org/repo/pull/1"}},
+		CustomFields: map[string]interface{}{
+			"cf_doc_type":      "Bug Fix",
+			"cf_story_points":  float64(3),
+			"cf_release_notes": map[string]interface{}{"values": []interface{}{"high", float64(2)}},
+		},
+	}
+
+	raw, err := MarshalBugYAML(&bug)
+	if err != nil {
+		t.Fatalf("expected no error marshalling the bug, got %v", err)
+	}
+	for _, expected := range []string{"cf_doc_type: Bug Fix\n", "last_change_time: \"2019-05-17T15:13:13Z\"\n", "ext_bz_bug_id: org/repo/pull/1\n"} {
+		if !strings.Contains(string(raw), expected) {
+			t.Errorf("expected the YAML to contain %q, got:\n%s", expected, raw)
+		}
+	}
+	roundTripped, err := UnmarshalBugYAML(raw)
+	if err != nil {
+		t.Fatalf("expected no error unmarshalling the bug, got %v", err)
+	}
+	if !reflect.DeepEqual(roundTripped, &bug) {
+		t.Errorf("bug changed in the round trip: %v", diff.ObjectReflectDiff(&bug, roundTripped))
+	}
+}
+
+func TestBugYAMLTags(t *testing.T) {
+	bug := bugzilla.Bug{
+		ID:               1,
+		AssignedToDetail: &bugzilla.User{Email: "dev@example.com", RealName: "Dev"},
+		CreationTime:     mustParseTimestamp("2019-05-01T19:33:36Z"),
+		Flags:            []bugzilla.Flag{{Name: "needinfo", Status: "?"}},
+		TargetRelease:    []string{"4.6.0"},
+	}
+	raw, err := yamlv2.Marshal(bug)
+	if err != nil {
+		t.Fatalf("expected no error marshalling the bug, got %v", err)
+	}
+	for _, expected := range []string{"assigned_to_detail:\n", "  real_name: Dev\n", "creation_time: \"2019-05-01T19:33:36Z\"\n", "target_release:\n"} {
+		if !strings.Contains(string(raw), expected) {
+			t.Errorf("expected the YAML to contain %q, got:\n%s", expected, raw)
+		}
+	}
+	if strings.Contains(string(raw), "last_change_time") {
+		t.Errorf("expected empty fields to be left out, got:\n%s", raw)
+	}
+	var roundTripped bugzilla.Bug
+	if err := yamlv2.Unmarshal(raw, &roundTripped); err != nil {
+		t.Fatalf("expected no error unmarshalling the bug, got %v", err)
+	}
+	if !reflect.DeepEqual(roundTripped, bug) {
+		t.Errorf("bug changed in the round trip: %v", diff.ObjectReflectDiff(bug, roundTripped))
+	}
+}
+
+func TestMarshalBugYAMLRejectsCustomFieldsOfTheBug(t *testing.T) {
+	bug := bugzilla.Bug{ID: 1, CustomFields: map[string]interface{}{"cf_pm_score": "100"}}
+	if _, err := MarshalBugYAML(&bug); err == nil {
+		t.Error("expected an error for a custom field which is a field of the bug")
+	}
+}
+
+func TestUnmarshalHandWrittenBugYAML(t *testing.T) {
+	raw := []byte(`id: 1
+version:
+- 4.10
+cf_story_points: 3
+cf_release_notes:
+  values: [high]
+not_a_field: ignored
+`)
+	bug, err := UnmarshalBugYAML(raw)
+	if err != nil {
+		t.Fatalf("expected no error unmarshalling the bug, got %v", err)
+	}
+	expected := &bugzilla.Bug{
+		ID:      1,
+		Version: []string{"4.10"},
+		CustomFields: map[string]interface{}{
+			"cf_story_points":  float64(3),
+			"cf_release_notes": map[string]interface{}{"values": []interface{}{"high"}},
+		},
+	}
+	if !reflect.DeepEqual(bug, expected) {
+		t.Errorf("got incorrect bug: %v", diff.ObjectReflectDiff(expected, bug))
+	}
+}
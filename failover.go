@@ -0,0 +1,95 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// WithSecondaryEndpoints configures endpoints of read replicas of the primary
+// Bugzilla server. Read-only calls which fail on the primary because it is
+// unavailable, i.e. with a server error, a transport error, exhausted retries
+// or an open circuit breaker, are sent to the secondaries in the given order
+// until one succeeds. Writes and calls sent with POST, like JSONRPC calls, are
+// only ever sent to the primary, as are calls which fail for other reasons.
+func WithSecondaryEndpoints(endpoints ...string) Option {
+	return func(c *client) {
+		c.secondaries = nil
+		for _, endpoint := range endpoints {
+			c.secondaries = append(c.secondaries, strings.TrimSuffix(endpoint, "/"))
+		}
+	}
+}
+
+// shouldFailover determines if a request which failed with the error should
+// be sent to a secondary endpoint
+func shouldFailover(err error) bool {
+	return isRetryable(err) || IsRetryExhausted(err) || IsCircuitOpen(err)
+}
+
+// requestWithFailover sends the request to the primary endpoint and, if the
+// request is read-only and the primary is unavailable, to the secondaries
+func (c *client) requestWithFailover(req *http.Request, logger *logEntry) ([]byte, error) {
+	raw, err := c.requestWithRetries(req, logger)
+	if err == nil || len(c.secondaries) == 0 || req.Method != http.MethodGet || !shouldFailover(err) {
+		return raw, err
+	}
+	for _, endpoint := range c.secondaries {
+		secondaryReq, rewriteErr := c.forEndpoint(req, endpoint)
+		if rewriteErr != nil {
+			logger.WithError(rewriteErr).WithField("endpoint", endpoint).Warn("Could not send request to secondary endpoint.")
+			continue
+		}
+		secondaryLogger := logger.WithField("endpoint", endpoint)
+		secondaryLogger.WithError(err).Debug("Primary endpoint is unavailable, failing over to secondary endpoint.")
+		c.metrics.failedOver(logger.Data[methodField].(string))
+		secondaryRaw, secondaryErr := c.doRequest(secondaryReq, secondaryLogger)
+		if secondaryErr == nil {
+			return secondaryRaw, nil
+		}
+		if !shouldFailover(secondaryErr) {
+			return nil, secondaryErr
+		}
+		secondaryLogger.WithError(secondaryErr).Debug("Secondary endpoint is unavailable.")
+	}
+	return nil, err
+}
+
+// forEndpoint returns a copy of the request to the primary endpoint which is
+// sent to the other endpoint instead, keeping the path below the endpoint, the
+// query and the headers
+func (c *client) forEndpoint(req *http.Request, endpoint string) (*http.Request, error) {
+	primary, err := url.Parse(c.endpoint)
+	if err != nil {
+		return nil, err
+	}
+	secondary, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	target := *req.URL
+	target.Scheme = secondary.Scheme
+	target.Host = secondary.Host
+	target.Path = strings.TrimSuffix(secondary.Path, "/") + strings.TrimPrefix(req.URL.Path, strings.TrimSuffix(primary.Path, "/"))
+	target.RawPath = ""
+	secondaryReq := req.Clone(req.Context())
+	secondaryReq.URL = &target
+	secondaryReq.Host = ""
+	return secondaryReq, nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFailover(t *testing.T) {
+	var testCases = []struct {
+		name              string
+		primaryStatus     int
+		secondaryStatus   int
+		write             bool
+		expectedErr       bool
+		expectedSecondary int
+	}{
+		{
+			name:          "healthy primary serves reads",
+			primaryStatus: http.StatusOK,
+		},
+		{
+			name:              "reads fail over when the primary is unavailable",
+			primaryStatus:     http.StatusServiceUnavailable,
+			secondaryStatus:   http.StatusOK,
+			expectedSecondary: 1,
+		},
+		{
+			name:          "reads do not fail over on client errors",
+			primaryStatus: http.StatusNotFound,
+			expectedErr:   true,
+		},
+		{
+			name:              "reads fail when the secondary is unavailable too",
+			primaryStatus:     http.StatusServiceUnavailable,
+			secondaryStatus:   http.StatusServiceUnavailable,
+			expectedErr:       true,
+			expectedSecondary: 1,
+		},
+		{
+			name:            "writes never fail over",
+			primaryStatus:   http.StatusServiceUnavailable,
+			secondaryStatus: http.StatusOK,
+			write:           true,
+			expectedErr:     true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			primary := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if testCase.primaryStatus != http.StatusOK {
+					http.Error(w, http.StatusText(testCase.primaryStatus), testCase.primaryStatus)
+					return
+				}
+				w.Write(bugData)
+			}))
+			defer primary.Close()
+			secondaryRequests := 0
+			secondary := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				secondaryRequests++
+				if r.Method != http.MethodGet {
+					t.Errorf("%s: expected only reads on the secondary, got %s", testCase.name, r.Method)
+				}
+				if r.URL.Path != "/bugzilla/rest/bug/1705243" {
+					t.Errorf("%s: incorrect path on the secondary: %s", testCase.name, r.URL.Path)
+				}
+				if r.Header.Get("X-BUGZILLA-API-KEY") != "api-key" {
+					t.Errorf("%s: expected the API key to be sent to the secondary", testCase.name)
+				}
+				if testCase.secondaryStatus != http.StatusOK {
+					http.Error(w, http.StatusText(testCase.secondaryStatus), testCase.secondaryStatus)
+					return
+				}
+				w.Write(bugData)
+			}))
+			defer secondary.Close()
+			c := clientForUrl(primary.URL).(*client)
+			WithSecondaryEndpoints(secondary.URL + "/bugzilla/")(c)
+
+			var err error
+			if testCase.write {
+				err = c.UpdateBug(1705243, BugUpdate{Status: "MODIFIED"})
+			} else {
+				var bug *Bug
+				bug, err = c.GetBug(1705243)
+				if err == nil && bug.ID != 1705243 {
+					t.Errorf("%s: got the wrong bug: %d", testCase.name, bug.ID)
+				}
+			}
+			if testCase.expectedErr != (err != nil) {
+				t.Errorf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+			}
+			if secondaryRequests != testCase.expectedSecondary {
+				t.Errorf("%s: expected %d requests to the secondary, got %d", testCase.name, testCase.expectedSecondary, secondaryRequests)
+			}
+		})
+	}
+}
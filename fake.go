@@ -17,19 +17,87 @@ limitations under the License.
 package bugzilla
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
-
-	"k8s.io/apimachinery/pkg/util/sets"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Fake is a fake Bugzilla client with injectable fields
 type Fake struct {
 	EndpointString string
+	VersionString  string
 	Bugs           map[int]Bug
-	BugErrors      sets.Int
+	BugComments    map[int][]Comment
+	BugErrors      map[int]bool
+	BugHistory     map[int][]History
+	BugAttachments map[int][]Attachment
+	// AttachmentData holds the data of the attachments, keyed by attachment ID.
+	AttachmentData map[int][]byte
 	ExternalBugs   map[int][]ExternalBug
+	Products       map[string]Product
+	// TargetReleases are the target releases of the products, keyed by product.
+	// Products without an entry accept any target release.
+	TargetReleases map[string][]string
+	// CurrentUser is the user the fake is authenticated as, calls needing it
+	// fail as unauthorized if it is not set.
+	CurrentUser *User
+	Users       []User
+	// Groups are the groups visible to the current user.
+	Groups []Group
+	// Fields are the metadata of the bug fields.
+	Fields []Field
+	// ExternalTrackerTypes are the external bug trackers configured on the server.
+	ExternalTrackerTypes []ExternalBugType
+	// Responses are the responses to Do, keyed by the method and path of the
+	// request, like "GET /rest/bug/1/flag_types".
+	Responses map[string]interface{}
+	// Simulation, if set, makes calls slow or fail like a struggling server.
+	Simulation *Simulation
+}
+
+// simulate applies the simulation, if any, to a call to the method
+func (c *Fake) simulate(method string) error {
+	if c.Simulation == nil {
+		return nil
+	}
+	return c.Simulation.call(method)
+}
+
+// unsimulated returns the fake without the simulation, for calls which the
+// fake makes itself while handling a call which was already simulated. The
+// maps holding the state of the fake are allocated first, so the returned
+// fake shares them and the bugs, comments and attachments it adds reach the
+// fake.
+func (c *Fake) unsimulated() *Fake {
+	c.allocateState()
+	unsimulated := *c
+	unsimulated.Simulation = nil
+	return &unsimulated
+}
+
+// allocateState allocates the maps of the state which calls add to
+func (c *Fake) allocateState() {
+	if c.Bugs == nil {
+		c.Bugs = map[int]Bug{}
+	}
+	if c.BugComments == nil {
+		c.BugComments = map[int][]Comment{}
+	}
+	if c.BugAttachments == nil {
+		c.BugAttachments = map[int][]Attachment{}
+	}
+	if c.AttachmentData == nil {
+		c.AttachmentData = map[int][]byte{}
+	}
+	if c.ExternalBugs == nil {
+		c.ExternalBugs = map[int][]ExternalBug{}
+	}
 }
 
 func (c *Fake) WithCGIClient(user, password string) Client {
@@ -45,32 +113,192 @@ func (c *Fake) Endpoint() string {
 	return c.EndpointString
 }
 
+// GetVersion returns the version for this fake
+func (c *Fake) GetVersion() (string, error) {
+	if err := c.simulate("GetVersion"); err != nil {
+		return "", err
+	}
+	return c.VersionString, nil
+}
+
 // GetBug retrieves the bug, if registered, or an error, if set,
 // or responds with an error that matches IsNotFound
 func (c *Fake) GetBug(id int) (*Bug, error) {
-	if c.BugErrors.Has(id) {
+	if err := c.simulate("GetBug"); err != nil {
+		return nil, err
+	}
+	if c.BugErrors[id] {
 		return nil, errors.New("injected error getting bug")
 	}
 	if bug, exists := c.Bugs[id]; exists {
 		return &bug, nil
 	}
-	return nil, &requestError{statusCode: http.StatusNotFound, message: "bug not registered in the fake"}
+	return nil, &RequestError{StatusCode: http.StatusNotFound, Message: "bug not registered in the fake"}
+}
+
+// GetBugWithFields retrieves the bug just like GetBug does, the fields
+// are ignored and the full registered bug is returned
+func (c *Fake) GetBugWithFields(id int, fields []string) (*Bug, error) {
+	if err := c.simulate("GetBugWithFields"); err != nil {
+		return nil, err
+	}
+	return c.unsimulated().GetBug(id)
+}
+
+// GetBugs retrieves the registered bugs in the order of the IDs, or an error
+// if any of them is not registered. Like for GetBugWithFields, the fields are
+// ignored.
+func (c *Fake) GetBugs(ids []int, fields Fields) ([]*Bug, error) {
+	if err := c.simulate("GetBugs"); err != nil {
+		return nil, err
+	}
+	bugs := []*Bug{}
+	for _, id := range ids {
+		bug, err := c.unsimulated().GetBug(id)
+		if err != nil {
+			return nil, err
+		}
+		bugs = append(bugs, bug)
+	}
+	return bugs, nil
+}
+
+// GetBugByAlias retrieves the bug with the alias, if registered, or an
+// error, if set, or responds with an error that matches IsAliasNotFound
+func (c *Fake) GetBugByAlias(alias string) (*Bug, error) {
+	if err := c.simulate("GetBugByAlias"); err != nil {
+		return nil, err
+	}
+	id, err := c.aliasID(alias)
+	if err != nil {
+		return nil, err
+	}
+	return c.unsimulated().GetBug(id)
+}
+
+// aliasID returns the ID of the registered bug with the alias
+func (c *Fake) aliasID(alias string) (int, error) {
+	for id, bug := range c.Bugs {
+		for _, bugAlias := range bug.Alias {
+			if bugAlias == alias {
+				return id, nil
+			}
+		}
+	}
+	return 0, &AliasNotFoundError{Alias: alias, Err: &RequestError{StatusCode: http.StatusNotFound, Message: "bug not registered in the fake"}}
+}
+
+// BulkGetBugs retrieves the bugs just like GetBug does
+func (c *Fake) BulkGetBugs(ids []int, concurrency int) ([]*Bug, error) {
+	return bulkGetBugs(c.GetBug, ids, concurrency)
 }
 
-// GetBugComments retrieves the comments of a Bug from the server
-// https://bugzilla.readthedocs.io/en/latest/api/core/v1/comment.html#get-comments
+// GetBugComments retrieves the comments of the bug, if registered, or an
+// error, if set, or responds with an error that matches IsNotFound
 func (c *Fake) GetBugComments(id int) ([]Comment, error) {
-	panic("implement me")
+	if err := c.simulate("GetBugComments"); err != nil {
+		return nil, err
+	}
+	if c.BugErrors[id] {
+		return nil, errors.New("injected error getting bug comments")
+	}
+	if _, exists := c.Bugs[id]; exists {
+		return c.BugComments[id], nil
+	}
+	return nil, &RequestError{StatusCode: http.StatusNotFound, Message: "bug not registered in the fake"}
+}
+
+// GetBugDescription retrieves the text of the registered comment of the bug
+// with the lowest count
+func (c *Fake) GetBugDescription(id int) (string, error) {
+	if err := c.simulate("GetBugDescription"); err != nil {
+		return "", err
+	}
+	comments, err := c.unsimulated().GetBugComments(id)
+	if err != nil {
+		return "", err
+	}
+	description := firstComment(comments)
+	if description == nil {
+		return "", fmt.Errorf("bug %d has no description", id)
+	}
+	return description.Text, nil
 }
 
-// GetBugHistory retrieves the history of a Bug from the server
-// https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#bug-history
+// UpdateCommentTags updates the tags of the registered comment with the ID,
+// or responds with an error that matches IsNotFound
+func (c *Fake) UpdateCommentTags(commentID int, add, remove []string) ([]string, error) {
+	if err := c.simulate("UpdateCommentTags"); err != nil {
+		return nil, err
+	}
+	for id, comments := range c.BugComments {
+		for i := range comments {
+			if comments[i].Id != commentID {
+				continue
+			}
+			if c.BugErrors[id] {
+				return nil, errors.New("injected error updating comment tags")
+			}
+			comments[i].Tags = updateStrings(comments[i].Tags, add, remove, nil)
+			return comments[i].Tags, nil
+		}
+	}
+	return nil, &RequestError{StatusCode: http.StatusNotFound, Message: "comment not registered in the fake"}
+}
+
+// GetBugHistory retrieves the history of the bug, if registered, or an
+// error, if set, or responds with an error that matches IsNotFound
 func (c *Fake) GetBugHistory(id int) ([]History, error) {
-	panic("implement me")
+	if err := c.simulate("GetBugHistory"); err != nil {
+		return nil, err
+	}
+	if c.BugErrors[id] {
+		return nil, errors.New("injected error getting bug history")
+	}
+	if _, exists := c.Bugs[id]; exists {
+		return c.BugHistory[id], nil
+	}
+	return nil, &RequestError{StatusCode: http.StatusNotFound, Message: "bug not registered in the fake"}
+}
+
+// GetBugAttachments retrieves the attachments of the bug, if registered, or
+// an error, if set, or responds with an error that matches IsNotFound
+func (c *Fake) GetBugAttachments(id int) ([]Attachment, error) {
+	if err := c.simulate("GetBugAttachments"); err != nil {
+		return nil, err
+	}
+	if c.BugErrors[id] {
+		return nil, errors.New("injected error getting bug attachments")
+	}
+	if _, exists := c.Bugs[id]; exists {
+		return c.BugAttachments[id], nil
+	}
+	return nil, &RequestError{StatusCode: http.StatusNotFound, Message: "bug not registered in the fake"}
+}
+
+// GetAttachmentData retrieves the data of the attachment, if registered, or
+// responds with an error that matches IsNotFound
+func (c *Fake) GetAttachmentData(id int) ([]byte, error) {
+	if err := c.simulate("GetAttachmentData"); err != nil {
+		return nil, err
+	}
+	if data, exists := c.AttachmentData[id]; exists {
+		return data, nil
+	}
+	return nil, &RequestError{StatusCode: http.StatusNotFound, Message: "attachment not registered in the fake"}
+}
+
+// GetBugFull retrieves the bug and everything registered for it just like
+// the individual calls do
+func (c *Fake) GetBugFull(id int) (*FullBug, error) {
+	return getBugFull(c, id)
 }
 
 // Search doesn't really work, it always returns all bugs
 func (c *Fake) Search(query Query) ([]*Bug, error) {
+	if err := c.simulate("Search"); err != nil {
+		return nil, err
+	}
 	bugs := []*Bug{}
 	for i, _ := range c.Bugs {
 		bug := c.Bugs[i]
@@ -79,52 +307,719 @@ func (c *Fake) Search(query Query) ([]*Bug, error) {
 	return bugs, nil
 }
 
+// SearchBugsIter iterates over all bugs, just like Search it ignores the query
+func (c *Fake) SearchBugsIter(query Query) *BugIter {
+	var bugs []*Bug
+	return newBugIter(func(limit, offset int) ([]*Bug, error) {
+		if err := c.simulate("SearchBugsIter"); err != nil {
+			return nil, err
+		}
+		if bugs == nil {
+			var err error
+			if bugs, err = c.unsimulated().Search(query); err != nil {
+				return nil, err
+			}
+		}
+		if offset >= len(bugs) {
+			return nil, nil
+		}
+		return bugs[offset:], nil
+	})
+}
+
+// GetBugsModifiedSince returns the registered bugs which changed at or after
+// the time, ordered by when they last changed; like Search it ignores the query
+func (c *Fake) GetBugsModifiedSince(since time.Time, query Query) ([]*Bug, error) {
+	if err := c.simulate("GetBugsModifiedSince"); err != nil {
+		return nil, err
+	}
+	all, err := c.unsimulated().Search(query)
+	if err != nil {
+		return nil, err
+	}
+	var bugs []*Bug
+	for _, bug := range all {
+		if !bug.LastChangeTime.Before(since) {
+			bugs = append(bugs, bug)
+		}
+	}
+	sortByLastChange(bugs)
+	return bugs, nil
+}
+
+// SearchInto decodes all bugs into dest, just like Search it ignores the query
+func (c *Fake) SearchInto(query Query, dest interface{}) error {
+	if err := c.simulate("SearchInto"); err != nil {
+		return err
+	}
+	slice, err := searchDestination(dest)
+	if err != nil {
+		return err
+	}
+	bugs, err := c.unsimulated().Search(query)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(struct {
+		Bugs []*Bug `json:"bugs"`
+	}{Bugs: bugs})
+	if err != nil {
+		return err
+	}
+	_, err = appendDecodedBugs(raw, slice, json.Unmarshal)
+	return err
+}
+
 // GetExternalBugPRsOnBug retrieves the external bugs for the Bugzilla bug,
 // if registered, or an error, if set, or responds with an
 // error that matches IsNotFound. It filters them by Github PRs.
 func (c *Fake) GetExternalBugPRsOnBug(id int) ([]ExternalBug, error) {
-	if c.BugErrors.Has(id) {
+	if err := c.simulate("GetExternalBugPRsOnBug"); err != nil {
+		return nil, err
+	}
+	if c.BugErrors[id] {
 		return nil, errors.New("injected error adding external bug to bug")
 	}
 	if _, exists := c.Bugs[id]; exists {
 		return c.ExternalBugs[id], nil
 	}
-	return nil, &requestError{statusCode: http.StatusNotFound, message: "bug not registered in the fake"}
+	return nil, &RequestError{StatusCode: http.StatusNotFound, Message: "bug not registered in the fake"}
 }
 
 // GetExternalBugs retrieves the external bugs for the Bugzilla bug,
 // if registered, or an error, if set, or responds with an
 // error that matches IsNotFound.
 func (c *Fake) GetExternalBugs(id int) ([]ExternalBug, error) {
-	if c.BugErrors.Has(id) {
+	if err := c.simulate("GetExternalBugs"); err != nil {
+		return nil, err
+	}
+	if c.BugErrors[id] {
 		return nil, errors.New("injected error adding external bug to bug")
 	}
 	if _, exists := c.Bugs[id]; exists {
 		return c.ExternalBugs[id], nil
 	}
-	return nil, &requestError{statusCode: http.StatusNotFound, message: "bug not registered in the fake"}
+	return nil, &RequestError{StatusCode: http.StatusNotFound, Message: "bug not registered in the fake"}
+}
+
+// GetExternalBugsForBugs retrieves the external bugs for all of the bugs,
+// if all are registered, or an error, if set for any of them, or responds
+// with an error that matches IsNotFound.
+func (c *Fake) GetExternalBugsForBugs(ids []int) (map[int][]ExternalBug, error) {
+	if err := c.simulate("GetExternalBugsForBugs"); err != nil {
+		return nil, err
+	}
+	external := map[int][]ExternalBug{}
+	for _, id := range ids {
+		if c.BugErrors[id] {
+			return nil, errors.New("injected error getting external bugs")
+		}
+		if _, exists := c.Bugs[id]; !exists {
+			return nil, &RequestError{StatusCode: http.StatusNotFound, Message: "bug not registered in the fake"}
+		}
+		if len(c.ExternalBugs[id]) > 0 {
+			external[id] = c.ExternalBugs[id]
+		}
+	}
+	return external, nil
+}
+
+// GetExternalBugPRsOnBugs retrieves the external bugs for all of the bugs
+// like GetExternalBugsForBugs does, and returns those with identifiers of
+// pull requests in the allowed orgs or repos, or all of them if none are
+// allowed.
+func (c *Fake) GetExternalBugPRsOnBugs(ids []int, allowed ...string) (map[int][]ExternalBug, error) {
+	if err := c.simulate("GetExternalBugPRsOnBugs"); err != nil {
+		return nil, err
+	}
+	external, err := c.unsimulated().GetExternalBugsForBugs(ids)
+	if err != nil {
+		return nil, err
+	}
+	prs := map[int][]ExternalBug{}
+	for id, ebs := range external {
+		var pulls []ExternalBug
+		for _, bug := range ebs {
+			org, repo, num, err := PullFromIdentifier(bug.ExternalBugID)
+			if err != nil {
+				continue
+			}
+			bug.Org, bug.Repo, bug.Num = org, repo, num
+			pulls = append(pulls, bug)
+		}
+		if pulls = filterAllowedPRs(pulls, allowed); len(pulls) > 0 {
+			prs[id] = pulls
+		}
+	}
+	return prs, nil
+}
+
+// GetProduct returns the product, if registered, or responds with
+// an error that matches IsNotFound
+func (c *Fake) GetProduct(name string) (*Product, error) {
+	if err := c.simulate("GetProduct"); err != nil {
+		return nil, err
+	}
+	if product, exists := c.Products[name]; exists {
+		return &product, nil
+	}
+	return nil, &RequestError{StatusCode: http.StatusNotFound, Message: "product not registered in the fake"}
+}
+
+// GetProductSchema returns the schema of the product, if registered, or
+// responds with an error that matches IsNotFound
+func (c *Fake) GetProductSchema(product string) (*ProductSchema, error) {
+	if err := c.simulate("GetProductSchema"); err != nil {
+		return nil, err
+	}
+	metadata, exists := c.Products[product]
+	if !exists {
+		return nil, &RequestError{StatusCode: http.StatusNotFound, Message: "product not registered in the fake"}
+	}
+	return newProductSchema(&metadata, c.TargetReleases[product]), nil
+}
+
+// GetSubComponentsForComponent returns the sub-components of the component
+// of the product, if registered, or responds with an error that matches IsNotFound
+func (c *Fake) GetSubComponentsForComponent(product, component string) ([]string, error) {
+	if err := c.simulate("GetSubComponentsForComponent"); err != nil {
+		return nil, err
+	}
+	return subComponentsForComponent(c.unsimulated(), product, component)
+}
+
+// ListProducts returns all registered products ordered by name
+func (c *Fake) ListProducts() ([]Product, error) {
+	if err := c.simulate("ListProducts"); err != nil {
+		return nil, err
+	}
+	var products []Product
+	for _, product := range c.Products {
+		products = append(products, product)
+	}
+	sort.Slice(products, func(i, j int) bool {
+		return products[i].Name < products[j].Name
+	})
+	return products, nil
+}
+
+// GetCurrentUser returns the current user, if set, or responds with an
+// error that matches IsUnauthorized
+func (c *Fake) GetCurrentUser() (*User, error) {
+	if err := c.simulate("GetCurrentUser"); err != nil {
+		return nil, err
+	}
+	if c.CurrentUser == nil {
+		return nil, &RequestError{StatusCode: http.StatusUnauthorized, Message: "no current user set in the fake"}
+	}
+	user := *c.CurrentUser
+	return &user, nil
+}
+
+// SearchUsers returns the registered users whose login name, real name or
+// e-mail contains the match, ignoring case
+func (c *Fake) SearchUsers(match string) ([]User, error) {
+	if err := c.simulate("SearchUsers"); err != nil {
+		return nil, err
+	}
+	match = strings.ToLower(match)
+	var users []User
+	for _, user := range c.Users {
+		for _, value := range []string{user.Name, user.RealName, user.Email} {
+			if strings.Contains(strings.ToLower(value), match) {
+				users = append(users, user)
+				break
+			}
+		}
+	}
+	return users, nil
 }
 
-// UpdateBug updates the bug, if registered, or an error, if set,
+// GetUser returns the registered user, or the current user, with the ID or
+// login name, or responds with an error that matches IsNotFound
+func (c *Fake) GetUser(idOrLogin string) (*User, error) {
+	if err := c.simulate("GetUser"); err != nil {
+		return nil, err
+	}
+	users := c.Users
+	if c.CurrentUser != nil {
+		users = append([]User{*c.CurrentUser}, users...)
+	}
+	for _, user := range users {
+		if strconv.Itoa(user.ID) == idOrLogin || user.is(idOrLogin) {
+			return &user, nil
+		}
+	}
+	return nil, &RequestError{StatusCode: http.StatusNotFound, Message: "user not registered in the fake"}
+}
+
+// GetGroups returns the registered groups
+func (c *Fake) GetGroups() ([]Group, error) {
+	if err := c.simulate("GetGroups"); err != nil {
+		return nil, err
+	}
+	return c.Groups, nil
+}
+
+// GetExternalTrackerTypes returns the registered external bug trackers
+func (c *Fake) GetExternalTrackerTypes() ([]ExternalBugType, error) {
+	if err := c.simulate("GetExternalTrackerTypes"); err != nil {
+		return nil, err
+	}
+	return c.ExternalTrackerTypes, nil
+}
+
+// GetFields returns the registered field with the name, or all registered
+// fields if the name is empty, or responds with an error that matches
+// IsNotFound
+func (c *Fake) GetFields(fieldName string) ([]Field, error) {
+	if err := c.simulate("GetFields"); err != nil {
+		return nil, err
+	}
+	if fieldName == "" {
+		return c.Fields, nil
+	}
+	for _, field := range c.Fields {
+		if field.Name == fieldName {
+			return []Field{field}, nil
+		}
+	}
+	return nil, &RequestError{StatusCode: http.StatusNotFound, Message: "field not registered in the fake"}
+}
+
+// UpdateBug updates the bug and registers the comment added with the update,
+// if registered, or an error, if set,
 // or responds with an error that matches IsNotFound
 func (c *Fake) UpdateBug(id int, update BugUpdate) error {
-	if c.BugErrors.Has(id) {
+	if err := c.simulate("UpdateBug"); err != nil {
+		return err
+	}
+	if c.BugErrors[id] {
 		return errors.New("injected error updating bug")
 	}
 	if bug, exists := c.Bugs[id]; exists {
-		bug.Status = update.Status
-		bug.Resolution = update.Resolution
-		c.Bugs[id] = bug
+		if update.LastChangeTime != nil {
+			if !update.LastChangeTime.Equal(bug.LastChangeTime.Time) {
+				return &MidairCollisionError{ID: id, LastChangeTime: *update.LastChangeTime, Err: &RequestError{StatusCode: http.StatusConflict, Message: "bug changed in the fake"}}
+			}
+		}
+		c.applyUpdate(id, update)
 		return nil
 	}
-	return &requestError{statusCode: http.StatusNotFound, message: "bug not registered in the fake"}
+	return &RequestError{StatusCode: http.StatusNotFound, Message: "bug not registered in the fake"}
+}
+
+// UpdateBugByAlias updates the bug with the alias, if registered, or an
+// error, if set, or responds with an error that matches IsAliasNotFound
+func (c *Fake) UpdateBugByAlias(alias string, update BugUpdate) error {
+	if err := c.simulate("UpdateBugByAlias"); err != nil {
+		return err
+	}
+	id, err := c.aliasID(alias)
+	if err != nil {
+		return err
+	}
+	return c.unsimulated().UpdateBug(id, update)
+}
+
+// applyUpdate applies the update to the registered bug, advances the time it
+// last changed and registers the comment added with it
+func (c *Fake) applyUpdate(id int, update BugUpdate) {
+	bug := c.Bugs[id]
+	applyUpdate(&bug, update)
+	// every update changes the bug, so that guarded updates based on an
+	// earlier read collide
+	bug.LastChangeTime = Timestamp{Time: bug.LastChangeTime.Add(time.Second)}
+	c.Bugs[id] = bug
+	if update.Comment != nil {
+		if c.BugComments == nil {
+			c.BugComments = map[int][]Comment{}
+		}
+		comment := Comment{BugId: id, Count: len(c.BugComments[id]), Text: update.Comment.Body, IsPrivate: update.Comment.Private, IsMarkdown: update.Comment.Markdown}
+		if c.CurrentUser != nil {
+			comment.Creator = c.CurrentUser.Name
+		}
+		c.BugComments[id] = append(c.BugComments[id], comment)
+	}
+}
+
+// UpdateBugs updates all of the bugs, if all are registered, or an error, if
+// set for any of them, or responds with an error that matches IsNotFound.
+// Like the server, the fake updates either all of the bugs or none.
+func (c *Fake) UpdateBugs(ids []int, update BugUpdate) error {
+	if err := c.simulate("UpdateBugs"); err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return errors.New("no bugs to update")
+	}
+	for _, id := range ids {
+		if c.BugErrors[id] {
+			return errors.New("injected error updating bug")
+		}
+		if _, exists := c.Bugs[id]; !exists {
+			return &RequestError{StatusCode: http.StatusNotFound, Message: "bug not registered in the fake"}
+		}
+	}
+	for _, id := range ids {
+		c.applyUpdate(id, update)
+	}
+	return nil
+}
+
+// CreateBug registers a new bug with the next free ID, its description is
+// registered as the first comment
+func (c *Fake) CreateBug(create BugCreate) (int, error) {
+	if err := c.simulate("CreateBug"); err != nil {
+		return 0, err
+	}
+	id := 1
+	for existing := range c.Bugs {
+		if existing >= id {
+			id = existing + 1
+		}
+	}
+	bug := Bug{
+		ID:              id,
+		Product:         create.Product,
+		Summary:         create.Summary,
+		OperatingSystem: create.OperatingSystem,
+		Platform:        create.Platform,
+		Priority:        create.Priority,
+		Severity:        create.Severity,
+		Alias:           create.Alias,
+		AssignedTo:      create.AssignedTo,
+		CC:              create.CC,
+		QAContact:       create.QAContact,
+		Status:          create.Status,
+		TargetMilestone: create.TargetMilestone,
+		TargetRelease:   create.TargetRelease,
+		DependsOn:       create.DependsOn,
+		Blocks:          create.Blocks,
+		Keywords:        create.Keywords,
+		URL:             create.URL,
+		Whiteboard:      create.Whiteboard,
+		Groups:          create.Groups,
+		CustomFields:    setCustomFields(nil, create.CustomFields),
+		IsOpen:          true,
+	}
+	if create.Component != "" {
+		bug.Component = []string{create.Component}
+	}
+	if create.Version != "" {
+		bug.Version = []string{create.Version}
+	}
+	if c.Bugs == nil {
+		c.Bugs = map[int]Bug{}
+	}
+	c.Bugs[id] = bug
+	if c.BugComments == nil {
+		c.BugComments = map[int][]Comment{}
+	}
+	c.BugComments[id] = []Comment{{BugId: id, Count: 0, Text: create.Description, IsPrivate: create.IsDescriptionPrivate}}
+	return id, nil
+}
+
+// CreateAttachment registers the attachment and its data for the bug, if
+// registered, or an error, if set, or responds with an error that matches
+// IsNotFound. The comment, if any, is added to the comments of the bug.
+func (c *Fake) CreateAttachment(id int, attachment AttachmentCreate) (int, error) {
+	if err := c.simulate("CreateAttachment"); err != nil {
+		return 0, err
+	}
+	if c.BugErrors[id] {
+		return 0, errors.New("injected error creating attachment")
+	}
+	if _, exists := c.Bugs[id]; !exists {
+		return 0, &RequestError{StatusCode: http.StatusNotFound, Message: "bug not registered in the fake"}
+	}
+	attachmentID := 1
+	for existing := range c.AttachmentData {
+		if existing >= attachmentID {
+			attachmentID = existing + 1
+		}
+	}
+	for _, attachments := range c.BugAttachments {
+		for _, existing := range attachments {
+			if existing.ID >= attachmentID {
+				attachmentID = existing.ID + 1
+			}
+		}
+	}
+	if c.BugAttachments == nil {
+		c.BugAttachments = map[int][]Attachment{}
+	}
+	c.BugAttachments[id] = append(c.BugAttachments[id], Attachment{
+		ID:          attachmentID,
+		BugID:       id,
+		FileName:    attachment.FileName,
+		Summary:     attachment.Summary,
+		ContentType: attachment.ContentType,
+		Size:        len(attachment.Data),
+		IsPrivate:   attachment.IsPrivate,
+		IsPatch:     attachment.IsPatch,
+	})
+	if c.AttachmentData == nil {
+		c.AttachmentData = map[int][]byte{}
+	}
+	c.AttachmentData[attachmentID] = attachment.Data
+	if attachment.Comment != "" {
+		if c.BugComments == nil {
+			c.BugComments = map[int][]Comment{}
+		}
+		c.BugComments[id] = append(c.BugComments[id], Comment{BugId: id, Count: len(c.BugComments[id]), Text: attachment.Comment, AttachmentId: &attachmentID})
+	}
+	return attachmentID, nil
+}
+
+// GetFlags returns the flags of the bug, if registered, or an error, if set,
+// or responds with an error that matches IsNotFound
+func (c *Fake) GetFlags(id int) ([]Flag, error) {
+	if err := c.simulate("GetFlags"); err != nil {
+		return nil, err
+	}
+	bug, err := c.unsimulated().GetBug(id)
+	if err != nil {
+		return nil, err
+	}
+	return bug.Flags, nil
+}
+
+// SetFlag sets the flag on the bug, if registered, or returns an error, if
+// set, or responds with an error that matches IsNotFound
+func (c *Fake) SetFlag(id int, name, status string) error {
+	if err := c.simulate("SetFlag"); err != nil {
+		return err
+	}
+	return c.changeFlag(id, FlagChange{Name: name, Status: status})
+}
+
+// ClearFlag removes the flag from the bug, if registered, or returns an error,
+// if set, or responds with an error that matches IsNotFound
+func (c *Fake) ClearFlag(id int, name string) error {
+	if err := c.simulate("ClearFlag"); err != nil {
+		return err
+	}
+	return c.changeFlag(id, FlagChange{Name: name, Status: FlagClear})
+}
+
+// AddKeywords adds the keywords missing from the bug, if registered, or
+// returns an error, if set, or responds with an error that matches IsNotFound
+func (c *Fake) AddKeywords(id int, keywords ...string) error {
+	if err := c.simulate("AddKeywords"); err != nil {
+		return err
+	}
+	return changeKeywords(c.unsimulated(), id, keywords, true)
+}
+
+// RemoveKeywords removes the keywords on the bug, if registered, or returns
+// an error, if set, or responds with an error that matches IsNotFound
+func (c *Fake) RemoveKeywords(id int, keywords ...string) error {
+	if err := c.simulate("RemoveKeywords"); err != nil {
+		return err
+	}
+	return changeKeywords(c.unsimulated(), id, keywords, false)
+}
+
+// GetSeeAlso returns the "see also" links of the bug, if registered, or
+// returns an error, if set, or responds with an error that matches IsNotFound
+func (c *Fake) GetSeeAlso(id int) ([]SeeAlso, error) {
+	if err := c.simulate("GetSeeAlso"); err != nil {
+		return nil, err
+	}
+	return getSeeAlso(c.unsimulated(), id)
+}
+
+// UpdateSeeAlso changes the "see also" links of the bug, if registered, or
+// returns an error, if set, or responds with an error that matches IsNotFound
+func (c *Fake) UpdateSeeAlso(id int, add, remove []string) error {
+	if err := c.simulate("UpdateSeeAlso"); err != nil {
+		return err
+	}
+	return updateSeeAlso(c.unsimulated(), id, add, remove)
+}
+
+// MarkAsDuplicate resolves the bug, if registered, as a duplicate of the
+// other bug, or returns an error, if set, or responds with an error that
+// matches IsNotFound
+func (c *Fake) MarkAsDuplicate(id, dupeOf int) error {
+	if err := c.simulate("MarkAsDuplicate"); err != nil {
+		return err
+	}
+	return markAsDuplicate(c.unsimulated(), id, dupeOf)
+}
+
+// ResolveDuplicateChain follows the duplicates starting at the bug, if
+// registered, or returns an error, if set, or responds with an error that
+// matches IsNotFound
+func (c *Fake) ResolveDuplicateChain(id int) (int, error) {
+	if err := c.simulate("ResolveDuplicateChain"); err != nil {
+		return 0, err
+	}
+	return resolveDuplicateChain(c.unsimulated(), id)
+}
+
+// EnsureBugState brings the bug, if registered, into the desired state, or
+// returns an error, if set, or responds with an error that matches IsNotFound
+func (c *Fake) EnsureBugState(id int, desired DesiredBugState) (*BugUpdate, error) {
+	if err := c.simulate("EnsureBugState"); err != nil {
+		return nil, err
+	}
+	return ensureBugState(c.unsimulated(), id, desired)
+}
+
+func (c *Fake) changeFlag(id int, change FlagChange) error {
+	if c.BugErrors[id] {
+		return errors.New("injected error changing flag")
+	}
+	if _, exists := c.Bugs[id]; !exists {
+		return &RequestError{StatusCode: http.StatusNotFound, Message: "bug not registered in the fake"}
+	}
+	c.applyUpdate(id, BugUpdate{Flags: []FlagChange{change}})
+	return nil
+}
+
+// CloneBug creates a copy of the bug in the fake
+func (c *Fake) CloneBug(bug *Bug, mutations ...CloneOption) (int, error) {
+	if err := c.simulate("CloneBug"); err != nil {
+		return 0, err
+	}
+	return cloneBug(c.unsimulated(), bug, mutations...)
+}
+
+// closedStatuses are the statuses of the default workflow which have a resolution
+var closedStatuses = map[string]bool{"RESOLVED": true, "VERIFIED": true, "CLOSED": true}
+
+// applyUpdate mimics the server applying the update to the bug. Fields are
+// only changed when they are set in the update or cleared with ClearFields.
+func applyUpdate(bug *Bug, update BugUpdate) {
+	setString := func(field *string, value string) {
+		if value != "" {
+			*field = value
+		}
+	}
+	setString(&bug.Status, update.Status)
+	setString(&bug.Resolution, update.Resolution)
+	if update.Status != "" && update.Resolution == "" && !closedStatuses[update.Status] {
+		// like the server, reopening the bug clears its resolution
+		bug.Resolution = ""
+	}
+	setString(&bug.TargetMilestone, update.TargetMilestone)
+	setString(&bug.Summary, update.Summary)
+	setString(&bug.Product, update.Product)
+	setString(&bug.URL, update.URL)
+	setString(&bug.DevelWhiteboard, update.DevWhiteboard)
+	setString(&bug.Whiteboard, update.Whiteboard)
+	setString(&bug.Priority, update.Priority)
+	setString(&bug.Severity, update.Severity)
+	setString(&bug.AssignedTo, update.AssignedTo)
+	setString(&bug.QAContact, update.QAContact)
+	if update.TargetRelease != "" {
+		bug.TargetRelease = []string{update.TargetRelease}
+	}
+	if update.Component != "" {
+		bug.Component = []string{update.Component}
+	}
+	if update.Version != "" {
+		bug.Version = []string{update.Version}
+	}
+	if update.Deadline != nil {
+		bug.Deadline = *update.Deadline
+	}
+	if update.DupeOf != nil {
+		bug.DupeOf = *update.DupeOf
+	}
+	clearable := map[string]*string{
+		"whiteboard":          &bug.Whiteboard,
+		"cf_devel_whiteboard": &bug.DevelWhiteboard,
+		"url":                 &bug.URL,
+		"target_milestone":    &bug.TargetMilestone,
+		"deadline":            &bug.Deadline,
+		"resolution":          &bug.Resolution,
+	}
+	for _, field := range update.ClearFields {
+		if value, ok := clearable[field]; ok {
+			*value = ""
+		}
+	}
+	if update.Verified != nil {
+		bug.Verified = update.Verified
+	}
+	if len(update.CustomFields) > 0 {
+		bug.CustomFields = setCustomFields(bug.CustomFields, update.CustomFields)
+	}
+	if update.Flags != nil {
+		bug.Flags = applyFlagChanges(bug.Flags, update.Flags)
+	}
+	if update.Alias != nil {
+		bug.Alias = updateStrings(bug.Alias, update.Alias.Add, update.Alias.Remove, update.Alias.Set)
+	}
+	if update.Keywords != nil {
+		bug.Keywords = updateStrings(bug.Keywords, update.Keywords.Add, update.Keywords.Remove, update.Keywords.Set)
+	}
+	if update.CC != nil {
+		bug.CC = updateStrings(bug.CC, update.CC.Add, update.CC.Remove, nil)
+	}
+	if update.DependsOn != nil {
+		bug.DependsOn = updateInts(bug.DependsOn, update.DependsOn)
+	}
+	if update.Blocks != nil {
+		bug.Blocks = updateInts(bug.Blocks, update.Blocks)
+	}
+	if update.Groups != nil {
+		bug.Groups = updateStrings(bug.Groups, update.Groups.Add, update.Groups.Remove, nil)
+	}
+	if update.SeeAlso != nil {
+		bug.SeeAlso = updateStrings(bug.SeeAlso, update.SeeAlso.Add, update.SeeAlso.Remove, nil)
+	}
+}
+
+func updateStrings(current, add, remove, set []string) []string {
+	if set != nil {
+		current = set
+	}
+	values := map[string]bool{}
+	for _, value := range append(append([]string{}, current...), add...) {
+		values[value] = true
+	}
+	for _, value := range remove {
+		delete(values, value)
+	}
+	updated := []string{}
+	for value := range values {
+		updated = append(updated, value)
+	}
+	sort.Strings(updated)
+	return updated
+}
+
+func updateInts(current []int, update *BugIDs) []int {
+	if update.Set != nil {
+		current = update.Set
+	}
+	values := map[int]bool{}
+	for _, value := range append(append([]int{}, current...), update.Add...) {
+		values[value] = true
+	}
+	for _, value := range update.Remove {
+		delete(values, value)
+	}
+	updated := []int{}
+	for value := range values {
+		updated = append(updated, value)
+	}
+	sort.Ints(updated)
+	return updated
 }
 
 // AddPullRequestAsExternalBug adds an external bug to the Bugzilla bug,
 // if registered, or an error, if set, or responds with an error that
 // matches IsNotFound
 func (c *Fake) AddPullRequestAsExternalBug(id int, org, repo string, num int) (bool, error) {
-	if c.BugErrors.Has(id) {
+	if err := c.simulate("AddPullRequestAsExternalBug"); err != nil {
+		return false, err
+	}
+	if c.BugErrors[id] {
 		return false, errors.New("injected error adding external bug to bug")
 	}
 	if _, exists := c.Bugs[id]; exists {
@@ -140,7 +1035,88 @@ func (c *Fake) AddPullRequestAsExternalBug(id int, org, repo string, num int) (b
 		})
 		return true, nil
 	}
-	return false, &requestError{statusCode: http.StatusNotFound, message: "bug not registered in the fake"}
+	return false, &RequestError{StatusCode: http.StatusNotFound, Message: "bug not registered in the fake"}
+}
+
+// UpdateExternalBugStatus sets the status of the external bug linked to the
+// Bugzilla bug, if both are registered, or an error, if set, or responds with
+// an error that matches IsNotFound. External bugs registered without a type
+// match identifiers of any type.
+func (c *Fake) UpdateExternalBugStatus(bugID int, identifier ExternalBugIdentifier, status string) error {
+	if err := c.simulate("UpdateExternalBugStatus"); err != nil {
+		return err
+	}
+	if c.BugErrors[bugID] {
+		return errors.New("injected error updating external bug")
+	}
+	if _, exists := c.Bugs[bugID]; !exists {
+		return &RequestError{StatusCode: http.StatusNotFound, Message: "bug not registered in the fake"}
+	}
+	for i, bug := range c.ExternalBugs[bugID] {
+		if bug.ExternalBugID == identifier.ID && (bug.Type.URL == "" || bug.Type.URL == identifier.Type) {
+			c.ExternalBugs[bugID][i].ExternalStatus = status
+			return nil
+		}
+	}
+	return &RequestError{StatusCode: http.StatusNotFound, Message: "external bug not registered in the fake"}
+}
+
+// AddJiraIssueAsExternalBug adds an external bug to the Bugzilla bug,
+// if registered, or an error, if set, or responds with an
+// error that matches IsNotFound
+func (c *Fake) AddJiraIssueAsExternalBug(id int, project string, num int) (bool, error) {
+	if err := c.simulate("AddJiraIssueAsExternalBug"); err != nil {
+		return false, err
+	}
+	if c.BugErrors[id] {
+		return false, errors.New("injected error adding external bug to bug")
+	}
+	if _, exists := c.Bugs[id]; !exists {
+		return false, &RequestError{StatusCode: http.StatusNotFound, Message: "bug not registered in the fake"}
+	}
+	identifier := IdentifierForJiraIssue(project, num)
+	for _, bug := range c.ExternalBugs[id] {
+		if bug.BugzillaBugID == id && bug.ExternalBugID == identifier {
+			return false, nil
+		}
+	}
+	if c.ExternalBugs == nil {
+		c.ExternalBugs = map[int][]ExternalBug{}
+	}
+	c.ExternalBugs[id] = append(c.ExternalBugs[id], ExternalBug{
+		Type:          ExternalBugType{URL: JiraTracker},
+		BugzillaBugID: id,
+		ExternalBugID: identifier,
+	})
+	return true, nil
+}
+
+// Do decodes the registered response to the request into out, if any, or
+// responds with an error that matches IsNotFound
+func (c *Fake) Do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	if err := c.simulate("Do"); err != nil {
+		return err
+	}
+	response, exists := c.Responses[method+" "+path]
+	if !exists {
+		return &RequestError{StatusCode: http.StatusNotFound, Message: "endpoint not registered in the fake"}
+	}
+	if out == nil {
+		return nil
+	}
+	raw, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// SetAPIKeySupplier doesn't do anything
+func (c *Fake) SetAPIKeySupplier(getAPIKey func() []byte) error {
+	if getAPIKey == nil {
+		return errors.New("the API key supplier must not be nil")
+	}
+	return nil
 }
 
 // SetAuthMethod doesn't do anything and you can only set a blank string
@@ -153,3 +1129,19 @@ func (c *Fake) SetAuthMethod(authMethod string) error {
 
 // the Fake is a Client
 var _ Client = &Fake{}
+
+// setCustomFields returns a copy of the custom fields with the values set, so
+// bugs handed out by the fake do not share their custom fields
+func setCustomFields(fields, values map[string]interface{}) map[string]interface{} {
+	if len(fields) == 0 && len(values) == 0 {
+		return nil
+	}
+	set := map[string]interface{}{}
+	for name, value := range fields {
+		set[name] = value
+	}
+	for name, value := range values {
+		set[name] = value
+	}
+	return set
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Fields selects the fields of the bugs returned by GetBugs and Search, see
+// Query.WithFields. The server returns its default fields for the zero value.
+// Besides field names, the server understands "_default", "_all", "_extra"
+// and "_custom" to select groups of fields, and nested fields like
+// "flags.name".
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/general.html#useful-parameters
+type Fields struct {
+	// Include lists the fields to return, all default fields are returned if it
+	// is empty.
+	Include []string
+	// Exclude lists fields which are not returned even if they are included.
+	Exclude []string
+}
+
+var (
+	// FieldsDefault selects the fields the server returns by default.
+	FieldsDefault = Fields{}
+	// FieldsAll selects all fields, including the extra fields which are not
+	// returned by default, like tags.
+	FieldsAll = Fields{Include: []string{"_all"}}
+	// FieldsMinimal selects the fields to tell bugs apart and see whether they
+	// are done.
+	FieldsMinimal = Fields{Include: []string{"id", "summary", "status", "resolution", "last_change_time"}}
+	// FieldsTriage selects the fields needed to triage bugs.
+	FieldsTriage = Fields{Include: []string{
+		"id", "summary", "status", "resolution", "product", "component", "priority", "severity",
+		"assigned_to", "keywords", "target_release", "flags", "creation_time", "last_change_time",
+	}}
+)
+
+// With returns the selection including the fields as well. The fields of a
+// selection of the default fields are added to the defaults.
+func (f Fields) With(fields ...string) Fields {
+	include := f.Include
+	if len(include) == 0 {
+		include = []string{"_default"}
+	}
+	return Fields{Include: withFields(include, fields...), Exclude: append([]string{}, f.Exclude...)}
+}
+
+// Without returns the selection excluding the fields as well
+func (f Fields) Without(fields ...string) Fields {
+	return Fields{Include: append([]string{}, f.Include...), Exclude: withFields(f.Exclude, fields...)}
+}
+
+// addTo sets the parameters selecting the fields
+func (f Fields) addTo(values *url.Values) {
+	if len(f.Include) > 0 {
+		values.Set("include_fields", strings.Join(f.Include, ","))
+	}
+	if len(f.Exclude) > 0 {
+		values.Set("exclude_fields", strings.Join(f.Exclude, ","))
+	}
+}
+
+// WithFields returns the query selecting the fields of the bugs it returns,
+// replacing the fields it selected before
+func (q Query) WithFields(fields Fields) Query {
+	q.IncludeFields = append([]string{}, fields.Include...)
+	q.ExcludeFields = append([]string{}, fields.Exclude...)
+	return q
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestFieldsWithAndWithout(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		fields   Fields
+		expected Fields
+	}{
+		{
+			name:     "adding to the defaults keeps them",
+			fields:   FieldsDefault.With("tags"),
+			expected: Fields{Include: []string{"_default", "tags"}, Exclude: []string{}},
+		},
+		{
+			name:     "adding to a preset",
+			fields:   FieldsMinimal.With("id", "priority"),
+			expected: Fields{Include: []string{"id", "summary", "status", "resolution", "last_change_time", "priority"}, Exclude: []string{}},
+		},
+		{
+			name:     "excluding fields",
+			fields:   FieldsAll.Without("description", "description"),
+			expected: Fields{Include: []string{"_all"}, Exclude: []string{"description"}},
+		},
+	}
+	for _, testCase := range testCases {
+		if !reflect.DeepEqual(testCase.fields, testCase.expected) {
+			t.Errorf("%s: got incorrect fields: %v", testCase.name, diff.ObjectReflectDiff(testCase.expected, testCase.fields))
+		}
+	}
+}
+
+func TestQueryWithFields(t *testing.T) {
+	query := Query{Product: []string{"OCP"}, IncludeFields: []string{"id"}}.WithFields(FieldsAll.Without("description"))
+	values := query.Values()
+	if actual, expected := values.Get("include_fields"), "_all"; actual != expected {
+		t.Errorf("expected include_fields %q, got %q", expected, actual)
+	}
+	if actual, expected := values.Get("exclude_fields"), "description"; actual != expected {
+		t.Errorf("expected exclude_fields %q, got %q", expected, actual)
+	}
+}
+
+func TestGetBugs(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/rest/bug" {
+			t.Errorf("incorrect request to get bugs: %s %s", r.Method, r.URL.Path)
+			http.Error(w, "404 Not Found", http.StatusNotFound)
+			return
+		}
+		if actual, expected := r.URL.Query()["id"], []string{"1", "2"}; !reflect.DeepEqual(actual, expected) {
+			t.Errorf("got incorrect ids: expected %v, got %v", expected, actual)
+		}
+		if actual, expected := r.URL.Query().Get("include_fields"), "id,status"; actual != expected {
+			t.Errorf("got incorrect include_fields: expected %q, got %q", expected, actual)
+		}
+		if actual, expected := r.URL.Query().Get("exclude_fields"), "flags"; actual != expected {
+			t.Errorf("got incorrect exclude_fields: expected %q, got %q", expected, actual)
+		}
+		w.Write([]byte(`{"bugs":[{"id":1,"status":"NEW"},{"id":2,"status":"POST"}],"faults":[]}`))
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	bugs, err := client.GetBugs([]int{1, 2}, Fields{Include: []string{"id", "status"}, Exclude: []string{"flags"}})
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	expected := []*Bug{{ID: 1, Status: "NEW"}, {ID: 2, Status: "POST"}}
+	if !reflect.DeepEqual(bugs, expected) {
+		t.Errorf("got incorrect bugs: %v", diff.ObjectReflectDiff(expected, bugs))
+	}
+	if bugs, err := client.GetBugs(nil, FieldsMinimal); err != nil || len(bugs) != 0 {
+		t.Errorf("expected no bugs without IDs, got %v and %v", bugs, err)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// GetFields retrieves the metadata of the bug field with the name, like
+// "bug_status" or "cf_doc_type", including its legal values, or of all bug
+// fields if the name is empty.
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/field.html#fields
+func (c *client) GetFields(fieldName string) ([]Field, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "GetFields", "field": fieldName})
+	return c.getFields(fieldName, logger)
+}
+
+func (c *client) getFields(fieldName string, logger *logEntry) ([]Field, error) {
+	path := fmt.Sprintf("%s/rest/field/bug", c.endpoint)
+	if fieldName != "" {
+		path = fmt.Sprintf("%s/%s", path, url.PathEscape(fieldName))
+	}
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := c.request(req, logger)
+	if err != nil {
+		return nil, err
+	}
+	var parsedResponse struct {
+		Fields []Field `json:"fields,omitempty"`
+	}
+	if err := c.unmarshal(raw, &parsedResponse); err != nil {
+		return nil, fmt.Errorf("could not unmarshal response body: %v", err)
+	}
+	return parsedResponse.Fields, nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestGetFields(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/field/bug/bug_status":
+			w.Write([]byte(`{"fields":[{"id":2,"type":2,"is_custom":false,"name":"bug_status","display_name":"Status","is_mandatory":true,"is_on_bug_entry":false,"visibility_field":null,"visibility_values":[],"value_field":null,"values":[{"name":"NEW","sort_key":10,"visibility_values":[],"is_active":true,"description":null,"is_open":true,"can_change_to":[{"name":"CLOSED","comment_required":true}]}]}]}`))
+		case "/rest/field/bug":
+			w.Write([]byte(`{"fields":[{"id":1,"name":"bug_id","display_name":"Bug ID"},{"id":2,"name":"bug_status","display_name":"Status"}]}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":true,"code":51,"message":"There is no field named 'nope'."}`))
+		}
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL).(*client)
+	WithStrictDecoding()(client)
+
+	fields, err := client.GetFields("bug_status")
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	expected := []Field{{
+		ID:               2,
+		Type:             2,
+		Name:             "bug_status",
+		DisplayName:      "Status",
+		IsMandatory:      true,
+		VisibilityValues: []string{},
+		Values: []FieldValue{{
+			Name:             "NEW",
+			SortKey:          10,
+			VisibilityValues: []string{},
+			IsActive:         true,
+			IsOpen:           true,
+			CanChangeTo:      []StatusTransition{{Name: "CLOSED", CommentRequired: true}},
+		}},
+	}}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("got incorrect fields: %v", diff.ObjectReflectDiff(expected, fields))
+	}
+	if fields, err := client.GetFields(""); err != nil || len(fields) != 2 {
+		t.Errorf("expected all fields, got %v (error %v)", fields, err)
+	}
+	if _, err := client.GetFields("nope"); err == nil {
+		t.Error("expected an error for a missing field, but got none")
+	}
+}
+
+func TestFakeGetFields(t *testing.T) {
+	fake := &Fake{Fields: []Field{{Name: "bug_status"}, {Name: "priority"}}}
+	if fields, err := fake.GetFields("priority"); err != nil || len(fields) != 1 || fields[0].Name != "priority" {
+		t.Errorf("expected the priority field, got %v (error %v)", fields, err)
+	}
+	if fields, err := fake.GetFields(""); err != nil || len(fields) != 2 {
+		t.Errorf("expected all fields, got %v (error %v)", fields, err)
+	}
+	if _, err := fake.GetFields("nope"); !IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+)
+
+// The statuses of a flag, e.g. `blocker+` is the blocker flag with status FlagGranted
+const (
+	// FlagGranted is the status of a granted flag
+	FlagGranted = "+"
+	// FlagDenied is the status of a denied flag
+	FlagDenied = "-"
+	// FlagRequested is the status of a flag which was requested but not decided yet
+	FlagRequested = "?"
+	// FlagClear is the status used in a FlagChange to remove the flag from the bug
+	FlagClear = "X"
+)
+
+// GetFlags retrieves the flags set on a Bug from the server
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#get-bug
+func (c *client) GetFlags(id int) ([]Flag, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "GetFlags", "id": id})
+	bug, err := c.getBug(id, Fields{Include: []string{"id", "flags"}}, logger)
+	if err != nil {
+		return nil, err
+	}
+	return bug.Flags, nil
+}
+
+// SetFlag sets the flag on the bug to the status, which is one of FlagGranted,
+// FlagDenied or FlagRequested. To ask a specific user to decide a requested
+// flag, update the bug with a FlagChange holding the requestee.
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#update-bug
+func (c *client) SetFlag(id int, name, status string) error {
+	if status != FlagGranted && status != FlagDenied && status != FlagRequested {
+		return fmt.Errorf("invalid status %q for flag %s, must be one of %s, %s or %s", status, name, FlagGranted, FlagDenied, FlagRequested)
+	}
+	return c.UpdateBug(id, BugUpdate{Flags: []FlagChange{{Name: name, Status: status}}})
+}
+
+// ClearFlag removes the flag from the bug
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#update-bug
+func (c *client) ClearFlag(id int, name string) error {
+	return c.UpdateBug(id, BugUpdate{Flags: []FlagChange{{Name: name, Status: FlagClear}}})
+}
+
+// FlagStatus returns the status of the first flag with the name on the bug
+// and whether the bug has such a flag.
+func FlagStatus(bug *Bug, name string) (string, bool) {
+	for _, flag := range bug.Flags {
+		if flag.Name == name {
+			return flag.Status, true
+		}
+	}
+	return "", false
+}
+
+// applyFlagChanges mimics the server applying the flag changes to the flags
+// of a bug: cleared flags are removed, other flags are updated or added.
+func applyFlagChanges(flags []Flag, changes []FlagChange) []Flag {
+	for _, change := range changes {
+		var updated []Flag
+		found := false
+		for _, flag := range flags {
+			if flag.Name != change.Name {
+				updated = append(updated, flag)
+				continue
+			}
+			if change.Status == FlagClear {
+				continue
+			}
+			found = true
+			flag.Status = change.Status
+			flag.Requestee = change.Requestee
+			updated = append(updated, flag)
+		}
+		if !found && change.Status != FlagClear {
+			updated = append(updated, Flag{Name: change.Name, Status: change.Status, Requestee: change.Requestee})
+		}
+		flags = updated
+	}
+	return flags
+}
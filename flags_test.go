@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestFlags(t *testing.T) {
+	var updates []string
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/bug/1705243" {
+			http.Error(w, "404 Not Found", http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			if fields := r.URL.Query().Get("include_fields"); fields != "id,flags" {
+				t.Errorf("expected only the flags to be requested, got %q", fields)
+			}
+			w.Write([]byte(`{"bugs":[{"id":1705243,"flags":[{"id":1,"name":"blocker","status":"+","setter":"dev@example.com"}]}]}`))
+		case http.MethodPut:
+			raw, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				t.Errorf("failed to read update body: %v", err)
+			}
+			updates = append(updates, string(raw))
+		}
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	flags, err := client.GetFlags(1705243)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if expected := []Flag{{ID: 1, Name: "blocker", Status: FlagGranted, Setter: "dev@example.com"}}; !reflect.DeepEqual(flags, expected) {
+		t.Errorf("got incorrect flags: %v", diff.ObjectReflectDiff(expected, flags))
+	}
+	if err := client.SetFlag(1705243, "release-note", FlagRequested); err != nil {
+		t.Errorf("expected no error, but got one: %v", err)
+	}
+	if err := client.ClearFlag(1705243, "blocker"); err != nil {
+		t.Errorf("expected no error, but got one: %v", err)
+	}
+	if err := client.SetFlag(1705243, "blocker", "X"); err == nil {
+		t.Error("expected an error setting a flag to an invalid status, but got none")
+	}
+	expected := []string{
+		`{"flags":[{"name":"release-note","status":"?"}]}`,
+		`{"flags":[{"name":"blocker","status":"X"}]}`,
+	}
+	if !reflect.DeepEqual(updates, expected) {
+		t.Errorf("got incorrect updates: %v", diff.ObjectReflectDiff(expected, updates))
+	}
+	if _, err := client.GetFlags(1); !IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestApplyFlagChanges(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		flags    []Flag
+		changes  []FlagChange
+		expected []Flag
+	}{
+		{
+			name:     "requesting a new flag",
+			flags:    []Flag{{Name: "blocker", Status: FlagGranted}},
+			changes:  []FlagChange{{Name: "release-note", Status: FlagRequested, Requestee: "docs@example.com"}},
+			expected: []Flag{{Name: "blocker", Status: FlagGranted}, {Name: "release-note", Status: FlagRequested, Requestee: "docs@example.com"}},
+		},
+		{
+			name:     "granting a requested flag",
+			flags:    []Flag{{ID: 1, Name: "release-note", Status: FlagRequested, Requestee: "docs@example.com"}},
+			changes:  []FlagChange{{Name: "release-note", Status: FlagGranted}},
+			expected: []Flag{{ID: 1, Name: "release-note", Status: FlagGranted}},
+		},
+		{
+			name:    "clearing a flag",
+			flags:   []Flag{{Name: "blocker", Status: FlagDenied}},
+			changes: []FlagChange{{Name: "blocker", Status: FlagClear}},
+		},
+	}
+	for _, testCase := range testCases {
+		if actual := applyFlagChanges(testCase.flags, testCase.changes); !reflect.DeepEqual(actual, testCase.expected) {
+			t.Errorf("%s: got incorrect flags: %v", testCase.name, diff.ObjectReflectDiff(testCase.expected, actual))
+		}
+	}
+}
+
+func TestFakeFlags(t *testing.T) {
+	fake := &Fake{Bugs: map[int]Bug{1: {ID: 1, Status: "POST"}}}
+	if err := fake.SetFlag(1, "blocker", FlagGranted); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if status, ok := FlagStatus(&Bug{Flags: fake.Bugs[1].Flags}, "blocker"); !ok || status != FlagGranted {
+		t.Errorf("expected the blocker flag to be granted, got %q", status)
+	}
+	if fake.Bugs[1].Status != "POST" {
+		t.Errorf("expected the status to be left alone, got %q", fake.Bugs[1].Status)
+	}
+	if err := fake.ClearFlag(1, "blocker"); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if flags, _ := fake.GetFlags(1); len(flags) != 0 {
+		t.Errorf("expected no flags, got %v", flags)
+	}
+	if err := fake.SetFlag(2, "blocker", FlagGranted); !IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"sync"
+)
+
+// FullBug is a bug with everything attached to it
+type FullBug struct {
+	Bug          *Bug
+	Comments     []Comment
+	History      []History
+	Attachments  []Attachment
+	ExternalBugs []ExternalBug
+}
+
+// getBugFull retrieves the bug, its comments, history, attachments and
+// external bugs concurrently. If any call fails, the error of the first
+// failing call in that order is returned.
+func getBugFull(c Client, id int) (*FullBug, error) {
+	full := &FullBug{}
+	calls := []func() error{
+		func() (err error) {
+			full.Bug, err = c.GetBug(id)
+			return err
+		},
+		func() (err error) {
+			full.Comments, err = c.GetBugComments(id)
+			return err
+		},
+		func() (err error) {
+			full.History, err = c.GetBugHistory(id)
+			return err
+		},
+		func() (err error) {
+			full.Attachments, err = c.GetBugAttachments(id)
+			return err
+		},
+		func() (err error) {
+			full.ExternalBugs, err = c.GetExternalBugs(id)
+			return err
+		},
+	}
+	errs := make([]error, len(calls))
+	var wg sync.WaitGroup
+	for i := range calls {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = calls[i]()
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return full, nil
+}
+
+// BulkGetBugsFull retrieves the bugs with everything attached to them, see
+// Client.GetBugFull, with at most concurrency bugs retrieved at once. The bugs
+// are returned in the order of the IDs, leaving out those which failed; any
+// failures are returned as a *BulkError.
+func BulkGetBugsFull(c Client, ids []int, concurrency int) ([]*FullBug, error) {
+	bugs := make([]*FullBug, len(ids))
+	errs := bulk(ids, concurrency, func(index int) (err error) {
+		bugs[index], err = c.GetBugFull(ids[index])
+		return err
+	})
+	var retrieved []*FullBug
+	for index := range ids {
+		if errs[index] == nil {
+			retrieved = append(retrieved, bugs[index])
+		}
+	}
+	return retrieved, bulkError(ids, errs)
+}
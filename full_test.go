@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestGetBugFull(t *testing.T) {
+	failAttachments := false
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/bug/1":
+			if r.URL.Query().Get("include_fields") == "external_bugs" {
+				w.Write([]byte(`{"bugs":[{"external_bugs":[{"bug_id":1,"ext_bz_bug_id":"org/repo/pull/1"}]}]}`))
+				return
+			}
+			w.Write([]byte(`{"bugs":[{"id":1,"summary":"bug"}]}`))
+		case "/rest/bug/1/comment":
+			w.Write([]byte(`{"bugs":{"1":{"comments":[{"id":10,"text":"description"}]}}}`))
+		case "/rest/bug/1/history":
+			w.Write([]byte(`{"bugs":[{"history":[{"who":"someone","changes":[{"field_name":"status","removed":"NEW","added":"ASSIGNED"}]}]}]}`))
+		case "/rest/bug/1/attachment":
+			if r.URL.Query().Get("exclude_fields") != "data" {
+				t.Error("expected the attachment data to be excluded")
+			}
+			if failAttachments {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error":true,"code":101,"message":"Bug #1 does not exist."}`))
+				return
+			}
+			w.Write([]byte(`{"bugs":{"1":[{"id":100,"bug_id":1,"file_name":"must-gather.tar.gz","content_type":"application/gzip","size":1024}]},"attachments":{}}`))
+		default:
+			http.Error(w, "404 Not Found", http.StatusNotFound)
+		}
+	}))
+	defer testServer.Close()
+	c := clientForUrl(testServer.URL)
+
+	full, err := c.GetBugFull(1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := &FullBug{
+		Bug:          &Bug{ID: 1, Summary: "bug"},
+		Comments:     []Comment{{Id: 10, Text: "description"}},
+		History:      []History{{Who: "someone", Changes: []HistoryChange{{FieldName: "status", Removed: "NEW", Added: "ASSIGNED"}}}},
+		Attachments:  []Attachment{{ID: 100, BugID: 1, FileName: "must-gather.tar.gz", ContentType: "application/gzip", Size: 1024}},
+		ExternalBugs: []ExternalBug{{BugzillaBugID: 1, ExternalBugID: "org/repo/pull/1"}},
+	}
+	if !reflect.DeepEqual(full, expected) {
+		t.Errorf("got incorrect bug: %v", diff.ObjectReflectDiff(expected, full))
+	}
+
+	failAttachments = true
+	if _, err := c.GetBugFull(1); !IsNotFound(err) {
+		t.Errorf("expected the error of the failing call, got %v", err)
+	}
+}
+
+func TestFakeGetBugFull(t *testing.T) {
+	fake := &Fake{
+		Bugs:           map[int]Bug{1: {ID: 1}},
+		BugComments:    map[int][]Comment{1: {{Id: 10}}},
+		BugHistory:     map[int][]History{1: {{Who: "someone"}}},
+		BugAttachments: map[int][]Attachment{1: {{ID: 100}}},
+	}
+	full, err := fake.GetBugFull(1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := &FullBug{
+		Bug:         &Bug{ID: 1},
+		Comments:    []Comment{{Id: 10}},
+		History:     []History{{Who: "someone"}},
+		Attachments: []Attachment{{ID: 100}},
+	}
+	if !reflect.DeepEqual(full, expected) {
+		t.Errorf("got incorrect bug: %v", diff.ObjectReflectDiff(expected, full))
+	}
+	if _, err := fake.GetBugFull(2); !IsNotFound(err) {
+		t.Errorf("expected a not found error for an unknown bug, got %v", err)
+	}
+}
+
+func TestBulkGetBugsFull(t *testing.T) {
+	fake := &Fake{
+		Bugs:        map[int]Bug{1: {ID: 1}, 2: {ID: 2}},
+		BugComments: map[int][]Comment{2: {{Id: 20}}},
+	}
+	bugs, err := BulkGetBugsFull(fake, []int{2, 3, 1}, 2)
+	bulkErr, ok := err.(*BulkError)
+	if !ok || !reflect.DeepEqual(bulkErr.IDs(), []int{3}) {
+		t.Fatalf("expected bug 3 to fail, got %v", err)
+	}
+	expected := []*FullBug{
+		{Bug: &Bug{ID: 2}, Comments: []Comment{{Id: 20}}},
+		{Bug: &Bug{ID: 1}},
+	}
+	if !reflect.DeepEqual(bugs, expected) {
+		t.Errorf("got incorrect bugs: %v", diff.ObjectReflectDiff(expected, bugs))
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Callers maps the bearer tokens which callers authenticate with to the names
+// of the callers, which are recorded in the audit log.
+type Callers map[string]string
+
+// UnaryInterceptor authenticates the callers of unary calls and records every
+// call in the audit log with the bug it is about, but without the request.
+func UnaryInterceptor(callers Callers, logger *logrus.Entry) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		var response interface{}
+		caller, err := callers.authenticate(ctx)
+		if err == nil {
+			response, err = handler(ctx, req)
+		}
+		audit(ctx, logger, info.FullMethod, caller, req, start, err)
+		return response, err
+	}
+}
+
+// StreamInterceptor authenticates the callers of streaming calls and records
+// every call in the audit log.
+func StreamInterceptor(callers Callers, logger *logrus.Entry) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		caller, err := callers.authenticate(stream.Context())
+		if err == nil {
+			err = handler(srv, stream)
+		}
+		audit(stream.Context(), logger, info.FullMethod, caller, nil, start, err)
+		return err
+	}
+}
+
+// authenticate returns the name of the caller holding the bearer token in
+// the metadata of the call
+func (c Callers) authenticate(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, header := range md.Get("authorization") {
+		if !strings.HasPrefix(header, "Bearer ") {
+			continue
+		}
+		given := []byte(strings.TrimPrefix(header, "Bearer "))
+		for token, caller := range c {
+			if subtle.ConstantTimeCompare(given, []byte(token)) == 1 {
+				return caller, nil
+			}
+		}
+	}
+	return "", status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+}
+
+func audit(ctx context.Context, logger *logrus.Entry, method, caller string, req interface{}, start time.Time, err error) {
+	fields := logrus.Fields{
+		"grpc_method": method,
+		"caller":      caller,
+		"code":        status.Code(err).String(),
+		"duration":    time.Since(start).String(),
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		fields["peer"] = p.Addr.String()
+	}
+	// the request is not recorded, since it may hold comments on private bugs
+	if bug, ok := req.(interface{ GetId() int64 }); ok {
+		fields["bug"] = bug.GetId()
+	}
+	entry := logger.WithFields(fields)
+	if err != nil {
+		entry.WithError(err).Info("Rejected or failed call.")
+		return
+	}
+	entry.Info("Handled call.")
+}
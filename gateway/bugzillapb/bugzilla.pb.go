@@ -0,0 +1,1582 @@
+// Copyright 2020 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.21.0
+// 	protoc        (unknown)
+// source: bugzilla.proto
+
+package bugzillapb
+
+import (
+	context "context"
+	proto "github.com/golang/protobuf/proto"
+	empty "github.com/golang/protobuf/ptypes/empty"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type GetBugRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetBugRequest) Reset() {
+	*x = GetBugRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bugzilla_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBugRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBugRequest) ProtoMessage() {}
+
+func (x *GetBugRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bugzilla_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBugRequest.ProtoReflect.Descriptor instead.
+func (*GetBugRequest) Descriptor() ([]byte, []int) {
+	return file_bugzilla_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetBugRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type SearchBugsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// query is a search in the URL query format of the Bugzilla search page,
+	// e.g. "product=OpenShift Container Platform&bug_status=NEW".
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// include_fields limits the fields of the returned bugs, all fields are
+	// returned if it is empty.
+	IncludeFields []string `protobuf:"bytes,2,rep,name=include_fields,json=includeFields,proto3" json:"include_fields,omitempty"`
+}
+
+func (x *SearchBugsRequest) Reset() {
+	*x = SearchBugsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bugzilla_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchBugsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchBugsRequest) ProtoMessage() {}
+
+func (x *SearchBugsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bugzilla_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchBugsRequest.ProtoReflect.Descriptor instead.
+func (*SearchBugsRequest) Descriptor() ([]byte, []int) {
+	return file_bugzilla_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SearchBugsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchBugsRequest) GetIncludeFields() []string {
+	if x != nil {
+		return x.IncludeFields
+	}
+	return nil
+}
+
+type GetCommentsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetCommentsRequest) Reset() {
+	*x = GetCommentsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bugzilla_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCommentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentsRequest) ProtoMessage() {}
+
+func (x *GetCommentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bugzilla_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentsRequest.ProtoReflect.Descriptor instead.
+func (*GetCommentsRequest) Descriptor() ([]byte, []int) {
+	return file_bugzilla_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetCommentsRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetCommentsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Comments []*Comment `protobuf:"bytes,1,rep,name=comments,proto3" json:"comments,omitempty"`
+}
+
+func (x *GetCommentsResponse) Reset() {
+	*x = GetCommentsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bugzilla_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCommentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentsResponse) ProtoMessage() {}
+
+func (x *GetCommentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bugzilla_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentsResponse.ProtoReflect.Descriptor instead.
+func (*GetCommentsResponse) Descriptor() ([]byte, []int) {
+	return file_bugzilla_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetCommentsResponse) GetComments() []*Comment {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+type UpdateBugRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Fields left empty are not changed.
+	Status         string        `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Resolution     string        `protobuf:"bytes,3,opt,name=resolution,proto3" json:"resolution,omitempty"`
+	TargetRelease  string        `protobuf:"bytes,4,opt,name=target_release,json=targetRelease,proto3" json:"target_release,omitempty"`
+	AssignedTo     string        `protobuf:"bytes,5,opt,name=assigned_to,json=assignedTo,proto3" json:"assigned_to,omitempty"`
+	QaContact      string        `protobuf:"bytes,6,opt,name=qa_contact,json=qaContact,proto3" json:"qa_contact,omitempty"`
+	Priority       string        `protobuf:"bytes,7,opt,name=priority,proto3" json:"priority,omitempty"`
+	Severity       string        `protobuf:"bytes,8,opt,name=severity,proto3" json:"severity,omitempty"`
+	Whiteboard     string        `protobuf:"bytes,9,opt,name=whiteboard,proto3" json:"whiteboard,omitempty"`
+	AddKeywords    []string      `protobuf:"bytes,10,rep,name=add_keywords,json=addKeywords,proto3" json:"add_keywords,omitempty"`
+	RemoveKeywords []string      `protobuf:"bytes,11,rep,name=remove_keywords,json=removeKeywords,proto3" json:"remove_keywords,omitempty"`
+	Flags          []*FlagChange `protobuf:"bytes,12,rep,name=flags,proto3" json:"flags,omitempty"`
+	// comment is added along with the update, if set.
+	Comment *NewComment `protobuf:"bytes,13,opt,name=comment,proto3" json:"comment,omitempty"`
+	// minor_update suppresses the e-mail notifications for the update.
+	MinorUpdate bool `protobuf:"varint,14,opt,name=minor_update,json=minorUpdate,proto3" json:"minor_update,omitempty"`
+}
+
+func (x *UpdateBugRequest) Reset() {
+	*x = UpdateBugRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bugzilla_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateBugRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateBugRequest) ProtoMessage() {}
+
+func (x *UpdateBugRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bugzilla_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateBugRequest.ProtoReflect.Descriptor instead.
+func (*UpdateBugRequest) Descriptor() ([]byte, []int) {
+	return file_bugzilla_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdateBugRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateBugRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UpdateBugRequest) GetResolution() string {
+	if x != nil {
+		return x.Resolution
+	}
+	return ""
+}
+
+func (x *UpdateBugRequest) GetTargetRelease() string {
+	if x != nil {
+		return x.TargetRelease
+	}
+	return ""
+}
+
+func (x *UpdateBugRequest) GetAssignedTo() string {
+	if x != nil {
+		return x.AssignedTo
+	}
+	return ""
+}
+
+func (x *UpdateBugRequest) GetQaContact() string {
+	if x != nil {
+		return x.QaContact
+	}
+	return ""
+}
+
+func (x *UpdateBugRequest) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *UpdateBugRequest) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *UpdateBugRequest) GetWhiteboard() string {
+	if x != nil {
+		return x.Whiteboard
+	}
+	return ""
+}
+
+func (x *UpdateBugRequest) GetAddKeywords() []string {
+	if x != nil {
+		return x.AddKeywords
+	}
+	return nil
+}
+
+func (x *UpdateBugRequest) GetRemoveKeywords() []string {
+	if x != nil {
+		return x.RemoveKeywords
+	}
+	return nil
+}
+
+func (x *UpdateBugRequest) GetFlags() []*FlagChange {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+func (x *UpdateBugRequest) GetComment() *NewComment {
+	if x != nil {
+		return x.Comment
+	}
+	return nil
+}
+
+func (x *UpdateBugRequest) GetMinorUpdate() bool {
+	if x != nil {
+		return x.MinorUpdate
+	}
+	return false
+}
+
+type AddCommentRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      int64       `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Comment *NewComment `protobuf:"bytes,2,opt,name=comment,proto3" json:"comment,omitempty"`
+}
+
+func (x *AddCommentRequest) Reset() {
+	*x = AddCommentRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bugzilla_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddCommentRequest) ProtoMessage() {}
+
+func (x *AddCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bugzilla_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddCommentRequest.ProtoReflect.Descriptor instead.
+func (*AddCommentRequest) Descriptor() ([]byte, []int) {
+	return file_bugzilla_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AddCommentRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AddCommentRequest) GetComment() *NewComment {
+	if x != nil {
+		return x.Comment
+	}
+	return nil
+}
+
+type NewComment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Body    string `protobuf:"bytes,1,opt,name=body,proto3" json:"body,omitempty"`
+	Private bool   `protobuf:"varint,2,opt,name=private,proto3" json:"private,omitempty"`
+}
+
+func (x *NewComment) Reset() {
+	*x = NewComment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bugzilla_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NewComment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewComment) ProtoMessage() {}
+
+func (x *NewComment) ProtoReflect() protoreflect.Message {
+	mi := &file_bugzilla_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewComment.ProtoReflect.Descriptor instead.
+func (*NewComment) Descriptor() ([]byte, []int) {
+	return file_bugzilla_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *NewComment) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *NewComment) GetPrivate() bool {
+	if x != nil {
+		return x.Private
+	}
+	return false
+}
+
+type FlagChange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// status is "+", "-", "?" or "X" to clear the flag.
+	Status    string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Requestee string `protobuf:"bytes,3,opt,name=requestee,proto3" json:"requestee,omitempty"`
+}
+
+func (x *FlagChange) Reset() {
+	*x = FlagChange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bugzilla_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FlagChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlagChange) ProtoMessage() {}
+
+func (x *FlagChange) ProtoReflect() protoreflect.Message {
+	mi := &file_bugzilla_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlagChange.ProtoReflect.Descriptor instead.
+func (*FlagChange) Descriptor() ([]byte, []int) {
+	return file_bugzilla_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FlagChange) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FlagChange) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *FlagChange) GetRequestee() string {
+	if x != nil {
+		return x.Requestee
+	}
+	return ""
+}
+
+type Flag struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name      string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Status    string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Setter    string `protobuf:"bytes,3,opt,name=setter,proto3" json:"setter,omitempty"`
+	Requestee string `protobuf:"bytes,4,opt,name=requestee,proto3" json:"requestee,omitempty"`
+}
+
+func (x *Flag) Reset() {
+	*x = Flag{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bugzilla_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Flag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Flag) ProtoMessage() {}
+
+func (x *Flag) ProtoReflect() protoreflect.Message {
+	mi := &file_bugzilla_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Flag.ProtoReflect.Descriptor instead.
+func (*Flag) Descriptor() ([]byte, []int) {
+	return file_bugzilla_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Flag) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Flag) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Flag) GetSetter() string {
+	if x != nil {
+		return x.Setter
+	}
+	return ""
+}
+
+func (x *Flag) GetRequestee() string {
+	if x != nil {
+		return x.Requestee
+	}
+	return ""
+}
+
+type Comment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// count is the number of the comment on the bug, the description is 0.
+	Count        int64                `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Text         string               `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	Creator      string               `protobuf:"bytes,4,opt,name=creator,proto3" json:"creator,omitempty"`
+	CreationTime *timestamp.Timestamp `protobuf:"bytes,5,opt,name=creation_time,json=creationTime,proto3" json:"creation_time,omitempty"`
+	Private      bool                 `protobuf:"varint,6,opt,name=private,proto3" json:"private,omitempty"`
+	Tags         []string             `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty"`
+}
+
+func (x *Comment) Reset() {
+	*x = Comment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bugzilla_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Comment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Comment) ProtoMessage() {}
+
+func (x *Comment) ProtoReflect() protoreflect.Message {
+	mi := &file_bugzilla_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Comment.ProtoReflect.Descriptor instead.
+func (*Comment) Descriptor() ([]byte, []int) {
+	return file_bugzilla_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Comment) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Comment) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *Comment) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Comment) GetCreator() string {
+	if x != nil {
+		return x.Creator
+	}
+	return ""
+}
+
+func (x *Comment) GetCreationTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.CreationTime
+	}
+	return nil
+}
+
+func (x *Comment) GetPrivate() bool {
+	if x != nil {
+		return x.Private
+	}
+	return false
+}
+
+func (x *Comment) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type Bug struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id             int64                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Summary        string               `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	Status         string               `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Resolution     string               `protobuf:"bytes,4,opt,name=resolution,proto3" json:"resolution,omitempty"`
+	Product        string               `protobuf:"bytes,5,opt,name=product,proto3" json:"product,omitempty"`
+	Component      []string             `protobuf:"bytes,6,rep,name=component,proto3" json:"component,omitempty"`
+	Version        []string             `protobuf:"bytes,7,rep,name=version,proto3" json:"version,omitempty"`
+	TargetRelease  []string             `protobuf:"bytes,8,rep,name=target_release,json=targetRelease,proto3" json:"target_release,omitempty"`
+	AssignedTo     string               `protobuf:"bytes,9,opt,name=assigned_to,json=assignedTo,proto3" json:"assigned_to,omitempty"`
+	QaContact      string               `protobuf:"bytes,10,opt,name=qa_contact,json=qaContact,proto3" json:"qa_contact,omitempty"`
+	Creator        string               `protobuf:"bytes,11,opt,name=creator,proto3" json:"creator,omitempty"`
+	Priority       string               `protobuf:"bytes,12,opt,name=priority,proto3" json:"priority,omitempty"`
+	Severity       string               `protobuf:"bytes,13,opt,name=severity,proto3" json:"severity,omitempty"`
+	Keywords       []string             `protobuf:"bytes,14,rep,name=keywords,proto3" json:"keywords,omitempty"`
+	Whiteboard     string               `protobuf:"bytes,15,opt,name=whiteboard,proto3" json:"whiteboard,omitempty"`
+	DependsOn      []int64              `protobuf:"varint,16,rep,packed,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	Blocks         []int64              `protobuf:"varint,17,rep,packed,name=blocks,proto3" json:"blocks,omitempty"`
+	Flags          []*Flag              `protobuf:"bytes,18,rep,name=flags,proto3" json:"flags,omitempty"`
+	IsOpen         bool                 `protobuf:"varint,19,opt,name=is_open,json=isOpen,proto3" json:"is_open,omitempty"`
+	CreationTime   *timestamp.Timestamp `protobuf:"bytes,20,opt,name=creation_time,json=creationTime,proto3" json:"creation_time,omitempty"`
+	LastChangeTime *timestamp.Timestamp `protobuf:"bytes,21,opt,name=last_change_time,json=lastChangeTime,proto3" json:"last_change_time,omitempty"`
+}
+
+func (x *Bug) Reset() {
+	*x = Bug{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bugzilla_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Bug) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bug) ProtoMessage() {}
+
+func (x *Bug) ProtoReflect() protoreflect.Message {
+	mi := &file_bugzilla_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bug.ProtoReflect.Descriptor instead.
+func (*Bug) Descriptor() ([]byte, []int) {
+	return file_bugzilla_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Bug) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Bug) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *Bug) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Bug) GetResolution() string {
+	if x != nil {
+		return x.Resolution
+	}
+	return ""
+}
+
+func (x *Bug) GetProduct() string {
+	if x != nil {
+		return x.Product
+	}
+	return ""
+}
+
+func (x *Bug) GetComponent() []string {
+	if x != nil {
+		return x.Component
+	}
+	return nil
+}
+
+func (x *Bug) GetVersion() []string {
+	if x != nil {
+		return x.Version
+	}
+	return nil
+}
+
+func (x *Bug) GetTargetRelease() []string {
+	if x != nil {
+		return x.TargetRelease
+	}
+	return nil
+}
+
+func (x *Bug) GetAssignedTo() string {
+	if x != nil {
+		return x.AssignedTo
+	}
+	return ""
+}
+
+func (x *Bug) GetQaContact() string {
+	if x != nil {
+		return x.QaContact
+	}
+	return ""
+}
+
+func (x *Bug) GetCreator() string {
+	if x != nil {
+		return x.Creator
+	}
+	return ""
+}
+
+func (x *Bug) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *Bug) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *Bug) GetKeywords() []string {
+	if x != nil {
+		return x.Keywords
+	}
+	return nil
+}
+
+func (x *Bug) GetWhiteboard() string {
+	if x != nil {
+		return x.Whiteboard
+	}
+	return ""
+}
+
+func (x *Bug) GetDependsOn() []int64 {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *Bug) GetBlocks() []int64 {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+func (x *Bug) GetFlags() []*Flag {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+func (x *Bug) GetIsOpen() bool {
+	if x != nil {
+		return x.IsOpen
+	}
+	return false
+}
+
+func (x *Bug) GetCreationTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.CreationTime
+	}
+	return nil
+}
+
+func (x *Bug) GetLastChangeTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.LastChangeTime
+	}
+	return nil
+}
+
+var File_bugzilla_proto protoreflect.FileDescriptor
+
+var file_bugzilla_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x62, 0x75, 0x67, 0x7a, 0x69, 0x6c, 0x6c, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x0b, 0x62, 0x75, 0x67, 0x7a, 0x69, 0x6c, 0x6c, 0x61, 0x2e, 0x76, 0x31, 0x1a, 0x1b, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65,
+	0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x1f, 0x0a, 0x0d, 0x47,
+	0x65, 0x74, 0x42, 0x75, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0x50, 0x0a, 0x11,
+	0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x42, 0x75, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x69, 0x6e, 0x63, 0x6c, 0x75,
+	0x64, 0x65, 0x5f, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0d, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x22, 0x24,
+	0x0a, 0x12, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x02, 0x69, 0x64, 0x22, 0x47, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x65,
+	0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x08, 0x63,
+	0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x62, 0x75, 0x67, 0x7a, 0x69, 0x6c, 0x6c, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x6d,
+	0x65, 0x6e, 0x74, 0x52, 0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x22, 0xea, 0x03,
+	0x0a, 0x10, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x75, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65,
+	0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x72, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x5f, 0x72, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73,
+	0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x5f, 0x74, 0x6f,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64,
+	0x54, 0x6f, 0x12, 0x1d, 0x0a, 0x0a, 0x71, 0x61, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x71, 0x61, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63,
+	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x1a, 0x0a,
+	0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x77, 0x68, 0x69,
+	0x74, 0x65, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x77,
+	0x68, 0x69, 0x74, 0x65, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x64, 0x64,
+	0x5f, 0x6b, 0x65, 0x79, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0b, 0x61, 0x64, 0x64, 0x4b, 0x65, 0x79, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x27, 0x0a, 0x0f,
+	0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x5f, 0x6b, 0x65, 0x79, 0x77, 0x6f, 0x72, 0x64, 0x73, 0x18,
+	0x0b, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4b, 0x65, 0x79,
+	0x77, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x2d, 0x0a, 0x05, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x0c,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x62, 0x75, 0x67, 0x7a, 0x69, 0x6c, 0x6c, 0x61, 0x2e,
+	0x76, 0x31, 0x2e, 0x46, 0x6c, 0x61, 0x67, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x05, 0x66,
+	0x6c, 0x61, 0x67, 0x73, 0x12, 0x31, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x18,
+	0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x62, 0x75, 0x67, 0x7a, 0x69, 0x6c, 0x6c, 0x61,
+	0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x65, 0x77, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x07,
+	0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x69, 0x6e, 0x6f, 0x72,
+	0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x6d,
+	0x69, 0x6e, 0x6f, 0x72, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x22, 0x56, 0x0a, 0x11, 0x41, 0x64,
+	0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x31, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x62, 0x75, 0x67, 0x7a, 0x69, 0x6c, 0x6c, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x4e,
+	0x65, 0x77, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65,
+	0x6e, 0x74, 0x22, 0x3a, 0x0a, 0x0a, 0x4e, 0x65, 0x77, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x62, 0x6f, 0x64, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x22, 0x56,
+	0x0a, 0x0a, 0x46, 0x6c, 0x61, 0x67, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x65, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x65, 0x65, 0x22, 0x68, 0x0a, 0x04, 0x46, 0x6c, 0x61, 0x67, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65,
+	0x74, 0x74, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x74, 0x74,
+	0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x65,
+	0x22, 0xcc, 0x01, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x6f,
+	0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x6f, 0x72,
+	0x12, 0x3f, 0x0a, 0x0d, 0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x0c, 0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x61, 0x67, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x22,
+	0xae, 0x05, 0x0a, 0x03, 0x42, 0x75, 0x67, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61,
+	0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72,
+	0x79, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x73,
+	0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72,
+	0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x6f,
+	0x64, 0x75, 0x63, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x64,
+	0x75, 0x63, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74,
+	0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x0e, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x72, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x18, 0x08, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x65, 0x6c, 0x65, 0x61,
+	0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x5f, 0x74,
+	0x6f, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65,
+	0x64, 0x54, 0x6f, 0x12, 0x1d, 0x0a, 0x0a, 0x71, 0x61, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63,
+	0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x71, 0x61, 0x43, 0x6f, 0x6e, 0x74, 0x61,
+	0x63, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x1a, 0x0a, 0x08,
+	0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x76, 0x65,
+	0x72, 0x69, 0x74, 0x79, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x76, 0x65,
+	0x72, 0x69, 0x74, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x77, 0x6f, 0x72, 0x64, 0x73,
+	0x18, 0x0e, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x6b, 0x65, 0x79, 0x77, 0x6f, 0x72, 0x64, 0x73,
+	0x12, 0x1e, 0x0a, 0x0a, 0x77, 0x68, 0x69, 0x74, 0x65, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x18, 0x0f,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x77, 0x68, 0x69, 0x74, 0x65, 0x62, 0x6f, 0x61, 0x72, 0x64,
+	0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x73, 0x5f, 0x6f, 0x6e, 0x18, 0x10,
+	0x20, 0x03, 0x28, 0x03, 0x52, 0x09, 0x64, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x73, 0x4f, 0x6e, 0x12,
+	0x16, 0x0a, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x11, 0x20, 0x03, 0x28, 0x03, 0x52,
+	0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x27, 0x0a, 0x05, 0x66, 0x6c, 0x61, 0x67, 0x73,
+	0x18, 0x12, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x62, 0x75, 0x67, 0x7a, 0x69, 0x6c, 0x6c,
+	0x61, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x05, 0x66, 0x6c, 0x61, 0x67, 0x73,
+	0x12, 0x17, 0x0a, 0x07, 0x69, 0x73, 0x5f, 0x6f, 0x70, 0x65, 0x6e, 0x18, 0x13, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x06, 0x69, 0x73, 0x4f, 0x70, 0x65, 0x6e, 0x12, 0x3f, 0x0a, 0x0d, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0c, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x44, 0x0a, 0x10, 0x6c, 0x61,
+	0x73, 0x74, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x15,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x0e, 0x6c, 0x61, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x54, 0x69, 0x6d, 0x65,
+	0x32, 0xe0, 0x02, 0x0a, 0x08, 0x42, 0x75, 0x67, 0x7a, 0x69, 0x6c, 0x6c, 0x61, 0x12, 0x36, 0x0a,
+	0x06, 0x47, 0x65, 0x74, 0x42, 0x75, 0x67, 0x12, 0x1a, 0x2e, 0x62, 0x75, 0x67, 0x7a, 0x69, 0x6c,
+	0x6c, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x75, 0x67, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x62, 0x75, 0x67, 0x7a, 0x69, 0x6c, 0x6c, 0x61, 0x2e, 0x76,
+	0x31, 0x2e, 0x42, 0x75, 0x67, 0x12, 0x40, 0x0a, 0x0a, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x42,
+	0x75, 0x67, 0x73, 0x12, 0x1e, 0x2e, 0x62, 0x75, 0x67, 0x7a, 0x69, 0x6c, 0x6c, 0x61, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x42, 0x75, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x62, 0x75, 0x67, 0x7a, 0x69, 0x6c, 0x6c, 0x61, 0x2e, 0x76,
+	0x31, 0x2e, 0x42, 0x75, 0x67, 0x30, 0x01, 0x12, 0x50, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x43, 0x6f,
+	0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x1f, 0x2e, 0x62, 0x75, 0x67, 0x7a, 0x69, 0x6c, 0x6c,
+	0x61, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x62, 0x75, 0x67, 0x7a, 0x69, 0x6c,
+	0x6c, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x42, 0x0a, 0x09, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x42, 0x75, 0x67, 0x12, 0x1d, 0x2e, 0x62, 0x75, 0x67, 0x7a, 0x69, 0x6c, 0x6c,
+	0x61, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x75, 0x67, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x44, 0x0a,
+	0x0a, 0x41, 0x64, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1e, 0x2e, 0x62, 0x75,
+	0x67, 0x7a, 0x69, 0x6c, 0x6c, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x64, 0x64, 0x43, 0x6f, 0x6d,
+	0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x42, 0x2f, 0x5a, 0x2d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x65, 0x70, 0x61, 0x72, 0x69, 0x73, 0x2f, 0x62, 0x75, 0x67, 0x7a, 0x69, 0x6c, 0x6c,
+	0x61, 0x2f, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2f, 0x62, 0x75, 0x67, 0x7a, 0x69, 0x6c,
+	0x6c, 0x61, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_bugzilla_proto_rawDescOnce sync.Once
+	file_bugzilla_proto_rawDescData = file_bugzilla_proto_rawDesc
+)
+
+func file_bugzilla_proto_rawDescGZIP() []byte {
+	file_bugzilla_proto_rawDescOnce.Do(func() {
+		file_bugzilla_proto_rawDescData = protoimpl.X.CompressGZIP(file_bugzilla_proto_rawDescData)
+	})
+	return file_bugzilla_proto_rawDescData
+}
+
+var file_bugzilla_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_bugzilla_proto_goTypes = []interface{}{
+	(*GetBugRequest)(nil),       // 0: bugzilla.v1.GetBugRequest
+	(*SearchBugsRequest)(nil),   // 1: bugzilla.v1.SearchBugsRequest
+	(*GetCommentsRequest)(nil),  // 2: bugzilla.v1.GetCommentsRequest
+	(*GetCommentsResponse)(nil), // 3: bugzilla.v1.GetCommentsResponse
+	(*UpdateBugRequest)(nil),    // 4: bugzilla.v1.UpdateBugRequest
+	(*AddCommentRequest)(nil),   // 5: bugzilla.v1.AddCommentRequest
+	(*NewComment)(nil),          // 6: bugzilla.v1.NewComment
+	(*FlagChange)(nil),          // 7: bugzilla.v1.FlagChange
+	(*Flag)(nil),                // 8: bugzilla.v1.Flag
+	(*Comment)(nil),             // 9: bugzilla.v1.Comment
+	(*Bug)(nil),                 // 10: bugzilla.v1.Bug
+	(*timestamp.Timestamp)(nil), // 11: google.protobuf.Timestamp
+	(*empty.Empty)(nil),         // 12: google.protobuf.Empty
+}
+var file_bugzilla_proto_depIdxs = []int32{
+	9,  // 0: bugzilla.v1.GetCommentsResponse.comments:type_name -> bugzilla.v1.Comment
+	7,  // 1: bugzilla.v1.UpdateBugRequest.flags:type_name -> bugzilla.v1.FlagChange
+	6,  // 2: bugzilla.v1.UpdateBugRequest.comment:type_name -> bugzilla.v1.NewComment
+	6,  // 3: bugzilla.v1.AddCommentRequest.comment:type_name -> bugzilla.v1.NewComment
+	11, // 4: bugzilla.v1.Comment.creation_time:type_name -> google.protobuf.Timestamp
+	8,  // 5: bugzilla.v1.Bug.flags:type_name -> bugzilla.v1.Flag
+	11, // 6: bugzilla.v1.Bug.creation_time:type_name -> google.protobuf.Timestamp
+	11, // 7: bugzilla.v1.Bug.last_change_time:type_name -> google.protobuf.Timestamp
+	0,  // 8: bugzilla.v1.Bugzilla.GetBug:input_type -> bugzilla.v1.GetBugRequest
+	1,  // 9: bugzilla.v1.Bugzilla.SearchBugs:input_type -> bugzilla.v1.SearchBugsRequest
+	2,  // 10: bugzilla.v1.Bugzilla.GetComments:input_type -> bugzilla.v1.GetCommentsRequest
+	4,  // 11: bugzilla.v1.Bugzilla.UpdateBug:input_type -> bugzilla.v1.UpdateBugRequest
+	5,  // 12: bugzilla.v1.Bugzilla.AddComment:input_type -> bugzilla.v1.AddCommentRequest
+	10, // 13: bugzilla.v1.Bugzilla.GetBug:output_type -> bugzilla.v1.Bug
+	10, // 14: bugzilla.v1.Bugzilla.SearchBugs:output_type -> bugzilla.v1.Bug
+	3,  // 15: bugzilla.v1.Bugzilla.GetComments:output_type -> bugzilla.v1.GetCommentsResponse
+	12, // 16: bugzilla.v1.Bugzilla.UpdateBug:output_type -> google.protobuf.Empty
+	12, // 17: bugzilla.v1.Bugzilla.AddComment:output_type -> google.protobuf.Empty
+	13, // [13:18] is the sub-list for method output_type
+	8,  // [8:13] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_bugzilla_proto_init() }
+func file_bugzilla_proto_init() {
+	if File_bugzilla_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_bugzilla_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBugRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bugzilla_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchBugsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bugzilla_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCommentsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bugzilla_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCommentsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bugzilla_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateBugRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bugzilla_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddCommentRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bugzilla_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NewComment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bugzilla_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FlagChange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bugzilla_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Flag); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bugzilla_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Comment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bugzilla_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Bug); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_bugzilla_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_bugzilla_proto_goTypes,
+		DependencyIndexes: file_bugzilla_proto_depIdxs,
+		MessageInfos:      file_bugzilla_proto_msgTypes,
+	}.Build()
+	File_bugzilla_proto = out.File
+	file_bugzilla_proto_rawDesc = nil
+	file_bugzilla_proto_goTypes = nil
+	file_bugzilla_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// BugzillaClient is the client API for Bugzilla service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type BugzillaClient interface {
+	// GetBug retrieves a bug by its ID.
+	GetBug(ctx context.Context, in *GetBugRequest, opts ...grpc.CallOption) (*Bug, error)
+	// SearchBugs streams the bugs matching a search, page by page.
+	SearchBugs(ctx context.Context, in *SearchBugsRequest, opts ...grpc.CallOption) (Bugzilla_SearchBugsClient, error)
+	// GetComments retrieves the comments of a bug, the description first.
+	GetComments(ctx context.Context, in *GetCommentsRequest, opts ...grpc.CallOption) (*GetCommentsResponse, error)
+	// UpdateBug updates the fields of a bug which are set in the request.
+	UpdateBug(ctx context.Context, in *UpdateBugRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// AddComment adds a comment to a bug.
+	AddComment(ctx context.Context, in *AddCommentRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+}
+
+type bugzillaClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBugzillaClient(cc grpc.ClientConnInterface) BugzillaClient {
+	return &bugzillaClient{cc}
+}
+
+func (c *bugzillaClient) GetBug(ctx context.Context, in *GetBugRequest, opts ...grpc.CallOption) (*Bug, error) {
+	out := new(Bug)
+	err := c.cc.Invoke(ctx, "/bugzilla.v1.Bugzilla/GetBug", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bugzillaClient) SearchBugs(ctx context.Context, in *SearchBugsRequest, opts ...grpc.CallOption) (Bugzilla_SearchBugsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Bugzilla_serviceDesc.Streams[0], "/bugzilla.v1.Bugzilla/SearchBugs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bugzillaSearchBugsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Bugzilla_SearchBugsClient interface {
+	Recv() (*Bug, error)
+	grpc.ClientStream
+}
+
+type bugzillaSearchBugsClient struct {
+	grpc.ClientStream
+}
+
+func (x *bugzillaSearchBugsClient) Recv() (*Bug, error) {
+	m := new(Bug)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bugzillaClient) GetComments(ctx context.Context, in *GetCommentsRequest, opts ...grpc.CallOption) (*GetCommentsResponse, error) {
+	out := new(GetCommentsResponse)
+	err := c.cc.Invoke(ctx, "/bugzilla.v1.Bugzilla/GetComments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bugzillaClient) UpdateBug(ctx context.Context, in *UpdateBugRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/bugzilla.v1.Bugzilla/UpdateBug", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bugzillaClient) AddComment(ctx context.Context, in *AddCommentRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/bugzilla.v1.Bugzilla/AddComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BugzillaServer is the server API for Bugzilla service.
+type BugzillaServer interface {
+	// GetBug retrieves a bug by its ID.
+	GetBug(context.Context, *GetBugRequest) (*Bug, error)
+	// SearchBugs streams the bugs matching a search, page by page.
+	SearchBugs(*SearchBugsRequest, Bugzilla_SearchBugsServer) error
+	// GetComments retrieves the comments of a bug, the description first.
+	GetComments(context.Context, *GetCommentsRequest) (*GetCommentsResponse, error)
+	// UpdateBug updates the fields of a bug which are set in the request.
+	UpdateBug(context.Context, *UpdateBugRequest) (*empty.Empty, error)
+	// AddComment adds a comment to a bug.
+	AddComment(context.Context, *AddCommentRequest) (*empty.Empty, error)
+}
+
+// UnimplementedBugzillaServer can be embedded to have forward compatible implementations.
+type UnimplementedBugzillaServer struct {
+}
+
+func (*UnimplementedBugzillaServer) GetBug(context.Context, *GetBugRequest) (*Bug, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBug not implemented")
+}
+func (*UnimplementedBugzillaServer) SearchBugs(*SearchBugsRequest, Bugzilla_SearchBugsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SearchBugs not implemented")
+}
+func (*UnimplementedBugzillaServer) GetComments(context.Context, *GetCommentsRequest) (*GetCommentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetComments not implemented")
+}
+func (*UnimplementedBugzillaServer) UpdateBug(context.Context, *UpdateBugRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateBug not implemented")
+}
+func (*UnimplementedBugzillaServer) AddComment(context.Context, *AddCommentRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddComment not implemented")
+}
+
+func RegisterBugzillaServer(s *grpc.Server, srv BugzillaServer) {
+	s.RegisterService(&_Bugzilla_serviceDesc, srv)
+}
+
+func _Bugzilla_GetBug_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBugRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BugzillaServer).GetBug(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bugzilla.v1.Bugzilla/GetBug",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BugzillaServer).GetBug(ctx, req.(*GetBugRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Bugzilla_SearchBugs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchBugsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BugzillaServer).SearchBugs(m, &bugzillaSearchBugsServer{stream})
+}
+
+type Bugzilla_SearchBugsServer interface {
+	Send(*Bug) error
+	grpc.ServerStream
+}
+
+type bugzillaSearchBugsServer struct {
+	grpc.ServerStream
+}
+
+func (x *bugzillaSearchBugsServer) Send(m *Bug) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Bugzilla_GetComments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCommentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BugzillaServer).GetComments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bugzilla.v1.Bugzilla/GetComments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BugzillaServer).GetComments(ctx, req.(*GetCommentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Bugzilla_UpdateBug_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateBugRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BugzillaServer).UpdateBug(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bugzilla.v1.Bugzilla/UpdateBug",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BugzillaServer).UpdateBug(ctx, req.(*UpdateBugRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Bugzilla_AddComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddCommentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BugzillaServer).AddComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bugzilla.v1.Bugzilla/AddComment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BugzillaServer).AddComment(ctx, req.(*AddCommentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Bugzilla_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "bugzilla.v1.Bugzilla",
+	HandlerType: (*BugzillaServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetBug",
+			Handler:    _Bugzilla_GetBug_Handler,
+		},
+		{
+			MethodName: "GetComments",
+			Handler:    _Bugzilla_GetComments_Handler,
+		},
+		{
+			MethodName: "UpdateBug",
+			Handler:    _Bugzilla_UpdateBug_Handler,
+		},
+		{
+			MethodName: "AddComment",
+			Handler:    _Bugzilla_AddComment_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SearchBugs",
+			Handler:       _Bugzilla_SearchBugs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "bugzilla.proto",
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// bugzilla-gateway serves the Bugzilla gRPC service defined in
+// gateway/bugzillapb, so services which are not written in Go can use
+// Bugzilla through one audited gateway. Callers authenticate with a bearer
+// token from the callers file, which holds one caller per line:
+//
+//	<caller name> <token>
+//
+// so the gateway serves TLS with the given certificate unless it runs behind
+// something terminating TLS for it. Every call is logged with the name of
+// the caller.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/eparis/bugzilla"
+	"github.com/eparis/bugzilla/gateway"
+	"github.com/eparis/bugzilla/gateway/bugzillapb"
+	"github.com/eparis/bugzilla/logrusadapter"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+func main() {
+	address := flag.String("address", ":9090", "Address to serve on.")
+	endpoint := flag.String("endpoint", "https://bugzilla.redhat.com", "Bugzilla endpoint.")
+	apiKeyPath := flag.String("api-key-path", "", "Path to the file holding the Bugzilla API key.")
+	callersPath := flag.String("callers-path", "", "Path to the file holding the names and bearer tokens of the callers.")
+	qps := flag.Float64("qps", 5, "Requests per second allowed to Bugzilla on average.")
+	burst := flag.Int("burst", 10, "Requests allowed to Bugzilla in a burst.")
+	certPath := flag.String("tls-cert-path", "", "Path to the TLS certificate to serve with.")
+	keyPath := flag.String("tls-key-path", "", "Path to the key of the TLS certificate.")
+	plaintext := flag.Bool("insecure-plaintext", false, "Serve without TLS, only for running behind something terminating TLS.")
+	flag.Parse()
+
+	if *apiKeyPath == "" || *callersPath == "" {
+		logrus.Fatal("--api-key-path and --callers-path are required")
+	}
+	if (*certPath == "" || *keyPath == "") != *plaintext {
+		logrus.Fatal("either --tls-cert-path and --tls-key-path or --insecure-plaintext are required")
+	}
+	callers, err := loadCallers(*callersPath)
+	if err != nil {
+		logrus.WithError(err).Fatal("Could not load callers.")
+	}
+	client, err := bugzilla.NewClientFromSecretFile(*apiKeyPath, time.Minute, *endpoint,
+		bugzilla.WithRateLimit(*qps, *burst),
+		bugzilla.WithRetries(3, time.Second),
+		bugzilla.WithLogger(logrusadapter.New(logrus.NewEntry(logrus.StandardLogger()))),
+	)
+	if err != nil {
+		logrus.WithError(err).Fatal("Could not create Bugzilla client.")
+	}
+	listener, err := net.Listen("tcp", *address)
+	if err != nil {
+		logrus.WithError(err).Fatal("Could not listen.")
+	}
+	logger := logrus.WithField("component", "bugzilla-gateway")
+	options := []grpc.ServerOption{
+		grpc.UnaryInterceptor(gateway.UnaryInterceptor(callers, logger)),
+		grpc.StreamInterceptor(gateway.StreamInterceptor(callers, logger)),
+	}
+	if !*plaintext {
+		creds, err := credentials.NewServerTLSFromFile(*certPath, *keyPath)
+		if err != nil {
+			logrus.WithError(err).Fatal("Could not load TLS certificate.")
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+	server := grpc.NewServer(options...)
+	bugzillapb.RegisterBugzillaServer(server, gateway.NewServer(client))
+	logger.WithField("address", *address).Info("Serving Bugzilla gateway.")
+	if err := server.Serve(listener); err != nil {
+		logger.WithError(err).Fatal("Could not serve.")
+	}
+}
+
+func loadCallers(path string) (gateway.Callers, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read callers: %v", err)
+	}
+	callers := gateway.Callers{}
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d of %s is not of the form '<caller name> <token>'", line, path)
+		}
+		callers[fields[1]] = fields[0]
+	}
+	if len(callers) == 0 {
+		return nil, fmt.Errorf("no callers in %s", path)
+	}
+	return callers, nil
+}
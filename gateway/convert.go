@@ -0,0 +1,128 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"github.com/eparis/bugzilla"
+	"github.com/eparis/bugzilla/gateway/bugzillapb"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func bugToProto(bug *bugzilla.Bug) (*bugzillapb.Bug, error) {
+	creationTime, err := timestampToProto(bug.CreationTime)
+	if err != nil {
+		return nil, err
+	}
+	lastChangeTime, err := timestampToProto(bug.LastChangeTime)
+	if err != nil {
+		return nil, err
+	}
+	converted := &bugzillapb.Bug{
+		Id:             int64(bug.ID),
+		Summary:        bug.Summary,
+		Status:         bug.Status,
+		Resolution:     bug.Resolution,
+		Product:        bug.Product,
+		Component:      bug.Component,
+		Version:        bug.Version,
+		TargetRelease:  bug.TargetRelease,
+		AssignedTo:     bug.AssignedTo,
+		QaContact:      bug.QAContact,
+		Creator:        bug.Creator,
+		Priority:       bug.Priority,
+		Severity:       bug.Severity,
+		Keywords:       bug.Keywords,
+		Whiteboard:     bug.Whiteboard,
+		DependsOn:      idsToProto(bug.DependsOn),
+		Blocks:         idsToProto(bug.Blocks),
+		IsOpen:         bug.IsOpen,
+		CreationTime:   creationTime,
+		LastChangeTime: lastChangeTime,
+	}
+	for _, flag := range bug.Flags {
+		converted.Flags = append(converted.Flags, &bugzillapb.Flag{
+			Name:      flag.Name,
+			Status:    flag.Status,
+			Setter:    flag.Setter,
+			Requestee: flag.Requestee,
+		})
+	}
+	return converted, nil
+}
+
+func commentToProto(comment *bugzilla.Comment) (*bugzillapb.Comment, error) {
+	creationTime, err := timestampToProto(comment.CreationTime)
+	if err != nil {
+		return nil, err
+	}
+	return &bugzillapb.Comment{
+		Id:           int64(comment.Id),
+		Count:        int64(comment.Count),
+		Text:         comment.Text,
+		Creator:      comment.Creator,
+		CreationTime: creationTime,
+		Private:      comment.IsPrivate,
+		Tags:         comment.Tags,
+	}, nil
+}
+
+func updateFromProto(req *bugzillapb.UpdateBugRequest) bugzilla.BugUpdate {
+	update := bugzilla.BugUpdate{
+		Status:        req.Status,
+		Resolution:    req.Resolution,
+		TargetRelease: req.TargetRelease,
+		AssignedTo:    req.AssignedTo,
+		QAContact:     req.QaContact,
+		Priority:      req.Priority,
+		Severity:      req.Severity,
+		Whiteboard:    req.Whiteboard,
+		MinorUpdate:   req.MinorUpdate,
+	}
+	if len(req.AddKeywords) != 0 || len(req.RemoveKeywords) != 0 {
+		update.Keywords = &bugzilla.BugKeywords{Add: req.AddKeywords, Remove: req.RemoveKeywords}
+	}
+	for _, flag := range req.Flags {
+		update.Flags = append(update.Flags, bugzilla.FlagChange{Name: flag.Name, Status: flag.Status, Requestee: flag.Requestee})
+	}
+	if req.Comment != nil && req.Comment.Body != "" {
+		update.Comment = &bugzilla.BugComment{Body: req.Comment.Body, Private: req.Comment.Private}
+	}
+	return update
+}
+
+func idsToProto(ids []int) []int64 {
+	var converted []int64
+	for _, id := range ids {
+		converted = append(converted, int64(id))
+	}
+	return converted
+}
+
+// timestampToProto converts a time, leaving it unset if it was not reported
+func timestampToProto(t bugzilla.Timestamp) (*timestamp.Timestamp, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	converted, err := ptypes.TimestampProto(t.Time)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "invalid time %v: %v", t, err)
+	}
+	return converted, nil
+}
@@ -0,0 +1,13 @@
+module github.com/eparis/bugzilla/gateway
+
+go 1.14
+
+require (
+	github.com/eparis/bugzilla v0.0.0
+	github.com/golang/protobuf v1.4.0
+	github.com/sirupsen/logrus v1.6.0
+	google.golang.org/grpc v1.29.1
+	google.golang.org/protobuf v1.21.0
+)
+
+replace github.com/eparis/bugzilla => ../
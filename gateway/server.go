@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gateway serves the Bugzilla gRPC service defined in bugzillapb on
+// top of a Client, so services which are not written in Go can use Bugzilla
+// through one audited gateway. Go callers can use the client generated in
+// bugzillapb.
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/eparis/bugzilla"
+	"github.com/eparis/bugzilla/gateway/bugzillapb"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements the Bugzilla gRPC service using a Client
+type Server struct {
+	client bugzilla.Client
+}
+
+// NewServer creates a Server which sends all requests through the client
+func NewServer(client bugzilla.Client) *Server {
+	return &Server{client: client}
+}
+
+// the Server implements the Bugzilla gRPC service
+var _ bugzillapb.BugzillaServer = &Server{}
+
+func (s *Server) GetBug(ctx context.Context, req *bugzillapb.GetBugRequest) (*bugzillapb.Bug, error) {
+	bug, err := s.client.GetBug(int(req.Id))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return bugToProto(bug)
+}
+
+func (s *Server) SearchBugs(req *bugzillapb.SearchBugsRequest, stream bugzillapb.Bugzilla_SearchBugsServer) error {
+	if req.Query == "" {
+		return status.Error(codes.InvalidArgument, "a search query is required")
+	}
+	iter := s.client.SearchBugsIter(bugzilla.Query{Raw: req.Query, IncludeFields: req.IncludeFields})
+	for iter.Next() {
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		bug, err := bugToProto(iter.Bug())
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(bug); err != nil {
+			return err
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return toStatus(err)
+	}
+	return nil
+}
+
+func (s *Server) GetComments(ctx context.Context, req *bugzillapb.GetCommentsRequest) (*bugzillapb.GetCommentsResponse, error) {
+	comments, err := s.client.GetBugComments(int(req.Id))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	response := &bugzillapb.GetCommentsResponse{}
+	for i := range comments {
+		comment, err := commentToProto(&comments[i])
+		if err != nil {
+			return nil, err
+		}
+		response.Comments = append(response.Comments, comment)
+	}
+	return response, nil
+}
+
+func (s *Server) UpdateBug(ctx context.Context, req *bugzillapb.UpdateBugRequest) (*empty.Empty, error) {
+	if err := s.client.UpdateBug(int(req.Id), updateFromProto(req)); err != nil {
+		return nil, toStatus(err)
+	}
+	return &empty.Empty{}, nil
+}
+
+func (s *Server) AddComment(ctx context.Context, req *bugzillapb.AddCommentRequest) (*empty.Empty, error) {
+	if req.Comment == nil || req.Comment.Body == "" {
+		return nil, status.Error(codes.InvalidArgument, "the comment body is required")
+	}
+	update := bugzilla.BugUpdate{Comment: &bugzilla.BugComment{Body: req.Comment.Body, Private: req.Comment.Private}}
+	if err := s.client.UpdateBug(int(req.Id), update); err != nil {
+		return nil, toStatus(err)
+	}
+	return &empty.Empty{}, nil
+}
+
+// toStatus converts an error of the client to a gRPC status error. Only
+// errors which may go away by themselves are Unavailable, so callers do not
+// retry requests Bugzilla rejected.
+func toStatus(err error) error {
+	var reqError *bugzilla.RequestError
+	switch {
+	case bugzilla.IsNotFound(err), bugzilla.IsInvalidBug(err):
+		return status.Error(codes.NotFound, err.Error())
+	case bugzilla.IsForbidden(err):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case bugzilla.IsRateLimited(err):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case bugzilla.IsUnauthorized(err):
+		return status.Error(codes.Internal, "the gateway could not authenticate with Bugzilla")
+	case bugzilla.IsMidairCollision(err):
+		return status.Error(codes.Aborted, err.Error())
+	case bugzilla.IsReadOnly(err):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case bugzilla.IsValidationError(err):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.As(err, &reqError) && reqError.StatusCode == http.StatusConflict:
+		return status.Error(codes.Aborted, err.Error())
+	case errors.As(err, &reqError) && reqError.StatusCode == http.StatusPreconditionFailed:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.As(err, &reqError) && reqError.StatusCode >= 400 && reqError.StatusCode < 500:
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Unavailable, err.Error())
+	}
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eparis/bugzilla"
+	"github.com/eparis/bugzilla/gateway/bugzillapb"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T, fake *bugzilla.Fake) (bugzillapb.BugzillaClient, func()) {
+	listener := bufconn.Listen(1024 * 1024)
+	logger := logrus.WithField("testing", "true")
+	callers := Callers{"secret": "tester"}
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryInterceptor(callers, logger)),
+		grpc.StreamInterceptor(StreamInterceptor(callers, logger)),
+	)
+	bugzillapb.RegisterBugzillaServer(server, NewServer(fake))
+	go server.Serve(listener)
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return listener.Dial()
+	}))
+	if err != nil {
+		t.Fatalf("could not dial the server: %v", err)
+	}
+	return bugzillapb.NewBugzillaClient(conn), func() {
+		conn.Close()
+		server.Stop()
+	}
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestServer(t *testing.T) {
+	fake := &bugzilla.Fake{
+		Bugs: map[int]bugzilla.Bug{
+			1: {ID: 1, Summary: "flake", Status: "NEW", Flags: []bugzilla.Flag{{Name: "blocker", Status: "+"}}},
+			2: {ID: 2, Summary: "crash", Status: "ASSIGNED"},
+		},
+		BugComments: map[int][]bugzilla.Comment{
+			1: {{BugId: 1, Count: 0, Text: "it flaked"}},
+		},
+	}
+	client, cleanup := newTestClient(t, fake)
+	defer cleanup()
+	ctx := withToken("secret")
+
+	if _, err := client.GetBug(withToken("guess"), &bugzillapb.GetBugRequest{Id: 1}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected an invalid token to be rejected, got %v", err)
+	}
+
+	bug, err := client.GetBug(ctx, &bugzillapb.GetBugRequest{Id: 1})
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if bug.Summary != "flake" || len(bug.Flags) != 1 || bug.Flags[0].Name != "blocker" || bug.CreationTime != nil {
+		t.Errorf("got incorrect bug: %v", bug)
+	}
+	if _, err := client.GetBug(ctx, &bugzillapb.GetBugRequest{Id: 3}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected a missing bug to not be found, got %v", err)
+	}
+
+	stream, err := client.SearchBugs(ctx, &bugzillapb.SearchBugsRequest{Query: "product=OpenShift"})
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	found := map[int64]bool{}
+	for {
+		bug, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("expected no error, but got one: %v", err)
+		}
+		found[bug.Id] = true
+	}
+	if len(found) != 2 || !found[1] || !found[2] {
+		t.Errorf("expected both bugs to be found, got %v", found)
+	}
+
+	comments, err := client.GetComments(ctx, &bugzillapb.GetCommentsRequest{Id: 1})
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if len(comments.Comments) != 1 || comments.Comments[0].Text != "it flaked" {
+		t.Errorf("got incorrect comments: %v", comments)
+	}
+
+	if _, err := client.UpdateBug(ctx, &bugzillapb.UpdateBugRequest{Id: 2, Status: "POST", AddKeywords: []string{"Regression"}}); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if updated := fake.Bugs[2]; updated.Status != "POST" || len(updated.Keywords) != 1 {
+		t.Errorf("expected the update to be applied, got %#v", updated)
+	}
+	if _, err := client.AddComment(ctx, &bugzillapb.AddCommentRequest{Id: 2}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected an empty comment to be rejected, got %v", err)
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		err      error
+		expected codes.Code
+	}{
+		{
+			name:     "missing bug",
+			err:      &bugzilla.RequestError{StatusCode: http.StatusNotFound},
+			expected: codes.NotFound,
+		},
+		{
+			name:     "rejected request",
+			err:      &bugzilla.RequestError{StatusCode: http.StatusBadRequest, Code: 51},
+			expected: codes.InvalidArgument,
+		},
+		{
+			name:     "wrapped rejected request",
+			err:      fmt.Errorf("updating: %w", &bugzilla.RequestError{StatusCode: http.StatusUnprocessableEntity}),
+			expected: codes.InvalidArgument,
+		},
+		{
+			name:     "mid-air collision",
+			err:      &bugzilla.MidairCollisionError{ID: 1, Err: errors.New("changed")},
+			expected: codes.Aborted,
+		},
+		{
+			name:     "conflict",
+			err:      &bugzilla.RequestError{StatusCode: http.StatusConflict},
+			expected: codes.Aborted,
+		},
+		{
+			name:     "failed precondition",
+			err:      &bugzilla.RequestError{StatusCode: http.StatusPreconditionFailed},
+			expected: codes.FailedPrecondition,
+		},
+		{
+			name:     "read-only client",
+			err:      &bugzilla.ReadOnlyError{Method: "UpdateBug"},
+			expected: codes.FailedPrecondition,
+		},
+		{
+			name:     "invalid update",
+			err:      &bugzilla.ValidationError{Problems: []string{"summary is required"}},
+			expected: codes.InvalidArgument,
+		},
+		{
+			name:     "rate limited",
+			err:      &bugzilla.RequestError{StatusCode: http.StatusTooManyRequests},
+			expected: codes.ResourceExhausted,
+		},
+		{
+			name:     "server error",
+			err:      &bugzilla.RequestError{StatusCode: http.StatusBadGateway},
+			expected: codes.Unavailable,
+		},
+		{
+			name:     "transport error",
+			err:      errors.New("connection reset"),
+			expected: codes.Unavailable,
+		},
+	}
+	for _, testCase := range testCases {
+		if actual := status.Code(toStatus(testCase.err)); actual != testCase.expected {
+			t.Errorf("%s: expected code %v, got %v", testCase.name, testCase.expected, actual)
+		}
+	}
+}
+
+func TestAuditDoesNotRecordRequests(t *testing.T) {
+	var out bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&out)
+	req := &bugzillapb.AddCommentRequest{Id: 2, Comment: &bugzillapb.NewComment{Body: "private details"}}
+	audit(context.Background(), logrus.NewEntry(logger), "/bugzilla.Bugzilla/AddComment", "tester", req, time.Now(), nil)
+	if strings.Contains(out.String(), "private details") {
+		t.Errorf("expected the comment not to be recorded, got %s", out.String())
+	}
+	if !strings.Contains(out.String(), "bug=2") || !strings.Contains(out.String(), "caller=tester") {
+		t.Errorf("expected the bug and caller to be recorded, got %s", out.String())
+	}
+}
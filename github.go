@@ -0,0 +1,44 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import "strings"
+
+// WithGitHubTrackers sets the URLs of the external trackers for GitHub, like
+// https://github.mycorp.com/ for GitHub Enterprise, instead of GitHubTracker.
+// AddPullRequestAsExternalBug links pull requests in the first tracker, while
+// pull requests in any of them are returned by GetExternalBugPRsOnBug and
+// GetExternalBugPRsOnBugs. The URLs get a trailing slash if they lack one.
+func WithGitHubTrackers(trackers ...string) Option {
+	return func(c *client) {
+		c.githubTrackers = nil
+		for _, tracker := range trackers {
+			if !strings.HasSuffix(tracker, "/") {
+				tracker += "/"
+			}
+			c.githubTrackers = append(c.githubTrackers, tracker)
+		}
+	}
+}
+
+// gitHubTrackers returns the URLs of the external trackers for GitHub
+func (c *client) gitHubTrackers() []string {
+	if len(c.githubTrackers) == 0 {
+		return []string{GitHubTracker}
+	}
+	return c.githubTrackers
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package github links GitHub pull requests and Bugzilla bugs: it finds the
+// bugs which reference a pull request as an external bug, links them both as
+// external bugs and "see also" URLs and moves bugs along as their pull
+// requests are opened and merged.
+package github
+
+import (
+	"fmt"
+
+	"github.com/eparis/bugzilla"
+)
+
+// Tracker is the URL of the GitHub external bug tracker
+const Tracker = "https://github.com/"
+
+// Pull identifies a pull request
+type Pull struct {
+	Org    string
+	Repo   string
+	Number int
+}
+
+// ParsePull parses the external bug identifier of a pull request, like
+// "org/repo/pull/1". It returns an error which matches
+// bugzilla.IsIdentifierNotForPullErr for the identifiers of other GitHub
+// objects, like issues.
+func ParsePull(identifier string) (Pull, error) {
+	org, repo, number, err := bugzilla.PullFromIdentifier(identifier)
+	if err != nil {
+		return Pull{}, err
+	}
+	return Pull{Org: org, Repo: repo, Number: number}, nil
+}
+
+// Identifier returns the external bug identifier of the pull request
+func (p Pull) Identifier() string {
+	return bugzilla.IdentifierForPull(p.Org, p.Repo, p.Number)
+}
+
+// URL returns the URL of the pull request, which is how it is referenced in
+// the "see also" field of bugs
+func (p Pull) URL() string {
+	return Tracker + p.Identifier()
+}
+
+func (p Pull) String() string {
+	return fmt.Sprintf("%s/%s#%d", p.Org, p.Repo, p.Number)
+}
+
+// Link links the pull request to the bug as an external bug and as a "see
+// also" URL. It returns true if the bug was changed, i.e. if either link was
+// missing.
+func Link(c bugzilla.Client, id int, pull Pull) (bool, error) {
+	changed, err := c.AddPullRequestAsExternalBug(id, pull.Org, pull.Repo, pull.Number)
+	if err != nil {
+		return false, err
+	}
+	bug, err := c.GetBugWithFields(id, []string{"id", "see_also"})
+	if err != nil {
+		return changed, err
+	}
+	for _, url := range bug.SeeAlso {
+		if url == pull.URL() {
+			return changed, nil
+		}
+	}
+	if err := c.UpdateBug(id, bugzilla.BugUpdate{SeeAlso: &bugzilla.BugSeeAlso{Add: []string{pull.URL()}}}); err != nil {
+		return changed, err
+	}
+	return true, nil
+}
+
+// PullsForBug returns the pull requests linked to the bug as external bugs
+func PullsForBug(c bugzilla.Client, id int) ([]Pull, error) {
+	external, err := c.GetExternalBugs(id)
+	if err != nil {
+		return nil, err
+	}
+	var pulls []Pull
+	for _, bug := range external {
+		if bug.Type.URL != Tracker {
+			continue
+		}
+		pull, err := ParsePull(bug.ExternalBugID)
+		if bugzilla.IsIdentifierNotForPullErr(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		pulls = append(pulls, pull)
+	}
+	return pulls, nil
+}
+
+// BugsForPull returns the bugs which reference the pull request as an
+// external bug, with their ID, status and external bugs
+func BugsForPull(c bugzilla.Client, pull Pull) ([]*bugzilla.Bug, error) {
+	candidates, err := c.Search(bugzilla.Query{
+		Advanced: []bugzilla.AdvancedQuery{{
+			Field: "ext_bz_bug_map.ext_bz_bug_id",
+			Op:    "equals",
+			Value: pull.Identifier(),
+		}},
+		IncludeFields: []string{"id", "status", "resolution", "external_bugs"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	// the server matches the identifier for any tracker
+	var bugs []*bugzilla.Bug
+	for _, bug := range candidates {
+		for _, external := range bug.ExternalBugs {
+			if external.Type.URL == Tracker && external.ExternalBugID == pull.Identifier() {
+				bugs = append(bugs, bug)
+				break
+			}
+		}
+	}
+	return bugs, nil
+}
+
+// PullState is the state of a pull request
+type PullState string
+
+const (
+	PullOpen   PullState = "open"
+	PullMerged PullState = "merged"
+	PullClosed PullState = "closed"
+)
+
+// StatusSync moves the bugs referencing a pull request along as the pull
+// request is opened and merged
+type StatusSync struct {
+	Client bugzilla.Client
+	// Statuses maps the states of pull requests to the status their bugs
+	// move to. Bugs are left alone for states without a status. By default,
+	// bugs move to POST when their pull request is opened and to MODIFIED
+	// when it merges.
+	Statuses map[PullState]string
+	// From are the statuses bugs are moved from, so bugs which moved on, e.g.
+	// to VERIFIED, are not moved back. By default bugs are moved from NEW,
+	// ASSIGNED and POST.
+	From []string
+}
+
+// DefaultStatuses are the statuses bugs move to if StatusSync.Statuses is nil
+var DefaultStatuses = map[PullState]string{
+	PullOpen:   "POST",
+	PullMerged: "MODIFIED",
+}
+
+// DefaultFrom are the statuses bugs are moved from if StatusSync.From is nil
+var DefaultFrom = []string{"NEW", "ASSIGNED", "POST"}
+
+// Sync moves the bugs referencing the pull request to the status for its
+// state and returns the IDs of the bugs which were moved
+func (s *StatusSync) Sync(pull Pull, state PullState) ([]int, error) {
+	statuses, from := s.Statuses, s.From
+	if statuses == nil {
+		statuses = DefaultStatuses
+	}
+	if from == nil {
+		from = DefaultFrom
+	}
+	status, ok := statuses[state]
+	if !ok {
+		return nil, nil
+	}
+	bugs, err := BugsForPull(s.Client, pull)
+	if err != nil {
+		return nil, err
+	}
+	var moved []int
+	for _, bug := range bugs {
+		if bug.Status == status || !contains(from, bug.Status) {
+			continue
+		}
+		if err := s.Client.UpdateBug(bug.ID, bugzilla.BugUpdate{Status: status}); err != nil {
+			return moved, fmt.Errorf("could not move bug %d to %s: %v", bug.ID, status, err)
+		}
+		moved = append(moved, bug.ID)
+	}
+	return moved, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
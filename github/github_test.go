@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/eparis/bugzilla"
+)
+
+func TestParsePull(t *testing.T) {
+	pull, err := ParsePull("org/repo/pull/12")
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if expected := (Pull{Org: "org", Repo: "repo", Number: 12}); pull != expected {
+		t.Errorf("expected %v, got %v", expected, pull)
+	}
+	if actual, expected := pull.URL(), "https://github.com/org/repo/pull/12"; actual != expected {
+		t.Errorf("expected URL %q, got %q", expected, actual)
+	}
+	if _, err := ParsePull("org/repo/issues/12"); !bugzilla.IsIdentifierNotForPullErr(err) {
+		t.Errorf("expected an error for an issue, got %v", err)
+	}
+}
+
+func githubBug(identifier string) bugzilla.ExternalBug {
+	return bugzilla.ExternalBug{Type: bugzilla.ExternalBugType{URL: Tracker}, ExternalBugID: identifier}
+}
+
+func TestLink(t *testing.T) {
+	fake := &bugzilla.Fake{Bugs: map[int]bugzilla.Bug{1: {ID: 1}}, ExternalBugs: map[int][]bugzilla.ExternalBug{}}
+	pull := Pull{Org: "org", Repo: "repo", Number: 12}
+	for i, expected := range []bool{true, false} {
+		changed, err := Link(fake, 1, pull)
+		if err != nil {
+			t.Fatalf("expected no error, but got one: %v", err)
+		}
+		if changed != expected {
+			t.Errorf("link %d: expected changed %v, got %v", i, expected, changed)
+		}
+	}
+	if actual, expected := fake.Bugs[1].SeeAlso, []string{"https://github.com/org/repo/pull/12"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected see also %v, got %v", expected, actual)
+	}
+	if actual := fake.ExternalBugs[1]; len(actual) != 1 || actual[0].ExternalBugID != "org/repo/pull/12" {
+		t.Errorf("expected the pull request as external bug, got %v", actual)
+	}
+}
+
+func TestPullsForBug(t *testing.T) {
+	fake := &bugzilla.Fake{Bugs: map[int]bugzilla.Bug{1: {ID: 1}}, ExternalBugs: map[int][]bugzilla.ExternalBug{1: {
+		githubBug("org/repo/pull/12"),
+		githubBug("org/repo/issues/13"),
+		{Type: bugzilla.ExternalBugType{URL: "https://jira.example.com/"}, ExternalBugID: "org/repo/pull/14"},
+	}}}
+	pulls, err := PullsForBug(fake, 1)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if expected := []Pull{{Org: "org", Repo: "repo", Number: 12}}; !reflect.DeepEqual(pulls, expected) {
+		t.Errorf("expected pulls %v, got %v", expected, pulls)
+	}
+}
+
+func TestStatusSync(t *testing.T) {
+	pull := Pull{Org: "org", Repo: "repo", Number: 12}
+	fake := &bugzilla.Fake{Bugs: map[int]bugzilla.Bug{
+		1: {ID: 1, Status: "NEW", ExternalBugs: []bugzilla.ExternalBug{githubBug(pull.Identifier())}},
+		2: {ID: 2, Status: "VERIFIED", ExternalBugs: []bugzilla.ExternalBug{githubBug(pull.Identifier())}},
+		3: {ID: 3, Status: "NEW", ExternalBugs: []bugzilla.ExternalBug{githubBug("org/repo/pull/13")}},
+		4: {ID: 4, Status: "POST", ExternalBugs: []bugzilla.ExternalBug{githubBug(pull.Identifier())}},
+	}}
+	sync := &StatusSync{Client: fake}
+
+	moved, err := sync.Sync(pull, PullOpen)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if expected := []int{1}; !reflect.DeepEqual(moved, expected) {
+		t.Errorf("expected bugs %v to move when the pull opened, got %v", expected, moved)
+	}
+	if moved, err := sync.Sync(pull, PullClosed); err != nil || len(moved) != 0 {
+		t.Errorf("expected no bugs to move when the pull closed, got %v (error %v)", moved, err)
+	}
+	moved, err = sync.Sync(pull, PullMerged)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if len(moved) != 2 || fake.Bugs[1].Status != "MODIFIED" || fake.Bugs[4].Status != "MODIFIED" {
+		t.Errorf("expected bugs 1 and 4 to move when the pull merged, got %v", moved)
+	}
+	if fake.Bugs[2].Status != "VERIFIED" || fake.Bugs[3].Status != "NEW" {
+		t.Errorf("expected other bugs to be left alone, got %v and %v", fake.Bugs[2].Status, fake.Bugs[3].Status)
+	}
+}
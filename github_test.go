@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestGitHubTrackers(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		trackers []string
+		expected []string
+	}{
+		{
+			name:     "github.com is used by default",
+			expected: []string{GitHubTracker},
+		},
+		{
+			name:     "trackers get a trailing slash",
+			trackers: []string{"https://github.mycorp.com", "https://github.com/"},
+			expected: []string{"https://github.mycorp.com/", GitHubTracker},
+		},
+	}
+	for _, testCase := range testCases {
+		c := clientForUrl("").(*client)
+		if testCase.trackers != nil {
+			WithGitHubTrackers(testCase.trackers...)(c)
+		}
+		if actual := c.gitHubTrackers(); !reflect.DeepEqual(actual, testCase.expected) {
+			t.Errorf("%s: expected trackers %v, got %v", testCase.name, testCase.expected, actual)
+		}
+	}
+}
+
+func TestGitHubEnterprisePullRequests(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			raw, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				t.Errorf("failed to read request body: %v", err)
+			}
+			if !strings.Contains(string(raw), `"ext_type_url":"https://github.mycorp.com/"`) {
+				t.Errorf("expected the pull request to be linked in the enterprise tracker, got %s", raw)
+			}
+			w.Write([]byte(`{"error":null,"id":"identifier","result":{"bugs":[{"changes":{"ext_bz_bug_map.ext_bz_bug_id":{"added":"org/repo/pull/1","removed":""}},"id":1}]}}`))
+			return
+		}
+		fmt.Fprint(w, `{"bugs":[{"external_bugs":[`+
+			`{"bug_id":1,"ext_bz_bug_id":"org/repo/pull/1","type":{"url":"https://github.mycorp.com/"}},`+
+			`{"bug_id":1,"ext_bz_bug_id":"org/repo/pull/2","type":{"url":"https://github.com/"}}]}]}`)
+	}))
+	defer testServer.Close()
+	c := clientForUrl(testServer.URL).(*client)
+	WithGitHubTrackers("https://github.mycorp.com")(c)
+
+	changed, err := c.AddPullRequestAsExternalBug(1, "org", "repo", 1)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if !changed {
+		t.Error("expected the pull request to be added, but it was not")
+	}
+	prs, err := c.GetExternalBugPRsOnBug(1)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	expected := []ExternalBug{{Type: ExternalBugType{URL: "https://github.mycorp.com/"}, BugzillaBugID: 1, ExternalBugID: "org/repo/pull/1", Org: "org", Repo: "repo", Num: 1}}
+	if !reflect.DeepEqual(prs, expected) {
+		t.Errorf("got incorrect pull requests: %v", diff.ObjectReflectDiff(expected, prs))
+	}
+}
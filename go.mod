@@ -5,5 +5,9 @@ go 1.14
 require (
 	github.com/prometheus/client_golang v1.6.0
 	github.com/sirupsen/logrus v1.6.0
+	go.etcd.io/bbolt v1.3.6
+	golang.org/x/sys v0.0.0-20200923182605-d9f96fdee20d
+	gopkg.in/yaml.v2 v2.2.8
 	k8s.io/apimachinery v0.18.2
+	sigs.k8s.io/yaml v1.2.0
 )
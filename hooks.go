@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+)
+
+// WithRequestHook calls the hook with every request before it is sent,
+// including retries. The hook gets a copy of the request with a readable
+// body and with the credentials redacted, so it may be logged as it is.
+func WithRequestHook(hook func(*http.Request)) Option {
+	return func(c *client) {
+		c.requestHooks = append(c.requestHooks, hook)
+	}
+}
+
+// WithResponseHook calls the hook with every response received, whatever its
+// status. The hook gets a copy of the response with a readable body and with
+// the credentials in it and in its request redacted.
+func WithResponseHook(hook func(*http.Response)) Option {
+	return func(c *client) {
+		c.responseHooks = append(c.responseHooks, hook)
+	}
+}
+
+// WithPayloadLogging logs every request and response in full at debug level,
+// with the credentials redacted, to debug issues which need the exact
+// payloads to be understood.
+func WithPayloadLogging() Option {
+	return func(c *client) {
+		c.logPayloads = true
+	}
+}
+
+// observesPayloads returns true if requests and responses have to be copied
+// for hooks or payload logging
+func (c *client) observesPayloads(logger *logEntry) bool {
+	return len(c.requestHooks) > 0 || len(c.responseHooks) > 0 || c.logsPayloads(logger)
+}
+
+func (c *client) logsPayloads(logger *logEntry) bool {
+	return c.logPayloads && logger.logger.DebugEnabled()
+}
+
+// redactedHeader returns a copy of the header with credentials redacted
+func redactedHeader(header http.Header, secrets []string) http.Header {
+	copied := http.Header{}
+	for key, values := range header {
+		for _, value := range values {
+			copied.Add(key, redact(value, secrets))
+		}
+	}
+	for _, key := range []string{"Authorization", "X-Bugzilla-Api-Key"} {
+		if copied.Get(key) != "" {
+			copied.Set(key, redacted)
+		}
+	}
+	return copied
+}
+
+// redactedRequest returns a copy of the request with a readable body and the
+// credentials redacted
+func redactedRequest(req *http.Request, secrets []string) *http.Request {
+	copied := req.Clone(req.Context())
+	copied.Header = redactedHeader(req.Header, secrets)
+	if u, err := url.Parse(redact(req.URL.String(), secrets)); err == nil {
+		copied.URL = u
+	}
+	copied.Body = http.NoBody
+	copied.GetBody = nil
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			raw, _ := ioutil.ReadAll(body)
+			body.Close()
+			raw = []byte(redact(string(raw), secrets))
+			copied.Body = ioutil.NopCloser(bytes.NewReader(raw))
+			copied.GetBody = func() (io.ReadCloser, error) {
+				return ioutil.NopCloser(bytes.NewReader(raw)), nil
+			}
+			copied.ContentLength = int64(len(raw))
+		}
+	}
+	return copied
+}
+
+// redactedResponse returns a copy of the response holding the given body with
+// the credentials redacted
+func redactedResponse(resp *http.Response, raw []byte, req *http.Request) *http.Response {
+	copied := *resp
+	copied.Header = redactedHeader(resp.Header, nil)
+	copied.Body = ioutil.NopCloser(bytes.NewReader(raw))
+	copied.ContentLength = int64(len(raw))
+	copied.Request = req
+	return &copied
+}
+
+// observeRequest hands the request to the hooks and logs it, if configured
+func (c *client) observeRequest(req *http.Request, logger *logEntry) *http.Request {
+	copied := redactedRequest(req, c.credentials())
+	for _, hook := range c.requestHooks {
+		hook(redactedRequest(copied, nil))
+	}
+	if c.logsPayloads(logger) {
+		if dump, err := httputil.DumpRequest(redactedRequest(copied, nil), true); err == nil {
+			logger.WithField("request", string(dump)).Debug("Sending request to Bugzilla.")
+		}
+	}
+	return copied
+}
+
+// observeResponse hands the response to the hooks and logs it, if configured
+func (c *client) observeResponse(resp *http.Response, raw []byte, req *http.Request, logger *logEntry) {
+	raw = []byte(redact(string(raw), c.credentials()))
+	for _, hook := range c.responseHooks {
+		hook(redactedResponse(resp, raw, req))
+	}
+	if c.logsPayloads(logger) {
+		if dump, err := httputil.DumpResponse(redactedResponse(resp, raw, req), true); err == nil {
+			logger.WithField("response", string(dump)).Debug("Got response from Bugzilla.")
+		}
+	}
+}
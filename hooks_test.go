@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	for _, authMethod := range []string{"", AuthBearer, AuthQuery, AuthXBugzillaAPIKey} {
+		t.Run(authMethod, func(t *testing.T) {
+			testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// echo the key back, like some servers do in error messages
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":true,"code":100,"message":"invalid request for key api-key"}`))
+			}))
+			defer testServer.Close()
+			var out bytes.Buffer
+			c := clientForUrl(testServer.URL).(*client)
+			WithLogger(NewLogger(log.New(&out, "", 0), true))(c)
+			if err := c.SetAuthMethod(authMethod); err != nil {
+				t.Fatalf("expected no error setting auth method, got %v", err)
+			}
+			var requests, responses []string
+			WithRequestHook(func(req *http.Request) {
+				body, err := ioutil.ReadAll(req.Body)
+				if err != nil {
+					t.Errorf("expected to read the request body, got %v", err)
+				}
+				requests = append(requests, req.URL.String()+" "+req.Header.Get("Authorization")+req.Header.Get("X-BUGZILLA-API-KEY")+" "+string(body))
+			})(c)
+			WithResponseHook(func(resp *http.Response) {
+				body, err := ioutil.ReadAll(resp.Body)
+				if err != nil {
+					t.Errorf("expected to read the response body, got %v", err)
+				}
+				responses = append(responses, resp.Status+" "+resp.Request.URL.String()+" "+string(body))
+			})(c)
+			WithPayloadLogging()(c)
+
+			if err := c.UpdateBug(1, BugUpdate{Status: "MODIFIED"}); err == nil {
+				t.Fatal("expected an error, got none")
+			}
+			if _, err := c.AddPullRequestAsExternalBug(1, "org", "repo", 1); err == nil {
+				t.Fatal("expected an error, got none")
+			}
+			if len(requests) != 2 || len(responses) != 2 {
+				t.Fatalf("expected 2 requests and responses to be observed, got %d and %d", len(requests), len(responses))
+			}
+			if !strings.Contains(requests[0], `"status":"MODIFIED"`) {
+				t.Errorf("expected the request body to be observed, got %q", requests[0])
+			}
+			if !strings.Contains(responses[0], "400 Bad Request") {
+				t.Errorf("expected the response status to be observed, got %q", responses[0])
+			}
+			for _, observed := range append(append(requests, responses...), out.String()) {
+				if strings.Contains(observed, "api-key") {
+					t.Errorf("expected the API key to be redacted, got %q", observed)
+				}
+			}
+			if !strings.Contains(out.String(), "MODIFIED") {
+				t.Errorf("expected the payloads to be logged, got %q", out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import ()
+
+// BugIter iterates over the bugs matching a search, retrieving one page of
+// bugs at a time. Use it like:
+//
+//	iter := c.SearchBugsIter(query)
+//	for iter.Next() {
+//		bug := iter.Bug()
+//	}
+//	if err := iter.Err(); err != nil {
+//		...
+//	}
+type BugIter struct {
+	fetch func(limit, offset int) ([]*Bug, error)
+	pages pager
+	page  []*Bug
+	bug   *Bug
+	err   error
+}
+
+// newBugIter creates an iterator which uses fetch to retrieve a page of bugs
+func newBugIter(fetch func(limit, offset int) ([]*Bug, error)) *BugIter {
+	return &BugIter{fetch: fetch}
+}
+
+// Next advances the iterator to the next bug, retrieving the next page if
+// needed. It returns false when there are no more bugs or an error occurred.
+func (i *BugIter) Next() bool {
+	for len(i.page) == 0 {
+		if i.err != nil || i.pages.done {
+			i.bug = nil
+			return false
+		}
+		i.page, i.err = i.fetch(i.pages.limit, i.pages.offset)
+		if i.err != nil {
+			i.bug = nil
+			return false
+		}
+		i.pages.advance(len(i.page))
+	}
+	i.bug = i.page[0]
+	i.page = i.page[1:]
+	return true
+}
+
+// Bug returns the current bug, or nil if Next was not called or returned false
+func (i *BugIter) Bug() *Bug {
+	return i.bug
+}
+
+// Err returns the error which stopped the iteration, if any
+func (i *BugIter) Err() error {
+	return i.err
+}
+
+// SearchBugsIter returns an iterator over the bugs matching the search which
+// retrieves the bugs one page at a time, so large searches can be processed
+// without holding all bugs in memory.
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#search-bugs
+func (c *client) SearchBugsIter(query Query) *BugIter {
+	logger := c.logger.WithFields(LogFields{methodField: "SearchBugsIter"})
+	values := query.Values()
+	return newBugIter(func(limit, offset int) ([]*Bug, error) {
+		raw, err := c.searchPage(values, limit, offset, logger)
+		if err != nil {
+			return nil, err
+		}
+		return c.decodeSearchPage(raw, query)
+	})
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestSearchBugsIter(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		bugs          int
+		failAtOffset  int
+		expectedIDs   []int
+		expectedPages []string
+		expectedErr   bool
+	}{
+		{
+			name:          "bugs are retrieved page by page",
+			bugs:          5,
+			failAtOffset:  -1,
+			expectedIDs:   []int{1, 2, 3, 4, 5},
+			expectedPages: []string{"0/0", "2/2", "2/4"},
+		},
+		{
+			name:          "an empty last page ends the iteration",
+			bugs:          4,
+			failAtOffset:  -1,
+			expectedIDs:   []int{1, 2, 3, 4},
+			expectedPages: []string{"0/0", "2/2", "2/4"},
+		},
+		{
+			name:          "no bugs",
+			failAtOffset:  -1,
+			expectedPages: []string{"0/0"},
+		},
+		{
+			name:          "errors stop the iteration",
+			bugs:          5,
+			failAtOffset:  2,
+			expectedIDs:   []int{1, 2},
+			expectedPages: []string{"0/0", "2/2"},
+			expectedErr:   true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var pages []string
+			testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+				offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+				pages = append(pages, r.URL.Query().Get("limit")+"/"+r.URL.Query().Get("offset"))
+				if offset == testCase.failAtOffset {
+					http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
+					return
+				}
+				// the server allows at most two bugs per page
+				if limit == 0 || limit > 2 {
+					limit = 2
+				}
+				var bugs BugList
+				for id := offset + 1; id <= testCase.bugs && id <= offset+limit; id++ {
+					bugs.Bugs = append(bugs.Bugs, Bug{ID: id})
+				}
+				raw, err := json.Marshal(bugs)
+				if err != nil {
+					t.Fatalf("could not marshal bugs: %v", err)
+				}
+				w.Write(raw)
+			}))
+			defer testServer.Close()
+			client := clientForUrl(testServer.URL)
+
+			var ids []int
+			iter := client.SearchBugsIter(Query{Product: []string{"OpenShift Container Platform"}})
+			for iter.Next() {
+				ids = append(ids, iter.Bug().ID)
+			}
+			if testCase.expectedErr != (iter.Err() != nil) {
+				t.Errorf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, iter.Err())
+			}
+			if !reflect.DeepEqual(ids, testCase.expectedIDs) {
+				t.Errorf("%s: expected bugs %v, got %v", testCase.name, testCase.expectedIDs, ids)
+			}
+			if !reflect.DeepEqual(pages, testCase.expectedPages) {
+				t.Errorf("%s: expected pages %v, got %v", testCase.name, testCase.expectedPages, pages)
+			}
+			if iter.Next() {
+				t.Errorf("%s: expected the iterator to stay exhausted", testCase.name)
+			}
+		})
+	}
+}
+
+func TestFakeSearchBugsIter(t *testing.T) {
+	fake := &Fake{Bugs: map[int]Bug{1: {ID: 1}, 2: {ID: 2}}}
+	seen := map[int]bool{}
+	iter := fake.SearchBugsIter(Query{})
+	for iter.Next() {
+		seen[iter.Bug().ID] = true
+	}
+	if iter.Err() != nil {
+		t.Errorf("expected no error, but got one: %v", iter.Err())
+	}
+	if !reflect.DeepEqual(seen, map[int]bool{1: true, 2: true}) {
+		t.Errorf("expected to see both bugs, got %v", seen)
+	}
+}
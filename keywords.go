@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+// AddKeywords adds the keywords to the bug. Keywords which are already on
+// the bug are left alone and no update is sent if all of them are, so the
+// bug history only records actual changes.
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#update-bug
+func (c *client) AddKeywords(id int, keywords ...string) error {
+	return changeKeywords(c, id, keywords, true)
+}
+
+// RemoveKeywords removes the keywords from the bug. No update is sent if none
+// of them are on the bug.
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#update-bug
+func (c *client) RemoveKeywords(id int, keywords ...string) error {
+	return changeKeywords(c, id, keywords, false)
+}
+
+// changeKeywords reads the keywords of the bug and adds or removes those of
+// the given keywords which are missing or present, if any, guarded by the
+// time the bug last changed, see updateGuarded
+func changeKeywords(c Client, id int, keywords []string, add bool) error {
+	_, err := updateGuarded(c, id, []string{"id", "keywords"}, func(bug *Bug) (*BugUpdate, error) {
+		present := map[string]bool{}
+		for _, keyword := range bug.Keywords {
+			present[keyword] = true
+		}
+		var changed []string
+		for _, keyword := range keywords {
+			if present[keyword] != add {
+				changed = append(changed, keyword)
+				present[keyword] = add
+			}
+		}
+		if len(changed) == 0 {
+			return nil, nil
+		}
+		update := &BugKeywords{Remove: changed}
+		if add {
+			update = &BugKeywords{Add: changed}
+		}
+		return &BugUpdate{Keywords: update}, nil
+	})
+	return err
+}
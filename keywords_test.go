@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestChangeKeywords(t *testing.T) {
+	var updates []BugUpdate
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/bug/1705243" {
+			t.Errorf("incorrect path to change keywords: %s", r.URL.Path)
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+			return
+		}
+		if r.Method == http.MethodGet {
+			if fields := r.URL.Query().Get("include_fields"); fields != "id,keywords,last_change_time" && fields != "id,last_change_time" {
+				t.Errorf("expected only the keywords and the last change time to be requested, got %q", fields)
+			}
+			w.Write([]byte(`{"bugs":[{"id":1705243,"keywords":["Security","Reopened"],"last_change_time":"2020-05-01T12:00:00Z"}]}`))
+			return
+		}
+		raw, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("could not read request body: %v", err)
+		}
+		var update BugUpdate
+		if err := json.Unmarshal(raw, &update); err != nil {
+			t.Fatalf("could not unmarshal update: %v", err)
+		}
+		updates = append(updates, update)
+		w.Write([]byte(`{"bugs":[{"id":1705243,"changes":{}}]}`))
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	if err := client.AddKeywords(1705243, "Security"); err != nil {
+		t.Errorf("expected no error, but got one: %v", err)
+	}
+	if err := client.RemoveKeywords(1705243, "Regression"); err != nil {
+		t.Errorf("expected no error, but got one: %v", err)
+	}
+	if len(updates) != 0 {
+		t.Errorf("expected no update when nothing changes, got %v", updates)
+	}
+	if err := client.AddKeywords(1705243, "Security", "Regression", "Regression"); err != nil {
+		t.Errorf("expected no error, but got one: %v", err)
+	}
+	if err := client.RemoveKeywords(1705243, "Reopened", "Regression"); err != nil {
+		t.Errorf("expected no error, but got one: %v", err)
+	}
+	read := Timestamp{Time: time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)}
+	expected := []BugUpdate{
+		{Keywords: &BugKeywords{Add: []string{"Regression"}}, LastChangeTime: &read},
+		{Keywords: &BugKeywords{Remove: []string{"Reopened"}}, LastChangeTime: &read},
+	}
+	if !reflect.DeepEqual(updates, expected) {
+		t.Errorf("expected updates %v, got %v", expected, updates)
+	}
+}
+
+func TestFakeChangeKeywords(t *testing.T) {
+	fake := &Fake{Bugs: map[int]Bug{1: {ID: 1, Keywords: []string{"Security"}}}}
+	if err := fake.AddKeywords(1, "Security", "TestBlocker"); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if err := fake.RemoveKeywords(1, "Security"); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if expected := []string{"TestBlocker"}; !reflect.DeepEqual(fake.Bugs[1].Keywords, expected) {
+		t.Errorf("expected keywords %v, got %v", expected, fake.Bugs[1].Keywords)
+	}
+	if err := fake.AddKeywords(2, "Security"); !IsNotFound(err) {
+		t.Errorf("expected a not found error for a missing bug, got %v", err)
+	}
+}
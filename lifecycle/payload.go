@@ -0,0 +1,166 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lifecycle tracks how the fix for a bug moves towards a release:
+// how QE verified it and which release payloads it is fixed in. It only uses
+// the bugzilla.Client interface, so it works with the Fake as well.
+package lifecycle
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/eparis/bugzilla"
+)
+
+// payloadAliasSeparator separates the release payload from the bug ID in a
+// payload alias. Aliases are unique across all bugs, so the bug ID is needed
+// to link more than one bug to the same payload.
+const payloadAliasSeparator = "-bz"
+
+// maxAliasLength is the longest alias Bugzilla accepts
+const maxAliasLength = 40
+
+// maxPayloadKeyLength leaves room in an alias for the separator and bug IDs
+// of up to eight digits.
+const maxPayloadKeyLength = maxAliasLength - len(payloadAliasSeparator) - 8
+
+// payloadHashLength is the number of hex digits of the payload hash kept in
+// a shortened payload key
+const payloadHashLength = 8
+
+// PayloadKey returns the release payload as it is stored in payload aliases.
+// Payloads longer than 29 characters do not fit in an alias, so they are cut
+// and suffixed with a hash of the full name, which keeps them unique.
+func PayloadKey(payload string) string {
+	if len(payload) <= maxPayloadKeyLength {
+		return payload
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(payload)))[:payloadHashLength]
+	return payload[:maxPayloadKeyLength-payloadHashLength-1] + "-" + hash
+}
+
+// AliasForPayload returns the alias which links the bug to the release payload,
+// or an error if Bugzilla would not accept the alias.
+func AliasForPayload(payload string, id int) (string, error) {
+	if err := validatePayload(payload); err != nil {
+		return "", err
+	}
+	if id <= 0 {
+		return "", fmt.Errorf("invalid bug ID %d", id)
+	}
+	alias := fmt.Sprintf("%s%s%d", PayloadKey(payload), payloadAliasSeparator, id)
+	if len(alias) > maxAliasLength {
+		return "", fmt.Errorf("alias %q for release payload %q is longer than %d characters", alias, payload, maxAliasLength)
+	}
+	if _, err := strconv.Atoi(alias); err == nil {
+		return "", fmt.Errorf("alias %q for release payload %q must not be numeric", alias, payload)
+	}
+	return alias, nil
+}
+
+// PayloadFromAlias returns the release payload and bug ID encoded in a payload
+// alias, or an error if the alias does not link a bug to a payload.
+func PayloadFromAlias(alias string) (payload string, id int, err error) {
+	index := strings.LastIndex(alias, payloadAliasSeparator)
+	if index <= 0 {
+		return "", 0, fmt.Errorf("alias %q does not reference a release payload", alias)
+	}
+	id, err = strconv.Atoi(alias[index+len(payloadAliasSeparator):])
+	if err != nil {
+		return "", 0, fmt.Errorf("alias %q does not reference a release payload: could not parse bug ID: %v", alias, err)
+	}
+	return alias[:index], id, nil
+}
+
+// PayloadsForBug returns the release payloads the bug is linked to, as
+// returned by PayloadKey.
+func PayloadsForBug(bug *bugzilla.Bug) []string {
+	var payloads []string
+	for _, alias := range bug.Alias {
+		payload, id, err := PayloadFromAlias(alias)
+		if err != nil || id != bug.ID {
+			continue
+		}
+		payloads = append(payloads, payload)
+	}
+	return payloads
+}
+
+func validatePayload(payload string) error {
+	if payload == "" {
+		return fmt.Errorf("release payload must not be empty")
+	}
+	if strings.ContainsAny(payload, ", \t\n") {
+		return fmt.Errorf("release payload %q must not contain commas or whitespace", payload)
+	}
+	return nil
+}
+
+// LinkBugToPayload records that the bug is fixed in the release payload by
+// adding a payload alias to the bug.
+func LinkBugToPayload(c bugzilla.Client, id int, payload string) error {
+	alias, err := AliasForPayload(payload, id)
+	if err != nil {
+		return err
+	}
+	return c.UpdateBug(id, bugzilla.BugUpdate{Alias: &bugzilla.BugAliases{Add: []string{alias}}})
+}
+
+// UnlinkBugFromPayload removes the payload alias linking the bug to the release payload
+func UnlinkBugFromPayload(c bugzilla.Client, id int, payload string) error {
+	alias, err := AliasForPayload(payload, id)
+	if err != nil {
+		return err
+	}
+	return c.UpdateBug(id, bugzilla.BugUpdate{Alias: &bugzilla.BugAliases{Remove: []string{alias}}})
+}
+
+// PayloadQuery returns a query matching all bugs linked to the release payload
+func PayloadQuery(payload string) bugzilla.Query {
+	return bugzilla.Query{
+		Advanced: []bugzilla.AdvancedQuery{{
+			Field: "alias",
+			Op:    "regexp",
+			Value: fmt.Sprintf("^%s%s[0-9]+$", regexp.QuoteMeta(PayloadKey(payload)), payloadAliasSeparator),
+		}},
+	}
+}
+
+// GetBugsFixedInPayload returns all bugs linked to the release payload
+func GetBugsFixedInPayload(c bugzilla.Client, payload string) ([]*bugzilla.Bug, error) {
+	if err := validatePayload(payload); err != nil {
+		return nil, err
+	}
+	bugs, err := c.Search(PayloadQuery(payload))
+	if err != nil {
+		return nil, err
+	}
+	key := PayloadKey(payload)
+	var fixed []*bugzilla.Bug
+	for _, bug := range bugs {
+		for _, linked := range PayloadsForBug(bug) {
+			if linked == key {
+				fixed = append(fixed, bug)
+				break
+			}
+		}
+	}
+	return fixed, nil
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/eparis/bugzilla"
+)
+
+func TestPayloadFromAlias(t *testing.T) {
+	testCases := []struct {
+		name            string
+		alias           string
+		expectedPayload string
+		expectedID      int
+		expectedErr     bool
+	}{
+		{
+			name:            "payload alias is parsed",
+			alias:           "4.5.0-0.nightly-2020-05-01-123456-bz1705243",
+			expectedPayload: "4.5.0-0.nightly-2020-05-01-123456",
+			expectedID:      1705243,
+		},
+		{
+			name:        "unrelated alias fails",
+			alias:       "CVE-2020-1234",
+			expectedErr: true,
+		},
+		{
+			name:        "alias without bug ID fails",
+			alias:       "4.5.0-bzfoo",
+			expectedErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload, id, err := PayloadFromAlias(tc.alias)
+			if tc.expectedErr != (err != nil) {
+				t.Errorf("%s: expected error %v, got %v", tc.name, tc.expectedErr, err)
+			}
+			if payload != tc.expectedPayload || id != tc.expectedID {
+				t.Errorf("%s: expected %q/%d, got %q/%d", tc.name, tc.expectedPayload, tc.expectedID, payload, id)
+			}
+		})
+	}
+}
+
+func TestGetBugsFixedInPayload(t *testing.T) {
+	fake := &bugzilla.Fake{
+		Bugs: map[int]bugzilla.Bug{
+			1: {ID: 1},
+			2: {ID: 2, Alias: []string{"CVE-2020-1234"}},
+			3: {ID: 3},
+		},
+	}
+	for _, id := range []int{1, 2} {
+		if err := LinkBugToPayload(fake, id, "4.5.0-rc.1"); err != nil {
+			t.Fatalf("expected no error linking bug %d, got %v", id, err)
+		}
+	}
+	if err := LinkBugToPayload(fake, 3, "4.5.0-rc.10"); err != nil {
+		t.Fatalf("expected no error linking bug 3, got %v", err)
+	}
+	if err := LinkBugToPayload(fake, 3, "4.5.0 rc.1"); err == nil {
+		t.Error("expected an error linking an invalid payload, got none")
+	}
+
+	bugs, err := GetBugsFixedInPayload(fake, "4.5.0-rc.1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var ids []int
+	for _, bug := range bugs {
+		ids = append(ids, bug.ID)
+	}
+	sort.Ints(ids)
+	if expected := []int{1, 2}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected bugs %v, got %v", expected, ids)
+	}
+
+	if err := UnlinkBugFromPayload(fake, 2, "4.5.0-rc.1"); err != nil {
+		t.Fatalf("expected no error unlinking, got %v", err)
+	}
+	if actual, expected := fake.Bugs[2].Alias, []string{"CVE-2020-1234"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected aliases %v, got %v", expected, actual)
+	}
+}
+
+func TestAliasForPayload(t *testing.T) {
+	testCases := []struct {
+		name          string
+		payload       string
+		id            int
+		expectedAlias string
+		expectedErr   bool
+	}{
+		{
+			name:          "short payload is used as is",
+			payload:       "4.5.0-rc.1",
+			id:            1705243,
+			expectedAlias: "4.5.0-rc.1-bz1705243",
+		},
+		{
+			name:          "nightly payload is shortened",
+			payload:       "4.7.0-0.nightly-2020-10-27-051128",
+			id:            1891234,
+			expectedAlias: "4.7.0-0.nightly-2020-47b9a00f-bz1891234",
+		},
+		{
+			name:        "bug ID too long for an alias fails",
+			payload:     "4.7.0-0.nightly-2020-10-27-051128",
+			id:          1234567890,
+			expectedErr: true,
+		},
+		{
+			name:        "invalid payload fails",
+			payload:     "4.5.0 rc.1",
+			id:          1,
+			expectedErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			alias, err := AliasForPayload(tc.payload, tc.id)
+			if tc.expectedErr != (err != nil) {
+				t.Fatalf("%s: expected error %v, got %v", tc.name, tc.expectedErr, err)
+			}
+			if alias != tc.expectedAlias {
+				t.Errorf("%s: expected alias %q, got %q", tc.name, tc.expectedAlias, alias)
+			}
+			if len(alias) > maxAliasLength {
+				t.Errorf("%s: alias %q is longer than %d characters", tc.name, alias, maxAliasLength)
+			}
+		})
+	}
+}
+
+func TestGetBugsFixedInNightlyPayload(t *testing.T) {
+	fake := &bugzilla.Fake{Bugs: map[int]bugzilla.Bug{1891234: {ID: 1891234}, 1891235: {ID: 1891235}}}
+	if err := LinkBugToPayload(fake, 1891234, "4.7.0-0.nightly-2020-10-27-051128"); err != nil {
+		t.Fatalf("expected no error linking, got %v", err)
+	}
+	if err := LinkBugToPayload(fake, 1891235, "4.7.0-0.nightly-2020-10-28-062233"); err != nil {
+		t.Fatalf("expected no error linking, got %v", err)
+	}
+	bugs, err := GetBugsFixedInPayload(fake, "4.7.0-0.nightly-2020-10-27-051128")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(bugs) != 1 || bugs[0].ID != 1891234 {
+		t.Errorf("expected only bug 1891234, got %v", bugs)
+	}
+}
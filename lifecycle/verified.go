@@ -0,0 +1,52 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import "github.com/eparis/bugzilla"
+
+// HasVerifiedValue returns true if the Verified field of the bug contains the value
+func HasVerifiedValue(bug *bugzilla.Bug, value bugzilla.VerifiedValue) bool {
+	for _, v := range bug.Verified {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTested returns true if QE fully tested the fix for the bug
+func IsTested(bug *bugzilla.Bug) bool {
+	return HasVerifiedValue(bug, bugzilla.VerifiedTested)
+}
+
+// IsSanityOnly returns true if QE only sanity checked the fix for the bug,
+// without fully testing it
+func IsSanityOnly(bug *bugzilla.Bug) bool {
+	return HasVerifiedValue(bug, bugzilla.VerifiedSanityOnly) && !IsTested(bug)
+}
+
+// MarkTested records that the fix for the bug was fully tested. This replaces
+// any other value of the Verified field.
+func MarkTested(c bugzilla.Client, id int) error {
+	return c.UpdateBug(id, bugzilla.BugUpdate{Verified: []bugzilla.VerifiedValue{bugzilla.VerifiedTested}})
+}
+
+// MarkSanityOnly records that the fix for the bug was only sanity checked.
+// This replaces any other value of the Verified field.
+func MarkSanityOnly(c bugzilla.Client, id int) error {
+	return c.UpdateBug(id, bugzilla.BugUpdate{Verified: []bugzilla.VerifiedValue{bugzilla.VerifiedSanityOnly}})
+}
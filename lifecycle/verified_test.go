@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/eparis/bugzilla"
+)
+
+func TestVerified(t *testing.T) {
+	var bug bugzilla.Bug
+	if err := json.Unmarshal([]byte(`{"id":1,"cf_verified":["SanityOnly"]}`), &bug); err != nil {
+		t.Fatalf("failed to unmarshal bug: %v", err)
+	}
+	if !IsSanityOnly(&bug) || IsTested(&bug) {
+		t.Errorf("expected bug to be sanity only, got %v", bug.Verified)
+	}
+
+	fake := &bugzilla.Fake{Bugs: map[int]bugzilla.Bug{1: bug}}
+	if err := MarkTested(fake, 1); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	updated := fake.Bugs[1]
+	if IsSanityOnly(&updated) || !IsTested(&updated) {
+		t.Errorf("expected bug to be tested, got %v", updated.Verified)
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+// LogFields are the structured fields of a log entry
+type LogFields map[string]interface{}
+
+// Logger is the structured logger the client logs with. The logrusadapter
+// package adapts a logrus entry to it. By default, the client logs entries at
+// the info level and above with the standard library's log package.
+type Logger interface {
+	// WithFields returns a logger adding the fields to every entry.
+	WithFields(fields LogFields) Logger
+	Debug(msg string)
+	Info(msg string)
+	Warn(msg string)
+	Error(msg string)
+	// DebugEnabled returns true if debug entries are logged, so expensive
+	// debug entries, like request dumps, are only built when they are used.
+	DebugEnabled() bool
+}
+
+// WithLogger logs with the given logger
+func WithLogger(logger Logger) Option {
+	return func(c *client) {
+		c.logger = newLogEntry(logger, LogFields{"client": "bugzilla"})
+	}
+}
+
+// NewLogger returns a Logger writing entries with their fields as key=value
+// pairs to the standard library logger. Debug entries are only written if
+// debug is set.
+func NewLogger(logger *log.Logger, debug bool) Logger {
+	return &stdLogger{logger: logger, debug: debug}
+}
+
+// defaultLogger is the logger of clients created without WithLogger
+var defaultLogger = NewLogger(log.New(os.Stderr, "", log.LstdFlags), false)
+
+type stdLogger struct {
+	logger *log.Logger
+	debug  bool
+	fields LogFields
+}
+
+func (l *stdLogger) WithFields(fields LogFields) Logger {
+	return &stdLogger{logger: l.logger, debug: l.debug, fields: mergeLogFields(l.fields, fields)}
+}
+
+func (l *stdLogger) Debug(msg string) {
+	if l.debug {
+		l.write("debug", msg)
+	}
+}
+
+func (l *stdLogger) Info(msg string)    { l.write("info", msg) }
+func (l *stdLogger) Warn(msg string)    { l.write("warning", msg) }
+func (l *stdLogger) Error(msg string)   { l.write("error", msg) }
+func (l *stdLogger) DebugEnabled() bool { return l.debug }
+
+func (l *stdLogger) write(level, msg string) {
+	keys := make([]string, 0, len(l.fields))
+	for key := range l.fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	line := []string{"level=" + level, fmt.Sprintf("msg=%q", msg)}
+	for _, key := range keys {
+		line = append(line, fmt.Sprintf("%s=%q", key, fmt.Sprint(l.fields[key])))
+	}
+	l.logger.Print(strings.Join(line, " "))
+}
+
+func mergeLogFields(fields, more LogFields) LogFields {
+	merged := make(LogFields, len(fields)+len(more))
+	for key, value := range fields {
+		merged[key] = value
+	}
+	for key, value := range more {
+		merged[key] = value
+	}
+	return merged
+}
+
+// logEntry is an entry the client builds up before logging it. It keeps its
+// fields in Data, where the client reads back the details of a request, like
+// its method, and hands them to the Logger when the entry is logged.
+type logEntry struct {
+	logger Logger
+	Data   LogFields
+}
+
+func newLogEntry(logger Logger, fields LogFields) *logEntry {
+	return &logEntry{logger: logger, Data: fields}
+}
+
+func (e *logEntry) WithFields(fields LogFields) *logEntry {
+	return &logEntry{logger: e.logger, Data: mergeLogFields(e.Data, fields)}
+}
+
+func (e *logEntry) WithField(key string, value interface{}) *logEntry {
+	return e.WithFields(LogFields{key: value})
+}
+
+func (e *logEntry) WithError(err error) *logEntry {
+	return e.WithField("error", err)
+}
+
+func (e *logEntry) Debug(args ...interface{}) {
+	if e.logger.DebugEnabled() {
+		e.logger.WithFields(e.Data).Debug(fmt.Sprint(args...))
+	}
+}
+
+func (e *logEntry) Debugf(format string, args ...interface{}) {
+	if e.logger.DebugEnabled() {
+		e.logger.WithFields(e.Data).Debug(fmt.Sprintf(format, args...))
+	}
+}
+
+func (e *logEntry) Info(args ...interface{}) {
+	e.logger.WithFields(e.Data).Info(fmt.Sprint(args...))
+}
+
+func (e *logEntry) Warn(args ...interface{}) {
+	e.logger.WithFields(e.Data).Warn(fmt.Sprint(args...))
+}
+
+func (e *logEntry) Warnf(format string, args ...interface{}) {
+	e.logger.WithFields(e.Data).Warn(fmt.Sprintf(format, args...))
+}
+
+func (e *logEntry) Error(args ...interface{}) {
+	e.logger.WithFields(e.Data).Error(fmt.Sprint(args...))
+}
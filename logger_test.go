@@ -0,0 +1,40 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	var out bytes.Buffer
+	entry := newLogEntry(NewLogger(log.New(&out, "", 0), false), LogFields{"client": "bugzilla"})
+	entry.WithField(methodField, "GetBug").Debug("Got response from Bugzilla.")
+	if out.Len() != 0 {
+		t.Errorf("expected no debug entry, got %q", out.String())
+	}
+	entry.WithField(methodField, "GetBug").WithError(errors.New("boom")).Warnf("Retrying in %s.", "1s")
+	if expected := "level=warning msg=\"Retrying in 1s.\" client=\"bugzilla\" error=\"boom\" method=\"GetBug\"\n"; out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+	if _, set := entry.Data[methodField]; set {
+		t.Error("expected the fields of an entry not to change when fields are added")
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logrusadapter lets the Bugzilla client log with logrus, which the
+// core client does not depend on:
+//
+//	client := bugzilla.NewClient(getAPIKey, endpoint,
+//		bugzilla.WithLogger(logrusadapter.New(logrus.NewEntry(logrus.StandardLogger()))))
+package logrusadapter
+
+import (
+	"github.com/eparis/bugzilla"
+	"github.com/sirupsen/logrus"
+)
+
+// New returns a bugzilla.Logger logging to the logrus entry
+func New(entry *logrus.Entry) bugzilla.Logger {
+	return &logger{entry: entry}
+}
+
+type logger struct {
+	entry *logrus.Entry
+}
+
+func (l *logger) WithFields(fields bugzilla.LogFields) bugzilla.Logger {
+	return &logger{entry: l.entry.WithFields(logrus.Fields(fields))}
+}
+
+func (l *logger) Debug(msg string) { l.entry.Debug(msg) }
+func (l *logger) Info(msg string)  { l.entry.Info(msg) }
+func (l *logger) Warn(msg string)  { l.entry.Warn(msg) }
+func (l *logger) Error(msg string) { l.entry.Error(msg) }
+
+func (l *logger) DebugEnabled() bool {
+	return l.entry.Logger.IsLevelEnabled(logrus.DebugLevel)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logrusadapter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/eparis/bugzilla"
+	"github.com/sirupsen/logrus"
+)
+
+func TestLogger(t *testing.T) {
+	var out bytes.Buffer
+	base := logrus.New()
+	base.SetOutput(&out)
+	base.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+	logger := New(logrus.NewEntry(base))
+	if logger.DebugEnabled() {
+		t.Error("expected debug entries to be disabled at the info level")
+	}
+	logger.WithFields(bugzilla.LogFields{"method": "GetBug"}).Debug("hidden")
+	logger.WithFields(bugzilla.LogFields{"method": "GetBug"}).Warn("Retrying.")
+	if expected := "level=warning msg=Retrying. method=GetBug\n"; out.String() != expected {
+		t.Errorf("expected %q to be logged, got %q", expected, out.String())
+	}
+
+	base.SetLevel(logrus.DebugLevel)
+	if !logger.DebugEnabled() {
+		t.Error("expected debug entries to be enabled at the debug level")
+	}
+	out.Reset()
+	logger.Debug("shown")
+	if !strings.Contains(out.String(), "msg=shown") {
+		t.Errorf("expected the debug entry to be logged, got %q", out.String())
+	}
+}
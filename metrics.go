@@ -16,7 +16,11 @@ limitations under the License.
 
 package bugzilla
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
 
 // requestDurations provides the 'bugzilla_request_duration' histogram that keeps track
 // of the duration of Bugzilla requests by API path.
@@ -32,3 +36,155 @@ var requestDurations = prometheus.NewHistogramVec(
 func init() {
 	prometheus.MustRegister(requestDurations)
 }
+
+// clientMetrics holds the metrics registered for a single client with WithMetrics
+type clientMetrics struct {
+	requests         *prometheus.CounterVec
+	durations        *prometheus.HistogramVec
+	errors           *prometheus.CounterVec
+	retries          *prometheus.CounterVec
+	retriesExhausted *prometheus.CounterVec
+	failovers        *prometheus.CounterVec
+	skipped          *prometheus.CounterVec
+	circuitRejected  *prometheus.CounterVec
+	warmingUp        prometheus.Gauge
+}
+
+// WithMetrics registers metrics for the requests made by the client with the
+// registerer: the 'bugzilla_client_requests_total' and 'bugzilla_client_request_errors_total'
+// counters and the 'bugzilla_client_request_duration_seconds' histogram, all labeled
+// by API method and response status. Retries, failovers and requests which were
+// not sent are counted by API method on the 'bugzilla_request_retries_total',
+// 'bugzilla_request_retries_exhausted_total', 'bugzilla_request_failovers_total',
+// 'bugzilla_requests_skipped_total' and 'bugzilla_requests_circuit_rejected_total'
+// counters, and clients in their warm-up period on the 'bugzilla_client_warming_up'
+// gauge. Clients sharing a registerer share the metrics.
+func WithMetrics(registerer prometheus.Registerer) Option {
+	return func(c *client) {
+		c.metrics = &clientMetrics{
+			requests: registerOrReuse(registerer, prometheus.NewCounterVec(
+				prometheus.CounterOpts{
+					Name: "bugzilla_client_requests_total",
+					Help: "Bugzilla requests by API method and response status.",
+				},
+				[]string{methodField, "status"},
+			)).(*prometheus.CounterVec),
+			durations: registerOrReuse(registerer, prometheus.NewHistogramVec(
+				prometheus.HistogramOpts{
+					Name:    "bugzilla_client_request_duration_seconds",
+					Help:    "Bugzilla request duration in seconds by API method and response status.",
+					Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+				},
+				[]string{methodField, "status"},
+			)).(*prometheus.HistogramVec),
+			errors: registerOrReuse(registerer, prometheus.NewCounterVec(
+				prometheus.CounterOpts{
+					Name: "bugzilla_client_request_errors_total",
+					Help: "Failed Bugzilla requests by API method and response status.",
+				},
+				[]string{methodField, "status"},
+			)).(*prometheus.CounterVec),
+			retries: registerOrReuse(registerer, prometheus.NewCounterVec(
+				prometheus.CounterOpts{
+					Name: "bugzilla_request_retries_total",
+					Help: "Bugzilla request retries by API method.",
+				},
+				[]string{methodField},
+			)).(*prometheus.CounterVec),
+			retriesExhausted: registerOrReuse(registerer, prometheus.NewCounterVec(
+				prometheus.CounterOpts{
+					Name: "bugzilla_request_retries_exhausted_total",
+					Help: "Bugzilla requests which failed after exhausting all retries by API method.",
+				},
+				[]string{methodField},
+			)).(*prometheus.CounterVec),
+			failovers: registerOrReuse(registerer, prometheus.NewCounterVec(
+				prometheus.CounterOpts{
+					Name: "bugzilla_request_failovers_total",
+					Help: "Bugzilla requests sent to a secondary endpoint by API method.",
+				},
+				[]string{methodField},
+			)).(*prometheus.CounterVec),
+			skipped: registerOrReuse(registerer, prometheus.NewCounterVec(
+				prometheus.CounterOpts{
+					Name: "bugzilla_requests_skipped_total",
+					Help: "Non-critical Bugzilla requests skipped while degraded by API method.",
+				},
+				[]string{methodField},
+			)).(*prometheus.CounterVec),
+			circuitRejected: registerOrReuse(registerer, prometheus.NewCounterVec(
+				prometheus.CounterOpts{
+					Name: "bugzilla_requests_circuit_rejected_total",
+					Help: "Bugzilla requests not sent while the circuit breaker was open by API method.",
+				},
+				[]string{methodField},
+			)).(*prometheus.CounterVec),
+			warmingUp: registerOrReuse(registerer, prometheus.NewGauge(
+				prometheus.GaugeOpts{
+					Name: "bugzilla_client_warming_up",
+					Help: "Number of Bugzilla clients in their warm-up period.",
+				},
+			)).(prometheus.Gauge),
+		}
+	}
+}
+
+// registerOrReuse registers the collector, or returns the identical collector
+// which was registered before. Other registration failures are programmer
+// errors and cause a panic, like prometheus.MustRegister does.
+func registerOrReuse(registerer prometheus.Registerer, collector prometheus.Collector) prometheus.Collector {
+	if err := registerer.Register(collector); err != nil {
+		if alreadyRegistered, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return alreadyRegistered.ExistingCollector
+		}
+		panic(err)
+	}
+	return collector
+}
+
+func (m *clientMetrics) observe(labels prometheus.Labels, duration time.Duration, failed bool) {
+	m.requests.With(labels).Inc()
+	m.durations.With(labels).Observe(duration.Seconds())
+	if failed {
+		m.errors.With(labels).Inc()
+	}
+}
+
+// The following record events for clients created WithMetrics; clients without
+// metrics have a nil *clientMetrics and record nothing.
+
+func (m *clientMetrics) retried(method string) {
+	if m != nil {
+		m.retries.WithLabelValues(method).Inc()
+	}
+}
+
+func (m *clientMetrics) exhaustedRetries(method string) {
+	if m != nil {
+		m.retriesExhausted.WithLabelValues(method).Inc()
+	}
+}
+
+func (m *clientMetrics) failedOver(method string) {
+	if m != nil {
+		m.failovers.WithLabelValues(method).Inc()
+	}
+}
+
+func (m *clientMetrics) skippedRequest(method string) {
+	if m != nil {
+		m.skipped.WithLabelValues(method).Inc()
+	}
+}
+
+func (m *clientMetrics) rejectedRequest(method string) {
+	if m != nil {
+		m.circuitRejected.WithLabelValues(method).Inc()
+	}
+}
+
+func (m *clientMetrics) addWarmingUp(delta float64) {
+	if m != nil {
+		m.warmingUp.Add(delta)
+	}
+}
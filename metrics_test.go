@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestWithMetrics(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/bug/1705243" {
+			w.Write(bugData)
+			return
+		}
+		http.Error(w, "404 Not Found", http.StatusNotFound)
+	}))
+	defer testServer.Close()
+
+	registry := prometheus.NewRegistry()
+	c := clientForUrl(testServer.URL).(*client)
+	WithMetrics(registry)(c)
+	// a second client must be able to share the registry
+	other := clientForUrl(testServer.URL).(*client)
+	WithMetrics(registry)(other)
+
+	if _, err := c.GetBug(1705243); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := other.GetBug(1); !IsNotFound(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	samples := map[string]map[string]float64{}
+	for _, family := range families {
+		samples[family.GetName()] = map[string]float64{}
+		for _, metric := range family.GetMetric() {
+			var status string
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "status" {
+					status = label.GetValue()
+				}
+			}
+			switch {
+			case metric.GetCounter() != nil:
+				samples[family.GetName()][status] = metric.GetCounter().GetValue()
+			case metric.GetHistogram() != nil:
+				samples[family.GetName()][status] = float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	for name, expected := range map[string]map[string]float64{
+		"bugzilla_client_requests_total":           {"200": 1, "404": 1},
+		"bugzilla_client_request_duration_seconds": {"200": 1, "404": 1},
+		"bugzilla_client_request_errors_total":     {"404": 1},
+	} {
+		for status, value := range expected {
+			if actual := samples[name][status]; actual != value {
+				t.Errorf("expected %s{status=%q} to be %v, got %v", name, status, value, actual)
+			}
+		}
+		if len(samples[name]) != len(expected) {
+			t.Errorf("expected %d samples for %s, got %v", len(expected), name, samples[name])
+		}
+	}
+}
+
+func TestWithMetricsCountsRetriesAndWarmUp(t *testing.T) {
+	failures := 2
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failures > 0 {
+			failures--
+			http.Error(w, "503 Service Unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(bugData)
+	}))
+	defer testServer.Close()
+
+	registry := prometheus.NewRegistry()
+	clock := &fakeClock{now: time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)}
+	c := clientForUrl(testServer.URL).(*client)
+	for _, opt := range []Option{WithClock(clock), WithRetries(2, time.Second), WithMetrics(registry)} {
+		opt(c)
+	}
+	if _, err := c.GetBug(1705243); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// the warm-up starts after all options, so it is counted on metrics set up after it
+	NewClient(func() []byte { return nil }, "", WithWarmUp(WarmUp{Duration: time.Hour}), WithClock(&blockingClock{sleeps: make(chan time.Duration, 1)}), WithMetrics(registry))
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	samples := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			switch {
+			case metric.GetCounter() != nil:
+				samples[family.GetName()] += metric.GetCounter().GetValue()
+			case metric.GetGauge() != nil:
+				samples[family.GetName()] += metric.GetGauge().GetValue()
+			}
+		}
+	}
+	for name, expected := range map[string]float64{
+		"bugzilla_request_retries_total": 2,
+		"bugzilla_client_warming_up":     1,
+	} {
+		if actual := samples[name]; actual != expected {
+			t.Errorf("expected %s to be %v, got %v", name, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// guardedUpdateAttempts is how often updateGuarded reads a bug and updates
+// it before it gives up on concurrent updates
+const guardedUpdateAttempts = 3
+
+// MidairCollisionError is returned when an update guarded by
+// BugUpdate.LastChangeTime is rejected because the bug changed since it was
+// read. The bug should be read again and the update recomputed.
+type MidairCollisionError struct {
+	// ID is the ID of the bug, unless it was updated by its alias.
+	ID int
+	// Alias is the alias of the bug, if it was updated by its alias.
+	Alias string
+	// LastChangeTime is the time the update expected the bug to have last
+	// changed at.
+	LastChangeTime Timestamp
+	// Err is the error returned by the server, or describes the change the
+	// client found before sending the update.
+	Err error
+}
+
+func (e *MidairCollisionError) Error() string {
+	bug := fmt.Sprintf("bug %d", e.ID)
+	if e.Alias != "" {
+		bug = fmt.Sprintf("bug %q", e.Alias)
+	}
+	return fmt.Sprintf("%s changed since %s: %v", bug, e.LastChangeTime, e.Err)
+}
+
+func (e *MidairCollisionError) Unwrap() error {
+	return e.Err
+}
+
+// IsMidairCollision returns true if an update was rejected because the bug
+// changed since it was read
+func IsMidairCollision(err error) bool {
+	var target *MidairCollisionError
+	return errors.As(err, &target)
+}
+
+// checkLastChange reads the time the bug last changed, if the update is
+// guarded, and returns a MidairCollisionError if it is not the time the
+// update expects. The server does not check the time itself, so this leaves
+// only the time between the check and the update for concurrent updates to
+// go unnoticed; the guard is still sent with the update for servers which
+// do check it. Bugs are addressed by their alias if it is set.
+func (c *client) checkLastChange(id int, alias string, update BugUpdate, logger *logEntry) error {
+	if update.LastChangeTime == nil {
+		return nil
+	}
+	path := fmt.Sprintf("%s/rest/bug/%d", c.endpoint, id)
+	if alias != "" {
+		var err error
+		if path, err = c.aliasPath(alias); err != nil {
+			return err
+		}
+	}
+	values := &url.Values{}
+	Fields{Include: []string{"id", "last_change_time"}}.addTo(values)
+	bugs, err := c.getBugs(path, values, logger.WithField("guard", true))
+	if err != nil {
+		if alias != "" {
+			return aliasNotFound(alias, err)
+		}
+		return err
+	}
+	if len(bugs) != 1 {
+		return fmt.Errorf("did not get one bug, but %d: %v", len(bugs), bugs)
+	}
+	if !bugs[0].LastChangeTime.Equal(update.LastChangeTime.Time) {
+		return &MidairCollisionError{ID: id, Alias: alias, LastChangeTime: *update.LastChangeTime, Err: fmt.Errorf("the bug last changed at %s", bugs[0].LastChangeTime)}
+	}
+	return nil
+}
+
+// midairCollision wraps the error in a MidairCollisionError if the update was
+// guarded by its LastChangeTime and the server rejected it as conflicting.
+func midairCollision(id int, alias string, update BugUpdate, err error) error {
+	if err == nil || update.LastChangeTime == nil {
+		return err
+	}
+	reqError, ok := asRequestError(err)
+	if !ok || reqError.StatusCode != http.StatusConflict {
+		return err
+	}
+	return &MidairCollisionError{ID: id, Alias: alias, LastChangeTime: *update.LastChangeTime, Err: err}
+}
+
+// updateGuarded reads the fields of the bug and the time it last changed,
+// computes the update from them and applies it guarded by that time. If the
+// bug changed in between, it is read again and the update recomputed, so
+// the update is never based on a stale read. No update is sent if compute
+// returns nil. It returns the update which was applied, without the guard.
+func updateGuarded(c Client, id int, fields []string, compute func(*Bug) (*BugUpdate, error)) (*BugUpdate, error) {
+	fields = append(append([]string{}, fields...), "last_change_time")
+	for attempt := 1; ; attempt++ {
+		bug, err := c.GetBugWithFields(id, fields)
+		if err != nil {
+			return nil, err
+		}
+		update, err := compute(bug)
+		if err != nil || update == nil {
+			return nil, err
+		}
+		guarded := *update
+		lastChange := bug.LastChangeTime
+		guarded.LastChangeTime = &lastChange
+		err = c.UpdateBug(id, guarded)
+		if err == nil {
+			return update, nil
+		}
+		if !IsMidairCollision(err) || attempt == guardedUpdateAttempts {
+			return nil, err
+		}
+	}
+}
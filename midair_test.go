@@ -0,0 +1,174 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMidairCollision(t *testing.T) {
+	current := Timestamp{Time: time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)}
+	stale := Timestamp{Time: current.Add(-time.Hour)}
+	// reported is the last change time the server reports, while it checks
+	// guarded updates against the current one
+	reported := current
+	var requests []string
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method)
+		if r.URL.Path != "/rest/bug/1705243" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+			return
+		}
+		if r.Method == http.MethodGet {
+			if fields := r.URL.Query().Get("include_fields"); fields != "id,last_change_time" {
+				t.Errorf("expected only the last change time to be requested, got %q", fields)
+			}
+			fmt.Fprintf(w, `{"bugs":[{"id":1705243,"last_change_time":%q}]}`, reported.Format(time.RFC3339))
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read the request body: %v", err)
+		}
+		var payload map[string]interface{}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("failed to unmarshal the request body: %v", err)
+		}
+		if payload["delta_ts"] != nil && payload["delta_ts"] != "2020-05-01T12:00:00Z" {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error":true,"code":32610,"message":"The bug has been changed since you last read it."}`))
+			return
+		}
+		if payload["status"] == "INVALID" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":true,"code":60,"message":"This bug has been changed to a status which is not valid."}`))
+			return
+		}
+		w.Write([]byte(`{"bugs":[{"id":1705243,"changes":{}}]}`))
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	if err := client.UpdateBug(1705243, BugUpdate{Status: "ASSIGNED", LastChangeTime: &current}); err != nil {
+		t.Errorf("expected no error, but got one: %v", err)
+	}
+	if err := client.UpdateBug(1705243, BugUpdate{Status: "ASSIGNED"}); err != nil {
+		t.Errorf("expected no error, but got one: %v", err)
+	}
+	if expected := []string{http.MethodGet, http.MethodPut, http.MethodPut}; !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected only the guarded update to check the last change time, got requests %v", requests)
+	}
+
+	requests = nil
+	err := client.UpdateBug(1705243, BugUpdate{Status: "ASSIGNED", LastChangeTime: &stale})
+	if !IsMidairCollision(err) {
+		t.Fatalf("expected a mid-air collision, got %v", err)
+	}
+	var collision *MidairCollisionError
+	if !errors.As(err, &collision) || collision.ID != 1705243 || !collision.LastChangeTime.Equal(stale.Time) {
+		t.Errorf("expected the error to hold the bug and the expected last change time, got %v", err)
+	}
+	if expected := []string{http.MethodGet}; !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected the stale update not to be sent, got requests %v", requests)
+	}
+
+	reported = stale
+	err = client.UpdateBug(1705243, BugUpdate{Status: "ASSIGNED", LastChangeTime: &stale})
+	if !IsMidairCollision(err) {
+		t.Fatalf("expected a mid-air collision rejected by the server, got %v", err)
+	}
+	var reqError *RequestError
+	if !errors.As(err, &reqError) || reqError.StatusCode != http.StatusConflict {
+		t.Errorf("expected the error to wrap the server error, got %v", err)
+	}
+	reported = current
+	if err := client.UpdateBug(1705243, BugUpdate{Status: "INVALID", LastChangeTime: &current}); err == nil || IsMidairCollision(err) {
+		t.Errorf("expected a bad request mentioning changes not to be a mid-air collision, got %v", err)
+	}
+	if err := client.UpdateBugs([]int{1, 2}, BugUpdate{Status: "ASSIGNED", LastChangeTime: &current}); err == nil {
+		t.Error("expected an error guarding an update of several bugs")
+	}
+}
+
+func TestUpdateGuarded(t *testing.T) {
+	fake := &Fake{Bugs: map[int]Bug{1: {ID: 1, Whiteboard: "a"}}}
+	client := &collidingClient{Fake: fake, collisions: 2}
+	update, err := updateGuarded(client, 1, []string{"id", "whiteboard"}, func(bug *Bug) (*BugUpdate, error) {
+		return &BugUpdate{Whiteboard: bug.Whiteboard + " b"}, nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if expected := (&BugUpdate{Whiteboard: "a c b"}); !reflect.DeepEqual(update, expected) {
+		t.Errorf("expected the update to be computed from the latest read, got %v", update)
+	}
+	if whiteboard := fake.Bugs[1].Whiteboard; whiteboard != "a c b" {
+		t.Errorf("expected the concurrent change to be kept, got whiteboard %q", whiteboard)
+	}
+
+	client.collisions = guardedUpdateAttempts
+	if _, err := updateGuarded(client, 1, []string{"id", "whiteboard"}, func(bug *Bug) (*BugUpdate, error) {
+		return &BugUpdate{Whiteboard: bug.Whiteboard + " d"}, nil
+	}); !IsMidairCollision(err) {
+		t.Errorf("expected updates which keep colliding to fail, got %v", err)
+	}
+}
+
+// collidingClient changes the whiteboard of the bug before the first guarded
+// updates, like a concurrent update would
+type collidingClient struct {
+	*Fake
+	collisions int
+}
+
+func (c *collidingClient) UpdateBug(id int, update BugUpdate) error {
+	if c.collisions > 0 && update.LastChangeTime != nil {
+		c.collisions--
+		if err := c.Fake.UpdateBug(id, BugUpdate{Whiteboard: "a c"}); err != nil {
+			return err
+		}
+	}
+	return c.Fake.UpdateBug(id, update)
+}
+
+func TestFakeMidairCollision(t *testing.T) {
+	read := Timestamp{Time: time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)}
+	fake := &Fake{Bugs: map[int]Bug{1: {ID: 1, Status: "NEW", LastChangeTime: read}}}
+
+	if err := fake.UpdateBug(1, BugUpdate{Status: "ASSIGNED", LastChangeTime: &read}); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if err := fake.UpdateBug(1, BugUpdate{Status: "POST", LastChangeTime: &read}); !IsMidairCollision(err) {
+		t.Errorf("expected a mid-air collision, got %v", err)
+	}
+	bug := fake.Bugs[1]
+	if bug.Status != "ASSIGNED" {
+		t.Errorf("expected the colliding update not to be applied, got status %s", bug.Status)
+	}
+	if err := fake.UpdateBug(1, BugUpdate{Status: "POST", LastChangeTime: &bug.LastChangeTime}); err != nil {
+		t.Errorf("expected no error, but got one: %v", err)
+	}
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mirror
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/eparis/bugzilla"
+	"github.com/eparis/bugzilla/events"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	bugsBucket  = []byte("bugs")
+	indexBucket = []byte("index")
+	metaBucket  = []byte("meta")
+
+	checkpointKey = []byte("checkpoint")
+)
+
+// BoltStore is a Store keeping the bugs in a bbolt database file, indexed
+// by their product, component and status
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// the BoltStore can find bugs by the indexed fields
+var _ IndexedStore = &BoltStore{}
+
+// NewBoltStore opens the database file at path, creating it if it does not
+// exist. Only one process can open the file at a time. The store must be
+// closed when it is no longer used.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: 10 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("could not open mirror database: %v", err)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{bugsBucket, indexBucket, metaBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("could not create mirror buckets: %v", err)
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// Close closes the database file
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// bugKey encodes the ID so the keys sort like the IDs
+func bugKey(id int) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(id))
+	return key
+}
+
+// indexedValues returns the values of the indexed fields of the bug
+func indexedValues(bug *bugzilla.Bug) map[string][]string {
+	return map[string][]string{
+		"product":   {bug.Product},
+		"component": bug.Component,
+		"status":    {bug.Status},
+	}
+}
+
+func getBug(tx *bolt.Tx, id int) (*bugzilla.Bug, error) {
+	raw := tx.Bucket(bugsBucket).Get(bugKey(id))
+	if raw == nil {
+		return nil, nil
+	}
+	var bug bugzilla.Bug
+	if err := json.Unmarshal(raw, &bug); err != nil {
+		return nil, fmt.Errorf("could not parse bug %d: %v", id, err)
+	}
+	return &bug, nil
+}
+
+func (s *BoltStore) Get(id int) (*bugzilla.Bug, error) {
+	var bug *bugzilla.Bug
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		bug, err = getBug(tx, id)
+		return err
+	})
+	return bug, err
+}
+
+// Put stores all bugs in one transaction, so either all or none are stored
+func (s *BoltStore) Put(bugs []*bugzilla.Bug) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		index := tx.Bucket(indexBucket)
+		for _, bug := range bugs {
+			raw, err := json.Marshal(bug)
+			if err != nil {
+				return fmt.Errorf("could not serialize bug %d: %v", bug.ID, err)
+			}
+			key := bugKey(bug.ID)
+			previous, err := getBug(tx, bug.ID)
+			if err != nil {
+				return err
+			}
+			if previous != nil {
+				for field, values := range indexedValues(previous) {
+					for _, value := range values {
+						if err := unindex(index, field, value, key); err != nil {
+							return fmt.Errorf("could not index bug %d: %v", bug.ID, err)
+						}
+					}
+				}
+			}
+			if err := tx.Bucket(bugsBucket).Put(key, raw); err != nil {
+				return fmt.Errorf("could not write bug %d: %v", bug.ID, err)
+			}
+			for field, values := range indexedValues(bug) {
+				for _, value := range values {
+					if err := indexBug(index, field, value, key); err != nil {
+						return fmt.Errorf("could not index bug %d: %v", bug.ID, err)
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// indexBug adds the bug to the bucket of the value of the field, unless the
+// value is empty
+func indexBug(index *bolt.Bucket, field, value string, key []byte) error {
+	if value == "" {
+		return nil
+	}
+	fieldBucket, err := index.CreateBucketIfNotExists([]byte(field))
+	if err != nil {
+		return err
+	}
+	valueBucket, err := fieldBucket.CreateBucketIfNotExists([]byte(value))
+	if err != nil {
+		return err
+	}
+	return valueBucket.Put(key, []byte{})
+}
+
+// unindex removes the bug from the bucket of the value of the field
+func unindex(index *bolt.Bucket, field, value string, key []byte) error {
+	fieldBucket := index.Bucket([]byte(field))
+	if value == "" || fieldBucket == nil {
+		return nil
+	}
+	valueBucket := fieldBucket.Bucket([]byte(value))
+	if valueBucket == nil {
+		return nil
+	}
+	return valueBucket.Delete(key)
+}
+
+func (s *BoltStore) All() ([]*bugzilla.Bug, error) {
+	var bugs []*bugzilla.Bug
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bugsBucket).ForEach(func(key, raw []byte) error {
+			var bug bugzilla.Bug
+			if err := json.Unmarshal(raw, &bug); err != nil {
+				return fmt.Errorf("could not parse bug %d: %v", binary.BigEndian.Uint64(key), err)
+			}
+			bugs = append(bugs, &bug)
+			return nil
+		})
+	})
+	return bugs, err
+}
+
+// Find reads only the bugs in the index buckets of the values
+func (s *BoltStore) Find(field string, values []string) ([]*bugzilla.Bug, error) {
+	if _, indexed := indexedValues(&bugzilla.Bug{})[field]; !indexed {
+		return nil, fmt.Errorf("field %q is not indexed", field)
+	}
+	var bugs []*bugzilla.Bug
+	err := s.db.View(func(tx *bolt.Tx) error {
+		fieldBucket := tx.Bucket(indexBucket).Bucket([]byte(field))
+		if fieldBucket == nil {
+			return nil
+		}
+		found := map[int]bool{}
+		for _, value := range values {
+			if value == "" {
+				continue
+			}
+			valueBucket := fieldBucket.Bucket([]byte(value))
+			if valueBucket == nil {
+				continue
+			}
+			if err := valueBucket.ForEach(func(key, _ []byte) error {
+				id := int(binary.BigEndian.Uint64(key))
+				if found[id] {
+					return nil
+				}
+				found[id] = true
+				bug, err := getBug(tx, id)
+				if err != nil || bug == nil {
+					return err
+				}
+				bugs = append(bugs, bug)
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return bugs, err
+}
+
+func (s *BoltStore) Checkpoint() events.Checkpoint {
+	return &boltCheckpoint{db: s.db}
+}
+
+// boltCheckpoint keeps the checkpoint as JSON in the meta bucket of the database
+type boltCheckpoint struct {
+	db *bolt.DB
+}
+
+func (c *boltCheckpoint) Load(state interface{}) error {
+	return c.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(metaBucket).Get(checkpointKey)
+		if raw == nil {
+			return nil
+		}
+		if err := json.Unmarshal(raw, state); err != nil {
+			return fmt.Errorf("could not unmarshal checkpoint: %v", err)
+		}
+		return nil
+	})
+}
+
+func (c *boltCheckpoint) Save(state interface{}) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("could not marshal checkpoint: %v", err)
+	}
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(metaBucket).Put(checkpointKey, raw)
+	})
+}
@@ -0,0 +1,353 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mirror keeps a local copy of the bugs matching a query, so reports
+// can be run against it offline or without waiting for the server. The copy
+// is refreshed incrementally with the bugs which changed since the last sync,
+// using their last_change_time.
+//
+// Bugs are kept in a Store. NewDirStore keeps them as JSON files in a
+// directory and NewBoltStore in a bbolt database, which indexes them so
+// searches only read the bugs which may match. Other backends can be used by
+// implementing Store, or IndexedStore.
+package mirror
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/eparis/bugzilla"
+	"github.com/eparis/bugzilla/events"
+)
+
+// Store keeps the bugs of a Mirror and how far the last sync got
+type Store interface {
+	// Get returns the bug with the ID, or nil if the store does not have it.
+	Get(id int) (*bugzilla.Bug, error)
+	// Put adds the bugs, replacing the stored bugs with the same IDs.
+	Put(bugs []*bugzilla.Bug) error
+	// All returns all stored bugs in any order.
+	All() ([]*bugzilla.Bug, error)
+	// Checkpoint returns where the time of the latest synced change is stored.
+	Checkpoint() events.Checkpoint
+}
+
+// IndexedStore is a Store which can find bugs by the fields queries are
+// usually restricted to, so searches do not read all bugs
+type IndexedStore interface {
+	Store
+	// Find returns the stored bugs which have any of the values in the
+	// field, which is one of indexedFields, in any order.
+	Find(field string, values []string) ([]*bugzilla.Bug, error)
+}
+
+// indexedFields are the fields an IndexedStore finds bugs by, the most
+// selective first
+var indexedFields = []string{"component", "product", "status"}
+
+// queryValues returns the values the query wants in the indexed field
+func queryValues(query bugzilla.Query, field string) []string {
+	switch field {
+	case "component":
+		return query.Component
+	case "product":
+		return query.Product
+	case "status":
+		return query.Status
+	}
+	return nil
+}
+
+// Mirror keeps a local copy of the bugs matching a query
+type Mirror struct {
+	client bugzilla.Client
+	store  Store
+	query  bugzilla.Query
+}
+
+// New returns a Mirror of the bugs matching the query, kept in the store.
+// Only the fields in the IncludeFields of the query are mirrored, if it has
+// any, so they must include all fields reports need.
+func New(client bugzilla.Client, store Store, query bugzilla.Query) *Mirror {
+	return &Mirror{client: client, store: store, query: query}
+}
+
+// Sync copies the bugs which changed since the last sync to the store and
+// returns how many were copied. The first sync copies all bugs matching the
+// query. Bugs which stop matching the query are kept as they were when they
+// last matched.
+func (m *Mirror) Sync() (int, error) {
+	synced := 0
+	err := events.SyncModifiedSince(m.client, m.store.Checkpoint(), m.query, func(bugs []*bugzilla.Bug) error {
+		if err := m.store.Put(bugs); err != nil {
+			return fmt.Errorf("could not store bugs: %v", err)
+		}
+		synced = len(bugs)
+		return nil
+	})
+	return synced, err
+}
+
+// GetBug returns the mirrored bug with the ID. Like the client, it returns an
+// error matching bugzilla.IsNotFound if the bug is not mirrored.
+func (m *Mirror) GetBug(id int) (*bugzilla.Bug, error) {
+	bug, err := m.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if bug == nil {
+		return nil, &bugzilla.RequestError{StatusCode: http.StatusNotFound, Message: "bug not in the mirror"}
+	}
+	return bug, nil
+}
+
+// Search returns the mirrored bugs matching the query, ordered by ID. Only
+// the simple fields of the query can be evaluated locally, a query with
+// advanced conditions, a raw query or verified values returns an
+// UnsupportedQueryError. The IncludeFields of the query are ignored, the
+// bugs have all mirrored fields. Only the bugs with the IDs of the query are
+// read, or for an IndexedStore the bugs with the values of the query in an
+// indexed field, and all bugs otherwise.
+func (m *Mirror) Search(query bugzilla.Query) ([]*bugzilla.Bug, error) {
+	match, ids, err := matcher(query)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := m.candidates(query, ids)
+	if err != nil {
+		return nil, err
+	}
+	bugs := []*bugzilla.Bug{}
+	for _, bug := range candidates {
+		if match(bug) {
+			bugs = append(bugs, bug)
+		}
+	}
+	sort.Slice(bugs, func(i, j int) bool {
+		return bugs[i].ID < bugs[j].ID
+	})
+	return bugs, nil
+}
+
+// candidates returns the stored bugs which may match the query with the IDs
+func (m *Mirror) candidates(query bugzilla.Query, ids map[int]bool) ([]*bugzilla.Bug, error) {
+	if len(ids) > 0 {
+		var bugs []*bugzilla.Bug
+		for id := range ids {
+			bug, err := m.store.Get(id)
+			if err != nil {
+				return nil, err
+			}
+			if bug != nil {
+				bugs = append(bugs, bug)
+			}
+		}
+		return bugs, nil
+	}
+	if indexed, ok := m.store.(IndexedStore); ok {
+		for _, field := range indexedFields {
+			if values := queryValues(query, field); len(values) > 0 {
+				return indexed.Find(field, values)
+			}
+		}
+	}
+	return m.store.All()
+}
+
+// UnsupportedQueryError is returned when searching the mirror with a query
+// which can not be evaluated locally
+type UnsupportedQueryError struct {
+	// Reason describes the part of the query which is not supported.
+	Reason string
+}
+
+func (e *UnsupportedQueryError) Error() string {
+	return fmt.Sprintf("query can not be evaluated by the mirror: %s", e.Reason)
+}
+
+// matcher returns a function telling whether a bug matches the query, and
+// the IDs the query wants if it has any. Like for the server, a bug matches a
+// field of the query if it matches any of its values, and it matches the
+// query if it matches all fields which are set.
+func matcher(query bugzilla.Query) (func(*bugzilla.Bug) bool, map[int]bool, error) {
+	switch {
+	case len(query.Advanced) > 0:
+		return nil, nil, &UnsupportedQueryError{Reason: "advanced conditions"}
+	case query.Raw != "":
+		return nil, nil, &UnsupportedQueryError{Reason: "raw query"}
+	case len(query.Verified) > 0:
+		return nil, nil, &UnsupportedQueryError{Reason: "verified values"}
+	}
+	switch query.KeywordsType {
+	case "", "anywords", "allwords", "nowords":
+	default:
+		return nil, nil, &UnsupportedQueryError{Reason: fmt.Sprintf("keywords type %q", query.KeywordsType)}
+	}
+	switch query.BugIDsType {
+	case "", "anyexact":
+	default:
+		return nil, nil, &UnsupportedQueryError{Reason: fmt.Sprintf("bug IDs type %q", query.BugIDsType)}
+	}
+	ids := map[int]bool{}
+	for _, value := range query.BugIDs {
+		for _, field := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' }) {
+			id, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid bug ID %q: %v", field, err)
+			}
+			ids[id] = true
+		}
+	}
+	return func(bug *bugzilla.Bug) bool {
+		return (len(ids) == 0 || ids[bug.ID]) &&
+			matchesAny(query.Classification, bug.Classification) &&
+			matchesAny(query.Product, bug.Product) &&
+			matchesAny(query.Status, bug.Status) &&
+			matchesAny(query.Priority, bug.Priority) &&
+			matchesAny(query.Severity, bug.Severity) &&
+			matchesAny(query.Component, bug.Component...) &&
+			matchesAny(query.TargetRelease, bug.TargetRelease...) &&
+			matchesAny(query.Alias, bug.Alias...) &&
+			matchesKeywords(query.Keywords, query.KeywordsType, bug.Keywords)
+	}, ids, nil
+}
+
+// matchesAny returns true if no values are wanted or any of the values of the
+// bug is wanted
+func matchesAny(wanted []string, values ...string) bool {
+	if len(wanted) == 0 {
+		return true
+	}
+	for _, value := range values {
+		for _, want := range wanted {
+			if value == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesKeywords evaluates the keywords of the query like the server does,
+// where every value of the query may hold several space separated keywords
+func matchesKeywords(wanted []string, keywordsType string, keywords []string) bool {
+	var words []string
+	for _, value := range wanted {
+		words = append(words, strings.Fields(value)...)
+	}
+	if len(words) == 0 {
+		return true
+	}
+	found := 0
+	for _, word := range words {
+		if matchesAny([]string{word}, keywords...) {
+			found++
+		}
+	}
+	switch keywordsType {
+	case "allwords":
+		return found == len(words)
+	case "nowords":
+		return found == 0
+	default:
+		return found > 0
+	}
+}
+
+// NewDirStore returns a Store keeping every bug as a JSON file in the
+// directory and the checkpoint in a file next to them. The directory is
+// created if it does not exist.
+func NewDirStore(dir string) (Store, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "bugs"), 0755); err != nil {
+		return nil, fmt.Errorf("could not create mirror directory: %v", err)
+	}
+	return &dirStore{dir: dir}, nil
+}
+
+type dirStore struct {
+	dir string
+}
+
+func (s *dirStore) path(id int) string {
+	return filepath.Join(s.dir, "bugs", fmt.Sprintf("%d.json", id))
+}
+
+func (s *dirStore) Get(id int) (*bugzilla.Bug, error) {
+	raw, err := ioutil.ReadFile(s.path(id))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read bug %d: %v", id, err)
+	}
+	var bug bugzilla.Bug
+	if err := json.Unmarshal(raw, &bug); err != nil {
+		return nil, fmt.Errorf("could not parse bug %d: %v", id, err)
+	}
+	return &bug, nil
+}
+
+// Put replaces the file of every bug, so a crash while storing a bug leaves
+// the previous version of it
+func (s *dirStore) Put(bugs []*bugzilla.Bug) error {
+	for _, bug := range bugs {
+		raw, err := json.Marshal(bug)
+		if err != nil {
+			return fmt.Errorf("could not serialize bug %d: %v", bug.ID, err)
+		}
+		if err := bugzilla.WriteFileAtomic(s.path(bug.ID), raw); err != nil {
+			return fmt.Errorf("could not write bug %d: %v", bug.ID, err)
+		}
+	}
+	return nil
+}
+
+func (s *dirStore) All() ([]*bugzilla.Bug, error) {
+	entries, err := ioutil.ReadDir(filepath.Join(s.dir, "bugs"))
+	if err != nil {
+		return nil, fmt.Errorf("could not list bugs: %v", err)
+	}
+	var bugs []*bugzilla.Bug
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		id, err := strconv.Atoi(strings.TrimSuffix(name, ".json"))
+		if err != nil {
+			continue
+		}
+		bug, err := s.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		if bug != nil {
+			bugs = append(bugs, bug)
+		}
+	}
+	return bugs, nil
+}
+
+func (s *dirStore) Checkpoint() events.Checkpoint {
+	return events.NewFileCheckpoint(filepath.Join(s.dir, "checkpoint.json"))
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mirror
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/eparis/bugzilla"
+)
+
+func bugIDs(bugs []*bugzilla.Bug) []int {
+	ids := []int{}
+	for _, bug := range bugs {
+		ids = append(ids, bug.ID)
+	}
+	return ids
+}
+
+func TestSync(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mirror")
+	if err != nil {
+		t.Fatalf("could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	store, err := NewDirStore(dir)
+	if err != nil {
+		t.Fatalf("could not create store: %v", err)
+	}
+
+	start := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	fake := &bugzilla.Fake{Bugs: map[int]bugzilla.Bug{
+		1: {ID: 1, Status: "NEW", LastChangeTime: bugzilla.NewTimestamp(start)},
+		2: {ID: 2, Status: "ASSIGNED", LastChangeTime: bugzilla.NewTimestamp(start.Add(time.Minute))},
+	}}
+	mirror := New(fake, store, bugzilla.Query{})
+
+	if synced, err := mirror.Sync(); err != nil || synced != 2 {
+		t.Fatalf("expected the first sync to copy 2 bugs, got %d and %v", synced, err)
+	}
+	fake.Bugs[1] = bugzilla.Bug{ID: 1, Status: "POST", LastChangeTime: bugzilla.NewTimestamp(start.Add(time.Hour))}
+	// the bug which changed at the checkpoint is copied again
+	if synced, err := mirror.Sync(); err != nil || synced != 2 {
+		t.Fatalf("expected the second sync to copy 2 bugs, got %d and %v", synced, err)
+	}
+	bug, err := mirror.GetBug(1)
+	if err != nil {
+		t.Fatalf("expected no error getting the bug, got %v", err)
+	}
+	if bug.Status != "POST" {
+		t.Errorf("expected the mirrored bug to be updated, got status %q", bug.Status)
+	}
+	if _, err := mirror.GetBug(3); !bugzilla.IsNotFound(err) {
+		t.Errorf("expected a not found error for a bug which is not mirrored, got %v", err)
+	}
+
+	// the mirror is served from the store, so a new mirror sees the same bugs
+	fake.Bugs = nil
+	bugs, err := New(fake, store, bugzilla.Query{}).Search(bugzilla.Query{})
+	if err != nil {
+		t.Fatalf("expected no error searching, got %v", err)
+	}
+	if actual, expected := bugIDs(bugs), []int{1, 2}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected bugs %v, got %v", expected, actual)
+	}
+}
+
+// stores creates every kind of store in the directory
+var stores = map[string]func(dir string) (Store, error){
+	"directory": NewDirStore,
+	"bolt": func(dir string) (Store, error) {
+		return NewBoltStore(filepath.Join(dir, "mirror.db"))
+	},
+}
+
+func TestSearch(t *testing.T) {
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			testSearch(t, newStore)
+		})
+	}
+}
+
+func testSearch(t *testing.T, newStore func(string) (Store, error)) {
+	dir, err := ioutil.TempDir("", "mirror")
+	if err != nil {
+		t.Fatalf("could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	store, err := newStore(dir)
+	if err != nil {
+		t.Fatalf("could not create store: %v", err)
+	}
+	if closer, ok := store.(io.Closer); ok {
+		defer closer.Close()
+	}
+	if err := store.Put([]*bugzilla.Bug{
+		{ID: 1, Product: "OCP", Component: []string{"Networking"}, Status: "NEW", Keywords: []string{"Triaged"}},
+		{ID: 2, Product: "OCP", Component: []string{"Storage"}, Status: "POST", Keywords: []string{"Triaged", "Regression"}},
+		{ID: 3, Product: "RHEL", Component: []string{"kernel"}, Status: "NEW", TargetRelease: []string{"8.4"}},
+	}); err != nil {
+		t.Fatalf("could not store bugs: %v", err)
+	}
+	mirror := New(nil, store, bugzilla.Query{})
+
+	var testCases = []struct {
+		name        string
+		query       bugzilla.Query
+		expected    []int
+		expectedErr bool
+	}{
+		{
+			name:     "empty query matches all bugs",
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:     "fields match any of their values",
+			query:    bugzilla.Query{Status: []string{"NEW", "ASSIGNED"}},
+			expected: []int{1, 3},
+		},
+		{
+			name:     "all fields must match",
+			query:    bugzilla.Query{Product: []string{"OCP"}, Status: []string{"NEW"}},
+			expected: []int{1},
+		},
+		{
+			name:     "list fields match any of the values of the bug",
+			query:    bugzilla.Query{Component: []string{"Storage"}, TargetRelease: []string{}},
+			expected: []int{2},
+		},
+		{
+			name:     "bug IDs",
+			query:    bugzilla.Query{BugIDs: []string{"1,3"}, BugIDsType: "anyexact"},
+			expected: []int{1, 3},
+		},
+		{
+			name:     "all keywords",
+			query:    bugzilla.Query{Keywords: []string{"Triaged Regression"}, KeywordsType: "allwords"},
+			expected: []int{2},
+		},
+		{
+			name:     "no keywords",
+			query:    bugzilla.Query{Keywords: []string{"Triaged"}, KeywordsType: "nowords"},
+			expected: []int{3},
+		},
+		{
+			name:        "advanced conditions are not supported",
+			query:       bugzilla.Query{Advanced: []bugzilla.AdvancedQuery{{Field: "delta_ts", Op: "greaterthan", Value: "2020-01-01"}}},
+			expectedErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			bugs, err := mirror.Search(testCase.query)
+			if testCase.expectedErr != (err != nil) {
+				t.Fatalf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+			}
+			if testCase.expectedErr {
+				return
+			}
+			if actual := bugIDs(bugs); !reflect.DeepEqual(actual, testCase.expected) {
+				t.Errorf("%s: expected bugs %v, got %v", testCase.name, testCase.expected, actual)
+			}
+		})
+	}
+}
+
+// countingStore counts how often all bugs are read
+type countingStore struct {
+	IndexedStore
+	all int
+}
+
+func (s *countingStore) All() ([]*bugzilla.Bug, error) {
+	s.all++
+	return s.IndexedStore.All()
+}
+
+func TestBoltStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mirror")
+	if err != nil {
+		t.Fatalf("could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "mirror.db")
+	bolt, err := NewBoltStore(path)
+	if err != nil {
+		t.Fatalf("could not create store: %v", err)
+	}
+	store := &countingStore{IndexedStore: bolt}
+	if err := store.Put([]*bugzilla.Bug{
+		{ID: 1, Product: "OCP", Component: []string{"Networking"}, Status: "NEW"},
+		{ID: 2, Product: "OCP", Component: []string{"Storage"}, Status: "NEW"},
+	}); err != nil {
+		t.Fatalf("could not store bugs: %v", err)
+	}
+	// the index follows the changes of the bugs
+	if err := store.Put([]*bugzilla.Bug{{ID: 1, Product: "OCP", Component: []string{"Networking"}, Status: "POST"}}); err != nil {
+		t.Fatalf("could not store bugs: %v", err)
+	}
+	mirror := New(nil, store, bugzilla.Query{})
+	bugs, err := mirror.Search(bugzilla.Query{Status: []string{"NEW"}})
+	if err != nil {
+		t.Fatalf("expected no error searching, got %v", err)
+	}
+	if actual, expected := bugIDs(bugs), []int{2}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected bugs %v, got %v", expected, actual)
+	}
+	if store.all != 0 {
+		t.Errorf("expected a search by an indexed field not to read all bugs, read them %d times", store.all)
+	}
+
+	since := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	if err := store.Checkpoint().Save(since); err != nil {
+		t.Fatalf("could not save checkpoint: %v", err)
+	}
+	if err := bolt.Close(); err != nil {
+		t.Fatalf("could not close store: %v", err)
+	}
+	reopened, err := NewBoltStore(path)
+	if err != nil {
+		t.Fatalf("could not reopen store: %v", err)
+	}
+	defer reopened.Close()
+	var loaded time.Time
+	if err := reopened.Checkpoint().Load(&loaded); err != nil || !loaded.Equal(since) {
+		t.Errorf("expected checkpoint %v, got %v and %v", since, loaded, err)
+	}
+	if bug, err := reopened.Get(1); err != nil || bug == nil || bug.Status != "POST" {
+		t.Errorf("expected the bug to be kept, got %v and %v", bug, err)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"sort"
+	"time"
+)
+
+// GetBugsModifiedSince searches for the bugs matching the query which changed
+// at or after the time, ordered by when they last changed. The id and
+// last_change_time fields are added to the IncludeFields of the query, if it
+// has any, as they are needed to order the bugs.
+func (c *client) GetBugsModifiedSince(since time.Time, query Query) ([]*Bug, error) {
+	query.Advanced = append(append([]AdvancedQuery{}, query.Advanced...), AdvancedQuery{
+		Field: "delta_ts",
+		Op:    "greaterthaneq",
+		Value: NewTimestamp(since).String(),
+	})
+	if len(query.IncludeFields) > 0 {
+		query.IncludeFields = withFields(query.IncludeFields, "id", "last_change_time")
+	}
+	bugs, err := c.Search(query)
+	if err != nil {
+		return nil, err
+	}
+	sortByLastChange(bugs)
+	return bugs, nil
+}
+
+func withFields(fields []string, required ...string) []string {
+	fields = append([]string{}, fields...)
+	for _, field := range required {
+		if !contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+func sortByLastChange(bugs []*Bug) {
+	sort.SliceStable(bugs, func(i, j int) bool {
+		if !bugs[i].LastChangeTime.Equal(bugs[j].LastChangeTime.Time) {
+			return bugs[i].LastChangeTime.Before(bugs[j].LastChangeTime.Time)
+		}
+		return bugs[i].ID < bugs[j].ID
+	})
+}
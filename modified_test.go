@@ -0,0 +1,56 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetBugsModifiedSince(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		values := r.URL.Query()
+		if values.Get("f1") != "delta_ts" || values.Get("o1") != "greaterthaneq" || values.Get("v1") != "2020-06-01T12:00:00Z" {
+			t.Errorf("did not search for changes since the time: %v", values)
+		}
+		if actual, expected := values.Get("include_fields"), "status,id,last_change_time"; actual != expected {
+			t.Errorf("expected include_fields %q, got %q", expected, actual)
+		}
+		if values.Get("offset") != "0" {
+			w.Write([]byte(`{"bugs":[]}`))
+			return
+		}
+		w.Write([]byte(`{"bugs":[{"id":2,"last_change_time":"2020-06-01T12:05:00Z"},{"id":3,"last_change_time":"2020-06-01T12:00:00Z"},{"id":1,"last_change_time":"2020-06-01T12:05:00Z"}]}`))
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	bugs, err := client.GetBugsModifiedSince(time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC), Query{IncludeFields: []string{"status"}})
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	var ids []int
+	for _, bug := range bugs {
+		ids = append(ids, bug.ID)
+	}
+	if expected := []int{3, 1, 2}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected bugs %v, got %v", expected, ids)
+	}
+}
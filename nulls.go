@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// WithStrictNulls makes the client set Bug.Nullable, so consumers can tell a
+// field which is unset on the server, like dupe_of or deadline, from a field
+// which is empty.
+func WithStrictNulls() Option {
+	return func(c *client) {
+		c.strictNulls = true
+	}
+}
+
+// NullableFields are the fields of a bug which the server returns as null
+// when they are unset. They are not valid if the server returned null or did
+// not return the field at all.
+type NullableFields struct {
+	DupeOf    NullInt    `json:"dupe_of"`
+	Deadline  NullString `json:"deadline"`
+	QAContact NullString `json:"qa_contact"`
+}
+
+// NullInt is an integer which may be null
+type NullInt struct {
+	Int   int
+	Valid bool
+}
+
+// UnmarshalJSON decodes an integer, leaving the NullInt invalid for null
+func (n *NullInt) UnmarshalJSON(raw []byte) error {
+	*n = NullInt{}
+	if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		return nil
+	}
+	if err := json.Unmarshal(raw, &n.Int); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// NullString is a string which may be null
+type NullString struct {
+	String string
+	Valid  bool
+}
+
+// UnmarshalJSON decodes a string, leaving the NullString invalid for null
+func (n *NullString) UnmarshalJSON(raw []byte) error {
+	*n = NullString{}
+	if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		return nil
+	}
+	if err := json.Unmarshal(raw, &n.String); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// decodeNullableFields sets the Nullable fields of the bugs decoded from the raw response
+func decodeNullableFields(raw []byte, bugs []*Bug) error {
+	var parsedResponse struct {
+		Bugs []*NullableFields `json:"bugs,omitempty"`
+	}
+	// the other fields of the bugs are ignored, so this can not use the strict decoder
+	if err := json.Unmarshal(raw, &parsedResponse); err != nil {
+		return fmt.Errorf("could not unmarshal response body: %v", err)
+	}
+	if len(parsedResponse.Bugs) != len(bugs) {
+		return fmt.Errorf("got %d raw bugs for %d bugs", len(parsedResponse.Bugs), len(bugs))
+	}
+	for i, nullable := range parsedResponse.Bugs {
+		bugs[i].Nullable = nullable
+	}
+	return nil
+}
+
+// MarshalJSON marshals the update, sending the fields in ClearFields as empty
+// strings and adding the CustomFields
+func (u BugUpdate) MarshalJSON() ([]byte, error) {
+	// the alias type does not have this method, which avoids recursing
+	type update BugUpdate
+	raw, err := json.Marshal(update(u))
+	if err != nil || (len(u.ClearFields) == 0 && len(u.CustomFields) == 0) {
+		return raw, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	for _, field := range u.ClearFields {
+		if _, set := fields[field]; set {
+			return nil, fmt.Errorf("field %q can not be both set and cleared", field)
+		}
+		fields[field] = json.RawMessage(`""`)
+	}
+	if err := addCustomFields(fields, u.CustomFields); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
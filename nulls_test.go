@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestStrictNulls(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bugData)
+	}))
+	defer testServer.Close()
+
+	lenient := clientForUrl(testServer.URL)
+	bug, err := lenient.GetBug(1705243)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if bug.Nullable != nil {
+		t.Errorf("expected no nullable fields without strict mode, got %v", bug.Nullable)
+	}
+
+	strict := clientForUrl(testServer.URL).(*client)
+	WithStrictNulls()(strict)
+	bug, err = strict.GetBug(1705243)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := &NullableFields{QAContact: NullString{String: "", Valid: true}}
+	if !reflect.DeepEqual(bug.Nullable, expected) {
+		t.Errorf("expected dupe_of and deadline to be null and qa_contact to be empty, got %+v", bug.Nullable)
+	}
+}
+
+func TestNullableFieldsUnmarshal(t *testing.T) {
+	var fields NullableFields
+	if err := json.Unmarshal([]byte(`{"dupe_of":1705243,"deadline":null,"qa_contact":"qa@example.com"}`), &fields); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := NullableFields{DupeOf: NullInt{Int: 1705243, Valid: true}, QAContact: NullString{String: "qa@example.com", Valid: true}}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected %+v, got %+v", expected, fields)
+	}
+	if err := json.Unmarshal([]byte(`{"dupe_of":"foo"}`), &fields); err == nil {
+		t.Error("expected an error decoding a string as dupe_of, got none")
+	}
+}
+
+func TestBugUpdateClearFields(t *testing.T) {
+	deadline := ""
+	var testCases = []struct {
+		name        string
+		update      BugUpdate
+		expected    string
+		expectedErr bool
+	}{
+		{
+			name:     "pointer fields distinguish cleared from unset",
+			update:   BugUpdate{Deadline: &deadline},
+			expected: `{"deadline":""}`,
+		},
+		{
+			name:     "cleared fields are sent empty",
+			update:   BugUpdate{Status: "ASSIGNED", ClearFields: []string{"whiteboard"}},
+			expected: `{"status":"ASSIGNED","whiteboard":""}`,
+		},
+		{
+			name:     "custom fields are sent",
+			update:   BugUpdate{Status: "POST", CustomFields: map[string]interface{}{"cf_pm_score": 10}},
+			expected: `{"cf_pm_score":10,"status":"POST"}`,
+		},
+		{
+			name:        "fields can not be set and cleared",
+			update:      BugUpdate{Whiteboard: "something", ClearFields: []string{"whiteboard"}},
+			expectedErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			raw, err := json.Marshal(testCase.update)
+			if testCase.expectedErr != (err != nil) {
+				t.Fatalf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+			}
+			if err == nil && string(raw) != testCase.expected {
+				t.Errorf("%s: expected payload %s, got %s", testCase.name, testCase.expected, string(raw))
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/eparis/bugzilla"
+)
+
+// UnsetTargetRelease is the target release of a bug which is not targeted yet
+const UnsetTargetRelease = "---"
+
+// Release is a target release following the OpenShift conventions: a minor
+// release is targeted as `4.6.0`, while the z-stream of a release which is
+// already out is targeted as `4.5.z`.
+type Release struct {
+	Major   int
+	Minor   int
+	ZStream bool
+}
+
+func (r Release) String() string {
+	if r.ZStream {
+		return fmt.Sprintf("%d.%d.z", r.Major, r.Minor)
+	}
+	return fmt.Sprintf("%d.%d.0", r.Major, r.Minor)
+}
+
+var targetReleaseRe = regexp.MustCompile(`^(\d+)\.(\d+)\.(0|z)$`)
+
+// ParseTargetRelease parses a target release like `4.6.0` or `4.5.z`
+func ParseTargetRelease(targetRelease string) (Release, error) {
+	parts := targetReleaseRe.FindStringSubmatch(targetRelease)
+	if parts == nil {
+		return Release{}, fmt.Errorf("target release %q is not of the form X.Y.0 or X.Y.z", targetRelease)
+	}
+	major, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return Release{}, fmt.Errorf("invalid major version in target release %q: %v", targetRelease, err)
+	}
+	minor, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return Release{}, fmt.Errorf("invalid minor version in target release %q: %v", targetRelease, err)
+	}
+	return Release{Major: major, Minor: minor, ZStream: parts[3] == "z"}, nil
+}
+
+// HasTargetRelease returns true if the bug is targeted at a release
+func HasTargetRelease(bug *bugzilla.Bug) bool {
+	for _, targetRelease := range bug.TargetRelease {
+		if targetRelease != "" && targetRelease != UnsetTargetRelease {
+			return true
+		}
+	}
+	return false
+}
+
+// TargetRelease returns the release the bug is targeted at. It fails if the
+// bug is not targeted at exactly one release following the conventions.
+func TargetRelease(bug *bugzilla.Bug) (Release, error) {
+	if !HasTargetRelease(bug) {
+		return Release{}, fmt.Errorf("bug %d has no target release", bug.ID)
+	}
+	if len(bug.TargetRelease) != 1 {
+		return Release{}, fmt.Errorf("bug %d has %d target releases, not one", bug.ID, len(bug.TargetRelease))
+	}
+	return ParseTargetRelease(bug.TargetRelease[0])
+}
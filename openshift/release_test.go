@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"testing"
+
+	"github.com/eparis/bugzilla"
+)
+
+func TestParseTargetRelease(t *testing.T) {
+	var testCases = []struct {
+		targetRelease string
+		expected      Release
+		expectedErr   bool
+	}{
+		{targetRelease: "4.6.0", expected: Release{Major: 4, Minor: 6}},
+		{targetRelease: "4.5.z", expected: Release{Major: 4, Minor: 5, ZStream: true}},
+		{targetRelease: "4.10.z", expected: Release{Major: 4, Minor: 10, ZStream: true}},
+		{targetRelease: UnsetTargetRelease, expectedErr: true},
+		{targetRelease: "4.5", expectedErr: true},
+		{targetRelease: "4.5.1", expectedErr: true},
+	}
+	for _, testCase := range testCases {
+		actual, err := ParseTargetRelease(testCase.targetRelease)
+		if testCase.expectedErr != (err != nil) {
+			t.Errorf("%s: expected error %v, got %v", testCase.targetRelease, testCase.expectedErr, err)
+		}
+		if actual != testCase.expected {
+			t.Errorf("%s: expected %v, got %v", testCase.targetRelease, testCase.expected, actual)
+		}
+		if err == nil && actual.String() != testCase.targetRelease {
+			t.Errorf("%s: expected to format as itself, got %s", testCase.targetRelease, actual.String())
+		}
+	}
+}
+
+func TestTargetRelease(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		bug         bugzilla.Bug
+		expected    Release
+		expectedErr bool
+	}{
+		{name: "no target release", bug: bugzilla.Bug{}, expectedErr: true},
+		{name: "unset target release", bug: bugzilla.Bug{TargetRelease: []string{UnsetTargetRelease}}, expectedErr: true},
+		{name: "multiple target releases", bug: bugzilla.Bug{TargetRelease: []string{"4.5.z", "4.6.0"}}, expectedErr: true},
+		{name: "single target release", bug: bugzilla.Bug{TargetRelease: []string{"4.5.z"}}, expected: Release{Major: 4, Minor: 5, ZStream: true}},
+	}
+	for _, testCase := range testCases {
+		actual, err := TargetRelease(&testCase.bug)
+		if testCase.expectedErr != (err != nil) {
+			t.Errorf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+		}
+		if actual != testCase.expected {
+			t.Errorf("%s: expected %v, got %v", testCase.name, testCase.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package openshift encodes the bug workflow of OpenShift Container Platform
+// on top of the generic Bugzilla client, so tooling shares one validated
+// implementation of the valid status transitions, the flags required in each
+// status and the target release conventions.
+package openshift
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eparis/bugzilla"
+)
+
+// Product is the Bugzilla product of OpenShift Container Platform
+const Product = "OpenShift Container Platform"
+
+// The statuses a bug goes through in the OpenShift workflow, in order
+const (
+	StatusNew            = "NEW"
+	StatusAssigned       = "ASSIGNED"
+	StatusPost           = "POST"
+	StatusModified       = "MODIFIED"
+	StatusOnQA           = "ON_QA"
+	StatusVerified       = "VERIFIED"
+	StatusReleasePending = "RELEASE_PENDING"
+	StatusClosed         = "CLOSED"
+)
+
+// The resolutions of closed bugs
+const (
+	ResolutionErrata           = "ERRATA"
+	ResolutionCurrentRelease   = "CURRENTRELEASE"
+	ResolutionNotABug          = "NOTABUG"
+	ResolutionWontFix          = "WONTFIX"
+	ResolutionDeferred         = "DEFERRED"
+	ResolutionDuplicate        = "DUPLICATE"
+	ResolutionInsufficientData = "INSUFFICIENT_DATA"
+)
+
+// FlagRequiresDocText is the flag which records whether a bug needs to be
+// mentioned in the release notes
+const FlagRequiresDocText = "requires_doc_text"
+
+// Transitions holds the statuses a bug may move to from each status. Any open
+// bug may be closed, and a closed bug may only be reopened as ASSIGNED.
+var Transitions = map[string][]string{
+	StatusNew:            {StatusAssigned, StatusPost, StatusClosed},
+	StatusAssigned:       {StatusNew, StatusPost, StatusModified, StatusClosed},
+	StatusPost:           {StatusAssigned, StatusModified, StatusClosed},
+	StatusModified:       {StatusAssigned, StatusPost, StatusOnQA, StatusClosed},
+	StatusOnQA:           {StatusAssigned, StatusVerified, StatusClosed},
+	StatusVerified:       {StatusAssigned, StatusReleasePending, StatusClosed},
+	StatusReleasePending: {StatusAssigned, StatusClosed},
+	StatusClosed:         {StatusAssigned},
+}
+
+// RequiredFlags holds the flags which must be granted or denied before a bug
+// may move into each status.
+var RequiredFlags = map[string][]string{
+	StatusVerified:       {FlagRequiresDocText},
+	StatusReleasePending: {FlagRequiresDocText},
+}
+
+// requiresTargetRelease holds the statuses in which a bug must be targeted at
+// a release, as a fix is or has been in progress.
+var requiresTargetRelease = map[string]bool{
+	StatusPost:           true,
+	StatusModified:       true,
+	StatusOnQA:           true,
+	StatusVerified:       true,
+	StatusReleasePending: true,
+}
+
+// ValidTransition returns true if a bug may move from one status to another.
+// Staying in the same status is always valid.
+func ValidTransition(from, to string) bool {
+	if from == to {
+		return true
+	}
+	for _, allowed := range Transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition checks that the bug may move to the given status and
+// resolution: the transition must be valid, the resolution must be set if and
+// only if the bug is closed, the required flags must be granted or denied and
+// the bug must have a target release once a fix is in progress.
+func ValidateTransition(bug *bugzilla.Bug, status, resolution string) error {
+	if _, known := Transitions[status]; !known {
+		return fmt.Errorf("unknown status %s", status)
+	}
+	if !ValidTransition(bug.Status, status) {
+		return fmt.Errorf("bug %d may not move from %s to %s", bug.ID, bug.Status, status)
+	}
+	if status == StatusClosed && resolution == "" {
+		return fmt.Errorf("bug %d needs a resolution to be closed", bug.ID)
+	}
+	if status != StatusClosed && resolution != "" {
+		return fmt.Errorf("bug %d may only have a resolution when it is closed, not %s", bug.ID, status)
+	}
+	var missing []string
+	for _, name := range RequiredFlags[status] {
+		if !flagDecided(bug, name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) != 0 {
+		return fmt.Errorf("bug %d needs the %s flags to be set to move to %s", bug.ID, strings.Join(missing, ","), status)
+	}
+	if requiresTargetRelease[status] && !HasTargetRelease(bug) {
+		return fmt.Errorf("bug %d needs a target release to move to %s", bug.ID, status)
+	}
+	return nil
+}
+
+// Transition validates the transition and moves the bug to the given status
+// and resolution.
+func Transition(c bugzilla.Client, bug *bugzilla.Bug, status, resolution string) error {
+	if err := ValidateTransition(bug, status, resolution); err != nil {
+		return err
+	}
+	return c.UpdateBug(bug.ID, bugzilla.BugUpdate{Status: status, Resolution: resolution})
+}
+
+// flagDecided returns true if the flag was granted or denied on the bug
+func flagDecided(bug *bugzilla.Bug, name string) bool {
+	status, _ := bugzilla.FlagStatus(bug, name)
+	return status == bugzilla.FlagGranted || status == bugzilla.FlagDenied
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"testing"
+
+	"github.com/eparis/bugzilla"
+)
+
+func TestValidateTransition(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		bug         bugzilla.Bug
+		status      string
+		resolution  string
+		expectedErr bool
+	}{
+		{
+			name:   "assigning a new bug",
+			bug:    bugzilla.Bug{ID: 1, Status: StatusNew},
+			status: StatusAssigned,
+		},
+		{
+			name:        "skipping QA is not allowed",
+			bug:         bugzilla.Bug{ID: 1, Status: StatusModified, TargetRelease: []string{"4.6.0"}},
+			status:      StatusVerified,
+			expectedErr: true,
+		},
+		{
+			name:        "unknown status",
+			bug:         bugzilla.Bug{ID: 1, Status: StatusNew},
+			status:      "IN_PROGRESS",
+			expectedErr: true,
+		},
+		{
+			name:        "a fix needs a target release",
+			bug:         bugzilla.Bug{ID: 1, Status: StatusAssigned, TargetRelease: []string{UnsetTargetRelease}},
+			status:      StatusPost,
+			expectedErr: true,
+		},
+		{
+			name:   "a fix with a target release",
+			bug:    bugzilla.Bug{ID: 1, Status: StatusAssigned, TargetRelease: []string{"4.6.0"}},
+			status: StatusPost,
+		},
+		{
+			name:        "verifying needs the doc text flag",
+			bug:         bugzilla.Bug{ID: 1, Status: StatusOnQA, TargetRelease: []string{"4.6.0"}, Flags: []bugzilla.Flag{{Name: FlagRequiresDocText, Status: "?"}}},
+			status:      StatusVerified,
+			expectedErr: true,
+		},
+		{
+			name:   "verifying with the doc text flag denied",
+			bug:    bugzilla.Bug{ID: 1, Status: StatusOnQA, TargetRelease: []string{"4.6.0"}, Flags: []bugzilla.Flag{{Name: FlagRequiresDocText, Status: "-"}}},
+			status: StatusVerified,
+		},
+		{
+			name:        "closing needs a resolution",
+			bug:         bugzilla.Bug{ID: 1, Status: StatusNew},
+			status:      StatusClosed,
+			expectedErr: true,
+		},
+		{
+			name:       "closing with a resolution",
+			bug:        bugzilla.Bug{ID: 1, Status: StatusNew},
+			status:     StatusClosed,
+			resolution: ResolutionNotABug,
+		},
+		{
+			name:        "open bugs have no resolution",
+			bug:         bugzilla.Bug{ID: 1, Status: StatusClosed, Resolution: ResolutionNotABug},
+			status:      StatusAssigned,
+			resolution:  ResolutionNotABug,
+			expectedErr: true,
+		},
+		{
+			name:   "reopening a closed bug",
+			bug:    bugzilla.Bug{ID: 1, Status: StatusClosed, Resolution: ResolutionNotABug},
+			status: StatusAssigned,
+		},
+	}
+	for _, testCase := range testCases {
+		err := ValidateTransition(&testCase.bug, testCase.status, testCase.resolution)
+		if testCase.expectedErr != (err != nil) {
+			t.Errorf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+		}
+	}
+}
+
+func TestTransition(t *testing.T) {
+	bug := bugzilla.Bug{ID: 1, Status: StatusNew}
+	fake := &bugzilla.Fake{Bugs: map[int]bugzilla.Bug{1: bug}}
+	if err := Transition(fake, &bug, StatusOnQA, ""); err == nil {
+		t.Error("expected an error for an invalid transition, but got none")
+	}
+	if err := Transition(fake, &bug, StatusAssigned, ""); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if status := fake.Bugs[1].Status; status != StatusAssigned {
+		t.Errorf("expected the bug to be %s, got %s", StatusAssigned, status)
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"time"
+)
+
+// Priority orders the requests waiting for the rate limit when the client
+// uses a priority queue, see WithPriorityQueue
+type Priority int
+
+const (
+	// PriorityBulk is for calls of big syncs, like search pagination and
+	// bulk updates, which may wait.
+	PriorityBulk Priority = iota
+	// PriorityNormal is for calls without a priority.
+	PriorityNormal
+	// PriorityInteractive is for calls a user waits for, like GetBug.
+	PriorityInteractive
+)
+
+// DefaultPriorities are the priorities of the client methods in the
+// priority queue given to WithPriorityQueue with nil priorities
+var DefaultPriorities = map[string]Priority{
+	"GetBug":                  PriorityInteractive,
+	"GetBugWithFields":        PriorityInteractive,
+	"GetBugByAlias":           PriorityInteractive,
+	"GetCurrentUser":          PriorityInteractive,
+	"Search":                  PriorityBulk,
+	"SearchInto":              PriorityBulk,
+	"SearchBugsIter":          PriorityBulk,
+	"GetBugsModifiedSince":    PriorityBulk,
+	"GetExternalBugsForBugs":  PriorityBulk,
+	"GetExternalBugPRsOnBugs": PriorityBulk,
+	"UpdateBugs":              PriorityBulk,
+}
+
+// WithPriorityQueue makes requests which wait for the rate limit, see
+// WithRateLimit, take turns by priority, so user-facing calls stay
+// responsive during big syncs: a request only gets its turn once no request
+// with a higher priority is waiting. The priorities map the names of client
+// methods to their priority, methods without one have PriorityNormal. The
+// DefaultPriorities are used if priorities is nil. WithRequestPriority tags
+// all calls made through a client with a priority instead.
+func WithPriorityQueue(priorities map[string]Priority) Option {
+	if priorities == nil {
+		priorities = DefaultPriorities
+	}
+	return func(c *client) {
+		limiter := c.ensureLimiter()
+		limiter.priorities = priorities
+		limiter.waiting = map[Priority]int{}
+	}
+}
+
+// WithRequestPriority returns a client whose calls have the priority in the
+// priority queue, regardless of the method. The returned client shares all
+// configuration and state with the given one. Clients which do not support
+// priorities are returned as they are.
+func WithRequestPriority(c Client, priority Priority) Client {
+	original, ok := c.(*client)
+	if !ok {
+		return c
+	}
+	prioritized := *original
+	prioritized.priority = &priority
+	return &prioritized
+}
+
+// requestPriority returns the priority of the request logged with the logger
+func (c *client) requestPriority(logger *logEntry) Priority {
+	if c.priority != nil {
+		return *c.priority
+	}
+	method, _ := logger.Data[methodField].(string)
+	if priority, ok := c.limiter.priorities[method]; ok {
+		return priority
+	}
+	return PriorityNormal
+}
+
+// waitForTurn blocks on the clock until a token is available and no request
+// with a higher priority is waiting for one
+func (l *rateLimiter) waitForTurn(clock Clock, priority Priority) {
+	l.lock.Lock()
+	l.waiting[priority]++
+	for {
+		now := clock.Now()
+		qps, burst := l.rate(now)
+		if qps <= 0 {
+			l.last = now
+			break
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		l.tokens += now.Sub(l.last).Seconds() * qps
+		if l.tokens > burst {
+			l.tokens = burst
+		}
+		l.last = now
+		if l.tokens >= 1 && !l.higherWaiting(priority) {
+			l.tokens--
+			break
+		}
+		// wait for the next token, when waiting requests check again
+		delay := time.Duration((1 - l.tokens) / qps * float64(time.Second))
+		if delay <= 0 {
+			delay = time.Duration(float64(time.Second) / qps)
+		}
+		l.lock.Unlock()
+		clock.Sleep(delay)
+		l.lock.Lock()
+	}
+	l.waiting[priority]--
+	l.lock.Unlock()
+}
+
+// higherWaiting returns true if a request with a higher priority is waiting
+func (l *rateLimiter) higherWaiting(priority Priority) bool {
+	for waiting, count := range l.waiting {
+		if waiting > priority && count > 0 {
+			return true
+		}
+	}
+	return false
+}
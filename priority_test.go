@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRequestPriority(t *testing.T) {
+	c := clientForUrl("http://example.com").(*client)
+	WithPriorityQueue(nil)(c)
+	for method, expected := range map[string]Priority{
+		"GetBug":     PriorityInteractive,
+		"Search":     PriorityBulk,
+		"UpdateBug":  PriorityNormal,
+		"GetVersion": PriorityNormal,
+	} {
+		if actual := c.requestPriority(c.logger.WithField(methodField, method)); actual != expected {
+			t.Errorf("%s: expected priority %d, got %d", method, expected, actual)
+		}
+	}
+	tagged := WithRequestPriority(c, PriorityBulk).(*client)
+	if actual := tagged.requestPriority(tagged.logger.WithField(methodField, "GetBug")); actual != PriorityBulk {
+		t.Errorf("expected the tagged client to use its priority, got %d", actual)
+	}
+}
+
+func TestWaitForTurn(t *testing.T) {
+	limiter := &rateLimiter{qps: 50, burst: 1, tokens: 1, last: time.Now(), waiting: map[Priority]int{}, priorities: DefaultPriorities}
+
+	var lock sync.Mutex
+	var order []Priority
+	var wg sync.WaitGroup
+	wait := func(priority Priority) {
+		defer wg.Done()
+		limiter.waitForTurn(realClock{}, priority)
+		lock.Lock()
+		order = append(order, priority)
+		lock.Unlock()
+	}
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go wait(PriorityBulk)
+	}
+	// let the bulk requests take the token and start waiting for the next ones
+	time.Sleep(5 * time.Millisecond)
+	wg.Add(1)
+	go wait(PriorityInteractive)
+	wg.Wait()
+
+	// only the bulk request which took the first token may go first
+	if len(order) != 4 || (order[0] != PriorityInteractive && order[1] != PriorityInteractive) {
+		t.Errorf("expected the interactive request to preempt the waiting bulk requests, got order %v", order)
+	}
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package progcall compiles the status of tracker bugs for program calls:
+// who owns them, where they stand, what was said last and how their pull
+// requests are doing, rendered as a table to paste into the meeting notes.
+package progcall
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/eparis/bugzilla"
+)
+
+// Status is the status of one tracker bug
+type Status struct {
+	ID         int
+	Summary    string
+	Status     string
+	Resolution string
+	Severity   string
+	AssignedTo string
+	QAContact  string
+	// LatestComment is the latest comment on the bug, if any
+	LatestComment *bugzilla.Comment
+	// PRs are the pull requests linked to the bug
+	PRs []bugzilla.ExternalBug
+}
+
+// State returns the status of the bug, with the resolution if there is one
+func (s Status) State() string {
+	if s.Resolution == "" {
+		return s.Status
+	}
+	return s.Status + " " + s.Resolution
+}
+
+// Collector retrieves the status of tracker bugs
+type Collector struct {
+	Client bugzilla.Client
+	// Concurrency is the number of bugs retrieved at once, one if unset.
+	Concurrency int
+	// IncludePrivate makes private comments count as the latest comment. They
+	// are left out by default, as program calls are usually broader audiences.
+	IncludePrivate bool
+}
+
+// Collect retrieves the status of the bugs, in the order of the IDs. Every
+// bug is retrieved with everything attached to it, see
+// bugzilla.BulkGetBugsFull, and the pull requests of all bugs at once. Bugs
+// which could not be retrieved are left out and returned as a
+// *bugzilla.BulkError along with the others.
+func (c *Collector) Collect(ids []int) ([]Status, error) {
+	bugs, err := bugzilla.BulkGetBugsFull(c.Client, ids, c.Concurrency)
+	var bulkErr *bugzilla.BulkError
+	if err != nil && !errors.As(err, &bulkErr) {
+		return nil, err
+	}
+	var retrieved []int
+	for _, bug := range bugs {
+		retrieved = append(retrieved, bug.Bug.ID)
+	}
+	var prs map[int][]bugzilla.ExternalBug
+	if len(retrieved) > 0 {
+		if prs, err = c.Client.GetExternalBugPRsOnBugs(retrieved); err != nil {
+			return nil, fmt.Errorf("could not get pull requests: %v", err)
+		}
+	}
+	var collected []Status
+	for _, bug := range bugs {
+		collected = append(collected, c.status(bug, prs[bug.Bug.ID]))
+	}
+	if bulkErr != nil {
+		return collected, bulkErr
+	}
+	return collected, nil
+}
+
+// status returns the status of the bug with the pull requests
+func (c *Collector) status(full *bugzilla.FullBug, prs []bugzilla.ExternalBug) Status {
+	bug := full.Bug
+	status := Status{
+		ID:         bug.ID,
+		Summary:    bug.Summary,
+		Status:     bug.Status,
+		Resolution: bug.Resolution,
+		Severity:   bug.Severity,
+		AssignedTo: bug.AssignedTo,
+		QAContact:  bug.QAContact,
+		PRs:        prs,
+	}
+	for i := len(full.Comments) - 1; i >= 0; i-- {
+		if full.Comments[i].IsPrivate && !c.IncludePrivate {
+			continue
+		}
+		status.LatestComment = &full.Comments[i]
+		break
+	}
+	return status
+}
+
+// Snippet returns the text with its whitespace collapsed, cut to at most
+// length characters
+func Snippet(text string, length int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if length <= 0 || len(runes) <= length {
+		return text
+	}
+	if length == 1 {
+		return "…"
+	}
+	return strings.TrimSpace(string(runes[:length-1])) + "…"
+}
+
+// Format is the format the table is rendered in
+type Format string
+
+const (
+	Markdown Format = "markdown"
+	CSV      Format = "csv"
+)
+
+// Table renders the statuses of tracker bugs
+type Table struct {
+	// Endpoint is the Bugzilla the bugs are linked to.
+	Endpoint string
+	// SnippetLength is the length comments are cut to, 80 if unset.
+	SnippetLength int
+}
+
+var header = []string{"Bug", "Summary", "Status", "Severity", "Assignee", "QA Contact", "Latest Comment", "PRs"}
+
+// row returns the cells of a status, in the order of the header
+func (t Table) row(status Status) []string {
+	length := t.SnippetLength
+	if length == 0 {
+		length = 80
+	}
+	comment := ""
+	if status.LatestComment != nil {
+		comment = fmt.Sprintf("%s (%s): %s", status.LatestComment.Creator, status.LatestComment.Time.Format("2006-01-02"), Snippet(status.LatestComment.Text, length))
+	}
+	var prs []string
+	for _, pr := range status.PRs {
+		state := pr.ExternalStatus
+		if state == "" {
+			state = "unknown"
+		}
+		prs = append(prs, fmt.Sprintf("%s/%s#%d %s", pr.Org, pr.Repo, pr.Num, strings.ToLower(state)))
+	}
+	return []string{
+		fmt.Sprintf("%d", status.ID),
+		status.Summary,
+		status.State(),
+		status.Severity,
+		status.AssignedTo,
+		status.QAContact,
+		comment,
+		strings.Join(prs, ", "),
+	}
+}
+
+// Render writes the statuses as a table in the format
+func (t Table) Render(w io.Writer, format Format, statuses []Status) error {
+	switch format {
+	case Markdown:
+		return t.renderMarkdown(w, statuses)
+	case CSV:
+		return t.renderCSV(w, statuses)
+	default:
+		return fmt.Errorf("unknown format %q, expected %s or %s", format, Markdown, CSV)
+	}
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\n", " ")
+
+func (t Table) renderMarkdown(w io.Writer, statuses []Status) error {
+	writeRow := func(cells []string) error {
+		for i := range cells {
+			cells[i] = markdownEscaper.Replace(cells[i])
+		}
+		_, err := fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+		return err
+	}
+	if err := writeRow(append([]string{}, header...)); err != nil {
+		return err
+	}
+	separator := make([]string, len(header))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	if err := writeRow(separator); err != nil {
+		return err
+	}
+	endpoint := strings.TrimSuffix(t.Endpoint, "/")
+	for _, status := range statuses {
+		cells := t.row(status)
+		if endpoint != "" {
+			cells[0] = fmt.Sprintf("[%d](%s/show_bug.cgi?id=%d)", status.ID, endpoint, status.ID)
+		}
+		if err := writeRow(cells); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t Table) renderCSV(w io.Writer, statuses []Status) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		if err := writer.Write(t.row(status)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
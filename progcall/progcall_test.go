@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progcall
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/eparis/bugzilla"
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestCollect(t *testing.T) {
+	commented := bugzilla.NewTimestamp(time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC))
+	fake := &bugzilla.Fake{
+		Bugs: map[int]bugzilla.Bug{
+			1: {ID: 1, Summary: "router drops connections", Status: "POST", Severity: "high", AssignedTo: "dev@example.com", QAContact: "qe@example.com"},
+			2: {ID: 2, Summary: "installer hangs", Status: "CLOSED", Resolution: "ERRATA"},
+			3: {ID: 3},
+		},
+		BugComments: map[int][]bugzilla.Comment{
+			1: {{Text: "description"}, {Text: "public update", Time: commented}, {Text: "private update", IsPrivate: true}},
+		},
+		ExternalBugs: map[int][]bugzilla.ExternalBug{
+			1: {{ExternalBugID: "openshift/router/pull/1", ExternalStatus: "open"}, {ExternalBugID: "RHSA-2020:1234"}},
+		},
+		BugErrors: map[int]bool{3: true},
+	}
+	collector := &Collector{Client: fake, Concurrency: 2}
+	statuses, err := collector.Collect([]int{2, 3, 1})
+	bulkErr, ok := err.(*bugzilla.BulkError)
+	if !ok || !reflect.DeepEqual(bulkErr.IDs(), []int{3}) {
+		t.Fatalf("expected bug 3 to fail, got %v", err)
+	}
+	expected := []Status{
+		{ID: 2, Summary: "installer hangs", Status: "CLOSED", Resolution: "ERRATA"},
+		{
+			ID: 1, Summary: "router drops connections", Status: "POST", Severity: "high", AssignedTo: "dev@example.com", QAContact: "qe@example.com",
+			LatestComment: &bugzilla.Comment{Text: "public update", Time: commented},
+			PRs:           []bugzilla.ExternalBug{{ExternalBugID: "openshift/router/pull/1", Org: "openshift", Repo: "router", Num: 1, ExternalStatus: "open"}},
+		},
+	}
+	if !reflect.DeepEqual(statuses, expected) {
+		t.Errorf("got incorrect statuses: %v", diff.ObjectReflectDiff(expected, statuses))
+	}
+
+	collector.IncludePrivate = true
+	statuses, err = collector.Collect([]int{1})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if statuses[0].LatestComment == nil || statuses[0].LatestComment.Text != "private update" {
+		t.Errorf("expected the private comment to be the latest, got %+v", statuses[0].LatestComment)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	var testCases = []struct {
+		text     string
+		length   int
+		expected string
+	}{
+		{text: "short", length: 10, expected: "short"},
+		{text: "  spread\n\nover   lines ", length: 20, expected: "spread over lines"},
+		{text: "this is far too long", length: 9, expected: "this is…"},
+		{text: "unlimited length", length: 0, expected: "unlimited length"},
+	}
+	for _, testCase := range testCases {
+		if actual := Snippet(testCase.text, testCase.length); actual != testCase.expected {
+			t.Errorf("%q: expected %q, got %q", testCase.text, testCase.expected, actual)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	statuses := []Status{
+		{
+			ID: 1, Summary: "router | drops connections", Status: "POST", Severity: "high", AssignedTo: "dev@example.com", QAContact: "qe@example.com",
+			LatestComment: &bugzilla.Comment{Creator: "dev@example.com", Text: "fix is\nin review", Time: bugzilla.NewTimestamp(time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC))},
+			PRs:           []bugzilla.ExternalBug{{Org: "openshift", Repo: "router", Num: 1, ExternalStatus: "Open"}, {Org: "openshift", Repo: "api", Num: 2, ExternalStatus: "merged"}},
+		},
+		{ID: 2, Summary: "installer hangs", Status: "CLOSED", Resolution: "ERRATA"},
+	}
+	var testCases = []struct {
+		format   Format
+		expected string
+	}{
+		{
+			format: Markdown,
+			expected: `| Bug | Summary | Status | Severity | Assignee | QA Contact | Latest Comment | PRs |
+| --- | --- | --- | --- | --- | --- | --- | --- |
+| [1](https://bugzilla.example.com/show_bug.cgi?id=1) | router \| drops connections | POST | high | dev@example.com | qe@example.com | dev@example.com (2020-06-01): fix is in review | openshift/router#1 open, openshift/api#2 merged |
+| [2](https://bugzilla.example.com/show_bug.cgi?id=2) | installer hangs | CLOSED ERRATA |  |  |  |  |  |
+`,
+		},
+		{
+			format: CSV,
+			expected: `Bug,Summary,Status,Severity,Assignee,QA Contact,Latest Comment,PRs
+1,router | drops connections,POST,high,dev@example.com,qe@example.com,dev@example.com (2020-06-01): fix is in review,"openshift/router#1 open, openshift/api#2 merged"
+2,installer hangs,CLOSED ERRATA,,,,,
+`,
+		},
+	}
+	table := Table{Endpoint: "https://bugzilla.example.com/"}
+	for _, testCase := range testCases {
+		var out bytes.Buffer
+		if err := table.Render(&out, testCase.format, statuses); err != nil {
+			t.Fatalf("%s: expected no error, got %v", testCase.format, err)
+		}
+		if out.String() != testCase.expected {
+			t.Errorf("%s: got incorrect table: %v", testCase.format, diff.StringDiff(testCase.expected, out.String()))
+		}
+	}
+	if err := table.Render(&bytes.Buffer{}, Format("html"), statuses); err == nil {
+		t.Error("expected an error for an unknown format, got none")
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package quality scores how well bugs are filed, to prioritize triage queues
+// and to tell reporters what their bugs are missing.
+package quality
+
+import (
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/eparis/bugzilla"
+)
+
+// Criterion is a property of a well filed bug
+type Criterion string
+
+const (
+	// Reproducer is met when the description holds steps to reproduce the bug
+	Reproducer Criterion = "reproducer"
+	// Logs is met when logs were attached to the bug
+	Logs Criterion = "logs"
+	// Component is met when the component of the bug is set
+	Component Criterion = "component"
+	// Severity is met when the severity of the bug is set
+	Severity Criterion = "severity"
+	// RecentActivity is met when the bug changed recently
+	RecentActivity Criterion = "recent activity"
+)
+
+// Criteria are all criteria, in the order in which they are reported
+var Criteria = []Criterion{Reproducer, Logs, Component, Severity, RecentActivity}
+
+// Weights holds the weight of every criterion in the score. Criteria without
+// a weight do not count.
+type Weights map[Criterion]float64
+
+// DefaultWeights favors what a developer needs most to act on a bug
+var DefaultWeights = Weights{
+	Reproducer:     3,
+	Logs:           2,
+	Component:      1,
+	Severity:       1,
+	RecentActivity: 1,
+}
+
+var (
+	// DefaultReproducerPattern matches a "Steps to Reproduce" section whose
+	// first step is filled in, unlike the template of a new bug
+	DefaultReproducerPattern = regexp.MustCompile(`(?im)^steps to reproduce:?[ \t]*\n?[ \t]*(?:1\.)?[ \t]*[^\s\d:.]`)
+	// DefaultLogsPattern matches the comment of an attachment holding logs
+	DefaultLogsPattern = regexp.MustCompile(`(?i)\blogs?\b|must-gather|sosreport|journal`)
+)
+
+// Scorer computes the quality score of bugs. The zero value uses the
+// defaults for every setting.
+type Scorer struct {
+	// Weights are the weights of the criteria, DefaultWeights if nil.
+	Weights Weights
+	// ReproducerPattern is matched against the description of the bug,
+	// DefaultReproducerPattern if nil.
+	ReproducerPattern *regexp.Regexp
+	// LogsPattern is matched against the comments which were added with an
+	// attachment, DefaultLogsPattern if nil.
+	LogsPattern *regexp.Regexp
+	// ActivityWindow is how recently a bug must have changed to be active,
+	// 30 days if zero.
+	ActivityWindow time.Duration
+	// Now returns the current time, time.Now if nil.
+	Now func() time.Time
+}
+
+// Score is the quality score of a bug
+type Score struct {
+	// Value is the weight of the criteria which are met relative to the
+	// weight of all criteria, between 0 and 1.
+	Value float64
+	// Missing holds the weighted criteria which are not met, to tell the
+	// reporter what to add.
+	Missing []Criterion
+}
+
+// Score computes the score of the bug from the bug and its comments, of which
+// the first is the description.
+func (s *Scorer) Score(bug *bugzilla.Bug, comments []bugzilla.Comment) Score {
+	weights := s.Weights
+	if weights == nil {
+		weights = DefaultWeights
+	}
+	var score Score
+	var met, total float64
+	for _, criterion := range Criteria {
+		weight := weights[criterion]
+		if weight <= 0 {
+			continue
+		}
+		total += weight
+		if s.meets(criterion, bug, comments) {
+			met += weight
+		} else {
+			score.Missing = append(score.Missing, criterion)
+		}
+	}
+	if total > 0 {
+		score.Value = met / total
+	}
+	return score
+}
+
+func (s *Scorer) meets(criterion Criterion, bug *bugzilla.Bug, comments []bugzilla.Comment) bool {
+	switch criterion {
+	case Reproducer:
+		pattern := s.ReproducerPattern
+		if pattern == nil {
+			pattern = DefaultReproducerPattern
+		}
+		return len(comments) > 0 && pattern.MatchString(comments[0].Text)
+	case Logs:
+		pattern := s.LogsPattern
+		if pattern == nil {
+			pattern = DefaultLogsPattern
+		}
+		for _, comment := range comments {
+			if comment.AttachmentId != nil && pattern.MatchString(comment.Text) {
+				return true
+			}
+		}
+		return false
+	case Component:
+		for _, component := range bug.Component {
+			if component != "" && component != "Unknown" {
+				return true
+			}
+		}
+		return false
+	case Severity:
+		return bug.Severity != "" && bug.Severity != "unspecified"
+	case RecentActivity:
+		window := s.ActivityWindow
+		if window == 0 {
+			window = 30 * 24 * time.Hour
+		}
+		now := time.Now
+		if s.Now != nil {
+			now = s.Now
+		}
+		return !bug.LastChangeTime.IsZero() && now().Sub(bug.LastChangeTime.Time) <= window
+	}
+	return false
+}
+
+// ScoredBug is a bug with its score
+type ScoredBug struct {
+	Bug   *bugzilla.Bug
+	Score Score
+}
+
+// Rank scores the bugs, fetching their comments, and sorts them so the best
+// filed bugs, which are the quickest to act on, come first. Bugs with the
+// same score keep their order.
+func (s *Scorer) Rank(c bugzilla.Client, bugs []*bugzilla.Bug) ([]ScoredBug, error) {
+	scored := make([]ScoredBug, 0, len(bugs))
+	for _, bug := range bugs {
+		comments, err := c.GetBugComments(bug.ID)
+		if err != nil {
+			return nil, err
+		}
+		scored = append(scored, ScoredBug{Bug: bug, Score: s.Score(bug, comments)})
+	}
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].Score.Value > scored[j].Score.Value
+	})
+	return scored, nil
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quality
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/eparis/bugzilla"
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestScore(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	attachment := 1
+	wellFiled := bugzilla.Bug{
+		ID:             1,
+		Component:      []string{"Networking"},
+		Severity:       "high",
+		LastChangeTime: bugzilla.NewTimestamp(now.Add(-24 * time.Hour)),
+	}
+	description := bugzilla.Comment{Text: "Description of problem:\nPods lose connectivity.\n\nSteps to Reproduce:\n1. Create a pod\n2. Restart the node"}
+	logs := bugzilla.Comment{Text: "Created attachment 1\nmust-gather", AttachmentId: &attachment}
+
+	var testCases = []struct {
+		name            string
+		weights         Weights
+		bug             bugzilla.Bug
+		comments        []bugzilla.Comment
+		expectedValue   float64
+		expectedMissing []Criterion
+	}{
+		{
+			name:          "well filed bug",
+			bug:           wellFiled,
+			comments:      []bugzilla.Comment{description, logs},
+			expectedValue: 1,
+		},
+		{
+			name:            "empty bug",
+			bug:             bugzilla.Bug{ID: 1, Component: []string{"Unknown"}, Severity: "unspecified"},
+			expectedMissing: Criteria,
+		},
+		{
+			name:            "template left unfilled",
+			bug:             wellFiled,
+			comments:        []bugzilla.Comment{{Text: "Description of problem:\n\nSteps to Reproduce:\n1.\n2.\n3.\n\nActual results:\n"}, logs},
+			expectedValue:   5.0 / 8,
+			expectedMissing: []Criterion{Reproducer},
+		},
+		{
+			name:            "attachment without logs",
+			bug:             wellFiled,
+			comments:        []bugzilla.Comment{description, {Text: "Created attachment 1\nscreenshot", AttachmentId: &attachment}},
+			expectedValue:   6.0 / 8,
+			expectedMissing: []Criterion{Logs},
+		},
+		{
+			name: "stale bug",
+			bug: bugzilla.Bug{
+				ID:             1,
+				Component:      []string{"Networking"},
+				Severity:       "high",
+				LastChangeTime: bugzilla.NewTimestamp(now.Add(-60 * 24 * time.Hour)),
+			},
+			comments:        []bugzilla.Comment{description, logs},
+			expectedValue:   7.0 / 8,
+			expectedMissing: []Criterion{RecentActivity},
+		},
+		{
+			name:            "criteria without weight do not count",
+			weights:         Weights{Component: 1, Severity: 1},
+			bug:             bugzilla.Bug{ID: 1, Component: []string{"Networking"}},
+			expectedValue:   0.5,
+			expectedMissing: []Criterion{Severity},
+		},
+	}
+	for _, testCase := range testCases {
+		scorer := &Scorer{Weights: testCase.weights, Now: func() time.Time { return now }}
+		score := scorer.Score(&testCase.bug, testCase.comments)
+		if score.Value != testCase.expectedValue {
+			t.Errorf("%s: expected score %v, got %v", testCase.name, testCase.expectedValue, score.Value)
+		}
+		if !reflect.DeepEqual(score.Missing, testCase.expectedMissing) {
+			t.Errorf("%s: got incorrect missing criteria: %v", testCase.name, diff.ObjectReflectDiff(testCase.expectedMissing, score.Missing))
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	fake := &bugzilla.Fake{
+		Bugs: map[int]bugzilla.Bug{
+			1: {ID: 1},
+			2: {ID: 2, Severity: "high"},
+			3: {ID: 3, Severity: "high", Component: []string{"Networking"}},
+		},
+		BugComments: map[int][]bugzilla.Comment{1: {}, 2: {}, 3: {}},
+	}
+	var bugs []*bugzilla.Bug
+	for _, id := range []int{1, 2, 3} {
+		bug := fake.Bugs[id]
+		bugs = append(bugs, &bug)
+	}
+	scorer := &Scorer{Weights: Weights{Component: 1, Severity: 1}}
+	ranked, err := scorer.Rank(fake, bugs)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var ids []int
+	for _, scored := range ranked {
+		ids = append(ids, scored.Bug.ID)
+	}
+	if expected := []int{3, 2, 1}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("got incorrect order: %v", diff.ObjectReflectDiff(expected, ids))
+	}
+
+	if _, err := scorer.Rank(fake, []*bugzilla.Bug{{ID: 4}}); !bugzilla.IsNotFound(err) {
+		t.Errorf("expected a not found error for an unknown bug, got %v", err)
+	}
+}
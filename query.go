@@ -17,11 +17,12 @@ limitations under the License.
 package bugzilla
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
-
-	"github.com/sirupsen/logrus"
 )
 
 // Values returns a url.Values strcture based on the query search parameters.
@@ -63,9 +64,15 @@ func (q *Query) Values() *url.Values {
 	if q.BugIDsType != "" {
 		values.Add("bug_id_type", q.BugIDsType)
 	}
+	for _, val := range q.Alias {
+		values.Add("alias", val)
+	}
 	for _, val := range q.TargetRelease {
 		values.Add("target_release", val)
 	}
+	for _, val := range q.Verified {
+		values.Add("cf_verified", string(val))
+	}
 	for i, adv := range q.Advanced {
 		fieldNum := i + 1
 		values.Set(fmt.Sprintf("f%d", fieldNum), adv.Field)
@@ -78,12 +85,19 @@ func (q *Query) Values() *url.Values {
 		}
 	}
 	if len(q.IncludeFields) != 0 {
-		fields := strings.Join(q.IncludeFields, ",")
+		includeFields := q.IncludeFields
+		if q.UserDetails {
+			includeFields = withUserDetailFields(includeFields)
+		}
+		fields := strings.Join(includeFields, ",")
 		values.Set("include_fields", fields)
 	}
+	if len(q.ExcludeFields) != 0 {
+		values.Set("exclude_fields", strings.Join(q.ExcludeFields, ","))
+	}
 	v, err := url.ParseQuery(q.Raw)
 	if err != nil {
-		logrus.Warnf("Unable to parse Raw search query: %q: %v", q.Raw, err)
+		defaultLogger.Warn(fmt.Sprintf("Unable to parse Raw search query: %q: %v", q.Raw, err))
 	}
 	for k, vals := range v {
 		for _, val := range vals {
@@ -96,39 +110,139 @@ func (q *Query) Values() *url.Values {
 // Search retrieves all Bugs matching the search
 // https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#search-bugs
 func (c *client) Search(query Query) ([]*Bug, error) {
-	limit := 0
-	offset := 0
 	outbugs := []*Bug{}
-
-	logger := c.logger.WithFields(logrus.Fields{methodField: "Search"})
-	url := fmt.Sprintf("%s/rest/bug", c.endpoint)
-
-	values := query.Values()
-	for {
-		values.Set("limit", fmt.Sprint(limit))
-		values.Set("offset", fmt.Sprint(offset))
-		bugs, err := c.getBugs(url, values, logger)
+	logger := c.logger.WithFields(LogFields{methodField: "Search"})
+	err := c.searchPages(query.Values(), logger, func(raw []byte) (int, error) {
+		bugs, err := c.decodeSearchPage(raw, query)
 		if err != nil {
-			return nil, err
-		}
-		if len(bugs) == 0 {
-			break
+			return 0, err
 		}
 		outbugs = append(outbugs, bugs...)
+		return len(bugs), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return outbugs, nil
+}
 
-		// If we do a query and get back N bugs we assume that N was the maximum number of bugs we can get
-		// If the server can send us 1,000 bugs and we get back only 12, we're going to assume that 12 was
-		// the server limit. And we are going to do a second query with limit = 12, offset = 12. That second
-		// query will return 0 bugs and we will break.
-		// That wasted second query wouldn't be needed if we could tell how many total bugs existed or if we
-		// knew the server limit. Since we don't have either, best we can do it guess and test.
-		if limit == 0 {
-			limit = len(bugs)
+// decodeSearchPage decodes the bugs in a search response
+func (c *client) decodeSearchPage(raw []byte, query Query) ([]*Bug, error) {
+	bugs, err := c.decodeBugs(raw)
+	if err != nil {
+		return nil, err
+	}
+	if query.UserDetails {
+		for _, bug := range bugs {
+			NormalizeUserDetails(bug)
 		}
-		if len(bugs) < limit {
-			break
+	}
+	return bugs, nil
+}
+
+// SearchInto retrieves all bugs matching the search and decodes them directly
+// into dest, which must be a pointer to a slice of structs or of pointers to
+// structs. The bugs are decoded using the json tags of the struct, so it is
+// best combined with the IncludeFields of the query to only fetch the fields
+// the struct holds.
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#search-bugs
+func (c *client) SearchInto(query Query, dest interface{}) error {
+	slice, err := searchDestination(dest)
+	if err != nil {
+		return err
+	}
+	logger := c.logger.WithFields(LogFields{methodField: "SearchInto"})
+	return c.searchPages(query.Values(), logger, func(raw []byte) (int, error) {
+		return appendDecodedBugs(raw, slice, c.unmarshal)
+	})
+}
+
+// searchDestination validates that dest is a pointer to a slice and returns the slice
+func searchDestination(dest interface{}) (reflect.Value, error) {
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Slice {
+		return reflect.Value{}, fmt.Errorf("search destination must be a non-nil pointer to a slice, not %T", dest)
+	}
+	return value.Elem(), nil
+}
+
+// appendDecodedBugs decodes the bugs in a search response with unmarshal and
+// appends them to the slice
+func appendDecodedBugs(raw []byte, slice reflect.Value, unmarshal func([]byte, interface{}) error) (int, error) {
+	var parsedResponse struct {
+		Bugs json.RawMessage `json:"bugs,omitempty"`
+	}
+	if err := json.Unmarshal(raw, &parsedResponse); err != nil {
+		return 0, fmt.Errorf("could not unmarshal response body: %v", err)
+	}
+	page := reflect.New(slice.Type())
+	if len(parsedResponse.Bugs) == 0 {
+		return 0, nil
+	}
+	// the bugs are decoded on their own, so strict decoding checks their type
+	if err := unmarshal(parsedResponse.Bugs, page.Interface()); err != nil {
+		return 0, fmt.Errorf("could not unmarshal response body: %v", err)
+	}
+	slice.Set(reflect.AppendSlice(slice, page.Elem()))
+	return page.Elem().Len(), nil
+}
+
+// searchPages runs the search page by page, passing the raw response for every
+// page to handle, which must return the number of bugs on the page.
+func (c *client) searchPages(values *url.Values, logger *logEntry, handle func(raw []byte) (int, error)) error {
+	var pages pager
+	for !pages.done {
+		raw, err := c.searchPage(values, pages.limit, pages.offset, logger)
+		if err != nil {
+			return err
+		}
+		count, err := handle(raw)
+		if err != nil {
+			return err
 		}
-		offset += limit
+		pages.advance(count)
 	}
-	return outbugs, nil
+	return nil
+}
+
+// searchPage retrieves the raw response for a single page of the search
+func (c *client) searchPage(values *url.Values, limit, offset int, logger *logEntry) ([]byte, error) {
+	values.Set("limit", fmt.Sprint(limit))
+	values.Set("offset", fmt.Sprint(offset))
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/rest/bug", c.endpoint), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.URL.RawQuery = values.Encode()
+	return c.request(req, logger)
+}
+
+// pager tracks the limit and offset of the next page of a search
+type pager struct {
+	limit  int
+	offset int
+	done   bool
+}
+
+// advance moves the pager past a page which held count bugs
+func (p *pager) advance(count int) {
+	if count == 0 {
+		p.done = true
+		return
+	}
+
+	// If we do a query and get back N bugs we assume that N was the maximum number of bugs we can get
+	// If the server can send us 1,000 bugs and we get back only 12, we're going to assume that 12 was
+	// the server limit. And we are going to do a second query with limit = 12, offset = 12. That second
+	// query will return 0 bugs and we will break.
+	// That wasted second query wouldn't be needed if we could tell how many total bugs existed or if we
+	// knew the server limit. Since we don't have either, best we can do it guess and test.
+	if p.limit == 0 {
+		p.limit = count
+	}
+	if count < p.limit {
+		p.done = true
+		return
+	}
+	p.offset += p.limit
 }
@@ -0,0 +1,168 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits the client to qps requests per second on average,
+// allowing bursts of up to burst requests.
+func WithRateLimit(qps float64, burst int) Option {
+	return func(c *client) {
+		limiter := c.ensureLimiter()
+		limiter.qps = qps
+		limiter.burst = float64(burst)
+		limiter.tokens = float64(burst)
+	}
+}
+
+// WarmUp configures a warm-up period after the client is created, during
+// which requests are throttled so a cold start does not flood the server.
+type WarmUp struct {
+	// Duration is how long the warm-up period lasts.
+	Duration time.Duration
+	// InitialQPS is the rate of requests per second allowed when the client
+	// is created, which increases linearly to FinalQPS over the warm-up period.
+	// Rates below one request every ten seconds, including zero, are raised
+	// to it.
+	InitialQPS float64
+	// FinalQPS is the rate of requests per second allowed at the end of the
+	// warm-up period. Afterwards, only the limit set WithRateLimit applies.
+	FinalQPS float64
+	// Done, if set, is closed when the warm-up period is over.
+	Done chan struct{}
+}
+
+// WithWarmUp throttles the requests of the client during a warm-up period.
+// No bursts are allowed while the client is warming up. The completion of the
+// warm-up is reported by closing the Done channel and on the
+// 'bugzilla_client_warming_up' gauge of clients created WithMetrics.
+func WithWarmUp(warmUp WarmUp) Option {
+	return func(c *client) {
+		c.ensureLimiter().warmUp = &warmUp
+	}
+}
+
+// startWarmUp starts the warm-up period, if any, once all options are applied,
+// so it uses the clock and metrics of the client regardless of the option order.
+func (c *client) startWarmUp() {
+	if c.limiter == nil || c.limiter.warmUp == nil {
+		return
+	}
+	warmUp := c.limiter.warmUp
+	c.limiter.warmUpStart = c.now()
+	c.metrics.addWarmingUp(1)
+	clock := c.timeSource()
+	end := c.limiter.warmUpStart.Add(warmUp.Duration)
+	go func() {
+		for remaining := end.Sub(clock.Now()); remaining > 0; remaining = end.Sub(clock.Now()) {
+			clock.Sleep(remaining)
+		}
+		c.metrics.addWarmingUp(-1)
+		if warmUp.Done != nil {
+			close(warmUp.Done)
+		}
+	}()
+}
+
+// minWarmUpQPS is the lowest rate of requests per second while the client is
+// warming up, so a warm-up starting at zero is throttled too
+const minWarmUpQPS = 0.1
+
+func (c *client) ensureLimiter() *rateLimiter {
+	if c.limiter == nil {
+		c.limiter = &rateLimiter{last: c.now()}
+	}
+	return c.limiter
+}
+
+// rateLimiter is a token bucket which optionally ramps up its rate during a
+// warm-up period. A zero qps means no limit outside of the warm-up period.
+type rateLimiter struct {
+	lock   sync.Mutex
+	qps    float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	warmUp      *WarmUp
+	warmUpStart time.Time
+
+	// priorities, if set, make requests take turns by priority
+	priorities map[string]Priority
+	// waiting counts the requests waiting for their turn by priority
+	waiting map[Priority]int
+}
+
+// rate returns the current rate and burst at the given time
+func (l *rateLimiter) rate(now time.Time) (float64, float64) {
+	if l.warmUp != nil {
+		elapsed := now.Sub(l.warmUpStart)
+		if elapsed < l.warmUp.Duration {
+			progress := float64(elapsed) / float64(l.warmUp.Duration)
+			qps := l.warmUp.InitialQPS + (l.warmUp.FinalQPS-l.warmUp.InitialQPS)*progress
+			if qps < minWarmUpQPS {
+				qps = minWarmUpQPS
+			}
+			return qps, 1
+		}
+	}
+	return l.qps, l.burst
+}
+
+// reserve takes a token from the bucket and returns how long the caller has to
+// wait before the request may be sent.
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	qps, burst := l.rate(now)
+	if qps <= 0 {
+		l.last = now
+		return 0
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	l.tokens += now.Sub(l.last).Seconds() * qps
+	if l.tokens > burst {
+		l.tokens = burst
+	}
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / qps * float64(time.Second))
+}
+
+// wait blocks on the clock until the next request may be sent
+func (l *rateLimiter) wait(clock Clock) {
+	if delay := l.reserve(clock.Now()); delay > 0 {
+		clock.Sleep(delay)
+	}
+}
+
+// PrioritizeRecentlyChanged sorts the bugs so the most recently changed bugs
+// come first, which is the order in which a cold cache is best warmed up.
+func PrioritizeRecentlyChanged(bugs []*Bug) {
+	sort.SliceStable(bugs, func(i, j int) bool {
+		return bugs[i].LastChangeTime.After(bugs[j].LastChangeTime.Time)
+	})
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	start := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+	limiter := &rateLimiter{qps: 10, burst: 2, tokens: 2, last: start}
+	var delays []time.Duration
+	for i := 0; i < 4; i++ {
+		delays = append(delays, limiter.reserve(start))
+	}
+	if expected := []time.Duration{0, 0, 100 * time.Millisecond, 200 * time.Millisecond}; !reflect.DeepEqual(delays, expected) {
+		t.Errorf("expected delays %v, got %v", expected, delays)
+	}
+	// after a second the bucket is full again
+	if delay := limiter.reserve(start.Add(time.Second)); delay != 0 {
+		t.Errorf("expected no delay after the bucket refilled, got %v", delay)
+	}
+}
+
+func TestRateLimiterWarmUp(t *testing.T) {
+	start := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+	limiter := &rateLimiter{
+		last:        start,
+		warmUp:      &WarmUp{Duration: 10 * time.Second, InitialQPS: 1, FinalQPS: 11},
+		warmUpStart: start,
+	}
+	if qps, burst := limiter.rate(start); qps != 1 || burst != 1 {
+		t.Errorf("expected 1 qps without burst at the start, got %v/%v", qps, burst)
+	}
+	if qps, _ := limiter.rate(start.Add(5 * time.Second)); qps != 6 {
+		t.Errorf("expected 6 qps half way through, got %v", qps)
+	}
+	if qps, _ := limiter.rate(start.Add(10 * time.Second)); qps != 0 {
+		t.Errorf("expected no limit after the warm-up, got %v", qps)
+	}
+
+	limiter.warmUp.InitialQPS = 0
+	if qps, _ := limiter.rate(start); qps != minWarmUpQPS {
+		t.Errorf("expected the minimal rate at the start of a warm-up from zero, got %v", qps)
+	}
+	if delay := limiter.reserve(start); delay != 10*time.Second {
+		t.Errorf("expected a warm-up from zero to be throttled, got a delay of %v", delay)
+	}
+
+	done := make(chan struct{})
+	NewClient(func() []byte { return nil }, "", WithWarmUp(WarmUp{Duration: time.Millisecond, InitialQPS: 1, FinalQPS: 10, Done: done}))
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("expected the warm-up to complete")
+	}
+}
+
+// blockingClock is a clock on which sleeps only end when the test advances it
+type blockingClock struct {
+	lock   sync.Mutex
+	now    time.Time
+	sleeps chan time.Duration
+	wakeUp chan struct{}
+}
+
+func (c *blockingClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+func (c *blockingClock) Sleep(d time.Duration) {
+	c.sleeps <- d
+	<-c.wakeUp
+}
+
+func (c *blockingClock) advance(d time.Duration) {
+	c.lock.Lock()
+	c.now = c.now.Add(d)
+	c.lock.Unlock()
+	c.wakeUp <- struct{}{}
+}
+
+func TestWarmUpEndsOnTheClock(t *testing.T) {
+	clock := &blockingClock{now: time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC), sleeps: make(chan time.Duration), wakeUp: make(chan struct{})}
+	done := make(chan struct{})
+	NewClient(func() []byte { return nil }, "", WithWarmUp(WarmUp{Duration: time.Hour, InitialQPS: 1, FinalQPS: 10, Done: done}), WithClock(clock))
+
+	if sleep := <-clock.sleeps; sleep != time.Hour {
+		t.Errorf("expected to wait for the warm-up on the clock, got %v", sleep)
+	}
+	clock.advance(30 * time.Minute)
+	if sleep := <-clock.sleeps; sleep != 30*time.Minute {
+		t.Errorf("expected to wait for the rest of the warm-up on the clock, got %v", sleep)
+	}
+	select {
+	case <-done:
+		t.Error("expected the warm-up to continue until the clock passed its end")
+	default:
+	}
+	clock.advance(30 * time.Minute)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("expected the warm-up to complete")
+	}
+}
+
+func TestPrioritizeRecentlyChanged(t *testing.T) {
+	bugs := []*Bug{
+		{ID: 1, LastChangeTime: mustParseTimestamp("2019-05-17T15:13:13Z")},
+		{ID: 2},
+		{ID: 3, LastChangeTime: mustParseTimestamp("2020-01-01T00:00:00Z")},
+		{ID: 4, LastChangeTime: mustParseTimestamp("2019-06-01T00:00:00Z")},
+	}
+	PrioritizeRecentlyChanged(bugs)
+	var ids []int
+	for _, bug := range bugs {
+		ids = append(ids, bug.ID)
+	}
+	if expected := []int{3, 4, 1, 2}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected order %v, got %v", expected, ids)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// NewReadOnlyClient wraps the client so that all calls which change bugs
+// fail with a ReadOnlyError without being sent, for jobs like reports which
+// must not change anything. Calls which only read are passed through.
+func NewReadOnlyClient(c Client) Client {
+	return &readOnlyClient{Client: c}
+}
+
+type readOnlyClient struct {
+	Client
+}
+
+// ReadOnlyError is returned for a call which would change bugs through a
+// client returned by NewReadOnlyClient
+type ReadOnlyError struct {
+	// Method is the client method which was rejected.
+	Method string
+}
+
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("%s is not allowed with a read-only client", e.Method)
+}
+
+// IsReadOnly returns true if the error was returned because a call which
+// would change bugs was made with a read-only client
+func IsReadOnly(err error) bool {
+	var target *ReadOnlyError
+	return errors.As(err, &target)
+}
+
+func (c *readOnlyClient) UpdateCommentTags(commentID int, add, remove []string) ([]string, error) {
+	return nil, &ReadOnlyError{Method: "UpdateCommentTags"}
+}
+
+func (c *readOnlyClient) UpdateBug(id int, update BugUpdate) error {
+	return &ReadOnlyError{Method: "UpdateBug"}
+}
+
+func (c *readOnlyClient) UpdateBugs(ids []int, update BugUpdate) error {
+	return &ReadOnlyError{Method: "UpdateBugs"}
+}
+
+func (c *readOnlyClient) UpdateBugByAlias(alias string, update BugUpdate) error {
+	return &ReadOnlyError{Method: "UpdateBugByAlias"}
+}
+
+func (c *readOnlyClient) SetFlag(id int, name, status string) error {
+	return &ReadOnlyError{Method: "SetFlag"}
+}
+
+func (c *readOnlyClient) ClearFlag(id int, name string) error {
+	return &ReadOnlyError{Method: "ClearFlag"}
+}
+
+func (c *readOnlyClient) AddKeywords(id int, keywords ...string) error {
+	return &ReadOnlyError{Method: "AddKeywords"}
+}
+
+func (c *readOnlyClient) RemoveKeywords(id int, keywords ...string) error {
+	return &ReadOnlyError{Method: "RemoveKeywords"}
+}
+
+func (c *readOnlyClient) UpdateSeeAlso(id int, add, remove []string) error {
+	return &ReadOnlyError{Method: "UpdateSeeAlso"}
+}
+
+func (c *readOnlyClient) MarkAsDuplicate(id, dupeOf int) error {
+	return &ReadOnlyError{Method: "MarkAsDuplicate"}
+}
+
+func (c *readOnlyClient) EnsureBugState(id int, desired DesiredBugState) (*BugUpdate, error) {
+	return nil, &ReadOnlyError{Method: "EnsureBugState"}
+}
+
+func (c *readOnlyClient) CreateBug(bug BugCreate) (int, error) {
+	return 0, &ReadOnlyError{Method: "CreateBug"}
+}
+
+func (c *readOnlyClient) CreateAttachment(id int, attachment AttachmentCreate) (int, error) {
+	return 0, &ReadOnlyError{Method: "CreateAttachment"}
+}
+
+func (c *readOnlyClient) CloneBug(bug *Bug, mutations ...CloneOption) (int, error) {
+	return 0, &ReadOnlyError{Method: "CloneBug"}
+}
+
+func (c *readOnlyClient) AddPullRequestAsExternalBug(id int, org, repo string, num int) (bool, error) {
+	return false, &ReadOnlyError{Method: "AddPullRequestAsExternalBug"}
+}
+
+func (c *readOnlyClient) AddJiraIssueAsExternalBug(id int, project string, num int) (bool, error) {
+	return false, &ReadOnlyError{Method: "AddJiraIssueAsExternalBug"}
+}
+
+func (c *readOnlyClient) UpdateExternalBugStatus(bugID int, identifier ExternalBugIdentifier, status string) error {
+	return &ReadOnlyError{Method: "UpdateExternalBugStatus"}
+}
+
+// Do only sends requests which do not change anything, like GET requests
+func (c *readOnlyClient) Do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	if method != http.MethodGet && method != http.MethodHead && method != http.MethodOptions {
+		return &ReadOnlyError{Method: "Do"}
+	}
+	return c.Client.Do(ctx, method, path, body, out)
+}
+
+// WithCGIClient keeps the client returned by the wrapped client read-only
+func (c *readOnlyClient) WithCGIClient(user, password string) Client {
+	return NewReadOnlyClient(c.Client.WithCGIClient(user, password))
+}
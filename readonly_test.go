@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestReadOnlyClient(t *testing.T) {
+	fake := &Fake{Bugs: map[int]Bug{1: {ID: 1, Status: "NEW", Keywords: []string{"Triaged"}}}}
+	original := fake.Bugs[1]
+	c := NewReadOnlyClient(fake)
+
+	if bug, err := c.GetBug(1); err != nil || bug.Status != "NEW" {
+		t.Errorf("expected reads to be passed through, got %v and %v", bug, err)
+	}
+
+	mutations := map[string]func() error{
+		"UpdateCommentTags": func() error { _, err := c.UpdateCommentTags(1, []string{"tag"}, nil); return err },
+		"UpdateBug":         func() error { return c.UpdateBug(1, BugUpdate{Status: "POST"}) },
+		"UpdateBugs":        func() error { return c.UpdateBugs([]int{1}, BugUpdate{Status: "POST"}) },
+		"UpdateBugByAlias":  func() error { return c.UpdateBugByAlias("alias", BugUpdate{Status: "POST"}) },
+		"SetFlag":           func() error { return c.SetFlag(1, "needinfo", FlagRequested) },
+		"ClearFlag":         func() error { return c.ClearFlag(1, "needinfo") },
+		"AddKeywords":       func() error { return c.AddKeywords(1, "Regression") },
+		"RemoveKeywords":    func() error { return c.RemoveKeywords(1, "Triaged") },
+		"UpdateSeeAlso":     func() error { return c.UpdateSeeAlso(1, []string{"https://github.com/org/repo/pull/1"}, nil) },
+		"MarkAsDuplicate":   func() error { return c.MarkAsDuplicate(1, 2) },
+		"EnsureBugState": func() error {
+			_, err := c.EnsureBugState(1, DesiredBugState{Status: "POST"})
+			return err
+		},
+		"CreateBug": func() error { _, err := c.CreateBug(BugCreate{Summary: "new"}); return err },
+		"CloneBug":  func() error { _, err := c.CloneBug(&original); return err },
+		"AddPullRequestAsExternalBug": func() error {
+			_, err := c.AddPullRequestAsExternalBug(1, "org", "repo", 1)
+			return err
+		},
+		"AddJiraIssueAsExternalBug": func() error {
+			_, err := c.AddJiraIssueAsExternalBug(1, "OCPBUGS", 1)
+			return err
+		},
+		"CreateAttachment": func() error {
+			_, err := c.CreateAttachment(1, AttachmentCreate{FileName: "log.txt", Data: []byte("log")})
+			return err
+		},
+		"Do": func() error {
+			return c.Do(context.Background(), http.MethodPost, "/rest/bug/1/attachment", map[string]string{"summary": "logs"}, nil)
+		},
+		"UpdateExternalBugStatus": func() error {
+			return c.UpdateExternalBugStatus(1, ExternalBugIdentifier{Type: "https://github.com/", ID: "org/repo/pull/1"}, "MERGED")
+		},
+	}
+	for method, mutate := range mutations {
+		err := mutate()
+		if !IsReadOnly(err) || !IsReadOnly(fmt.Errorf("wrapped: %w", err)) {
+			t.Errorf("%s: expected a read-only error, got %v", method, err)
+		} else if err.(*ReadOnlyError).Method != method {
+			t.Errorf("%s: got error for the wrong method: %v", method, err)
+		}
+	}
+	if !reflect.DeepEqual(fake.Bugs, map[int]Bug{1: original}) || len(fake.ExternalBugs) != 0 {
+		t.Errorf("expected no bugs to be changed, got %v", fake.Bugs)
+	}
+}
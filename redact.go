@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// redacted replaces credentials in errors, logs and requests handed to hooks
+const redacted = "xxxxxxxx"
+
+// credentialParams matches credentials in query parameters, in URLs on their
+// own or embedded in error messages
+var credentialParams = regexp.MustCompile(`(^|[?&])(api_key|password|token)=[^&"'\s]*`)
+
+// credentialFields matches credentials in JSON payloads, like the token in the
+// response to a login
+var credentialFields = regexp.MustCompile(`("(?:api_key|password|token)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// obfuscatedURL redacts the credentials in the query parameters of a URL, or
+// of URLs in a text
+func obfuscatedURL(url string) string {
+	return credentialParams.ReplaceAllString(url, `${1}${2}=`+redacted)
+}
+
+// credentials returns the secrets which the client may send
+func (c *client) credentials() []string {
+	var secrets []string
+	if apiKey := c.getAPIKey(); len(apiKey) > 0 {
+		secrets = append(secrets, string(apiKey))
+	}
+	if c.session != nil {
+		if token := c.session.currentToken(); token != "" {
+			secrets = append(secrets, token)
+		}
+		if c.session.password != "" {
+			secrets = append(secrets, c.session.password)
+		}
+	}
+	if c.basicAuth != nil {
+		if password := c.basicAuth.getPassword(); len(password) > 0 {
+			secrets = append(secrets, string(password))
+		}
+	}
+	return secrets
+}
+
+// redact replaces the credentials in the text, both the given secrets and
+// anything which looks like a credential in query parameters or JSON
+func redact(text string, secrets []string) string {
+	text = obfuscatedURL(text)
+	text = credentialFields.ReplaceAllString(text, `${1}"`+redacted+`"`)
+	for _, secret := range secrets {
+		text = strings.Replace(text, secret, redacted, -1)
+		if escaped := url.QueryEscape(secret); escaped != secret {
+			text = strings.Replace(text, escaped, redacted, -1)
+		}
+	}
+	return text
+}
+
+// redactError removes the credentials from the message of an error built
+// from what the transport or the server returned, which may echo them
+func (c *client) redactError(err *RequestError) *RequestError {
+	err.Message = redact(err.Message, c.credentials())
+	return err
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestObfuscatedURL(t *testing.T) {
+	var testCases = []struct {
+		url      string
+		expected string
+	}{
+		{
+			url:      "https://bugzilla.example.com/rest/login?login=user&password=secret",
+			expected: "https://bugzilla.example.com/rest/login?login=user&password=xxxxxxxx",
+		},
+		{
+			url:      "https://bugzilla.example.com/rest/bug/1?token=1-abcdef&include_fields=id",
+			expected: "https://bugzilla.example.com/rest/bug/1?token=xxxxxxxx&include_fields=id",
+		},
+		{
+			url:      "https://bugzilla.example.com/rest/bug/1?api_key=secret&include_fields=id",
+			expected: "https://bugzilla.example.com/rest/bug/1?api_key=xxxxxxxx&include_fields=id",
+		},
+		{
+			url:      "https://bugzilla.example.com/rest/bug/1?include_fields=id&api_key=secret",
+			expected: "https://bugzilla.example.com/rest/bug/1?include_fields=id&api_key=xxxxxxxx",
+		},
+		{
+			url:      `Get "https://bugzilla.example.com/rest/bug/1?api_key=secret": dial tcp: connection refused`,
+			expected: `Get "https://bugzilla.example.com/rest/bug/1?api_key=xxxxxxxx": dial tcp: connection refused`,
+		},
+		{
+			url:      "https://bugzilla.example.com/rest/bug/1?my_api_key=value",
+			expected: "https://bugzilla.example.com/rest/bug/1?my_api_key=value",
+		},
+	}
+	for _, testCase := range testCases {
+		if actual := obfuscatedURL(testCase.url); actual != testCase.expected {
+			t.Errorf("expected %q, got %q", testCase.expected, actual)
+		}
+	}
+}
+
+func TestCredentialsDoNotLeak(t *testing.T) {
+	const apiKey, password = "api/key+secret", "pass word"
+	for _, authMethod := range []string{"", AuthBearer, AuthQuery, AuthXBugzillaAPIKey, AuthToken} {
+		t.Run(authMethod, func(t *testing.T) {
+			testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/rest/login":
+					fmt.Fprint(w, `{"id":1,"token":"1-sessiontoken"}`)
+				case "/jsonrpc.cgi":
+					fmt.Fprintf(w, `{"error":{"code":100,"message":"bad request %s"},"id":"identifier"}`, r.URL.RawQuery)
+				default:
+					// echo everything which was sent, like careless servers do in errors
+					w.WriteHeader(http.StatusBadRequest)
+					fmt.Fprintf(w, `{"error":true,"code":100,"message":"bad request %s %v"}`, r.URL.RawQuery, r.Header)
+				}
+			}))
+			var out bytes.Buffer
+			c := clientForUrl(testServer.URL).(*client)
+			WithLogger(NewLogger(log.New(&out, "", 0), true))(c)
+			c.apiKey = newAPIKeySupplier(func() []byte { return []byte(apiKey) })
+			WithLogin("user", password)(c)
+			WithPayloadLogging()(c)
+			if err := c.SetAuthMethod(authMethod); err != nil {
+				t.Fatalf("expected no error setting auth method, got %v", err)
+			}
+
+			var errs []error
+			_, err := c.GetBug(1)
+			errs = append(errs, err)
+			_, err = c.AddPullRequestAsExternalBug(1, "org", "repo", 1)
+			errs = append(errs, err)
+			testServer.Close()
+			_, err = c.GetBug(1)
+			errs = append(errs, err)
+
+			secrets := []string{apiKey, "api%2Fkey%2Bsecret", password, "pass+word", "1-sessiontoken"}
+			for i, err := range errs {
+				if err == nil {
+					t.Fatalf("expected call %d to fail, it did not", i)
+				}
+				for _, secret := range secrets {
+					if strings.Contains(err.Error(), secret) {
+						t.Errorf("expected the error of call %d to not contain %q, got %q", i, secret, err.Error())
+					}
+				}
+			}
+			for _, secret := range secrets {
+				if strings.Contains(out.String(), secret) {
+					t.Errorf("expected the logs to not contain %q, got %q", secret, out.String())
+				}
+			}
+		})
+	}
+}
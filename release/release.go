@@ -0,0 +1,251 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package release parses and compares the target releases of bugs, like
+// "4.12.0" or "4.12.z", and matches them against semver-style constraints
+// like ">=4.10, <4.13", for automation deciding where fixes are backported.
+package release
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/eparis/bugzilla"
+)
+
+// Unset is the target release of a bug which is not targeted yet
+const Unset = "---"
+
+// Version is a parsed target release. A target release of the z-stream of a
+// minor release, like "4.12.z", stands for its releases after the minor
+// release, so it is newer than every patch release of it.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+	// ZStream is set for target releases like "4.12.z", Patch is 0 for them.
+	ZStream bool
+	// parts is the number of parts the version was parsed from, to print it
+	// the same way
+	parts int
+}
+
+// Parse parses a target release like "4", "4.12", "4.12.3" or "4.12.z". A
+// leading "v" is ignored.
+func Parse(value string) (Version, error) {
+	fields := strings.Split(strings.TrimPrefix(strings.TrimSpace(value), "v"), ".")
+	if len(fields) > 3 {
+		return Version{}, fmt.Errorf("target release %q has more than three parts", value)
+	}
+	var numbers [3]int
+	version := Version{parts: len(fields)}
+	for i, field := range fields {
+		if i == 2 && field == "z" {
+			version.ZStream = true
+			continue
+		}
+		number, err := strconv.Atoi(field)
+		if err != nil || number < 0 {
+			return Version{}, fmt.Errorf("target release %q is not of the form X.Y.Z or X.Y.z", value)
+		}
+		numbers[i] = number
+	}
+	version.Major, version.Minor, version.Patch = numbers[0], numbers[1], numbers[2]
+	return version, nil
+}
+
+// MustParse parses the target release like Parse and panics if it is invalid
+func MustParse(value string) Version {
+	version, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+	return version
+}
+
+func (v Version) String() string {
+	switch {
+	case v.ZStream:
+		return fmt.Sprintf("%d.%d.z", v.Major, v.Minor)
+	case v.parts == 1:
+		return strconv.Itoa(v.Major)
+	case v.parts == 2:
+		return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	default:
+		return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	}
+}
+
+// Compare returns -1, 0 or 1 if the version is older than, the same as or
+// newer than the other. Missing parts count as 0, so "4.12" is the same as
+// "4.12.0".
+func (v Version) Compare(other Version) int {
+	for _, pair := range [][2]int{{v.Major, other.Major}, {v.Minor, other.Minor}} {
+		if pair[0] != pair[1] {
+			return sign(pair[0] - pair[1])
+		}
+	}
+	switch {
+	case v.ZStream && other.ZStream:
+		return 0
+	case v.ZStream:
+		return 1
+	case other.ZStream:
+		return -1
+	default:
+		return sign(v.Patch - other.Patch)
+	}
+}
+
+func sign(value int) int {
+	switch {
+	case value < 0:
+		return -1
+	case value > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Less returns true if the version is older than the other
+func (v Version) Less(other Version) bool {
+	return v.Compare(other) < 0
+}
+
+// Sort sorts the versions from the oldest to the newest
+func Sort(versions []Version) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].Less(versions[j])
+	})
+}
+
+// TargetReleases returns the parsed target releases of the bug, leaving out
+// empty and unset ones
+func TargetReleases(bug *bugzilla.Bug) ([]Version, error) {
+	var versions []Version
+	for _, targetRelease := range bug.TargetRelease {
+		if targetRelease == "" || targetRelease == Unset {
+			continue
+		}
+		version, err := Parse(targetRelease)
+		if err != nil {
+			return nil, fmt.Errorf("bug %d: %v", bug.ID, err)
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+// Newest returns the newest of the target releases of the bug, or false if
+// the bug is not targeted at any release
+func Newest(bug *bugzilla.Bug) (Version, bool, error) {
+	versions, err := TargetReleases(bug)
+	if err != nil || len(versions) == 0 {
+		return Version{}, false, err
+	}
+	Sort(versions)
+	return versions[len(versions)-1], true, nil
+}
+
+// Oldest returns the oldest of the target releases of the bug, or false if
+// the bug is not targeted at any release
+func Oldest(bug *bugzilla.Bug) (Version, bool, error) {
+	versions, err := TargetReleases(bug)
+	if err != nil || len(versions) == 0 {
+		return Version{}, false, err
+	}
+	Sort(versions)
+	return versions[0], true, nil
+}
+
+// Constraint is a set of conditions on versions, all of which must hold
+type Constraint struct {
+	conditions []condition
+	raw        string
+}
+
+type condition struct {
+	op      string
+	version Version
+}
+
+// ParseConstraint parses comma separated conditions, each an operator followed
+// by a version, like ">=4.10, <4.13". The operators are =, !=, >, >=, < and
+// <=, as well as ~ for the releases of the same minor release, like "~4.12"
+// for "4.12.0" to "4.12.z", and ^ for the releases of the same major release.
+// A version without an operator must match exactly.
+func ParseConstraint(value string) (Constraint, error) {
+	constraint := Constraint{raw: value}
+	for _, raw := range strings.Split(value, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			return Constraint{}, fmt.Errorf("constraint %q has an empty condition", value)
+		}
+		op := "="
+		for _, candidate := range []string{">=", "<=", "!=", ">", "<", "=", "~", "^"} {
+			if strings.HasPrefix(raw, candidate) {
+				op = candidate
+				raw = strings.TrimSpace(strings.TrimPrefix(raw, candidate))
+				break
+			}
+		}
+		version, err := Parse(raw)
+		if err != nil {
+			return Constraint{}, fmt.Errorf("constraint %q: %v", value, err)
+		}
+		constraint.conditions = append(constraint.conditions, condition{op: op, version: version})
+	}
+	return constraint, nil
+}
+
+// Matches returns true if the version meets all conditions of the constraint
+func (c Constraint) Matches(version Version) bool {
+	for _, condition := range c.conditions {
+		if !condition.matches(version) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c condition) matches(version Version) bool {
+	switch c.op {
+	case "=":
+		return version.Compare(c.version) == 0
+	case "!=":
+		return version.Compare(c.version) != 0
+	case ">":
+		return version.Compare(c.version) > 0
+	case ">=":
+		return version.Compare(c.version) >= 0
+	case "<":
+		return version.Compare(c.version) < 0
+	case "<=":
+		return version.Compare(c.version) <= 0
+	case "~":
+		return version.Major == c.version.Major && version.Minor == c.version.Minor && version.Compare(c.version) >= 0
+	case "^":
+		return version.Major == c.version.Major && version.Compare(c.version) >= 0
+	}
+	return false
+}
+
+func (c Constraint) String() string {
+	return c.raw
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/eparis/bugzilla"
+)
+
+func TestParse(t *testing.T) {
+	var testCases = []struct {
+		value       string
+		expected    Version
+		expectedErr bool
+	}{
+		{value: "4", expected: Version{Major: 4, parts: 1}},
+		{value: "4.12", expected: Version{Major: 4, Minor: 12, parts: 2}},
+		{value: "v4.12.3", expected: Version{Major: 4, Minor: 12, Patch: 3, parts: 3}},
+		{value: "4.12.z", expected: Version{Major: 4, Minor: 12, ZStream: true, parts: 3}},
+		{value: "4.z", expectedErr: true},
+		{value: "4.12.3.1", expectedErr: true},
+		{value: "---", expectedErr: true},
+	}
+	for _, testCase := range testCases {
+		actual, err := Parse(testCase.value)
+		if testCase.expectedErr != (err != nil) {
+			t.Errorf("%q: expected error %v, got %v", testCase.value, testCase.expectedErr, err)
+			continue
+		}
+		if actual != testCase.expected {
+			t.Errorf("%q: expected %#v, got %#v", testCase.value, testCase.expected, actual)
+		}
+		if err == nil && actual.String() != testCase.value && "v"+actual.String() != testCase.value {
+			t.Errorf("%q: printed as %q", testCase.value, actual.String())
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	ordered := []string{"4.9.0", "4.10", "4.10.1", "4.10.z", "4.12.0", "5"}
+	for i := range ordered {
+		for j := range ordered {
+			if actual, expected := MustParse(ordered[i]).Compare(MustParse(ordered[j])), sign(i-j); actual != expected {
+				t.Errorf("comparing %s to %s: expected %d, got %d", ordered[i], ordered[j], expected, actual)
+			}
+		}
+	}
+	if MustParse("4.12").Compare(MustParse("4.12.0")) != 0 {
+		t.Error("expected missing parts to count as 0")
+	}
+}
+
+func TestNewestAndOldest(t *testing.T) {
+	bug := &bugzilla.Bug{ID: 1, TargetRelease: []string{"4.12.z", "---", "4.10.0", "4.11.z"}}
+	newest, ok, err := Newest(bug)
+	if err != nil || !ok || newest.String() != "4.12.z" {
+		t.Errorf("expected the newest target release to be 4.12.z, got %s, %v and %v", newest, ok, err)
+	}
+	oldest, ok, err := Oldest(bug)
+	if err != nil || !ok || oldest.String() != "4.10.0" {
+		t.Errorf("expected the oldest target release to be 4.10.0, got %s, %v and %v", oldest, ok, err)
+	}
+	if _, ok, err := Newest(&bugzilla.Bug{TargetRelease: []string{Unset}}); ok || err != nil {
+		t.Errorf("expected no target release for an untargeted bug, got %v and %v", ok, err)
+	}
+	if _, _, err := Oldest(&bugzilla.Bug{TargetRelease: []string{"next"}}); err == nil {
+		t.Error("expected an error for an invalid target release")
+	}
+}
+
+func TestConstraint(t *testing.T) {
+	var testCases = []struct {
+		constraint string
+		matching   []string
+		other      []string
+	}{
+		{
+			constraint: ">=4.10, <4.13",
+			matching:   []string{"4.10.0", "4.10.z", "4.12.z"},
+			other:      []string{"4.9.z", "4.13.0"},
+		},
+		{
+			constraint: "~4.12",
+			matching:   []string{"4.12.0", "4.12.5", "4.12.z"},
+			other:      []string{"4.11.z", "4.13.0"},
+		},
+		{
+			constraint: "^4.10",
+			matching:   []string{"4.10.0", "4.14.z"},
+			other:      []string{"4.9.z", "5.0.0"},
+		},
+		{
+			constraint: "4.12.z",
+			matching:   []string{"4.12.z"},
+			other:      []string{"4.12.0"},
+		},
+		{
+			constraint: "!=4.11.z",
+			matching:   []string{"4.11.0"},
+			other:      []string{"4.11.z"},
+		},
+	}
+	for _, testCase := range testCases {
+		constraint, err := ParseConstraint(testCase.constraint)
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", testCase.constraint, err)
+			continue
+		}
+		var matching, other []string
+		for _, value := range append(append([]string{}, testCase.matching...), testCase.other...) {
+			if constraint.Matches(MustParse(value)) {
+				matching = append(matching, value)
+			} else {
+				other = append(other, value)
+			}
+		}
+		if !reflect.DeepEqual(matching, testCase.matching) || !reflect.DeepEqual(other, testCase.other) {
+			t.Errorf("%s: expected %v to match and %v not, got %v and %v", testCase.constraint, testCase.matching, testCase.other, matching, other)
+		}
+	}
+	for _, invalid := range []string{"", ">=4.10,", ">=next"} {
+		if _, err := ParseConstraint(invalid); err == nil {
+			t.Errorf("%q: expected an error, got none", invalid)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package relnotes renders release notes from the bugs fixed in a release,
+// grouped by component and using the doc text of the bugs where there is one.
+package relnotes
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/eparis/bugzilla"
+	"github.com/eparis/bugzilla/openshift"
+)
+
+// DocTypeNoDocUpdate is the doc type of bugs which need no release note
+const DocTypeNoDocUpdate = "No Doc Update"
+
+// Query returns the query for the bugs of a product which were shipped in an
+// erratum for the target release
+func Query(product, targetRelease string) bugzilla.Query {
+	return bugzilla.Query{
+		Product:       []string{product},
+		Status:        []string{"CLOSED"},
+		TargetRelease: []string{targetRelease},
+		Advanced: []bugzilla.AdvancedQuery{
+			{Field: "resolution", Op: "equals", Value: "ERRATA"},
+		},
+	}
+}
+
+// Note is the release note of one bug
+type Note struct {
+	ID      int    `json:"id"`
+	Summary string `json:"summary"`
+	// Component is the first component of the bug
+	Component string `json:"-"`
+	// DocType is the type of the note, like "Bug Fix" or "Known Issue"
+	DocType string `json:"cf_doc_type"`
+	// DocText is the text of the note, if one was written
+	DocText string `json:"cf_release_notes"`
+}
+
+// Text returns the doc text of the note or the summary of the bug if no doc
+// text was written
+func (n Note) Text() string {
+	if text := strings.TrimSpace(n.DocText); text != "" {
+		return text
+	}
+	return n.Summary
+}
+
+// searchResult is a bug as searched for, before it is turned into a Note
+type searchResult struct {
+	Note
+	Components []string        `json:"component"`
+	Flags      []bugzilla.Flag `json:"flags"`
+}
+
+// Collect retrieves the notes of the bugs matching the query, leaving out the
+// bugs which need no release note: those of the DocTypeNoDocUpdate type or
+// for which the openshift.FlagRequiresDocText flag is denied. The notes are sorted by component and ID.
+func Collect(c bugzilla.Client, query bugzilla.Query) ([]Note, error) {
+	query.IncludeFields = []string{"id", "summary", "component", "flags", "cf_doc_type", "cf_release_notes"}
+	var results []searchResult
+	if err := c.SearchInto(query, &results); err != nil {
+		return nil, fmt.Errorf("could not search for bugs: %v", err)
+	}
+	var notes []Note
+	for _, result := range results {
+		if result.DocType == DocTypeNoDocUpdate {
+			continue
+		}
+		if status, _ := bugzilla.FlagStatus(&bugzilla.Bug{Flags: result.Flags}, openshift.FlagRequiresDocText); status == bugzilla.FlagDenied {
+			continue
+		}
+		note := result.Note
+		if len(result.Components) > 0 {
+			note.Component = result.Components[0]
+		}
+		notes = append(notes, note)
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		if notes[i].Component != notes[j].Component {
+			return notes[i].Component < notes[j].Component
+		}
+		return notes[i].ID < notes[j].ID
+	})
+	return notes, nil
+}
+
+// Format is the markup the release notes are rendered in
+type Format string
+
+const (
+	Markdown Format = "markdown"
+	AsciiDoc Format = "asciidoc"
+)
+
+// Group holds the notes of one component
+type Group struct {
+	Component string
+	Notes     []Note
+}
+
+// Groups groups the notes by component, in the order of the notes
+func Groups(notes []Note) []Group {
+	var groups []Group
+	for _, note := range notes {
+		if len(groups) == 0 || groups[len(groups)-1].Component != note.Component {
+			groups = append(groups, Group{Component: note.Component})
+		}
+		groups[len(groups)-1].Notes = append(groups[len(groups)-1].Notes, note)
+	}
+	return groups
+}
+
+var funcs = template.FuncMap{
+	// indent indents the lines after the first one, so multi-line doc text
+	// stays within its list item
+	"indent": func(spaces int, text string) string {
+		lines := strings.Split(text, "\n")
+		for i := 1; i < len(lines); i++ {
+			if lines[i] != "" {
+				lines[i] = strings.Repeat(" ", spaces) + lines[i]
+			}
+		}
+		return strings.Join(lines, "\n")
+	},
+	// continued joins the paragraphs of multi-line doc text to their list item
+	"continued": func(text string) string {
+		return strings.Replace(text, "\n\n", "\n+\n", -1)
+	},
+}
+
+var templates = map[Format]*template.Template{
+	Markdown: template.Must(template.New(string(Markdown)).Funcs(funcs).Parse(`# {{ .Title }}
+{{ range .Groups }}
+## {{ .Component }}
+{{ range .Notes }}
+* {{ if .DocType }}**{{ .DocType }}:** {{ end }}{{ indent 2 .Text }} ([{{ .ID }}]({{ $.Endpoint }}/show_bug.cgi?id={{ .ID }}))
+{{- end }}
+{{ end -}}
+`)),
+	AsciiDoc: template.Must(template.New(string(AsciiDoc)).Funcs(funcs).Parse(`= {{ .Title }}
+{{ range .Groups }}
+== {{ .Component }}
+{{ range .Notes }}
+* {{ if .DocType }}*{{ .DocType }}:* {{ end }}{{ continued .Text }} ({{ $.Endpoint }}/show_bug.cgi?id={{ .ID }}[{{ .ID }}])
+{{- end }}
+{{ end -}}
+`)),
+}
+
+// Render writes the release notes in the format, linking every note to its
+// bug on the given Bugzilla endpoint
+func Render(w io.Writer, format Format, title, endpoint string, notes []Note) error {
+	tmpl, ok := templates[format]
+	if !ok {
+		return fmt.Errorf("unknown format %q, expected %s or %s", format, Markdown, AsciiDoc)
+	}
+	return tmpl.Execute(w, struct {
+		Title    string
+		Endpoint string
+		Groups   []Group
+	}{
+		Title:    title,
+		Endpoint: strings.TrimSuffix(endpoint, "/"),
+		Groups:   Groups(notes),
+	})
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package relnotes
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/eparis/bugzilla"
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+// searchClient answers searches with fixed bugs
+type searchClient struct {
+	bugzilla.Client
+	bugs string
+}
+
+func (c *searchClient) SearchInto(query bugzilla.Query, dest interface{}) error {
+	return json.Unmarshal([]byte(c.bugs), dest)
+}
+
+func TestCollect(t *testing.T) {
+	client := &searchClient{bugs: `[
+		{"id":3,"summary":"router drops connections","component":["Routing"],"cf_doc_type":"Bug Fix","cf_release_notes":"The router no longer drops connections."},
+		{"id":1,"summary":"typo in help","component":["oc"],"cf_doc_type":"No Doc Update"},
+		{"id":2,"summary":"installer hangs","component":["Installer","Networking"],"cf_doc_type":"Known Issue"},
+		{"id":4,"summary":"internal refactoring","component":["Installer"],"flags":[{"name":"requires_doc_text","status":"-"}]},
+		{"id":5,"summary":"router leaks memory","component":["Routing"],"cf_doc_type":"Bug Fix","flags":[{"name":"requires_doc_text","status":"+"}]}
+	]`}
+	notes, err := Collect(client, Query("OpenShift Container Platform", "4.6.0"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []Note{
+		{ID: 2, Summary: "installer hangs", Component: "Installer", DocType: "Known Issue"},
+		{ID: 3, Summary: "router drops connections", Component: "Routing", DocType: "Bug Fix", DocText: "The router no longer drops connections."},
+		{ID: 5, Summary: "router leaks memory", Component: "Routing", DocType: "Bug Fix"},
+	}
+	if !reflect.DeepEqual(notes, expected) {
+		t.Errorf("got incorrect notes: %v", diff.ObjectReflectDiff(expected, notes))
+	}
+}
+
+func TestRender(t *testing.T) {
+	notes := []Note{
+		{ID: 2, Summary: "installer hangs", Component: "Installer", DocType: "Known Issue"},
+		{ID: 3, Summary: "router drops connections", Component: "Routing", DocType: "Bug Fix", DocText: "The router no longer drops connections.\n\nUpgrade to fix it."},
+		{ID: 5, Summary: "router leaks memory", Component: "Routing"},
+	}
+	var testCases = []struct {
+		format      Format
+		expected    string
+		expectedErr bool
+	}{
+		{
+			format: Markdown,
+			expected: `# OpenShift 4.6.0
+
+## Installer
+
+* **Known Issue:** installer hangs ([2](https://bugzilla.example.com/show_bug.cgi?id=2))
+
+## Routing
+
+* **Bug Fix:** The router no longer drops connections.
+
+  Upgrade to fix it. ([3](https://bugzilla.example.com/show_bug.cgi?id=3))
+* router leaks memory ([5](https://bugzilla.example.com/show_bug.cgi?id=5))
+`,
+		},
+		{
+			format: AsciiDoc,
+			expected: `= OpenShift 4.6.0
+
+== Installer
+
+* *Known Issue:* installer hangs (https://bugzilla.example.com/show_bug.cgi?id=2[2])
+
+== Routing
+
+* *Bug Fix:* The router no longer drops connections.
++
+Upgrade to fix it. (https://bugzilla.example.com/show_bug.cgi?id=3[3])
+* router leaks memory (https://bugzilla.example.com/show_bug.cgi?id=5[5])
+`,
+		},
+		{
+			format:      "html",
+			expectedErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		var out bytes.Buffer
+		err := Render(&out, testCase.format, "OpenShift 4.6.0", "https://bugzilla.example.com/", notes)
+		if testCase.expectedErr != (err != nil) {
+			t.Errorf("%s: expected error %v, got %v", testCase.format, testCase.expectedErr, err)
+		}
+		if actual := out.String(); actual != testCase.expected {
+			t.Errorf("%s: got incorrect release notes: %v", testCase.format, diff.StringDiff(testCase.expected, actual))
+		}
+	}
+}
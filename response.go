@@ -0,0 +1,164 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// WithMaxResponseSize makes requests fail with a ResponseTooLargeError when
+// the body of the response is larger than the limit in bytes, instead of
+// reading it into memory, to survive proxies which answer with huge pages.
+func WithMaxResponseSize(limit int64) Option {
+	return func(c *client) {
+		c.maxResponseSize = limit
+	}
+}
+
+// ResponseTooLargeError is returned when the body of a response is larger
+// than the limit set with WithMaxResponseSize
+type ResponseTooLargeError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Limit is the maximum size of a response body in bytes.
+	Limit int64
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("response with code %d has a body larger than %d bytes", e.StatusCode, e.Limit)
+}
+
+// IsResponseTooLarge returns true if the response was dropped because its
+// body was larger than the limit set with WithMaxResponseSize
+func IsResponseTooLarge(err error) bool {
+	var target *ResponseTooLargeError
+	return errors.As(err, &target)
+}
+
+// WithStrictDecoding makes the client fail to decode responses holding
+// fields which the types of this package, like Bug or Comment, have no field
+// for, to catch when the schema of the server drifts from the client. Custom
+// fields of bugs are still collected in Bug.CustomFields.
+func WithStrictDecoding() Option {
+	return func(c *client) {
+		c.strictDecoding = true
+	}
+}
+
+// UnknownFieldsError is returned by clients created WithStrictDecoding for a
+// response holding fields which are not known to the client
+type UnknownFieldsError struct {
+	// Fields are the paths of the unknown fields in the response, like
+	// "bugs[0].new_field", in lexical order.
+	Fields []string
+}
+
+func (e *UnknownFieldsError) Error() string {
+	return fmt.Sprintf("unknown fields in response: %s", strings.Join(e.Fields, ", "))
+}
+
+// unmarshal decodes the response body into the value, checking it for
+// unknown fields if the client decodes strictly
+func (c *client) unmarshal(raw []byte, v interface{}) error {
+	if err := json.Unmarshal(raw, v); err != nil {
+		return err
+	}
+	if !c.strictDecoding {
+		return nil
+	}
+	if unknown := unknownFields(raw, reflect.TypeOf(v), ""); len(unknown) > 0 {
+		sort.Strings(unknown)
+		return &UnknownFieldsError{Fields: unknown}
+	}
+	return nil
+}
+
+var (
+	packagePath = reflect.TypeOf(Bug{}).PkgPath()
+	bugType     = reflect.TypeOf(Bug{})
+)
+
+// unknownFields returns the paths of the fields in the raw JSON which the
+// type has no field for. Only the named structs of this package are checked,
+// the anonymous structs used to unwrap responses only hold what is needed.
+func unknownFields(raw json.RawMessage, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var unknown []string
+	switch t.Kind() {
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(raw, &fields) != nil {
+			return nil
+		}
+		types := jsonFieldTypes(t)
+		checked := t.Name() != "" && t.PkgPath() == packagePath
+		for name, value := range fields {
+			fieldPath := name
+			if path != "" {
+				fieldPath = path + "." + name
+			}
+			fieldType, known := types[name]
+			if !known {
+				if checked && !(t == bugType && strings.HasPrefix(name, customFieldPrefix)) {
+					unknown = append(unknown, fieldPath)
+				}
+				continue
+			}
+			unknown = append(unknown, unknownFields(value, fieldType, fieldPath)...)
+		}
+	case reflect.Slice, reflect.Array:
+		var values []json.RawMessage
+		if json.Unmarshal(raw, &values) != nil {
+			return nil
+		}
+		for i, value := range values {
+			unknown = append(unknown, unknownFields(value, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case reflect.Map:
+		var values map[string]json.RawMessage
+		if json.Unmarshal(raw, &values) != nil {
+			return nil
+		}
+		for key, value := range values {
+			unknown = append(unknown, unknownFields(value, t.Elem(), fmt.Sprintf("%s[%s]", path, key))...)
+		}
+	}
+	return unknown
+}
+
+// jsonFieldTypes returns the types of the fields of the struct type by their
+// JSON names
+func jsonFieldTypes(t reflect.Type) map[string]reflect.Type {
+	types := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = t.Field(i).Name
+		}
+		if name != "-" {
+			types[name] = t.Field(i).Type
+		}
+	}
+	return types
+}
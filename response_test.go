@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestMaxResponseSize(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		status      int
+		body        string
+		chunked     bool
+		expectedErr bool
+	}{
+		{
+			name:   "response within the limit is read",
+			status: http.StatusOK,
+			body:   string(bugData),
+		},
+		{
+			name:        "response with a large content length is dropped",
+			status:      http.StatusOK,
+			body:        strings.Repeat("x", 2*len(bugData)),
+			expectedErr: true,
+		},
+		{
+			name:        "large chunked response is dropped",
+			status:      http.StatusOK,
+			body:        strings.Repeat("x", 2*len(bugData)),
+			chunked:     true,
+			expectedErr: true,
+		},
+		{
+			name:        "large error page is dropped",
+			status:      http.StatusBadGateway,
+			body:        strings.Repeat("x", 2*len(bugData)),
+			chunked:     true,
+			expectedErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(testCase.status)
+				if testCase.chunked {
+					w.(http.Flusher).Flush()
+				}
+				w.Write([]byte(testCase.body))
+			}))
+			defer testServer.Close()
+			c := clientForUrl(testServer.URL).(*client)
+			WithMaxResponseSize(int64(len(bugData)))(c)
+			_, err := c.GetBug(1705243)
+			if testCase.expectedErr != IsResponseTooLarge(err) || testCase.expectedErr != IsResponseTooLarge(fmt.Errorf("wrapped: %w", err)) {
+				t.Errorf("expected response too large %v, got %v", testCase.expectedErr, err)
+			}
+			if !testCase.expectedErr && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestStrictDecoding(t *testing.T) {
+	body := string(bugData)
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/bug/1705243/comment" {
+			w.Write([]byte(`{"bugs":{"1705243":{"comments":[{"id":1,"text":"description","reactions":[]}]}},"comments":{}}`))
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer testServer.Close()
+	c := clientForUrl(testServer.URL).(*client)
+	WithStrictDecoding()(c)
+
+	if _, err := c.GetBug(1705243); err != nil {
+		t.Errorf("expected no error decoding a known bug, got %v", err)
+	}
+	body = strings.Replace(body, `"id":1705243`, `"id":1705243,"cf_doc_type":"Bug Fix","new_field":true`, 1)
+	_, err := c.GetBug(1705243)
+	if !strings.Contains(err.Error(), "bugs[0].new_field") || strings.Contains(err.Error(), "cf_doc_type") {
+		t.Errorf("expected an error for the unknown field, got %v", err)
+	}
+	var bugs []Bug
+	if err := c.SearchInto(Query{Product: []string{"OpenShift"}}, &bugs); err == nil || !strings.Contains(err.Error(), "new_field") {
+		t.Errorf("expected an error for the unknown field in the search results, got %v", err)
+	}
+	_, err = c.GetBugComments(1705243)
+	if !strings.Contains(err.Error(), "bugs[1705243].comments[0].reactions") {
+		t.Errorf("expected an error for the unknown comment field, got %v", err)
+	}
+
+	fields := unknownFields([]byte(`{"products":[{"name":"OCP","owner":"me","components":[{"name":"Networking","team":"SDN"}]}]}`), reflect.TypeOf(&struct {
+		Products []Product `json:"products"`
+	}{}), "")
+	sort.Strings(fields)
+	if expected := []string{"products[0].components[0].team", "products[0].owner"}; !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected unknown fields %v, got %v", expected, fields)
+	}
+}
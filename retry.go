@@ -0,0 +1,153 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WithRetries makes the client retry requests which failed because of a
+// transport error, a server error or rate limiting up to maxRetries times.
+// The delay between attempts starts at backoff and doubles for every retry,
+// unless the server asks for a longer one with the Retry-After header. Once
+// all retries are used up, a *RetryExhaustedError is returned.
+// Only requests which can be sent twice without changing anything twice are
+// retried: GET, HEAD and OPTIONS requests and RPC calls which only read.
+// Writes like creating bugs or adding comments are never retried, since the
+// server may have applied them although the response did not arrive.
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *client) {
+		c.maxRetries = maxRetries
+		c.retryBackoff = backoff
+	}
+}
+
+// RetryExhaustedError is returned when a request still failed after all
+// configured retries were used up.
+type RetryExhaustedError struct {
+	// Attempts is the total number of attempts made, including the first one.
+	Attempts int
+	// LastStatus is the HTTP status code of the last attempt, or -1 if the
+	// last attempt did not get a response.
+	LastStatus int
+	// LastErr is the error returned by the last attempt.
+	LastErr error
+}
+
+func (e RetryExhaustedError) Error() string {
+	return fmt.Sprintf("request failed after %d attempts, last status %d: %v", e.Attempts, e.LastStatus, e.LastErr)
+}
+
+// Unwrap returns the error of the last attempt
+func (e RetryExhaustedError) Unwrap() error {
+	return e.LastErr
+}
+
+// IsRetryExhausted returns true if the error was returned because a request
+// still failed after all retries were used up.
+func IsRetryExhausted(err error) bool {
+	var target *RetryExhaustedError
+	return errors.As(err, &target)
+}
+
+// retrySafeKey marks requests in their context which may be sent again
+// although their method is not idempotent, see markRetrySafe
+type retrySafeKey struct{}
+
+// markRetrySafe marks the request as safe to send again, for requests which
+// only read although they are sent with POST, like RPC calls getting data
+func markRetrySafe(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), retrySafeKey{}, true))
+}
+
+// mayRetry determines if the request can be sent again without changing
+// anything twice
+func mayRetry(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	safe, _ := req.Context().Value(retrySafeKey{}).(bool)
+	return safe
+}
+
+// retryAfter parses the Retry-After header, which holds either a number of
+// seconds or a date, into the delay the server asks for
+func retryAfter(header string, now time.Time) time.Duration {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// isRetryable determines if a failed request may succeed when it is sent again
+func isRetryable(err error) bool {
+	reqError, ok := err.(*RequestError)
+	if !ok {
+		return false
+	}
+	return reqError.StatusCode == -1 || reqError.StatusCode == http.StatusTooManyRequests || reqError.StatusCode >= http.StatusInternalServerError
+}
+
+func (c *client) requestWithRetries(req *http.Request, logger *logEntry) ([]byte, error) {
+	method := logger.Data[methodField].(string)
+	backoff := c.retryBackoff
+	for attempt := 1; ; attempt++ {
+		raw, err := c.guardedRequest(req, logger)
+		if err == nil || !isRetryable(err) || !mayRetry(req) {
+			return raw, err
+		}
+		if c.maxRetries == 0 {
+			return nil, err
+		}
+		if attempt > c.maxRetries {
+			c.metrics.exhaustedRetries(method)
+			return nil, &RetryExhaustedError{Attempts: attempt, LastStatus: err.(*RequestError).StatusCode, LastErr: err}
+		}
+		if req.GetBody != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return nil, fmt.Errorf("could not reset request body for retry: %v", bodyErr)
+			}
+			req.Body = body
+		}
+		delay := backoff
+		if requested := err.(*RequestError).RetryAfter; requested > delay {
+			delay = requested
+		}
+		logger.WithError(err).WithField("attempt", attempt).Debug("Retrying failed request to Bugzilla.")
+		c.metrics.retried(method)
+		c.sleep(delay)
+		backoff *= 2
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestRetries(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		failures         int
+		failureCode      int
+		expectedAttempts int
+		expectedErr      bool
+		expectedExhaust  bool
+		expectedNotFound bool
+	}{
+		{
+			name:             "success without failures",
+			expectedAttempts: 1,
+		},
+		{
+			name:             "transient server errors are retried",
+			failures:         2,
+			failureCode:      http.StatusServiceUnavailable,
+			expectedAttempts: 3,
+		},
+		{
+			name:             "rate limiting is retried",
+			failures:         1,
+			failureCode:      http.StatusTooManyRequests,
+			expectedAttempts: 2,
+		},
+		{
+			name:             "persistent server errors exhaust retries",
+			failures:         5,
+			failureCode:      http.StatusInternalServerError,
+			expectedAttempts: 3,
+			expectedErr:      true,
+			expectedExhaust:  true,
+		},
+		{
+			name:             "client errors are not retried",
+			failures:         5,
+			failureCode:      http.StatusNotFound,
+			expectedAttempts: 1,
+			expectedErr:      true,
+			expectedNotFound: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			attempts := 0
+			testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				if attempts <= testCase.failures {
+					http.Error(w, http.StatusText(testCase.failureCode), testCase.failureCode)
+					return
+				}
+				w.Write(bugData)
+			}))
+			defer testServer.Close()
+			c := clientForUrl(testServer.URL).(*client)
+			WithRetries(2, time.Millisecond)(c)
+
+			_, err := c.GetBug(1705243)
+			if testCase.expectedErr != (err != nil) {
+				t.Errorf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+			}
+			if attempts != testCase.expectedAttempts {
+				t.Errorf("%s: expected %d attempts, got %d", testCase.name, testCase.expectedAttempts, attempts)
+			}
+			if IsRetryExhausted(err) != testCase.expectedExhaust {
+				t.Errorf("%s: expected retry exhaustion %v, got %v", testCase.name, testCase.expectedExhaust, err)
+			}
+			if IsNotFound(err) != testCase.expectedNotFound {
+				t.Errorf("%s: expected not found %v, got %v", testCase.name, testCase.expectedNotFound, err)
+			}
+			if exhausted, ok := err.(*RetryExhaustedError); ok {
+				if exhausted.Attempts != testCase.expectedAttempts || exhausted.LastStatus != testCase.failureCode {
+					t.Errorf("%s: got incorrect exhaustion details: %#v", testCase.name, exhausted)
+				}
+			}
+		})
+	}
+}
+
+func TestRetriesOnlyIdempotentRequests(t *testing.T) {
+	attempts := map[string]int{}
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		var call struct {
+			Method string `json:"method"`
+		}
+		json.Unmarshal(body, &call)
+		attempts[r.Method+" "+r.URL.Path+" "+call.Method]++
+		http.Error(w, "503 Service Unavailable", http.StatusServiceUnavailable)
+	}))
+	defer testServer.Close()
+	c := clientForUrl(testServer.URL).(*client)
+	WithRetries(2, time.Millisecond)(c)
+
+	if _, err := c.CreateBug(BugCreate{Product: "Product", Summary: "broken"}); err == nil || IsRetryExhausted(err) {
+		t.Errorf("expected the creation to fail without retries, got %v", err)
+	}
+	if _, err := c.AddPullRequestAsExternalBug(1, "org", "repo", 1); err == nil || IsRetryExhausted(err) {
+		t.Errorf("expected adding the external bug to fail without retries, got %v", err)
+	}
+	if err := c.UpdateBug(1, BugUpdate{Status: "MODIFIED"}); err == nil || IsRetryExhausted(err) {
+		t.Errorf("expected the update to fail without retries, got %v", err)
+	}
+	if _, err := c.GetExternalTrackerTypes(); !IsRetryExhausted(err) {
+		t.Errorf("expected the read-only RPC call to be retried, got %v", err)
+	}
+	expected := map[string]int{
+		"POST /rest/bug ": 1,
+		"POST /jsonrpc.cgi ExternalBugs.add_external_bug": 1,
+		"PUT /rest/bug/1 ": 1,
+		"POST /jsonrpc.cgi ExternalBugs.get_ext_types": 3,
+	}
+	if !reflect.DeepEqual(attempts, expected) {
+		t.Errorf("got incorrect attempts: %v", diff.ObjectReflectDiff(expected, attempts))
+	}
+}
+
+func TestRetriesHonorRetryAfter(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)}
+	attempts := 0
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		switch attempts {
+		case 1:
+			w.Header().Set("Retry-After", "120")
+			http.Error(w, "429 Too Many Requests", http.StatusTooManyRequests)
+		case 2:
+			w.Header().Set("Retry-After", clock.Now().Add(time.Hour).Format(http.TimeFormat))
+			http.Error(w, "503 Service Unavailable", http.StatusServiceUnavailable)
+		default:
+			w.Write(bugData)
+		}
+	}))
+	defer testServer.Close()
+	c := clientForUrl(testServer.URL).(*client)
+	WithClock(clock)(c)
+	WithRetries(2, time.Second)(c)
+
+	if _, err := c.GetBug(1705243); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if expected := []time.Duration{2 * time.Minute, time.Hour}; !reflect.DeepEqual(clock.sleeps, expected) {
+		t.Errorf("expected to wait %v as asked by the server, got %v", expected, clock.sleeps)
+	}
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package routing decides where bugs filed automatically, e.g. for CI
+// failures, belong. A Table maps GitHub repositories and paths within them
+// to the product, component, sub-component and default assignee of the bugs,
+// and is usually loaded from YAML:
+//
+//	routes:
+//	- org: openshift
+//	  repo: installer
+//	  path_prefix: pkg/asset/installconfig
+//	  product: OpenShift Container Platform
+//	  component: Installer
+//	  sub_component: openshift-installer
+//	  assigned_to: installer-team@example.com
+//	default:
+//	  product: OpenShift Container Platform
+//	  component: Unknown
+package routing
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/eparis/bugzilla"
+	"sigs.k8s.io/yaml"
+)
+
+// Destination is where bugs are filed
+type Destination struct {
+	Product   string `json:"product"`
+	Component string `json:"component"`
+	// SubComponent is the sub-component of the component, for servers which
+	// support sub-components.
+	SubComponent string `json:"sub_component,omitempty"`
+	// AssignedTo is the login name of the default assignee, the default
+	// assignee of the component if empty.
+	AssignedTo string `json:"assigned_to,omitempty"`
+}
+
+// Apply files the new bug at the destination
+func (d Destination) Apply(bug *bugzilla.BugCreate) {
+	bug.Product = d.Product
+	bug.Component = d.Component
+	if d.AssignedTo != "" {
+		bug.AssignedTo = d.AssignedTo
+	}
+	if d.SubComponent != "" {
+		if bug.CustomFields == nil {
+			bug.CustomFields = map[string]interface{}{}
+		}
+		bug.CustomFields["sub_components"] = map[string][]string{d.Component: {d.SubComponent}}
+	}
+}
+
+// Route sends the bugs for a GitHub org, or a repository in it, and
+// optionally for a path within the repository, to a destination
+type Route struct {
+	Org string `json:"org"`
+	// Repo is the name of the repository, the route covers all repositories
+	// of the org if empty.
+	Repo string `json:"repo,omitempty"`
+	// PathPrefix limits the route to a directory or file of the repository,
+	// like "pkg/asset". It matches whole path segments.
+	PathPrefix string `json:"path_prefix,omitempty"`
+	Destination
+}
+
+// matches returns whether the route covers the path in the repository
+func (r *Route) matches(org, repo, path string) bool {
+	if !strings.EqualFold(r.Org, org) || (r.Repo != "" && !strings.EqualFold(r.Repo, repo)) {
+		return false
+	}
+	prefix := strings.Trim(r.PathPrefix, "/")
+	path = strings.Trim(path, "/")
+	return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// specificity orders routes, the routes for a repository before those for
+// the whole org and longer paths before shorter ones
+func (r *Route) specificity() int {
+	specificity := len(strings.Split(strings.Trim(r.PathPrefix, "/"), "/"))
+	if strings.Trim(r.PathPrefix, "/") == "" {
+		specificity = 0
+	}
+	if r.Repo != "" {
+		specificity += 1 << 16
+	}
+	return specificity
+}
+
+// Table maps repositories and paths to destinations
+type Table struct {
+	Routes []Route `json:"routes"`
+	// Default, if set, is the destination of the bugs which no route covers.
+	Default *Destination `json:"default,omitempty"`
+}
+
+// Lookup returns the destination of bugs for the path, which may be empty,
+// in the GitHub repository: that of the most specific route covering it, or
+// the default. It returns false if neither a route nor a default applies.
+func (t *Table) Lookup(org, repo, path string) (Destination, bool) {
+	var match *Route
+	for i := range t.Routes {
+		route := &t.Routes[i]
+		if route.matches(org, repo, path) && (match == nil || route.specificity() > match.specificity()) {
+			match = route
+		}
+	}
+	if match != nil {
+		return match.Destination, true
+	}
+	if t.Default != nil {
+		return *t.Default, true
+	}
+	return Destination{}, false
+}
+
+// Validate checks that every route and the default have a product and a
+// component and that no two routes cover the same paths
+func (t *Table) Validate() error {
+	seen := map[string]bool{}
+	for i, route := range t.Routes {
+		if route.Org == "" {
+			return fmt.Errorf("route %d: org is required", i)
+		}
+		if err := route.Destination.validate(); err != nil {
+			return fmt.Errorf("route %d: %v", i, err)
+		}
+		key := strings.ToLower(fmt.Sprintf("%s/%s:%s", route.Org, route.Repo, strings.Trim(route.PathPrefix, "/")))
+		if seen[key] {
+			return fmt.Errorf("route %d: another route covers %s/%s with path prefix %q", i, route.Org, route.Repo, route.PathPrefix)
+		}
+		seen[key] = true
+	}
+	if t.Default != nil {
+		if err := t.Default.validate(); err != nil {
+			return fmt.Errorf("default: %v", err)
+		}
+	}
+	return nil
+}
+
+func (d *Destination) validate() error {
+	if d.Product == "" || d.Component == "" {
+		return fmt.Errorf("product and component are required")
+	}
+	return nil
+}
+
+// Parse reads a table from YAML, or JSON, and validates it
+func Parse(raw []byte) (*Table, error) {
+	var table Table
+	if err := yaml.UnmarshalStrict(raw, &table); err != nil {
+		return nil, fmt.Errorf("could not parse routing table: %v", err)
+	}
+	if err := table.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid routing table: %v", err)
+	}
+	return &table, nil
+}
+
+// Load reads a table from a YAML file and validates it
+func Load(path string) (*Table, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read routing table: %v", err)
+	}
+	return Parse(raw)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routing
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/eparis/bugzilla"
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+const table = `
+routes:
+- org: openshift
+  product: OpenShift Container Platform
+  component: Unknown
+- org: openshift
+  repo: installer
+  product: OpenShift Container Platform
+  component: Installer
+- org: openshift
+  repo: installer
+  path_prefix: pkg/asset/installconfig/aws
+  product: OpenShift Container Platform
+  component: Installer
+  sub_component: openshift-installer-aws
+  assigned_to: aws@example.com
+default:
+  product: Other
+  component: Triage
+`
+
+func TestLookup(t *testing.T) {
+	routes, err := Parse([]byte(table))
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	var testCases = []struct {
+		name            string
+		org, repo, path string
+		expected        Destination
+		expectedFound   bool
+	}{
+		{
+			name:          "the most specific path wins",
+			org:           "openshift",
+			repo:          "installer",
+			path:          "pkg/asset/installconfig/aws/platform.go",
+			expected:      Destination{Product: "OpenShift Container Platform", Component: "Installer", SubComponent: "openshift-installer-aws", AssignedTo: "aws@example.com"},
+			expectedFound: true,
+		},
+		{
+			name:          "path prefixes match whole segments",
+			org:           "openshift",
+			repo:          "installer",
+			path:          "pkg/asset/installconfig/awsx/platform.go",
+			expected:      Destination{Product: "OpenShift Container Platform", Component: "Installer"},
+			expectedFound: true,
+		},
+		{
+			name:          "repository routes win over org routes",
+			org:           "OpenShift",
+			repo:          "Installer",
+			expected:      Destination{Product: "OpenShift Container Platform", Component: "Installer"},
+			expectedFound: true,
+		},
+		{
+			name:          "org routes cover all repositories",
+			org:           "openshift",
+			repo:          "origin",
+			path:          "test/extended",
+			expected:      Destination{Product: "OpenShift Container Platform", Component: "Unknown"},
+			expectedFound: true,
+		},
+		{
+			name:          "the default covers other orgs",
+			org:           "kubernetes",
+			repo:          "kubernetes",
+			expected:      Destination{Product: "Other", Component: "Triage"},
+			expectedFound: true,
+		},
+	}
+	for _, testCase := range testCases {
+		actual, found := routes.Lookup(testCase.org, testCase.repo, testCase.path)
+		if found != testCase.expectedFound {
+			t.Errorf("%s: expected found %v, got %v", testCase.name, testCase.expectedFound, found)
+		}
+		if !reflect.DeepEqual(actual, testCase.expected) {
+			t.Errorf("%s: got incorrect destination: %v", testCase.name, diff.ObjectReflectDiff(testCase.expected, actual))
+		}
+	}
+
+	routes.Default = nil
+	if _, found := routes.Lookup("kubernetes", "kubernetes", ""); found {
+		t.Error("expected no destination without a default")
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for name, raw := range map[string]string{
+		"missing component": "routes:\n- org: openshift\n  product: OCP\n",
+		"missing org":       "routes:\n- product: OCP\n  component: Installer\n",
+		"duplicate route":   "routes:\n- org: openshift\n  product: OCP\n  component: A\n- org: OpenShift\n  product: OCP\n  component: B\n",
+		"unknown field":     "routes:\n- org: openshift\n  product: OCP\n  component: A\n  owner: someone\n",
+	} {
+		if _, err := Parse([]byte(raw)); err == nil {
+			t.Errorf("%s: expected an error, got none", name)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	bug := bugzilla.BugCreate{Summary: "CI failure", AssignedTo: "someone@example.com"}
+	Destination{Product: "OCP", Component: "Installer", SubComponent: "aws"}.Apply(&bug)
+	expected := bugzilla.BugCreate{
+		Summary:      "CI failure",
+		Product:      "OCP",
+		Component:    "Installer",
+		AssignedTo:   "someone@example.com",
+		CustomFields: map[string]interface{}{"sub_components": map[string][]string{"Installer": {"aws"}}},
+	}
+	if !reflect.DeepEqual(bug, expected) {
+		t.Errorf("got incorrect bug: %v", diff.ObjectReflectDiff(expected, bug))
+	}
+}
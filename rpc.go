@@ -0,0 +1,253 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// RPC protocols used for the calls which are not part of the REST API, like
+// adding external bugs
+const (
+	// RPCJSON10 is JSON-RPC 1.0, which all Bugzilla servers with JSON-RPC
+	// support. It is used by default.
+	RPCJSON10 = "jsonrpc-1.0"
+	// RPCJSON20 is JSON-RPC 2.0, supported since Bugzilla 5.0.
+	RPCJSON20 = "jsonrpc-2.0"
+	// RPCXML is XML-RPC, for servers on which JSON-RPC is disabled.
+	RPCXML = "xmlrpc"
+	// RPCAuto picks JSON-RPC 2.0 or 1.0 depending on the version the server
+	// reports at /rest/version and falls back to XML-RPC if the JSON-RPC
+	// endpoint is not found.
+	RPCAuto = "auto"
+)
+
+// WithRPCProtocol sets the protocol of RPC calls, one of RPCJSON10, RPCJSON20,
+// RPCXML or RPCAuto.
+func WithRPCProtocol(protocol string) Option {
+	return func(c *client) {
+		c.rpc = &rpcNegotiation{configured: protocol}
+	}
+}
+
+// rpcNegotiation holds the configured RPC protocol and, for RPCAuto, the
+// protocol which was negotiated with the server
+type rpcNegotiation struct {
+	configured string
+
+	lock       sync.Mutex
+	negotiated string
+}
+
+// rpcProtocol returns the protocol to use for the next RPC call
+func (c *client) rpcProtocol(logger *logEntry) (string, error) {
+	if c.rpc == nil {
+		return RPCJSON10, nil
+	}
+	switch c.rpc.configured {
+	case RPCJSON10, RPCJSON20, RPCXML:
+		return c.rpc.configured, nil
+	case RPCAuto:
+	default:
+		return "", fmt.Errorf("invalid RPC protocol %q. Valid values are %s, %s, %s or %s", c.rpc.configured, RPCJSON10, RPCJSON20, RPCXML, RPCAuto)
+	}
+	c.rpc.lock.Lock()
+	defer c.rpc.lock.Unlock()
+	if c.rpc.negotiated == "" {
+		c.rpc.negotiated = RPCJSON10
+		version, err := c.serverVersion()
+		if err != nil {
+			logger.WithError(err).Warn("Could not get the Bugzilla version, using JSON-RPC 1.0.")
+		} else if parsed, ok := parseVersion(version); ok && capabilities[capabilityJSONRPC20](parsed) {
+			c.rpc.negotiated = RPCJSON20
+		}
+	}
+	return c.rpc.negotiated, nil
+}
+
+// fallBackToXMLRPC switches a client negotiating its RPC protocol to XML-RPC
+// and returns true if it did
+func (c *client) fallBackToXMLRPC() bool {
+	if c.rpc == nil || c.rpc.configured != RPCAuto {
+		return false
+	}
+	c.rpc.lock.Lock()
+	defer c.rpc.lock.Unlock()
+	if c.rpc.negotiated == RPCXML {
+		return false
+	}
+	c.rpc.negotiated = RPCXML
+	return true
+}
+
+// rpcMethod holds the special cases of a method which is only available
+// over RPC
+type rpcMethod struct {
+	// readOnly is true for methods which do not change anything, so their
+	// calls are retried like GET requests, see WithRetries.
+	readOnly bool
+	// ignoreFault returns true for faults of the method which are not errors.
+	// The call then succeeds, leaving the result unchanged.
+	ignoreFault func(fault *RequestError) bool
+}
+
+// rpcMethods are the RPC methods with special cases. Methods without any
+// can be called without being registered.
+var rpcMethods = map[string]rpcMethod{
+	"ExternalBugs.get_ext_types": {readOnly: true},
+	"ExternalBugs.add_external_bug": {
+		// adding the external bug failed since it is already added
+		ignoreFault: func(fault *RequestError) bool {
+			return fault.Code == 100500 && strings.Contains(fault.Message, `duplicate key value violates unique constraint "ext_bz_bug_map_bug_id_idx"`)
+		},
+	},
+}
+
+// rpcClient calls the methods of the API which are only available over RPC,
+// like those of the ExternalBugs extension, using the protocol negotiated
+// with the server, see WithRPCProtocol.
+type rpcClient struct {
+	client *client
+	logger *logEntry
+	// changed are the bugs the calls change, see changing
+	changed []int
+}
+
+// rpcClient returns an RPC client logging to the logger
+func (c *client) rpcClient(logger *logEntry) *rpcClient {
+	return &rpcClient{client: c, logger: logger}
+}
+
+// changing declares the bugs the calls change, see changesBugs. Calls of
+// methods which do not only read change all bugs unless declared otherwise.
+func (r *rpcClient) changing(ids ...int) *rpcClient {
+	r.changed = ids
+	return r
+}
+
+// Call calls the RPC method with the params and decodes its result into
+// result. Faults returned by the method are returned as a *RequestError
+// holding the code of the fault, unless rpcMethods ignores them.
+func (r *rpcClient) Call(method string, params, result interface{}) error {
+	err := r.call(method, params, result)
+	if fault, ok := asRequestError(err); ok {
+		if special, registered := rpcMethods[method]; registered && special.ignoreFault != nil && special.ignoreFault(fault) {
+			r.logger.WithError(err).Debug("Ignoring RPC fault.")
+			return nil
+		}
+	}
+	return err
+}
+
+// call calls the method in the negotiated protocol
+func (r *rpcClient) call(method string, params, result interface{}) error {
+	protocol, err := r.client.rpcProtocol(r.logger)
+	if err != nil {
+		return err
+	}
+	if protocol == RPCXML {
+		return r.callXMLRPC(method, params, result)
+	}
+	err = r.callJSONRPC(strings.TrimPrefix(protocol, "jsonrpc-"), method, params, result)
+	if IsNotFound(err) && r.client.fallBackToXMLRPC() {
+		r.logger.WithError(err).Info("JSON-RPC endpoint not found, falling back to XML-RPC.")
+		return r.callXMLRPC(method, params, result)
+	}
+	return err
+}
+
+// markReadOnly marks calls of methods which only read as safe to retry, and
+// declares the bugs other calls change
+func (r *rpcClient) markReadOnly(method string, req *http.Request) *http.Request {
+	if rpcMethods[method].readOnly {
+		return markRetrySafe(req)
+	}
+	if r.changed != nil {
+		return changesBugs(req, r.changed...)
+	}
+	return req
+}
+
+// nextID returns the ID of the next JSON-RPC request
+func (r *rpcClient) nextID() string {
+	if r.client.rpcID != nil {
+		return r.client.rpcID()
+	}
+	return strconv.FormatUint(uint64(atomic.AddUint32(&r.client.rpcRequests, 1)), 10)
+}
+
+func (r *rpcClient) callJSONRPC(version, method string, params, result interface{}) error {
+	rpcPayload := struct {
+		Version string `json:"jsonrpc"`
+		Method  string `json:"method"`
+		// Parameters must be specified in JSONRPC 1.0 as a structure in the first
+		// index of this slice, later versions accept that too
+		Parameters []interface{} `json:"params"`
+		ID         string        `json:"id"`
+	}{
+		Version:    version,
+		Method:     method,
+		ID:         r.nextID(),
+		Parameters: []interface{}{params},
+	}
+	body, err := json.Marshal(rpcPayload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSONRPC payload: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/jsonrpc.cgi", r.client.endpoint), bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req = r.markReadOnly(method, req)
+
+	resp, err := r.client.request(req, r.logger)
+	if err != nil {
+		return err
+	}
+	var response struct {
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error,omitempty"`
+		ID     string          `json:"id"`
+		Result json.RawMessage `json:"result,omitempty"`
+	}
+	if err := json.Unmarshal(resp, &response); err != nil {
+		return fmt.Errorf("failed to unmarshal JSONRPC response: %v", err)
+	}
+	if response.Error != nil {
+		return r.client.redactError(&RequestError{StatusCode: http.StatusOK, Code: response.Error.Code, Message: fmt.Sprintf("JSONRPC error %d: %v", response.Error.Code, response.Error.Message)})
+	}
+	if response.ID != rpcPayload.ID {
+		return fmt.Errorf("JSONRPC returned mismatched identifier, expected %s but got %s", rpcPayload.ID, response.ID)
+	}
+	if len(response.Result) == 0 || string(response.Result) == "null" {
+		return nil
+	}
+	if err := r.client.unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("failed to unmarshal JSONRPC result: %v", err)
+	}
+	return nil
+}
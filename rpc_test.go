@@ -0,0 +1,241 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+const (
+	jsonrpcResult = `{"error":null,"id":"identifier","result":{"bugs":[{"changes":{"ext_bz_bug_map.ext_bz_bug_id":{"added":"Github org/repo/pull/1","removed":""}},"id":1}]}}`
+	xmlrpcCall    = `<?xml version="1.0" encoding="UTF-8"?><methodCall><methodName>ExternalBugs.add_external_bug</methodName><params><param><value><struct><member><name>api_key</name><value><string>api-key</string></value></member><member><name>bug_ids</name><value><array><data><value><int>1</int></value></data></array></value></member><member><name>external_bugs</name><value><array><data><value><struct><member><name>ext_bz_bug_id</name><value><string>org/repo/pull/1</string></value></member><member><name>ext_type_url</name><value><string>https://github.com/</string></value></member></struct></value></data></array></value></member></struct></value></param></params></methodCall>`
+	xmlrpcResult  = `<?xml version="1.0" encoding="UTF-8"?>
+<methodResponse><params><param><value><struct>
+<member><name>bugs</name><value><array><data><value><struct>
+<member><name>id</name><value><int>1</int></value></member>
+<member><name>changes</name><value><struct><member><name>ext_bz_bug_map.ext_bz_bug_id</name><value><struct>
+<member><name>added</name><value><string>Github org/repo/pull/1</string></value></member>
+<member><name>removed</name><value></value></member>
+</struct></value></member></struct></value></member>
+</struct></value></data></array></value></member>
+</struct></value></param></params></methodResponse>`
+	xmlrpcFault = `<?xml version="1.0" encoding="UTF-8"?>
+<methodResponse><fault><value><struct>
+<member><name>faultCode</name><value><int>%d</int></value></member>
+<member><name>faultString</name><value><string>%s</string></value></member>
+</struct></value></fault></methodResponse>`
+)
+
+func TestRPCProtocols(t *testing.T) {
+	var testCases = []struct {
+		name            string
+		protocol        string
+		version         string
+		jsonrpcDisabled bool
+		xmlrpcResponse  string
+		expectedCalls   []string
+		expectedChanged bool
+		expectedErr     bool
+		expectedCode    int
+	}{
+		{
+			name:            "JSON-RPC 1.0 by default",
+			expectedCalls:   []string{"jsonrpc 1.0"},
+			expectedChanged: true,
+		},
+		{
+			name:            "JSON-RPC 2.0",
+			protocol:        RPCJSON20,
+			expectedCalls:   []string{"jsonrpc 2.0"},
+			expectedChanged: true,
+		},
+		{
+			name:            "XML-RPC",
+			protocol:        RPCXML,
+			xmlrpcResponse:  xmlrpcResult,
+			expectedCalls:   []string{"xmlrpc"},
+			expectedChanged: true,
+		},
+		{
+			name:           "XML-RPC fault",
+			protocol:       RPCXML,
+			xmlrpcResponse: fmt.Sprintf(xmlrpcFault, 101, "Bug #1 does not exist."),
+			expectedCalls:  []string{"xmlrpc"},
+			expectedErr:    true,
+			expectedCode:   101,
+		},
+		{
+			name:           "XML-RPC fault for a duplicate is no error",
+			protocol:       RPCXML,
+			xmlrpcResponse: fmt.Sprintf(xmlrpcFault, 100500, `duplicate key value violates unique constraint "ext_bz_bug_map_bug_id_idx"`),
+			expectedCalls:  []string{"xmlrpc"},
+		},
+		{
+			name:            "auto picks JSON-RPC 2.0 for Bugzilla 5",
+			protocol:        RPCAuto,
+			version:         "5.0.4.rh83",
+			expectedCalls:   []string{"version", "jsonrpc 2.0"},
+			expectedChanged: true,
+		},
+		{
+			name:            "auto picks JSON-RPC 1.0 for older Bugzilla",
+			protocol:        RPCAuto,
+			version:         "4.4.12",
+			expectedCalls:   []string{"version", "jsonrpc 1.0"},
+			expectedChanged: true,
+		},
+		{
+			name:            "auto picks JSON-RPC 1.0 without a version",
+			protocol:        RPCAuto,
+			expectedCalls:   []string{"version", "jsonrpc 1.0"},
+			expectedChanged: true,
+		},
+		{
+			name:            "auto falls back to XML-RPC",
+			protocol:        RPCAuto,
+			version:         "5.0.4",
+			jsonrpcDisabled: true,
+			xmlrpcResponse:  xmlrpcResult,
+			expectedCalls:   []string{"version", "jsonrpc 2.0", "xmlrpc"},
+			expectedChanged: true,
+		},
+		{
+			name:        "invalid protocol",
+			protocol:    "soap",
+			expectedErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var calls []string
+			testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("failed to read request body: %v", err)
+					http.Error(w, "500 Server Error", http.StatusInternalServerError)
+					return
+				}
+				switch r.URL.Path {
+				case "/rest/version":
+					calls = append(calls, "version")
+					if testCase.version == "" {
+						http.Error(w, "404 Not Found", http.StatusNotFound)
+						return
+					}
+					fmt.Fprintf(w, `{"version":%q}`, testCase.version)
+				case "/jsonrpc.cgi":
+					var payload struct {
+						Version string `json:"jsonrpc"`
+					}
+					if err := json.Unmarshal(body, &payload); err != nil {
+						t.Errorf("malformed JSONRPC payload: %s", string(body))
+					}
+					calls = append(calls, "jsonrpc "+payload.Version)
+					if testCase.jsonrpcDisabled {
+						http.Error(w, "404 Not Found", http.StatusNotFound)
+						return
+					}
+					w.Write([]byte(jsonrpcResult))
+				case "/xmlrpc.cgi":
+					calls = append(calls, "xmlrpc")
+					if r.Header.Get("Content-Type") != "text/xml" {
+						t.Errorf("expected content type text/xml, got %q", r.Header.Get("Content-Type"))
+					}
+					if string(body) != xmlrpcCall {
+						t.Errorf("got incorrect XMLRPC call: %v", diff.StringDiff(xmlrpcCall, string(body)))
+					}
+					w.Write([]byte(testCase.xmlrpcResponse))
+				default:
+					http.Error(w, "404 Not Found", http.StatusNotFound)
+				}
+			}))
+			defer testServer.Close()
+			c := clientForUrl(testServer.URL).(*client)
+			if testCase.protocol != "" {
+				WithRPCProtocol(testCase.protocol)(c)
+			}
+
+			changed, err := c.AddPullRequestAsExternalBug(1, "org", "repo", 1)
+			if testCase.expectedErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", testCase.expectedErr, err)
+			}
+			if reqError, ok := asRequestError(err); testCase.expectedCode != 0 && (!ok || reqError.Code != testCase.expectedCode) {
+				t.Errorf("expected error code %d, got %v", testCase.expectedCode, err)
+			}
+			if changed != testCase.expectedChanged {
+				t.Errorf("expected changed %v, got %v", testCase.expectedChanged, changed)
+			}
+			if !reflect.DeepEqual(calls, testCase.expectedCalls) {
+				t.Errorf("got incorrect calls: %v", diff.ObjectReflectDiff(testCase.expectedCalls, calls))
+			}
+		})
+	}
+}
+
+func TestRPCClientCall(t *testing.T) {
+	var ids []string
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var call struct {
+			Method string `json:"method"`
+			ID     string `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&call); err != nil {
+			t.Errorf("malformed JSONRPC payload: %v", err)
+		}
+		ids = append(ids, call.ID)
+		switch call.Method {
+		case "Example.echo":
+			fmt.Fprintf(w, `{"error":null,"id":%q,"result":{"value":"echo"}}`, call.ID)
+		case "ExternalBugs.add_external_bug":
+			fmt.Fprintf(w, `{"error":{"code":100500,"message":"duplicate key value violates unique constraint \"ext_bz_bug_map_bug_id_idx\""},"id":%q}`, call.ID)
+		default:
+			fmt.Fprintf(w, `{"error":{"code":100500,"message":"failed"},"id":%q}`, call.ID)
+		}
+	}))
+	defer testServer.Close()
+	c := clientForUrl(testServer.URL).(*client)
+	c.rpcID = nil
+	rpc := c.rpcClient(c.logger.WithField(methodField, "Call"))
+
+	var result struct {
+		Value string `json:"value"`
+	}
+	if err := rpc.Call("Example.echo", struct{}{}, &result); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if result.Value != "echo" {
+		t.Errorf("got incorrect result: %v", result)
+	}
+	if err := rpc.Call("ExternalBugs.add_external_bug", struct{}{}, &result); err != nil {
+		t.Errorf("expected the duplicate external bug to be ignored, but got an error: %v", err)
+	}
+	err := rpc.Call("Example.fail", struct{}{}, &result)
+	if reqError, ok := asRequestError(err); !ok || reqError.Code != 100500 {
+		t.Errorf("expected a fault with code 100500, got %v", err)
+	}
+	if expected := []string{"1", "2", "3"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("got incorrect request IDs: %v", diff.ObjectReflectDiff(expected, ids))
+	}
+}
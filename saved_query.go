@@ -119,14 +119,15 @@ func newBugFromBzBug(protoBug bzBug) (bug *Bug, err error) {
 	if err != nil {
 		return nil, err
 	}
-	bug.LastChangeTime = t.String()
+	bug.LastChangeTime = NewTimestamp(t)
 	return bug, nil
 }
 
 // BugList takes a
 // cmdtype=dorem&remaction=run&namedcmd=openshift-group-b-stale&sharer_id=290313
 func (c *client) BugList(queryName, sharerID string) ([]Bug, error) {
-	if c.cgiClient == nil {
+	cgiClient := c.cgiClient()
+	if cgiClient == nil {
 		return nil, fmt.Errorf("BugList() is only supported with CGI client")
 	}
 	u, err := url.Parse(c.endpoint)
@@ -151,7 +152,7 @@ func (c *client) BugList(queryName, sharerID string) ([]Bug, error) {
 	u.RawQuery = v.Encode()
 	referer := u.String()
 
-	res, err := c.cgiClient.authenticated(func() (*http.Response, error) {
+	res, err := cgiClient.authenticated(func() (*http.Response, error) {
 		req, err := newHTTPRequest("GET", queryUrl, nil)
 		if err == nil {
 			req.Header.Set("Upgrade-Insecure-Request", "1")
@@ -166,7 +167,7 @@ func (c *client) BugList(queryName, sharerID string) ([]Bug, error) {
 		}
 		req.Header.Set("Accept", "text/csv")
 
-		res, err := c.cgiClient.httpClient.Do(req)
+		res, err := cgiClient.httpClient.Do(req)
 		if err != nil {
 			if strings.Contains(err.Error(), "use of closed network connection") {
 				return nil, fmt.Errorf("timeout occured while accessing %v", req.URL)
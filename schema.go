@@ -0,0 +1,229 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSchemaTTL is how long the client uses a product schema before it
+// fetches it again, unless configured with WithSchemaTTL.
+const DefaultSchemaTTL = time.Hour
+
+// WithSchemaTTL sets how long the client uses a product schema before it
+// fetches it again. A TTL of zero disables caching.
+func WithSchemaTTL(ttl time.Duration) Option {
+	return func(c *client) {
+		c.schemas = &schemaCache{ttl: ttl}
+	}
+}
+
+// ProductSchema holds the values which are valid for the bugs in a product.
+// Only active values are included. A nil list means the server does not
+// support the field, so any value is accepted for it.
+type ProductSchema struct {
+	// Product is the name of the product.
+	Product string
+	// Components are the components of the product.
+	Components []string
+	// SubComponents are the sub-components of the components which have any,
+	// keyed by component. Not all bugzilla instances support sub-components.
+	SubComponents map[string][]string
+	// Versions are the versions of the product.
+	Versions []string
+	// TargetReleases are the target releases of the product. Not all bugzilla
+	// instances support this field.
+	TargetReleases []string
+}
+
+// Validate checks that the values set by the update are valid for bugs in
+// the product. Updates which move bugs to another product are not checked.
+func (s *ProductSchema) Validate(update BugUpdate) error {
+	if update.Product != "" && update.Product != s.Product {
+		return nil
+	}
+	var invalid []string
+	check := func(field, value string, valid []string) {
+		if value != "" && valid != nil && !contains(valid, value) {
+			invalid = append(invalid, fmt.Sprintf("%s %q", field, value))
+		}
+	}
+	check("component", update.Component, s.Components)
+	check("version", update.Version, s.Versions)
+	check("target release", update.TargetRelease, s.TargetReleases)
+	components := make([]string, 0, len(update.SubComponents))
+	for component := range update.SubComponents {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+	for _, component := range components {
+		check("component", component, s.Components)
+		for _, subComponent := range update.SubComponents[component] {
+			valid := s.SubComponents[component]
+			if s.SubComponents != nil && valid == nil {
+				valid = []string{}
+			}
+			check(fmt.Sprintf("sub-component of %s", component), subComponent, valid)
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid values for product %s: %s", s.Product, strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// newProductSchema builds the schema of the product from its metadata
+func newProductSchema(product *Product, targetReleases []string) *ProductSchema {
+	schema := &ProductSchema{Product: product.Name, TargetReleases: targetReleases}
+	for _, component := range product.Components {
+		if !component.IsActive {
+			continue
+		}
+		schema.Components = append(schema.Components, component.Name)
+		if component.SubComponents == nil {
+			continue
+		}
+		if schema.SubComponents == nil {
+			schema.SubComponents = map[string][]string{}
+		}
+		schema.SubComponents[component.Name] = activeNames(component.SubComponents)
+	}
+	schema.Versions = activeNames(product.Versions)
+	return schema
+}
+
+func activeNames(values []ProductValue) []string {
+	names := []string{}
+	for _, value := range values {
+		if value.IsActive {
+			names = append(names, value.Name)
+		}
+	}
+	return names
+}
+
+// schemaCache holds the product schemas fetched by the client
+type schemaCache struct {
+	lock    sync.Mutex
+	ttl     time.Duration
+	entries map[string]schemaEntry
+}
+
+type schemaEntry struct {
+	schema  *ProductSchema
+	fetched time.Time
+}
+
+func (s *schemaCache) get(product string, now time.Time) *ProductSchema {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	entry, ok := s.entries[product]
+	if !ok || now.Sub(entry.fetched) >= s.ttl {
+		return nil
+	}
+	return entry.schema
+}
+
+func (s *schemaCache) put(schema *ProductSchema, now time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.entries == nil {
+		s.entries = map[string]schemaEntry{}
+	}
+	s.entries[schema.Product] = schemaEntry{schema: schema, fetched: now}
+}
+
+// GetProductSchema retrieves the values which are valid for bugs in the
+// product. The schema is cached, so validating updates against it does not
+// ask the server every time. Callers must not modify the schema.
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/product.html#get-product
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/field.html#fields
+func (c *client) GetProductSchema(product string) (*ProductSchema, error) {
+	if c.schemas != nil {
+		if schema := c.schemas.get(product, c.now()); schema != nil {
+			return schema, nil
+		}
+	}
+	metadata, err := c.GetProduct(product)
+	if err != nil {
+		return nil, err
+	}
+	logger := c.logger.WithFields(LogFields{methodField: "GetProductSchema", "product": product})
+	targetReleases, err := c.getTargetReleases(product, logger)
+	if err != nil {
+		return nil, err
+	}
+	schema := newProductSchema(metadata, targetReleases)
+	if c.schemas != nil {
+		c.schemas.put(schema, c.now())
+	}
+	return schema, nil
+}
+
+// getTargetReleases retrieves the active target releases which are visible
+// in the product, or nil if the server has no target_release field
+func (c *client) getTargetReleases(product string, logger *logEntry) ([]string, error) {
+	fields, err := c.getFields("target_release", logger)
+	if err != nil {
+		if reqError, ok := asRequestError(err); ok && (reqError.StatusCode == http.StatusNotFound || reqError.StatusCode == http.StatusBadRequest) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	releases := []string{}
+	for _, value := range fields[0].Values {
+		if value.IsActive && (len(value.VisibilityValues) == 0 || contains(value.VisibilityValues, product)) {
+			releases = append(releases, value.Name)
+		}
+	}
+	return releases, nil
+}
+
+// GetSubComponentsForComponent retrieves the active sub-components of the
+// component in the product from the cached product schema. It returns nil
+// if the component has no sub-components or the server does not support them.
+func (c *client) GetSubComponentsForComponent(product, component string) ([]string, error) {
+	return subComponentsForComponent(c, product, component)
+}
+
+func subComponentsForComponent(c Client, product, component string) ([]string, error) {
+	schema, err := c.GetProductSchema(product)
+	if err != nil {
+		return nil, err
+	}
+	if !contains(schema.Components, component) {
+		return nil, &RequestError{StatusCode: http.StatusNotFound, Message: fmt.Sprintf("component %q not found in product %q", component, product)}
+	}
+	return schema.SubComponents[component], nil
+}
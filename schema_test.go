@@ -0,0 +1,172 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestGetProductSchema(t *testing.T) {
+	requests := 0
+	fieldStatus := http.StatusOK
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/rest/product":
+			w.Write([]byte(`{"products":[{"id":1,"name":"OpenShift Container Platform","is_active":true,"components":[{"id":2,"name":"Networking","is_active":true,"sub_components":[{"id":5,"name":"ovn-kubernetes","is_active":true},{"id":6,"name":"openshift-sdn","is_active":false}]},{"id":3,"name":"Installer","is_active":true},{"id":4,"name":"Retired","is_active":false}],"versions":[{"id":3,"name":"4.5","is_active":true},{"id":4,"name":"4.1","is_active":false}]}]}`))
+		case "/rest/field/bug/target_release":
+			if fieldStatus != http.StatusOK {
+				w.WriteHeader(fieldStatus)
+				w.Write([]byte(`{"error":true,"code":51,"message":"There is no field named 'target_release'."}`))
+				return
+			}
+			w.Write([]byte(`{"fields":[{"name":"target_release","values":[{"name":"---","is_active":true},{"name":"4.5.0","is_active":true,"visibility_values":["OpenShift Container Platform"]},{"name":"8.3.0","is_active":true,"visibility_values":["Red Hat Enterprise Linux 8"]},{"name":"4.1.0","is_active":false,"visibility_values":["OpenShift Container Platform"]}]}]}`))
+		default:
+			t.Errorf("incorrect path to get a product schema: %s", r.URL.Path)
+			http.Error(w, "400 Bad Request", http.StatusBadRequest)
+		}
+	}))
+	defer testServer.Close()
+	c := clientForUrl(testServer.URL).(*client)
+	WithSchemaTTL(time.Hour)(c)
+
+	expected := &ProductSchema{
+		Product:        "OpenShift Container Platform",
+		Components:     []string{"Networking", "Installer"},
+		SubComponents:  map[string][]string{"Networking": {"ovn-kubernetes"}},
+		Versions:       []string{"4.5"},
+		TargetReleases: []string{"---", "4.5.0"},
+	}
+	for i := 0; i < 2; i++ {
+		schema, err := c.GetProductSchema("OpenShift Container Platform")
+		if err != nil {
+			t.Fatalf("expected no error, but got one: %v", err)
+		}
+		if !reflect.DeepEqual(schema, expected) {
+			t.Errorf("got incorrect schema: %v", diff.ObjectReflectDiff(expected, schema))
+		}
+	}
+	if requests != 2 {
+		t.Errorf("expected the schema to be fetched with 2 requests once, got %d requests", requests)
+	}
+	subComponents, err := c.GetSubComponentsForComponent("OpenShift Container Platform", "Networking")
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if !reflect.DeepEqual(subComponents, []string{"ovn-kubernetes"}) {
+		t.Errorf("got incorrect sub-components: %v", subComponents)
+	}
+	if _, err := c.GetSubComponentsForComponent("OpenShift Container Platform", "Retired"); !IsNotFound(err) {
+		t.Errorf("expected a not found error for an inactive component, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected sub-components to be served from the cache, got %d requests", requests)
+	}
+
+	WithSchemaTTL(0)(c)
+	fieldStatus = http.StatusBadRequest
+	schema, err := c.GetProductSchema("OpenShift Container Platform")
+	if err != nil {
+		t.Fatalf("expected no error without a target_release field, but got one: %v", err)
+	}
+	if schema.TargetReleases != nil {
+		t.Errorf("expected no target releases without a target_release field, got %v", schema.TargetReleases)
+	}
+	if requests != 4 {
+		t.Errorf("expected the expired schema to be fetched again, got %d requests", requests)
+	}
+}
+
+func TestProductSchemaValidate(t *testing.T) {
+	schema := &ProductSchema{
+		Product:        "OCP",
+		Components:     []string{"Networking", "Installer"},
+		SubComponents:  map[string][]string{"Networking": {"ovn-kubernetes"}},
+		Versions:       []string{"4.5"},
+		TargetReleases: []string{"4.5.0"},
+	}
+	var testCases = []struct {
+		name        string
+		schema      *ProductSchema
+		update      BugUpdate
+		expectedErr string
+	}{
+		{
+			name:   "valid values are accepted",
+			schema: schema,
+			update: BugUpdate{Component: "Networking", Version: "4.5", TargetRelease: "4.5.0", SubComponents: map[string][]string{"Networking": {"ovn-kubernetes"}}},
+		},
+		{
+			name:        "invalid values are all reported",
+			schema:      schema,
+			update:      BugUpdate{Component: "Storage", Version: "4.1", TargetRelease: "4.1.0"},
+			expectedErr: `invalid values for product OCP: component "Storage", version "4.1", target release "4.1.0"`,
+		},
+		{
+			name:        "sub-components must belong to the component",
+			schema:      schema,
+			update:      BugUpdate{SubComponents: map[string][]string{"Installer": {"ovn-kubernetes"}, "Networking": {"openshift-sdn"}}},
+			expectedErr: `invalid values for product OCP: sub-component of Installer "ovn-kubernetes", sub-component of Networking "openshift-sdn"`,
+		},
+		{
+			name:   "fields the server does not support are not checked",
+			schema: &ProductSchema{Product: "OCP", Components: []string{"Networking"}, Versions: []string{"4.5"}},
+			update: BugUpdate{TargetRelease: "4.5.0", SubComponents: map[string][]string{"Networking": {"ovn-kubernetes"}}},
+		},
+		{
+			name:   "updates moving bugs to another product are not checked",
+			schema: schema,
+			update: BugUpdate{Product: "RHEL", Component: "kernel"},
+		},
+	}
+	for _, testCase := range testCases {
+		err := testCase.schema.Validate(testCase.update)
+		if testCase.expectedErr == "" && err != nil {
+			t.Errorf("%s: expected no error, got %v", testCase.name, err)
+		}
+		if testCase.expectedErr != "" && (err == nil || err.Error() != testCase.expectedErr) {
+			t.Errorf("%s: expected error %q, got %v", testCase.name, testCase.expectedErr, err)
+		}
+	}
+}
+
+func TestFakeGetProductSchema(t *testing.T) {
+	fake := &Fake{
+		Products: map[string]Product{"OCP": {
+			Name:       "OCP",
+			Components: []ProductComponent{{Name: "Networking", IsActive: true, SubComponents: []ProductValue{{Name: "ovn-kubernetes", IsActive: true}}}},
+		}},
+		TargetReleases: map[string][]string{"OCP": {"4.5.0"}},
+	}
+	schema, err := fake.GetProductSchema("OCP")
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	expected := &ProductSchema{Product: "OCP", Components: []string{"Networking"}, SubComponents: map[string][]string{"Networking": {"ovn-kubernetes"}}, Versions: []string{}, TargetReleases: []string{"4.5.0"}}
+	if !reflect.DeepEqual(schema, expected) {
+		t.Errorf("got incorrect schema: %v", diff.ObjectReflectDiff(expected, schema))
+	}
+	if _, err := fake.GetSubComponentsForComponent("RHEL", "kernel"); !IsNotFound(err) {
+		t.Errorf("expected a not found error for a missing product, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package scopedrift periodically compares two queries, like the current and
+// a proposed query of some automation, and reports the bugs entering and
+// leaving the scope of each, to audit that a change of the query affects
+// exactly the bugs it is expected to.
+package scopedrift
+
+import (
+	"time"
+
+	"github.com/eparis/bugzilla"
+	"github.com/eparis/bugzilla/search"
+	"github.com/sirupsen/logrus"
+)
+
+// ScopeChange holds the bugs which entered and left the scope of a query
+// since it was last evaluated
+type ScopeChange struct {
+	Entered []int
+	Left    []int
+}
+
+// Report is the outcome of one evaluation of the queries
+type Report struct {
+	// Time is when the queries were evaluated.
+	Time time.Time
+	// Diff holds the bugs matched by each of the queries.
+	Diff *search.QueryDiff
+	// First and Second hold the changes of the scope of each query since the
+	// previous evaluation. They are empty for the first evaluation.
+	First  ScopeChange
+	Second ScopeChange
+}
+
+// Monitor evaluates two queries periodically
+type Monitor struct {
+	Client bugzilla.Client
+	First  bugzilla.Query
+	Second bugzilla.Query
+	// Interval is the time between evaluations.
+	Interval time.Duration
+	// Report is called with the report of every evaluation.
+	Report func(Report)
+	// Logger logs evaluations which failed, logrus' standard logger if nil.
+	Logger *logrus.Entry
+
+	first, second []int
+	evaluated     bool
+}
+
+// Evaluate runs both queries and returns how their scopes changed since the
+// previous evaluation
+func (m *Monitor) Evaluate() (Report, error) {
+	first, err := search.QueryIDs(m.Client, m.First)
+	if err != nil {
+		return Report{}, err
+	}
+	second, err := search.QueryIDs(m.Client, m.Second)
+	if err != nil {
+		return Report{}, err
+	}
+	report := Report{Time: time.Now(), Diff: search.DiffIDs(first, second)}
+	if m.evaluated {
+		report.First = change(m.first, first)
+		report.Second = change(m.second, second)
+	}
+	m.first, m.second, m.evaluated = first, second, true
+	return report, nil
+}
+
+// Run evaluates the queries right away and then every interval until stop is
+// closed. Failed evaluations are logged and do not reset the scopes the next
+// evaluation is compared to.
+func (m *Monitor) Run(stop <-chan struct{}) {
+	logger := m.Logger
+	if logger == nil {
+		logger = logrus.NewEntry(logrus.StandardLogger())
+	}
+	ticker := time.NewTicker(m.Interval)
+	defer ticker.Stop()
+	for {
+		if report, err := m.Evaluate(); err != nil {
+			logger.WithError(err).Warn("Could not evaluate the queries.")
+		} else if m.Report != nil {
+			m.Report(report)
+		}
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// change returns the bugs which entered and left the scope, given the sorted
+// IDs of the bugs before and after
+func change(before, after []int) ScopeChange {
+	diff := search.DiffIDs(before, after)
+	return ScopeChange{Entered: diff.OnlySecond, Left: diff.OnlyFirst}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scopedrift
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/eparis/bugzilla"
+	"github.com/eparis/bugzilla/search"
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+// componentClient matches the bugs of the components of a query
+type componentClient struct {
+	bugzilla.Client
+	bugsByComponent map[string][]int
+	err             error
+}
+
+func (c *componentClient) Search(query bugzilla.Query) ([]*bugzilla.Bug, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	var bugs []*bugzilla.Bug
+	for _, component := range query.Component {
+		for _, id := range c.bugsByComponent[component] {
+			bugs = append(bugs, &bugzilla.Bug{ID: id})
+		}
+	}
+	return bugs, nil
+}
+
+func TestEvaluate(t *testing.T) {
+	client := &componentClient{bugsByComponent: map[string][]int{
+		"Networking": {1, 2},
+		"Routing":    {3},
+	}}
+	monitor := &Monitor{
+		Client: client,
+		First:  bugzilla.Query{Component: []string{"Networking"}},
+		Second: bugzilla.Query{Component: []string{"Networking", "Routing"}},
+	}
+
+	report, err := monitor.Evaluate()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := Report{Diff: &search.QueryDiff{OnlySecond: []int{3}, Both: []int{1, 2}}}
+	report.Time = time.Time{}
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("got incorrect first report: %v", diff.ObjectReflectDiff(expected, report))
+	}
+
+	client.err = errors.New("oops")
+	if _, err := monitor.Evaluate(); err == nil {
+		t.Error("expected an error, got none")
+	}
+	client.err = nil
+
+	client.bugsByComponent["Networking"] = []int{2, 4}
+	report, err = monitor.Evaluate()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected = Report{
+		Diff:   &search.QueryDiff{OnlySecond: []int{3}, Both: []int{2, 4}},
+		First:  ScopeChange{Entered: []int{4}, Left: []int{1}},
+		Second: ScopeChange{Entered: []int{4}, Left: []int{1}},
+	}
+	report.Time = time.Time{}
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("got incorrect second report: %v", diff.ObjectReflectDiff(expected, report))
+	}
+}
+
+func TestRun(t *testing.T) {
+	reports := make(chan Report, 10)
+	stop := make(chan struct{})
+	monitor := &Monitor{
+		Client:   &componentClient{bugsByComponent: map[string][]int{"Networking": {1}}},
+		First:    bugzilla.Query{Component: []string{"Networking"}},
+		Second:   bugzilla.Query{Component: []string{"Networking"}},
+		Interval: time.Millisecond,
+		Report: func(report Report) {
+			select {
+			case reports <- report:
+			default:
+			}
+		},
+	}
+	done := make(chan struct{})
+	go func() {
+		monitor.Run(stop)
+		close(done)
+	}()
+	for i := 0; i < 2; i++ {
+		if report := <-reports; !report.Diff.Equal() {
+			t.Errorf("expected the queries to match the same bugs, got %v", report.Diff)
+		}
+	}
+	close(stop)
+	<-done
+}
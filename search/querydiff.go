@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package search
+
+import (
+	"sort"
+
+	"github.com/eparis/bugzilla"
+)
+
+// QueryDiff holds the IDs of the bugs matched by two queries, split by which
+// of the queries matched them. All IDs are sorted.
+type QueryDiff struct {
+	// OnlyFirst are the bugs matched by the first query only.
+	OnlyFirst []int
+	// OnlySecond are the bugs matched by the second query only.
+	OnlySecond []int
+	// Both are the bugs matched by both queries.
+	Both []int
+}
+
+// Equal returns true if both queries matched the same bugs
+func (d QueryDiff) Equal() bool {
+	return len(d.OnlyFirst) == 0 && len(d.OnlySecond) == 0
+}
+
+// CompareQueries runs both queries and returns which bugs each matched, e.g.
+// to check that a change to the query of some automation changes the bugs it
+// acts on exactly as expected. Only the IDs of the bugs are fetched.
+func CompareQueries(c bugzilla.Client, first, second bugzilla.Query) (*QueryDiff, error) {
+	firstIDs, err := QueryIDs(c, first)
+	if err != nil {
+		return nil, err
+	}
+	secondIDs, err := QueryIDs(c, second)
+	if err != nil {
+		return nil, err
+	}
+	return DiffIDs(firstIDs, secondIDs), nil
+}
+
+// QueryIDs returns the sorted IDs of the bugs matching the query
+func QueryIDs(c bugzilla.Client, query bugzilla.Query) ([]int, error) {
+	query.IncludeFields = []string{"id"}
+	query.UserDetails = false
+	bugs, err := c.Search(query)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(bugs))
+	for _, bug := range bugs {
+		ids = append(ids, bug.ID)
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// DiffIDs splits two sets of IDs by which of them holds every ID
+func DiffIDs(first, second []int) *QueryDiff {
+	inFirst := map[int]bool{}
+	for _, id := range first {
+		inFirst[id] = true
+	}
+	inSecond := map[int]bool{}
+	for _, id := range second {
+		inSecond[id] = true
+	}
+	diff := &QueryDiff{}
+	for id := range inFirst {
+		if inSecond[id] {
+			diff.Both = append(diff.Both, id)
+		} else {
+			diff.OnlyFirst = append(diff.OnlyFirst, id)
+		}
+	}
+	for id := range inSecond {
+		if !inFirst[id] {
+			diff.OnlySecond = append(diff.OnlySecond, id)
+		}
+	}
+	sort.Ints(diff.OnlyFirst)
+	sort.Ints(diff.OnlySecond)
+	sort.Ints(diff.Both)
+	return diff
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package search
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eparis/bugzilla"
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestDiffIDs(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		first    []int
+		second   []int
+		expected *QueryDiff
+	}{
+		{
+			name:     "same bugs",
+			first:    []int{2, 1},
+			second:   []int{1, 2},
+			expected: &QueryDiff{Both: []int{1, 2}},
+		},
+		{
+			name:     "disjoint bugs",
+			first:    []int{3, 1},
+			second:   []int{2},
+			expected: &QueryDiff{OnlyFirst: []int{1, 3}, OnlySecond: []int{2}},
+		},
+		{
+			name:     "overlapping bugs",
+			first:    []int{1, 2, 3},
+			second:   []int{2, 3, 4},
+			expected: &QueryDiff{OnlyFirst: []int{1}, OnlySecond: []int{4}, Both: []int{2, 3}},
+		},
+		{
+			name:     "no bugs",
+			expected: &QueryDiff{},
+		},
+	}
+	for _, testCase := range testCases {
+		actual := DiffIDs(testCase.first, testCase.second)
+		if !reflect.DeepEqual(actual, testCase.expected) {
+			t.Errorf("%s: got incorrect diff: %v", testCase.name, diff.ObjectReflectDiff(testCase.expected, actual))
+		}
+		if equal := len(testCase.expected.OnlyFirst) == 0 && len(testCase.expected.OnlySecond) == 0; actual.Equal() != equal {
+			t.Errorf("%s: expected equal %v, got %v", testCase.name, equal, actual.Equal())
+		}
+	}
+}
+
+func TestCompareQueries(t *testing.T) {
+	bugsByComponent := map[string][]int{
+		"Networking": {1, 2},
+		"Routing":    {3},
+	}
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fields := r.URL.Query().Get("include_fields"); fields != "id" {
+			t.Errorf("expected only IDs to be fetched, got %q", fields)
+		}
+		if r.URL.Query().Get("offset") != "0" {
+			w.Write([]byte(`{"bugs":[]}`))
+			return
+		}
+		var bugs []string
+		for _, component := range r.URL.Query()["component"] {
+			for _, id := range bugsByComponent[component] {
+				bugs = append(bugs, fmt.Sprintf(`{"id":%d}`, id))
+			}
+		}
+		fmt.Fprintf(w, `{"bugs":[%s]}`, strings.Join(bugs, ","))
+	}))
+	defer testServer.Close()
+	c := bugzilla.NewClient(nil, testServer.URL, bugzilla.WithHTTPClient(testServer.Client()))
+
+	actual, err := CompareQueries(c, bugzilla.Query{Component: []string{"Networking"}}, bugzilla.Query{Component: []string{"Networking", "Routing"}, IncludeFields: []string{"summary"}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := &QueryDiff{OnlySecond: []int{3}, Both: []int{1, 2}}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got incorrect diff: %v", diff.ObjectReflectDiff(expected, actual))
+	}
+}
@@ -0,0 +1,328 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package search builds bugzilla.Query values with a fluent API which checks
+// field names and operators as the query is built, instead of leaving typos to
+// be silently ignored by the server:
+//
+//	query, err := search.New().Product("OpenShift Container Platform").Status("NEW", "ASSIGNED").TargetRelease("4.12.z").ChangedSince(lastSync).Query()
+//
+// The same search can be compiled to a quicksearch string instead, for links
+// to the web UI, as long as it only uses what quicksearch can express.
+//
+// Searches too large for the server can be split into shards which run
+// concurrently with SearchSharded, and CompareQueries checks which bugs a
+// change to a query adds or drops.
+package search
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eparis/bugzilla"
+)
+
+// Fields are the names of the fields which can be searched on with Where,
+// besides the custom fields, which start with "cf_".
+var Fields = map[string]bool{
+	"alias": true, "assigned_to": true, "attachments.description": true, "attachments.filename": true,
+	"attachments.mimetype": true, "blocked": true, "bug_file_loc": true, "bug_id": true,
+	"bug_severity": true, "bug_status": true, "cc": true, "classification": true, "comment_tag": true,
+	"component": true, "content": true, "creation_ts": true, "days_elapsed": true, "deadline": true,
+	"delta_ts": true, "dependson": true, "dup_id": true, "everconfirmed": true,
+	"ext_bz_bug_map.ext_bz_bug_id": true, "ext_bz_bug_map.ext_status": true, "flagtypes.name": true,
+	"keywords": true, "last_visit_ts": true, "longdesc": true, "longdescs.count": true,
+	"longdescs.isprivate": true, "op_sys": true, "priority": true, "product": true, "qa_contact": true,
+	"rep_platform": true, "reporter": true, "resolution": true, "see_also": true, "short_desc": true,
+	"status_whiteboard": true, "tag": true, "target_milestone": true, "target_release": true,
+	"version": true, "votes": true,
+}
+
+// Operators are the operators which can be used with Where
+var Operators = map[string]bool{
+	"equals": true, "notequals": true, "anyexact": true, "substring": true, "casesubstring": true,
+	"notsubstring": true, "anywordssubstr": true, "allwordssubstr": true, "nowordssubstr": true,
+	"regexp": true, "notregexp": true, "lessthan": true, "lessthaneq": true, "greaterthan": true,
+	"greaterthaneq": true, "anywords": true, "allwords": true, "nowords": true, "changedbefore": true,
+	"changedafter": true, "changedfrom": true, "changedto": true, "changedby": true, "matches": true,
+	"notmatches": true, "isempty": true, "isnotempty": true,
+}
+
+// includeFields are the fields which can be returned with IncludeFields
+// besides the custom fields: the fields of bugzilla.Bug and the groups of
+// fields the server knows
+var includeFields = func() map[string]bool {
+	fields := map[string]bool{"_all": true, "_default": true, "_extra": true, "_custom": true}
+	bug := reflect.TypeOf(bugzilla.Bug{})
+	for i := 0; i < bug.NumField(); i++ {
+		if name := strings.Split(bug.Field(i).Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+}()
+
+// Builder builds a query. The methods record the first error they run into,
+// which is returned when the query is compiled.
+type Builder struct {
+	query bugzilla.Query
+	// quick holds the quicksearch terms and notQuick why the query can not be
+	// compiled to a quicksearch, if it can not
+	quick    []string
+	notQuick string
+	err      error
+}
+
+// New returns a builder for a query which matches all bugs
+func New() *Builder {
+	return &Builder{}
+}
+
+func (b *Builder) fail(format string, args ...interface{}) *Builder {
+	if b.err == nil {
+		b.err = fmt.Errorf(format, args...)
+	}
+	return b
+}
+
+// quickTerm adds a quicksearch term matching any of the values of the field
+func (b *Builder) quickTerm(field string, values ...string) {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		if strings.Contains(value, `"`) {
+			b.notQuickBecause(fmt.Sprintf("value %q of %s contains a double quote", value, field))
+			return
+		}
+		if value == "" || strings.ContainsAny(value, " \t,:") {
+			value = `"` + value + `"`
+		}
+		quoted = append(quoted, value)
+	}
+	b.quick = append(b.quick, field+":"+strings.Join(quoted, ","))
+}
+
+func (b *Builder) notQuickBecause(reason string) {
+	if b.notQuick == "" {
+		b.notQuick = reason
+	}
+}
+
+func (b *Builder) values(method string, values []string) bool {
+	if len(values) == 0 {
+		b.fail("%s needs at least one value", method)
+		return false
+	}
+	return true
+}
+
+// Classification matches bugs in any of the classifications
+func (b *Builder) Classification(classifications ...string) *Builder {
+	if b.values("Classification", classifications) {
+		b.query.Classification = append(b.query.Classification, classifications...)
+		b.quickTerm("classification", classifications...)
+	}
+	return b
+}
+
+// Product matches bugs in any of the products
+func (b *Builder) Product(products ...string) *Builder {
+	if b.values("Product", products) {
+		b.query.Product = append(b.query.Product, products...)
+		b.quickTerm("product", products...)
+	}
+	return b
+}
+
+// Component matches bugs in any of the components
+func (b *Builder) Component(components ...string) *Builder {
+	if b.values("Component", components) {
+		b.query.Component = append(b.query.Component, components...)
+		b.quickTerm("component", components...)
+	}
+	return b
+}
+
+// Status matches bugs in any of the statuses. Without it, bugs in all
+// statuses are matched.
+func (b *Builder) Status(statuses ...string) *Builder {
+	if b.values("Status", statuses) {
+		for _, status := range statuses {
+			if status != strings.ToUpper(status) || strings.ContainsAny(status, " \t,") {
+				return b.fail("invalid status %q, statuses are upper case words like NEW", status)
+			}
+		}
+		b.query.Status = append(b.query.Status, statuses...)
+	}
+	return b
+}
+
+// Priority matches bugs with any of the priorities
+func (b *Builder) Priority(priorities ...string) *Builder {
+	if b.values("Priority", priorities) {
+		b.query.Priority = append(b.query.Priority, priorities...)
+		b.quickTerm("priority", priorities...)
+	}
+	return b
+}
+
+// Severity matches bugs with any of the severities
+func (b *Builder) Severity(severities ...string) *Builder {
+	if b.values("Severity", severities) {
+		b.query.Severity = append(b.query.Severity, severities...)
+		b.quickTerm("severity", severities...)
+	}
+	return b
+}
+
+// TargetRelease matches bugs targeted at any of the releases
+func (b *Builder) TargetRelease(releases ...string) *Builder {
+	if b.values("TargetRelease", releases) {
+		b.query.TargetRelease = append(b.query.TargetRelease, releases...)
+		b.quickTerm("target_release", releases...)
+	}
+	return b
+}
+
+// Keywords matches bugs which have all of the keywords
+func (b *Builder) Keywords(keywords ...string) *Builder {
+	if b.values("Keywords", keywords) {
+		b.query.Keywords = append(b.query.Keywords, keywords...)
+		b.query.KeywordsType = "allwords"
+		for _, keyword := range keywords {
+			b.quickTerm("keywords", keyword)
+		}
+	}
+	return b
+}
+
+// IDs matches the bugs with any of the IDs
+func (b *Builder) IDs(ids ...int) *Builder {
+	if len(ids) == 0 {
+		return b.fail("IDs needs at least one value")
+	}
+	for _, id := range ids {
+		b.query.BugIDs = append(b.query.BugIDs, strconv.Itoa(id))
+	}
+	b.query.BugIDsType = "anyexact"
+	b.notQuickBecause("quicksearch can not match bug IDs along with other terms")
+	return b
+}
+
+// Aliases matches the bugs with any of the aliases
+func (b *Builder) Aliases(aliases ...string) *Builder {
+	if b.values("Aliases", aliases) {
+		b.query.Alias = append(b.query.Alias, aliases...)
+		b.notQuickBecause("quicksearch can not match bug aliases along with other terms")
+	}
+	return b
+}
+
+// ChangedSince matches bugs which changed at or after the time
+func (b *Builder) ChangedSince(t time.Time) *Builder {
+	b.where("delta_ts", "greaterthaneq", t.UTC().Format("2006-01-02T15:04:05Z"), false)
+	b.notQuickBecause("quicksearch can not match the time of the last change")
+	return b
+}
+
+// Where matches bugs whose field matches the value according to the
+// operator, like Where("status_whiteboard", "substring", "UpcomingSprint").
+// Only the substring operator can be compiled to a quicksearch.
+func (b *Builder) Where(field, operator, value string) *Builder {
+	return b.where(field, operator, value, false)
+}
+
+// WhereNot matches bugs which do not match Where(field, operator, value)
+func (b *Builder) WhereNot(field, operator, value string) *Builder {
+	return b.where(field, operator, value, true)
+}
+
+func (b *Builder) where(field, operator, value string, negate bool) *Builder {
+	if !Fields[field] && !strings.HasPrefix(field, "cf_") {
+		return b.fail("unknown field %q", field)
+	}
+	if !Operators[operator] {
+		return b.fail("unknown operator %q", operator)
+	}
+	if value == "" && operator != "isempty" && operator != "isnotempty" {
+		return b.fail("operator %q on field %q needs a value", operator, field)
+	}
+	b.query.Advanced = append(b.query.Advanced, bugzilla.AdvancedQuery{Field: field, Op: operator, Value: value, Negate: negate})
+	if operator != "substring" {
+		b.notQuickBecause(fmt.Sprintf("quicksearch can only match substrings, not %q", operator))
+		return b
+	}
+	if negate {
+		field = "-" + field
+	}
+	b.quickTerm(field, value)
+	return b
+}
+
+// IncludeFields limits the fields which are returned for every bug
+func (b *Builder) IncludeFields(fields ...string) *Builder {
+	if !b.values("IncludeFields", fields) {
+		return b
+	}
+	for _, field := range fields {
+		if !includeFields[field] && !strings.HasPrefix(field, "cf_") {
+			return b.fail("unknown field %q to include", field)
+		}
+	}
+	b.query.IncludeFields = append(b.query.IncludeFields, fields...)
+	return b
+}
+
+// Query compiles the query for the REST API
+func (b *Builder) Query() (bugzilla.Query, error) {
+	if b.err != nil {
+		return bugzilla.Query{}, b.err
+	}
+	return b.query, nil
+}
+
+// QuickSearch compiles the query to a quicksearch, as used by the search box
+// of the web UI
+func (b *Builder) QuickSearch() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	if b.notQuick != "" {
+		return "", errors.New(b.notQuick)
+	}
+	statuses := "ALL"
+	if len(b.query.Status) > 0 {
+		statuses = strings.Join(b.query.Status, ",")
+	}
+	return strings.Join(append([]string{statuses}, b.quick...), " "), nil
+}
+
+// QuickSearchQuery compiles the query to a quicksearch and wraps it in a
+// query for the REST API, which returns the fields to include, if any.
+func (b *Builder) QuickSearchQuery() (bugzilla.Query, error) {
+	quickSearch, err := b.QuickSearch()
+	if err != nil {
+		return bugzilla.Query{}, err
+	}
+	return bugzilla.Query{
+		IncludeFields: b.query.IncludeFields,
+		Raw:           url.Values{"quicksearch": []string{quickSearch}}.Encode(),
+	}, nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package search
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/eparis/bugzilla"
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestBuilder(t *testing.T) {
+	since := time.Date(2020, 6, 1, 14, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	var testCases = []struct {
+		name          string
+		builder       *Builder
+		expected      bugzilla.Query
+		expectedErr   bool
+		expectedQuick string
+	}{
+		{
+			name:          "empty search matches all bugs",
+			builder:       New(),
+			expectedQuick: "ALL",
+		},
+		{
+			name:    "fields are compiled",
+			builder: New().Product("OpenShift Container Platform").Component("Networking", "Routing").Status("NEW", "ASSIGNED").TargetRelease("4.12.z").Keywords("Regression", "Triaged").IncludeFields("id", "summary", "cf_doc_type"),
+			expected: bugzilla.Query{
+				Product:       []string{"OpenShift Container Platform"},
+				Component:     []string{"Networking", "Routing"},
+				Status:        []string{"NEW", "ASSIGNED"},
+				TargetRelease: []string{"4.12.z"},
+				Keywords:      []string{"Regression", "Triaged"},
+				KeywordsType:  "allwords",
+				IncludeFields: []string{"id", "summary", "cf_doc_type"},
+			},
+			expectedQuick: `NEW,ASSIGNED product:"OpenShift Container Platform" component:Networking,Routing target_release:4.12.z keywords:Regression keywords:Triaged`,
+		},
+		{
+			name:    "substring terms are compiled to quicksearch",
+			builder: New().Where("status_whiteboard", "substring", "UpcomingSprint").WhereNot("cf_devel_whiteboard", "substring", "no fix"),
+			expected: bugzilla.Query{Advanced: []bugzilla.AdvancedQuery{
+				{Field: "status_whiteboard", Op: "substring", Value: "UpcomingSprint"},
+				{Field: "cf_devel_whiteboard", Op: "substring", Value: "no fix", Negate: true},
+			}},
+			expectedQuick: `ALL status_whiteboard:UpcomingSprint -cf_devel_whiteboard:"no fix"`,
+		},
+		{
+			name:     "aliases are matched",
+			builder:  New().Aliases("CVE-2020-1234", "CVE-2020-5678"),
+			expected: bugzilla.Query{Alias: []string{"CVE-2020-1234", "CVE-2020-5678"}},
+		},
+		{
+			name:    "changes are matched in UTC",
+			builder: New().IDs(1, 2).ChangedSince(since),
+			expected: bugzilla.Query{
+				BugIDs:     []string{"1", "2"},
+				BugIDsType: "anyexact",
+				Advanced:   []bugzilla.AdvancedQuery{{Field: "delta_ts", Op: "greaterthaneq", Value: "2020-06-01T12:00:00Z"}},
+			},
+		},
+		{
+			name:        "unknown fields are rejected",
+			builder:     New().Product("OCP").Where("stauts", "equals", "NEW"),
+			expectedErr: true,
+		},
+		{
+			name:        "unknown operators are rejected",
+			builder:     New().Where("bug_status", "is", "NEW"),
+			expectedErr: true,
+		},
+		{
+			name:        "unknown fields to include are rejected",
+			builder:     New().IncludeFields("id", "summray"),
+			expectedErr: true,
+		},
+		{
+			name:        "statuses must be valid",
+			builder:     New().Status("new"),
+			expectedErr: true,
+		},
+		{
+			name:        "values are required",
+			builder:     New().Product(),
+			expectedErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			query, err := testCase.builder.Query()
+			if testCase.expectedErr != (err != nil) {
+				t.Fatalf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+			}
+			if !reflect.DeepEqual(query, testCase.expected) {
+				t.Errorf("%s: got incorrect query: %v", testCase.name, diff.ObjectReflectDiff(testCase.expected, query))
+			}
+			quick, err := testCase.builder.QuickSearch()
+			if expectedErr := testCase.expectedErr || testCase.expectedQuick == ""; expectedErr != (err != nil) {
+				t.Fatalf("%s: expected quicksearch error %v, got %v", testCase.name, expectedErr, err)
+			}
+			if quick != testCase.expectedQuick {
+				t.Errorf("%s: expected quicksearch %q, got %q", testCase.name, testCase.expectedQuick, quick)
+			}
+		})
+	}
+}
+
+func TestQuickSearchQuery(t *testing.T) {
+	query, err := New().Product("OCP").Status("NEW").IncludeFields("id").QuickSearchQuery()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := bugzilla.Query{IncludeFields: []string{"id"}, Raw: "quicksearch=NEW+product%3AOCP"}
+	if !reflect.DeepEqual(query, expected) {
+		t.Errorf("got incorrect query: %v", diff.ObjectReflectDiff(expected, query))
+	}
+	if values := query.Values(); values.Get("quicksearch") != "NEW product:OCP" || values.Get("include_fields") != "id" {
+		t.Errorf("got incorrect parameters: %v", values.Encode())
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eparis/bugzilla"
+)
+
+// Shard is a part of a search, selected by conditions which are added to
+// the query. The shards of a search should not overlap and together cover
+// all bugs, like the shards returned by ShardByID and ShardByCreationTime.
+type Shard struct {
+	// Name describes the shard in errors, like "bug_id [1000, 2000)", it must
+	// be unique among the shards of a search.
+	Name string
+	// Conditions select the bugs of the shard.
+	Conditions []bugzilla.AdvancedQuery
+}
+
+// ShardByID splits the IDs up to maxID into count ranges of the same size.
+// The last shard has no upper bound, so bugs created since maxID was picked
+// are not missed.
+func ShardByID(maxID, count int) []Shard {
+	if count < 1 {
+		count = 1
+	}
+	size := (maxID + count) / count
+	var shards []Shard
+	for i := 0; i < count; i++ {
+		start, end := i*size, (i+1)*size
+		shard := Shard{Conditions: []bugzilla.AdvancedQuery{{Field: "bug_id", Op: "greaterthaneq", Value: strconv.Itoa(start)}}}
+		if i < count-1 {
+			shard.Name = fmt.Sprintf("bug_id [%d, %d)", start, end)
+			shard.Conditions = append(shard.Conditions, bugzilla.AdvancedQuery{Field: "bug_id", Op: "lessthan", Value: strconv.Itoa(end)})
+		} else {
+			shard.Name = fmt.Sprintf("bug_id [%d, ...)", start)
+		}
+		shards = append(shards, shard)
+	}
+	return shards
+}
+
+// ShardByCreationTime splits the time between from and to into count
+// intervals of the same length. The first shard has no lower bound and the
+// last no upper bound, so bugs created outside of the interval are not
+// missed.
+func ShardByCreationTime(from, to time.Time, count int) []Shard {
+	if count < 1 {
+		count = 1
+	}
+	step := to.Sub(from) / time.Duration(count)
+	var shards []Shard
+	for i := 0; i < count; i++ {
+		var shard Shard
+		var bounds []string
+		if i > 0 {
+			start := bugzilla.NewTimestamp(from.Add(time.Duration(i) * step)).String()
+			shard.Conditions = append(shard.Conditions, bugzilla.AdvancedQuery{Field: "creation_ts", Op: "greaterthaneq", Value: start})
+			bounds = append(bounds, "["+start)
+		} else {
+			bounds = append(bounds, "(...")
+		}
+		if i < count-1 {
+			end := bugzilla.NewTimestamp(from.Add(time.Duration(i+1) * step)).String()
+			shard.Conditions = append(shard.Conditions, bugzilla.AdvancedQuery{Field: "creation_ts", Op: "lessthan", Value: end})
+			bounds = append(bounds, end+")")
+		} else {
+			bounds = append(bounds, "...)")
+		}
+		shard.Name = "creation_ts " + strings.Join(bounds, ", ")
+		shards = append(shards, shard)
+	}
+	return shards
+}
+
+// ShardError is returned when some shards of a sharded search failed. The
+// bugs of the other shards are returned along with it.
+type ShardError struct {
+	// Errors holds the error of every shard which failed, by its name.
+	Errors map[string]error
+}
+
+func (e *ShardError) Error() string {
+	var names []string
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	messages := make([]string, 0, len(names))
+	for _, name := range names {
+		messages = append(messages, fmt.Sprintf("shard %s: %v", name, e.Errors[name]))
+	}
+	return fmt.Sprintf("%d shards of the search failed: %s", len(names), strings.Join(messages, "; "))
+}
+
+// SearchSharded runs the search once for every shard, with at most
+// concurrency searches in flight, and returns the bugs of all shards ordered
+// by ID. A bug which is found by more than one shard, e.g. because it changed
+// while the search ran, is returned once. Failed shards are returned as a
+// *ShardError along with the bugs of the others.
+func SearchSharded(c bugzilla.Client, query bugzilla.Query, shards []Shard, concurrency int) ([]*bugzilla.Bug, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([][]*bugzilla.Bug, len(shards))
+	errs := make([]error, len(shards))
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(shards); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indices {
+				shardQuery := query
+				shardQuery.Advanced = append(append([]bugzilla.AdvancedQuery{}, query.Advanced...), shards[index].Conditions...)
+				if len(query.IncludeFields) > 0 {
+					shardQuery.IncludeFields = withID(query.IncludeFields)
+				}
+				results[index], errs[index] = c.Search(shardQuery)
+			}
+		}()
+	}
+	for index := range shards {
+		indices <- index
+	}
+	close(indices)
+	wg.Wait()
+
+	bugs := []*bugzilla.Bug{}
+	seen := map[int]bool{}
+	shardErr := &ShardError{Errors: map[string]error{}}
+	for index, err := range errs {
+		if err != nil {
+			shardErr.Errors[shards[index].Name] = err
+			continue
+		}
+		for _, bug := range results[index] {
+			if !seen[bug.ID] {
+				seen[bug.ID] = true
+				bugs = append(bugs, bug)
+			}
+		}
+	}
+	sort.Slice(bugs, func(i, j int) bool {
+		return bugs[i].ID < bugs[j].ID
+	})
+	if len(shardErr.Errors) > 0 {
+		return bugs, shardErr
+	}
+	return bugs, nil
+}
+
+// withID returns the fields with the id field added, if missing
+func withID(fields []string) []string {
+	for _, field := range fields {
+		if field == "id" {
+			return fields
+		}
+	}
+	return append(append([]string{}, fields...), "id")
+}
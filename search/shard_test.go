@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package search
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eparis/bugzilla"
+)
+
+// idRangeClient answers searches for ID ranges like the server does
+type idRangeClient struct {
+	bugzilla.Client
+	ids []int
+	// failing makes searches for shards starting at the ID fail
+	failing string
+
+	lock     sync.Mutex
+	searches int
+}
+
+func (c *idRangeClient) Search(query bugzilla.Query) ([]*bugzilla.Bug, error) {
+	c.lock.Lock()
+	c.searches++
+	c.lock.Unlock()
+	var bugs []*bugzilla.Bug
+	for _, id := range c.ids {
+		matches := true
+		for _, condition := range query.Advanced {
+			if condition.Op == "greaterthaneq" && condition.Value == c.failing {
+				return nil, errors.New("injected error")
+			}
+			value, err := strconv.Atoi(condition.Value)
+			if err != nil {
+				return nil, err
+			}
+			switch condition.Op {
+			case "greaterthaneq":
+				matches = matches && id >= value
+			case "lessthan":
+				matches = matches && id < value
+			}
+		}
+		if matches {
+			bugs = append(bugs, &bugzilla.Bug{ID: id})
+		}
+	}
+	return bugs, nil
+}
+
+func TestShardByID(t *testing.T) {
+	shards := ShardByID(9, 3)
+	expected := []Shard{
+		{Name: "bug_id [0, 4)", Conditions: []bugzilla.AdvancedQuery{{Field: "bug_id", Op: "greaterthaneq", Value: "0"}, {Field: "bug_id", Op: "lessthan", Value: "4"}}},
+		{Name: "bug_id [4, 8)", Conditions: []bugzilla.AdvancedQuery{{Field: "bug_id", Op: "greaterthaneq", Value: "4"}, {Field: "bug_id", Op: "lessthan", Value: "8"}}},
+		{Name: "bug_id [8, ...)", Conditions: []bugzilla.AdvancedQuery{{Field: "bug_id", Op: "greaterthaneq", Value: "8"}}},
+	}
+	if !reflect.DeepEqual(shards, expected) {
+		t.Errorf("expected shards %+v, got %+v", expected, shards)
+	}
+}
+
+func TestShardByCreationTime(t *testing.T) {
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	shards := ShardByCreationTime(from, from.Add(48*time.Hour), 2)
+	middle := bugzilla.NewTimestamp(from.Add(24 * time.Hour)).String()
+	expected := []Shard{
+		{Name: "creation_ts (..., " + middle + ")", Conditions: []bugzilla.AdvancedQuery{{Field: "creation_ts", Op: "lessthan", Value: middle}}},
+		{Name: "creation_ts [" + middle + ", ...)", Conditions: []bugzilla.AdvancedQuery{{Field: "creation_ts", Op: "greaterthaneq", Value: middle}}},
+	}
+	if !reflect.DeepEqual(shards, expected) {
+		t.Errorf("expected shards %+v, got %+v", expected, shards)
+	}
+}
+
+func TestSearchSharded(t *testing.T) {
+	ids := func(bugs []*bugzilla.Bug) []int {
+		ids := []int{}
+		for _, bug := range bugs {
+			ids = append(ids, bug.ID)
+		}
+		return ids
+	}
+	client := &idRangeClient{ids: []int{12, 3, 7, 1, 20, 9}}
+	bugs, err := SearchSharded(client, bugzilla.Query{Product: []string{"OCP"}}, ShardByID(10, 4), 2)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if actual, expected := ids(bugs), []int{1, 3, 7, 9, 12, 20}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected bugs %v, got %v", expected, actual)
+	}
+	if client.searches != 4 {
+		t.Errorf("expected 4 searches, got %d", client.searches)
+	}
+
+	// overlapping shards return every bug once
+	overlapping := append(ShardByID(10, 2), Shard{Name: "all"})
+	bugs, err = SearchSharded(client, bugzilla.Query{}, overlapping, 3)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if actual, expected := ids(bugs), []int{1, 3, 7, 9, 12, 20}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected bugs %v, got %v", expected, actual)
+	}
+
+	client.failing = "6"
+	bugs, err = SearchSharded(client, bugzilla.Query{}, ShardByID(10, 2), 1)
+	shardErr, ok := err.(*ShardError)
+	if !ok {
+		t.Fatalf("expected a shard error, got %v", err)
+	}
+	if _, failed := shardErr.Errors["bug_id [6, ...)"]; !failed || len(shardErr.Errors) != 1 {
+		t.Errorf("expected the second shard to fail, got %v", shardErr)
+	}
+	if actual, expected := ids(bugs), []int{1, 3}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected the bugs of the first shard %v, got %v", expected, actual)
+	}
+}
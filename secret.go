@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// NewClientFromSecretFile creates a client which reads its API key from the
+// file at path, e.g. a mounted secret. The file is read again when the key is
+// needed and it was last read more than interval ago, so a rotated secret is
+// picked up without restarting. If reloading fails, the last key is used
+// and the file is read again after another interval.
+func NewClientFromSecretFile(path string, interval time.Duration, endpoint string, opts ...Option) (Client, error) {
+	secret := &secretFile{
+		path:     path,
+		interval: interval,
+	}
+	c := NewClient(secret.get, endpoint, opts...)
+	secret.logger = c.(*client).logger.WithField("secret", path)
+	if err := secret.start(c.(*client).now); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// apiKeySupplier holds the function supplying the API key, which can be
+// replaced while requests are in flight
+type apiKeySupplier struct {
+	value atomic.Value
+}
+
+// newAPIKeySupplier returns a supplier using getAPIKey, or supplying no key
+// if getAPIKey is nil as for clients which log in with a username and password
+func newAPIKeySupplier(getAPIKey func() []byte) *apiKeySupplier {
+	if getAPIKey == nil {
+		getAPIKey = func() []byte { return nil }
+	}
+	s := &apiKeySupplier{}
+	s.set(getAPIKey)
+	return s
+}
+
+func (s *apiKeySupplier) set(getAPIKey func() []byte) {
+	s.value.Store(getAPIKey)
+}
+
+func (s *apiKeySupplier) get() []byte {
+	return s.value.Load().(func() []byte)()
+}
+
+// secretFile caches the content of a file which is reloaded periodically
+type secretFile struct {
+	path     string
+	interval time.Duration
+	logger   *logEntry
+
+	lock sync.Mutex
+	// now returns the current time, time.Now if nil
+	now   func() time.Time
+	value []byte
+	// next is when the file is read again
+	next time.Time
+}
+
+// start makes the secret use the clock and loads it, so all times compared
+// come from the same clock
+func (s *secretFile) start(now func() time.Time) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.now = now
+	return s.load(now())
+}
+
+func (s *secretFile) get() []byte {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	now := time.Now()
+	if s.now != nil {
+		now = s.now()
+	}
+	if !now.Before(s.next) {
+		if err := s.load(now); err != nil {
+			s.logger.WithError(err).Warnf("Could not reload API key, using the previous one and retrying in %s.", s.interval)
+		}
+	}
+	return s.value
+}
+
+// load reads the file, which is read again after the interval whether
+// reading it failed or not. The lock must be held.
+func (s *secretFile) load(now time.Time) error {
+	s.next = now.Add(s.interval)
+	raw, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("could not read API key: %v", err)
+	}
+	s.value = bytes.TrimSpace(raw)
+	return nil
+}
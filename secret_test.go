@@ -0,0 +1,158 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewClientFromSecretFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bugzilla")
+	if err != nil {
+		t.Fatalf("could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "api-key")
+
+	if _, err := NewClientFromSecretFile(path, time.Hour, "https://bugzilla.example.com"); err == nil {
+		t.Error("expected an error for a missing secret file, but got none")
+	}
+
+	if err := ioutil.WriteFile(path, []byte("first\n"), 0600); err != nil {
+		t.Fatalf("could not write secret: %v", err)
+	}
+	c, err := NewClientFromSecretFile(path, time.Millisecond, "https://bugzilla.example.com")
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	getAPIKey := c.(*client).getAPIKey
+	if key := string(getAPIKey()); key != "first" {
+		t.Errorf("expected key %q, got %q", "first", key)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("second\n"), 0600); err != nil {
+		t.Fatalf("could not write secret: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if key := string(getAPIKey()); key != "second" {
+		t.Errorf("expected rotated key %q, got %q", "second", key)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("could not remove secret: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if key := string(getAPIKey()); key != "second" {
+		t.Errorf("expected previous key %q to be kept, got %q", "second", key)
+	}
+}
+
+func TestSetAPIKeySupplier(t *testing.T) {
+	c := NewClient(func() []byte { return []byte("old") }, "https://bugzilla.example.com")
+	nonCritical := NonCritical(c)
+	if err := c.SetAPIKeySupplier(func() []byte { return []byte("new") }); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if key := string(c.(*client).getAPIKey()); key != "new" {
+		t.Errorf("expected key %q, got %q", "new", key)
+	}
+	if key := string(nonCritical.(*client).getAPIKey()); key != "new" {
+		t.Errorf("expected the non-critical client to use key %q, got %q", "new", key)
+	}
+	if err := c.SetAPIKeySupplier(nil); err == nil {
+		t.Error("expected an error for a nil supplier")
+	}
+	if key := string(c.(*client).getAPIKey()); key != "new" {
+		t.Errorf("expected key %q to be kept, got %q", "new", key)
+	}
+}
+
+func TestSetAPIKeySupplierConcurrently(t *testing.T) {
+	c := NewClient(func() []byte { return []byte("old") }, "https://bugzilla.example.com")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := c.SetAPIKeySupplier(func() []byte { return []byte("new") }); err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if key := string(c.(*client).getAPIKey()); key != "old" && key != "new" {
+				t.Errorf("unexpected key %q", key)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSecretFileUsesTheClockAndBacksOff(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bugzilla")
+	if err != nil {
+		t.Fatalf("could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "api-key")
+	if err := ioutil.WriteFile(path, []byte("first\n"), 0600); err != nil {
+		t.Fatalf("could not write secret: %v", err)
+	}
+	// the clock is far behind the time the file was written at
+	clock := &fakeClock{now: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c, err := NewClientFromSecretFile(path, time.Minute, "https://bugzilla.example.com", WithClock(clock))
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	getAPIKey := c.(*client).getAPIKey
+
+	if err := ioutil.WriteFile(path, []byte("second\n"), 0600); err != nil {
+		t.Fatalf("could not write secret: %v", err)
+	}
+	if key := string(getAPIKey()); key != "first" {
+		t.Errorf("expected key %q before the interval passed, got %q", "first", key)
+	}
+	clock.Sleep(time.Minute)
+	if key := string(getAPIKey()); key != "second" {
+		t.Errorf("expected rotated key %q, got %q", "second", key)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("could not remove secret: %v", err)
+	}
+	clock.Sleep(time.Minute)
+	if key := string(getAPIKey()); key != "second" {
+		t.Errorf("expected previous key %q to be kept, got %q", "second", key)
+	}
+	// the failed reload is not retried before another interval passed
+	if err := ioutil.WriteFile(path, []byte("third\n"), 0600); err != nil {
+		t.Fatalf("could not write secret: %v", err)
+	}
+	clock.Sleep(time.Second)
+	if key := string(getAPIKey()); key != "second" {
+		t.Errorf("expected key %q until the next reload, got %q", "second", key)
+	}
+	clock.Sleep(time.Minute)
+	if key := string(getAPIKey()); key != "third" {
+		t.Errorf("expected key %q after the next reload, got %q", "third", key)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SeeAlso is a URL in the "see also" field of a bug. Links to GitHub and
+// Jira are parsed into the fields for them.
+type SeeAlso struct {
+	URL string
+	// GitHub is set for links to GitHub pull requests and issues.
+	GitHub *GitHubLink
+	// Jira is set for links to Jira issues.
+	Jira *JiraLink
+}
+
+// GitHubLink is a link to a GitHub pull request or issue
+type GitHubLink struct {
+	Org  string
+	Repo string
+	// Pull is true for pull requests and false for issues.
+	Pull   bool
+	Number int
+}
+
+// JiraLink is a link to a Jira issue
+type JiraLink struct {
+	// Host is the host of the Jira server, like issues.redhat.com.
+	Host    string
+	Project string
+	Number  int
+}
+
+// ParseSeeAlso parses the URL of a "see also" link. It fails if the value
+// is not an absolute HTTP or HTTPS URL, which the server rejects.
+func ParseSeeAlso(value string) (SeeAlso, error) {
+	link := SeeAlso{URL: value}
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return link, fmt.Errorf("invalid see also URL %q: %v", value, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return link, fmt.Errorf("invalid see also URL %q: not an absolute HTTP URL", value)
+	}
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	switch {
+	case parsed.Host == "github.com" && len(parts) == 4 && (parts[2] == "pull" || parts[2] == "issues"):
+		if number, err := strconv.Atoi(parts[3]); err == nil {
+			link.GitHub = &GitHubLink{Org: parts[0], Repo: parts[1], Pull: parts[2] == "pull", Number: number}
+		}
+	case len(parts) == 2 && parts[0] == "browse":
+		if project, number, err := JiraIssueFromIdentifier(parts[1]); err == nil {
+			link.Jira = &JiraLink{Host: parsed.Host, Project: project, Number: number}
+		}
+	}
+	return link, nil
+}
+
+// GetSeeAlso retrieves the "see also" links of the bug, with the links to
+// GitHub and Jira parsed. Values which are not URLs are returned as they are.
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#get-bug
+func (c *client) GetSeeAlso(id int) ([]SeeAlso, error) {
+	return getSeeAlso(c, id)
+}
+
+// UpdateSeeAlso adds and removes the "see also" links of the bug in one
+// update. It fails without changing the bug if any URL to add is invalid.
+// Links which are already on the bug are not added again and links which
+// are not on it are not removed, and no update is sent if that leaves
+// nothing to change.
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#update-bug
+func (c *client) UpdateSeeAlso(id int, add, remove []string) error {
+	return updateSeeAlso(c, id, add, remove)
+}
+
+func getSeeAlso(c Client, id int) ([]SeeAlso, error) {
+	bug, err := c.GetBugWithFields(id, []string{"id", "see_also"})
+	if err != nil {
+		return nil, err
+	}
+	var links []SeeAlso
+	for _, value := range bug.SeeAlso {
+		link, _ := ParseSeeAlso(value)
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+func updateSeeAlso(c Client, id int, add, remove []string) error {
+	for _, value := range add {
+		if _, err := ParseSeeAlso(value); err != nil {
+			return err
+		}
+	}
+	bug, err := c.GetBugWithFields(id, []string{"id", "see_also"})
+	if err != nil {
+		return err
+	}
+	present := map[string]bool{}
+	for _, value := range bug.SeeAlso {
+		present[value] = true
+	}
+	update := &BugSeeAlso{}
+	for _, value := range add {
+		if !present[value] {
+			update.Add = append(update.Add, value)
+			present[value] = true
+		}
+	}
+	for _, value := range remove {
+		if present[value] {
+			update.Remove = append(update.Remove, value)
+			present[value] = false
+		}
+	}
+	if len(update.Add) == 0 && len(update.Remove) == 0 {
+		return nil
+	}
+	return c.UpdateBug(id, BugUpdate{SeeAlso: update})
+}
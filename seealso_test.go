@@ -0,0 +1,139 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestParseSeeAlso(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		value       string
+		expected    SeeAlso
+		expectedErr bool
+	}{
+		{
+			name:     "GitHub pull request",
+			value:    "https://github.com/openshift/installer/pull/2728",
+			expected: SeeAlso{URL: "https://github.com/openshift/installer/pull/2728", GitHub: &GitHubLink{Org: "openshift", Repo: "installer", Pull: true, Number: 2728}},
+		},
+		{
+			name:     "GitHub issue",
+			value:    "https://github.com/openshift/installer/issues/12",
+			expected: SeeAlso{URL: "https://github.com/openshift/installer/issues/12", GitHub: &GitHubLink{Org: "openshift", Repo: "installer", Number: 12}},
+		},
+		{
+			name:     "Jira issue",
+			value:    "https://issues.redhat.com/browse/OCPBUGS-1234",
+			expected: SeeAlso{URL: "https://issues.redhat.com/browse/OCPBUGS-1234", Jira: &JiraLink{Host: "issues.redhat.com", Project: "OCPBUGS", Number: 1234}},
+		},
+		{
+			name:     "other link",
+			value:    "https://bugzilla.redhat.com/show_bug.cgi?id=1705243",
+			expected: SeeAlso{URL: "https://bugzilla.redhat.com/show_bug.cgi?id=1705243"},
+		},
+		{
+			name:        "relative URL is invalid",
+			value:       "openshift/installer/pull/2728",
+			expectedErr: true,
+		},
+		{
+			name:        "other scheme is invalid",
+			value:       "ftp://example.com/file",
+			expectedErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		actual, err := ParseSeeAlso(testCase.value)
+		if testCase.expectedErr != (err != nil) {
+			t.Errorf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+			continue
+		}
+		if !testCase.expectedErr && !reflect.DeepEqual(actual, testCase.expected) {
+			t.Errorf("%s: got incorrect link: %v", testCase.name, diff.ObjectReflectDiff(testCase.expected, actual))
+		}
+	}
+}
+
+func TestUpdateSeeAlso(t *testing.T) {
+	var updates []BugUpdate
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"bugs":[{"id":1705243,"see_also":["https://github.com/org/repo/pull/1"]}]}`))
+			return
+		}
+		raw, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("could not read request body: %v", err)
+		}
+		var update BugUpdate
+		if err := json.Unmarshal(raw, &update); err != nil {
+			t.Fatalf("could not unmarshal update: %v", err)
+		}
+		updates = append(updates, update)
+		w.Write([]byte(`{"bugs":[{"id":1705243,"changes":{}}]}`))
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	if err := client.UpdateSeeAlso(1705243, []string{"https://github.com/org/repo/pull/1"}, []string{"https://github.com/org/repo/pull/2"}); err != nil {
+		t.Errorf("expected no error, but got one: %v", err)
+	}
+	if err := client.UpdateSeeAlso(1705243, []string{"org/repo/pull/3"}, nil); err == nil {
+		t.Error("expected an error for an invalid URL, but got none")
+	}
+	if len(updates) != 0 {
+		t.Errorf("expected no update when nothing changes, got %v", updates)
+	}
+	if err := client.UpdateSeeAlso(1705243, []string{"https://issues.redhat.com/browse/OCPBUGS-1"}, []string{"https://github.com/org/repo/pull/1"}); err != nil {
+		t.Errorf("expected no error, but got one: %v", err)
+	}
+	expected := []BugUpdate{{SeeAlso: &BugSeeAlso{Add: []string{"https://issues.redhat.com/browse/OCPBUGS-1"}, Remove: []string{"https://github.com/org/repo/pull/1"}}}}
+	if !reflect.DeepEqual(updates, expected) {
+		t.Errorf("expected updates %v, got %v", expected, updates)
+	}
+
+	links, err := client.GetSeeAlso(1705243)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if len(links) != 1 || links[0].GitHub == nil || links[0].GitHub.Number != 1 {
+		t.Errorf("expected the parsed pull request, got %v", links)
+	}
+}
+
+func TestFakeSeeAlso(t *testing.T) {
+	fake := &Fake{Bugs: map[int]Bug{1: {ID: 1, Status: "NEW", SeeAlso: []string{"https://example.com/a"}}}}
+	if err := fake.UpdateSeeAlso(1, []string{"https://example.com/b"}, []string{"https://example.com/a"}); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	links, err := fake.GetSeeAlso(1)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if expected := []SeeAlso{{URL: "https://example.com/b"}}; !reflect.DeepEqual(links, expected) {
+		t.Errorf("expected links %v, got %v", expected, links)
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+)
+
+// AuthToken authenticates requests with a session token which the client
+// obtains from /rest/login using the credentials given with WithLogin.
+const AuthToken = "token"
+
+// WithLogin configures the username and password used to log in when the
+// AuthToken auth method is used, for users who do not have an API key.
+func WithLogin(username, password string) Option {
+	return func(c *client) {
+		c.session = &session{username: username, password: password}
+	}
+}
+
+// session holds the credentials and the current token of a logged in client
+type session struct {
+	username string
+	password string
+
+	// lock serializes logins
+	lock sync.Mutex
+	// token holds the current token as a string, it is read without the lock
+	// to redact it from requests which are sent while logging in
+	token atomic.Value
+}
+
+// currentToken returns the current token, if any
+func (s *session) currentToken() string {
+	token, _ := s.token.Load().(string)
+	return token
+}
+
+// login exchanges the username and password for a session token.
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/user.html#login
+func (c *client) login() (string, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "Login", "username": c.session.username})
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/rest/login", c.endpoint), nil)
+	if err != nil {
+		return "", err
+	}
+	values := url.Values{}
+	values.Set("login", c.session.username)
+	values.Set("password", c.session.password)
+	req.URL.RawQuery = values.Encode()
+	c.setBasicAuth(req)
+	logger = logger.WithField("url", obfuscatedURL(req.URL.String())).WithField("verb", req.Method)
+	raw, err := c.requestWithRetries(req, logger)
+	if err != nil {
+		return "", err
+	}
+	var parsedResponse struct {
+		ID    int    `json:"id"`
+		Token string `json:"token"`
+	}
+	if err := c.unmarshal(raw, &parsedResponse); err != nil {
+		return "", fmt.Errorf("could not unmarshal response body: %v", err)
+	}
+	if parsedResponse.Token == "" {
+		return "", errors.New("login response did not contain a token")
+	}
+	return parsedResponse.Token, nil
+}
+
+// sessionToken returns the current session token, logging in first if there
+// is none yet. If stale is set and still the current token, it is replaced.
+func (c *client) sessionToken(stale string) (string, error) {
+	c.session.lock.Lock()
+	defer c.session.lock.Unlock()
+	if current := c.session.currentToken(); current != "" && (stale == "" || current != stale) {
+		return current, nil
+	}
+	token, err := c.login()
+	if err != nil {
+		return "", err
+	}
+	c.session.token.Store(token)
+	return token, nil
+}
+
+// requestWithToken sends the request with the session token. If the server
+// rejects the token, the client logs in again and resends the request once.
+func (c *client) requestWithToken(req *http.Request, logger *logEntry) ([]byte, error) {
+	token, err := c.sessionToken("")
+	if err != nil {
+		return nil, fmt.Errorf("could not log in: %v", err)
+	}
+	setToken(req, token)
+	raw, err := c.requestWithFailover(req, logger)
+	if !IsUnauthorized(err) {
+		return raw, err
+	}
+	logger.WithError(err).Debug("Session token was rejected, logging in again.")
+	token, err = c.sessionToken(token)
+	if err != nil {
+		return nil, fmt.Errorf("could not log in: %v", err)
+	}
+	if req.GetBody != nil {
+		body, bodyErr := req.GetBody()
+		if bodyErr != nil {
+			return nil, fmt.Errorf("could not reset request body for retry: %v", bodyErr)
+		}
+		req.Body = body
+	}
+	setToken(req, token)
+	return c.requestWithFailover(req, logger)
+}
+
+func setToken(req *http.Request, token string) {
+	values := req.URL.Query()
+	values.Set("token", token)
+	req.URL.RawQuery = values.Encode()
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTokenAuth(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		password       string
+		rejectTokens   int
+		expectedLogins int
+		expectedErr    bool
+	}{
+		{
+			name:           "logs in once and reuses the token",
+			password:       "secret",
+			expectedLogins: 1,
+		},
+		{
+			name:           "logs in again when the token is rejected",
+			password:       "secret",
+			rejectTokens:   1,
+			expectedLogins: 2,
+		},
+		{
+			name:           "gives up when the new token is rejected too",
+			password:       "secret",
+			rejectTokens:   2,
+			expectedLogins: 2,
+			expectedErr:    true,
+		},
+		{
+			name:           "invalid credentials fail",
+			password:       "wrong",
+			expectedLogins: 1,
+			expectedErr:    true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			logins, rejected := 0, 0
+			testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/rest/login" {
+					logins++
+					if r.URL.Query().Get("login") != "user" || r.URL.Query().Get("password") != "secret" {
+						w.WriteHeader(http.StatusUnauthorized)
+						fmt.Fprint(w, `{"error":true,"code":300,"message":"The username or password you entered is not valid."}`)
+						return
+					}
+					fmt.Fprintf(w, `{"id":1,"token":"token-%d"}`, logins)
+					return
+				}
+				if r.URL.Query().Get("api_key") != "" || r.Header.Get("X-BUGZILLA-API-KEY") != "" {
+					t.Errorf("%s: expected no API key to be sent", testCase.name)
+				}
+				if r.URL.Query().Get("token") != fmt.Sprintf("token-%d", logins) || rejected < testCase.rejectTokens {
+					rejected++
+					w.WriteHeader(http.StatusUnauthorized)
+					fmt.Fprint(w, `{"error":true,"code":32000,"message":"The token you provided is invalid."}`)
+					return
+				}
+				w.Write(bugData)
+			}))
+			defer testServer.Close()
+			c := clientForUrl(testServer.URL).(*client)
+			WithLogin("user", testCase.password)(c)
+			if err := c.SetAuthMethod(AuthToken); err != nil {
+				t.Fatalf("%s: expected no error setting auth method, but got one: %v", testCase.name, err)
+			}
+
+			for i := 0; i < 2; i++ {
+				_, err := c.GetBug(1705243)
+				if testCase.expectedErr != (err != nil) {
+					t.Errorf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+				}
+				if testCase.expectedErr {
+					break
+				}
+			}
+			if logins != testCase.expectedLogins {
+				t.Errorf("%s: expected %d logins, got %d", testCase.name, testCase.expectedLogins, logins)
+			}
+		})
+	}
+}
+
+func TestSetAuthMethodTokenRequiresLogin(t *testing.T) {
+	c := clientForUrl("http://example.com").(*client)
+	if err := c.SetAuthMethod(AuthToken); err == nil {
+		t.Error("expected an error setting token auth without credentials, but got none")
+	}
+}
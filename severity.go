@@ -0,0 +1,161 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BugSeverity is the severity of a bug. Severities are ordered, so a bug can
+// be checked to be at least of some severity. The zero value is
+// SeverityUnspecified, which is lower than all other severities.
+type BugSeverity int
+
+const (
+	SeverityUnspecified BugSeverity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityUrgent
+)
+
+// BugPriority is the priority of a bug, ordered like BugSeverity. The zero
+// value is PriorityUnspecified, which is lower than all other priorities.
+type BugPriority int
+
+const (
+	PriorityUnspecified BugPriority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+// levels are the names of the severities and priorities, by their value
+var levels = []string{"unspecified", "low", "medium", "high", "urgent"}
+
+// parseLevel returns the value of the named severity or priority, ignoring
+// case as the server does
+func parseLevel(kind, value string) (int, error) {
+	for level, name := range levels {
+		if strings.EqualFold(value, name) {
+			return level, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown %s %q, expected one of %s", kind, value, strings.Join(levels, ", "))
+}
+
+// levelName returns the name of the severity or priority as the server spells it
+func levelName(level int) string {
+	if level < 0 || level >= len(levels) {
+		return fmt.Sprintf("unknown(%d)", level)
+	}
+	return levels[level]
+}
+
+// compareLevels returns -1, 0 or 1 if a is lower than, the same as or higher than b
+func compareLevels(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ParseBugSeverity parses a severity as the server returns it, like "high".
+// An empty severity is parsed as SeverityUnspecified.
+func ParseBugSeverity(value string) (BugSeverity, error) {
+	if value == "" {
+		return SeverityUnspecified, nil
+	}
+	level, err := parseLevel("severity", value)
+	return BugSeverity(level), err
+}
+
+// String returns the severity as the server spells it
+func (s BugSeverity) String() string {
+	return levelName(int(s))
+}
+
+// Compare returns -1, 0 or 1 if the severity is lower than, the same as or
+// higher than the other
+func (s BugSeverity) Compare(other BugSeverity) int {
+	return compareLevels(int(s), int(other))
+}
+
+// AtLeast returns true if the severity is the same as or higher than the other
+func (s BugSeverity) AtLeast(other BugSeverity) bool {
+	return s >= other
+}
+
+// ParseBugPriority parses a priority as the server returns it, like "high".
+// An empty priority is parsed as PriorityUnspecified.
+func ParseBugPriority(value string) (BugPriority, error) {
+	if value == "" {
+		return PriorityUnspecified, nil
+	}
+	level, err := parseLevel("priority", value)
+	return BugPriority(level), err
+}
+
+// String returns the priority as the server spells it
+func (p BugPriority) String() string {
+	return levelName(int(p))
+}
+
+// Compare returns -1, 0 or 1 if the priority is lower than, the same as or
+// higher than the other
+func (p BugPriority) Compare(other BugPriority) int {
+	return compareLevels(int(p), int(other))
+}
+
+// AtLeast returns true if the priority is the same as or higher than the other
+func (p BugPriority) AtLeast(other BugPriority) bool {
+	return p >= other
+}
+
+// ValidateSeverityAndPriority checks that the legal values of the severity and
+// priority fields of the server are the ones BugSeverity and BugPriority know,
+// so servers with other values are noticed before their bugs are compared.
+func ValidateSeverityAndPriority(c Client) error {
+	for _, field := range []struct{ name, kind string }{{"bug_severity", "severity"}, {"priority", "priority"}} {
+		fields, err := c.GetFields(field.name)
+		if err != nil {
+			return err
+		}
+		if len(fields) != 1 {
+			return fmt.Errorf("did not get one %s field, but %d", field.name, len(fields))
+		}
+		var unknown []string
+		for _, value := range fields[0].Values {
+			if value.Name == "" {
+				continue
+			}
+			if _, err := parseLevel(field.kind, value.Name); err != nil {
+				unknown = append(unknown, value.Name)
+			}
+		}
+		if len(unknown) > 0 {
+			return fmt.Errorf("the server has unknown values for the %s: %s", field.kind, strings.Join(unknown, ", "))
+		}
+	}
+	return nil
+}
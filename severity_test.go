@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import "testing"
+
+func TestParseBugSeverity(t *testing.T) {
+	var testCases = []struct {
+		value       string
+		expected    BugSeverity
+		expectedErr bool
+	}{
+		{value: "", expected: SeverityUnspecified},
+		{value: "unspecified", expected: SeverityUnspecified},
+		{value: "low", expected: SeverityLow},
+		{value: "Medium", expected: SeverityMedium},
+		{value: "HIGH", expected: SeverityHigh},
+		{value: "urgent", expected: SeverityUrgent},
+		{value: "critical", expectedErr: true},
+	}
+	for _, testCase := range testCases {
+		actual, err := ParseBugSeverity(testCase.value)
+		if testCase.expectedErr != (err != nil) {
+			t.Errorf("%q: expected error %v, got %v", testCase.value, testCase.expectedErr, err)
+		}
+		if actual != testCase.expected {
+			t.Errorf("%q: expected %s, got %s", testCase.value, testCase.expected, actual)
+		}
+		if err == nil && testCase.value != "" {
+			priority, err := ParseBugPriority(testCase.value)
+			if err != nil || priority.String() != actual.String() {
+				t.Errorf("%q: expected priority %s, got %s and %v", testCase.value, actual, priority, err)
+			}
+		}
+	}
+}
+
+func TestLevelOrdering(t *testing.T) {
+	if !SeverityUrgent.AtLeast(SeverityHigh) || !SeverityHigh.AtLeast(SeverityHigh) || SeverityMedium.AtLeast(SeverityHigh) {
+		t.Error("severities are not ordered")
+	}
+	if SeverityUnspecified.Compare(SeverityLow) != -1 || SeverityLow.Compare(SeverityLow) != 0 || SeverityUrgent.Compare(SeverityLow) != 1 {
+		t.Error("severities do not compare correctly")
+	}
+	if !PriorityHigh.AtLeast(PriorityMedium) || PriorityLow.AtLeast(PriorityMedium) || PriorityMedium.Compare(PriorityHigh) != -1 {
+		t.Error("priorities are not ordered")
+	}
+	if actual, expected := BugSeverity(42).String(), "unknown(42)"; actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestValidateSeverityAndPriority(t *testing.T) {
+	values := func(names ...string) []FieldValue {
+		var values []FieldValue
+		for _, name := range names {
+			values = append(values, FieldValue{Name: name})
+		}
+		return values
+	}
+	var testCases = []struct {
+		name        string
+		fields      []Field
+		expectedErr bool
+	}{
+		{
+			name: "known values",
+			fields: []Field{
+				{Name: "bug_severity", Values: values("urgent", "high", "medium", "low", "unspecified")},
+				{Name: "priority", Values: values("", "urgent", "high", "medium", "low", "unspecified")},
+			},
+		},
+		{
+			name: "unknown severity",
+			fields: []Field{
+				{Name: "bug_severity", Values: values("blocker", "high")},
+				{Name: "priority", Values: values("high")},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "unknown priority",
+			fields: []Field{
+				{Name: "bug_severity", Values: values("high")},
+				{Name: "priority", Values: values("P1")},
+			},
+			expectedErr: true,
+		},
+		{
+			name:        "missing field",
+			fields:      []Field{{Name: "bug_severity", Values: values("high")}},
+			expectedErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		err := ValidateSeverityAndPriority(&Fake{Fields: testCase.fields})
+		if testCase.expectedErr != (err != nil) {
+			t.Errorf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+		}
+	}
+}
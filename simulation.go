@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Simulation makes the Fake behave like a struggling server, so that retry
+// and backoff logic can be tested without a real server. All random decisions
+// are drawn from a source seeded with Seed, so a simulation with the same seed
+// and the same calls always fails the same calls.
+type Simulation struct {
+	// Seed seeds the random decisions of the simulation.
+	Seed int64
+	// Default is the behavior of methods which are not in Methods.
+	Default MethodSimulation
+	// Methods holds the behavior per method, keyed by the name of the Client
+	// method, like "GetBug".
+	Methods map[string]MethodSimulation
+	// Sleep is called with the latency of every call, time.Sleep if nil. Tests
+	// can record the latency instead of waiting for it.
+	Sleep func(time.Duration)
+
+	lock  sync.Mutex
+	rand  *rand.Rand
+	calls map[string]int
+}
+
+// MethodSimulation is the simulated behavior of calls to a method
+type MethodSimulation struct {
+	// Latency is added to every call.
+	Latency time.Duration
+	// Jitter adds a random latency of up to Jitter to every call.
+	Jitter time.Duration
+	// ServerErrorRate is the fraction of calls, between 0 and 1, which fail
+	// with a 500 Internal Server Error.
+	ServerErrorRate float64
+	// RateLimitBurst is the number of calls which are allowed before calls
+	// are rate limited. Calls are never rate limited if it is 0.
+	RateLimitBurst int
+	// RateLimitedCalls is the number of calls which fail with 429 Too Many
+	// Requests after every burst of allowed calls.
+	RateLimitedCalls int
+}
+
+// Calls returns the number of calls made to the method so far
+func (s *Simulation) Calls(method string) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.calls[method]
+}
+
+// call simulates a call to the method, returning the error the call fails with
+func (s *Simulation) call(method string) error {
+	s.lock.Lock()
+	if s.rand == nil {
+		s.rand = rand.New(rand.NewSource(s.Seed))
+		s.calls = map[string]int{}
+	}
+	behavior, ok := s.Methods[method]
+	if !ok {
+		behavior = s.Default
+	}
+	call := s.calls[method]
+	s.calls[method]++
+	latency := behavior.Latency
+	if behavior.Jitter > 0 {
+		latency += time.Duration(s.rand.Int63n(int64(behavior.Jitter)))
+	}
+	failed := behavior.ServerErrorRate > 0 && s.rand.Float64() < behavior.ServerErrorRate
+	s.lock.Unlock()
+
+	if latency > 0 {
+		sleep := s.Sleep
+		if sleep == nil {
+			sleep = time.Sleep
+		}
+		sleep(latency)
+	}
+	if behavior.RateLimitBurst > 0 && call%(behavior.RateLimitBurst+behavior.RateLimitedCalls) >= behavior.RateLimitBurst {
+		return &RequestError{StatusCode: http.StatusTooManyRequests, Message: "simulated rate limiting"}
+	}
+	if failed {
+		return &RequestError{StatusCode: http.StatusInternalServerError, Message: "simulated server error"}
+	}
+	return nil
+}
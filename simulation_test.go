@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSimulation(t *testing.T) {
+	outcomes := func(seed int64) []string {
+		fake := &Fake{
+			Bugs: map[int]Bug{1: {ID: 1}},
+			Simulation: &Simulation{
+				Seed:    seed,
+				Default: MethodSimulation{ServerErrorRate: 0.5},
+				Methods: map[string]MethodSimulation{
+					"UpdateBug": {RateLimitBurst: 2, RateLimitedCalls: 1},
+				},
+			},
+		}
+		var outcomes []string
+		for i := 0; i < 6; i++ {
+			_, err := fake.GetBug(1)
+			switch {
+			case err == nil:
+				outcomes = append(outcomes, "ok")
+			case isRetryable(err):
+				outcomes = append(outcomes, "5xx")
+			default:
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		for i := 0; i < 6; i++ {
+			err := fake.UpdateBug(1, BugUpdate{Status: "POST"})
+			switch {
+			case err == nil:
+				outcomes = append(outcomes, "ok")
+			case IsRateLimited(err):
+				outcomes = append(outcomes, "429")
+			default:
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		return outcomes
+	}
+
+	first, second := outcomes(42), outcomes(42)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("expected the same outcomes for the same seed, got %v and %v", first, second)
+	}
+	failures := 0
+	for _, outcome := range first[:6] {
+		if outcome == "5xx" {
+			failures++
+		}
+	}
+	if failures == 0 || failures == 6 {
+		t.Errorf("expected some but not all calls to fail, got %v", first[:6])
+	}
+	if expected := []string{"ok", "ok", "429", "ok", "ok", "429"}; !reflect.DeepEqual(first[6:], expected) {
+		t.Errorf("expected rate limiting after every burst, got %v", first[6:])
+	}
+}
+
+func TestSimulationLatency(t *testing.T) {
+	var slept []time.Duration
+	simulation := &Simulation{
+		Default: MethodSimulation{Latency: time.Second},
+		Methods: map[string]MethodSimulation{
+			"UpdateBug": {Latency: time.Minute, Jitter: time.Second},
+		},
+		Sleep: func(d time.Duration) { slept = append(slept, d) },
+	}
+	fake := &Fake{Bugs: map[int]Bug{1: {ID: 1}}, Simulation: simulation}
+	if _, err := fake.GetBugWithFields(1, []string{"id"}); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if err := fake.UpdateBug(1, BugUpdate{Status: "POST"}); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if len(slept) != 2 || slept[0] != time.Second || slept[1] < time.Minute || slept[1] >= time.Minute+time.Second {
+		t.Errorf("got incorrect latencies: %v", slept)
+	}
+	if calls := simulation.Calls("GetBugWithFields"); calls != 1 {
+		t.Errorf("expected 1 call to GetBugWithFields, got %d", calls)
+	}
+	if calls := simulation.Calls("GetBug"); calls != 0 {
+		t.Errorf("expected calls made by the fake itself to not be simulated, got %d", calls)
+	}
+}
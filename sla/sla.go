@@ -0,0 +1,169 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sla computes whether open bugs were fixed within the time their
+// severity allows, for triage dashboards.
+package sla
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eparis/bugzilla"
+)
+
+const day = 24 * time.Hour
+
+// DefaultMaxAge holds how long bugs may stay open by severity, used when a
+// Policy does not set MaxAge.
+var DefaultMaxAge = map[string]time.Duration{
+	"urgent": 7 * day,
+	"high":   30 * day,
+	"medium": 90 * day,
+	"low":    180 * day,
+}
+
+// Policy decides how long bugs may stay open. The zero value uses the
+// defaults for every setting.
+type Policy struct {
+	// MaxAge is how long bugs may stay open after they were created, keyed by
+	// severity, DefaultMaxAge if nil. Severities are matched ignoring case.
+	// Bugs with other severities are not covered by the policy.
+	MaxAge map[string]time.Duration
+	// Now returns the current time, time.Now if nil.
+	Now func() time.Time
+}
+
+// Status is the state of a bug with regard to the policy
+type Status struct {
+	Bug *bugzilla.Bug
+	// Covered is set if the policy applies to the bug, i.e. if the bug is open,
+	// its creation time is known and the policy has a maximum age for its
+	// severity. The other fields are only set for covered bugs.
+	Covered bool
+	// Deadline is when the bug has to be fixed.
+	Deadline time.Time
+	// Remaining is the time left until the deadline, negative once breached.
+	Remaining time.Duration
+	// Breached is set if the bug is still open after the deadline.
+	Breached bool
+}
+
+// Evaluate computes the status of the bug
+func (p *Policy) Evaluate(bug *bugzilla.Bug) Status {
+	return p.evaluate(bug, p.now())
+}
+
+func (p *Policy) evaluate(bug *bugzilla.Bug, now time.Time) Status {
+	status := Status{Bug: bug}
+	maxAge, ok := p.maxAge()[strings.ToLower(bug.Severity)]
+	if !ok || !bug.IsOpen || bug.CreationTime.IsZero() {
+		return status
+	}
+	status.Covered = true
+	status.Deadline = bug.CreationTime.Add(maxAge)
+	status.Remaining = status.Deadline.Sub(now)
+	status.Breached = status.Remaining < 0
+	return status
+}
+
+func (p *Policy) maxAge() map[string]time.Duration {
+	if p.MaxAge == nil {
+		return DefaultMaxAge
+	}
+	return p.MaxAge
+}
+
+func (p *Policy) now() time.Time {
+	if p.Now == nil {
+		return time.Now()
+	}
+	return p.Now()
+}
+
+// Summary counts the bugs covered by the policy and those of them which
+// breached it
+type Summary struct {
+	Covered  int
+	Breached int
+}
+
+// Report summarizes the statuses of a set of bugs, e.g. a search result
+type Report struct {
+	// Total is the number of bugs, including those not covered.
+	Total int
+	// Summary counts all covered bugs.
+	Summary
+	// BySeverity counts the covered bugs by their severity in lower case.
+	BySeverity map[string]Summary
+	// Statuses are the statuses of the covered bugs, the closest to or the
+	// furthest past their deadline first.
+	Statuses []Status
+}
+
+// Report evaluates the bugs against the policy at the same time and
+// summarizes the result
+func (p *Policy) Report(bugs []*bugzilla.Bug) Report {
+	now := p.now()
+	report := Report{Total: len(bugs), BySeverity: map[string]Summary{}}
+	for _, bug := range bugs {
+		status := p.evaluate(bug, now)
+		if !status.Covered {
+			continue
+		}
+		severity := strings.ToLower(bug.Severity)
+		summary := report.BySeverity[severity]
+		summary.Covered++
+		report.Covered++
+		if status.Breached {
+			summary.Breached++
+			report.Breached++
+		}
+		report.BySeverity[severity] = summary
+		report.Statuses = append(report.Statuses, status)
+	}
+	sort.SliceStable(report.Statuses, func(i, j int) bool {
+		return report.Statuses[i].Remaining < report.Statuses[j].Remaining
+	})
+	return report
+}
+
+// fields are the fields of a bug which the policy needs
+var fields = []string{"id", "severity", "is_open", "creation_time"}
+
+// Search reports on the bugs matching the query. If the query limits the
+// fields of the bugs, the fields the policy needs are added.
+func (p *Policy) Search(c bugzilla.Client, query bugzilla.Query) (Report, error) {
+	if len(query.IncludeFields) > 0 {
+		included := append([]string{}, query.IncludeFields...)
+		for _, field := range fields {
+			found := false
+			for _, existing := range included {
+				found = found || existing == field
+			}
+			if !found {
+				included = append(included, field)
+			}
+		}
+		query.IncludeFields = included
+	}
+	bugs, err := c.Search(query)
+	if err != nil {
+		return Report{}, err
+	}
+	return p.Report(bugs), nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sla
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/eparis/bugzilla"
+)
+
+func TestEvaluate(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	created := bugzilla.NewTimestamp(now.Add(-10 * day))
+	policy := &Policy{Now: func() time.Time { return now }}
+	var testCases = []struct {
+		name     string
+		bug      *bugzilla.Bug
+		expected Status
+	}{
+		{
+			name:     "open bug within its deadline",
+			bug:      &bugzilla.Bug{IsOpen: true, Severity: "high", CreationTime: created},
+			expected: Status{Covered: true, Deadline: now.Add(20 * day), Remaining: 20 * day},
+		},
+		{
+			name:     "open bug past its deadline",
+			bug:      &bugzilla.Bug{IsOpen: true, Severity: "Urgent", CreationTime: created},
+			expected: Status{Covered: true, Deadline: now.Add(-3 * day), Remaining: -3 * day, Breached: true},
+		},
+		{
+			name: "closed bugs are not covered",
+			bug:  &bugzilla.Bug{Severity: "urgent", CreationTime: created},
+		},
+		{
+			name: "bugs with other severities are not covered",
+			bug:  &bugzilla.Bug{IsOpen: true, Severity: "unspecified", CreationTime: created},
+		},
+		{
+			name: "bugs without creation time are not covered",
+			bug:  &bugzilla.Bug{IsOpen: true, Severity: "urgent"},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase.expected.Bug = testCase.bug
+		if actual := policy.Evaluate(testCase.bug); !reflect.DeepEqual(actual, testCase.expected) {
+			t.Errorf("%s: expected status %+v, got %+v", testCase.name, testCase.expected, actual)
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	createdAgo := func(age time.Duration) bugzilla.Timestamp {
+		return bugzilla.NewTimestamp(now.Add(-age))
+	}
+	bugs := []*bugzilla.Bug{
+		{ID: 1, IsOpen: true, Severity: "high", CreationTime: createdAgo(day)},
+		{ID: 2, IsOpen: true, Severity: "low", CreationTime: createdAgo(3 * day)},
+		{ID: 3, IsOpen: true, Severity: "high", CreationTime: createdAgo(5 * day)},
+		{ID: 4, Severity: "high", CreationTime: createdAgo(5 * day)},
+		{ID: 5, IsOpen: true, Severity: "medium", CreationTime: createdAgo(day)},
+	}
+	policy := &Policy{
+		MaxAge: map[string]time.Duration{"high": 2 * day, "low": 10 * day},
+		Now:    func() time.Time { return now },
+	}
+	report := policy.Report(bugs)
+	if report.Total != 5 || report.Covered != 3 || report.Breached != 1 {
+		t.Errorf("expected 5 bugs, 3 covered and 1 breached, got %d, %d and %d", report.Total, report.Covered, report.Breached)
+	}
+	expected := map[string]Summary{"high": {Covered: 2, Breached: 1}, "low": {Covered: 1}}
+	if !reflect.DeepEqual(report.BySeverity, expected) {
+		t.Errorf("expected summaries %v, got %v", expected, report.BySeverity)
+	}
+	var order []int
+	for _, status := range report.Statuses {
+		order = append(order, status.Bug.ID)
+	}
+	if !reflect.DeepEqual(order, []int{3, 1, 2}) {
+		t.Errorf("expected the most urgent bugs first, got %v", order)
+	}
+}
+
+type searchClient struct {
+	bugzilla.Client
+	bugs  []*bugzilla.Bug
+	query bugzilla.Query
+}
+
+func (c *searchClient) Search(query bugzilla.Query) ([]*bugzilla.Bug, error) {
+	c.query = query
+	return c.bugs, nil
+}
+
+func TestSearch(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	client := &searchClient{bugs: []*bugzilla.Bug{{ID: 1, IsOpen: true, Severity: "urgent", CreationTime: bugzilla.NewTimestamp(now.Add(-8 * day))}}}
+	policy := &Policy{Now: func() time.Time { return now }}
+	report, err := policy.Search(client, bugzilla.Query{Product: []string{"OCP"}, IncludeFields: []string{"id", "summary"}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if report.Breached != 1 {
+		t.Errorf("expected 1 breached bug, got %d", report.Breached)
+	}
+	if expected := []string{"id", "summary", "severity", "is_open", "creation_time"}; !reflect.DeepEqual(client.query.IncludeFields, expected) {
+		t.Errorf("expected fields %v, got %v", expected, client.query.IncludeFields)
+	}
+}
@@ -25,8 +25,6 @@ import (
 	"net/http/httptest"
 	"strconv"
 	"strings"
-
-	"github.com/sirupsen/logrus"
 )
 
 func (tc *testClient) readData() error {
@@ -144,6 +142,14 @@ func (tc testClient) UpdateBug(_ int, _ BugUpdate) error {
 	return nil
 }
 
+func (tc testClient) UpdateBugs(_ []int, _ BugUpdate) error {
+	return nil
+}
+
+func (tc testClient) UpdateBugByAlias(_ string, _ BugUpdate) error {
+	return nil
+}
+
 func (tc *testClient) Search(query Query) ([]*Bug, error) {
 	srv := tc.getTestServer(tc.path)
 	defer srv.Close()
@@ -159,6 +165,14 @@ func (tc testClient) GetExternalBugs(_ int) ([]ExternalBug, error) {
 	return []ExternalBug{}, nil
 }
 
+func (tc testClient) GetExternalBugsForBugs(_ []int) (map[int][]ExternalBug, error) {
+	return map[int][]ExternalBug{}, nil
+}
+
+func (tc testClient) GetExternalBugPRsOnBugs(_ []int, _ ...string) (map[int][]ExternalBug, error) {
+	return map[int][]ExternalBug{}, nil
+}
+
 func (tc testClient) GetBug(id int) (*Bug, error) {
 	srv := tc.getTestServer(tc.path)
 	defer srv.Close()
@@ -174,19 +188,32 @@ func (testClient) AddPullRequestAsExternalBug(_ int, _ string, _ string, _ int)
 	return false, nil
 }
 
+func (testClient) AddJiraIssueAsExternalBug(_ int, _ string, _ int) (bool, error) {
+	return false, nil
+}
+
+func (testClient) UpdateExternalBugStatus(_ int, _ ExternalBugIdentifier, _ string) error {
+	return nil
+}
+
+func (testClient) CreateAttachment(_ int, _ AttachmentCreate) (int, error) {
+	return 0, nil
+}
+
 // GetTestClient returns a client which acts on the data in a json file specified in path
 func GetTestClient(path string) Client {
 	tc := &testClient{
 		client: client{
-			logger: logrus.WithField("testing", "true"),
+			logger: newLogEntry(defaultLogger, LogFields{"testing": "true"}),
 			client: &http.Client{
 				Transport: &http.Transport{
 					TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 				},
 			},
-			getAPIKey: func() []byte {
+			apiKey: newAPIKeySupplier(func() []byte {
 				return []byte("api-key")
-			},
+			}),
+			settings: &clientSettings{},
 		},
 		path: path,
 		bugs: map[int]Bug{},
@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// WithTimeout limits how long every request sent to the server may take,
+// including reading the response. A request which is retried gets the full
+// timeout for every attempt. WithMethodTimeouts and WithCallTimeout override
+// it for some calls.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *client) {
+		c.timeout = timeout
+	}
+}
+
+// WithMethodTimeouts overrides the timeout of WithTimeout for the requests of
+// client methods, keyed by the method name like "Search", e.g. to give
+// searches more time than quick calls like GetBug.
+func WithMethodTimeouts(timeouts map[string]time.Duration) Option {
+	return func(c *client) {
+		c.methodTimeouts = timeouts
+	}
+}
+
+// WithCallTimeout returns a client whose requests have the timeout,
+// regardless of the method, for a call which is known to be slow or which
+// must be quick. The returned client shares all configuration and state with
+// the given one. Clients which do not support timeouts are returned as they
+// are.
+func WithCallTimeout(c Client, timeout time.Duration) Client {
+	original, ok := c.(*client)
+	if !ok {
+		return c
+	}
+	limited := *original
+	limited.callTimeout = &timeout
+	return &limited
+}
+
+// requestTimeout returns the timeout of the request logged with the logger,
+// or 0 if it has none
+func (c *client) requestTimeout(logger *logEntry) time.Duration {
+	if c.callTimeout != nil {
+		return *c.callTimeout
+	}
+	method, _ := logger.Data[methodField].(string)
+	if timeout, ok := c.methodTimeouts[method]; ok {
+		return timeout
+	}
+	return c.timeout
+}
+
+// withTimeout returns the request with the timeout of the request applied to
+// its context and a function releasing the context once the response is read
+func (c *client) withTimeout(req *http.Request, logger *logEntry) (*http.Request, context.CancelFunc) {
+	timeout := c.requestTimeout(logger)
+	if timeout <= 0 {
+		return req, func() {}
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	return req.WithContext(ctx), cancel
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimeouts(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write(bugData)
+	}))
+	defer testServer.Close()
+
+	var testCases = []struct {
+		name        string
+		options     []Option
+		callTimeout time.Duration
+		expectedErr bool
+	}{
+		{
+			name: "no timeout",
+		},
+		{
+			name:        "client timeout",
+			options:     []Option{WithTimeout(50 * time.Millisecond)},
+			expectedErr: true,
+		},
+		{
+			name:    "method timeout overrides the client timeout",
+			options: []Option{WithTimeout(50 * time.Millisecond), WithMethodTimeouts(map[string]time.Duration{"GetBug": 10 * time.Second})},
+		},
+		{
+			name:        "timeouts of other methods do not apply",
+			options:     []Option{WithTimeout(50 * time.Millisecond), WithMethodTimeouts(map[string]time.Duration{"Search": 10 * time.Second})},
+			expectedErr: true,
+		},
+		{
+			name:        "call timeout overrides the method timeout",
+			options:     []Option{WithMethodTimeouts(map[string]time.Duration{"GetBug": 10 * time.Second})},
+			callTimeout: 50 * time.Millisecond,
+			expectedErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := clientForUrl(testServer.URL).(*client)
+			for _, option := range testCase.options {
+				option(c)
+			}
+			var caller Client = c
+			if testCase.callTimeout != 0 {
+				caller = WithCallTimeout(c, testCase.callTimeout)
+			}
+			_, err := caller.GetBug(1705243)
+			if testCase.expectedErr != (err != nil) {
+				t.Errorf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+			}
+		})
+	}
+}
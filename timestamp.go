@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// timestampFormat is the format Bugzilla uses for times in the REST API
+const timestampFormat = "2006-01-02T15:04:05Z"
+
+// timestampLayouts are the formats accepted when decoding a Timestamp, which
+// include the formats used by older servers, the CSV export and the
+// dateTime.iso8601 values of XML-RPC
+var timestampLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02", "20060102T15:04:05", "20060102T15:04:05Z07:00"}
+
+// Timestamp is a time reported by Bugzilla. It embeds time.Time, so it can be
+// used like one, and encodes to and decodes from the formats Bugzilla uses.
+// A zero Timestamp means the time was not reported and encodes as null on
+// purpose: omitempty does not omit struct fields, so fields holding a
+// Timestamp are always encoded.
+type Timestamp struct {
+	time.Time
+}
+
+// NewTimestamp returns the Timestamp for the time
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Time: t}
+}
+
+// ParseTimestamp parses a time in one of the formats used by Bugzilla
+func ParseTimestamp(value string) (Timestamp, error) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return Timestamp{Time: t}, nil
+		}
+	}
+	return Timestamp{}, fmt.Errorf("could not parse time %q", value)
+}
+
+// String formats the time the way Bugzilla does, or returns an empty string
+// for a zero Timestamp
+func (t Timestamp) String() string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(timestampFormat)
+}
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.String())
+}
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("time must be a string: %v", err)
+	}
+	if value == "" {
+		*t = Timestamp{}
+		return nil
+	}
+	parsed, err := ParseTimestamp(value)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// MarshalYAML marshals the Timestamp like MarshalJSON does, for YAML
+// libraries which use the yaml struct tags
+func (t Timestamp) MarshalYAML() (interface{}, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	return t.String(), nil
+}
+
+// UnmarshalYAML unmarshals the Timestamp like UnmarshalJSON does, for YAML
+// libraries which use the yaml struct tags
+func (t *Timestamp) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value *string
+	if err := unmarshal(&value); err != nil {
+		return fmt.Errorf("time must be a string: %v", err)
+	}
+	if value == nil || *value == "" {
+		*t = Timestamp{}
+		return nil
+	}
+	parsed, err := ParseTimestamp(*value)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func mustParseTimestamp(value string) Timestamp {
+	t, err := ParseTimestamp(value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestTimestampJSON(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		raw          string
+		expected     time.Time
+		expectedJSON string
+		expectedErr  bool
+	}{
+		{
+			name:         "REST format",
+			raw:          `"2019-05-01T19:33:36Z"`,
+			expected:     time.Date(2019, 5, 1, 19, 33, 36, 0, time.UTC),
+			expectedJSON: `"2019-05-01T19:33:36Z"`,
+		},
+		{
+			name:         "time with offset is normalized to UTC",
+			raw:          `"2019-05-01T21:33:36+02:00"`,
+			expected:     time.Date(2019, 5, 1, 19, 33, 36, 0, time.UTC),
+			expectedJSON: `"2019-05-01T19:33:36Z"`,
+		},
+		{
+			name:         "CSV format",
+			raw:          `"2019-05-01 19:33:36"`,
+			expected:     time.Date(2019, 5, 1, 19, 33, 36, 0, time.UTC),
+			expectedJSON: `"2019-05-01T19:33:36Z"`,
+		},
+		{
+			name:         "date",
+			raw:          `"2019-05-01"`,
+			expected:     time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC),
+			expectedJSON: `"2019-05-01T00:00:00Z"`,
+		},
+		{
+			name:         "XML-RPC format",
+			raw:          `"20201018T02:38:45"`,
+			expected:     time.Date(2020, 10, 18, 2, 38, 45, 0, time.UTC),
+			expectedJSON: `"2020-10-18T02:38:45Z"`,
+		},
+		{
+			name:         "XML-RPC format with zone",
+			raw:          `"20201018T04:38:45+02:00"`,
+			expected:     time.Date(2020, 10, 18, 2, 38, 45, 0, time.UTC),
+			expectedJSON: `"2020-10-18T02:38:45Z"`,
+		},
+		{
+			name:         "null",
+			raw:          `null`,
+			expectedJSON: `null`,
+		},
+		{
+			name:         "empty",
+			raw:          `""`,
+			expectedJSON: `null`,
+		},
+		{
+			name:        "invalid",
+			raw:         `"yesterday"`,
+			expectedErr: true,
+		},
+		{
+			name:        "not a string",
+			raw:         `1556739216`,
+			expectedErr: true,
+		},
+	}
+	for _, testCase := range testCases {
+		var actual Timestamp
+		err := json.Unmarshal([]byte(testCase.raw), &actual)
+		if testCase.expectedErr != (err != nil) {
+			t.Errorf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+		}
+		if err != nil {
+			continue
+		}
+		if !actual.Equal(testCase.expected) {
+			t.Errorf("%s: expected %v, got %v", testCase.name, testCase.expected, actual.Time)
+		}
+		raw, err := json.Marshal(actual)
+		if err != nil {
+			t.Errorf("%s: expected no error, but got one: %v", testCase.name, err)
+		}
+		if string(raw) != testCase.expectedJSON {
+			t.Errorf("%s: expected JSON %s, got %s", testCase.name, testCase.expectedJSON, raw)
+		}
+	}
+}
+
+func TestBugTimestamps(t *testing.T) {
+	var bug Bug
+	if err := json.Unmarshal([]byte(`{"id":1,"creation_time":"2019-05-01T19:33:36Z","last_change_time":"2019-05-17T15:13:13Z"}`), &bug); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if !bug.LastChangeTime.After(bug.CreationTime.Time) {
+		t.Errorf("expected the bug to be changed after it was created, got %v and %v", bug.CreationTime, bug.LastChangeTime)
+	}
+	if bug.CreationTime.Year() != 2019 || bug.CreationTime.Month() != time.May {
+		t.Errorf("got incorrect creation time %v", bug.CreationTime)
+	}
+}
+
+func TestZeroTimestampsEncodeAsNull(t *testing.T) {
+	raw, err := json.Marshal(Comment{Id: 1})
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if value, present := decoded["time"]; !present || value != nil {
+		t.Errorf("expected the zero time to be encoded as null, got %s", raw)
+	}
+}
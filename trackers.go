@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// GetExternalTrackerTypes retrieves the external bug trackers configured on
+// the server, with their URLs and descriptions. The ExternalBugs extension
+// either returns the list of trackers or an object holding them as types.
+// This will be done via JSONRPC or XMLRPC, see WithRPCProtocol:
+// https://bugzilla.redhat.com/docs/en/html/integrating/api/Bugzilla/Extension/ExternalBugs/WebService.html
+func (c *client) GetExternalTrackerTypes() ([]ExternalBugType, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "GetExternalTrackerTypes"})
+	params := struct {
+		APIKey string `json:"api_key"`
+	}{APIKey: string(c.getAPIKey())}
+	var result json.RawMessage
+	if err := c.rpcClient(logger).Call("ExternalBugs.get_ext_types", params, &result); err != nil {
+		return nil, err
+	}
+	var types []ExternalBugType
+	if err := json.Unmarshal(result, &types); err == nil {
+		return types, nil
+	}
+	var wrapped struct {
+		Types []ExternalBugType `json:"types"`
+	}
+	if err := json.Unmarshal(result, &wrapped); err != nil {
+		return nil, fmt.Errorf("could not unmarshal external tracker types: %v", err)
+	}
+	return wrapped.Types, nil
+}
+
+// ResolveExternalTracker retrieves the external bug tracker with the name,
+// which matches the description, like "Github", the type, like "GitHub", or
+// the URL, like "https://github.com/", of the tracker without regard to case.
+// It responds with an error that matches IsNotFound if no tracker matches.
+func ResolveExternalTracker(c Client, name string) (*ExternalBugType, error) {
+	types, err := c.GetExternalTrackerTypes()
+	if err != nil {
+		return nil, err
+	}
+	for i := range types {
+		tracker := &types[i]
+		if strings.EqualFold(name, tracker.Description) || strings.EqualFold(name, tracker.Type) || strings.EqualFold(name, tracker.URL) {
+			return tracker, nil
+		}
+	}
+	return nil, &RequestError{StatusCode: http.StatusNotFound, Message: fmt.Sprintf("no external tracker named %q", name)}
+}
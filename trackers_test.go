@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestGetExternalTrackerTypes(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		result string
+	}{
+		{
+			name:   "trackers returned as a list",
+			result: `[{"id":1,"description":"Github","type":"GitHub","url":"https://github.com/","full_url":"https://github.com/%id%"}]`,
+		},
+		{
+			name:   "trackers returned as types",
+			result: `{"types":[{"id":1,"description":"Github","type":"GitHub","url":"https://github.com/","full_url":"https://github.com/%id%"}]}`,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				raw, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("failed to read request body: %v", err)
+				}
+				expected := `{"jsonrpc":"1.0","method":"ExternalBugs.get_ext_types","params":[{"api_key":"api-key"}],"id":"identifier"}`
+				if actual := string(raw); actual != expected {
+					t.Errorf("%s: got incorrect JSONRPC payload: %v", testCase.name, diff.ObjectReflectDiff(expected, actual))
+				}
+				w.Write([]byte(`{"error":null,"id":"identifier","result":` + testCase.result + `}`))
+			}))
+			defer testServer.Close()
+			client := clientForUrl(testServer.URL)
+
+			types, err := client.GetExternalTrackerTypes()
+			if err != nil {
+				t.Fatalf("%s: expected no error, but got one: %v", testCase.name, err)
+			}
+			expected := []ExternalBugType{{ID: 1, Description: "Github", Type: "GitHub", URL: GitHubTracker, FullURL: "https://github.com/%id%"}}
+			if !reflect.DeepEqual(types, expected) {
+				t.Errorf("%s: got incorrect tracker types: %v", testCase.name, diff.ObjectReflectDiff(expected, types))
+			}
+		})
+	}
+}
+
+func TestResolveExternalTracker(t *testing.T) {
+	fake := &Fake{ExternalTrackerTypes: []ExternalBugType{
+		{ID: 1, Description: "Github", Type: "GitHub", URL: GitHubTracker},
+		{ID: 2, Description: "Red Hat Issue Tracker", Type: "JIRA", URL: JiraTracker},
+	}}
+	for _, name := range []string{"jira", "Red Hat Issue Tracker", JiraTracker} {
+		tracker, err := ResolveExternalTracker(fake, name)
+		if err != nil {
+			t.Fatalf("%s: expected no error, but got one: %v", name, err)
+		}
+		if tracker.ID != 2 {
+			t.Errorf("%s: resolved the wrong tracker: %v", name, tracker)
+		}
+	}
+	if _, err := ResolveExternalTracker(fake, "gitlab"); !IsNotFound(err) {
+		t.Errorf("expected a not found error for an unknown tracker, got %v", err)
+	}
+}
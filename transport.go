@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// WithHTTPClient sends requests with the given HTTP client instead of a
+// default one, e.g. to set a timeout or a cookie jar. Options applied after
+// this one which configure the transport modify a copy of the client, the
+// given client is never changed.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *client) {
+		c.client = httpClient
+	}
+}
+
+// WithTransport sends requests through the given round tripper, e.g. to use
+// a proxy or to instrument requests.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *client) {
+		httpClient := *c.client
+		httpClient.Transport = transport
+		c.client = &httpClient
+	}
+}
+
+// WithTLSConfig uses the given TLS configuration for connections to the
+// server, e.g. to trust a private certificate authority or to present a
+// client certificate. TLS options are applied once all options ran, to the
+// final transport, see withTLSConfig.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *client) {
+		c.tlsUpdates = append(c.tlsUpdates, func(*tls.Config) *tls.Config {
+			return config
+		})
+	}
+}
+
+// WithCABundle trusts the certificate authorities in the PEM encoded bundle
+// at the path, instead of the system roots, to verify the server. The bundle
+// is read when the option is created.
+func WithCABundle(path string) (Option, error) {
+	bundle, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read CA bundle: %v", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(bundle) {
+		return nil, fmt.Errorf("no certificates found in CA bundle %s", path)
+	}
+	return func(c *client) {
+		c.tlsUpdates = append(c.tlsUpdates, func(config *tls.Config) *tls.Config {
+			config.RootCAs = roots
+			return config
+		})
+	}, nil
+}
+
+// WithClientCertificate presents the PEM encoded certificate and key at the
+// paths to the server, for servers which authenticate clients with mutual
+// TLS. The pair is read when the option is created.
+func WithClientCertificate(certFile, keyFile string) (Option, error) {
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not load client certificate: %v", err)
+	}
+	return func(c *client) {
+		c.tlsUpdates = append(c.tlsUpdates, func(config *tls.Config) *tls.Config {
+			config.Certificates = []tls.Certificate{certificate}
+			return config
+		})
+	}, nil
+}
+
+// applyTLSConfig replaces the transport with a copy using the TLS
+// configuration of the TLS options, in the order they were given, so options
+// like WithCABundle and WithClientCertificate can be combined. It runs after
+// all options, so the order of the TLS options and WithTransport does not
+// matter. If the transport can not be configured, the error is kept and
+// returned by every request instead of silently dropping the TLS settings.
+func (c *client) applyTLSConfig() {
+	if len(c.tlsUpdates) == 0 {
+		return
+	}
+	transport, err := withTLSConfig(c.client.Transport, func(config *tls.Config) *tls.Config {
+		for _, update := range c.tlsUpdates {
+			config = update(config)
+		}
+		return config
+	})
+	if err != nil {
+		c.optionsErr = err
+		return
+	}
+	WithTransport(transport)(c)
+}
+
+// withTLSConfig returns a copy of the transport using the TLS configuration
+// returned by update, which gets a copy of the current one. Transports of
+// this package which wrap another one, like the CompressingTransport, are
+// copied with a copy of the wrapped one, and a copy of http.DefaultTransport
+// is used if there is no transport. Other transports can not be configured,
+// so an error is returned for them.
+func withTLSConfig(transport http.RoundTripper, update func(*tls.Config) *tls.Config) (http.RoundTripper, error) {
+	switch t := transport.(type) {
+	case nil:
+		return withTLSConfig(http.DefaultTransport, update)
+	case *http.Transport:
+		t = t.Clone()
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig = update(t.TLSClientConfig)
+		return t, nil
+	case *CompressingTransport:
+		base, err := withTLSConfig(t.Base, update)
+		if err != nil {
+			return nil, err
+		}
+		return &CompressingTransport{Base: base, MinRequestSize: t.MinRequestSize}, nil
+	case *CachingTransport:
+		base, err := withTLSConfig(t.Base, update)
+		if err != nil {
+			return nil, err
+		}
+		return &CachingTransport{Base: base, TTL: t.TTL, MaxEntries: t.MaxEntries, MaxEntrySize: t.MaxEntrySize, Clock: t.Clock}, nil
+	}
+	return nil, fmt.Errorf("can not apply the TLS options to transport %T, configure TLS on the transport instead", transport)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type countingTransport struct {
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestTransportOptions(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bugData)
+	}))
+	defer testServer.Close()
+	roots := x509.NewCertPool()
+	roots.AddCert(testServer.Certificate())
+
+	if _, err := NewClient(nil, testServer.URL).GetBug(1705243); err == nil {
+		t.Error("expected the server certificate not to be trusted by default")
+	}
+	if _, err := NewClient(nil, testServer.URL, WithTLSConfig(&tls.Config{RootCAs: roots})).GetBug(1705243); err != nil {
+		t.Errorf("expected the server certificate to be trusted with the TLS config, got %v", err)
+	}
+
+	httpClient := &http.Client{Timeout: time.Minute}
+	c := NewClient(nil, testServer.URL, WithHTTPClient(httpClient), WithCompression(1), WithResponseCache(time.Hour), WithTLSConfig(&tls.Config{RootCAs: roots}))
+	if _, err := c.GetBug(1705243); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if cache, ok := c.(*client).client.Transport.(*CachingTransport); !ok {
+		t.Errorf("expected the TLS config to keep the response cache, got transport %T", c.(*client).client.Transport)
+	} else if compression, ok := cache.Base.(*CompressingTransport); !ok || compression.MinRequestSize != 1 {
+		t.Errorf("expected the TLS config to keep the compression, got transport %T", cache.Base)
+	}
+	if httpClient.Transport != nil {
+		t.Error("expected the given HTTP client not to be modified")
+	}
+	if c.(*client).client.Timeout != time.Minute {
+		t.Error("expected the HTTP client configuration to be kept")
+	}
+
+	transport := &countingTransport{}
+	for _, opts := range [][]Option{
+		{WithTransport(transport), WithTLSConfig(&tls.Config{RootCAs: roots})},
+		{WithTLSConfig(&tls.Config{RootCAs: roots}), WithTransport(transport)},
+	} {
+		c = NewClient(nil, testServer.URL, opts...)
+		if _, err := c.GetBug(1705243); err == nil || !strings.Contains(err.Error(), "can not apply the TLS options") {
+			t.Errorf("expected the TLS options to fail for a transport they can not configure, got %v", err)
+		}
+	}
+	if transport.requests != 0 {
+		t.Errorf("expected no request without the TLS options, got %d", transport.requests)
+	}
+
+	c = NewClient(nil, testServer.URL, WithTransport(transport), WithHTTPClient(&http.Client{}), WithCompression(1), WithTLSConfig(&tls.Config{RootCAs: roots}))
+	if _, err := c.GetBug(1705243); err != nil {
+		t.Errorf("expected the TLS options to apply to the final transport, got %v", err)
+	}
+}
+
+// writeClientCertificate writes a self-signed client certificate and its key
+// to the directory and returns the certificate and the paths to both files
+func writeClientCertificate(t *testing.T, dir string) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "bugzilla-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("could not create certificate: %v", err)
+	}
+	certificate, err := x509.ParseCertificate(raw)
+	if err != nil {
+		t.Fatalf("could not parse certificate: %v", err)
+	}
+	rawKey, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("could not marshal key: %v", err)
+	}
+	certFile, keyFile := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw}), 0600); err != nil {
+		t.Fatalf("could not write certificate: %v", err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: rawKey}), 0600); err != nil {
+		t.Fatalf("could not write key: %v", err)
+	}
+	return certificate, certFile, keyFile
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls")
+	if err != nil {
+		t.Fatalf("could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	clientCertificate, certFile, keyFile := writeClientCertificate(t, dir)
+
+	testServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bugData)
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCertificate)
+	testServer.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	testServer.StartTLS()
+	defer testServer.Close()
+	bundle := filepath.Join(dir, "ca.pem")
+	if err := ioutil.WriteFile(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: testServer.Certificate().Raw}), 0600); err != nil {
+		t.Fatalf("could not write CA bundle: %v", err)
+	}
+
+	withCABundle, err := WithCABundle(bundle)
+	if err != nil {
+		t.Fatalf("expected no error loading the CA bundle, got %v", err)
+	}
+	withClientCertificate, err := WithClientCertificate(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("expected no error loading the client certificate, got %v", err)
+	}
+	if _, err := NewClient(nil, testServer.URL, withCABundle).GetBug(1705243); err == nil {
+		t.Error("expected the server to reject a client without a certificate")
+	}
+	if _, err := NewClient(nil, testServer.URL, withCABundle, withClientCertificate).GetBug(1705243); err != nil {
+		t.Errorf("expected no error with the CA bundle and client certificate, got %v", err)
+	}
+
+	if _, err := WithCABundle(keyFile); err == nil {
+		t.Error("expected an error loading a CA bundle without certificates")
+	}
+	if _, err := WithClientCertificate(certFile, bundle); err == nil {
+		t.Error("expected an error loading a certificate with a file which is not a key")
+	}
+}
@@ -18,103 +18,117 @@ package bugzilla
 
 // Bug is a record of a bug. See API documentation at:
 // https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#get-bug
+// When only a subset of fields is requested from the server, fields that were
+// not returned are left at their zero value.
 type Bug struct {
 	// ActualTime is the total number of hours that this bug has taken so far. If you are not in the time-tracking group, this field will not be included in the return value.
-	ActualTime int `json:"actual_time,omitempty"`
+	ActualTime int `json:"actual_time,omitempty" yaml:"actual_time,omitempty"`
 	// Alias is the unique aliases of this bug. An empty array will be returned if this bug has no aliases.
-	Alias []string `json:"alias,omitempty"`
+	Alias []string `json:"alias,omitempty" yaml:"alias,omitempty"`
 	// AssignedTo is the login name of the user to whom the bug is assigned.
-	AssignedTo string `json:"assigned_to,omitempty"`
+	AssignedTo string `json:"assigned_to,omitempty" yaml:"assigned_to,omitempty"`
 	// AssignedToDetail is an object containing detailed user information for the assigned_to. To see the keys included in the user detail object, see below.
-	AssignedToDetail *User `json:"assigned_to_detail,omitempty"`
+	AssignedToDetail *User `json:"assigned_to_detail,omitempty" yaml:"assigned_to_detail,omitempty"`
 	// Blocks is the IDs of bugs that are "blocked" by this bug.
-	Blocks []int `json:"blocks,omitempty"`
+	Blocks []int `json:"blocks,omitempty" yaml:"blocks,omitempty"`
 	// CC is the login names of users on the CC list of this bug.
-	CC []string `json:"cc,omitempty"`
+	CC []string `json:"cc,omitempty" yaml:"cc,omitempty"`
 	// CCDetail is array of objects containing detailed user information for each of the cc list members. To see the keys included in the user detail object, see below.
-	CCDetail []User `json:"cc_detail,omitempty"`
+	CCDetail []User `json:"cc_detail,omitempty" yaml:"cc_detail,omitempty"`
 	// Classification is the name of the current classification the bug is in.
-	Classification string `json:"classification,omitempty"`
+	Classification string `json:"classification,omitempty" yaml:"classification,omitempty"`
 	// Component is an array of names of the current components of this bug.
-	Component []string `json:"component,omitempty"`
+	Component []string `json:"component,omitempty" yaml:"component,omitempty"`
 	// CreationTime is when the bug was created.
-	CreationTime string `json:"creation_time,omitempty"`
+	CreationTime Timestamp `json:"creation_time" yaml:"creation_time,omitempty"`
 	// Creator is the login name of the person who filed this bug (the reporter).
-	Creator string `json:"creator,omitempty"`
+	Creator string `json:"creator,omitempty" yaml:"creator,omitempty"`
 	// CreatorDetail is an object containing detailed user information for the creator. To see the keys included in the user detail object, see below.
-	CreatorDetail *User `json:"creator_detail,omitempty"`
+	CreatorDetail *User `json:"creator_detail,omitempty" yaml:"creator_detail,omitempty"`
 	// Deadline is the day that this bug is due to be completed, in the format YYYY-MM-DD.
-	Deadline string `json:"deadline,omitempty"`
+	Deadline string `json:"deadline,omitempty" yaml:"deadline,omitempty"`
 	// DependsOn is the IDs of bugs that this bug "depends on".
-	DependsOn []int `json:"depends_on,omitempty"`
+	DependsOn []int `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+	// DocsContact is the login name of the user who writes the documentation for this bug. Not all bugzilla instances support this field.
+	DocsContact string `json:"docs_contact,omitempty" yaml:"docs_contact,omitempty"`
 	// DupeOf is the bug ID of the bug that this bug is a duplicate of. If this bug isn't a duplicate of any bug, this will be null.
-	DupeOf int `json:"dupe_of,omitempty"`
+	DupeOf int `json:"dupe_of,omitempty" yaml:"dupe_of,omitempty"`
 	// EstimatedTime is the number of hours that it was estimated that this bug would take. If you are not in the time-tracking group, this field will not be included in the return value.
-	EstimatedTime int `json:"estimated_time,omitempty"`
+	EstimatedTime int `json:"estimated_time,omitempty" yaml:"estimated_time,omitempty"`
 	// Flags is an array of objects containing the information about flags currently set for the bug. Each flag objects contains the following items
-	Flags []Flag `json:"flags,omitempty"`
+	Flags []Flag `json:"flags,omitempty" yaml:"flags,omitempty"`
 	// Groups is the names of all the groups that this bug is in.
-	Groups []string `json:"groups,omitempty"`
+	Groups []string `json:"groups,omitempty" yaml:"groups,omitempty"`
 	// ID is the unique numeric ID of this bug.
-	ID int `json:"id,omitempty"`
+	ID int `json:"id,omitempty" yaml:"id,omitempty"`
 	// IsCCAccessible is if true, this bug can be accessed by members of the CC list, even if they are not in the groups the bug is restricted to.
-	IsCCAccessible bool `json:"is_cc_accessible,omitempty"`
+	IsCCAccessible bool `json:"is_cc_accessible,omitempty" yaml:"is_cc_accessible,omitempty"`
 	// IsConfirmed is true if the bug has been confirmed. Usually this means that the bug has at some point been moved out of the UNCONFIRMED status and into another open status.
-	IsConfirmed bool `json:"is_confirmed,omitempty"`
+	IsConfirmed bool `json:"is_confirmed,omitempty" yaml:"is_confirmed,omitempty"`
 	// IsOpen is true if this bug is open, false if it is closed.
-	IsOpen bool `json:"is_open,omitempty"`
+	IsOpen bool `json:"is_open,omitempty" yaml:"is_open,omitempty"`
 	// IsCreatorAccessible is if true, this bug can be accessed by the creator of the bug, even if they are not a member of the groups the bug is restricted to.
-	IsCreatorAccessible bool `json:"is_creator_accessible,omitempty"`
+	IsCreatorAccessible bool `json:"is_creator_accessible,omitempty" yaml:"is_creator_accessible,omitempty"`
 	// Keywords is each keyword that is on this bug.
-	Keywords []string `json:"keywords,omitempty"`
+	Keywords []string `json:"keywords,omitempty" yaml:"keywords,omitempty"`
 	// LastChangeTime is when the bug was last changed.
-	LastChangeTime string `json:"last_change_time,omitempty"`
+	LastChangeTime Timestamp `json:"last_change_time" yaml:"last_change_time,omitempty"`
 	// OperatingSystem is the name of the operating system that the bug was filed against.
-	OperatingSystem string `json:"op_sys,omitempty"`
+	OperatingSystem string `json:"op_sys,omitempty" yaml:"op_sys,omitempty"`
 	// Platform is the name of the platform (hardware) that the bug was filed against.
-	Platform string `json:"platform,omitempty"`
+	Platform string `json:"platform,omitempty" yaml:"platform,omitempty"`
 	// Priority is the priority of the bug.
-	Priority string `json:"priority,omitempty"`
+	Priority string `json:"priority,omitempty" yaml:"priority,omitempty"`
 	// Product is the name of the product this bug is in.
-	Product string `json:"product,omitempty"`
+	Product string `json:"product,omitempty" yaml:"product,omitempty"`
 	// PMScore is the score assigned which tries to account for many factors
-	PMScore string `json:"cf_pm_score,omitempty"`
+	PMScore string `json:"cf_pm_score,omitempty" yaml:"cf_pm_score,omitempty"`
 	// QAContact is the login name of the current QA Contact on the bug.
-	QAContact string `json:"qa_contact,omitempty"`
+	QAContact string `json:"qa_contact,omitempty" yaml:"qa_contact,omitempty"`
 	// QAContactDetail is an object containing detailed user information for the qa_contact. To see the keys included in the user detail object, see below.
-	QAContactDetail *User `json:"qa_contact_detail,omitempty"`
+	QAContactDetail *User `json:"qa_contact_detail,omitempty" yaml:"qa_contact_detail,omitempty"`
 	// RemainingTime is the number of hours of work remaining until work on this bug is complete. If you are not in the time-tracking group, this field will not be included in the return value.
-	RemainingTime int `json:"remaining_time,omitempty"`
+	RemainingTime int `json:"remaining_time,omitempty" yaml:"remaining_time,omitempty"`
 	// Resolution is the current resolution of the bug, or an empty string if the bug is open.
-	Resolution string `json:"resolution,omitempty"`
+	Resolution string `json:"resolution,omitempty" yaml:"resolution,omitempty"`
 	// SeeAlso is the URLs in the See Also field on the bug.
-	SeeAlso []string `json:"see_also,omitempty"`
+	SeeAlso []string `json:"see_also,omitempty" yaml:"see_also,omitempty"`
 	// Severity is the current severity of the bug.
-	Severity string `json:"severity,omitempty"`
+	Severity string `json:"severity,omitempty" yaml:"severity,omitempty"`
 	// Status is the current status of the bug.
-	Status string `json:"status,omitempty"`
+	Status string `json:"status,omitempty" yaml:"status,omitempty"`
 	// SubComponent is the subcomponent for a given component. Not all bugzilla instances support this field.
-	SubComponent map[string][]string `json:"sub_components,omitempty"`
+	SubComponent map[string][]string `json:"sub_components,omitempty" yaml:"sub_components,omitempty"`
 	// Summary is the summary of this bug.
-	Summary string `json:"summary,omitempty"`
+	Summary string `json:"summary,omitempty" yaml:"summary,omitempty"`
 	// TargetMilestone is the milestone that this bug is supposed to be fixed by, or for closed bugs, the milestone that it was fixed for.
-	TargetMilestone string `json:"target_milestone,omitempty"`
+	TargetMilestone string `json:"target_milestone,omitempty" yaml:"target_milestone,omitempty"`
 	// TargetRelease are the releases that the bug will be fixed in.
-	TargetRelease []string `json:"target_release,omitempty"`
+	TargetRelease []string `json:"target_release,omitempty" yaml:"target_release,omitempty"`
 	// UpdateToken is the token that you would have to pass to the process_bug.cgi page in order to update this bug. This changes every time the bug is updated. This field is not returned to logged-out users.
-	UpdateToken string `json:"update_token,omitempty"`
+	UpdateToken string `json:"update_token,omitempty" yaml:"update_token,omitempty"`
 	// URL is a URL that demonstrates the problem described in the bug, or is somehow related to the bug report.
-	URL string `json:"url,omitempty"`
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
 	// Version are the versions the bug was reported against.
-	Version []string `json:"version,omitempty"`
+	Version []string `json:"version,omitempty" yaml:"version,omitempty"`
 	// Whiteboard is he value of the "status whiteboard" field on the bug.
-	Whiteboard string `json:"whiteboard,omitempty"`
+	Whiteboard string `json:"whiteboard,omitempty" yaml:"whiteboard,omitempty"`
 	// DevelWhiteboard is the value of the "devel whiteboard" field on the bug.
-	DevelWhiteboard string `json:"cf_devel_whiteboard,omitempty"`
+	DevelWhiteboard string `json:"cf_devel_whiteboard,omitempty" yaml:"cf_devel_whiteboard,omitempty"`
 	// Escalation is set to "Yes" when this bug is escalated.
-	Escalation string `json:"cf_cust_facing,omitempty"`
+	Escalation string `json:"cf_cust_facing,omitempty" yaml:"cf_cust_facing,omitempty"`
 	// ExternalBugs is a list of references to other trackers.
-	ExternalBugs []ExternalBug `json:"external_bugs,omitempty"`
+	ExternalBugs []ExternalBug `json:"external_bugs,omitempty" yaml:"external_bugs,omitempty"`
+	// Verified is the value of the RHEL-style "Verified" multi-select field, recording how QE verified the bug.
+	Verified []VerifiedValue `json:"cf_verified,omitempty" yaml:"cf_verified,omitempty"`
+
+	// CustomFields are the values of the custom fields which Bug has no field for, keyed by their
+	// name, like "cf_doc_type". Values are decoded as by json.Unmarshal into an interface{}.
+	CustomFields map[string]interface{} `json:"-" yaml:"-"`
+
+	// Nullable holds the fields which the server may return as null, keeping null values apart
+	// from empty ones. Only set by clients created WithStrictNulls.
+	Nullable *NullableFields `json:"-" yaml:"-"`
 }
 
 type Comment struct {
@@ -131,11 +145,11 @@ type Comment struct {
 	// The login name of the comment's author.
 	Creator string `json:"creator,omitempty"`
 	// The time (in Bugzilla's timezone) that the comment was added.
-	Time string `json:"time,omitempty"`
+	Time Timestamp `json:"time"`
 	// This is exactly same as the time key. Use this field instead of time for consistency with other methods including Get Bug and Get Attachment.
 	//
 	// 	For compatibility, time is still usable. However, please note that time may be deprecated and removed in a future release.
-	CreationTime string `json:"creation_time,omitempty"`
+	CreationTime Timestamp `json:"creation_time"`
 	// true if this comment is private (only visible to a certain group called the "insidergroup"), false otherwise.
 	IsPrivate bool `json:"is_private,omitempty"`
 	// true if this comment needs Markdown processing; false otherwise.
@@ -144,9 +158,58 @@ type Comment struct {
 	Tags []string `json:"tags,omitempty"`
 }
 
+// Attachment holds the metadata of an attachment, without its data
+type Attachment struct {
+	// The ID of the attachment.
+	ID int `json:"id,omitempty"`
+	// The ID of the bug this attachment is on.
+	BugID int `json:"bug_id,omitempty"`
+	// The file name of the attachment.
+	FileName string `json:"file_name,omitempty"`
+	// A short string describing the attachment.
+	Summary string `json:"summary,omitempty"`
+	// The MIME type of the attachment.
+	ContentType string `json:"content_type,omitempty"`
+	// The length in bytes of the attachment.
+	Size int `json:"size,omitempty"`
+	// The login name of the user that created the attachment.
+	Creator string `json:"creator,omitempty"`
+	// When the attachment was created.
+	CreationTime Timestamp `json:"creation_time"`
+	// When the attachment was last changed.
+	LastChangeTime Timestamp `json:"last_change_time"`
+	// true if the attachment is private, false otherwise.
+	IsPrivate bool `json:"is_private,omitempty"`
+	// true if the attachment is obsolete, false otherwise.
+	IsObsolete bool `json:"is_obsolete,omitempty"`
+	// true if the attachment is a patch, false otherwise.
+	IsPatch bool `json:"is_patch,omitempty"`
+	// The flags set on the attachment.
+	Flags []Flag `json:"flags,omitempty"`
+}
+
+// AttachmentCreate contains the fields of a new attachment. See API documentation at:
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/attachment.html#create-attachment
+type AttachmentCreate struct {
+	// FileName is the file name of the attachment.
+	FileName string `json:"file_name"`
+	// Summary is a short string describing the attachment.
+	Summary string `json:"summary"`
+	// ContentType is the MIME type of the attachment, like text/plain.
+	ContentType string `json:"content_type"`
+	// Data is the content of the attachment, sent base64 encoded.
+	Data []byte `json:"data"`
+	// Comment is a comment to add to the bug along with the attachment.
+	Comment string `json:"comment,omitempty"`
+	// IsPatch is true if the attachment is a patch.
+	IsPatch bool `json:"is_patch,omitempty"`
+	// IsPrivate makes the attachment private.
+	IsPrivate bool `json:"is_private,omitempty"`
+}
+
 type History struct {
 	// The date the bug activity/change happened.
-	When string `json:"when,omitempty"`
+	When Timestamp `json:"when"`
 	// The login name of the user who performed the bug change.
 	Who string `json:"who,omitempty"`
 	// An array of Change objects which contain all the changes that happened to the bug at this time (as specified by when).
@@ -167,33 +230,50 @@ type HistoryChange struct {
 // User holds information about a user
 type User struct {
 	// The user ID for this user.
-	ID int `json:"id,omitempty"`
+	ID int `json:"id,omitempty" yaml:"id,omitempty"`
 	// The 'real' name for this user, if any.
-	RealName string `json:"real_name,omitempty"`
+	RealName string `json:"real_name,omitempty" yaml:"real_name,omitempty"`
 	// The user's Bugzilla login.
-	Name string `json:"name,omitempty"`
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
 	// The user's e-mail.
-	Email string `json:"email,omitempty"`
+	Email string `json:"email,omitempty" yaml:"email,omitempty"`
+	// Groups are the groups the user is a member of, only set by GetUser.
+	Groups []Group `json:"groups,omitempty" yaml:"groups,omitempty"`
+}
+
+// Group holds information about a group, which can restrict who can see bugs
+type Group struct {
+	// ID is the ID of the group.
+	ID int `json:"id,omitempty"`
+	// Name is the name of the group.
+	Name string `json:"name,omitempty"`
+	// Description is the description of the group.
+	Description string `json:"description,omitempty"`
+	// IsActive is true if bugs can be added to the group.
+	IsActive bool `json:"is_active,omitempty"`
+	// UserRegExp is the regular expression matching the logins of the users
+	// who are automatically members of the group.
+	UserRegExp string `json:"user_regexp,omitempty"`
 }
 
 // Flag holds information about a flag set on a bug
 type Flag struct {
 	// The ID of the flag.
-	ID int `json:"id,omitempty"`
+	ID int `json:"id,omitempty" yaml:"id,omitempty"`
 	// The name of the flag.
-	Name string `json:"name,omitempty"`
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
 	// The type ID of the flag.
-	TypeID int `json:"type_id,omitempty"`
+	TypeID int `json:"type_id,omitempty" yaml:"type_id,omitempty"`
 	// The timestamp when this flag was originally created.
-	CreationDate string `json:"creation_date,omitempty"`
+	CreationDate string `json:"creation_date,omitempty" yaml:"creation_date,omitempty"`
 	// The timestamp when the flag was last modified.
-	ModificationDate string `json:"modification_date,omitempty"`
+	ModificationDate string `json:"modification_date,omitempty" yaml:"modification_date,omitempty"`
 	// The current status of the flag.
-	Status string `json:"status,omitempty"`
+	Status string `json:"status,omitempty" yaml:"status,omitempty"`
 	// The login name of the user who created or last modified the flag.
-	Setter string `json:"setter,omitempty"`
+	Setter string `json:"setter,omitempty" yaml:"setter,omitempty"`
 	// The login name of the user this flag has been requested to be granted or denied. Note, this field is only returned if a requestee is set.
-	Requestee string `json:"requestee,omitempty"`
+	Requestee string `json:"requestee,omitempty" yaml:"requestee,omitempty"`
 }
 
 // BugComment contains the fields used when updating a comment on a Bug. See API documentation at:
@@ -204,10 +284,14 @@ type BugComment struct {
 	Markdown bool   `json:"is_markdown,omitempty"`
 }
 
+// BugKeywords changes the keywords of a bug when updating it
 type BugKeywords struct {
-	Add    []string `json:"add,omitempty"`
+	// Add are the keywords to add to the bug.
+	Add []string `json:"add,omitempty"`
+	// Remove are the keywords to remove from the bug.
 	Remove []string `json:"remove,omitempty"`
-	Set    []string `json:"set,omitempty"`
+	// Set are the keywords to replace the keywords of the bug with.
+	Set []string `json:"set,omitempty"`
 }
 
 // BugList holds a list of bugs. This is a normal response from the /rest/bugs/ api call
@@ -215,29 +299,287 @@ type BugList struct {
 	Bugs []Bug `json:"bugs,omitempty"`
 }
 
+// FlagChange sets, requests or clears a flag when updating a bug
 type FlagChange struct {
+	// The name of the flag.
 	Name string `json:"name,omitempty"`
 	// The flags new status (i.e. "?", "+", "-" or "X" to clear a flag).
-	Status    string `json:"status,omitempty"`
+	Status string `json:"status,omitempty"`
+	// The login name of the user asked to decide a requested flag.
 	Requestee string `json:"requestee,omitempty"`
 }
 
 // BugUpdate contains fields to update on a Bug. See API documentation at:
 // https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#update-bug
+// Fields left at their zero value are omitted from the update and are not
+// changed on the server.
 type BugUpdate struct {
 	// Status is the current status of the bug.
-	Status        string       `json:"status,omitempty"`
-	Resolution    string       `json:"resolution,omitempty"`
-	TargetRelease string       `json:"target_release,omitempty"`
-	DevWhiteboard string       `json:"cf_devel_whiteboard,omitempty"`
-	Whiteboard    string       `json:"whiteboard,omitempty"`
-	Comment       *BugComment  `json:"comment,omitempty"`
-	Keywords      *BugKeywords `json:"keywords,omitempty"`
-	Flags         []FlagChange `json:"flags,omitempty"`
-	Priority      string       `json:"priority,omitempty"`
-	Severity      string       `json:"severity,omitempty"`
-	MinorUpdate   bool         `json:"minor_update,omitempty"`
-	AssignedTo    string       `json:"assigned_to,omitempty"`
+	Status string `json:"status,omitempty"`
+	// Resolution is the current resolution of the bug, only valid for closed statuses.
+	Resolution string `json:"resolution,omitempty"`
+	// TargetRelease is the release that the bug will be fixed in.
+	TargetRelease string `json:"target_release,omitempty"`
+	// TargetMilestone is the milestone that the bug will be fixed by.
+	TargetMilestone string `json:"target_milestone,omitempty"`
+	// Summary is the summary of the bug.
+	Summary string `json:"summary,omitempty"`
+	// Product is the name of the product the bug is in.
+	Product string `json:"product,omitempty"`
+	// Component is the name of the component the bug is in.
+	Component string `json:"component,omitempty"`
+	// Version is the version the bug was reported against.
+	Version string `json:"version,omitempty"`
+	// URL is a URL that demonstrates the problem described in the bug.
+	URL string `json:"url,omitempty"`
+	// DevWhiteboard is the value of the "devel whiteboard" field on the bug.
+	DevWhiteboard string `json:"cf_devel_whiteboard,omitempty"`
+	// Whiteboard is the value of the "status whiteboard" field on the bug.
+	Whiteboard string `json:"whiteboard,omitempty"`
+	// Alias are the aliases to add to, remove from or set on the bug.
+	Alias *BugAliases `json:"alias,omitempty"`
+	// Comment is a comment to add to the bug along with the update.
+	Comment *BugComment `json:"comment,omitempty"`
+	// Keywords are the keywords to add, remove or set on the bug.
+	Keywords *BugKeywords `json:"keywords,omitempty"`
+	// CC are the users to add to or remove from the CC list of the bug.
+	CC *BugCC `json:"cc,omitempty"`
+	// DependsOn are the bugs to add to, remove from or set as the bugs this bug depends on.
+	DependsOn *BugIDs `json:"depends_on,omitempty"`
+	// Blocks are the bugs to add to, remove from or set as the bugs this bug blocks.
+	Blocks *BugIDs `json:"blocks,omitempty"`
+	// Flags are the changes to make to the flags on the bug.
+	Flags []FlagChange `json:"flags,omitempty"`
+	// Priority is the priority of the bug.
+	Priority string `json:"priority,omitempty"`
+	// Severity is the severity of the bug.
+	Severity string `json:"severity,omitempty"`
+	// MinorUpdate is true if this update should not send out e-mail notifications.
+	// It is left out for servers older than Bugzilla 5.0, which do not support it.
+	MinorUpdate bool `json:"minor_update,omitempty"`
+	// SubComponents sets the sub-component of components, keyed by component. Only
+	// Red Hat's Bugzilla supports sub-components, for other servers they are left out.
+	SubComponents map[string][]string `json:"sub_components,omitempty"`
+	// AssignedTo is the login name of the user to whom the bug is assigned.
+	AssignedTo string `json:"assigned_to,omitempty"`
+	// QAContact is the login name of the QA contact of the bug.
+	QAContact string `json:"qa_contact,omitempty"`
+	// ResetAssignedTo resets the assignee of the bug to the default assignee of the component.
+	ResetAssignedTo bool `json:"reset_assigned_to,omitempty"`
+	// ResetQAContact resets the QA contact of the bug to the default QA contact of the component.
+	ResetQAContact bool `json:"reset_qa_contact,omitempty"`
+	// Deadline is the day the bug is due to be completed, in the format YYYY-MM-DD. Point to an empty string to clear it.
+	Deadline *string `json:"deadline,omitempty"`
+	// DupeOf is the ID of the bug this bug is a duplicate of.
+	DupeOf *int `json:"dupe_of,omitempty"`
+	// LastChangeTime, if set, guards the update of a single bug: the client
+	// checks the time the bug last changed before sending the update and
+	// fails with a MidairCollisionError if the bug changed after this time,
+	// which should be the LastChangeTime of the bug as read before.
+	LastChangeTime *Timestamp `json:"delta_ts,omitempty"`
+	// ClearFields are the JSON names of text fields to clear, like "whiteboard", which can
+	// not be cleared by leaving them empty as empty fields are not sent to the server.
+	ClearFields []string `json:"-"`
+	// CustomFields are the values to set for custom fields, keyed by their name, like "cf_doc_type".
+	CustomFields map[string]interface{} `json:"-"`
+	// Verified replaces the values of the RHEL-style "Verified" multi-select field.
+	Verified []VerifiedValue `json:"cf_verified,omitempty"`
+	// Groups are the groups to add the bug to or remove it from.
+	Groups *BugGroups `json:"groups,omitempty"`
+	// SeeAlso are the URLs of related bugs to add to or remove from the bug.
+	SeeAlso *BugSeeAlso `json:"see_also,omitempty"`
+}
+
+// BugSeeAlso contains the URLs to add to or remove from the "see also" field of a Bug
+type BugSeeAlso struct {
+	Add    []string `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
+
+// BugGroups contains the names of the groups to add a Bug to or remove it from
+type BugGroups struct {
+	Add    []string `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
+
+// BugAliases contains the aliases to add to, remove from or set on a Bug
+type BugAliases struct {
+	Add    []string `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+	Set    []string `json:"set,omitempty"`
+}
+
+// BugCreate contains the fields of a new Bug. See API documentation at:
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/bug.html#create-bug
+type BugCreate struct {
+	// Product is the name of the product the bug is filed against.
+	Product string `json:"product,omitempty"`
+	// Component is the name of the component the bug is filed against.
+	Component string `json:"component,omitempty"`
+	// Summary is a brief description of the bug.
+	Summary string `json:"summary,omitempty"`
+	// Version is the version of the product the bug was found in.
+	Version string `json:"version,omitempty"`
+	// Description is the initial description of the bug, its first comment.
+	Description string `json:"description,omitempty"`
+	// IsDescriptionPrivate makes the description private.
+	IsDescriptionPrivate bool `json:"comment_is_private,omitempty"`
+	// OperatingSystem is the operating system the bug was found on.
+	OperatingSystem string `json:"op_sys,omitempty"`
+	// Platform is the platform (hardware) the bug was found on.
+	Platform string `json:"platform,omitempty"`
+	// Priority is the priority of the bug.
+	Priority string `json:"priority,omitempty"`
+	// Severity is the severity of the bug.
+	Severity string `json:"severity,omitempty"`
+	// Alias is the aliases of the bug.
+	Alias []string `json:"alias,omitempty"`
+	// AssignedTo is the login name of the user to assign the bug to.
+	AssignedTo string `json:"assigned_to,omitempty"`
+	// CC is the login names of the users to put on the CC list of the bug.
+	CC []string `json:"cc,omitempty"`
+	// QAContact is the login name of the QA contact of the bug.
+	QAContact string `json:"qa_contact,omitempty"`
+	// Status is the status the bug is filed in.
+	Status string `json:"status,omitempty"`
+	// TargetMilestone is the milestone the bug should be fixed by.
+	TargetMilestone string `json:"target_milestone,omitempty"`
+	// TargetRelease are the releases the bug should be fixed in.
+	TargetRelease []string `json:"target_release,omitempty"`
+	// DependsOn is the IDs of the bugs this bug depends on.
+	DependsOn []int `json:"depends_on,omitempty"`
+	// Blocks is the IDs of the bugs this bug blocks.
+	Blocks []int `json:"blocks,omitempty"`
+	// Keywords are the keywords of the bug.
+	Keywords []string `json:"keywords,omitempty"`
+	// URL is a URL that demonstrates the problem described in the bug.
+	URL string `json:"url,omitempty"`
+	// Whiteboard is the value of the "status whiteboard" field of the bug.
+	Whiteboard string `json:"whiteboard,omitempty"`
+	// Groups are the names of the groups which restrict who can see the bug.
+	Groups []string `json:"groups,omitempty"`
+	// CustomFields are the values to set for custom fields, keyed by their name, like "cf_doc_type".
+	CustomFields map[string]interface{} `json:"-"`
+}
+
+// BugCC contains the users to add to or remove from the CC list of a Bug
+type BugCC struct {
+	Add    []string `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
+
+// BugIDs contains the bug IDs to add to, remove from or set on a Bug field
+// that references other bugs, like depends_on or blocks
+type BugIDs struct {
+	Add    []int `json:"add,omitempty"`
+	Remove []int `json:"remove,omitempty"`
+	Set    []int `json:"set,omitempty"`
+}
+
+// Product holds the metadata of a product. See API documentation at:
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/product.html#get-product
+type Product struct {
+	// The ID of the product.
+	ID int `json:"id,omitempty"`
+	// The name of the product.
+	Name string `json:"name,omitempty"`
+	// A description of the product, which may contain HTML.
+	Description string `json:"description,omitempty"`
+	// A boolean indicating if the product is active.
+	IsActive bool `json:"is_active,omitempty"`
+	// The name of the default milestone for the product.
+	DefaultMilestone string `json:"default_milestone,omitempty"`
+	// The classification name for the product.
+	Classification string `json:"classification,omitempty"`
+	// The components of the product.
+	Components []ProductComponent `json:"components,omitempty"`
+	// The versions of the product.
+	Versions []ProductValue `json:"versions,omitempty"`
+	// The milestones of the product.
+	Milestones []ProductValue `json:"milestones,omitempty"`
+}
+
+// ProductComponent holds the metadata of a component of a product
+type ProductComponent struct {
+	// The ID of the component.
+	ID int `json:"id,omitempty"`
+	// The name of the component.
+	Name string `json:"name,omitempty"`
+	// A description of the component, which may contain HTML.
+	Description string `json:"description,omitempty"`
+	// The login name of the user to whom new bugs will be assigned by default.
+	DefaultAssignedTo string `json:"default_assigned_to,omitempty"`
+	// The login name of the user who will be set as the QA Contact for new bugs by default.
+	DefaultQAContact string `json:"default_qa_contact,omitempty"`
+	// A boolean indicating if the component is active.
+	IsActive bool `json:"is_active,omitempty"`
+	// The sub-components of the component. Not all bugzilla instances support this field.
+	SubComponents []ProductValue `json:"sub_components,omitempty"`
+}
+
+// ProductValue holds a version or a milestone of a product
+type ProductValue struct {
+	// The ID of the version or milestone.
+	ID int `json:"id,omitempty"`
+	// The name of the version or milestone.
+	Name string `json:"name,omitempty"`
+	// The sort key used to order the values.
+	SortKey int `json:"sort_key,omitempty"`
+	// A boolean indicating if the value is active.
+	IsActive bool `json:"is_active,omitempty"`
+}
+
+// Field holds the metadata of a bug field. See API documentation at:
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/field.html#fields
+type Field struct {
+	// The ID of the field.
+	ID int `json:"id,omitempty"`
+	// The type of the field, like 2 for drop-down fields, see the API documentation.
+	Type int `json:"type,omitempty"`
+	// A boolean indicating if the field is a custom field.
+	IsCustom bool `json:"is_custom,omitempty"`
+	// The name of the field, as used in the API, like "bug_status" or "cf_doc_type".
+	Name string `json:"name,omitempty"`
+	// The name of the field as shown in the user interface.
+	DisplayName string `json:"display_name,omitempty"`
+	// A boolean indicating if the field must have a value.
+	IsMandatory bool `json:"is_mandatory,omitempty"`
+	// A boolean indicating if the field is shown when filing bugs.
+	IsOnBugEntry bool `json:"is_on_bug_entry,omitempty"`
+	// The name of the field which controls whether this field is shown.
+	VisibilityField string `json:"visibility_field,omitempty"`
+	// The values of the VisibilityField for which this field is shown.
+	VisibilityValues []string `json:"visibility_values,omitempty"`
+	// The name of the field which controls which values of this field are shown.
+	ValueField string `json:"value_field,omitempty"`
+	// The legal values of the field, for fields which have a list of them.
+	Values []FieldValue `json:"values,omitempty"`
+}
+
+// FieldValue holds a legal value of a bug field
+type FieldValue struct {
+	// The name of the value.
+	Name string `json:"name,omitempty"`
+	// The sort key used to order the values.
+	SortKey int `json:"sort_key,omitempty"`
+	// The values of the ValueField of the field for which this value is shown.
+	VisibilityValues []string `json:"visibility_values,omitempty"`
+	// A boolean indicating if the value can be set on bugs.
+	IsActive bool `json:"is_active,omitempty"`
+	// The description of the value.
+	Description string `json:"description,omitempty"`
+	// For statuses, a boolean indicating if the status is open.
+	IsOpen bool `json:"is_open,omitempty"`
+	// For statuses, the statuses a bug in this status can change to.
+	CanChangeTo []StatusTransition `json:"can_change_to,omitempty"`
+}
+
+// StatusTransition holds a status a bug can change to
+type StatusTransition struct {
+	// The name of the status.
+	Name string `json:"name,omitempty"`
+	// A boolean indicating if changing to the status requires a comment.
+	CommentRequired bool `json:"comment_required,omitempty"`
 }
 
 // ExternalBug contains details about an external bug linked to a Bugzilla bug.
@@ -245,17 +587,17 @@ type BugUpdate struct {
 // https://bugzilla.redhat.com/docs/en/html/integrating/api/Bugzilla/Extension/ExternalBugs/WebService.html
 type ExternalBug struct {
 	// Type holds more metadata for the external bug tracker
-	Type ExternalBugType `json:"type"`
+	Type ExternalBugType `json:"type" yaml:"type"`
 	// BugzillaBugID is the ID of the Bugzilla bug this external bug is linked to
-	BugzillaBugID int `json:"bug_id"`
+	BugzillaBugID int `json:"bug_id" yaml:"bug_id"`
 	// ExternalBugID is a unique identifier for the bug under the tracker
-	ExternalBugID string `json:"ext_bz_bug_id"`
+	ExternalBugID string `json:"ext_bz_bug_id" yaml:"ext_bz_bug_id"`
 	// ExternalDescription is the description in the external bug system
-	ExternalDescription string `json:"ext_description"`
+	ExternalDescription string `json:"ext_description" yaml:"ext_description"`
 	// ExternalPriority is the priority in the external bug system
-	ExternalPriority string `json:"ext_priority"`
+	ExternalPriority string `json:"ext_priority" yaml:"ext_priority"`
 	// ExternalStatus is the external bug status, e.g. Closed (depending on bug system).
-	ExternalStatus string `json:"ext_status"`
+	ExternalStatus string `json:"ext_status" yaml:"ext_status"`
 
 	// The following fields are parsed from the external bug identifier for github pulls. These are only filled by GetExternalBugPRsOnBug.
 	Org, Repo string
@@ -264,12 +606,16 @@ type ExternalBug struct {
 
 // ExternalBugType holds identifying metadata for a tracker
 type ExternalBugType struct {
+	// ID is the ID of the tracker on the server, only set by GetExternalTrackerTypes
+	ID int `json:"id,omitempty" yaml:"id,omitempty"`
 	// URL is the identifying URL for this tracker
-	URL string `json:"url"`
+	URL string `json:"url" yaml:"url"`
 	// Description is the tracker name
-	Description string `json:"description"`
+	Description string `json:"description" yaml:"description"`
 	// Type is the key for the external bug type
-	Type string `json:"type"`
+	Type string `json:"type" yaml:"type"`
+	// FullURL is the template of the URLs of the external bugs, only set by GetExternalTrackerTypes
+	FullURL string `json:"full_url,omitempty" yaml:"full_url,omitempty"`
 }
 
 // AddExternalBugParameters are the parameters required to add an external
@@ -295,6 +641,30 @@ type NewExternalBugIdentifier struct {
 	ID string `json:"ext_bz_bug_id"`
 }
 
+// ExternalBugIdentifier identifies an external bug which is linked to a
+// Bugzilla bug already
+type ExternalBugIdentifier struct {
+	// Type is the URL prefix that identifies the external bug tracker type,
+	// like https://github.com/
+	Type string `json:"ext_type_url"`
+	// ID is the identifier of the external bug within the bug tracker type,
+	// like `org/repo/pull/number`.
+	ID string `json:"ext_bz_bug_id"`
+}
+
+// UpdateExternalBugParameters are the parameters required to update an
+// external tracker bug linked to Bugzilla bugs
+type UpdateExternalBugParameters struct {
+	// APIKey is the API key to use when authenticating with Bugzilla
+	APIKey string `json:"api_key"`
+	// BugIDs limit the update to the links to these Bugzilla bugs
+	BugIDs []int `json:"bug_ids"`
+	// ExternalBugIdentifier identifies the external bug to update
+	ExternalBugIdentifier
+	// Status is the new status of the external bug, like "MERGED"
+	Status string `json:"ext_status"`
+}
+
 // AdvancedQuery allows the user to specifc the Field and Operation (required) and optional
 // Value and Negation. There is no validation. If you use invalid strings for Field or Op
 // it just will be ignored by BZ.
@@ -317,9 +687,13 @@ type Query struct {
 	KeywordsType   string          `json:"keywords_type,omitempty"`
 	BugIDs         []string        `json:"bug_ids,omitempty"`
 	BugIDsType     string          `json:"bug_ids_type,omitempty"`
+	Alias          []string        `json:"alias,omitempty"`
 	Component      []string        `json:"component,omitempty"`
 	TargetRelease  []string        `json:"target_release,omitempty"`
 	Advanced       []AdvancedQuery `json:"advanced,omitempty"`
 	IncludeFields  []string        `json:"include_fields,omitempty"`
+	ExcludeFields  []string        `json:"exclude_fields,omitempty"`
+	UserDetails    bool            `json:"user_details,omitempty"`
+	Verified       []VerifiedValue `json:"cf_verified,omitempty"`
 	Raw            string          `json:"raw,omitempty"`
 }
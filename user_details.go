@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+// userDetailFields are the fields holding detailed user information for the
+// fields that only hold login names
+var userDetailFields = []struct {
+	login, detail string
+}{
+	{login: "assigned_to", detail: "assigned_to_detail"},
+	{login: "cc", detail: "cc_detail"},
+	{login: "creator", detail: "creator_detail"},
+	{login: "qa_contact", detail: "qa_contact_detail"},
+}
+
+// withUserDetailFields adds the detail field for every included user field
+// which does not have its detail field included yet
+func withUserDetailFields(fields []string) []string {
+	included := map[string]bool{}
+	for _, field := range fields {
+		included[field] = true
+	}
+	out := append([]string{}, fields...)
+	for _, user := range userDetailFields {
+		if (included[user.login] || included["_default"] || included["_all"]) && !included[user.detail] {
+			out = append(out, user.detail)
+		}
+	}
+	return out
+}
+
+// NormalizeUserDetails makes the user fields of the bug consistent: every
+// login name has a detail object and every detail object has its login name
+// set. Detail objects that are filled in from a login name only hold the name.
+func NormalizeUserDetails(bug *Bug) {
+	normalizeUser(&bug.AssignedTo, &bug.AssignedToDetail)
+	normalizeUser(&bug.Creator, &bug.CreatorDetail)
+	normalizeUser(&bug.QAContact, &bug.QAContactDetail)
+
+	detailed := map[string]bool{}
+	for _, user := range bug.CCDetail {
+		detailed[user.Name] = true
+	}
+	listed := map[string]bool{}
+	for _, login := range bug.CC {
+		listed[login] = true
+		if !detailed[login] {
+			bug.CCDetail = append(bug.CCDetail, User{Name: login})
+		}
+	}
+	for _, user := range bug.CCDetail {
+		if !listed[user.Name] {
+			bug.CC = append(bug.CC, user.Name)
+		}
+	}
+}
+
+func normalizeUser(login *string, detail **User) {
+	switch {
+	case *login != "" && *detail == nil:
+		*detail = &User{Name: *login}
+	case *login == "" && *detail != nil:
+		*login = (*detail).Name
+	}
+}
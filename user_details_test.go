@@ -0,0 +1,52 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestUserDetailsQuery(t *testing.T) {
+	query := Query{IncludeFields: []string{"id", "assigned_to", "cc", "creator_detail", "creator"}, UserDetails: true}
+	if actual, expected := query.Values().Get("include_fields"), "id,assigned_to,cc,creator_detail,creator,assigned_to_detail,cc_detail"; actual != expected {
+		t.Errorf("expected include_fields %q, got %q", expected, actual)
+	}
+}
+
+func TestNormalizeUserDetails(t *testing.T) {
+	bug := &Bug{
+		AssignedTo:    "skuznets@redhat.com",
+		CreatorDetail: &User{ID: 1, Name: "dmace@redhat.com"},
+		CC:            []string{"a@redhat.com"},
+		CCDetail:      []User{{ID: 2, Name: "b@redhat.com"}},
+	}
+	NormalizeUserDetails(bug)
+	expected := &Bug{
+		AssignedTo:       "skuznets@redhat.com",
+		AssignedToDetail: &User{Name: "skuznets@redhat.com"},
+		Creator:          "dmace@redhat.com",
+		CreatorDetail:    &User{ID: 1, Name: "dmace@redhat.com"},
+		CC:               []string{"a@redhat.com", "b@redhat.com"},
+		CCDetail:         []User{{ID: 2, Name: "b@redhat.com"}, {Name: "a@redhat.com"}},
+	}
+	if !reflect.DeepEqual(bug, expected) {
+		t.Errorf("got incorrect normalized bug: %v", diff.ObjectReflectDiff(expected, bug))
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// EditBugsGroup is the group of the users who can change any field of the
+	// bugs they can see.
+	EditBugsGroup = "editbugs"
+	// CanConfirmGroup is the group of the users who can confirm UNCONFIRMED bugs.
+	CanConfirmGroup = "canconfirm"
+)
+
+// GetUser retrieves the user with the ID or login name, which is usually the
+// e-mail address, with the groups the user is a member of. The server only
+// returns the groups of the current user, or of any user to members of the
+// editusers group; the groups of other users are empty.
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/user.html#get-user
+func (c *client) GetUser(idOrLogin string) (*User, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "GetUser", "user": idOrLogin})
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/rest/user/%s", c.endpoint, url.PathEscape(idOrLogin)), nil)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := c.request(req, logger)
+	if err != nil {
+		return nil, err
+	}
+	var parsedResponse struct {
+		Users []User `json:"users,omitempty"`
+	}
+	if err := c.unmarshal(raw, &parsedResponse); err != nil {
+		return nil, fmt.Errorf("could not unmarshal response body: %v", err)
+	}
+	if len(parsedResponse.Users) != 1 {
+		return nil, fmt.Errorf("did not get one user, but %d: %v", len(parsedResponse.Users), parsedResponse.Users)
+	}
+	return &parsedResponse.Users[0], nil
+}
+
+// InGroup returns whether the user is a member of the group with the name
+func (u *User) InGroup(name string) bool {
+	for _, group := range u.Groups {
+		if group.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// is returns whether the login name or e-mail is the one of the user
+func (u *User) is(login string) bool {
+	return login != "" && (strings.EqualFold(login, u.Name) || strings.EqualFold(login, u.Email))
+}
+
+// CanSee returns whether the user can see the bug: bugs in groups are only
+// visible to the members of all of their groups, and to their reporter,
+// assignee and QA contact. The user must have been retrieved with its groups,
+// see GetUser.
+func CanSee(bug *Bug, user *User) bool {
+	if user.is(bug.Creator) || user.is(bug.AssignedTo) || user.is(bug.QAContact) {
+		return true
+	}
+	for _, group := range bug.Groups {
+		if !user.InGroup(group) {
+			return false
+		}
+	}
+	return true
+}
+
+// CanEdit returns whether the user can change the bug, like the server
+// checks it before an update: members of the editbugs group can change the
+// bugs they can see, other users only the bugs they reported, are assigned
+// to or are the QA contact of. Product specific permissions are not taken
+// into account. The user must have been retrieved with its groups, see
+// GetUser.
+func CanEdit(bug *Bug, user *User) bool {
+	if !CanSee(bug, user) {
+		return false
+	}
+	return user.InGroup(EditBugsGroup) || user.is(bug.Creator) || user.is(bug.AssignedTo) || user.is(bug.QAContact)
+}
+
+// CanChangeStatus returns whether the user can move the bug to the status,
+// e.g. to MODIFIED, so that automation can check its rights before starting
+// a batch of updates. Besides being able to edit the bug, confirming an
+// UNCONFIRMED bug needs membership in the canconfirm or editbugs groups.
+// Whether the workflow allows the transition is checked by NewValidator.
+func CanChangeStatus(bug *Bug, user *User, status string) bool {
+	if !CanEdit(bug, user) {
+		return false
+	}
+	if bug.Status == "UNCONFIRMED" && status != "UNCONFIRMED" && !user.InGroup(EditBugsGroup) && !user.InGroup(CanConfirmGroup) {
+		// only resolving the bug does not confirm it
+		return status == "CLOSED" || status == "RESOLVED"
+	}
+	return true
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestGetUser(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/rest/user/someone@example.com" {
+			t.Errorf("incorrect request to get user: %s %s", r.Method, r.URL.Path)
+			http.Error(w, "404 Not Found", http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"users":[{"id":1,"name":"someone@example.com","real_name":"Someone","groups":[{"id":2,"name":"editbugs","description":"Can edit all bug fields"}]}]}`))
+	}))
+	defer testServer.Close()
+	client := clientForUrl(testServer.URL)
+
+	user, err := client.GetUser("someone@example.com")
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	expected := &User{ID: 1, Name: "someone@example.com", RealName: "Someone", Groups: []Group{{ID: 2, Name: EditBugsGroup, Description: "Can edit all bug fields"}}}
+	if !reflect.DeepEqual(user, expected) {
+		t.Errorf("got incorrect user: %v", diff.ObjectReflectDiff(expected, user))
+	}
+}
+
+func TestFakeGetUser(t *testing.T) {
+	fake := &Fake{
+		CurrentUser: &User{ID: 1, Name: "bot@example.com"},
+		Users:       []User{{ID: 2, Name: "someone", Email: "someone@example.com"}},
+	}
+	for idOrLogin, expected := range map[string]int{"1": 1, "bot@example.com": 1, "2": 2, "someone@example.com": 2} {
+		user, err := fake.GetUser(idOrLogin)
+		if err != nil {
+			t.Fatalf("%s: expected no error, but got one: %v", idOrLogin, err)
+		}
+		if user.ID != expected {
+			t.Errorf("%s: got the wrong user %d", idOrLogin, user.ID)
+		}
+	}
+	if _, err := fake.GetUser("nobody"); !IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestCanChangeStatus(t *testing.T) {
+	editor := &User{Name: "editor@example.com", Groups: []Group{{Name: EditBugsGroup}}}
+	confirmer := &User{Name: "confirmer@example.com", Groups: []Group{{Name: CanConfirmGroup}}}
+	reporter := &User{Name: "reporter@example.com"}
+	outsider := &User{Name: "outsider@example.com"}
+	var testCases = []struct {
+		name      string
+		bug       *Bug
+		user      *User
+		status    string
+		canEdit   bool
+		canChange bool
+	}{
+		{
+			name:      "members of editbugs can move any bug",
+			bug:       &Bug{Status: "ASSIGNED", Creator: "reporter@example.com"},
+			user:      editor,
+			status:    "MODIFIED",
+			canEdit:   true,
+			canChange: true,
+		},
+		{
+			name:   "members of editbugs can not change bugs in groups they are not in",
+			bug:    &Bug{Status: "ASSIGNED", Groups: []string{"private"}},
+			user:   editor,
+			status: "MODIFIED",
+		},
+		{
+			name:      "reporters can change their bugs",
+			bug:       &Bug{Status: "ASSIGNED", Creator: "reporter@example.com", Groups: []string{"private"}},
+			user:      reporter,
+			status:    "MODIFIED",
+			canEdit:   true,
+			canChange: true,
+		},
+		{
+			name:   "other users can not change bugs",
+			bug:    &Bug{Status: "ASSIGNED", Creator: "reporter@example.com"},
+			user:   outsider,
+			status: "MODIFIED",
+		},
+		{
+			name:    "reporters can not confirm their bugs",
+			bug:     &Bug{Status: "UNCONFIRMED", Creator: "reporter@example.com"},
+			user:    reporter,
+			status:  "NEW",
+			canEdit: true,
+		},
+		{
+			name:      "reporters can close their unconfirmed bugs",
+			bug:       &Bug{Status: "UNCONFIRMED", Creator: "reporter@example.com"},
+			user:      reporter,
+			status:    "CLOSED",
+			canEdit:   true,
+			canChange: true,
+		},
+		{
+			name:      "members of canconfirm can confirm their bugs",
+			bug:       &Bug{Status: "UNCONFIRMED", AssignedTo: "confirmer@example.com"},
+			user:      confirmer,
+			status:    "NEW",
+			canEdit:   true,
+			canChange: true,
+		},
+	}
+	for _, testCase := range testCases {
+		if actual := CanEdit(testCase.bug, testCase.user); actual != testCase.canEdit {
+			t.Errorf("%s: expected CanEdit %v, got %v", testCase.name, testCase.canEdit, actual)
+		}
+		if actual := CanChangeStatus(testCase.bug, testCase.user, testCase.status); actual != testCase.canChange {
+			t.Errorf("%s: expected CanChangeStatus %v, got %v", testCase.name, testCase.canChange, actual)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Validator checks new bugs and updates against the metadata of the server
+// before they are sent, reporting all problems at once instead of one server
+// error at a time: that the component, version and target releases exist in
+// the product, that the status change is allowed by the workflow and that
+// the fields required in the status are set.
+type Validator struct {
+	client   Client
+	workflow *Workflow
+	required map[string][]string
+}
+
+// NewValidator returns a Validator using the metadata of the server the
+// client talks to. The required fields are the fields, by their names in the
+// API like "target_release" or "cf_doc_type", which bugs must have set in a
+// status, keyed by the status.
+func NewValidator(c Client, required map[string][]string) (*Validator, error) {
+	workflow, err := GetWorkflow(c)
+	if err != nil {
+		return nil, fmt.Errorf("could not get the workflow: %v", err)
+	}
+	return &Validator{client: c, workflow: workflow, required: required}, nil
+}
+
+// ValidationError holds all problems found with a new bug or an update
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid bug: %s", strings.Join(e.Problems, "; "))
+}
+
+// IsValidationError returns true if the error was returned because a new bug
+// or an update is not valid
+func IsValidationError(err error) bool {
+	var target *ValidationError
+	return errors.As(err, &target)
+}
+
+// ValidateCreate checks the new bug. It returns a *ValidationError holding
+// all problems, or another error if the metadata could not be retrieved.
+func (v *Validator) ValidateCreate(bug BugCreate) error {
+	var problems []string
+	for _, field := range []struct{ name, value string }{{"product", bug.Product}, {"component", bug.Component}, {"summary", bug.Summary}, {"version", bug.Version}} {
+		if field.value == "" {
+			problems = append(problems, fmt.Sprintf("%s is required", field.name))
+		}
+	}
+	if bug.Product != "" {
+		schema, err := v.client.GetProductSchema(bug.Product)
+		if IsNotFound(err) {
+			problems = append(problems, fmt.Sprintf("product %q does not exist", bug.Product))
+		} else if err != nil {
+			return err
+		} else {
+			problems = append(problems, checkValue(schema, "component", bug.Component, schema.Components)...)
+			problems = append(problems, checkValue(schema, "version", bug.Version, schema.Versions)...)
+			for _, targetRelease := range bug.TargetRelease {
+				problems = append(problems, checkValue(schema, "target release", targetRelease, schema.TargetReleases)...)
+			}
+		}
+	}
+	if bug.Status != "" {
+		if _, known := v.workflow.transitions[bug.Status]; !known {
+			problems = append(problems, fmt.Sprintf("status %q is not known", bug.Status))
+		}
+	}
+	fields, err := setFields(bug)
+	if err != nil {
+		return err
+	}
+	problems = append(problems, v.checkRequired(bug.Status, fields)...)
+	return validationResult(problems)
+}
+
+// ValidateUpdate checks the update of the bug, which must hold the fields
+// which are required in the status the bug ends up in. It returns a
+// *ValidationError holding all problems, or another error if the metadata
+// could not be retrieved.
+func (v *Validator) ValidateUpdate(bug *Bug, update BugUpdate) error {
+	var problems []string
+	product := bug.Product
+	if update.Product != "" {
+		product = update.Product
+	}
+	if product != "" {
+		schema, err := v.client.GetProductSchema(product)
+		if IsNotFound(err) {
+			problems = append(problems, fmt.Sprintf("product %q does not exist", product))
+		} else if err != nil {
+			return err
+		} else if err := schema.Validate(update); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if err := v.workflow.ValidateUpdate(bug.Status, update); err != nil {
+		problems = append(problems, err.Error())
+	}
+	fields, err := setFields(bug)
+	if err != nil {
+		return err
+	}
+	updated, err := setFields(update)
+	if err != nil {
+		return err
+	}
+	for field, set := range updated {
+		fields[field] = set
+	}
+	status := bug.Status
+	if update.Status != "" {
+		status = update.Status
+	}
+	problems = append(problems, v.checkRequired(status, fields)...)
+	return validationResult(problems)
+}
+
+// checkRequired returns a problem for every field required in the status
+// which is not set
+func (v *Validator) checkRequired(status string, set map[string]bool) []string {
+	var problems []string
+	for _, field := range v.required[status] {
+		if !set[field] {
+			problems = append(problems, fmt.Sprintf("%s is required in status %s", field, status))
+		}
+	}
+	return problems
+}
+
+func checkValue(schema *ProductSchema, field, value string, valid []string) []string {
+	if value != "" && valid != nil && !contains(valid, value) {
+		return []string{fmt.Sprintf("%s %q does not exist in product %s", field, value, schema.Product)}
+	}
+	return nil
+}
+
+func validationResult(problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: problems}
+}
+
+// setFields returns whether the fields of the bug, new bug or update are set,
+// by their names in the API. Fields which are sent empty, like the fields an
+// update clears, are not set.
+func setFields(value interface{}) (map[string]bool, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal bug: %v", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("could not unmarshal bug: %v", err)
+	}
+	set := map[string]bool{}
+	for field, value := range fields {
+		set[field] = isSet(value)
+	}
+	return set, nil
+}
+
+func isSet(value interface{}) bool {
+	switch value := value.(type) {
+	case nil:
+		return false
+	case string:
+		return value != "" && value != "---"
+	case []interface{}:
+		for _, item := range value {
+			if isSet(item) {
+				return true
+			}
+		}
+		return false
+	case map[string]interface{}:
+		return len(value) > 0
+	}
+	return true
+}
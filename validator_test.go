@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidator(t *testing.T) {
+	fake := &Fake{
+		Products: map[string]Product{"OCP": {
+			Name:       "OCP",
+			Components: []ProductComponent{{Name: "Networking", IsActive: true}},
+			Versions:   []ProductValue{{Name: "4.6", IsActive: true}},
+		}},
+		TargetReleases: map[string][]string{"OCP": {"4.6.0", "4.7.0"}},
+		Fields: []Field{{Name: "bug_status", Values: []FieldValue{
+			{Name: "NEW", IsOpen: true, CanChangeTo: []StatusTransition{{Name: "POST"}, {Name: "CLOSED"}}},
+			{Name: "POST", IsOpen: true, CanChangeTo: []StatusTransition{{Name: "NEW"}}},
+			{Name: "CLOSED", CanChangeTo: []StatusTransition{{Name: "NEW"}}},
+		}}},
+	}
+	validator, err := NewValidator(fake, map[string][]string{"POST": {"target_release", "cf_doc_type"}})
+	if err != nil {
+		t.Fatalf("expected no error creating the validator, got %v", err)
+	}
+
+	var createCases = []struct {
+		name     string
+		bug      BugCreate
+		expected []string
+	}{
+		{
+			name: "valid bug",
+			bug:  BugCreate{Product: "OCP", Component: "Networking", Summary: "broken", Version: "4.6", TargetRelease: []string{"4.7.0"}},
+		},
+		{
+			name: "all problems are reported",
+			bug:  BugCreate{Product: "OCP", Component: "Storage", Version: "4.5", TargetRelease: []string{"4.6.0", "5.0.0"}, Status: "POST"},
+			expected: []string{
+				"summary is required",
+				`component "Storage" does not exist in product OCP`,
+				`version "4.5" does not exist in product OCP`,
+				`target release "5.0.0" does not exist in product OCP`,
+				"cf_doc_type is required in status POST",
+			},
+		},
+		{
+			name:     "unknown product and status",
+			bug:      BugCreate{Product: "RHEL", Component: "kernel", Summary: "broken", Version: "8", Status: "DONE"},
+			expected: []string{`product "RHEL" does not exist`, `status "DONE" is not known`},
+		},
+		{
+			name: "required custom fields",
+			bug:  BugCreate{Product: "OCP", Component: "Networking", Summary: "broken", Version: "4.6", Status: "POST", TargetRelease: []string{"4.7.0"}, CustomFields: map[string]interface{}{"cf_doc_type": "Bug Fix"}},
+		},
+	}
+	for _, testCase := range createCases {
+		checkValidation(t, testCase.name, validator.ValidateCreate(testCase.bug), testCase.expected)
+	}
+
+	bug := &Bug{ID: 1, Product: "OCP", Status: "NEW", TargetRelease: []string{"---"}}
+	var updateCases = []struct {
+		name     string
+		update   BugUpdate
+		expected []string
+	}{
+		{
+			name:   "valid update",
+			update: BugUpdate{Status: "POST", TargetRelease: "4.7.0", CustomFields: map[string]interface{}{"cf_doc_type": "Bug Fix"}},
+		},
+		{
+			name:   "all problems are reported",
+			update: BugUpdate{Status: "POST", Component: "Storage"},
+			expected: []string{
+				`invalid values for product OCP: component "Storage"`,
+				"target_release is required in status POST",
+				"cf_doc_type is required in status POST",
+			},
+		},
+		{
+			name:     "closing requires a resolution",
+			update:   BugUpdate{Status: "CLOSED"},
+			expected: []string{"status NEW can not change to CLOSED: a resolution is required"},
+		},
+	}
+	for _, testCase := range updateCases {
+		checkValidation(t, testCase.name, validator.ValidateUpdate(bug, testCase.update), testCase.expected)
+	}
+
+	posted := &Bug{ID: 1, Product: "OCP", Status: "POST", TargetRelease: []string{"4.7.0"}, CustomFields: map[string]interface{}{"cf_doc_type": "Bug Fix"}}
+	checkValidation(t, "fields set on the bug", validator.ValidateUpdate(posted, BugUpdate{Whiteboard: "reviewed"}), nil)
+	checkValidation(t, "cleared fields", validator.ValidateUpdate(posted, BugUpdate{ClearFields: []string{"cf_doc_type"}}), []string{"cf_doc_type is required in status POST"})
+}
+
+func checkValidation(t *testing.T, name string, err error, expected []string) {
+	t.Helper()
+	if expected == nil {
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", name, err)
+		}
+		return
+	}
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Errorf("%s: expected a validation error, got %v", name, err)
+		return
+	}
+	if !reflect.DeepEqual(validationErr.Problems, expected) {
+		t.Errorf("%s: expected problems %q, got %q", name, expected, validationErr.Problems)
+	}
+}
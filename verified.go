@@ -0,0 +1,30 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+// VerifiedValue is a value of the RHEL-style "Verified" (cf_verified)
+// multi-select field which QE uses to record how a bug was verified
+type VerifiedValue string
+
+const (
+	// VerifiedTested means the fix was verified with a full test of the scenario
+	VerifiedTested VerifiedValue = "Tested"
+	// VerifiedSanityOnly means the fix was only sanity checked, not fully tested
+	VerifiedSanityOnly VerifiedValue = "SanityOnly"
+	// VerifiedFailedQA means the fix failed verification
+	VerifiedFailedQA VerifiedValue = "FailedQA"
+)
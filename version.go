@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+// WithServerVersion sets the version of the server, so the client does not
+// have to ask the server for it before using features which not all versions
+// support.
+func WithServerVersion(version string) Option {
+	return func(c *client) {
+		c.versions = &versionCache{version: version, known: true}
+	}
+}
+
+// versionCache holds the version of the server once it is known
+type versionCache struct {
+	lock    sync.Mutex
+	version string
+	known   bool
+}
+
+// GetVersion retrieves the version of the server, like "5.0.4.rh83"
+// https://bugzilla.readthedocs.io/en/latest/api/core/v1/bugzilla.html#version
+func (c *client) GetVersion() (string, error) {
+	logger := c.logger.WithFields(LogFields{methodField: "GetVersion"})
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/rest/version", c.endpoint), nil)
+	if err != nil {
+		return "", err
+	}
+	raw, err := c.request(req, logger)
+	if err != nil {
+		return "", err
+	}
+	var parsedResponse struct {
+		Version string `json:"version"`
+	}
+	if err := c.unmarshal(raw, &parsedResponse); err != nil {
+		return "", fmt.Errorf("could not unmarshal response body: %v", err)
+	}
+	return parsedResponse.Version, nil
+}
+
+// serverVersion returns the version of the server, asking the server only
+// the first time it is needed
+func (c *client) serverVersion() (string, error) {
+	if c.versions == nil {
+		return c.GetVersion()
+	}
+	c.versions.lock.Lock()
+	defer c.versions.lock.Unlock()
+	if !c.versions.known {
+		version, err := c.GetVersion()
+		if err != nil {
+			return "", err
+		}
+		c.versions.version, c.versions.known = version, true
+	}
+	return c.versions.version, nil
+}
+
+var versionRe = regexp.MustCompile(`^(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:\.(\w+?)\d*)?$`)
+
+// parsedVersion is a version like 5.0.4.rh83, of vendor "rh"
+type parsedVersion struct {
+	major, minor, patch int
+	vendor              string
+}
+
+func parseVersion(version string) (parsedVersion, bool) {
+	parts := versionRe.FindStringSubmatch(version)
+	if parts == nil {
+		return parsedVersion{}, false
+	}
+	var parsed parsedVersion
+	parsed.major, _ = strconv.Atoi(parts[1])
+	parsed.minor, _ = strconv.Atoi(parts[2])
+	parsed.patch, _ = strconv.Atoi(parts[3])
+	parsed.vendor = parts[4]
+	return parsed, true
+}
+
+func (v parsedVersion) atLeast(major, minor int) bool {
+	return v.major > major || (v.major == major && v.minor >= minor)
+}
+
+// capability is a feature which not all servers support
+type capability string
+
+const (
+	// capabilityMinorUpdate is the minor_update parameter of updates, which
+	// suppresses e-mail notifications
+	capabilityMinorUpdate capability = "minor_update"
+	// capabilitySubComponents is the sub_components field of Red Hat's
+	// Bugzilla
+	capabilitySubComponents capability = "sub_components"
+	// capabilityJSONRPC20 is version 2.0 of JSON-RPC
+	capabilityJSONRPC20 capability = "jsonrpc-2.0"
+)
+
+// capabilities returns whether a server of a version supports a capability
+var capabilities = map[capability]func(parsedVersion) bool{
+	capabilityMinorUpdate: func(v parsedVersion) bool {
+		return v.atLeast(5, 0)
+	},
+	capabilitySubComponents: func(v parsedVersion) bool {
+		return v.vendor == "rh"
+	},
+	capabilityJSONRPC20: func(v parsedVersion) bool {
+		return v.atLeast(5, 0)
+	},
+}
+
+// adaptUpdate removes the parts of the update which the server does not
+// support. If the version of the server can not be determined, the update is
+// sent as it is and the server gets to reject what it does not support.
+func (c *client) adaptUpdate(update BugUpdate, logger *logEntry) BugUpdate {
+	if !update.MinorUpdate && len(update.SubComponents) == 0 {
+		return update
+	}
+	version, err := c.serverVersion()
+	if err != nil {
+		logger.WithError(err).Debug("Could not get the server version, sending the update as it is.")
+		return update
+	}
+	parsed, ok := parseVersion(version)
+	if !ok {
+		logger.Debugf("Could not parse server version %q, sending the update as it is.", version)
+		return update
+	}
+	if update.MinorUpdate && !capabilities[capabilityMinorUpdate](parsed) {
+		logger.Debug("Server does not support minor updates, sending a regular update.")
+		update.MinorUpdate = false
+	}
+	if len(update.SubComponents) > 0 && !capabilities[capabilitySubComponents](parsed) {
+		logger.Warn("Server does not support sub-components, leaving them out of the update.")
+		update.SubComponents = nil
+	}
+	return update
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/diff"
+)
+
+func TestParseVersion(t *testing.T) {
+	var testCases = []struct {
+		version  string
+		expected parsedVersion
+		ok       bool
+	}{
+		{version: "5.0.4.rh83", expected: parsedVersion{major: 5, minor: 0, patch: 4, vendor: "rh"}, ok: true},
+		{version: "4.4.12", expected: parsedVersion{major: 4, minor: 4, patch: 12}, ok: true},
+		{version: "5.1", expected: parsedVersion{major: 5, minor: 1}, ok: true},
+		{version: "5", expected: parsedVersion{major: 5}, ok: true},
+		{version: "unknown"},
+		{version: ""},
+	}
+	for _, testCase := range testCases {
+		actual, ok := parseVersion(testCase.version)
+		if ok != testCase.ok {
+			t.Errorf("%q: expected ok %v, got %v", testCase.version, testCase.ok, ok)
+		}
+		if actual != testCase.expected {
+			t.Errorf("%q: expected %+v, got %+v", testCase.version, testCase.expected, actual)
+		}
+	}
+}
+
+func TestGetVersion(t *testing.T) {
+	requests := 0
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/rest/version" {
+			t.Errorf("incorrect path: %s", r.URL.Path)
+			http.Error(w, "404 Not Found", http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"version":"5.0.4.rh83"}`)
+	}))
+	defer testServer.Close()
+	c := clientForUrl(testServer.URL).(*client)
+	version, err := c.GetVersion()
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if version != "5.0.4.rh83" {
+		t.Errorf("expected version 5.0.4.rh83, got %q", version)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := c.serverVersion(); err != nil {
+			t.Fatalf("expected no error, but got one: %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("expected the server version to be requested once more after GetVersion, got %d requests", requests)
+	}
+}
+
+func TestUpdateBugAdaptsToVersion(t *testing.T) {
+	update := BugUpdate{
+		Status:        "MODIFIED",
+		MinorUpdate:   true,
+		SubComponents: map[string][]string{"Networking": {"ovn-kubernetes"}},
+	}
+	var testCases = []struct {
+		name             string
+		version          string
+		versionFails     bool
+		expectedUpdate   BugUpdate
+		expectedRequests int
+	}{
+		{
+			name:             "Red Hat Bugzilla 5 supports everything",
+			version:          "5.0.4.rh83",
+			expectedUpdate:   update,
+			expectedRequests: 2,
+		},
+		{
+			name:             "upstream Bugzilla 5 does not support sub-components",
+			version:          "5.0.6",
+			expectedUpdate:   BugUpdate{Status: "MODIFIED", MinorUpdate: true},
+			expectedRequests: 2,
+		},
+		{
+			name:             "upstream Bugzilla 4 supports neither",
+			version:          "4.4.12",
+			expectedUpdate:   BugUpdate{Status: "MODIFIED"},
+			expectedRequests: 2,
+		},
+		{
+			name:             "unknown version sends everything",
+			versionFails:     true,
+			expectedUpdate:   update,
+			expectedRequests: 2,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			requests := 0
+			var actual BugUpdate
+			testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if r.URL.Path == "/rest/version" {
+					if testCase.versionFails {
+						http.Error(w, "500 Internal Server Error", http.StatusInternalServerError)
+						return
+					}
+					fmt.Fprintf(w, `{"version":%q}`, testCase.version)
+					return
+				}
+				raw, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("failed to read request body: %v", err)
+				}
+				if err := json.Unmarshal(raw, &actual); err != nil {
+					t.Errorf("malformed JSON body: %v", err)
+				}
+				fmt.Fprint(w, `{"bugs":[{"id":1}]}`)
+			}))
+			defer testServer.Close()
+			c := clientForUrl(testServer.URL).(*client)
+			if err := c.UpdateBug(1, update); err != nil {
+				t.Fatalf("expected no error, but got one: %v", err)
+			}
+			if !reflect.DeepEqual(actual, testCase.expectedUpdate) {
+				t.Errorf("got incorrect update: %v", diff.ObjectReflectDiff(testCase.expectedUpdate, actual))
+			}
+			if requests != testCase.expectedRequests {
+				t.Errorf("expected %d requests, got %d", testCase.expectedRequests, requests)
+			}
+		})
+	}
+}
+
+func TestWithServerVersion(t *testing.T) {
+	c := clientForUrl("http://example.com").(*client)
+	WithServerVersion("4.4.12")(c)
+	if c.adaptUpdate(BugUpdate{MinorUpdate: true}, c.logger).MinorUpdate {
+		t.Error("expected the minor update to be left out for a 4.4 server")
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PRStatusWhiteboardKey is the key of the whiteboard token which mirrors the
+// states of the pull requests linked to a bug, e.g. `prs:2-open,1-merged`
+const PRStatusWhiteboardKey = "prs"
+
+// WhiteboardToken returns the value of the first `key:value` token in the
+// whiteboard with the given key and whether such a token was found.
+func WhiteboardToken(whiteboard, key string) (string, bool) {
+	prefix := key + ":"
+	for _, token := range strings.Fields(whiteboard) {
+		if strings.HasPrefix(token, prefix) {
+			return strings.TrimPrefix(token, prefix), true
+		}
+	}
+	return "", false
+}
+
+// SetWhiteboardToken returns the whiteboard with the `key:value` token set.
+// An existing token with the same key is replaced in place, otherwise the
+// token is appended. All other content of the whiteboard is preserved.
+func SetWhiteboardToken(whiteboard, key, value string) string {
+	prefix := key + ":"
+	token := prefix + value
+	tokens := strings.Fields(whiteboard)
+	var out []string
+	replaced := false
+	for _, existing := range tokens {
+		if strings.HasPrefix(existing, prefix) {
+			if !replaced {
+				out = append(out, token)
+				replaced = true
+			}
+			continue
+		}
+		out = append(out, existing)
+	}
+	if !replaced {
+		out = append(out, token)
+	}
+	return strings.Join(out, " ")
+}
+
+// RemoveWhiteboardToken returns the whiteboard without any `key:value` token
+// with the given key. All other content of the whiteboard is preserved.
+func RemoveWhiteboardToken(whiteboard, key string) string {
+	prefix := key + ":"
+	var out []string
+	for _, existing := range strings.Fields(whiteboard) {
+		if !strings.HasPrefix(existing, prefix) {
+			out = append(out, existing)
+		}
+	}
+	if len(out) == len(strings.Fields(whiteboard)) {
+		return whiteboard
+	}
+	return strings.Join(out, " ")
+}
+
+// PRStatusSummary summarizes the states of the given pull requests as
+// `<count>-<state>` pairs ordered by state, e.g. `2-open,1-merged`. A bug
+// without any linked pull requests is summarized as `none`.
+func PRStatusSummary(prs []ExternalBug) string {
+	if len(prs) == 0 {
+		return "none"
+	}
+	counts := map[string]int{}
+	for _, pr := range prs {
+		state := strings.ToLower(strings.Join(strings.Fields(pr.ExternalStatus), "_"))
+		if state == "" {
+			state = "unknown"
+		}
+		counts[state]++
+	}
+	var states []string
+	for state := range counts {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+	var parts []string
+	for _, state := range states {
+		parts = append(parts, fmt.Sprintf("%d-%s", counts[state], state))
+	}
+	return strings.Join(parts, ",")
+}
+
+// SyncPRStatusToWhiteboard mirrors the states of the pull requests linked to
+// the bug into the PRStatusWhiteboardKey whiteboard token, for consumers that
+// only see the whiteboard and not the external tracker table, see
+// SetBugWhiteboardToken. It returns whether the whiteboard was changed.
+func SyncPRStatusToWhiteboard(c Client, id int) (bool, error) {
+	prs, err := c.GetExternalBugPRsOnBug(id)
+	if err != nil {
+		return false, err
+	}
+	return SetBugWhiteboardToken(c, id, StatusWhiteboard, PRStatusWhiteboardKey, PRStatusSummary(prs))
+}
+
+// WhiteboardTokens parses the `key:value` tokens of the whiteboard into a map.
+// Like for WhiteboardToken, the first token with a key wins. Words which are
+// not `key:value` tokens are left out.
+func WhiteboardTokens(whiteboard string) map[string]string {
+	tokens := map[string]string{}
+	for _, token := range strings.Fields(whiteboard) {
+		index := strings.Index(token, ":")
+		if index <= 0 {
+			continue
+		}
+		if _, exists := tokens[token[:index]]; !exists {
+			tokens[token[:index]] = token[index+1:]
+		}
+	}
+	return tokens
+}
+
+// WhiteboardField names a whiteboard field of bugs
+type WhiteboardField string
+
+const (
+	// StatusWhiteboard is the status whiteboard every server has.
+	StatusWhiteboard WhiteboardField = "whiteboard"
+	// InternalWhiteboard is the internal whiteboard of Red Hat Bugzilla.
+	InternalWhiteboard WhiteboardField = "cf_internal_whiteboard"
+	// DevelWhiteboard is the development whiteboard of Red Hat Bugzilla.
+	DevelWhiteboard WhiteboardField = "cf_devel_whiteboard"
+)
+
+// value returns the value of the whiteboard field of the bug
+func (f WhiteboardField) value(bug *Bug) string {
+	if f == StatusWhiteboard {
+		return bug.Whiteboard
+	}
+	value, _ := bug.CustomFields[string(f)].(string)
+	return value
+}
+
+// update returns the update which only sets the whiteboard field
+func (f WhiteboardField) update(value string) BugUpdate {
+	if f == StatusWhiteboard {
+		return BugUpdate{Whiteboard: value}
+	}
+	return BugUpdate{CustomFields: map[string]interface{}{string(f): value}}
+}
+
+// GetBugWhiteboardToken retrieves the value of the `key:value` token in the
+// whiteboard field of the bug and whether the token was found.
+func GetBugWhiteboardToken(c Client, id int, field WhiteboardField, key string) (string, bool, error) {
+	bug, err := c.GetBugWithFields(id, []string{"id", string(field)})
+	if err != nil {
+		return "", false, err
+	}
+	value, found := WhiteboardToken(field.value(bug), key)
+	return value, found, nil
+}
+
+// SetBugWhiteboardToken sets the `key:value` token in the whiteboard field of
+// the bug, see updateWhiteboard. It returns whether the whiteboard changed.
+func SetBugWhiteboardToken(c Client, id int, field WhiteboardField, key, value string) (bool, error) {
+	return updateWhiteboard(c, id, field, func(whiteboard string) string {
+		return SetWhiteboardToken(whiteboard, key, value)
+	})
+}
+
+// RemoveBugWhiteboardToken removes the token with the key from the whiteboard
+// field of the bug, see updateWhiteboard. It returns whether the whiteboard
+// changed.
+func RemoveBugWhiteboardToken(c Client, id int, field WhiteboardField, key string) (bool, error) {
+	return updateWhiteboard(c, id, field, func(whiteboard string) string {
+		return RemoveWhiteboardToken(whiteboard, key)
+	})
+}
+
+// updateWhiteboard applies the change to the whiteboard field of the bug. The
+// whiteboard is read immediately before the update, which is skipped if the
+// change is already applied and otherwise only touches the whiteboard field.
+// The update is guarded by the time the bug last changed, so a concurrent
+// update is never undone: the whiteboard is read again and the change applied
+// to it instead, see updateGuarded.
+func updateWhiteboard(c Client, id int, field WhiteboardField, change func(string) string) (bool, error) {
+	update, err := updateGuarded(c, id, []string{"id", string(field)}, func(bug *Bug) (*BugUpdate, error) {
+		current := field.value(bug)
+		desired := change(current)
+		if desired == current {
+			return nil, nil
+		}
+		update := field.update(desired)
+		return &update, nil
+	})
+	return update != nil, err
+}
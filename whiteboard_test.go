@@ -0,0 +1,250 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSetWhiteboardToken(t *testing.T) {
+	testCases := []struct {
+		name       string
+		whiteboard string
+		expected   string
+	}{
+		{
+			name:     "empty whiteboard",
+			expected: "prs:1-open",
+		},
+		{
+			name:       "token appended to other content",
+			whiteboard: "UpcomingSprint",
+			expected:   "UpcomingSprint prs:1-open",
+		},
+		{
+			name:       "existing token replaced in place",
+			whiteboard: "UpcomingSprint prs:2-merged other:value",
+			expected:   "UpcomingSprint prs:1-open other:value",
+		},
+		{
+			name:       "duplicate tokens collapsed",
+			whiteboard: "prs:2-merged prs:none",
+			expected:   "prs:1-open",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := SetWhiteboardToken(tc.whiteboard, PRStatusWhiteboardKey, "1-open"); actual != tc.expected {
+				t.Errorf("%s: expected %q, got %q", tc.name, tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRemoveWhiteboardToken(t *testing.T) {
+	testCases := []struct {
+		name       string
+		whiteboard string
+		expected   string
+	}{
+		{
+			name:       "missing token leaves whiteboard untouched",
+			whiteboard: "UpcomingSprint  other:value",
+			expected:   "UpcomingSprint  other:value",
+		},
+		{
+			name:       "all tokens with the key removed",
+			whiteboard: "prs:2-merged UpcomingSprint prs:none other:value",
+			expected:   "UpcomingSprint other:value",
+		},
+		{
+			name:       "only token removed",
+			whiteboard: "prs:none",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := RemoveWhiteboardToken(tc.whiteboard, PRStatusWhiteboardKey); actual != tc.expected {
+				t.Errorf("%s: expected %q, got %q", tc.name, tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPRStatusSummary(t *testing.T) {
+	testCases := []struct {
+		name     string
+		prs      []ExternalBug
+		expected string
+	}{
+		{
+			name:     "no pull requests",
+			expected: "none",
+		},
+		{
+			name:     "states are counted and sorted",
+			prs:      []ExternalBug{{ExternalStatus: "open"}, {ExternalStatus: "MERGED"}, {ExternalStatus: "open"}, {}},
+			expected: "1-merged,2-open,1-unknown",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := PRStatusSummary(tc.prs); actual != tc.expected {
+				t.Errorf("%s: expected %q, got %q", tc.name, tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSyncPRStatusToWhiteboard(t *testing.T) {
+	fake := &Fake{
+		Bugs: map[int]Bug{1: {ID: 1, Whiteboard: "UpcomingSprint"}},
+		ExternalBugs: map[int][]ExternalBug{1: {
+			{BugzillaBugID: 1, ExternalBugID: "org/repo/pull/1", ExternalStatus: "open"},
+			{BugzillaBugID: 1, ExternalBugID: "org/repo/pull/2", ExternalStatus: "merged"},
+		}},
+	}
+	changed, err := SyncPRStatusToWhiteboard(fake, 1)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if !changed {
+		t.Error("expected the whiteboard to change, but it did not")
+	}
+	if actual, expected := fake.Bugs[1].Whiteboard, "UpcomingSprint prs:1-merged,1-open"; actual != expected {
+		t.Errorf("expected whiteboard %q, got %q", expected, actual)
+	}
+
+	changed, err = SyncPRStatusToWhiteboard(fake, 1)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if changed {
+		t.Error("expected the whiteboard not to change on a second sync, but it did")
+	}
+
+	if _, err := SyncPRStatusToWhiteboard(fake, 2); !IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestWhiteboardTokens(t *testing.T) {
+	expected := map[string]string{"prs": "1-open", "sprint": "12", "empty": ""}
+	if actual := WhiteboardTokens("UpcomingSprint prs:1-open sprint:12 sprint:13 :odd empty:"); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected tokens %v, got %v", expected, actual)
+	}
+}
+
+// racingClient changes the whiteboards of bugs before the first guarded
+// updates, like a concurrent update would
+type racingClient struct {
+	*Fake
+	races   int
+	updates int
+}
+
+func (c *racingClient) UpdateBug(id int, update BugUpdate) error {
+	c.updates++
+	if c.races > 0 && update.LastChangeTime != nil {
+		c.races--
+		bug := c.Fake.Bugs[id]
+		if err := c.Fake.UpdateBug(id, BugUpdate{Whiteboard: bug.Whiteboard + " Concurrent"}); err != nil {
+			return err
+		}
+	}
+	return c.Fake.UpdateBug(id, update)
+}
+
+func TestSetBugWhiteboardToken(t *testing.T) {
+	var testCases = []struct {
+		name            string
+		field           WhiteboardField
+		races           int
+		expectedChanged bool
+		expectedErr     bool
+		expectedUpdates int
+	}{
+		{
+			name:            "the token is set in the status whiteboard",
+			field:           StatusWhiteboard,
+			expectedChanged: true,
+			expectedUpdates: 1,
+		},
+		{
+			name:            "the token is set in the internal whiteboard",
+			field:           InternalWhiteboard,
+			expectedChanged: true,
+			expectedUpdates: 1,
+		},
+		{
+			name:            "changes colliding with concurrent updates are applied to the new whiteboard",
+			field:           StatusWhiteboard,
+			races:           2,
+			expectedChanged: true,
+			expectedUpdates: 3,
+		},
+		{
+			name:            "changes which keep colliding fail",
+			field:           StatusWhiteboard,
+			races:           5,
+			expectedErr:     true,
+			expectedUpdates: guardedUpdateAttempts,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fake := &Fake{Bugs: map[int]Bug{1: {
+				ID:           1,
+				Whiteboard:   "UpcomingSprint",
+				CustomFields: map[string]interface{}{"cf_internal_whiteboard": "UpcomingSprint", "cf_devel_whiteboard": "UpcomingSprint"},
+			}}}
+			client := &racingClient{Fake: fake, races: testCase.races}
+			changed, err := SetBugWhiteboardToken(client, 1, testCase.field, "sprint", "12")
+			if testCase.expectedErr != (err != nil) {
+				t.Fatalf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+			}
+			if changed != testCase.expectedChanged {
+				t.Errorf("%s: expected changed %v, got %v", testCase.name, testCase.expectedChanged, changed)
+			}
+			if client.updates != testCase.expectedUpdates {
+				t.Errorf("%s: expected %d updates, got %d", testCase.name, testCase.expectedUpdates, client.updates)
+			}
+			if testCase.expectedErr {
+				return
+			}
+			value, found, err := GetBugWhiteboardToken(client, 1, testCase.field, "sprint")
+			if err != nil || !found || value != "12" {
+				t.Errorf("%s: expected the token to be set, got %q, %v, %v", testCase.name, value, found, err)
+			}
+			if whiteboard := fake.Bugs[1].Whiteboard; testCase.races > 0 && !strings.Contains(whiteboard, "Concurrent") {
+				t.Errorf("%s: expected the concurrent changes to be kept, got whiteboard %q", testCase.name, whiteboard)
+			}
+
+			client.updates = 0
+			changed, err = SetBugWhiteboardToken(client, 1, testCase.field, "sprint", "12")
+			if err != nil || changed || client.updates != 0 {
+				t.Errorf("%s: expected setting the token again to do nothing, got changed %v after %d updates: %v", testCase.name, changed, client.updates, err)
+			}
+			changed, err = RemoveBugWhiteboardToken(client, 1, testCase.field, "sprint")
+			if err != nil || !changed {
+				t.Errorf("%s: expected the token to be removed, got changed %v: %v", testCase.name, changed, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Workflow holds the status changes the server allows, so a change can be
+// checked before sending it to the server, which rejects illegal changes
+// with errors that do not say which changes are allowed.
+type Workflow struct {
+	open map[string]bool
+	// transitions maps statuses to the transitions out of them
+	transitions map[string][]StatusTransition
+}
+
+// NewWorkflow returns the workflow described by the values of the bug_status
+// field, see GetFields
+func NewWorkflow(statuses []FieldValue) *Workflow {
+	workflow := &Workflow{open: map[string]bool{}, transitions: map[string][]StatusTransition{}}
+	for _, status := range statuses {
+		if status.Name == "" {
+			continue
+		}
+		workflow.open[status.Name] = status.IsOpen
+		workflow.transitions[status.Name] = status.CanChangeTo
+	}
+	return workflow
+}
+
+// GetWorkflow retrieves the workflow of the server from the metadata of the
+// bug_status field
+func GetWorkflow(c Client) (*Workflow, error) {
+	fields, err := c.GetFields("bug_status")
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) != 1 {
+		return nil, fmt.Errorf("did not get one bug_status field, but %d", len(fields))
+	}
+	return NewWorkflow(fields[0].Values), nil
+}
+
+// IsOpen returns true if bugs in the status are open
+func (w *Workflow) IsOpen(status string) bool {
+	return w.open[status]
+}
+
+// Allowed returns the statuses a bug in the status can change to, in the
+// order of the server, or nil if the status is not known
+func (w *Workflow) Allowed(from string) []string {
+	var allowed []string
+	for _, transition := range w.transitions[from] {
+		allowed = append(allowed, transition.Name)
+	}
+	return allowed
+}
+
+// Validate checks that a bug in the status can change to the other status.
+// Keeping the status is always allowed. It returns a *TransitionError, which
+// lists the allowed statuses, if the change is not allowed.
+func (w *Workflow) Validate(from, to string) error {
+	_, err := w.transition(from, to)
+	return err
+}
+
+// ValidateUpdate checks that the update can be applied to a bug in the
+// status: that the status change, if any, is allowed, that a resolution is
+// given when closing the bug and that a comment is given when the change
+// requires one.
+func (w *Workflow) ValidateUpdate(from string, update BugUpdate) error {
+	if update.Status == "" || update.Status == from {
+		return nil
+	}
+	transition, err := w.transition(from, update.Status)
+	if err != nil {
+		return err
+	}
+	if !w.open[update.Status] && update.Resolution == "" {
+		return &TransitionError{From: from, To: update.Status, Reason: "a resolution is required"}
+	}
+	if transition.CommentRequired && (update.Comment == nil || update.Comment.Body == "") {
+		return &TransitionError{From: from, To: update.Status, Reason: "a comment is required"}
+	}
+	return nil
+}
+
+func (w *Workflow) transition(from, to string) (StatusTransition, error) {
+	if from == to {
+		return StatusTransition{Name: to}, nil
+	}
+	if _, known := w.transitions[to]; !known {
+		return StatusTransition{}, &TransitionError{From: from, To: to, Reason: "the status is not known", Allowed: w.Allowed(from)}
+	}
+	for _, transition := range w.transitions[from] {
+		if transition.Name == to {
+			return transition, nil
+		}
+	}
+	return StatusTransition{}, &TransitionError{From: from, To: to, Reason: "the change is not allowed", Allowed: w.Allowed(from)}
+}
+
+// TransitionError is returned when a bug can not change from one status to
+// another
+type TransitionError struct {
+	From string
+	To   string
+	// Reason says why the change is not allowed.
+	Reason string
+	// Allowed are the statuses the bug can change to instead, if the change
+	// itself is not allowed.
+	Allowed []string
+}
+
+func (e *TransitionError) Error() string {
+	message := fmt.Sprintf("status %s can not change to %s: %s", e.From, e.To, e.Reason)
+	if len(e.Allowed) > 0 {
+		message += fmt.Sprintf(", allowed statuses are %s", strings.Join(e.Allowed, ", "))
+	}
+	return message
+}
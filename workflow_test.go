@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWorkflow(t *testing.T) {
+	fake := &Fake{Fields: []Field{{Name: "bug_status", Values: []FieldValue{
+		{Name: "NEW", IsOpen: true, CanChangeTo: []StatusTransition{{Name: "ASSIGNED"}, {Name: "CLOSED", CommentRequired: true}}},
+		{Name: "ASSIGNED", IsOpen: true, CanChangeTo: []StatusTransition{{Name: "NEW"}, {Name: "VERIFIED"}, {Name: "CLOSED"}}},
+		{Name: "VERIFIED", IsOpen: true, CanChangeTo: []StatusTransition{{Name: "CLOSED"}}},
+		{Name: "CLOSED", CanChangeTo: []StatusTransition{{Name: "NEW", CommentRequired: true}}},
+	}}}}
+	workflow, err := GetWorkflow(fake)
+	if err != nil {
+		t.Fatalf("expected no error, but got one: %v", err)
+	}
+	if actual, expected := workflow.Allowed("NEW"), []string{"ASSIGNED", "CLOSED"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected allowed statuses %v, got %v", expected, actual)
+	}
+
+	var testCases = []struct {
+		name            string
+		from            string
+		update          BugUpdate
+		expectedErr     bool
+		expectedAllowed []string
+	}{
+		{
+			name: "no status change is valid",
+			from: "NEW",
+		},
+		{
+			name:   "keeping the status is valid",
+			from:   "NEW",
+			update: BugUpdate{Status: "NEW"},
+		},
+		{
+			name:   "allowed change is valid",
+			from:   "NEW",
+			update: BugUpdate{Status: "ASSIGNED"},
+		},
+		{
+			name:            "illegal change suggests allowed statuses",
+			from:            "NEW",
+			update:          BugUpdate{Status: "VERIFIED"},
+			expectedErr:     true,
+			expectedAllowed: []string{"ASSIGNED", "CLOSED"},
+		},
+		{
+			name:            "unknown status is invalid",
+			from:            "ASSIGNED",
+			update:          BugUpdate{Status: "DONE"},
+			expectedErr:     true,
+			expectedAllowed: []string{"NEW", "VERIFIED", "CLOSED"},
+		},
+		{
+			name:        "closing requires a resolution",
+			from:        "VERIFIED",
+			update:      BugUpdate{Status: "CLOSED"},
+			expectedErr: true,
+		},
+		{
+			name:   "closing with a resolution is valid",
+			from:   "VERIFIED",
+			update: BugUpdate{Status: "CLOSED", Resolution: "ERRATA"},
+		},
+		{
+			name:        "change requiring a comment is invalid without one",
+			from:        "CLOSED",
+			update:      BugUpdate{Status: "NEW"},
+			expectedErr: true,
+		},
+		{
+			name:   "change requiring a comment is valid with one",
+			from:   "CLOSED",
+			update: BugUpdate{Status: "NEW", Comment: &BugComment{Body: "Not fixed after all."}},
+		},
+	}
+	for _, testCase := range testCases {
+		err := workflow.ValidateUpdate(testCase.from, testCase.update)
+		if testCase.expectedErr != (err != nil) {
+			t.Errorf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+			continue
+		}
+		if transitionErr, ok := err.(*TransitionError); ok && !reflect.DeepEqual(transitionErr.Allowed, testCase.expectedAllowed) {
+			t.Errorf("%s: expected allowed statuses %v, got %v", testCase.name, testCase.expectedAllowed, transitionErr.Allowed)
+		}
+	}
+}
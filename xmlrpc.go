@@ -0,0 +1,244 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bugzilla
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// callXMLRPC calls the method over XML-RPC. The params are encoded and the
+// result is decoded using their json tags, so the same types serve JSON-RPC
+// and XML-RPC.
+// https://bugzilla.readthedocs.io/en/5.0/api/Bugzilla/WebService/Server/XMLRPC.html
+func (r *rpcClient) callXMLRPC(method string, params, result interface{}) error {
+	body, err := encodeXMLRPCCall(method, params)
+	if err != nil {
+		return fmt.Errorf("failed to encode XMLRPC call: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/xmlrpc.cgi", r.client.endpoint), bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/xml")
+	req = r.markReadOnly(method, req)
+
+	resp, err := r.client.request(req, r.logger)
+	if err != nil {
+		return err
+	}
+	decoded, fault, err := decodeXMLRPCResponse(resp)
+	if err != nil {
+		return fmt.Errorf("failed to decode XMLRPC response: %v", err)
+	}
+	if fault != nil {
+		return r.client.redactError(fault)
+	}
+	raw, err := json.Marshal(decoded)
+	if err != nil {
+		return fmt.Errorf("failed to convert XMLRPC result: %v", err)
+	}
+	if err := r.client.unmarshal(raw, result); err != nil {
+		return fmt.Errorf("failed to unmarshal XMLRPC result: %v", err)
+	}
+	return nil
+}
+
+// encodeXMLRPCCall encodes a call of the method with the params as its only
+// parameter
+func encodeXMLRPCCall(method string, params interface{}) ([]byte, error) {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?><methodCall><methodName>`)
+	if err := xml.EscapeText(&buf, []byte(method)); err != nil {
+		return nil, err
+	}
+	buf.WriteString(`</methodName><params><param>`)
+	if err := encodeXMLRPCValue(&buf, generic); err != nil {
+		return nil, err
+	}
+	buf.WriteString(`</param></params></methodCall>`)
+	return buf.Bytes(), nil
+}
+
+// encodeXMLRPCValue encodes a value as decoded from JSON
+func encodeXMLRPCValue(buf *bytes.Buffer, value interface{}) error {
+	buf.WriteString("<value>")
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("<nil/>")
+	case bool:
+		if v {
+			buf.WriteString("<boolean>1</boolean>")
+		} else {
+			buf.WriteString("<boolean>0</boolean>")
+		}
+	case json.Number:
+		if _, err := strconv.ParseInt(string(v), 10, 32); err == nil {
+			fmt.Fprintf(buf, "<int>%s</int>", v)
+		} else {
+			fmt.Fprintf(buf, "<double>%s</double>", v)
+		}
+	case string:
+		buf.WriteString("<string>")
+		if err := xml.EscapeText(buf, []byte(v)); err != nil {
+			return err
+		}
+		buf.WriteString("</string>")
+	case []interface{}:
+		buf.WriteString("<array><data>")
+		for _, item := range v {
+			if err := encodeXMLRPCValue(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("</data></array>")
+	case map[string]interface{}:
+		var names []string
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		buf.WriteString("<struct>")
+		for _, name := range names {
+			buf.WriteString("<member><name>")
+			if err := xml.EscapeText(buf, []byte(name)); err != nil {
+				return err
+			}
+			buf.WriteString("</name>")
+			if err := encodeXMLRPCValue(buf, v[name]); err != nil {
+				return err
+			}
+			buf.WriteString("</member>")
+		}
+		buf.WriteString("</struct>")
+	default:
+		return fmt.Errorf("can not encode %T", value)
+	}
+	buf.WriteString("</value>")
+	return nil
+}
+
+// xmlrpcValue is an XML-RPC value of any type
+type xmlrpcValue struct {
+	String   *string   `xml:"string"`
+	Int      *string   `xml:"int"`
+	I4       *string   `xml:"i4"`
+	Boolean  *string   `xml:"boolean"`
+	Double   *string   `xml:"double"`
+	DateTime *string   `xml:"dateTime.iso8601"`
+	Base64   *string   `xml:"base64"`
+	Nil      *struct{} `xml:"nil"`
+	Struct   *struct {
+		Members []struct {
+			Name  string      `xml:"name"`
+			Value xmlrpcValue `xml:"value"`
+		} `xml:"member"`
+	} `xml:"struct"`
+	Array *struct {
+		Values []xmlrpcValue `xml:"data>value"`
+	} `xml:"array"`
+	// Text holds the value if it has no type, which makes it a string
+	Text string `xml:",chardata"`
+}
+
+// generic returns the value as the types JSON is decoded into
+func (v xmlrpcValue) generic() (interface{}, error) {
+	switch {
+	case v.String != nil:
+		return *v.String, nil
+	case v.Int != nil, v.I4 != nil:
+		text := v.Int
+		if text == nil {
+			text = v.I4
+		}
+		return strconv.ParseInt(strings.TrimSpace(*text), 10, 64)
+	case v.Boolean != nil:
+		return strings.TrimSpace(*v.Boolean) == "1", nil
+	case v.Double != nil:
+		return strconv.ParseFloat(strings.TrimSpace(*v.Double), 64)
+	case v.DateTime != nil:
+		return strings.TrimSpace(*v.DateTime), nil
+	case v.Base64 != nil:
+		return strings.TrimSpace(*v.Base64), nil
+	case v.Nil != nil:
+		return nil, nil
+	case v.Struct != nil:
+		members := map[string]interface{}{}
+		for _, member := range v.Struct.Members {
+			value, err := member.Value.generic()
+			if err != nil {
+				return nil, fmt.Errorf("member %s: %v", member.Name, err)
+			}
+			members[member.Name] = value
+		}
+		return members, nil
+	case v.Array != nil:
+		items := []interface{}{}
+		for _, item := range v.Array.Values {
+			value, err := item.generic()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+		}
+		return items, nil
+	}
+	return v.Text, nil
+}
+
+// decodeXMLRPCResponse decodes the result of a call or the fault the call
+// failed with
+func decodeXMLRPCResponse(raw []byte) (interface{}, *RequestError, error) {
+	var response struct {
+		Params []xmlrpcValue `xml:"params>param>value"`
+		Fault  *xmlrpcValue  `xml:"fault>value"`
+	}
+	if err := xml.Unmarshal(raw, &response); err != nil {
+		return nil, nil, err
+	}
+	if response.Fault != nil {
+		decoded, err := response.Fault.generic()
+		if err != nil {
+			return nil, nil, err
+		}
+		fault, _ := decoded.(map[string]interface{})
+		code, _ := fault["faultCode"].(int64)
+		message, _ := fault["faultString"].(string)
+		return nil, &RequestError{StatusCode: http.StatusOK, Code: int(code), Message: fmt.Sprintf("XMLRPC fault %d: %v", code, message)}, nil
+	}
+	if len(response.Params) == 0 {
+		return nil, nil, nil
+	}
+	decoded, err := response.Params[0].generic()
+	return decoded, nil, err
+}